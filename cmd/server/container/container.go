@@ -26,6 +26,8 @@ import (
 	"github.com/e154/smart-home/internal/endpoint"
 	"github.com/e154/smart-home/internal/system/automation"
 	"github.com/e154/smart-home/internal/system/backup"
+	"github.com/e154/smart-home/internal/system/discovery"
+	"github.com/e154/smart-home/internal/system/exporter"
 	"github.com/e154/smart-home/internal/system/gate/client"
 	"github.com/e154/smart-home/internal/system/initial"
 	localMigrations "github.com/e154/smart-home/internal/system/initial/local_migrations"
@@ -39,6 +41,7 @@ import (
 	"github.com/e154/smart-home/internal/system/orm"
 	"github.com/e154/smart-home/internal/system/rbac"
 	"github.com/e154/smart-home/internal/system/rbac/access_list"
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
 	"github.com/e154/smart-home/internal/system/rbac/rbac_echo"
 	"github.com/e154/smart-home/internal/system/rbac/rbac_http"
 	"github.com/e154/smart-home/internal/system/scheduler"
@@ -86,6 +89,7 @@ func BuildContainer(opt fx.Option) (app *fx.App) {
 			mqtt_authenticator.NewAuthenticator,
 			mqtt.NewMqtt,
 			access_list.NewAccessListService,
+			entity_access.NewEntityAccessService,
 			rbac_echo.NewEchoAccessFilter,
 			rbac_http.NewHttpAccessFilter,
 			jwt_manager.NewJwtManager,
@@ -94,6 +98,8 @@ func BuildContainer(opt fx.Option) (app *fx.App) {
 			storage.NewStorage,
 			supervisor.NewSupervisor,
 			automation.NewAutomation,
+			exporter.NewExporter,
+			discovery.NewDiscovery,
 			endpoint.NewCommonEndpoint,
 			endpoint.NewEndpoint,
 			NewApiConfig,
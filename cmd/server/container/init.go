@@ -65,5 +65,15 @@ func MigrationList(adaptors *adaptors.Adaptors,
 		local_migrations2.NewMigrationRemoveTriggersPlugin(adaptors),
 		local_migrations2.NewMigrationMdns(adaptors),
 		local_migrations2.NewMigrationMedia(adaptors),
+		local_migrations2.NewMigrationStateTriggers(adaptors),
+		local_migrations2.NewMigrationMqttTrigger(adaptors),
+		local_migrations2.NewMigrationWebhookTrigger(adaptors),
+		local_migrations2.NewMigrationBackupTargets(adaptors),
+		local_migrations2.NewMigrationStatistics(adaptors),
+		local_migrations2.NewMigrationExporter(adaptors),
+		local_migrations2.NewMigrationPresence(adaptors),
+		local_migrations2.NewMigrationDiscovery(adaptors),
+		local_migrations2.NewMigrationEncryptedVariables(adaptors),
+		local_migrations2.NewMigrationBackupIncremental(adaptors),
 	}
 }
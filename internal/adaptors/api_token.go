@@ -0,0 +1,132 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/e154/smart-home/internal/db"
+	"github.com/e154/smart-home/pkg/adaptors"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+var _ adaptors.ApiTokenRepo = (*ApiToken)(nil)
+
+// ApiToken ...
+type ApiToken struct {
+	table *db.ApiTokens
+	db    *gorm.DB
+}
+
+// GetApiTokenAdaptor ...
+func GetApiTokenAdaptor(d *gorm.DB) *ApiToken {
+	return &ApiToken{
+		table: &db.ApiTokens{&db.Common{Db: d}},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *ApiToken) Add(ctx context.Context, token *m.ApiToken) (id int64, err error) {
+	id, err = n.table.Add(ctx, n.toDb(token))
+	return
+}
+
+// GetById ...
+func (n *ApiToken) GetById(ctx context.Context, id int64) (token *m.ApiToken, err error) {
+	var dbVer *db.ApiToken
+	if dbVer, err = n.table.GetById(ctx, id); err != nil {
+		return
+	}
+	token = n.fromDb(dbVer)
+	return
+}
+
+// GetByToken ...
+func (n *ApiToken) GetByToken(ctx context.Context, hash string) (token *m.ApiToken, err error) {
+	var dbVer *db.ApiToken
+	if dbVer, err = n.table.GetByToken(ctx, hash); err != nil {
+		return
+	}
+	token = n.fromDb(dbVer)
+	return
+}
+
+// List ...
+func (n *ApiToken) List(ctx context.Context, userId int64) (list []*m.ApiToken, err error) {
+	var dbList []*db.ApiToken
+	if dbList, err = n.table.List(ctx, userId); err != nil {
+		return
+	}
+	list = make([]*m.ApiToken, len(dbList))
+	for i, dbVer := range dbList {
+		list[i] = n.fromDb(dbVer)
+	}
+	return
+}
+
+// Touch ...
+func (n *ApiToken) Touch(ctx context.Context, id int64, lastUsed time.Time) (err error) {
+	err = n.table.Touch(ctx, id, lastUsed)
+	return
+}
+
+// Delete ...
+func (n *ApiToken) Delete(ctx context.Context, id int64) (err error) {
+	err = n.table.Delete(ctx, id)
+	return
+}
+
+func (n *ApiToken) fromDb(dbVer *db.ApiToken) (ver *m.ApiToken) {
+	ver = &m.ApiToken{
+		Id:         dbVer.Id,
+		UserId:     dbVer.UserId,
+		Name:       dbVer.Name,
+		Token:      dbVer.Token,
+		Scopes:     make([]string, 0),
+		ExpiresAt:  dbVer.ExpiresAt,
+		LastUsedAt: dbVer.LastUsedAt,
+		CreatedAt:  dbVer.CreatedAt,
+	}
+	if len(dbVer.Scopes) > 0 {
+		_ = json.Unmarshal(dbVer.Scopes, &ver.Scopes)
+	}
+	return
+}
+
+func (n *ApiToken) toDb(ver *m.ApiToken) (dbVer *db.ApiToken) {
+	dbVer = &db.ApiToken{
+		Id:         ver.Id,
+		UserId:     ver.UserId,
+		Name:       ver.Name,
+		Token:      ver.Token,
+		ExpiresAt:  ver.ExpiresAt,
+		LastUsedAt: ver.LastUsedAt,
+	}
+	scopes := ver.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	dbVer.Scopes, _ = json.Marshal(scopes)
+	return
+}
@@ -20,6 +20,7 @@ package adaptors
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/e154/smart-home/internal/db"
@@ -123,6 +124,11 @@ func (n *Condition) fromDb(dbVer *db.Condition) (ver *m.Condition) {
 		CreatedAt:   dbVer.CreatedAt,
 		UpdatedAt:   dbVer.UpdatedAt,
 	}
+	// rule
+	if len(dbVer.Rule) > 0 && string(dbVer.Rule) != "null" {
+		ver.Rule = &m.ConditionRule{}
+		_ = json.Unmarshal(dbVer.Rule, ver.Rule)
+	}
 	// script
 	if dbVer.Script != nil {
 		scriptAdaptor := GetScriptAdaptor(n.db)
@@ -147,6 +153,10 @@ func (n *Condition) toDb(ver *m.Condition) (dbVer *db.Condition) {
 		UpdatedAt:   ver.UpdatedAt,
 	}
 
+	if ver.Rule != nil {
+		dbVer.Rule, _ = json.Marshal(ver.Rule)
+	}
+
 	if ver.Script != nil {
 		dbVer.ScriptId = common.Int64(ver.Script.Id)
 	}
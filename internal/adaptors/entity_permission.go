@@ -0,0 +1,120 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"context"
+
+	"github.com/e154/smart-home/internal/db"
+	"github.com/e154/smart-home/pkg/adaptors"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+var _ adaptors.EntityPermissionRepo = (*EntityPermission)(nil)
+
+// EntityPermission ...
+type EntityPermission struct {
+	table *db.EntityPermissions
+	db    *gorm.DB
+}
+
+// GetEntityPermissionAdaptor ...
+func GetEntityPermissionAdaptor(d *gorm.DB) *EntityPermission {
+	return &EntityPermission{
+		table: &db.EntityPermissions{&db.Common{Db: d}},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *EntityPermission) Add(ctx context.Context, permission *m.EntityPermission) (id int64, err error) {
+	id, err = n.table.Add(ctx, n.toDb(permission))
+	return
+}
+
+// GetById ...
+func (n *EntityPermission) GetById(ctx context.Context, id int64) (permission *m.EntityPermission, err error) {
+	var dbVer *db.EntityPermission
+	if dbVer, err = n.table.GetById(ctx, id); err != nil {
+		return
+	}
+	permission = n.fromDb(dbVer)
+	return
+}
+
+// Delete ...
+func (n *EntityPermission) Delete(ctx context.Context, id int64) (err error) {
+	err = n.table.Delete(ctx, id)
+	return
+}
+
+// List ...
+func (n *EntityPermission) List(ctx context.Context, roleName string) (list []*m.EntityPermission, err error) {
+	var dbList []*db.EntityPermission
+	if dbList, err = n.table.List(ctx, roleName); err != nil {
+		return
+	}
+	list = n.fromDbList(dbList)
+	return
+}
+
+// GetAllPermissions ...
+func (n *EntityPermission) GetAllPermissions(ctx context.Context, roleName string) (list []*m.EntityPermission, err error) {
+	var dbList []*db.EntityPermission
+	if dbList, err = n.table.GetAllPermissions(ctx, roleName); err != nil {
+		return
+	}
+	list = n.fromDbList(dbList)
+	return
+}
+
+func (n *EntityPermission) fromDbList(dbList []*db.EntityPermission) (list []*m.EntityPermission) {
+	list = make([]*m.EntityPermission, len(dbList))
+	for i, dbVer := range dbList {
+		list[i] = n.fromDb(dbVer)
+	}
+	return
+}
+
+func (n *EntityPermission) fromDb(dbVer *db.EntityPermission) (ver *m.EntityPermission) {
+	ver = &m.EntityPermission{
+		Id:        dbVer.Id,
+		RoleName:  dbVer.RoleName,
+		EntityId:  dbVer.EntityId,
+		AreaId:    dbVer.AreaId,
+		Tag:       dbVer.Tag,
+		Level:     dbVer.Level,
+		CreatedAt: dbVer.CreatedAt,
+	}
+	return
+}
+
+func (n *EntityPermission) toDb(ver *m.EntityPermission) (dbVer *db.EntityPermission) {
+	dbVer = &db.EntityPermission{
+		Id:       ver.Id,
+		RoleName: ver.RoleName,
+		EntityId: ver.EntityId,
+		AreaId:   ver.AreaId,
+		Tag:      ver.Tag,
+		Level:    ver.Level,
+	}
+	return
+}
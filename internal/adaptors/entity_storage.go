@@ -36,6 +36,7 @@ var _ adaptors.EntityStorageRepo = (*EntityStorage)(nil)
 // EntityStorage ...
 type EntityStorage struct {
 	table *db.EntityStorages
+	stats *db.EntityStorageStats
 	db    *gorm.DB
 }
 
@@ -43,6 +44,7 @@ type EntityStorage struct {
 func GetEntityStorageAdaptor(d *gorm.DB) *EntityStorage {
 	return &EntityStorage{
 		table: &db.EntityStorages{&db.Common{Db: d}},
+		stats: &db.EntityStorageStats{Common: &db.Common{Db: d}},
 		db:    d,
 	}
 }
@@ -97,6 +99,52 @@ func (n *EntityStorage) DeleteOldest(ctx context.Context, days int) (err error)
 	return
 }
 
+// Rollup ...
+func (n *EntityStorage) Rollup(ctx context.Context) (err error) {
+	err = n.stats.Rollup(ctx)
+	return
+}
+
+// Statistics returns the rolled up numeric attributes, hourly or daily depending on the range
+func (n *EntityStorage) Statistics(ctx context.Context, entityId common.EntityId, attributes []string, from, to time.Time) (list []*models.EntityStorageStat, err error) {
+
+	resolution := common.StatsResolutionByRange(from, to)
+	if resolution == common.StatsResolutionRaw {
+		resolution = common.StatsResolutionHour
+	}
+
+	var dbList []*db.EntityStorageStat
+	if dbList, err = n.stats.List(ctx, entityId, resolution.String(), attributes, from, to); err != nil {
+		return
+	}
+
+	list = make([]*models.EntityStorageStat, len(dbList))
+	for i, dbVer := range dbList {
+		list[i] = n.statFromDb(dbVer)
+	}
+	return
+}
+
+// DeleteOldestStats ...
+func (n *EntityStorage) DeleteOldestStats(ctx context.Context, resolution common.StatsResolution, days int) (err error) {
+	err = n.stats.DeleteOldest(ctx, resolution.String(), days)
+	return
+}
+
+func (n *EntityStorage) statFromDb(dbVer *db.EntityStorageStat) *models.EntityStorageStat {
+	return &models.EntityStorageStat{
+		EntityId:   dbVer.EntityId,
+		Resolution: common.StatsResolution(dbVer.Resolution),
+		Name:       dbVer.Name,
+		Time:       dbVer.Time,
+		Min:        dbVer.Min,
+		Max:        dbVer.Max,
+		Mean:       dbVer.Mean,
+		Sum:        dbVer.Sum,
+		Count:      dbVer.Count,
+	}
+}
+
 func (n *EntityStorage) fromDb(dbVer *db.EntityStorage) (ver *models.EntityStorage) {
 	ver = &models.EntityStorage{
 		Id:         dbVer.Id,
@@ -0,0 +1,240 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/e154/smart-home/internal/db"
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/models"
+)
+
+// historyMaxBuckets limits the number of buckets per entity in one history query
+const historyMaxBuckets = 10000
+
+var historyPathSegment = regexp.MustCompile(`^[\w\-]+$`)
+
+// History aggregates the state or an attribute of the entities into buckets of the query interval,
+// the range defaults to the current day up to now
+func (n *EntityStorage) History(ctx context.Context, query *models.EntityHistoryQuery) (list []*models.EntityHistoryPoint, err error) {
+
+	var path []string
+	if path, err = validateHistoryQuery(query); err != nil {
+		return
+	}
+
+	var dbList []*db.EntityStorageSample
+	if dbList, err = n.table.Samples(ctx, query.EntityIds, path, query.From, query.To); err != nil {
+		return
+	}
+
+	samples := make([]*models.EntityHistorySample, len(dbList))
+	for i, dbVer := range dbList {
+		samples[i] = &models.EntityHistorySample{
+			EntityId: dbVer.EntityId,
+			Time:     dbVer.CreatedAt,
+			Value:    dbVer.Value,
+		}
+	}
+
+	list = aggregateHistory(query, samples, time.Now())
+	return
+}
+
+func validateHistoryQuery(query *models.EntityHistoryQuery) (path []string, err error) {
+
+	if len(query.EntityIds) == 0 {
+		err = fmt.Errorf("%s: %w", "empty entity list", apperr.ErrInvalidRequest)
+		return
+	}
+
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		year, month, day := query.To.Date()
+		query.From = time.Date(year, month, day, 0, 0, 0, 0, query.To.Location())
+	}
+
+	if !query.From.Before(query.To) {
+		err = fmt.Errorf("%s: %w", "from must be before to", apperr.ErrInvalidRequest)
+		return
+	}
+
+	switch query.Aggregation {
+	case "":
+		query.Aggregation = common.HistoryAggregationLast
+	case common.HistoryAggregationLast, common.HistoryAggregationMin, common.HistoryAggregationMax,
+		common.HistoryAggregationMean, common.HistoryAggregationCount, common.HistoryAggregationDuration:
+	default:
+		err = fmt.Errorf("%s: %w", fmt.Sprintf("aggregation \"%s\"", query.Aggregation), apperr.ErrInvalidRequest)
+		return
+	}
+
+	if query.Interval < 0 {
+		err = fmt.Errorf("%s: %w", "negative interval", apperr.ErrInvalidRequest)
+		return
+	}
+
+	if query.Interval > 0 && query.To.Sub(query.From)/query.Interval >= historyMaxBuckets {
+		err = fmt.Errorf("%s: %w", fmt.Sprintf("more than %d buckets", historyMaxBuckets), apperr.ErrInvalidRequest)
+		return
+	}
+
+	if query.Attribute == "" {
+		return
+	}
+
+	path = strings.Split(query.Attribute, ".")
+	for _, segment := range path {
+		if !historyPathSegment.MatchString(segment) {
+			err = fmt.Errorf("%s: %w", fmt.Sprintf("attribute \"%s\"", query.Attribute), apperr.ErrInvalidRequest)
+			return
+		}
+	}
+	return
+}
+
+// aggregateHistory expects the samples ordered by entity and time, the first sample of an entity
+// may precede the range and carries the value the entity had at the start of it
+func aggregateHistory(query *models.EntityHistoryQuery, samples []*models.EntityHistorySample, now time.Time) []*models.EntityHistoryPoint {
+
+	interval := query.Interval
+	if interval <= 0 {
+		interval = query.To.Sub(query.From)
+	}
+	buckets := int((query.To.Sub(query.From) + interval - 1) / interval)
+
+	byEntity := make(map[common.EntityId][]*models.EntityHistorySample)
+	for _, sample := range samples {
+		byEntity[sample.EntityId] = append(byEntity[sample.EntityId], sample)
+	}
+
+	points := make([]*models.EntityHistoryPoint, 0, len(query.EntityIds)*buckets)
+	for _, entityId := range query.EntityIds {
+		list := byEntity[entityId]
+		for i := 0; i < buckets; i++ {
+			start := query.From.Add(interval * time.Duration(i))
+			end := start.Add(interval)
+			if end.After(query.To) {
+				end = query.To
+			}
+			points = append(points, &models.EntityHistoryPoint{
+				EntityId: entityId,
+				Time:     start,
+				Value:    aggregateBucket(query, list, start, end, now),
+			})
+		}
+	}
+
+	return points
+}
+
+func aggregateBucket(query *models.EntityHistoryQuery, list []*models.EntityHistorySample, start, end, now time.Time) interface{} {
+
+	first := sort.Search(len(list), func(i int) bool { return !list[i].Time.Before(start) })
+	last := sort.Search(len(list), func(i int) bool { return !list[i].Time.Before(end) })
+
+	switch query.Aggregation {
+	case common.HistoryAggregationCount:
+		return last - first
+
+	case common.HistoryAggregationLast:
+		if last == 0 {
+			return nil
+		}
+		return historyValue(list[last-1].Value)
+
+	case common.HistoryAggregationMin, common.HistoryAggregationMax, common.HistoryAggregationMean:
+		var count int
+		var result float64
+		for _, sample := range list[first:last] {
+			if sample.Value == nil {
+				continue
+			}
+			value, err := strconv.ParseFloat(*sample.Value, 64)
+			if err != nil {
+				continue
+			}
+			switch {
+			case query.Aggregation == common.HistoryAggregationMean:
+				result += value
+			case count == 0,
+				query.Aggregation == common.HistoryAggregationMin && value < result,
+				query.Aggregation == common.HistoryAggregationMax && value > result:
+				result = value
+			}
+			count++
+		}
+		if count == 0 {
+			return nil
+		}
+		if query.Aggregation == common.HistoryAggregationMean {
+			return result / float64(count)
+		}
+		return result
+
+	case common.HistoryAggregationDuration:
+		limit := end
+		if now.Before(limit) {
+			limit = now
+		}
+		if first > 0 {
+			first--
+		}
+		var total time.Duration
+		for i := first; i < last; i++ {
+			if list[i].Value == nil || *list[i].Value != query.State {
+				continue
+			}
+			from, to := list[i].Time, limit
+			if from.Before(start) {
+				from = start
+			}
+			if i+1 < len(list) && list[i+1].Time.Before(to) {
+				to = list[i+1].Time
+			}
+			if to.After(from) {
+				total += to.Sub(from)
+			}
+		}
+		return total.Seconds()
+	}
+
+	return nil
+}
+
+// historyValue returns numeric values as numbers and everything else as is
+func historyValue(value *string) interface{} {
+	if value == nil {
+		return nil
+	}
+	if f, err := strconv.ParseFloat(*value, 64); err == nil {
+		return f
+	}
+	return *value
+}
@@ -0,0 +1,140 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func historySample(entityId common.EntityId, t time.Time, value string) *models.EntityHistorySample {
+	return &models.EntityHistorySample{EntityId: entityId, Time: t, Value: &value}
+}
+
+func TestAggregateHistory(t *testing.T) {
+
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	heater := common.EntityId("switch.heater")
+	sensor := common.EntityId("sensor.temp")
+
+	samples := []*models.EntityHistorySample{
+		historySample(heater, day.Add(-time.Hour), "ON"),
+		historySample(heater, day.Add(2*time.Hour), "OFF"),
+		historySample(heater, day.Add(5*time.Hour), "ON"),
+		historySample(heater, day.Add(6*time.Hour), "OFF"),
+		historySample(sensor, day.Add(time.Hour), "20"),
+		historySample(sensor, day.Add(2*time.Hour), "24"),
+		historySample(sensor, day.Add(3*time.Hour), "n/a"),
+		historySample(sensor, day.Add(13*time.Hour), "19"),
+	}
+
+	query := func(aggregation common.HistoryAggregation, interval time.Duration) *models.EntityHistoryQuery {
+		return &models.EntityHistoryQuery{
+			EntityIds:   []common.EntityId{heater, sensor},
+			Aggregation: aggregation,
+			Interval:    interval,
+			State:       "ON",
+			From:        day,
+			To:          day.Add(24 * time.Hour),
+		}
+	}
+
+	values := func(points []*models.EntityHistoryPoint, entityId common.EntityId) (result []interface{}) {
+		for _, point := range points {
+			if point.EntityId == entityId {
+				result = append(result, point.Value)
+			}
+		}
+		return
+	}
+
+	t.Run("duration", func(t *testing.T) {
+		points := aggregateHistory(query(common.HistoryAggregationDuration, 0), samples, day.Add(48*time.Hour))
+		require.Equal(t, []interface{}{3 * 3600.0}, values(points, heater))
+
+		points = aggregateHistory(query(common.HistoryAggregationDuration, 12*time.Hour), samples, day.Add(48*time.Hour))
+		require.Equal(t, day.Add(12*time.Hour), points[1].Time)
+		require.Equal(t, []interface{}{3 * 3600.0, 0.0}, values(points, heater))
+	})
+
+	t.Run("duration until now", func(t *testing.T) {
+		points := aggregateHistory(query(common.HistoryAggregationDuration, 0), samples[:3], day.Add(8*time.Hour))
+		require.Equal(t, []interface{}{5 * 3600.0}, values(points, heater))
+	})
+
+	t.Run("numeric", func(t *testing.T) {
+		points := aggregateHistory(query(common.HistoryAggregationMean, 12*time.Hour), samples, day)
+		require.Equal(t, []interface{}{22.0, 19.0}, values(points, sensor))
+		require.Equal(t, []interface{}{nil, nil}, values(points, heater))
+
+		points = aggregateHistory(query(common.HistoryAggregationMin, 0), samples, day)
+		require.Equal(t, []interface{}{19.0}, values(points, sensor))
+
+		points = aggregateHistory(query(common.HistoryAggregationMax, 0), samples, day)
+		require.Equal(t, []interface{}{24.0}, values(points, sensor))
+	})
+
+	t.Run("count and last", func(t *testing.T) {
+		points := aggregateHistory(query(common.HistoryAggregationCount, 12*time.Hour), samples, day)
+		require.Equal(t, []interface{}{3, 0}, values(points, heater))
+		require.Equal(t, []interface{}{3, 1}, values(points, sensor))
+
+		points = aggregateHistory(query(common.HistoryAggregationLast, 12*time.Hour), samples, day)
+		require.Equal(t, []interface{}{"OFF", "OFF"}, values(points, heater))
+		require.Equal(t, []interface{}{"n/a", 19.0}, values(points, sensor))
+	})
+}
+
+func TestValidateHistoryQuery(t *testing.T) {
+
+	now := time.Now()
+	valid := func() *models.EntityHistoryQuery {
+		return &models.EntityHistoryQuery{
+			EntityIds: []common.EntityId{"sensor.temp"},
+			Attribute: "climate.temperature",
+			From:      now.Add(-time.Hour),
+			To:        now,
+		}
+	}
+
+	query := valid()
+	path, err := validateHistoryQuery(query)
+	require.NoError(t, err)
+	require.Equal(t, []string{"climate", "temperature"}, path)
+	require.Equal(t, common.HistoryAggregationLast, query.Aggregation)
+
+	for _, update := range []func(q *models.EntityHistoryQuery){
+		func(q *models.EntityHistoryQuery) { q.EntityIds = nil },
+		func(q *models.EntityHistoryQuery) { q.From = q.To },
+		func(q *models.EntityHistoryQuery) { q.Aggregation = "median" },
+		func(q *models.EntityHistoryQuery) { q.Interval = time.Millisecond },
+		func(q *models.EntityHistoryQuery) { q.Attribute = "temp}'" },
+	} {
+		query = valid()
+		update(query)
+		_, err = validateHistoryQuery(query)
+		require.True(t, errors.Is(err, apperr.ErrInvalidRequest))
+	}
+}
@@ -52,9 +52,13 @@ func NewAdaptors(lc fx.Lifecycle,
 		Tag:               GetTagAdaptor(db),
 		Role:              GetRoleAdaptor(db),
 		Permission:        GetPermissionAdaptor(db),
+		EntityPermission:  GetEntityPermissionAdaptor(db),
 		User:              GetUserAdaptor(db),
 		UserMeta:          GetUserMetaAdaptor(db),
 		UserDevice:        GetUserDeviceAdaptor(db),
+		UserSession:       GetUserSessionAdaptor(db),
+		UserTotp:          GetUserTotpAdaptor(db),
+		ApiToken:          GetApiTokenAdaptor(db),
 		Image:             GetImageAdaptor(db),
 		Variable:          GetVariableAdaptor(db),
 		Entity:            GetEntityAdaptor(db, orm),
@@ -77,6 +81,7 @@ func NewAdaptors(lc fx.Lifecycle,
 		Trigger:           GetTriggerAdaptor(db, orm),
 		Task:              GetTaskAdaptor(db, orm),
 		RunHistory:        GetRunHistoryAdaptor(db),
+		TaskRun:           GetTaskRunAdaptor(db),
 		Plugin:            GetPluginAdaptor(db),
 		TelegramChat:      GetTelegramChannelAdaptor(db),
 		Dashboard:         GetDashboardAdaptor(db),
@@ -20,9 +20,12 @@ package adaptors
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/e154/smart-home/internal/db"
 	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 
 	"gorm.io/gorm"
@@ -94,6 +97,21 @@ func (n *MessageDelivery) GetAllUncompleted(ctx context.Context, limit, offset i
 	return
 }
 
+// GetQueued ...
+func (n *MessageDelivery) GetQueued(ctx context.Context, messageType string, entityId *common.EntityId, now time.Time, limit int64) (list []*m.MessageDelivery, err error) {
+	var dbList []*db.MessageDelivery
+	if dbList, err = n.table.GetQueued(ctx, messageType, entityId, now, int(limit)); err != nil {
+		return
+	}
+
+	list = make([]*m.MessageDelivery, 0, len(dbList))
+	for _, dbVer := range dbList {
+		list = append(list, n.fromDb(dbVer))
+	}
+
+	return
+}
+
 // Delete ...
 func (n *MessageDelivery) Delete(ctx context.Context, id int64) (err error) {
 	err = n.table.Delete(ctx, id)
@@ -123,10 +141,20 @@ func (n *MessageDelivery) fromDb(dbVer *db.MessageDelivery) (ver *m.MessageDeliv
 		Status:             m.MessageStatus(dbVer.Status),
 		ErrorMessageStatus: dbVer.ErrorMessageStatus,
 		ErrorMessageBody:   dbVer.ErrorMessageBody,
+		Attempts:           dbVer.Attempts,
+		NextAttemptAt:      dbVer.NextAttemptAt,
+		AttemptLog:         make([]m.MessageDeliveryAttempt, 0),
 		CreatedAt:          dbVer.CreatedAt,
 		UpdatedAt:          dbVer.UpdatedAt,
 	}
 
+	if len(dbVer.AttemptLog) > 0 {
+		_ = json.Unmarshal(dbVer.AttemptLog, &ver.AttemptLog)
+	}
+	if len(dbVer.Fallback) > 0 {
+		_ = json.Unmarshal(dbVer.Fallback, &ver.Fallback)
+	}
+
 	if dbVer.MessageId != 0 {
 		messageAdaptor := GetMessageAdaptor(n.db)
 		ver.Message = messageAdaptor.fromDb(dbVer.Message)
@@ -145,9 +173,23 @@ func (n *MessageDelivery) toDb(ver *m.MessageDelivery) (dbVer *db.MessageDeliver
 		Status:             string(ver.Status),
 		ErrorMessageStatus: ver.ErrorMessageStatus,
 		ErrorMessageBody:   ver.ErrorMessageBody,
+		Attempts:           ver.Attempts,
+		NextAttemptAt:      ver.NextAttemptAt,
 		CreatedAt:          ver.CreatedAt,
 		UpdatedAt:          ver.UpdatedAt,
 	}
 
+	attemptLog := ver.AttemptLog
+	if attemptLog == nil {
+		attemptLog = []m.MessageDeliveryAttempt{}
+	}
+	dbVer.AttemptLog, _ = json.Marshal(attemptLog)
+
+	fallback := ver.Fallback
+	if fallback == nil {
+		fallback = []m.MessageFallback{}
+	}
+	dbVer.Fallback, _ = json.Marshal(fallback)
+
 	return
 }
@@ -37,6 +37,7 @@ var _ adaptors.MetricBucketRepo = (*MetricBucket)(nil)
 // MetricBucket ...
 type MetricBucket struct {
 	table *db.MetricBuckets
+	stats *db.MetricBucketStats
 	db    *gorm.DB
 }
 
@@ -48,6 +49,7 @@ func GetMetricBucketAdaptor(d *gorm.DB, orm *orm.Orm) *MetricBucket {
 	}
 	return &MetricBucket{
 		table: table,
+		stats: &db.MetricBucketStats{Common: table.Common},
 		db:    d,
 	}
 }
@@ -86,6 +88,21 @@ func (n *MetricBucket) List(ctx context.Context, from, to *time.Time, metricId i
 	return
 }
 
+// GetLast ...
+func (n *MetricBucket) GetLast(ctx context.Context, metricIds []int64) (list []*m.MetricDataItem, err error) {
+
+	var dbList []*db.MetricBucket
+	if dbList, err = n.table.GetLast(ctx, metricIds); err != nil {
+		return
+	}
+
+	list = make([]*m.MetricDataItem, len(dbList))
+	for i, dbVer := range dbList {
+		list[i] = n.fromDb(dbVer)
+	}
+	return
+}
+
 // DeleteOldest ...
 func (n *MetricBucket) DeleteOldest(ctx context.Context, days int) (err error) {
 	err = n.table.DeleteOldest(ctx, days)
@@ -100,7 +117,22 @@ func (n *MetricBucket) DeleteById(ctx context.Context, id int64) (err error) {
 
 // DeleteByMetricId ...
 func (n *MetricBucket) DeleteByMetricId(ctx context.Context, metricId int64) (err error) {
-	err = n.table.DeleteByMetricId(ctx, metricId)
+	if err = n.table.DeleteByMetricId(ctx, metricId); err != nil {
+		return
+	}
+	err = n.stats.DeleteByMetricId(ctx, metricId)
+	return
+}
+
+// Rollup ...
+func (n *MetricBucket) Rollup(ctx context.Context) (err error) {
+	err = n.stats.Rollup(ctx)
+	return
+}
+
+// DeleteOldestStats ...
+func (n *MetricBucket) DeleteOldestStats(ctx context.Context, resolution common.StatsResolution, days int) (err error) {
+	err = n.stats.DeleteOldest(ctx, resolution.String(), days)
 	return
 }
 
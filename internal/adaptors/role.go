@@ -158,6 +158,7 @@ func (n *Role) fromDb(dbRole *db.Role) (role *models.Role) {
 	role = &models.Role{
 		Name:        dbRole.Name,
 		Description: dbRole.Description,
+		RequireTotp: dbRole.RequireTotp,
 		CreatedAt:   dbRole.CreatedAt,
 		UpdatedAt:   dbRole.UpdatedAt,
 		Children:    []*models.Role{},
@@ -181,6 +182,7 @@ func (n *Role) toDb(role *models.Role) (dbRole *db.Role) {
 	dbRole = &db.Role{
 		Name:        role.Name,
 		Description: role.Description,
+		RequireTotp: role.RequireTotp,
 	}
 
 	if role.Parent != nil {
@@ -155,6 +155,7 @@ func (n *Script) fromDb(dbVer *db.Script) (ver *m.Script, err error) {
 		Source:      dbVer.Source,
 		Description: dbVer.Description,
 		Compiled:    dbVer.Compiled,
+		Timeout:     dbVer.Timeout,
 		CreatedAt:   dbVer.CreatedAt,
 		UpdatedAt:   dbVer.UpdatedAt,
 		Info: &m.ScriptInfo{
@@ -189,6 +190,7 @@ func (n *Script) toDb(script *m.Script) (dbVer *db.Script) {
 		Source:      script.Source,
 		Description: script.Description,
 		Compiled:    script.Compiled,
+		Timeout:     script.Timeout,
 		CreatedAt:   script.CreatedAt,
 		UpdatedAt:   script.UpdatedAt,
 	}
@@ -20,10 +20,12 @@ package adaptors
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/e154/smart-home/internal/db"
 	"github.com/e154/smart-home/internal/system/orm"
 	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 
 	"gorm.io/gorm"
@@ -61,7 +63,10 @@ func (n *Task) Add(ctx context.Context, ver *m.NewTask) (taskId int64, err error
 		Description: ver.Description,
 		Enabled:     ver.Enabled,
 		Condition:   ver.Condition,
+		Mode:        taskMode(ver.Mode),
+		MaxRuns:     ver.MaxRuns,
 		AreaId:      ver.AreaId,
+		Steps:       taskSteps(ver.Steps),
 	}
 
 	//triggers
@@ -157,10 +162,17 @@ func (n *Task) fromDb(dbVer *db.Task) (ver *m.Task) {
 		Enabled:     dbVer.Enabled,
 		AreaId:      dbVer.AreaId,
 		Condition:   dbVer.Condition,
+		Mode:        dbVer.Mode,
+		MaxRuns:     dbVer.MaxRuns,
 		CreatedAt:   dbVer.CreatedAt,
 		UpdatedAt:   dbVer.UpdatedAt,
 	}
 
+	// steps
+	if len(dbVer.Steps) > 0 {
+		_ = json.Unmarshal(dbVer.Steps, &ver.Steps)
+	}
+
 	// triggers
 	triggerAdaptor := GetTriggerAdaptor(n.db, n.orm)
 	for _, dbVer := range dbVer.Triggers {
@@ -198,7 +210,10 @@ func (n *Task) toDb(ver *m.Task) (dbVer *db.Task) {
 		Description: ver.Description,
 		Enabled:     ver.Enabled,
 		Condition:   ver.Condition,
+		Mode:        taskMode(ver.Mode),
+		MaxRuns:     ver.MaxRuns,
 		AreaId:      ver.AreaId,
+		Steps:       taskSteps(ver.Steps),
 		CreatedAt:   ver.CreatedAt,
 		UpdatedAt:   ver.UpdatedAt,
 	}
@@ -220,3 +235,18 @@ func (n *Task) toDb(ver *m.Task) (dbVer *db.Task) {
 
 	return
 }
+
+func taskMode(mode common.TaskMode) common.TaskMode {
+	if mode == "" {
+		return common.TaskModeSingle
+	}
+	return mode
+}
+
+func taskSteps(steps []*m.TaskStep) json.RawMessage {
+	if steps == nil {
+		steps = make([]*m.TaskStep, 0)
+	}
+	b, _ := json.Marshal(steps)
+	return b
+}
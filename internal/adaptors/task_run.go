@@ -0,0 +1,129 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/e154/smart-home/internal/db"
+	"github.com/e154/smart-home/pkg/adaptors"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+var _ adaptors.TaskRunRepo = (*TaskRun)(nil)
+
+// TaskRun ...
+type TaskRun struct {
+	table *db.TaskRuns
+	db    *gorm.DB
+}
+
+// GetTaskRunAdaptor ...
+func GetTaskRunAdaptor(d *gorm.DB) *TaskRun {
+	return &TaskRun{
+		table: &db.TaskRuns{&db.Common{Db: d}},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *TaskRun) Add(ctx context.Context, run *m.TaskRun) (id int64, err error) {
+	id, err = n.table.Add(ctx, n.toDb(run))
+	return
+}
+
+// GetById ...
+func (n *TaskRun) GetById(ctx context.Context, id int64) (run *m.TaskRun, err error) {
+	var dbVer *db.TaskRun
+	if dbVer, err = n.table.GetById(ctx, id); err != nil {
+		return
+	}
+	run = n.fromDb(dbVer)
+	return
+}
+
+// List ...
+func (n *TaskRun) List(ctx context.Context, limit, offset int64, orderBy, sort string, taskId int64) (list []*m.TaskRun, total int64, err error) {
+	var dbList []*db.TaskRun
+	if dbList, total, err = n.table.List(ctx, int(limit), int(offset), orderBy, sort, taskId); err != nil {
+		return
+	}
+
+	list = make([]*m.TaskRun, len(dbList))
+	for i, dbVer := range dbList {
+		list[i] = n.fromDb(dbVer)
+	}
+	return
+}
+
+// DeleteOldest ...
+func (n *TaskRun) DeleteOldest(ctx context.Context, days int) (err error) {
+	err = n.table.DeleteOldest(ctx, days)
+	return
+}
+
+func (n *TaskRun) fromDb(dbVer *db.TaskRun) (run *m.TaskRun) {
+	run = &m.TaskRun{
+		Id:        dbVer.Id,
+		TaskId:    dbVer.TaskId,
+		TriggerId: dbVer.TriggerId,
+		EntityId:  dbVer.EntityId,
+		Payload:   dbVer.Payload,
+		Status:    dbVer.Status,
+		Error:     dbVer.Error,
+		Start:     dbVer.Start,
+		End:       dbVer.End,
+	}
+
+	// trace
+	if len(dbVer.Trace) > 0 {
+		_ = json.Unmarshal(dbVer.Trace, &run.Trace)
+	}
+
+	return
+}
+
+func (n *TaskRun) toDb(run *m.TaskRun) (dbVer *db.TaskRun) {
+	dbVer = &db.TaskRun{
+		Id:        run.Id,
+		TaskId:    run.TaskId,
+		TriggerId: run.TriggerId,
+		EntityId:  run.EntityId,
+		Payload:   run.Payload,
+		Status:    run.Status,
+		Error:     run.Error,
+		Start:     run.Start,
+		End:       run.End,
+	}
+
+	if len(dbVer.Payload) == 0 {
+		dbVer.Payload = json.RawMessage("{}")
+	}
+
+	trace := run.Trace
+	if trace == nil {
+		trace = make([]*m.TaskRunStep, 0)
+	}
+	dbVer.Trace, _ = json.Marshal(trace)
+
+	return
+}
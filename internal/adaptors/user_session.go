@@ -0,0 +1,146 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/e154/smart-home/internal/db"
+	"github.com/e154/smart-home/pkg/adaptors"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+var _ adaptors.UserSessionRepo = (*UserSession)(nil)
+
+// UserSession ...
+type UserSession struct {
+	table *db.UserSessions
+	db    *gorm.DB
+}
+
+// GetUserSessionAdaptor ...
+func GetUserSessionAdaptor(d *gorm.DB) *UserSession {
+	return &UserSession{
+		table: &db.UserSessions{&db.Common{Db: d}},
+		db:    d,
+	}
+}
+
+// Add ...
+func (n *UserSession) Add(ctx context.Context, session *m.UserSession) (id int64, err error) {
+	id, err = n.table.Add(ctx, n.toDb(session))
+	return
+}
+
+// GetById ...
+func (n *UserSession) GetById(ctx context.Context, id int64) (session *m.UserSession, err error) {
+	var dbVer *db.UserSession
+	if dbVer, err = n.table.GetById(ctx, id); err != nil {
+		return
+	}
+	session = n.fromDb(dbVer)
+	return
+}
+
+// GetByToken ...
+func (n *UserSession) GetByToken(ctx context.Context, token string) (session *m.UserSession, err error) {
+	var dbVer *db.UserSession
+	if dbVer, err = n.table.GetByToken(ctx, token); err != nil {
+		return
+	}
+	session = n.fromDb(dbVer)
+	return
+}
+
+// Rotate ...
+func (n *UserSession) Rotate(ctx context.Context, session *m.UserSession, token string) (ok bool, err error) {
+	ok, err = n.table.Rotate(ctx, n.toDb(session), token)
+	return
+}
+
+// Touch ...
+func (n *UserSession) Touch(ctx context.Context, id int64, lastSeen time.Time) (err error) {
+	err = n.table.Touch(ctx, id, lastSeen)
+	return
+}
+
+// Revoke ...
+func (n *UserSession) Revoke(ctx context.Context, id int64) (err error) {
+	err = n.table.Revoke(ctx, id)
+	return
+}
+
+// RevokeAll ...
+func (n *UserSession) RevokeAll(ctx context.Context, userId int64) (err error) {
+	err = n.table.RevokeAll(ctx, userId)
+	return
+}
+
+// List ...
+func (n *UserSession) List(ctx context.Context, userId int64) (list []*m.UserSession, err error) {
+	var dbList []*db.UserSession
+	if dbList, err = n.table.List(ctx, userId); err != nil {
+		return
+	}
+	list = make([]*m.UserSession, len(dbList))
+	for i, dbVer := range dbList {
+		list[i] = n.fromDb(dbVer)
+	}
+	return
+}
+
+// DeleteOld ...
+func (n *UserSession) DeleteOld(ctx context.Context, before time.Time) (err error) {
+	err = n.table.DeleteOld(ctx, before)
+	return
+}
+
+func (n *UserSession) fromDb(dbVer *db.UserSession) (ver *m.UserSession) {
+	ver = &m.UserSession{
+		Id:            dbVer.Id,
+		UserId:        dbVer.UserId,
+		RefreshToken:  dbVer.RefreshToken,
+		PreviousToken: dbVer.PreviousToken,
+		Device:        dbVer.Device,
+		Ip:            dbVer.Ip,
+		CreatedAt:     dbVer.CreatedAt,
+		LastSeenAt:    dbVer.LastSeenAt,
+		ExpiresAt:     dbVer.ExpiresAt,
+		RevokedAt:     dbVer.RevokedAt,
+	}
+	return
+}
+
+func (n *UserSession) toDb(ver *m.UserSession) (dbVer *db.UserSession) {
+	dbVer = &db.UserSession{
+		Id:            ver.Id,
+		UserId:        ver.UserId,
+		RefreshToken:  ver.RefreshToken,
+		PreviousToken: ver.PreviousToken,
+		Device:        ver.Device,
+		Ip:            ver.Ip,
+		LastSeenAt:    ver.LastSeenAt,
+		ExpiresAt:     ver.ExpiresAt,
+		RevokedAt:     ver.RevokedAt,
+	}
+	return
+}
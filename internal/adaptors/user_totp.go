@@ -0,0 +1,99 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/e154/smart-home/internal/db"
+	"github.com/e154/smart-home/pkg/adaptors"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+var _ adaptors.UserTotpRepo = (*UserTotp)(nil)
+
+// UserTotp ...
+type UserTotp struct {
+	table *db.UserTotps
+	db    *gorm.DB
+}
+
+// GetUserTotpAdaptor ...
+func GetUserTotpAdaptor(d *gorm.DB) *UserTotp {
+	return &UserTotp{
+		table: &db.UserTotps{&db.Common{Db: d}},
+		db:    d,
+	}
+}
+
+// Save ...
+func (n *UserTotp) Save(ctx context.Context, totp *m.UserTotp) (err error) {
+	err = n.table.Save(ctx, n.toDb(totp))
+	return
+}
+
+// GetByUserId ...
+func (n *UserTotp) GetByUserId(ctx context.Context, userId int64) (totp *m.UserTotp, err error) {
+	var dbVer *db.UserTotp
+	if dbVer, err = n.table.GetByUserId(ctx, userId); err != nil {
+		return
+	}
+	totp = n.fromDb(dbVer)
+	return
+}
+
+// Delete ...
+func (n *UserTotp) Delete(ctx context.Context, userId int64) (err error) {
+	err = n.table.Delete(ctx, userId)
+	return
+}
+
+func (n *UserTotp) fromDb(dbVer *db.UserTotp) (ver *m.UserTotp) {
+	ver = &m.UserTotp{
+		UserId:        dbVer.UserId,
+		Secret:        dbVer.Secret,
+		Enabled:       dbVer.Enabled,
+		RecoveryCodes: make([]string, 0),
+		LastCounter:   dbVer.LastCounter,
+		CreatedAt:     dbVer.CreatedAt,
+		UpdatedAt:     dbVer.UpdatedAt,
+	}
+	if len(dbVer.RecoveryCodes) > 0 {
+		_ = json.Unmarshal(dbVer.RecoveryCodes, &ver.RecoveryCodes)
+	}
+	return
+}
+
+func (n *UserTotp) toDb(ver *m.UserTotp) (dbVer *db.UserTotp) {
+	dbVer = &db.UserTotp{
+		UserId:      ver.UserId,
+		Secret:      ver.Secret,
+		Enabled:     ver.Enabled,
+		LastCounter: ver.LastCounter,
+	}
+	codes := ver.RecoveryCodes
+	if codes == nil {
+		codes = []string{}
+	}
+	dbVer.RecoveryCodes, _ = json.Marshal(codes)
+	return
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/e154/smart-home/internal/db"
 	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common/encryptor"
 	"github.com/e154/smart-home/pkg/models"
 
 	"gorm.io/gorm"
@@ -46,7 +47,11 @@ func GetVariableAdaptor(d *gorm.DB) *Variable {
 
 // CreateOrUpdate ...
 func (n *Variable) CreateOrUpdate(ctx context.Context, ver models.Variable) (err error) {
-	err = n.table.CreateOrUpdate(ctx, n.toDb(ver))
+	var dbVer db.Variable
+	if dbVer, err = n.toDb(ver); err != nil {
+		return
+	}
+	err = n.table.CreateOrUpdate(ctx, dbVer)
 	return
 }
 
@@ -110,10 +115,18 @@ func (n *Variable) fromDb(dbVer db.Variable) (ver models.Variable) {
 		Name:      dbVer.Name,
 		Value:     dbVer.Value,
 		System:    dbVer.System,
+		Encrypted: dbVer.Encrypted,
 		CreatedAt: dbVer.CreatedAt,
 		UpdatedAt: dbVer.UpdatedAt,
 		EntityId:  dbVer.EntityId,
 	}
+	if ver.Encrypted && ver.Value != "" {
+		var err error
+		if ver.Value, err = encryptor.Decrypt(dbVer.Value); err != nil {
+			log.Errorf("variable %s: %s", dbVer.Name, err.Error())
+			ver.Value = ""
+		}
+	}
 	// tags
 	for _, tag := range dbVer.Tags {
 		ver.Tags = append(ver.Tags, &models.Tag{
@@ -124,12 +137,18 @@ func (n *Variable) fromDb(dbVer db.Variable) (ver models.Variable) {
 	return
 }
 
-func (n *Variable) toDb(ver models.Variable) (dbVer db.Variable) {
+func (n *Variable) toDb(ver models.Variable) (dbVer db.Variable, err error) {
 	dbVer = db.Variable{
-		Name:     ver.Name,
-		Value:    ver.Value,
-		System:   ver.System,
-		EntityId: ver.EntityId,
+		Name:      ver.Name,
+		Value:     ver.Value,
+		System:    ver.System,
+		Encrypted: ver.Encrypted,
+		EntityId:  ver.EntityId,
+	}
+	if ver.Encrypted && ver.Value != "" {
+		if dbVer.Value, err = encryptor.Encrypt(ver.Value); err != nil {
+			return
+		}
 	}
 	// tags
 	if len(ver.Tags) > 0 {
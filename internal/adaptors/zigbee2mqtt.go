@@ -113,6 +113,7 @@ func (n *Zigbee2mqtt) fromDb(dbVer *db.Zigbee2mqtt) (ver *models.Zigbee2mqtt) {
 		Login:             dbVer.Login,
 		Name:              dbVer.Name,
 		PermitJoin:        dbVer.PermitJoin,
+		AutoDiscovery:     dbVer.AutoDiscovery,
 		BaseTopic:         dbVer.BaseTopic,
 		CreatedAt:         dbVer.CreatedAt,
 		UpdatedAt:         dbVer.UpdatedAt,
@@ -138,6 +139,7 @@ func (n *Zigbee2mqtt) toDb(ver *models.Zigbee2mqtt) (dbVer *db.Zigbee2mqtt) {
 		Login:             ver.Login,
 		Name:              ver.Name,
 		PermitJoin:        ver.PermitJoin,
+		AutoDiscovery:     ver.AutoDiscovery,
 		BaseTopic:         ver.BaseTopic,
 		EncryptedPassword: ver.EncryptedPassword,
 	}
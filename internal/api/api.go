@@ -257,6 +257,8 @@ func (a *Api) registerHandlers() {
 	v1.POST("/backups", a.echoFilter.Auth(wrapper.BackupServiceNewBackup))
 	v1.POST("/backup/upload", a.echoFilter.Auth(wrapper.BackupServiceUploadBackup))
 	v1.POST("/backup/apply", a.echoFilter.Auth(wrapper.BackupServiceApplyState))
+	v1.POST("/backup/export", a.echoFilter.Auth(wrapper.BackupServiceExportConfig))
+	v1.POST("/backup/import", a.echoFilter.Auth(wrapper.BackupServiceImportConfig))
 	v1.POST("/backup/rollback", a.echoFilter.Auth(wrapper.BackupServiceRevertState))
 	v1.PUT("/backup/:name", a.echoFilter.Auth(wrapper.BackupServiceRestoreBackup))
 	v1.DELETE("/backup/:name", a.echoFilter.Auth(wrapper.BackupServiceDeleteBackup))
@@ -295,6 +297,10 @@ func (a *Api) registerHandlers() {
 	v1.GET("/developer_tools/bus/state", a.echoFilter.Auth(wrapper.DeveloperToolsServiceGetEventBusStateList))
 	v1.POST("/developer_tools/entity/reload", a.echoFilter.Auth(wrapper.DeveloperToolsServiceReloadEntity))
 	v1.POST("/developer_tools/entity/set_state", a.echoFilter.Auth(wrapper.DeveloperToolsServiceEntitySetState))
+	v1.GET("/discovery", a.echoFilter.Auth(wrapper.DiscoveryServiceGetDeviceList))
+	v1.POST("/discovery/scan", a.echoFilter.Auth(wrapper.DiscoveryServiceScan))
+	v1.DELETE("/discovery/:id", a.echoFilter.Auth(wrapper.DiscoveryServiceIgnoreDevice))
+	v1.POST("/discovery/:id/accept", a.echoFilter.Auth(wrapper.DiscoveryServiceAcceptDevice))
 	v1.GET("/entities", a.echoFilter.Auth(wrapper.EntityServiceGetEntityList))
 	v1.POST("/entities/import", a.echoFilter.Auth(wrapper.EntityServiceImportEntity))
 	v1.POST("/entity", a.echoFilter.Auth(wrapper.EntityServiceAddEntity))
@@ -305,6 +311,7 @@ func (a *Api) registerHandlers() {
 	v1.POST("/entity/:id/disable", a.echoFilter.Auth(wrapper.EntityServiceDisabledEntity))
 	v1.POST("/entity/:id/enable", a.echoFilter.Auth(wrapper.EntityServiceEnabledEntity))
 	v1.GET("/entity_storage", a.echoFilter.Auth(wrapper.EntityStorageServiceGetEntityStorageList))
+	v1.GET("/entity_storage/history", a.echoFilter.Auth(wrapper.EntityStorageServiceGetEntityStorageHistory))
 	v1.GET("/entities/statistic", a.echoFilter.Auth(wrapper.EntityServiceGetStatistic))
 	v1.POST("/image", a.echoFilter.Auth(wrapper.ImageServiceAddImage))
 	v1.POST("/image/upload", a.echoFilter.Auth(wrapper.ImageServiceUploadImage))
@@ -331,12 +338,16 @@ func (a *Api) registerHandlers() {
 	v1.GET("/plugins/search", a.echoFilter.Auth(wrapper.PluginServiceSearchPlugin))
 	v1.POST("/plugins/upload", a.echoFilter.Auth(wrapper.PluginServiceUploadPlugin))
 	v1.GET("/plugin/:name/readme", a.echoFilter.Auth(wrapper.PluginServiceGetPluginReadme))
+	v1.POST("/refresh_token", wrapper.AuthServiceRefreshToken)
 	v1.POST("/role", a.echoFilter.Auth(wrapper.RoleServiceAddRole))
 	v1.DELETE("/role/:name", a.echoFilter.Auth(wrapper.RoleServiceDeleteRoleByName))
 	v1.GET("/role/:name", a.echoFilter.Auth(wrapper.RoleServiceGetRoleByName))
 	v1.PUT("/role/:name", a.echoFilter.Auth(wrapper.RoleServiceUpdateRoleByName))
 	v1.GET("/role/:name/access_list", a.echoFilter.Auth(wrapper.RoleServiceGetRoleAccessList))
 	v1.PUT("/role/:name/access_list", a.echoFilter.Auth(wrapper.RoleServiceUpdateRoleAccessList))
+	v1.GET("/role/:name/entity_permissions", a.echoFilter.Auth(wrapper.RoleServiceGetEntityPermissionList))
+	v1.POST("/role/:name/entity_permissions", a.echoFilter.Auth(wrapper.RoleServiceAddEntityPermission))
+	v1.DELETE("/role/:name/entity_permissions/:id", a.echoFilter.Auth(wrapper.RoleServiceDeleteEntityPermission))
 	v1.GET("/roles", a.echoFilter.Auth(wrapper.RoleServiceGetRoleList))
 	v1.GET("/roles/search", a.echoFilter.Auth(wrapper.RoleServiceSearchRoleByName))
 	v1.POST("/script", a.echoFilter.Auth(wrapper.ScriptServiceAddScript))
@@ -355,6 +366,9 @@ func (a *Api) registerHandlers() {
 	v1.DELETE("/tag/:id", a.echoFilter.Auth(wrapper.TagServiceDeleteTagById))
 	v1.GET("/tag/:id", a.echoFilter.Auth(wrapper.TagServiceGetTagById))
 	v1.PUT("/tag/:id", a.echoFilter.Auth(wrapper.TagServiceUpdateTagById))
+	v1.DELETE("/session/:id", a.echoFilter.Auth(wrapper.AuthServiceRevokeSession))
+	v1.DELETE("/sessions", a.echoFilter.Auth(wrapper.AuthServiceRevokeAllSessions))
+	v1.GET("/sessions", a.echoFilter.Auth(wrapper.AuthServiceGetSessionList))
 	v1.POST("/signin", wrapper.AuthServiceSignin)
 	v1.POST("/signout", a.echoFilter.Auth(wrapper.AuthServiceSignout))
 	v1.POST("/task", a.echoFilter.Auth(wrapper.AutomationServiceAddTask))
@@ -363,6 +377,9 @@ func (a *Api) registerHandlers() {
 	v1.PUT("/task/:id", a.echoFilter.Auth(wrapper.AutomationServiceUpdateTask))
 	v1.POST("/task/:id/disable", a.echoFilter.Auth(wrapper.AutomationServiceDisableTask))
 	v1.POST("/task/:id/enable", a.echoFilter.Auth(wrapper.AutomationServiceEnableTask))
+	v1.GET("/task/:id/runs", a.echoFilter.Auth(wrapper.AutomationServiceGetTaskRunList))
+	v1.GET("/task_run/:id", a.echoFilter.Auth(wrapper.AutomationServiceGetTaskRun))
+	v1.POST("/task_run/:id/replay", a.echoFilter.Auth(wrapper.AutomationServiceReplayTaskRun))
 	v1.GET("/tasks", a.echoFilter.Auth(wrapper.AutomationServiceGetTaskList))
 	v1.POST("/tasks/import", a.echoFilter.Auth(wrapper.AutomationServiceImportTask))
 	v1.POST("/trigger", a.echoFilter.Auth(wrapper.TriggerServiceAddTrigger))
@@ -378,6 +395,13 @@ func (a *Api) registerHandlers() {
 	v1.DELETE("/user/:id", a.echoFilter.Auth(wrapper.UserServiceDeleteUserById))
 	v1.GET("/user/:id", a.echoFilter.Auth(wrapper.UserServiceGetUserById))
 	v1.PUT("/user/:id", a.echoFilter.Auth(wrapper.UserServiceUpdateUserById))
+	v1.DELETE("/user/:id/api_token/:tokenId", a.echoFilter.Auth(wrapper.UserServiceDeleteApiToken))
+	v1.GET("/user/:id/api_tokens", a.echoFilter.Auth(wrapper.UserServiceGetApiTokenList))
+	v1.POST("/user/:id/api_tokens", a.echoFilter.Auth(wrapper.UserServiceAddApiToken))
+	v1.POST("/user/:id/totp/confirm", a.echoFilter.Auth(wrapper.UserServiceConfirmTotp))
+	v1.POST("/user/:id/totp/disable", a.echoFilter.Auth(wrapper.UserServiceDisableTotp))
+	v1.POST("/user/:id/totp/enroll", a.echoFilter.Auth(wrapper.UserServiceEnrollTotp))
+	v1.POST("/user/:id/totp/recovery_codes", a.echoFilter.Auth(wrapper.UserServiceRegenerateRecoveryCodes))
 	v1.GET("/users", a.echoFilter.Auth(wrapper.UserServiceGetUserList))
 	v1.POST("/variable", a.echoFilter.Auth(wrapper.VariableServiceAddVariable))
 	v1.DELETE("/variable/:name", a.echoFilter.Auth(wrapper.VariableServiceDeleteVariable))
@@ -390,7 +414,16 @@ func (a *Api) registerHandlers() {
 	v1.DELETE("/zigbee2mqtt/bridge/:id", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceDeleteBridgeById))
 	v1.GET("/zigbee2mqtt/bridge/:id", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceGetZigbee2mqttBridge))
 	v1.PUT("/zigbee2mqtt/bridge/:id/bridge", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceUpdateBridgeById))
+	v1.POST("/zigbee2mqtt/bridge/:id/device_bind", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceDeviceBind))
+	v1.POST("/zigbee2mqtt/bridge/:id/device_options", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceDeviceOptions))
+	v1.POST("/zigbee2mqtt/bridge/:id/device_ota_check", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceDeviceOtaCheck))
+	v1.POST("/zigbee2mqtt/bridge/:id/device_ota_update", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceDeviceOtaUpdate))
+	v1.POST("/zigbee2mqtt/bridge/:id/device_unbind", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceDeviceUnbind))
 	v1.GET("/zigbee2mqtt/bridge/:id/devices", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceDeviceList))
+	v1.POST("/zigbee2mqtt/bridge/:id/group_add", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceAddGroup))
+	v1.POST("/zigbee2mqtt/bridge/:id/group_member_add", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceGroupAddMember))
+	v1.POST("/zigbee2mqtt/bridge/:id/group_member_remove", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceGroupRemoveMember))
+	v1.POST("/zigbee2mqtt/bridge/:id/group_remove", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceRemoveGroup))
 	v1.GET("/zigbee2mqtt/bridge/:id/networkmap", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceNetworkmap))
 	v1.POST("/zigbee2mqtt/bridge/:id/networkmap", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceUpdateNetworkmap))
 	v1.POST("/zigbee2mqtt/bridge/:id/reset", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceResetBridgeById))
@@ -400,6 +433,9 @@ func (a *Api) registerHandlers() {
 	v1.GET("/zigbee2mqtt/search_device", a.echoFilter.Auth(wrapper.Zigbee2mqttServiceSearchDevice))
 	v1.GET("/ws", a.echoFilter.Auth(wrapper.StreamServiceSubscribe))
 
+	// prometheus exporter
+	a.echo.GET("/metrics", a.echoFilter.Auth(a.controllers.PrometheusMetrics))
+
 	// static files
 	a.echo.GET("/", echo.WrapHandler(a.controllers.Index(publicAssets.F)))
 	a.echo.GET("/index.html", echo.WrapHandler(a.controllers.Index(publicAssets.F)))
@@ -19,6 +19,7 @@
 package controllers
 
 import (
+	"errors"
 	"github.com/e154/smart-home/internal/api/stub"
 	"github.com/e154/smart-home/internal/common"
 	"github.com/e154/smart-home/pkg/apperr"
@@ -44,29 +45,48 @@ func (c ControllerAuth) AuthServiceSignin(ctx echo.Context) error {
 	username, pass, _ := c.parseBasicAuth(ctx.Request().Header.Get("authorization"))
 
 	var user *m.User
-	var accessToken string
+	var tokens *m.AuthTokens
 
-	var ip string
-	if _ip := ctx.Request().Header.Get("ip"); _ip != "" {
-		if ok, _ := c.validation.ValidVar(_ip, "ip", "required,ipv4"); ok {
-			ip = _ip
+	var err error
+	device := ctx.Request().UserAgent()
+	totpCode := ctx.Request().Header.Get("totp")
+	if user, tokens, err = c.endpoint.Auth.SignIn(ctx.Request().Context(), username, pass, totpCode, c.clientIp(ctx), device); err != nil {
+		// the client asks the code of the two-factor authentication
+		if errors.Is(err, apperr.ErrUserTotpRequired) || errors.Is(err, apperr.ErrUserTotpInvalid) {
+			return c.ERROR(ctx, err)
 		}
+		return c.ERROR(ctx, apperr.ErrUnauthorized)
 	}
 
-	var err error
-	if user, accessToken, err = c.endpoint.Auth.SignIn(ctx.Request().Context(), username, pass, ip); err != nil {
+	return c.HTTP200(ctx, ResponseWithObj(ctx, c.signinResponse(user, tokens)))
+}
+
+// AuthServiceRefreshToken ...
+func (c ControllerAuth) AuthServiceRefreshToken(ctx echo.Context) error {
+
+	obj := &stub.ApiRefreshTokenRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	user, tokens, err := c.endpoint.Auth.RefreshToken(ctx.Request().Context(), obj.RefreshToken, c.clientIp(ctx))
+	if err != nil {
 		return c.ERROR(ctx, apperr.ErrUnauthorized)
 	}
 
+	return c.HTTP200(ctx, ResponseWithObj(ctx, c.signinResponse(user, tokens)))
+}
+
+func (c ControllerAuth) signinResponse(user *m.User, tokens *m.AuthTokens) *stub.ApiSigninResponse {
 	currentUser := &stub.ApiCurrentUser{}
 	_ = common.Copy(&currentUser, &user, common.JsonEngine)
 
-	resp := &stub.ApiSigninResponse{
-		CurrentUser: currentUser,
-		AccessToken: accessToken,
+	return &stub.ApiSigninResponse{
+		CurrentUser:          currentUser,
+		AccessToken:          tokens.AccessToken,
+		AccessTokenExpiresAt: tokens.AccessTokenExpiresAt,
+		RefreshToken:         tokens.RefreshToken,
 	}
-
-	return c.HTTP200(ctx, ResponseWithObj(ctx, resp))
 }
 
 // Signout ...
@@ -77,13 +97,59 @@ func (c ControllerAuth) AuthServiceSignout(ctx echo.Context) error {
 		return c.ERROR(ctx, apperr.ErrUnauthorized)
 	}
 
-	if err = c.endpoint.Auth.SignOut(ctx.Request().Context(), currentUser); err != nil {
+	if err = c.endpoint.Auth.SignOut(ctx.Request().Context(), currentUser, c.accessToken(ctx)); err != nil {
 		return c.ERROR(ctx, apperr.ErrUnauthorized)
 	}
 
 	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
 }
 
+// AuthServiceGetSessionList ...
+func (c ControllerAuth) AuthServiceGetSessionList(ctx echo.Context) error {
+
+	currentUser, err := c.currentUser(ctx)
+	if err != nil {
+		return c.ERROR(ctx, apperr.ErrUnauthorized)
+	}
+
+	list, current, err := c.endpoint.Auth.Sessions(ctx.Request().Context(), currentUser, c.accessToken(ctx))
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, c.dto.User.ToUserSessionListResult(list, current)))
+}
+
+// AuthServiceRevokeSession ...
+func (c ControllerAuth) AuthServiceRevokeSession(ctx echo.Context, id int64) error {
+
+	currentUser, err := c.currentUser(ctx)
+	if err != nil {
+		return c.ERROR(ctx, apperr.ErrUnauthorized)
+	}
+
+	if err = c.endpoint.Auth.RevokeSession(ctx.Request().Context(), currentUser, id); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// AuthServiceRevokeAllSessions ...
+func (c ControllerAuth) AuthServiceRevokeAllSessions(ctx echo.Context) error {
+
+	currentUser, err := c.currentUser(ctx)
+	if err != nil {
+		return c.ERROR(ctx, apperr.ErrUnauthorized)
+	}
+
+	if err = c.endpoint.Auth.RevokeAllSessions(ctx.Request().Context(), currentUser); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
 // AccessList ...
 func (c ControllerAuth) AuthServiceAccessList(ctx echo.Context) error {
 
@@ -117,3 +183,12 @@ func (c ControllerAuth) AuthServicePasswordReset(ctx echo.Context, _ stub.AuthSe
 	}
 	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
 }
+
+func (c ControllerAuth) clientIp(ctx echo.Context) (ip string) {
+	if _ip := ctx.Request().Header.Get("ip"); _ip != "" {
+		if ok, _ := c.validation.ValidVar(_ip, "ip", "required,ipv4"); ok {
+			ip = _ip
+		}
+	}
+	return
+}
@@ -121,6 +121,39 @@ func (c ControllerAutomation) AutomationServiceDisableTask(ctx echo.Context, id
 	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
 }
 
+// GetTaskRunList ...
+func (c ControllerAutomation) AutomationServiceGetTaskRunList(ctx echo.Context, id int64, params stub.AutomationServiceGetTaskRunListParams) error {
+
+	pagination := c.Pagination(params.Page, params.Limit, params.Sort)
+	items, total, err := c.endpoint.Task.RunList(ctx.Request().Context(), id, pagination)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithList(ctx, c.dto.Automation.ToTaskRunListResult(items), total, pagination))
+}
+
+// GetTaskRun ...
+func (c ControllerAutomation) AutomationServiceGetTaskRun(ctx echo.Context, id int64) error {
+
+	run, err := c.endpoint.Task.GetRunById(ctx.Request().Context(), id)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, c.dto.Automation.GetTaskRun(run)))
+}
+
+// ReplayTaskRun ...
+func (c ControllerAutomation) AutomationServiceReplayTaskRun(ctx echo.Context, id int64) error {
+
+	if err := c.endpoint.Task.ReplayRun(ctx.Request().Context(), id); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
 // ImportTask ...
 func (c ControllerAutomation) AutomationServiceImportTask(ctx echo.Context, _ stub.AutomationServiceImportTaskParams) error {
 
@@ -19,8 +19,15 @@
 package controllers
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
 	"github.com/e154/smart-home/internal/api/stub"
 	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
 	"github.com/labstack/echo/v4"
 )
 
@@ -134,3 +141,73 @@ func (c ControllerBackup) BackupServiceRevertState(ctx echo.Context, _ stub.Back
 
 	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
 }
+
+// BackupServiceExportConfig ...
+func (c ControllerBackup) BackupServiceExportConfig(ctx echo.Context, params stub.BackupServiceExportConfigParams) error {
+
+	obj := &stub.ApiConfigExportRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	archive, err := c.endpoint.ConfigArchive.Export(ctx.Request().Context(), c.dto.Backup.ToConfigExportParams(obj))
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	format := archiveFormat(params.Format, "")
+	data, err := archive.Marshal(format)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	var contentType = echo.MIMEApplicationJSON
+	if format == common.ArchiveFormatYaml {
+		contentType = "application/yaml"
+	}
+	name := fmt.Sprintf("config_%s.%s", archive.CreatedAt.Format("2006-01-02T15-04-05"), format)
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+
+	return ctx.Blob(http.StatusOK, contentType, data)
+}
+
+// BackupServiceImportConfig ...
+func (c ControllerBackup) BackupServiceImportConfig(ctx echo.Context, params stub.BackupServiceImportConfigParams) error {
+
+	r := ctx.Request()
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return c.ERROR(ctx, fmt.Errorf("%s: %w", err.Error(), apperr.ErrInvalidRequest))
+	}
+
+	archive, err := m.UnmarshalConfigArchive(data, archiveFormat(params.Format, r.Header.Get(echo.HeaderContentType)))
+	if err != nil {
+		return c.ERROR(ctx, fmt.Errorf("%s: %w", err.Error(), apperr.ErrInvalidRequest))
+	}
+
+	var conflict common.ImportConflict
+	if params.Conflict != nil {
+		conflict = common.ImportConflict(*params.Conflict)
+	}
+
+	result, err := c.endpoint.ConfigArchive.Import(r.Context(), archive, conflict)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, c.dto.Backup.ToConfigImportResult(result)))
+}
+
+// archiveFormat returns yaml if it is requested by the parameter or the content type, json otherwise
+func archiveFormat(format *string, contentType string) common.ArchiveFormat {
+	if format != nil {
+		if strings.EqualFold(*format, string(common.ArchiveFormatYaml)) || strings.EqualFold(*format, "yml") {
+			return common.ArchiveFormatYaml
+		}
+		return common.ArchiveFormatJson
+	}
+	if strings.Contains(contentType, "yaml") {
+		return common.ArchiveFormatYaml
+	}
+	return common.ArchiveFormatJson
+}
@@ -303,6 +303,13 @@ func (c ControllerCommon) currentUser(ctx echo.Context) (*models.User, error) {
 	return user, nil
 }
 
+func (c ControllerCommon) accessToken(ctx echo.Context) string {
+	if accessToken := ctx.Request().Header.Get("authorization"); accessToken != "" {
+		return accessToken
+	}
+	return ctx.Request().URL.Query().Get("access_token")
+}
+
 func (c ControllerCommon) parseBasicAuth(auth string) (username, password string, ok bool) {
 	const prefix = "Basic "
 	// Case insensitive prefix match. See Issue 22736.
@@ -57,6 +57,8 @@ type Controllers struct {
 	*ControllerMessageDelivery
 	*ControllerIndex
 	*ControllerMqtt
+	*ControllerExporter
+	*ControllerDiscovery
 }
 
 // NewControllers ...
@@ -97,5 +99,7 @@ func NewControllers(
 		ControllerMessageDelivery:   NewControllerMessageDelivery(common),
 		ControllerIndex:             NewControllerIndex(common),
 		ControllerMqtt:              NewControllerMqtt(common),
+		ControllerExporter:          NewControllerExporter(common),
+		ControllerDiscovery:         NewControllerDiscovery(common),
 	}
 }
@@ -0,0 +1,83 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package controllers
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/e154/smart-home/internal/api/dto"
+	"github.com/e154/smart-home/internal/api/stub"
+)
+
+// ControllerDiscovery ...
+type ControllerDiscovery struct {
+	*ControllerCommon
+}
+
+// NewControllerDiscovery ...
+func NewControllerDiscovery(common *ControllerCommon) *ControllerDiscovery {
+	return &ControllerDiscovery{
+		ControllerCommon: common,
+	}
+}
+
+// DiscoveryServiceGetDeviceList ...
+func (c ControllerDiscovery) DiscoveryServiceGetDeviceList(ctx echo.Context) error {
+
+	items, err := c.endpoint.Discovery.GetList(ctx.Request().Context())
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, c.dto.Discovery.ToListResult(items)))
+}
+
+// DiscoveryServiceScan ...
+func (c ControllerDiscovery) DiscoveryServiceScan(ctx echo.Context) error {
+
+	c.endpoint.Discovery.Scan(ctx.Request().Context())
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// DiscoveryServiceIgnoreDevice ...
+func (c ControllerDiscovery) DiscoveryServiceIgnoreDevice(ctx echo.Context, id string) error {
+
+	if err := c.endpoint.Discovery.Ignore(ctx.Request().Context(), id); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// DiscoveryServiceAcceptDevice ...
+func (c ControllerDiscovery) DiscoveryServiceAcceptDevice(ctx echo.Context, id string) error {
+
+	obj := &stub.ApiAcceptDiscoveredDeviceRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	entity, err := c.endpoint.Discovery.Accept(ctx.Request().Context(), id, obj.Name, obj.Description, obj.AreaId)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, dto.ToEntity(entity)))
+}
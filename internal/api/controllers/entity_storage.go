@@ -55,3 +55,19 @@ func (c ControllerEntityStorage) EntityStorageServiceGetEntityStorageList(ctx ec
 
 	return c.HTTP200(ctx, ResponseWithList(ctx, c.dto.EntityStorage.ToListResult(items), total, pagination))
 }
+
+// EntityStorageServiceGetEntityStorageHistory ...
+func (c ControllerEntityStorage) EntityStorageServiceGetEntityStorageHistory(ctx echo.Context, params stub.EntityStorageServiceGetEntityStorageHistoryParams) error {
+
+	query, err := c.dto.EntityStorage.FromHistoryParams(params)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	items, err := c.endpoint.EntityStorage.History(ctx.Request().Context(), query)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, c.dto.EntityStorage.ToHistoryResult(items)))
+}
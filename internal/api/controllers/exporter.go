@@ -0,0 +1,62 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/e154/smart-home/internal/system/exporter"
+)
+
+// ControllerExporter ...
+type ControllerExporter struct {
+	*ControllerCommon
+}
+
+// NewControllerExporter ...
+func NewControllerExporter(common *ControllerCommon) *ControllerExporter {
+	return &ControllerExporter{
+		ControllerCommon: common,
+	}
+}
+
+// PrometheusMetrics the samples in the Prometheus text format,
+// the entities are filtered by the "tags" and "area" query params
+// (GET /metrics)
+func (c ControllerExporter) PrometheusMetrics(ctx echo.Context) error {
+
+	var area *string
+	if value := ctx.QueryParam("area"); value != "" {
+		area = &value
+	}
+
+	samples, err := c.endpoint.Exporter.Samples(ctx.Request().Context(), ctx.QueryParams()["tags"], area)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err = exporter.WritePrometheus(buf, samples); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return ctx.Blob(http.StatusOK, exporter.ContentTypePrometheus, buf.Bytes())
+}
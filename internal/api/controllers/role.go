@@ -142,3 +142,40 @@ func (c ControllerRole) RoleServiceDeleteRoleByName(ctx echo.Context, name strin
 
 	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
 }
+
+// RoleServiceGetEntityPermissionList ...
+func (c ControllerRole) RoleServiceGetEntityPermissionList(ctx echo.Context, name string) error {
+
+	items, err := c.endpoint.Role.GetEntityPermissions(ctx.Request().Context(), name)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, c.dto.Role.ToEntityPermissionListResult(items)))
+}
+
+// RoleServiceAddEntityPermission ...
+func (c ControllerRole) RoleServiceAddEntityPermission(ctx echo.Context, name string) error {
+
+	obj := &stub.ApiNewEntityPermissionRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	permission, err := c.endpoint.Role.AddEntityPermission(ctx.Request().Context(), c.dto.Role.FromNewEntityPermissionRequest(obj, name))
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP201(ctx, ResponseWithObj(ctx, c.dto.Role.ToEntityPermission(permission)))
+}
+
+// RoleServiceDeleteEntityPermission ...
+func (c ControllerRole) RoleServiceDeleteEntityPermission(ctx echo.Context, name string, id int64) error {
+
+	if err := c.endpoint.Role.DeleteEntityPermission(ctx.Request().Context(), name, id); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
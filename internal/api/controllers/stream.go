@@ -43,7 +43,7 @@ func (c ControllerStream) StreamServiceSubscribe(ctx echo.Context) error {
 		return c.ERROR(ctx, apperr.ErrUnauthorized)
 	}
 
-	if err = c.endpoint.Stream.Subscribe(ctx, currentUser); err != nil {
+	if err = c.endpoint.Stream.Subscribe(ctx, currentUser, c.accessToken(ctx)); err != nil {
 		log.Error(err.Error())
 		return c.ERROR(ctx, err)
 	}
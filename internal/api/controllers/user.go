@@ -123,3 +123,108 @@ func (c ControllerUser) UserServiceDeleteUserById(ctx echo.Context, id int64) er
 
 	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
 }
+
+// UserServiceGetApiTokenList ...
+func (c ControllerUser) UserServiceGetApiTokenList(ctx echo.Context, id int64) error {
+
+	list, err := c.endpoint.User.GetApiTokens(ctx.Request().Context(), id)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, c.dto.User.ToApiTokenListResult(list)))
+}
+
+// UserServiceAddApiToken ...
+func (c ControllerUser) UserServiceAddApiToken(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiNewApiTokenRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	apiToken, token, err := c.endpoint.User.AddApiToken(ctx.Request().Context(), id, c.dto.User.AddApiTokenRequest(obj))
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, &stub.ApiNewApiTokenResult{
+		ApiToken: c.dto.User.ToApiToken(apiToken),
+		Token:    token,
+	}))
+}
+
+// UserServiceDeleteApiToken ...
+func (c ControllerUser) UserServiceDeleteApiToken(ctx echo.Context, id int64, tokenId int64) error {
+
+	if err := c.endpoint.User.DeleteApiToken(ctx.Request().Context(), id, tokenId); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// UserServiceEnrollTotp ...
+func (c ControllerUser) UserServiceEnrollTotp(ctx echo.Context, id int64) error {
+
+	enrolment, err := c.endpoint.User.EnrollTotp(ctx.Request().Context(), id)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, &stub.ApiTotpEnrolment{
+		Secret: enrolment.Secret,
+		Uri:    enrolment.Uri,
+	}))
+}
+
+// UserServiceConfirmTotp ...
+func (c ControllerUser) UserServiceConfirmTotp(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiTotpCodeRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	codes, err := c.endpoint.User.ConfirmTotp(ctx.Request().Context(), id, obj.Code)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, &stub.ApiTotpRecoveryCodes{
+		RecoveryCodes: codes,
+	}))
+}
+
+// UserServiceDisableTotp ...
+func (c ControllerUser) UserServiceDisableTotp(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiTotpCodeRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	if err := c.endpoint.User.DisableTotp(ctx.Request().Context(), id, obj.Code); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// UserServiceRegenerateRecoveryCodes ...
+func (c ControllerUser) UserServiceRegenerateRecoveryCodes(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiTotpCodeRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	codes, err := c.endpoint.User.RegenerateRecoveryCodes(ctx.Request().Context(), id, obj.Code)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, &stub.ApiTotpRecoveryCodes{
+		RecoveryCodes: codes,
+	}))
+}
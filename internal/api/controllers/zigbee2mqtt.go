@@ -209,3 +209,147 @@ func (c ControllerZigbee2mqtt) Zigbee2mqttServiceDeviceList(ctx echo.Context, id
 
 	return c.HTTP200(ctx, ResponseWithList(ctx, c.dto.Zigbee2mqtt.ToListResult(items), total, pagination))
 }
+
+// AddGroup ...
+func (c ControllerZigbee2mqtt) Zigbee2mqttServiceAddGroup(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiZigbee2mqttGroupRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	err := c.endpoint.Zigbee2mqtt.AddGroup(ctx.Request().Context(), id, obj.Name)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// RemoveGroup ...
+func (c ControllerZigbee2mqtt) Zigbee2mqttServiceRemoveGroup(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiZigbee2mqttGroupRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	err := c.endpoint.Zigbee2mqtt.RemoveGroup(ctx.Request().Context(), id, obj.Name)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// GroupAddMember ...
+func (c ControllerZigbee2mqtt) Zigbee2mqttServiceGroupAddMember(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiZigbee2mqttGroupMemberRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	err := c.endpoint.Zigbee2mqtt.GroupAddMember(ctx.Request().Context(), id, obj.Group, obj.Device)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// GroupRemoveMember ...
+func (c ControllerZigbee2mqtt) Zigbee2mqttServiceGroupRemoveMember(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiZigbee2mqttGroupMemberRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	err := c.endpoint.Zigbee2mqtt.GroupRemoveMember(ctx.Request().Context(), id, obj.Group, obj.Device)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// DeviceOtaCheck ...
+func (c ControllerZigbee2mqtt) Zigbee2mqttServiceDeviceOtaCheck(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiZigbee2mqttDeviceRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	updateAvailable, err := c.endpoint.Zigbee2mqtt.DeviceOtaCheck(ctx.Request().Context(), id, obj.FriendlyName)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, &stub.ApiZigbee2mqttOtaCheckResult{UpdateAvailable: updateAvailable}))
+}
+
+// DeviceOtaUpdate ...
+func (c ControllerZigbee2mqtt) Zigbee2mqttServiceDeviceOtaUpdate(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiZigbee2mqttDeviceRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	err := c.endpoint.Zigbee2mqtt.DeviceOtaUpdate(ctx.Request().Context(), id, obj.FriendlyName)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// DeviceOptions ...
+func (c ControllerZigbee2mqtt) Zigbee2mqttServiceDeviceOptions(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiZigbee2mqttDeviceOptionsRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	err := c.endpoint.Zigbee2mqtt.DeviceOptions(ctx.Request().Context(), id, obj.FriendlyName, obj.Options)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// DeviceBind ...
+func (c ControllerZigbee2mqtt) Zigbee2mqttServiceDeviceBind(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiZigbee2mqttBindRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	err := c.endpoint.Zigbee2mqtt.DeviceBind(ctx.Request().Context(), id, obj.From, obj.To, obj.Clusters)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
+
+// DeviceUnbind ...
+func (c ControllerZigbee2mqtt) Zigbee2mqttServiceDeviceUnbind(ctx echo.Context, id int64) error {
+
+	obj := &stub.ApiZigbee2mqttBindRequest{}
+	if err := c.Body(ctx, obj); err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	err := c.endpoint.Zigbee2mqtt.DeviceUnbind(ctx.Request().Context(), id, obj.From, obj.To, obj.Clusters)
+	if err != nil {
+		return c.ERROR(ctx, err)
+	}
+
+	return c.HTTP200(ctx, ResponseWithObj(ctx, struct{}{}))
+}
@@ -19,6 +19,8 @@
 package dto
 
 import (
+	"encoding/json"
+
 	"github.com/e154/smart-home/internal/api/stub"
 	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
@@ -47,6 +49,15 @@ func (r Automation) AddTask(obj *stub.ApiNewTaskRequest) (task *m.NewTask) {
 		ActionIds:    obj.ActionIds,
 		AreaId:       obj.AreaId,
 	}
+	if obj.Mode != nil {
+		task.Mode = common.TaskMode(*obj.Mode)
+	}
+	if obj.MaxRuns != nil {
+		task.MaxRuns = int(*obj.MaxRuns)
+	}
+	if obj.Steps != nil {
+		task.Steps = ImportTaskSteps(*obj.Steps)
+	}
 	return
 }
 
@@ -63,6 +74,15 @@ func (r Automation) UpdateTask(obj *stub.AutomationServiceUpdateTaskJSONBody, id
 		ActionIds:    obj.ActionIds,
 		AreaId:       obj.AreaId,
 	}
+	if obj.Mode != nil {
+		task.Mode = common.TaskMode(*obj.Mode)
+	}
+	if obj.MaxRuns != nil {
+		task.MaxRuns = int(*obj.MaxRuns)
+	}
+	if obj.Steps != nil {
+		task.Steps = ImportTaskSteps(*obj.Steps)
+	}
 
 	return
 }
@@ -91,6 +111,8 @@ func (r Automation) GetTask(task *m.Task) (obj *stub.ApiTask) {
 		Area:        GetStubArea(task.Area),
 		AreaId:      task.AreaId,
 		Condition:   string(task.Condition),
+		Mode:        string(task.Mode),
+		MaxRuns:     int32(task.MaxRuns),
 		CreatedAt:   task.CreatedAt,
 		UpdatedAt:   task.UpdatedAt,
 	}
@@ -122,6 +144,12 @@ func (r Automation) GetTask(task *m.Task) (obj *stub.ApiTask) {
 		obj.ActionIds = append(obj.ActionIds, action.Id)
 	}
 
+	// steps
+	if task.Steps != nil {
+		steps := GetStubTaskSteps(task.Steps)
+		obj.Steps = &steps
+	}
+
 	// telemetry
 	if task.Telemetry != nil {
 		obj.Telemetry = make([]stub.ApiTelemetryItem, 0)
@@ -146,3 +174,141 @@ func (r Automation) GetTask(task *m.Task) (obj *stub.ApiTask) {
 
 	return
 }
+
+// ToTaskRunListResult ...
+func (r Automation) ToTaskRunListResult(list []*m.TaskRun) []*stub.ApiTaskRun {
+
+	items := make([]*stub.ApiTaskRun, 0, len(list))
+
+	for _, i := range list {
+		items = append(items, r.GetTaskRun(i))
+	}
+
+	return items
+}
+
+// GetTaskRun ...
+func (r Automation) GetTaskRun(run *m.TaskRun) (obj *stub.ApiTaskRun) {
+
+	obj = &stub.ApiTaskRun{
+		Id:        run.Id,
+		TaskId:    run.TaskId,
+		TriggerId: run.TriggerId,
+		Status:    string(run.Status),
+		Error:     run.Error,
+		Trace:     make([]stub.ApiTaskRunStep, 0, len(run.Trace)),
+		Start:     run.Start,
+		End:       run.End,
+	}
+
+	if run.EntityId != nil {
+		obj.EntityId = common.String(run.EntityId.String())
+	}
+
+	if len(run.Payload) > 0 {
+		var payload = make(map[string]interface{})
+		if err := json.Unmarshal(run.Payload, &payload); err == nil {
+			obj.Payload = &payload
+		}
+	}
+
+	for _, item := range run.Trace {
+		step := stub.ApiTaskRunStep{
+			Type:     item.Type,
+			Id:       item.Id,
+			Level:    int32(item.Level),
+			Start:    item.Start,
+			Duration: int64(item.Duration),
+		}
+		if item.Result != "" {
+			step.Result = common.String(item.Result)
+		}
+		if item.Error != "" {
+			step.Error = common.String(item.Error)
+		}
+		obj.Trace = append(obj.Trace, step)
+	}
+
+	return
+}
+
+// ImportTaskSteps ...
+func ImportTaskSteps(from []stub.ApiTaskStep) (steps []*m.TaskStep) {
+	steps = make([]*m.TaskStep, 0, len(from))
+	for _, item := range from {
+		step := &m.TaskStep{
+			Type:        common.StepType(item.Type),
+			ActionId:    item.ActionId,
+			ConditionId: item.ConditionId,
+			EntityId:    common.NewEntityIdFromPtr(item.EntityId),
+			State:       common.StringValue(item.State),
+		}
+		if item.Delay != nil {
+			step.Delay = int(*item.Delay)
+		}
+		if item.Timeout != nil {
+			step.Timeout = int(*item.Timeout)
+		}
+		if item.ContinueOnTimeout != nil {
+			step.ContinueOnTimeout = *item.ContinueOnTimeout
+		}
+		if item.MaxIterations != nil {
+			step.MaxIterations = int(*item.MaxIterations)
+		}
+		if item.Then != nil {
+			step.Then = ImportTaskSteps(*item.Then)
+		}
+		if item.Else != nil {
+			step.Else = ImportTaskSteps(*item.Else)
+		}
+		if item.Steps != nil {
+			step.Steps = ImportTaskSteps(*item.Steps)
+		}
+		steps = append(steps, step)
+	}
+	return
+}
+
+// GetStubTaskSteps ...
+func GetStubTaskSteps(from []*m.TaskStep) (steps []stub.ApiTaskStep) {
+	steps = make([]stub.ApiTaskStep, 0, len(from))
+	for _, item := range from {
+		step := stub.ApiTaskStep{
+			Type:        stub.ApiTaskStepType(item.Type),
+			ActionId:    item.ActionId,
+			ConditionId: item.ConditionId,
+		}
+		if item.Delay > 0 {
+			step.Delay = common.Int32(int32(item.Delay))
+		}
+		if item.EntityId != nil {
+			step.EntityId = common.String(item.EntityId.String())
+		}
+		if item.State != "" {
+			step.State = common.String(item.State)
+		}
+		if item.Timeout > 0 {
+			step.Timeout = common.Int32(int32(item.Timeout))
+		}
+		if item.ContinueOnTimeout {
+			step.ContinueOnTimeout = common.Bool(true)
+		}
+		if item.MaxIterations > 0 {
+			step.MaxIterations = common.Int32(int32(item.MaxIterations))
+		}
+		if len(item.Then) > 0 {
+			then := GetStubTaskSteps(item.Then)
+			step.Then = &then
+		}
+		if len(item.Else) > 0 {
+			elseSteps := GetStubTaskSteps(item.Else)
+			step.Else = &elseSteps
+		}
+		if len(item.Steps) > 0 {
+			nested := GetStubTaskSteps(item.Steps)
+			step.Steps = &nested
+		}
+		steps = append(steps, step)
+	}
+	return
+}
@@ -20,6 +20,7 @@ package dto
 
 import (
 	"github.com/e154/smart-home/internal/api/stub"
+	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 )
 
@@ -45,3 +46,55 @@ func (b *Backup) ToBackupListResult(images []*m.Backup) []*stub.ApiBackup {
 
 	return items
 }
+
+// ToConfigExportParams ...
+func (b *Backup) ToConfigExportParams(obj *stub.ApiConfigExportRequest) *m.ConfigExportParams {
+	params := &m.ConfigExportParams{}
+	if obj.Scripts != nil {
+		params.Scripts = *obj.Scripts
+	}
+	if obj.Areas != nil {
+		params.Areas = *obj.Areas
+	}
+	if obj.Entities != nil {
+		for _, id := range *obj.Entities {
+			params.Entities = append(params.Entities, common.EntityId(id))
+		}
+	}
+	if obj.Variables != nil {
+		params.Variables = *obj.Variables
+	}
+	if obj.Conditions != nil {
+		params.Conditions = *obj.Conditions
+	}
+	if obj.Triggers != nil {
+		params.Triggers = *obj.Triggers
+	}
+	if obj.Actions != nil {
+		params.Actions = *obj.Actions
+	}
+	if obj.Tasks != nil {
+		params.Tasks = *obj.Tasks
+	}
+	if obj.Dashboards != nil {
+		params.Dashboards = *obj.Dashboards
+	}
+	return params
+}
+
+// ToConfigImportResult ...
+func (b *Backup) ToConfigImportResult(result *m.ConfigImportResult) *stub.ApiConfigImportResult {
+	var items = make([]stub.ApiConfigImportItem, 0, len(result.Items))
+	for _, item := range result.Items {
+		items = append(items, stub.ApiConfigImportItem{
+			Name:   item.Name,
+			NewId:  item.NewId,
+			OldId:  item.OldId,
+			Status: string(item.Status),
+			Type:   item.Type,
+		})
+	}
+	return &stub.ApiConfigImportResult{
+		Items: items,
+	}
+}
@@ -20,6 +20,7 @@ package dto
 
 import (
 	"github.com/e154/smart-home/internal/api/stub"
+	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 )
 
@@ -37,6 +38,7 @@ func (r Condition) AddCondition(from *stub.ApiNewConditionRequest) (action *m.Co
 		Name:        from.Name,
 		Description: from.Description,
 		ScriptId:    from.ScriptId,
+		Rule:        ImportConditionRule(from.Rule),
 		AreaId:      from.AreaId,
 	}
 	return
@@ -49,6 +51,7 @@ func (r Condition) UpdateCondition(from *stub.ConditionServiceUpdateConditionJSO
 		Name:        from.Name,
 		Description: from.Description,
 		ScriptId:    from.ScriptId,
+		Rule:        ImportConditionRule(from.Rule),
 		AreaId:      from.AreaId,
 	}
 	return
@@ -80,6 +83,7 @@ func (r Condition) ToListResult(list []*m.Condition) []*stub.ApiCondition {
 			Name:        cond.Name,
 			Description: cond.Description,
 			ScriptId:    cond.ScriptId,
+			Rule:        GetStubConditionRule(cond.Rule),
 			AreaId:      cond.AreaId,
 			Area:        GetStubArea(cond.Area),
 			CreatedAt:   cond.CreatedAt,
@@ -100,6 +104,7 @@ func ToCondition(cond *m.Condition) (obj *stub.ApiCondition) {
 		Name:        cond.Name,
 		Description: cond.Description,
 		ScriptId:    cond.ScriptId,
+		Rule:        GetStubConditionRule(cond.Rule),
 		AreaId:      cond.AreaId,
 		Script:      GetStubScript(cond.Script),
 		Area:        GetStubArea(cond.Area),
@@ -109,3 +114,89 @@ func ToCondition(cond *m.Condition) (obj *stub.ApiCondition) {
 
 	return
 }
+
+// ImportConditionRule ...
+func ImportConditionRule(from *stub.ApiConditionRule) (rule *m.ConditionRule) {
+	if from == nil {
+		return
+	}
+	rule = &m.ConditionRule{
+		Type:      common.ConditionRuleType(from.Type),
+		EntityId:  common.NewEntityIdFromPtr(from.EntityId),
+		State:     common.StringValue(from.State),
+		Attribute: common.StringValue(from.Attribute),
+		Above:     from.Above,
+		Below:     from.Below,
+		After:     common.StringValue(from.After),
+		Before:    common.StringValue(from.Before),
+		Template:  common.StringValue(from.Template),
+	}
+	if from.NotEqual != nil {
+		rule.NotEqual = *from.NotEqual
+	}
+	if from.For != nil {
+		rule.For = int(*from.For)
+	}
+	if from.Weekdays != nil {
+		for _, day := range *from.Weekdays {
+			rule.Weekdays = append(rule.Weekdays, int(day))
+		}
+	}
+	if from.Rules != nil {
+		for _, nested := range *from.Rules {
+			rule.Rules = append(rule.Rules, ImportConditionRule(&nested))
+		}
+	}
+	return
+}
+
+// GetStubConditionRule ...
+func GetStubConditionRule(from *m.ConditionRule) (rule *stub.ApiConditionRule) {
+	if from == nil {
+		return
+	}
+	rule = &stub.ApiConditionRule{
+		Type:  stub.ApiConditionRuleType(from.Type),
+		Above: from.Above,
+		Below: from.Below,
+	}
+	if from.EntityId != nil {
+		rule.EntityId = common.String(from.EntityId.String())
+	}
+	if from.State != "" {
+		rule.State = common.String(from.State)
+	}
+	if from.NotEqual {
+		rule.NotEqual = common.Bool(true)
+	}
+	if from.Attribute != "" {
+		rule.Attribute = common.String(from.Attribute)
+	}
+	if from.For > 0 {
+		rule.For = common.Int32(int32(from.For))
+	}
+	if from.After != "" {
+		rule.After = common.String(from.After)
+	}
+	if from.Before != "" {
+		rule.Before = common.String(from.Before)
+	}
+	if len(from.Weekdays) > 0 {
+		weekdays := make([]int32, 0, len(from.Weekdays))
+		for _, day := range from.Weekdays {
+			weekdays = append(weekdays, int32(day))
+		}
+		rule.Weekdays = &weekdays
+	}
+	if from.Template != "" {
+		rule.Template = common.String(from.Template)
+	}
+	if len(from.Rules) > 0 {
+		rules := make([]stub.ApiConditionRule, 0, len(from.Rules))
+		for _, nested := range from.Rules {
+			rules = append(rules, *GetStubConditionRule(nested))
+		}
+		rule.Rules = &rules
+	}
+	return
+}
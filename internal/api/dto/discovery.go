@@ -0,0 +1,77 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package dto
+
+import (
+	"github.com/e154/smart-home/internal/api/stub"
+	"github.com/e154/smart-home/internal/system/discovery"
+)
+
+// Discovery ...
+type Discovery struct{}
+
+// NewDiscoveryDto ...
+func NewDiscoveryDto() Discovery {
+	return Discovery{}
+}
+
+// ToListResult ...
+func (_ Discovery) ToListResult(list []*discovery.Device) *stub.ApiGetDiscoveredDeviceListResult {
+	var items = make([]stub.ApiDiscoveredDevice, 0, len(list))
+	for _, device := range list {
+		items = append(items, ToDiscoveredDevice(device))
+	}
+	return &stub.ApiGetDiscoveredDeviceListResult{
+		Items: items,
+	}
+}
+
+// ToDiscoveredDevice ...
+func ToDiscoveredDevice(device *discovery.Device) (obj stub.ApiDiscoveredDevice) {
+	obj = stub.ApiDiscoveredDevice{
+		Id:           device.Id,
+		Name:         device.Name,
+		Kind:         string(device.Kind),
+		Plugin:       device.Plugin,
+		EntityId:     discovery.NewEntity(device).Id.String(),
+		Address:      device.Address,
+		Port:         int32(device.Port),
+		Model:        device.Model,
+		Manufacturer: device.Manufacturer,
+		Services:     make([]stub.ApiDiscoveredService, 0, len(device.Services)),
+		FirstSeen:    device.FirstSeen,
+		LastSeen:     device.LastSeen,
+	}
+	for _, service := range device.Services {
+		item := stub.ApiDiscoveredService{
+			Source:   stub.ApiDiscoveredServiceSource(service.Source),
+			Type:     service.Type,
+			Name:     service.Name,
+			Host:     service.Host,
+			Port:     int32(service.Port),
+			Txt:      service.Txt,
+			LastSeen: service.LastSeen,
+		}
+		if service.Location != "" {
+			item.Location = &service.Location
+		}
+		obj.Services = append(obj.Services, item)
+	}
+	return
+}
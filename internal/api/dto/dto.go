@@ -44,6 +44,7 @@ type Dto struct {
 	DeveloperTools    DeveloperTools
 	Mqtt              Mqtt
 	Backup            Backup
+	Discovery         Discovery
 }
 
 // NewDto ...
@@ -72,5 +73,6 @@ func NewDto() Dto {
 		DeveloperTools:    NewDeveloperToolsDto(),
 		Mqtt:              NewMqttDto(),
 		Backup:            NewBackupDto(),
+		Discovery:         NewDiscoveryDto(),
 	}
 }
@@ -19,7 +19,12 @@
 package dto
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/e154/smart-home/internal/api/stub"
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 )
 
@@ -51,3 +56,54 @@ func (_ EntityStorage) ToListResult(list *m.EntityStorageList) []*stub.ApiEntity
 
 	return items
 }
+
+// FromHistoryParams ...
+func (_ EntityStorage) FromHistoryParams(params stub.EntityStorageServiceGetEntityStorageHistoryParams) (query *m.EntityHistoryQuery, err error) {
+
+	query = &m.EntityHistoryQuery{}
+
+	if params.EntityId != nil {
+		for _, item := range *params.EntityId {
+			query.EntityIds = append(query.EntityIds, common.EntityId(item))
+		}
+	}
+	if params.StartDate != nil {
+		query.From = *params.StartDate
+	}
+	if params.EndDate != nil {
+		query.To = *params.EndDate
+	}
+	if params.Attribute != nil {
+		query.Attribute = *params.Attribute
+	}
+	if params.Aggregation != nil {
+		query.Aggregation = common.HistoryAggregation(*params.Aggregation)
+	}
+	if params.State != nil {
+		query.State = *params.State
+	}
+	if params.Interval != nil && *params.Interval != "" {
+		if query.Interval, err = time.ParseDuration(*params.Interval); err != nil {
+			err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrInvalidRequest)
+		}
+	}
+
+	return
+}
+
+// ToHistoryResult ...
+func (_ EntityStorage) ToHistoryResult(list []*m.EntityHistoryPoint) *stub.ApiGetEntityHistoryResult {
+
+	items := make([]stub.ApiEntityHistoryPoint, 0, len(list))
+	for _, item := range list {
+		items = append(items, stub.ApiEntityHistoryPoint{
+			EntityId: item.EntityId.String(),
+			Time:     item.Time,
+			Value:    item.Value,
+		})
+	}
+
+	return &stub.ApiGetEntityHistoryResult{
+		Items: items,
+	}
+}
@@ -50,9 +50,27 @@ func (m MessageDelivery) ToMessageDelivery(message *models.MessageDelivery) (obj
 		Status:             string(message.Status),
 		ErrorMessageStatus: message.ErrorMessageStatus,
 		ErrorMessageBody:   message.ErrorMessageBody,
+		Attempts:           int32(message.Attempts),
+		NextAttemptAt:      message.NextAttemptAt,
+		AttemptLog:         make([]stub.ApiMessageDeliveryAttempt, 0, len(message.AttemptLog)),
+		Fallback:           make([]string, 0, len(message.Fallback)),
 		CreatedAt:          message.CreatedAt,
 		UpdatedAt:          message.UpdatedAt,
 	}
+	for _, attempt := range message.AttemptLog {
+		obj.AttemptLog = append(obj.AttemptLog, stub.ApiMessageDeliveryAttempt{
+			Attempt:   int32(attempt.Attempt),
+			Error:     attempt.Error,
+			CreatedAt: attempt.CreatedAt,
+		})
+	}
+	for _, fallback := range message.Fallback {
+		if fallback.EntityId != nil {
+			obj.Fallback = append(obj.Fallback, fallback.EntityId.String())
+			continue
+		}
+		obj.Fallback = append(obj.Fallback, fallback.Type)
+	}
 	return
 }
 
@@ -21,6 +21,7 @@ package dto
 import (
 	"github.com/e154/smart-home/internal/api/stub"
 	"github.com/e154/smart-home/internal/system/rbac/access_list"
+	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 )
 
@@ -38,6 +39,9 @@ func (r Role) FromNewRoleRequest(from *stub.ApiNewRoleRequest) (to *m.Role) {
 		Name:        from.Name,
 		Description: from.Description,
 	}
+	if from.RequireTotp != nil {
+		to.RequireTotp = *from.RequireTotp
+	}
 	if from.Parent != nil {
 		to.Parent = &m.Role{
 			Name: *from.Parent,
@@ -51,6 +55,7 @@ func (r Role) FromUpdateRoleRequest(from *stub.RoleServiceUpdateRoleByNameJSONBo
 	to = &m.Role{
 		Name:        name,
 		Description: from.Description,
+		RequireTotp: from.RequireTotp,
 	}
 	if from.Parent != nil {
 		to.Parent = &m.Role{
@@ -92,6 +97,7 @@ func (r Role) GetStubRole(from *m.Role) (to stub.ApiRole) {
 		Name:        from.Name,
 		Description: from.Description,
 		AccessList:  nil,
+		RequireTotp: from.RequireTotp,
 		CreatedAt:   from.CreatedAt,
 		UpdatedAt:   from.UpdatedAt,
 	}
@@ -189,3 +195,42 @@ func (r Role) UpdateRoleAccessList(req *stub.RoleServiceUpdateRoleAccessListJSON
 
 	return
 }
+
+// FromNewEntityPermissionRequest ...
+func (r Role) FromNewEntityPermissionRequest(from *stub.ApiNewEntityPermissionRequest, name string) (to *m.EntityPermission) {
+	to = &m.EntityPermission{
+		RoleName: name,
+		AreaId:   from.AreaId,
+		Tag:      from.Tag,
+		Level:    common.EntityAccessLevel(from.Level),
+	}
+	to.EntityId = common.NewEntityIdFromPtr(from.EntityId)
+	return
+}
+
+// ToEntityPermission ...
+func (r Role) ToEntityPermission(from *m.EntityPermission) (to stub.ApiEntityPermission) {
+	to = stub.ApiEntityPermission{
+		Id:        from.Id,
+		RoleName:  from.RoleName,
+		AreaId:    from.AreaId,
+		Tag:       from.Tag,
+		Level:     from.Level.String(),
+		CreatedAt: from.CreatedAt,
+	}
+	if from.EntityId != nil {
+		to.EntityId = common.String(from.EntityId.String())
+	}
+	return
+}
+
+// ToEntityPermissionListResult ...
+func (r Role) ToEntityPermissionListResult(list []*m.EntityPermission) *stub.ApiGetEntityPermissionListResult {
+	items := make([]stub.ApiEntityPermission, 0, len(list))
+	for _, item := range list {
+		items = append(items, r.ToEntityPermission(item))
+	}
+	return &stub.ApiGetEntityPermissionListResult{
+		Items: items,
+	}
+}
@@ -40,6 +40,9 @@ func (s Script) FromNewScriptRequest(req *stub.ApiNewScriptRequest) (script *m.S
 		Source:      req.Source,
 		Description: req.Description,
 	}
+	if req.Timeout != nil {
+		script.Timeout = int(*req.Timeout)
+	}
 	return
 }
 
@@ -52,6 +55,9 @@ func (s Script) FromUpdateScriptRequest(req *stub.ScriptServiceUpdateScriptByIdJ
 		Source:      req.Source,
 		Description: req.Description,
 	}
+	if req.Timeout != nil {
+		script.Timeout = int(*req.Timeout)
+	}
 	return
 }
 
@@ -126,6 +132,7 @@ func GetStubScript(script *m.Script) (result *stub.ApiScript) {
 		Name:        script.Name,
 		Source:      script.Source,
 		Description: script.Description,
+		Timeout:     common.Int32(int32(script.Timeout)),
 		ScriptInfo: &stub.ApiScriptInfo{
 			AlexaIntents:         int32(script.Info.AlexaIntents),
 			EntityActions:        int32(script.Info.EntityActions),
@@ -165,11 +172,15 @@ func ImportScript(from *stub.ApiScript) (*int64, *m.Script) {
 	if from == nil {
 		return nil, nil
 	}
-	return common.Int64(from.Id), &m.Script{
+	script := &m.Script{
 		Id:          from.Id,
 		Lang:        common.ScriptLang(from.Lang),
 		Name:        from.Name,
 		Source:      from.Source,
 		Description: from.Description,
 	}
+	if from.Timeout != nil {
+		script.Timeout = int(*from.Timeout)
+	}
+	return common.Int64(from.Id), script
 }
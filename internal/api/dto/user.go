@@ -169,3 +169,59 @@ func (u User) UpdateUserByIdRequest(req *stub.UserServiceUpdateUserByIdJSONBody,
 	user.Id = id
 	return
 }
+
+// ToUserSessionListResult ...
+func (u User) ToUserSessionListResult(list []*m.UserSession, current int64) (result *stub.ApiGetUserSessionListResult) {
+	result = &stub.ApiGetUserSessionListResult{
+		Items: make([]stub.ApiUserSession, 0, len(list)),
+	}
+	for _, session := range list {
+		result.Items = append(result.Items, stub.ApiUserSession{
+			Id:         session.Id,
+			Device:     session.Device,
+			Ip:         session.Ip,
+			Current:    session.Id == current,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+	return
+}
+
+// AddApiTokenRequest ...
+func (u User) AddApiTokenRequest(req *stub.ApiNewApiTokenRequest) (token *m.ApiToken) {
+	token = &m.ApiToken{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	return
+}
+
+// ToApiToken ...
+func (u User) ToApiToken(token *m.ApiToken) (result stub.ApiApiToken) {
+	result = stub.ApiApiToken{
+		Id:         token.Id,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+	if result.Scopes == nil {
+		result.Scopes = []string{}
+	}
+	return
+}
+
+// ToApiTokenListResult ...
+func (u User) ToApiTokenListResult(list []*m.ApiToken) (result *stub.ApiGetApiTokenListResult) {
+	result = &stub.ApiGetApiTokenListResult{
+		Items: make([]stub.ApiApiToken, 0, len(list)),
+	}
+	for _, token := range list {
+		result.Items = append(result.Items, u.ToApiToken(token))
+	}
+	return
+}
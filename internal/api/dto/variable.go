@@ -105,8 +105,9 @@ func ToVariable(ver models.Variable) (obj *stub.ApiVariable) {
 	}
 	obj = &stub.ApiVariable{
 		Name:      ver.Name,
-		Value:     ver.Value,
+		Value:     ver.MaskedValue(),
 		System:    ver.System,
+		Encrypted: ver.Encrypted,
 		CreatedAt: ver.CreatedAt,
 		UpdatedAt: ver.UpdatedAt,
 	}
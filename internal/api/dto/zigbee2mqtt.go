@@ -34,11 +34,12 @@ func NewZigbee2mqttDto() Zigbee2mqtt {
 // AddZigbee2MqttBridgeRequest ...
 func (u Zigbee2mqtt) AddZigbee2MqttBridgeRequest(obj *stub.ApiNewZigbee2mqttRequest) (bridge *models.Zigbee2mqtt) {
 	bridge = &models.Zigbee2mqtt{
-		Name:       obj.Name,
-		Login:      obj.Login,
-		Password:   obj.Password,
-		PermitJoin: obj.PermitJoin,
-		BaseTopic:  obj.BaseTopic,
+		Name:          obj.Name,
+		Login:         obj.Login,
+		Password:      obj.Password,
+		PermitJoin:    obj.PermitJoin,
+		AutoDiscovery: obj.AutoDiscovery,
+		BaseTopic:     obj.BaseTopic,
 	}
 	return
 }
@@ -54,6 +55,7 @@ func (u Zigbee2mqtt) AddZigbee2MqttBridgeResult(bridge *models.Zigbee2mqtt) (obj
 		Name:          bridge.Name,
 		Login:         bridge.Login,
 		PermitJoin:    bridge.PermitJoin,
+		AutoDiscovery: bridge.AutoDiscovery,
 		BaseTopic:     bridge.BaseTopic,
 		CreatedAt:     bridge.CreatedAt,
 		UpdatedAt:     bridge.UpdatedAt,
@@ -64,13 +66,14 @@ func (u Zigbee2mqtt) AddZigbee2MqttBridgeResult(bridge *models.Zigbee2mqtt) (obj
 // ToZigbee2mqttInfo ...
 func (u Zigbee2mqtt) ToZigbee2mqttInfo(bridge *models.Zigbee2mqtt) (obj *stub.ApiZigbee2mqtt) {
 	obj = &stub.ApiZigbee2mqtt{
-		Id:         bridge.Id,
-		Name:       bridge.Name,
-		Login:      bridge.Login,
-		PermitJoin: bridge.PermitJoin,
-		BaseTopic:  bridge.BaseTopic,
-		CreatedAt:  bridge.CreatedAt,
-		UpdatedAt:  bridge.UpdatedAt,
+		Id:            bridge.Id,
+		Name:          bridge.Name,
+		Login:         bridge.Login,
+		PermitJoin:    bridge.PermitJoin,
+		AutoDiscovery: bridge.AutoDiscovery,
+		BaseTopic:     bridge.BaseTopic,
+		CreatedAt:     bridge.CreatedAt,
+		UpdatedAt:     bridge.UpdatedAt,
 	}
 	if bridge.Info != nil {
 		obj.ScanInProcess = bridge.Info.ScanInProcess
@@ -84,12 +87,13 @@ func (u Zigbee2mqtt) ToZigbee2mqttInfo(bridge *models.Zigbee2mqtt) (obj *stub.Ap
 // UpdateBridgeByIdRequest ...
 func (u Zigbee2mqtt) UpdateBridgeByIdRequest(obj *stub.Zigbee2mqttServiceUpdateBridgeByIdJSONBody, id int64) (bridge *models.Zigbee2mqtt) {
 	bridge = &models.Zigbee2mqtt{
-		Id:         id,
-		Name:       obj.Name,
-		Login:      obj.Login,
-		Password:   obj.Password,
-		PermitJoin: obj.PermitJoin,
-		BaseTopic:  obj.BaseTopic,
+		Id:            id,
+		Name:          obj.Name,
+		Login:         obj.Login,
+		Password:      obj.Password,
+		PermitJoin:    obj.PermitJoin,
+		AutoDiscovery: obj.AutoDiscovery,
+		BaseTopic:     obj.BaseTopic,
 	}
 	return
 }
@@ -97,13 +101,14 @@ func (u Zigbee2mqtt) UpdateBridgeByIdRequest(obj *stub.Zigbee2mqttServiceUpdateB
 // UpdateBridgeByIdResult ...
 func (u Zigbee2mqtt) UpdateBridgeByIdResult(bridge *models.Zigbee2mqtt) (obj *stub.ApiZigbee2mqtt) {
 	obj = &stub.ApiZigbee2mqtt{
-		Id:         bridge.Id,
-		Name:       bridge.Name,
-		Login:      bridge.Login,
-		PermitJoin: bridge.PermitJoin,
-		BaseTopic:  bridge.BaseTopic,
-		CreatedAt:  bridge.CreatedAt,
-		UpdatedAt:  bridge.UpdatedAt,
+		Id:            bridge.Id,
+		Name:          bridge.Name,
+		Login:         bridge.Login,
+		PermitJoin:    bridge.PermitJoin,
+		AutoDiscovery: bridge.AutoDiscovery,
+		BaseTopic:     bridge.BaseTopic,
+		CreatedAt:     bridge.CreatedAt,
+		UpdatedAt:     bridge.UpdatedAt,
 	}
 	return
 }
@@ -113,13 +118,14 @@ func (u Zigbee2mqtt) GetBridgeListResult(list []*models.Zigbee2mqtt) []*stub.Api
 	items := make([]*stub.ApiZigbee2mqttShort, 0, len(list))
 	for _, item := range list {
 		items = append(items, &stub.ApiZigbee2mqttShort{
-			Id:         item.Id,
-			Name:       item.Name,
-			Login:      item.Login,
-			PermitJoin: item.PermitJoin,
-			BaseTopic:  item.BaseTopic,
-			CreatedAt:  item.CreatedAt,
-			UpdatedAt:  item.UpdatedAt,
+			Id:            item.Id,
+			Name:          item.Name,
+			Login:         item.Login,
+			PermitJoin:    item.PermitJoin,
+			AutoDiscovery: item.AutoDiscovery,
+			BaseTopic:     item.BaseTopic,
+			CreatedAt:     item.CreatedAt,
+			UpdatedAt:     item.UpdatedAt,
 		})
 	}
 
@@ -58,6 +58,12 @@ type ServerInterface interface {
 	// apply state
 	// (POST /v1/backup/apply)
 	BackupServiceApplyState(ctx echo.Context, params BackupServiceApplyStateParams) error
+	// export configuration objects
+	// (POST /v1/backup/export)
+	BackupServiceExportConfig(ctx echo.Context, params BackupServiceExportConfigParams) error
+	// import configuration objects
+	// (POST /v1/backup/import)
+	BackupServiceImportConfig(ctx echo.Context, params BackupServiceImportConfigParams) error
 	// revert state
 	// (POST /v1/backup/rollback)
 	BackupServiceRevertState(ctx echo.Context, params BackupServiceRevertStateParams) error
@@ -181,6 +187,18 @@ type ServerInterface interface {
 	// entity set state
 	// (POST /v1/developer_tools/entity/set_state)
 	DeveloperToolsServiceEntitySetState(ctx echo.Context, params DeveloperToolsServiceEntitySetStateParams) error
+	// discovered devices that are not configured
+	// (GET /v1/discovery)
+	DiscoveryServiceGetDeviceList(ctx echo.Context) error
+	// start the search
+	// (POST /v1/discovery/scan)
+	DiscoveryServiceScan(ctx echo.Context) error
+	// ignore discovered device
+	// (DELETE /v1/discovery/{id})
+	DiscoveryServiceIgnoreDevice(ctx echo.Context, id string) error
+	// add the entity of the matching plugin for the discovered device
+	// (POST /v1/discovery/{id}/accept)
+	DiscoveryServiceAcceptDevice(ctx echo.Context, id string) error
 	// get entity list
 	// (GET /v1/entities)
 	EntityServiceGetEntityList(ctx echo.Context, params EntityServiceGetEntityListParams) error
@@ -214,6 +232,9 @@ type ServerInterface interface {
 
 	// (GET /v1/entity_storage)
 	EntityStorageServiceGetEntityStorageList(ctx echo.Context, params EntityStorageServiceGetEntityStorageListParams) error
+	// aggregated history of the entity state or attribute
+	// (GET /v1/entity_storage/history)
+	EntityStorageServiceGetEntityStorageHistory(ctx echo.Context, params EntityStorageServiceGetEntityStorageHistoryParams) error
 	// add new image
 	// (POST /v1/image)
 	ImageServiceAddImage(ctx echo.Context, params ImageServiceAddImageParams) error
@@ -289,6 +310,9 @@ type ServerInterface interface {
 	// upload plugin archive
 	// (POST /v1/plugins/upload)
 	PluginServiceUploadPlugin(ctx echo.Context, params PluginServiceUploadPluginParams) error
+	// refresh access token
+	// (POST /v1/refresh_token)
+	AuthServiceRefreshToken(ctx echo.Context) error
 	// add new role
 	// (POST /v1/role)
 	RoleServiceAddRole(ctx echo.Context, params RoleServiceAddRoleParams) error
@@ -307,6 +331,15 @@ type ServerInterface interface {
 	// update role access list
 	// (PUT /v1/role/{name}/access_list)
 	RoleServiceUpdateRoleAccessList(ctx echo.Context, name string, params RoleServiceUpdateRoleAccessListParams) error
+	// get role entity permissions
+	// (GET /v1/role/{name}/entity_permissions)
+	RoleServiceGetEntityPermissionList(ctx echo.Context, name string) error
+	// add role entity permission
+	// (POST /v1/role/{name}/entity_permissions)
+	RoleServiceAddEntityPermission(ctx echo.Context, name string) error
+	// delete role entity permission
+	// (DELETE /v1/role/{name}/entity_permissions/{id})
+	RoleServiceDeleteEntityPermission(ctx echo.Context, name string, id int64) error
 	// get role list
 	// (GET /v1/roles)
 	RoleServiceGetRoleList(ctx echo.Context, params RoleServiceGetRoleListParams) error
@@ -346,6 +379,15 @@ type ServerInterface interface {
 	// get statistic
 	// (GET /v1/scripts/statistic)
 	ScriptServiceGetStatistic(ctx echo.Context) error
+	// revoke session
+	// (DELETE /v1/session/{id})
+	AuthServiceRevokeSession(ctx echo.Context, id int64) error
+	// revoke all sessions
+	// (DELETE /v1/sessions)
+	AuthServiceRevokeAllSessions(ctx echo.Context) error
+	// get session list
+	// (GET /v1/sessions)
+	AuthServiceGetSessionList(ctx echo.Context) error
 	// sign in user
 	// (POST /v1/signin)
 	AuthServiceSignin(ctx echo.Context) error
@@ -385,6 +427,15 @@ type ServerInterface interface {
 	// enable task
 	// (POST /v1/task/{id}/enable)
 	AutomationServiceEnableTask(ctx echo.Context, id int64) error
+	// get task run list
+	// (GET /v1/task/{id}/runs)
+	AutomationServiceGetTaskRunList(ctx echo.Context, id int64, params AutomationServiceGetTaskRunListParams) error
+	// get task run
+	// (GET /v1/task_run/{id})
+	AutomationServiceGetTaskRun(ctx echo.Context, id int64) error
+	// re-run the task with the recorded trigger payload
+	// (POST /v1/task_run/{id}/replay)
+	AutomationServiceReplayTaskRun(ctx echo.Context, id int64) error
 	// get task list
 	// (GET /v1/tasks)
 	AutomationServiceGetTaskList(ctx echo.Context, params AutomationServiceGetTaskListParams) error
@@ -427,6 +478,27 @@ type ServerInterface interface {
 	// update user by id
 	// (PUT /v1/user/{id})
 	UserServiceUpdateUserById(ctx echo.Context, id int64, params UserServiceUpdateUserByIdParams) error
+	// delete api token
+	// (DELETE /v1/user/{id}/api_token/{tokenId})
+	UserServiceDeleteApiToken(ctx echo.Context, id int64, tokenId int64) error
+	// get api token list
+	// (GET /v1/user/{id}/api_tokens)
+	UserServiceGetApiTokenList(ctx echo.Context, id int64) error
+	// add new api token
+	// (POST /v1/user/{id}/api_tokens)
+	UserServiceAddApiToken(ctx echo.Context, id int64) error
+	// confirm two-factor authentication
+	// (POST /v1/user/{id}/totp/confirm)
+	UserServiceConfirmTotp(ctx echo.Context, id int64) error
+	// disable two-factor authentication
+	// (POST /v1/user/{id}/totp/disable)
+	UserServiceDisableTotp(ctx echo.Context, id int64) error
+	// enroll two-factor authentication
+	// (POST /v1/user/{id}/totp/enroll)
+	UserServiceEnrollTotp(ctx echo.Context, id int64) error
+	// regenerate recovery codes
+	// (POST /v1/user/{id}/totp/recovery_codes)
+	UserServiceRegenerateRecoveryCodes(ctx echo.Context, id int64) error
 	// get user list
 	// (GET /v1/users)
 	UserServiceGetUserList(ctx echo.Context, params UserServiceGetUserListParams) error
@@ -466,9 +538,36 @@ type ServerInterface interface {
 	// update bridge by id
 	// (PUT /v1/zigbee2mqtt/bridge/{id}/bridge)
 	Zigbee2mqttServiceUpdateBridgeById(ctx echo.Context, id int64, params Zigbee2mqttServiceUpdateBridgeByIdParams) error
+	// device bind
+	// (POST /v1/zigbee2mqtt/bridge/{id}/device_bind)
+	Zigbee2mqttServiceDeviceBind(ctx echo.Context, id int64) error
+	// device options
+	// (POST /v1/zigbee2mqtt/bridge/{id}/device_options)
+	Zigbee2mqttServiceDeviceOptions(ctx echo.Context, id int64) error
+	// device ota check
+	// (POST /v1/zigbee2mqtt/bridge/{id}/device_ota_check)
+	Zigbee2mqttServiceDeviceOtaCheck(ctx echo.Context, id int64) error
+	// device ota update
+	// (POST /v1/zigbee2mqtt/bridge/{id}/device_ota_update)
+	Zigbee2mqttServiceDeviceOtaUpdate(ctx echo.Context, id int64) error
+	// device unbind
+	// (POST /v1/zigbee2mqtt/bridge/{id}/device_unbind)
+	Zigbee2mqttServiceDeviceUnbind(ctx echo.Context, id int64) error
 	// list device
 	// (GET /v1/zigbee2mqtt/bridge/{id}/devices)
 	Zigbee2mqttServiceDeviceList(ctx echo.Context, id int64, params Zigbee2mqttServiceDeviceListParams) error
+	// add group
+	// (POST /v1/zigbee2mqtt/bridge/{id}/group_add)
+	Zigbee2mqttServiceAddGroup(ctx echo.Context, id int64) error
+	// add group member
+	// (POST /v1/zigbee2mqtt/bridge/{id}/group_member_add)
+	Zigbee2mqttServiceGroupAddMember(ctx echo.Context, id int64) error
+	// remove group member
+	// (POST /v1/zigbee2mqtt/bridge/{id}/group_member_remove)
+	Zigbee2mqttServiceGroupRemoveMember(ctx echo.Context, id int64) error
+	// remove group
+	// (POST /v1/zigbee2mqtt/bridge/{id}/group_remove)
+	Zigbee2mqttServiceRemoveGroup(ctx echo.Context, id int64) error
 	// networkmap
 	// (GET /v1/zigbee2mqtt/bridge/{id}/networkmap)
 	Zigbee2mqttServiceNetworkmap(ctx echo.Context, id int64) error
@@ -903,6 +1002,70 @@ func (w *ServerInterfaceWrapper) BackupServiceApplyState(ctx echo.Context) error
 	return err
 }
 
+// BackupServiceExportConfig converts echo context to params.
+func (w *ServerInterfaceWrapper) BackupServiceExportConfig(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params BackupServiceExportConfigParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.BackupServiceExportConfig(ctx, params)
+	return err
+}
+
+// BackupServiceImportConfig converts echo context to params.
+func (w *ServerInterfaceWrapper) BackupServiceImportConfig(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params BackupServiceImportConfigParams
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "conflict" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "conflict", ctx.QueryParams(), &params.Conflict)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter conflict: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Accept" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept")]; found {
+		var Accept AcceptJSON
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Accept, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept", valueList[0], &Accept, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Accept: %s", err))
+		}
+
+		params.Accept = &Accept
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.BackupServiceImportConfig(ctx, params)
+	return err
+}
+
 // BackupServiceRevertState converts echo context to params.
 func (w *ServerInterfaceWrapper) BackupServiceRevertState(ctx echo.Context) error {
 	var err error
@@ -2107,6 +2270,64 @@ func (w *ServerInterfaceWrapper) DeveloperToolsServiceEntitySetState(ctx echo.Co
 	return err
 }
 
+// DiscoveryServiceGetDeviceList converts echo context to params.
+func (w *ServerInterfaceWrapper) DiscoveryServiceGetDeviceList(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DiscoveryServiceGetDeviceList(ctx)
+	return err
+}
+
+// DiscoveryServiceScan converts echo context to params.
+func (w *ServerInterfaceWrapper) DiscoveryServiceScan(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DiscoveryServiceScan(ctx)
+	return err
+}
+
+// DiscoveryServiceIgnoreDevice converts echo context to params.
+func (w *ServerInterfaceWrapper) DiscoveryServiceIgnoreDevice(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DiscoveryServiceIgnoreDevice(ctx, id)
+	return err
+}
+
+// DiscoveryServiceAcceptDevice converts echo context to params.
+func (w *ServerInterfaceWrapper) DiscoveryServiceAcceptDevice(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DiscoveryServiceAcceptDevice(ctx, id)
+	return err
+}
+
 // EntityServiceGetEntityList converts echo context to params.
 func (w *ServerInterfaceWrapper) EntityServiceGetEntityList(ctx echo.Context) error {
 	var err error
@@ -2441,6 +2662,68 @@ func (w *ServerInterfaceWrapper) EntityStorageServiceGetEntityStorageList(ctx ec
 	return err
 }
 
+// EntityStorageServiceGetEntityStorageHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) EntityStorageServiceGetEntityStorageHistory(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params EntityStorageServiceGetEntityStorageHistoryParams
+	// ------------- Optional query parameter "entityId[]" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "entityId[]", ctx.QueryParams(), &params.EntityId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter entityId[]: %s", err))
+	}
+
+	// ------------- Optional query parameter "startDate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "startDate", ctx.QueryParams(), &params.StartDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter startDate: %s", err))
+	}
+
+	// ------------- Optional query parameter "endDate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "endDate", ctx.QueryParams(), &params.EndDate)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter endDate: %s", err))
+	}
+
+	// ------------- Optional query parameter "attribute" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "attribute", ctx.QueryParams(), &params.Attribute)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter attribute: %s", err))
+	}
+
+	// ------------- Optional query parameter "aggregation" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "aggregation", ctx.QueryParams(), &params.Aggregation)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter aggregation: %s", err))
+	}
+
+	// ------------- Optional query parameter "interval" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "interval", ctx.QueryParams(), &params.Interval)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter interval: %s", err))
+	}
+
+	// ------------- Optional query parameter "state" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "state", ctx.QueryParams(), &params.State)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter state: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.EntityStorageServiceGetEntityStorageHistory(ctx, params)
+	return err
+}
+
 // ImageServiceAddImage converts echo context to params.
 func (w *ServerInterfaceWrapper) ImageServiceAddImage(ctx echo.Context) error {
 	var err error
@@ -3385,6 +3668,68 @@ func (w *ServerInterfaceWrapper) RoleServiceUpdateRoleAccessList(ctx echo.Contex
 	return err
 }
 
+// RoleServiceGetEntityPermissionList converts echo context to params.
+func (w *ServerInterfaceWrapper) RoleServiceGetEntityPermissionList(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RoleServiceGetEntityPermissionList(ctx, name)
+	return err
+}
+
+// RoleServiceAddEntityPermission converts echo context to params.
+func (w *ServerInterfaceWrapper) RoleServiceAddEntityPermission(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RoleServiceAddEntityPermission(ctx, name)
+	return err
+}
+
+// RoleServiceDeleteEntityPermission converts echo context to params.
+func (w *ServerInterfaceWrapper) RoleServiceDeleteEntityPermission(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RoleServiceDeleteEntityPermission(ctx, name, id)
+	return err
+}
+
 // RoleServiceGetRoleList converts echo context to params.
 func (w *ServerInterfaceWrapper) RoleServiceGetRoleList(ctx echo.Context) error {
 	var err error
@@ -3736,6 +4081,55 @@ func (w *ServerInterfaceWrapper) ScriptServiceGetStatistic(ctx echo.Context) err
 	return err
 }
 
+// AuthServiceRefreshToken converts echo context to params.
+func (w *ServerInterfaceWrapper) AuthServiceRefreshToken(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AuthServiceRefreshToken(ctx)
+	return err
+}
+
+// AuthServiceRevokeSession converts echo context to params.
+func (w *ServerInterfaceWrapper) AuthServiceRevokeSession(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AuthServiceRevokeSession(ctx, id)
+	return err
+}
+
+// AuthServiceRevokeAllSessions converts echo context to params.
+func (w *ServerInterfaceWrapper) AuthServiceRevokeAllSessions(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AuthServiceRevokeAllSessions(ctx)
+	return err
+}
+
+// AuthServiceGetSessionList converts echo context to params.
+func (w *ServerInterfaceWrapper) AuthServiceGetSessionList(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AuthServiceGetSessionList(ctx)
+	return err
+}
+
 // AuthServiceSignin converts echo context to params.
 func (w *ServerInterfaceWrapper) AuthServiceSignin(ctx echo.Context) error {
 	var err error
@@ -4055,14 +4449,21 @@ func (w *ServerInterfaceWrapper) AutomationServiceEnableTask(ctx echo.Context) e
 	return err
 }
 
-// AutomationServiceGetTaskList converts echo context to params.
-func (w *ServerInterfaceWrapper) AutomationServiceGetTaskList(ctx echo.Context) error {
+// AutomationServiceGetTaskRunList converts echo context to params.
+func (w *ServerInterfaceWrapper) AutomationServiceGetTaskRunList(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params AutomationServiceGetTaskListParams
+	var params AutomationServiceGetTaskRunListParams
 	// ------------- Optional query parameter "sort" -------------
 
 	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
@@ -4085,21 +4486,91 @@ func (w *ServerInterfaceWrapper) AutomationServiceGetTaskList(ctx echo.Context)
 	}
 
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.AutomationServiceGetTaskList(ctx, params)
+	err = w.Handler.AutomationServiceGetTaskRunList(ctx, id, params)
 	return err
 }
 
-// AutomationServiceImportTask converts echo context to params.
-func (w *ServerInterfaceWrapper) AutomationServiceImportTask(ctx echo.Context) error {
+// AutomationServiceGetTaskRun converts echo context to params.
+func (w *ServerInterfaceWrapper) AutomationServiceGetTaskRun(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
 
 	ctx.Set(ApiKeyAuthScopes, []string{})
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params AutomationServiceImportTaskParams
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AutomationServiceGetTaskRun(ctx, id)
+	return err
+}
 
-	headers := ctx.Request().Header
-	// ------------- Optional header parameter "Accept" -------------
+// AutomationServiceReplayTaskRun converts echo context to params.
+func (w *ServerInterfaceWrapper) AutomationServiceReplayTaskRun(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AutomationServiceReplayTaskRun(ctx, id)
+	return err
+}
+
+// AutomationServiceGetTaskList converts echo context to params.
+func (w *ServerInterfaceWrapper) AutomationServiceGetTaskList(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AutomationServiceGetTaskListParams
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", ctx.QueryParams(), &params.Page)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter page: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.AutomationServiceGetTaskList(ctx, params)
+	return err
+}
+
+// AutomationServiceImportTask converts echo context to params.
+func (w *ServerInterfaceWrapper) AutomationServiceImportTask(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AutomationServiceImportTaskParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Accept" -------------
 	if valueList, found := headers[http.CanonicalHeaderKey("Accept")]; found {
 		var Accept AcceptJSON
 		n := len(valueList)
@@ -4441,6 +4912,140 @@ func (w *ServerInterfaceWrapper) UserServiceUpdateUserById(ctx echo.Context) err
 	return err
 }
 
+// UserServiceDeleteApiToken converts echo context to params.
+func (w *ServerInterfaceWrapper) UserServiceDeleteApiToken(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	// ------------- Path parameter "tokenId" -------------
+	var tokenId int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tokenId", ctx.Param("tokenId"), &tokenId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tokenId: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserServiceDeleteApiToken(ctx, id, tokenId)
+	return err
+}
+
+// UserServiceGetApiTokenList converts echo context to params.
+func (w *ServerInterfaceWrapper) UserServiceGetApiTokenList(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserServiceGetApiTokenList(ctx, id)
+	return err
+}
+
+// UserServiceAddApiToken converts echo context to params.
+func (w *ServerInterfaceWrapper) UserServiceAddApiToken(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserServiceAddApiToken(ctx, id)
+	return err
+}
+
+// UserServiceConfirmTotp converts echo context to params.
+func (w *ServerInterfaceWrapper) UserServiceConfirmTotp(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserServiceConfirmTotp(ctx, id)
+	return err
+}
+
+// UserServiceDisableTotp converts echo context to params.
+func (w *ServerInterfaceWrapper) UserServiceDisableTotp(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserServiceDisableTotp(ctx, id)
+	return err
+}
+
+// UserServiceEnrollTotp converts echo context to params.
+func (w *ServerInterfaceWrapper) UserServiceEnrollTotp(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserServiceEnrollTotp(ctx, id)
+	return err
+}
+
+// UserServiceRegenerateRecoveryCodes converts echo context to params.
+func (w *ServerInterfaceWrapper) UserServiceRegenerateRecoveryCodes(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UserServiceRegenerateRecoveryCodes(ctx, id)
+	return err
+}
+
 // UserServiceGetUserList converts echo context to params.
 func (w *ServerInterfaceWrapper) UserServiceGetUserList(ctx echo.Context) error {
 	var err error
@@ -4819,6 +5424,96 @@ func (w *ServerInterfaceWrapper) Zigbee2mqttServiceUpdateBridgeById(ctx echo.Con
 	return err
 }
 
+// Zigbee2mqttServiceDeviceBind converts echo context to params.
+func (w *ServerInterfaceWrapper) Zigbee2mqttServiceDeviceBind(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Zigbee2mqttServiceDeviceBind(ctx, id)
+	return err
+}
+
+// Zigbee2mqttServiceDeviceOptions converts echo context to params.
+func (w *ServerInterfaceWrapper) Zigbee2mqttServiceDeviceOptions(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Zigbee2mqttServiceDeviceOptions(ctx, id)
+	return err
+}
+
+// Zigbee2mqttServiceDeviceOtaCheck converts echo context to params.
+func (w *ServerInterfaceWrapper) Zigbee2mqttServiceDeviceOtaCheck(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Zigbee2mqttServiceDeviceOtaCheck(ctx, id)
+	return err
+}
+
+// Zigbee2mqttServiceDeviceOtaUpdate converts echo context to params.
+func (w *ServerInterfaceWrapper) Zigbee2mqttServiceDeviceOtaUpdate(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Zigbee2mqttServiceDeviceOtaUpdate(ctx, id)
+	return err
+}
+
+// Zigbee2mqttServiceDeviceUnbind converts echo context to params.
+func (w *ServerInterfaceWrapper) Zigbee2mqttServiceDeviceUnbind(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Zigbee2mqttServiceDeviceUnbind(ctx, id)
+	return err
+}
+
 // Zigbee2mqttServiceDeviceList converts echo context to params.
 func (w *ServerInterfaceWrapper) Zigbee2mqttServiceDeviceList(ctx echo.Context) error {
 	var err error
@@ -4860,6 +5555,78 @@ func (w *ServerInterfaceWrapper) Zigbee2mqttServiceDeviceList(ctx echo.Context)
 	return err
 }
 
+// Zigbee2mqttServiceAddGroup converts echo context to params.
+func (w *ServerInterfaceWrapper) Zigbee2mqttServiceAddGroup(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Zigbee2mqttServiceAddGroup(ctx, id)
+	return err
+}
+
+// Zigbee2mqttServiceGroupAddMember converts echo context to params.
+func (w *ServerInterfaceWrapper) Zigbee2mqttServiceGroupAddMember(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Zigbee2mqttServiceGroupAddMember(ctx, id)
+	return err
+}
+
+// Zigbee2mqttServiceGroupRemoveMember converts echo context to params.
+func (w *ServerInterfaceWrapper) Zigbee2mqttServiceGroupRemoveMember(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Zigbee2mqttServiceGroupRemoveMember(ctx, id)
+	return err
+}
+
+// Zigbee2mqttServiceRemoveGroup converts echo context to params.
+func (w *ServerInterfaceWrapper) Zigbee2mqttServiceRemoveGroup(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id int64
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(ApiKeyAuthScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.Zigbee2mqttServiceRemoveGroup(ctx, id)
+	return err
+}
+
 // Zigbee2mqttServiceNetworkmap converts echo context to params.
 func (w *ServerInterfaceWrapper) Zigbee2mqttServiceNetworkmap(ctx echo.Context) error {
 	var err error
@@ -5084,6 +5851,8 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.GET(baseURL+"/v1/areas/search", wrapper.AreaServiceSearchArea)
 	router.GET(baseURL+"/v1/automation/statistic", wrapper.AutomationServiceGetStatistic)
 	router.POST(baseURL+"/v1/backup/apply", wrapper.BackupServiceApplyState)
+	router.POST(baseURL+"/v1/backup/export", wrapper.BackupServiceExportConfig)
+	router.POST(baseURL+"/v1/backup/import", wrapper.BackupServiceImportConfig)
 	router.POST(baseURL+"/v1/backup/rollback", wrapper.BackupServiceRevertState)
 	router.POST(baseURL+"/v1/backup/upload", wrapper.BackupServiceUploadBackup)
 	router.DELETE(baseURL+"/v1/backup/:name", wrapper.BackupServiceDeleteBackup)
@@ -5125,6 +5894,10 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.GET(baseURL+"/v1/developer_tools/bus/state", wrapper.DeveloperToolsServiceGetEventBusStateList)
 	router.POST(baseURL+"/v1/developer_tools/entity/reload", wrapper.DeveloperToolsServiceReloadEntity)
 	router.POST(baseURL+"/v1/developer_tools/entity/set_state", wrapper.DeveloperToolsServiceEntitySetState)
+	router.GET(baseURL+"/v1/discovery", wrapper.DiscoveryServiceGetDeviceList)
+	router.POST(baseURL+"/v1/discovery/scan", wrapper.DiscoveryServiceScan)
+	router.DELETE(baseURL+"/v1/discovery/:id", wrapper.DiscoveryServiceIgnoreDevice)
+	router.POST(baseURL+"/v1/discovery/:id/accept", wrapper.DiscoveryServiceAcceptDevice)
 	router.GET(baseURL+"/v1/entities", wrapper.EntityServiceGetEntityList)
 	router.POST(baseURL+"/v1/entities/import", wrapper.EntityServiceImportEntity)
 	router.GET(baseURL+"/v1/entities/statistic", wrapper.EntityServiceGetStatistic)
@@ -5136,6 +5909,7 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.POST(baseURL+"/v1/entity/:id/disable", wrapper.EntityServiceDisabledEntity)
 	router.POST(baseURL+"/v1/entity/:id/enable", wrapper.EntityServiceEnabledEntity)
 	router.GET(baseURL+"/v1/entity_storage", wrapper.EntityStorageServiceGetEntityStorageList)
+	router.GET(baseURL+"/v1/entity_storage/history", wrapper.EntityStorageServiceGetEntityStorageHistory)
 	router.POST(baseURL+"/v1/image", wrapper.ImageServiceAddImage)
 	router.POST(baseURL+"/v1/image/upload", wrapper.ImageServiceUploadImage)
 	router.DELETE(baseURL+"/v1/image/:id", wrapper.ImageServiceDeleteImageById)
@@ -5161,12 +5935,16 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.GET(baseURL+"/v1/plugins", wrapper.PluginServiceGetPluginList)
 	router.GET(baseURL+"/v1/plugins/search", wrapper.PluginServiceSearchPlugin)
 	router.POST(baseURL+"/v1/plugins/upload", wrapper.PluginServiceUploadPlugin)
+	router.POST(baseURL+"/v1/refresh_token", wrapper.AuthServiceRefreshToken)
 	router.POST(baseURL+"/v1/role", wrapper.RoleServiceAddRole)
 	router.DELETE(baseURL+"/v1/role/:name", wrapper.RoleServiceDeleteRoleByName)
 	router.GET(baseURL+"/v1/role/:name", wrapper.RoleServiceGetRoleByName)
 	router.PUT(baseURL+"/v1/role/:name", wrapper.RoleServiceUpdateRoleByName)
 	router.GET(baseURL+"/v1/role/:name/access_list", wrapper.RoleServiceGetRoleAccessList)
 	router.PUT(baseURL+"/v1/role/:name/access_list", wrapper.RoleServiceUpdateRoleAccessList)
+	router.GET(baseURL+"/v1/role/:name/entity_permissions", wrapper.RoleServiceGetEntityPermissionList)
+	router.POST(baseURL+"/v1/role/:name/entity_permissions", wrapper.RoleServiceAddEntityPermission)
+	router.DELETE(baseURL+"/v1/role/:name/entity_permissions/:id", wrapper.RoleServiceDeleteEntityPermission)
 	router.GET(baseURL+"/v1/roles", wrapper.RoleServiceGetRoleList)
 	router.GET(baseURL+"/v1/roles/search", wrapper.RoleServiceSearchRoleByName)
 	router.POST(baseURL+"/v1/script", wrapper.ScriptServiceAddScript)
@@ -5180,6 +5958,9 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.GET(baseURL+"/v1/scripts", wrapper.ScriptServiceGetScriptList)
 	router.GET(baseURL+"/v1/scripts/search", wrapper.ScriptServiceSearchScript)
 	router.GET(baseURL+"/v1/scripts/statistic", wrapper.ScriptServiceGetStatistic)
+	router.DELETE(baseURL+"/v1/session/:id", wrapper.AuthServiceRevokeSession)
+	router.DELETE(baseURL+"/v1/sessions", wrapper.AuthServiceRevokeAllSessions)
+	router.GET(baseURL+"/v1/sessions", wrapper.AuthServiceGetSessionList)
 	router.POST(baseURL+"/v1/signin", wrapper.AuthServiceSignin)
 	router.POST(baseURL+"/v1/signout", wrapper.AuthServiceSignout)
 	router.DELETE(baseURL+"/v1/tag/:id", wrapper.TagServiceDeleteTagById)
@@ -5193,6 +5974,9 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.PUT(baseURL+"/v1/task/:id", wrapper.AutomationServiceUpdateTask)
 	router.POST(baseURL+"/v1/task/:id/disable", wrapper.AutomationServiceDisableTask)
 	router.POST(baseURL+"/v1/task/:id/enable", wrapper.AutomationServiceEnableTask)
+	router.GET(baseURL+"/v1/task/:id/runs", wrapper.AutomationServiceGetTaskRunList)
+	router.GET(baseURL+"/v1/task_run/:id", wrapper.AutomationServiceGetTaskRun)
+	router.POST(baseURL+"/v1/task_run/:id/replay", wrapper.AutomationServiceReplayTaskRun)
 	router.GET(baseURL+"/v1/tasks", wrapper.AutomationServiceGetTaskList)
 	router.POST(baseURL+"/v1/tasks/import", wrapper.AutomationServiceImportTask)
 	router.POST(baseURL+"/v1/trigger", wrapper.TriggerServiceAddTrigger)
@@ -5207,6 +5991,13 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.DELETE(baseURL+"/v1/user/:id", wrapper.UserServiceDeleteUserById)
 	router.GET(baseURL+"/v1/user/:id", wrapper.UserServiceGetUserById)
 	router.PUT(baseURL+"/v1/user/:id", wrapper.UserServiceUpdateUserById)
+	router.DELETE(baseURL+"/v1/user/:id/api_token/:tokenId", wrapper.UserServiceDeleteApiToken)
+	router.GET(baseURL+"/v1/user/:id/api_tokens", wrapper.UserServiceGetApiTokenList)
+	router.POST(baseURL+"/v1/user/:id/api_tokens", wrapper.UserServiceAddApiToken)
+	router.POST(baseURL+"/v1/user/:id/totp/confirm", wrapper.UserServiceConfirmTotp)
+	router.POST(baseURL+"/v1/user/:id/totp/disable", wrapper.UserServiceDisableTotp)
+	router.POST(baseURL+"/v1/user/:id/totp/enroll", wrapper.UserServiceEnrollTotp)
+	router.POST(baseURL+"/v1/user/:id/totp/recovery_codes", wrapper.UserServiceRegenerateRecoveryCodes)
 	router.GET(baseURL+"/v1/users", wrapper.UserServiceGetUserList)
 	router.POST(baseURL+"/v1/variable", wrapper.VariableServiceAddVariable)
 	router.DELETE(baseURL+"/v1/variable/:name", wrapper.VariableServiceDeleteVariable)
@@ -5220,7 +6011,16 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.DELETE(baseURL+"/v1/zigbee2mqtt/bridge/:id", wrapper.Zigbee2mqttServiceDeleteBridgeById)
 	router.GET(baseURL+"/v1/zigbee2mqtt/bridge/:id", wrapper.Zigbee2mqttServiceGetZigbee2mqttBridge)
 	router.PUT(baseURL+"/v1/zigbee2mqtt/bridge/:id/bridge", wrapper.Zigbee2mqttServiceUpdateBridgeById)
+	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/device_bind", wrapper.Zigbee2mqttServiceDeviceBind)
+	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/device_options", wrapper.Zigbee2mqttServiceDeviceOptions)
+	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/device_ota_check", wrapper.Zigbee2mqttServiceDeviceOtaCheck)
+	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/device_ota_update", wrapper.Zigbee2mqttServiceDeviceOtaUpdate)
+	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/device_unbind", wrapper.Zigbee2mqttServiceDeviceUnbind)
 	router.GET(baseURL+"/v1/zigbee2mqtt/bridge/:id/devices", wrapper.Zigbee2mqttServiceDeviceList)
+	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/group_add", wrapper.Zigbee2mqttServiceAddGroup)
+	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/group_member_add", wrapper.Zigbee2mqttServiceGroupAddMember)
+	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/group_member_remove", wrapper.Zigbee2mqttServiceGroupRemoveMember)
+	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/group_remove", wrapper.Zigbee2mqttServiceRemoveGroup)
 	router.GET(baseURL+"/v1/zigbee2mqtt/bridge/:id/networkmap", wrapper.Zigbee2mqttServiceNetworkmap)
 	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/networkmap", wrapper.Zigbee2mqttServiceUpdateNetworkmap)
 	router.POST(baseURL+"/v1/zigbee2mqtt/bridge/:id/reset", wrapper.Zigbee2mqttServiceResetBridgeById)
@@ -30,10 +30,65 @@ const (
 	TIME      ApiTypes = "TIME"
 )
 
+// Defines values for ApiDiscoveredServiceSource.
+const (
+	Mdns ApiDiscoveredServiceSource = "mdns"
+	Ssdp ApiDiscoveredServiceSource = "ssdp"
+)
+
+// Defines values for ApiConditionRuleType.
+const (
+	ApiConditionRuleTypeAnd      ApiConditionRuleType = "and"
+	ApiConditionRuleTypeNot      ApiConditionRuleType = "not"
+	ApiConditionRuleTypeNumeric  ApiConditionRuleType = "numeric"
+	ApiConditionRuleTypeOr       ApiConditionRuleType = "or"
+	ApiConditionRuleTypeState    ApiConditionRuleType = "state"
+	ApiConditionRuleTypeSun      ApiConditionRuleType = "sun"
+	ApiConditionRuleTypeTemplate ApiConditionRuleType = "template"
+	ApiConditionRuleTypeTime     ApiConditionRuleType = "time"
+)
+
+// Defines values for ApiNewTaskRequestMode.
+const (
+	ApiNewTaskRequestModeParallel ApiNewTaskRequestMode = "parallel"
+	ApiNewTaskRequestModeQueued   ApiNewTaskRequestMode = "queued"
+	ApiNewTaskRequestModeRestart  ApiNewTaskRequestMode = "restart"
+	ApiNewTaskRequestModeSingle   ApiNewTaskRequestMode = "single"
+)
+
+// Defines values for ApiTaskStepType.
+const (
+	ApiTaskStepTypeAction   ApiTaskStepType = "action"
+	ApiTaskStepTypeDelay    ApiTaskStepType = "delay"
+	ApiTaskStepTypeIf       ApiTaskStepType = "if"
+	ApiTaskStepTypeParallel ApiTaskStepType = "parallel"
+	ApiTaskStepTypeRepeat   ApiTaskStepType = "repeat"
+	ApiTaskStepTypeWait     ApiTaskStepType = "wait"
+)
+
+// Defines values for AutomationServiceUpdateTaskJSONBodyMode.
+const (
+	AutomationServiceUpdateTaskJSONBodyModeParallel AutomationServiceUpdateTaskJSONBodyMode = "parallel"
+	AutomationServiceUpdateTaskJSONBodyModeQueued   AutomationServiceUpdateTaskJSONBodyMode = "queued"
+	AutomationServiceUpdateTaskJSONBodyModeRestart  AutomationServiceUpdateTaskJSONBodyMode = "restart"
+	AutomationServiceUpdateTaskJSONBodyModeSingle   AutomationServiceUpdateTaskJSONBodyMode = "single"
+)
+
+// Defines values for EntityStorageServiceGetEntityStorageHistoryParamsAggregation.
+const (
+	Count    EntityStorageServiceGetEntityStorageHistoryParamsAggregation = "count"
+	Duration EntityStorageServiceGetEntityStorageHistoryParamsAggregation = "duration"
+	Last     EntityStorageServiceGetEntityStorageHistoryParamsAggregation = "last"
+	Max      EntityStorageServiceGetEntityStorageHistoryParamsAggregation = "max"
+	Mean     EntityStorageServiceGetEntityStorageHistoryParamsAggregation = "mean"
+	Min      EntityStorageServiceGetEntityStorageHistoryParamsAggregation = "min"
+)
+
 // Defines values for MetricRange.
 const (
 	MetricRangeN12h MetricRange = "12h"
 	MetricRangeN1m  MetricRange = "1m"
+	MetricRangeN1y  MetricRange = "1y"
 	MetricRangeN24h MetricRange = "24h"
 	MetricRangeN30d MetricRange = "30d"
 	MetricRangeN6h  MetricRange = "6h"
@@ -44,6 +99,7 @@ const (
 const (
 	MetricServiceGetMetricParamsRangeN12h MetricServiceGetMetricParamsRange = "12h"
 	MetricServiceGetMetricParamsRangeN1m  MetricServiceGetMetricParamsRange = "1m"
+	MetricServiceGetMetricParamsRangeN1y  MetricServiceGetMetricParamsRange = "1y"
 	MetricServiceGetMetricParamsRangeN24h MetricServiceGetMetricParamsRange = "24h"
 	MetricServiceGetMetricParamsRangeN30d MetricServiceGetMetricParamsRange = "30d"
 	MetricServiceGetMetricParamsRangeN6h  MetricServiceGetMetricParamsRange = "6h"
@@ -214,15 +270,69 @@ type ApiClient struct {
 
 // ApiCondition defines model for apiCondition.
 type ApiCondition struct {
-	Area        *ApiArea   `json:"area,omitempty"`
-	AreaId      *int64     `json:"areaId,omitempty"`
-	CreatedAt   time.Time  `json:"createdAt"`
-	Description string     `json:"description"`
-	Id          int64      `json:"id"`
-	Name        string     `json:"name"`
-	Script      *ApiScript `json:"script,omitempty"`
-	ScriptId    *int64     `json:"scriptId,omitempty"`
-	UpdatedAt   time.Time  `json:"updatedAt"`
+	Area        *ApiArea          `json:"area,omitempty"`
+	AreaId      *int64            `json:"areaId,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	Description string            `json:"description"`
+	Id          int64             `json:"id"`
+	Name        string            `json:"name"`
+	Rule        *ApiConditionRule `json:"rule,omitempty"`
+	Script      *ApiScript        `json:"script,omitempty"`
+	ScriptId    *int64            `json:"scriptId,omitempty"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// ApiConditionRule defines model for apiConditionRule.
+type ApiConditionRule struct {
+	Above *float64 `json:"above,omitempty"`
+
+	// After time of day, 15:04
+	After     *string `json:"after,omitempty"`
+	Attribute *string `json:"attribute,omitempty"`
+
+	// Before time of day, 15:04
+	Before   *string  `json:"before,omitempty"`
+	Below    *float64 `json:"below,omitempty"`
+	EntityId *string  `json:"entityId,omitempty"`
+
+	// For the state or value must be held at least (ms)
+	For      *int32               `json:"for,omitempty"`
+	NotEqual *bool                `json:"notEqual,omitempty"`
+	Rules    *[]ApiConditionRule  `json:"rules,omitempty"`
+	State    *string              `json:"state,omitempty"`
+	Template *string              `json:"template,omitempty"`
+	Type     ApiConditionRuleType `json:"type"`
+	Weekdays *[]int32             `json:"weekdays,omitempty"`
+}
+
+// ApiConditionRuleType defines model for ApiConditionRule.Type.
+type ApiConditionRuleType string
+
+// ApiConfigExportRequest defines model for apiConfigExportRequest.
+type ApiConfigExportRequest struct {
+	Actions    *[]int64  `json:"actions,omitempty"`
+	Areas      *[]int64  `json:"areas,omitempty"`
+	Conditions *[]int64  `json:"conditions,omitempty"`
+	Dashboards *[]int64  `json:"dashboards,omitempty"`
+	Entities   *[]string `json:"entities,omitempty"`
+	Scripts    *[]int64  `json:"scripts,omitempty"`
+	Tasks      *[]int64  `json:"tasks,omitempty"`
+	Triggers   *[]int64  `json:"triggers,omitempty"`
+	Variables  *[]string `json:"variables,omitempty"`
+}
+
+// ApiConfigImportItem defines model for apiConfigImportItem.
+type ApiConfigImportItem struct {
+	Name   string `json:"name"`
+	NewId  string `json:"newId"`
+	OldId  string `json:"oldId"`
+	Status string `json:"status"`
+	Type   string `json:"type"`
+}
+
+// ApiConfigImportResult defines model for apiConfigImportResult.
+type ApiConfigImportResult struct {
+	Items []ApiConfigImportItem `json:"items"`
 }
 
 // ApiCurrentUser defines model for apiCurrentUser.
@@ -363,6 +473,48 @@ type ApiDeviceWhitelistRequest struct {
 	Id           int64  `json:"id"`
 }
 
+// ApiAcceptDiscoveredDeviceRequest defines model for apiAcceptDiscoveredDeviceRequest.
+type ApiAcceptDiscoveredDeviceRequest struct {
+	AreaId      *int64  `json:"areaId,omitempty"`
+	Description *string `json:"description,omitempty"`
+
+	// Name the name part of the entity id, the proposed name if empty
+	Name *string `json:"name,omitempty"`
+}
+
+// ApiDiscoveredDevice defines model for apiDiscoveredDevice.
+type ApiDiscoveredDevice struct {
+	Address string `json:"address"`
+
+	// EntityId the proposed entity id
+	EntityId     string                 `json:"entityId"`
+	FirstSeen    time.Time              `json:"firstSeen"`
+	Id           string                 `json:"id"`
+	Kind         string                 `json:"kind"`
+	LastSeen     time.Time              `json:"lastSeen"`
+	Manufacturer string                 `json:"manufacturer"`
+	Model        string                 `json:"model"`
+	Name         string                 `json:"name"`
+	Plugin       string                 `json:"plugin"`
+	Port         int32                  `json:"port"`
+	Services     []ApiDiscoveredService `json:"services"`
+}
+
+// ApiDiscoveredService defines model for apiDiscoveredService.
+type ApiDiscoveredService struct {
+	Host     string                     `json:"host"`
+	LastSeen time.Time                  `json:"lastSeen"`
+	Location *string                    `json:"location,omitempty"`
+	Name     string                     `json:"name"`
+	Port     int32                      `json:"port"`
+	Source   ApiDiscoveredServiceSource `json:"source"`
+	Txt      map[string]string          `json:"txt"`
+	Type     string                     `json:"type"`
+}
+
+// ApiDiscoveredServiceSource defines model for ApiDiscoveredService.Source.
+type ApiDiscoveredServiceSource string
+
 // ApiDisablePluginResult defines model for apiDisablePluginResult.
 type ApiDisablePluginResult = map[string]interface{}
 
@@ -413,6 +565,15 @@ type ApiEntityCallActionRequest struct {
 	Tags       []string                `json:"tags"`
 }
 
+// ApiEntityHistoryPoint defines model for apiEntityHistoryPoint.
+type ApiEntityHistoryPoint struct {
+	EntityId string    `json:"entity_id"`
+	Time     time.Time `json:"time"`
+
+	// Value number, string or null for an empty bucket
+	Value interface{} `json:"value"`
+}
+
 // ApiEntityParent defines model for apiEntityParent.
 type ApiEntityParent struct {
 	Id string `json:"id"`
@@ -545,6 +706,16 @@ type ApiGetDashboardTabListResult struct {
 	Meta  *ApiMeta               `json:"meta,omitempty"`
 }
 
+// ApiGetDiscoveredDeviceListResult defines model for apiGetDiscoveredDeviceListResult.
+type ApiGetDiscoveredDeviceListResult struct {
+	Items []ApiDiscoveredDevice `json:"items"`
+}
+
+// ApiGetEntityHistoryResult defines model for apiGetEntityHistoryResult.
+type ApiGetEntityHistoryResult struct {
+	Items []ApiEntityHistoryPoint `json:"items"`
+}
+
 // ApiGetEntityListResult defines model for apiGetEntityListResult.
 type ApiGetEntityListResult struct {
 	Items []ApiEntityShort `json:"items"`
@@ -622,6 +793,12 @@ type ApiGetTaskListResult struct {
 	Meta  *ApiMeta  `json:"meta,omitempty"`
 }
 
+// ApiGetTaskRunListResult defines model for apiGetTaskRunListResult.
+type ApiGetTaskRunListResult struct {
+	Items []ApiTaskRun `json:"items"`
+	Meta  *ApiMeta     `json:"meta,omitempty"`
+}
+
 // ApiGetTriggerListResult defines model for apiGetTriggerListResult.
 type ApiGetTriggerListResult struct {
 	Items []ApiTrigger `json:"items"`
@@ -674,14 +851,25 @@ type ApiMessage struct {
 
 // ApiMessageDelivery defines model for apiMessageDelivery.
 type ApiMessageDelivery struct {
-	Address            string     `json:"address"`
-	CreatedAt          time.Time  `json:"createdAt"`
-	ErrorMessageBody   *string    `json:"errorMessageBody,omitempty"`
-	ErrorMessageStatus *string    `json:"errorMessageStatus,omitempty"`
-	Id                 int64      `json:"id"`
-	Message            ApiMessage `json:"message"`
-	Status             string     `json:"status"`
-	UpdatedAt          time.Time  `json:"updatedAt"`
+	Address            string                      `json:"address"`
+	AttemptLog         []ApiMessageDeliveryAttempt `json:"attemptLog"`
+	Attempts           int32                       `json:"attempts"`
+	CreatedAt          time.Time                   `json:"createdAt"`
+	ErrorMessageBody   *string                     `json:"errorMessageBody,omitempty"`
+	ErrorMessageStatus *string                     `json:"errorMessageStatus,omitempty"`
+	Fallback           []string                    `json:"fallback"`
+	Id                 int64                       `json:"id"`
+	Message            ApiMessage                  `json:"message"`
+	NextAttemptAt      *time.Time                  `json:"nextAttemptAt,omitempty"`
+	Status             string                      `json:"status"`
+	UpdatedAt          time.Time                   `json:"updatedAt"`
+}
+
+// ApiMessageDeliveryAttempt defines model for apiMessageDeliveryAttempt.
+type ApiMessageDeliveryAttempt struct {
+	Attempt   int32     `json:"attempt"`
+	CreatedAt time.Time `json:"createdAt"`
+	Error     *string   `json:"error,omitempty"`
 }
 
 // ApiMeta defines model for apiMeta.
@@ -751,10 +939,11 @@ type ApiNewAreaRequest struct {
 
 // ApiNewConditionRequest defines model for apiNewConditionRequest.
 type ApiNewConditionRequest struct {
-	AreaId      *int64 `json:"areaId,omitempty"`
-	Description string `json:"description"`
-	Name        string `json:"name"`
-	ScriptId    *int64 `json:"scriptId,omitempty"`
+	AreaId      *int64            `json:"areaId,omitempty"`
+	Description string            `json:"description"`
+	Name        string            `json:"name"`
+	Rule        *ApiConditionRule `json:"rule,omitempty"`
+	ScriptId    *int64            `json:"scriptId,omitempty"`
 }
 
 // ApiNewDashboardCardItemRequest defines model for apiNewDashboardCardItemRequest.
@@ -859,6 +1048,7 @@ type ApiNewRoleRequest struct {
 	Description string  `json:"description"`
 	Name        string  `json:"name"`
 	Parent      *string `json:"parent,omitempty"`
+	RequireTotp *bool   `json:"requireTotp,omitempty"`
 }
 
 // ApiNewScriptRequest defines model for apiNewScriptRequest.
@@ -867,19 +1057,26 @@ type ApiNewScriptRequest struct {
 	Lang        string `json:"lang"`
 	Name        string `json:"name"`
 	Source      string `json:"source"`
+	Timeout     *int32 `json:"timeout,omitempty"`
 }
 
 // ApiNewTaskRequest defines model for apiNewTaskRequest.
 type ApiNewTaskRequest struct {
-	ActionIds    []int64 `json:"actionIds"`
-	AreaId       *int64  `json:"areaId,omitempty"`
-	Condition    string  `json:"condition"`
-	ConditionIds []int64 `json:"conditionIds"`
-	Description  string  `json:"description"`
-	Enabled      bool    `json:"enabled"`
-	Name         string  `json:"name"`
-	TriggerIds   []int64 `json:"triggerIds"`
-}
+	ActionIds    []int64                `json:"actionIds"`
+	AreaId       *int64                 `json:"areaId,omitempty"`
+	Condition    string                 `json:"condition"`
+	ConditionIds []int64                `json:"conditionIds"`
+	Description  string                 `json:"description"`
+	Enabled      bool                   `json:"enabled"`
+	MaxRuns      *int32                 `json:"maxRuns,omitempty"`
+	Mode         *ApiNewTaskRequestMode `json:"mode,omitempty"`
+	Name         string                 `json:"name"`
+	Steps        *[]ApiTaskStep         `json:"steps,omitempty"`
+	TriggerIds   []int64                `json:"triggerIds"`
+}
+
+// ApiNewTaskRequestMode defines model for ApiNewTaskRequest.Mode.
+type ApiNewTaskRequestMode string
 
 // ApiNewTriggerRequest defines model for apiNewTriggerRequest.
 type ApiNewTriggerRequest struct {
@@ -902,11 +1099,12 @@ type ApiNewVariableRequest struct {
 
 // ApiNewZigbee2mqttRequest defines model for apiNewZigbee2mqttRequest.
 type ApiNewZigbee2mqttRequest struct {
-	BaseTopic  string  `json:"baseTopic"`
-	Login      string  `json:"login"`
-	Name       string  `json:"name"`
-	Password   *string `json:"password,omitempty"`
-	PermitJoin bool    `json:"permitJoin"`
+	AutoDiscovery bool    `json:"autoDiscovery"`
+	BaseTopic     string  `json:"baseTopic"`
+	Login         string  `json:"login"`
+	Name          string  `json:"name"`
+	Password      *string `json:"password,omitempty"`
+	PermitJoin    bool    `json:"permitJoin"`
 }
 
 // ApiNewtUserRequest defines model for apiNewtUserRequest.
@@ -931,6 +1129,11 @@ type ApiPagination struct {
 	Total uint64 `json:"total"`
 }
 
+// ApiGetUserSessionListResult defines model for apiGetUserSessionListResult.
+type ApiGetUserSessionListResult struct {
+	Items []ApiUserSession `json:"items"`
+}
+
 // ApiPasswordResetRequest defines model for apiPasswordResetRequest.
 type ApiPasswordResetRequest struct {
 	Email       string  `json:"email"`
@@ -1014,9 +1217,34 @@ type ApiRole struct {
 	Description string             `json:"description"`
 	Name        string             `json:"name"`
 	Parent      *ApiRole           `json:"parent,omitempty"`
+	RequireTotp bool               `json:"requireTotp"`
 	UpdatedAt   time.Time          `json:"updatedAt"`
 }
 
+// ApiEntityPermission defines model for apiEntityPermission.
+type ApiEntityPermission struct {
+	AreaId    *int64    `json:"areaId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	EntityId  *string   `json:"entityId,omitempty"`
+	Id        int64     `json:"id"`
+	Level     string    `json:"level"`
+	RoleName  string    `json:"roleName"`
+	Tag       *string   `json:"tag,omitempty"`
+}
+
+// ApiGetEntityPermissionListResult defines model for apiGetEntityPermissionListResult.
+type ApiGetEntityPermissionListResult struct {
+	Items []ApiEntityPermission `json:"items"`
+}
+
+// ApiNewEntityPermissionRequest defines model for apiNewEntityPermissionRequest.
+type ApiNewEntityPermissionRequest struct {
+	AreaId   *int64  `json:"areaId,omitempty"`
+	EntityId *string `json:"entityId,omitempty"`
+	Level    string  `json:"level"`
+	Tag      *string `json:"tag,omitempty"`
+}
+
 // ApiRoleAccessList defines model for apiRoleAccessList.
 type ApiRoleAccessList struct {
 	Levels map[string]AccessListListOfString `json:"levels"`
@@ -1036,6 +1264,7 @@ type ApiScript struct {
 	Name        string             `json:"name"`
 	ScriptInfo  *ApiScriptInfo     `json:"scriptInfo,omitempty"`
 	Source      string             `json:"source"`
+	Timeout     *int32             `json:"timeout,omitempty"`
 	UpdatedAt   time.Time          `json:"updatedAt"`
 	Versions    []ApiScriptVersion `json:"versions"`
 }
@@ -1118,10 +1347,17 @@ type ApiSearchVariableResult struct {
 	Items []ApiVariable `json:"items"`
 }
 
+// ApiRefreshTokenRequest defines model for apiRefreshTokenRequest.
+type ApiRefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
 // ApiSigninResponse defines model for apiSigninResponse.
 type ApiSigninResponse struct {
-	AccessToken string          `json:"accessToken"`
-	CurrentUser *ApiCurrentUser `json:"currentUser,omitempty"`
+	AccessToken          string          `json:"accessToken"`
+	AccessTokenExpiresAt time.Time       `json:"accessTokenExpiresAt"`
+	CurrentUser          *ApiCurrentUser `json:"currentUser,omitempty"`
+	RefreshToken         string          `json:"refreshToken"`
 }
 
 // ApiStatistic defines model for apiStatistic.
@@ -1170,13 +1406,60 @@ type ApiTask struct {
 	Enabled      bool               `json:"enabled"`
 	Id           int64              `json:"id"`
 	IsLoaded     *bool              `json:"isLoaded,omitempty"`
+	MaxRuns      int32              `json:"maxRuns"`
+	Mode         string             `json:"mode"`
 	Name         string             `json:"name"`
+	Steps        *[]ApiTaskStep     `json:"steps,omitempty"`
 	Telemetry    []ApiTelemetryItem `json:"telemetry"`
 	TriggerIds   []int64            `json:"triggerIds"`
 	Triggers     []ApiTrigger       `json:"triggers"`
 	UpdatedAt    time.Time          `json:"updatedAt"`
 }
 
+// ApiTaskRun defines model for apiTaskRun.
+type ApiTaskRun struct {
+	End       *time.Time              `json:"end,omitempty"`
+	EntityId  *string                 `json:"entityId,omitempty"`
+	Error     string                  `json:"error"`
+	Id        int64                   `json:"id"`
+	Payload   *map[string]interface{} `json:"payload,omitempty"`
+	Start     time.Time               `json:"start"`
+	Status    string                  `json:"status"`
+	TaskId    int64                   `json:"taskId"`
+	Trace     []ApiTaskRunStep        `json:"trace"`
+	TriggerId *int64                  `json:"triggerId,omitempty"`
+}
+
+// ApiTaskRunStep defines model for apiTaskRunStep.
+type ApiTaskRunStep struct {
+	Duration int64     `json:"duration"`
+	Error    *string   `json:"error,omitempty"`
+	Id       *int64    `json:"id,omitempty"`
+	Level    int32     `json:"level"`
+	Result   *string   `json:"result,omitempty"`
+	Start    time.Time `json:"start"`
+	Type     string    `json:"type"`
+}
+
+// ApiTaskStep defines model for apiTaskStep.
+type ApiTaskStep struct {
+	ActionId          *int64          `json:"actionId,omitempty"`
+	ConditionId       *int64          `json:"conditionId,omitempty"`
+	ContinueOnTimeout *bool           `json:"continueOnTimeout,omitempty"`
+	Delay             *int32          `json:"delay,omitempty"`
+	Else              *[]ApiTaskStep  `json:"else,omitempty"`
+	EntityId          *string         `json:"entityId,omitempty"`
+	MaxIterations     *int32          `json:"maxIterations,omitempty"`
+	State             *string         `json:"state,omitempty"`
+	Steps             *[]ApiTaskStep  `json:"steps,omitempty"`
+	Then              *[]ApiTaskStep  `json:"then,omitempty"`
+	Timeout           *int32          `json:"timeout,omitempty"`
+	Type              ApiTaskStepType `json:"type"`
+}
+
+// ApiTaskStepType defines model for ApiTaskStep.Type.
+type ApiTaskStepType string
+
 // ApiTelemetryItem defines model for apiTelemetryItem.
 type ApiTelemetryItem struct {
 	Attributes   map[string]string `json:"attributes"`
@@ -1284,6 +1567,61 @@ type ApiUserMeta struct {
 	Value string `json:"value"`
 }
 
+// ApiUserSession defines model for apiUserSession.
+type ApiUserSession struct {
+	CreatedAt  time.Time `json:"createdAt"`
+	Current    bool      `json:"current"`
+	Device     string    `json:"device"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Id         int64     `json:"id"`
+	Ip         string    `json:"ip"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+}
+
+// ApiApiToken defines model for apiApiToken.
+type ApiApiToken struct {
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	Id         int64      `json:"id"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+}
+
+// ApiGetApiTokenListResult defines model for apiGetApiTokenListResult.
+type ApiGetApiTokenListResult struct {
+	Items []ApiApiToken `json:"items"`
+}
+
+// ApiNewApiTokenRequest defines model for apiNewApiTokenRequest.
+type ApiNewApiTokenRequest struct {
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+}
+
+// ApiNewApiTokenResult defines model for apiNewApiTokenResult.
+type ApiNewApiTokenResult struct {
+	ApiToken ApiApiToken `json:"apiToken"`
+	Token    string      `json:"token"`
+}
+
+// ApiTotpCodeRequest defines model for apiTotpCodeRequest.
+type ApiTotpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// ApiTotpEnrolment defines model for apiTotpEnrolment.
+type ApiTotpEnrolment struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+// ApiTotpRecoveryCodes defines model for apiTotpRecoveryCodes.
+type ApiTotpRecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
 // ApiUserShot defines model for apiUserShot.
 type ApiUserShot struct {
 	CreatedAt time.Time          `json:"createdAt"`
@@ -1310,6 +1648,7 @@ type ApiUserShotParent struct {
 // ApiVariable defines model for apiVariable.
 type ApiVariable struct {
 	CreatedAt time.Time `json:"createdAt"`
+	Encrypted bool      `json:"encrypted"`
 	Name      string    `json:"name"`
 	System    bool      `json:"system"`
 	Tags      []string  `json:"tags"`
@@ -1319,6 +1658,7 @@ type ApiVariable struct {
 
 // ApiZigbee2mqtt defines model for apiZigbee2mqtt.
 type ApiZigbee2mqtt struct {
+	AutoDiscovery bool       `json:"autoDiscovery"`
 	BaseTopic     string     `json:"baseTopic"`
 	CreatedAt     time.Time  `json:"createdAt"`
 	Id            int64      `json:"id"`
@@ -1332,6 +1672,13 @@ type ApiZigbee2mqtt struct {
 	UpdatedAt     time.Time  `json:"updatedAt"`
 }
 
+// ApiZigbee2mqttBindRequest defines model for apiZigbee2mqttBindRequest.
+type ApiZigbee2mqttBindRequest struct {
+	Clusters []string `json:"clusters,omitempty"`
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+}
+
 // ApiZigbee2mqttDevice defines model for apiZigbee2mqttDevice.
 type ApiZigbee2mqttDevice struct {
 	CreatedAt     time.Time `json:"createdAt"`
@@ -1349,15 +1696,43 @@ type ApiZigbee2mqttDevice struct {
 	Zigbee2mqttId int64     `json:"zigbee2mqttId"`
 }
 
+// ApiZigbee2mqttDeviceOptionsRequest defines model for apiZigbee2mqttDeviceOptionsRequest.
+type ApiZigbee2mqttDeviceOptionsRequest struct {
+	FriendlyName string                 `json:"friendlyName"`
+	Options      map[string]interface{} `json:"options"`
+}
+
+// ApiZigbee2mqttDeviceRequest defines model for apiZigbee2mqttDeviceRequest.
+type ApiZigbee2mqttDeviceRequest struct {
+	FriendlyName string `json:"friendlyName"`
+}
+
+// ApiZigbee2mqttGroupMemberRequest defines model for apiZigbee2mqttGroupMemberRequest.
+type ApiZigbee2mqttGroupMemberRequest struct {
+	Device string `json:"device"`
+	Group  string `json:"group"`
+}
+
+// ApiZigbee2mqttGroupRequest defines model for apiZigbee2mqttGroupRequest.
+type ApiZigbee2mqttGroupRequest struct {
+	Name string `json:"name"`
+}
+
+// ApiZigbee2mqttOtaCheckResult defines model for apiZigbee2mqttOtaCheckResult.
+type ApiZigbee2mqttOtaCheckResult struct {
+	UpdateAvailable bool `json:"updateAvailable"`
+}
+
 // ApiZigbee2mqttShort defines model for apiZigbee2mqttShort.
 type ApiZigbee2mqttShort struct {
-	BaseTopic  string    `json:"baseTopic"`
-	CreatedAt  time.Time `json:"createdAt"`
-	Id         int64     `json:"id"`
-	Login      string    `json:"login"`
-	Name       string    `json:"name"`
-	PermitJoin bool      `json:"permitJoin"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	AutoDiscovery bool      `json:"autoDiscovery"`
+	BaseTopic     string    `json:"baseTopic"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Id            int64     `json:"id"`
+	Login         string    `json:"login"`
+	Name          string    `json:"name"`
+	PermitJoin    bool      `json:"permitJoin"`
+	UpdatedAt     time.Time `json:"updatedAt"`
 }
 
 // AcceptJSON defines model for Accept-JSON.
@@ -1514,6 +1889,22 @@ type AreaServiceSearchAreaParams struct {
 	Limit  *SearchLimit  `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// BackupServiceExportConfigParams defines parameters for BackupServiceExportConfig.
+type BackupServiceExportConfigParams struct {
+	// Format archive format, json or yaml
+	Format *string `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// BackupServiceImportConfigParams defines parameters for BackupServiceImportConfig.
+type BackupServiceImportConfigParams struct {
+	// Format archive format, json or yaml
+	Format *string `form:"format,omitempty" json:"format,omitempty"`
+
+	// Conflict what happens to the existing objects: skip, overwrite or rename
+	Conflict *string     `form:"conflict,omitempty" json:"conflict,omitempty"`
+	Accept   *AcceptJSON `json:"Accept,omitempty"`
+}
+
 // BackupServiceApplyStateParams defines parameters for BackupServiceApplyState.
 type BackupServiceApplyStateParams struct {
 	Accept *AcceptJSON `json:"Accept,omitempty"`
@@ -1566,10 +1957,11 @@ type ConditionServiceGetConditionByIdParams struct {
 
 // ConditionServiceUpdateConditionJSONBody defines parameters for ConditionServiceUpdateCondition.
 type ConditionServiceUpdateConditionJSONBody struct {
-	AreaId      *int64 `json:"areaId,omitempty"`
-	Description string `json:"description"`
-	Name        string `json:"name"`
-	ScriptId    *int64 `json:"scriptId,omitempty"`
+	AreaId      *int64            `json:"areaId,omitempty"`
+	Description string            `json:"description"`
+	Name        string            `json:"name"`
+	Rule        *ApiConditionRule `json:"rule,omitempty"`
+	ScriptId    *int64            `json:"scriptId,omitempty"`
 }
 
 // ConditionServiceUpdateConditionParams defines parameters for ConditionServiceUpdateCondition.
@@ -1849,6 +2241,26 @@ type EntityServiceUpdateEntityParams struct {
 	Accept *AcceptJSON `json:"Accept,omitempty"`
 }
 
+// EntityStorageServiceGetEntityStorageHistoryParams defines parameters for EntityStorageServiceGetEntityStorageHistory.
+type EntityStorageServiceGetEntityStorageHistoryParams struct {
+	EntityId  *EntityIds `form:"entityId[],omitempty" json:"entityId[],omitempty"`
+	StartDate *StartDate `form:"startDate,omitempty" json:"startDate,omitempty"`
+	EndDate   *EndDate   `form:"endDate,omitempty" json:"endDate,omitempty"`
+
+	// Attribute dot separated attribute path, the state if empty
+	Attribute   *string                                                       `form:"attribute,omitempty" json:"attribute,omitempty"`
+	Aggregation *EntityStorageServiceGetEntityStorageHistoryParamsAggregation `form:"aggregation,omitempty" json:"aggregation,omitempty"`
+
+	// Interval bucket size as a duration (15m, 1h), the whole range if empty
+	Interval *string `form:"interval,omitempty" json:"interval,omitempty"`
+
+	// State the value the duration aggregation is counted for
+	State *string `form:"state,omitempty" json:"state,omitempty"`
+}
+
+// EntityStorageServiceGetEntityStorageHistoryParamsAggregation defines parameters for EntityStorageServiceGetEntityStorageHistory.
+type EntityStorageServiceGetEntityStorageHistoryParamsAggregation string
+
 // EntityStorageServiceGetEntityStorageListParams defines parameters for EntityStorageServiceGetEntityStorageList.
 type EntityStorageServiceGetEntityStorageListParams struct {
 	// Sort Field on which to sort and its direction
@@ -2044,6 +2456,7 @@ type RoleServiceAddRoleParams struct {
 type RoleServiceUpdateRoleByNameJSONBody struct {
 	Description string  `json:"description"`
 	Parent      *string `json:"parent,omitempty"`
+	RequireTotp bool    `json:"requireTotp"`
 }
 
 // RoleServiceUpdateRoleByNameParams defines parameters for RoleServiceUpdateRoleByName.
@@ -2096,6 +2509,7 @@ type ScriptServiceUpdateScriptByIdJSONBody struct {
 	Lang        string `json:"lang"`
 	Name        string `json:"name"`
 	Source      string `json:"source"`
+	Timeout     *int32 `json:"timeout,omitempty"`
 }
 
 // ScriptServiceUpdateScriptByIdParams defines parameters for ScriptServiceUpdateScriptById.
@@ -2164,15 +2578,21 @@ type AutomationServiceAddTaskParams struct {
 
 // AutomationServiceUpdateTaskJSONBody defines parameters for AutomationServiceUpdateTask.
 type AutomationServiceUpdateTaskJSONBody struct {
-	ActionIds    []int64 `json:"actionIds"`
-	AreaId       *int64  `json:"areaId,omitempty"`
-	Condition    string  `json:"condition"`
-	ConditionIds []int64 `json:"conditionIds"`
-	Description  string  `json:"description"`
-	Enabled      bool    `json:"enabled"`
-	Name         string  `json:"name"`
-	TriggerIds   []int64 `json:"triggerIds"`
-}
+	ActionIds    []int64                                  `json:"actionIds"`
+	AreaId       *int64                                   `json:"areaId,omitempty"`
+	Condition    string                                   `json:"condition"`
+	ConditionIds []int64                                  `json:"conditionIds"`
+	Description  string                                   `json:"description"`
+	Enabled      bool                                     `json:"enabled"`
+	MaxRuns      *int32                                   `json:"maxRuns,omitempty"`
+	Mode         *AutomationServiceUpdateTaskJSONBodyMode `json:"mode,omitempty"`
+	Name         string                                   `json:"name"`
+	Steps        *[]ApiTaskStep                           `json:"steps,omitempty"`
+	TriggerIds   []int64                                  `json:"triggerIds"`
+}
+
+// AutomationServiceUpdateTaskJSONBodyMode defines parameters for AutomationServiceUpdateTask.
+type AutomationServiceUpdateTaskJSONBodyMode string
 
 // AutomationServiceUpdateTaskParams defines parameters for AutomationServiceUpdateTask.
 type AutomationServiceUpdateTaskParams struct {
@@ -2191,6 +2611,18 @@ type AutomationServiceGetTaskListParams struct {
 	Limit *ListLimit `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
+// AutomationServiceGetTaskRunListParams defines parameters for AutomationServiceGetTaskRunList.
+type AutomationServiceGetTaskRunListParams struct {
+	// Sort Field on which to sort and its direction
+	Sort *ListSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Page Page number of the requested result set
+	Page *ListPage `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit The number of results returned on a page
+	Limit *ListLimit `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // AutomationServiceImportTaskParams defines parameters for AutomationServiceImportTask.
 type AutomationServiceImportTaskParams struct {
 	Accept *AcceptJSON `json:"Accept,omitempty"`
@@ -2335,11 +2767,12 @@ type Zigbee2mqttServiceAddZigbee2mqttBridgeParams struct {
 
 // Zigbee2mqttServiceUpdateBridgeByIdJSONBody defines parameters for Zigbee2mqttServiceUpdateBridgeById.
 type Zigbee2mqttServiceUpdateBridgeByIdJSONBody struct {
-	BaseTopic  string  `json:"baseTopic"`
-	Login      string  `json:"login"`
-	Name       string  `json:"name"`
-	Password   *string `json:"password,omitempty"`
-	PermitJoin bool    `json:"permitJoin"`
+	AutoDiscovery bool    `json:"autoDiscovery"`
+	BaseTopic     string  `json:"baseTopic"`
+	Login         string  `json:"login"`
+	Name          string  `json:"name"`
+	Password      *string `json:"password,omitempty"`
+	PermitJoin    bool    `json:"permitJoin"`
 }
 
 // Zigbee2mqttServiceUpdateBridgeByIdParams defines parameters for Zigbee2mqttServiceUpdateBridgeById.
@@ -2396,6 +2829,9 @@ type AreaServiceUpdateAreaJSONRequestBody AreaServiceUpdateAreaJSONBody
 // BackupServiceUploadBackupMultipartRequestBody defines body for BackupServiceUploadBackup for multipart/form-data ContentType.
 type BackupServiceUploadBackupMultipartRequestBody BackupServiceUploadBackupMultipartBody
 
+// BackupServiceExportConfigJSONRequestBody defines body for BackupServiceExportConfig for application/json ContentType.
+type BackupServiceExportConfigJSONRequestBody = ApiConfigExportRequest
+
 // BackupServiceNewBackupJSONRequestBody defines body for BackupServiceNewBackup for application/json ContentType.
 type BackupServiceNewBackupJSONRequestBody = BackupServiceNewBackupJSONBody
 
@@ -2453,6 +2889,9 @@ type DeveloperToolsServiceEntitySetStateJSONRequestBody = ApiEntityRequest
 // EntityServiceImportEntityJSONRequestBody defines body for EntityServiceImportEntity for application/json ContentType.
 type EntityServiceImportEntityJSONRequestBody = ApiEntity
 
+// DiscoveryServiceAcceptDeviceJSONRequestBody defines body for DiscoveryServiceAcceptDevice for application/json ContentType.
+type DiscoveryServiceAcceptDeviceJSONRequestBody = ApiAcceptDiscoveredDeviceRequest
+
 // EntityServiceAddEntityJSONRequestBody defines body for EntityServiceAddEntity for application/json ContentType.
 type EntityServiceAddEntityJSONRequestBody = ApiNewEntityRequest
 
@@ -2471,6 +2910,9 @@ type ImageServiceUpdateImageByIdJSONRequestBody ImageServiceUpdateImageByIdJSONB
 // AuthServicePasswordResetJSONRequestBody defines body for AuthServicePasswordReset for application/json ContentType.
 type AuthServicePasswordResetJSONRequestBody = ApiPasswordResetRequest
 
+// AuthServiceRefreshTokenJSONRequestBody defines body for AuthServiceRefreshToken for application/json ContentType.
+type AuthServiceRefreshTokenJSONRequestBody = ApiRefreshTokenRequest
+
 // PluginServiceUpdatePluginSettingsJSONRequestBody defines body for PluginServiceUpdatePluginSettings for application/json ContentType.
 type PluginServiceUpdatePluginSettingsJSONRequestBody PluginServiceUpdatePluginSettingsJSONBody
 
@@ -2483,6 +2925,9 @@ type RoleServiceAddRoleJSONRequestBody = ApiNewRoleRequest
 // RoleServiceUpdateRoleByNameJSONRequestBody defines body for RoleServiceUpdateRoleByName for application/json ContentType.
 type RoleServiceUpdateRoleByNameJSONRequestBody RoleServiceUpdateRoleByNameJSONBody
 
+// RoleServiceAddEntityPermissionJSONRequestBody defines body for RoleServiceAddEntityPermission for application/json ContentType.
+type RoleServiceAddEntityPermissionJSONRequestBody = ApiNewEntityPermissionRequest
+
 // RoleServiceUpdateRoleAccessListJSONRequestBody defines body for RoleServiceUpdateRoleAccessList for application/json ContentType.
 type RoleServiceUpdateRoleAccessListJSONRequestBody RoleServiceUpdateRoleAccessListJSONBody
 
@@ -2516,6 +2961,18 @@ type TriggerServiceUpdateTriggerJSONRequestBody TriggerServiceUpdateTriggerJSONB
 // UserServiceAddUserJSONRequestBody defines body for UserServiceAddUser for application/json ContentType.
 type UserServiceAddUserJSONRequestBody = ApiNewtUserRequest
 
+// UserServiceAddApiTokenJSONRequestBody defines body for UserServiceAddApiToken for application/json ContentType.
+type UserServiceAddApiTokenJSONRequestBody = ApiNewApiTokenRequest
+
+// UserServiceConfirmTotpJSONRequestBody defines body for UserServiceConfirmTotp for application/json ContentType.
+type UserServiceConfirmTotpJSONRequestBody = ApiTotpCodeRequest
+
+// UserServiceDisableTotpJSONRequestBody defines body for UserServiceDisableTotp for application/json ContentType.
+type UserServiceDisableTotpJSONRequestBody = ApiTotpCodeRequest
+
+// UserServiceRegenerateRecoveryCodesJSONRequestBody defines body for UserServiceRegenerateRecoveryCodes for application/json ContentType.
+type UserServiceRegenerateRecoveryCodesJSONRequestBody = ApiTotpCodeRequest
+
 // UserServiceUpdateUserByIdJSONRequestBody defines body for UserServiceUpdateUserById for application/json ContentType.
 type UserServiceUpdateUserByIdJSONRequestBody UserServiceUpdateUserByIdJSONBody
 
@@ -2528,6 +2985,33 @@ type VariableServiceUpdateVariableJSONRequestBody VariableServiceUpdateVariableJ
 // Zigbee2mqttServiceAddZigbee2mqttBridgeJSONRequestBody defines body for Zigbee2mqttServiceAddZigbee2mqttBridge for application/json ContentType.
 type Zigbee2mqttServiceAddZigbee2mqttBridgeJSONRequestBody = ApiNewZigbee2mqttRequest
 
+// Zigbee2mqttServiceDeviceBindJSONRequestBody defines body for Zigbee2mqttServiceDeviceBind for application/json ContentType.
+type Zigbee2mqttServiceDeviceBindJSONRequestBody = ApiZigbee2mqttBindRequest
+
+// Zigbee2mqttServiceDeviceOptionsJSONRequestBody defines body for Zigbee2mqttServiceDeviceOptions for application/json ContentType.
+type Zigbee2mqttServiceDeviceOptionsJSONRequestBody = ApiZigbee2mqttDeviceOptionsRequest
+
+// Zigbee2mqttServiceDeviceOtaCheckJSONRequestBody defines body for Zigbee2mqttServiceDeviceOtaCheck for application/json ContentType.
+type Zigbee2mqttServiceDeviceOtaCheckJSONRequestBody = ApiZigbee2mqttDeviceRequest
+
+// Zigbee2mqttServiceDeviceOtaUpdateJSONRequestBody defines body for Zigbee2mqttServiceDeviceOtaUpdate for application/json ContentType.
+type Zigbee2mqttServiceDeviceOtaUpdateJSONRequestBody = ApiZigbee2mqttDeviceRequest
+
+// Zigbee2mqttServiceDeviceUnbindJSONRequestBody defines body for Zigbee2mqttServiceDeviceUnbind for application/json ContentType.
+type Zigbee2mqttServiceDeviceUnbindJSONRequestBody = ApiZigbee2mqttBindRequest
+
+// Zigbee2mqttServiceAddGroupJSONRequestBody defines body for Zigbee2mqttServiceAddGroup for application/json ContentType.
+type Zigbee2mqttServiceAddGroupJSONRequestBody = ApiZigbee2mqttGroupRequest
+
+// Zigbee2mqttServiceGroupAddMemberJSONRequestBody defines body for Zigbee2mqttServiceGroupAddMember for application/json ContentType.
+type Zigbee2mqttServiceGroupAddMemberJSONRequestBody = ApiZigbee2mqttGroupMemberRequest
+
+// Zigbee2mqttServiceGroupRemoveMemberJSONRequestBody defines body for Zigbee2mqttServiceGroupRemoveMember for application/json ContentType.
+type Zigbee2mqttServiceGroupRemoveMemberJSONRequestBody = ApiZigbee2mqttGroupMemberRequest
+
+// Zigbee2mqttServiceRemoveGroupJSONRequestBody defines body for Zigbee2mqttServiceRemoveGroup for application/json ContentType.
+type Zigbee2mqttServiceRemoveGroupJSONRequestBody = ApiZigbee2mqttGroupRequest
+
 // Zigbee2mqttServiceUpdateBridgeByIdJSONRequestBody defines body for Zigbee2mqttServiceUpdateBridgeById for application/json ContentType.
 type Zigbee2mqttServiceUpdateBridgeByIdJSONRequestBody Zigbee2mqttServiceUpdateBridgeByIdJSONBody
 
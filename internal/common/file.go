@@ -31,6 +31,7 @@ const (
 	dataDir         = "data"
 	fileStoragePath = "file_storage"
 	staticPath      = "static"
+	recordsPath     = "records"
 	depth           = 3
 )
 
@@ -89,6 +90,11 @@ func StaticPath() string {
 	return filepath.Join(dataDir, staticPath)
 }
 
+// RecordsPath ...
+func RecordsPath() string {
+	return filepath.Join(dataDir, recordsPath)
+}
+
 // FileExist ...
 func FileExist(path string) (exist bool) {
 
@@ -20,6 +20,7 @@ package common
 
 import (
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -39,6 +40,19 @@ func ComputeHmac256() string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// RandomToken returns the hex encoded random bytes
+func RandomToken(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// HashToken the tokens are stored as the hash
+func HashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
 // ParseHmacToken ...
 func ParseHmacToken(tokenString string, key []byte) (jwt.MapClaims, error) {
 
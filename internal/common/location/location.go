@@ -119,18 +119,55 @@ func GetDistanceToPolygon(point1 m.Point, polygon1 []m.Point, unit ...string) (d
 	return
 }
 
+// GetDistanceToPolygonEdge returns the distance to the nearest edge of the polygon
+func GetDistanceToPolygonEdge(point1 m.Point, polygon1 []m.Point, unit ...string) (distance float64) {
+
+	point := s2.PointFromLatLng(s2.LatLngFromDegrees(point1.Lat, point1.Lon))
+
+	vertices := loopFromPolygon(polygon1).Vertices()
+	if len(vertices) == 0 {
+		return
+	}
+
+	minDistance := point.Distance(vertices[0])
+	for i, vertex := range vertices {
+		next := vertices[(i+1)%len(vertices)]
+		if distance := s2.DistanceFromSegment(point, vertex, next); distance < minDistance {
+			minDistance = distance
+		}
+	}
+
+	distance = angleToDistance(minDistance, unit...)
+
+	return
+}
+
+// PointInsidePolygon the polygon may be drawn in either direction, it is the smaller of the two regions
 func PointInsidePolygon(point1 m.Point, polygon1 []m.Point) bool {
 
 	point := s2.PointFromLatLng(s2.LatLngFromDegrees(point1.Lat, point1.Lon))
 
+	loop := loopFromPolygon(polygon1)
+	if loop.NumVertices() < 3 {
+		return false
+	}
+	loop.Normalize()
+
+	return loop.ContainsPoint(point)
+}
+
+// loopFromPolygon drops the closing vertex which repeats the first one
+func loopFromPolygon(polygon []m.Point) *s2.Loop {
+	if n := len(polygon); n > 1 && polygon[0] == polygon[n-1] {
+		polygon = polygon[:n-1]
+	}
+
 	var points []s2.Point
-	for _, point := range polygon1 {
+	for _, point := range polygon {
 		points = append(points, s2.PointFromLatLng(s2.LatLngFromDegrees(point.Lat, point.Lon)))
 	}
 
-	loop := s2.LoopFromPoints(points)
-
-	return !loop.ContainsPoint(point)
+	return s2.LoopFromPoints(points)
 }
 
 // Преобразовать угол в расстояние с учетом радиуса Земли.
@@ -130,3 +130,39 @@ func TestPointInsidePolygon(t *testing.T) {
 	require.True(t, contains)
 
 }
+
+func TestPointInsidePolygonDirection(t *testing.T) {
+
+	square := []m.Point{
+		{Lon: 37.60, Lat: 55.70},
+		{Lon: 37.62, Lat: 55.70},
+		{Lon: 37.62, Lat: 55.72},
+		{Lon: 37.60, Lat: 55.72},
+	}
+	reversed := []m.Point{square[3], square[2], square[1], square[0]}
+
+	inside := m.Point{Lon: 37.61, Lat: 55.71}
+	outside := m.Point{Lon: 37.65, Lat: 55.71}
+
+	for _, polygon := range [][]m.Point{square, reversed} {
+		require.True(t, PointInsidePolygon(inside, polygon))
+		require.False(t, PointInsidePolygon(outside, polygon))
+	}
+
+	require.False(t, PointInsidePolygon(inside, square[:2]))
+}
+
+func TestGetDistanceToPolygonEdge(t *testing.T) {
+
+	square := []m.Point{
+		{Lon: 37.60, Lat: 55.70},
+		{Lon: 37.62, Lat: 55.70},
+		{Lon: 37.62, Lat: 55.72},
+		{Lon: 37.60, Lat: 55.72},
+	}
+
+	// the middle of the east edge is far from the vertices
+	point := m.Point{Lon: 37.63, Lat: 55.71}
+	require.InDelta(t, 0.63, GetDistanceToPolygonEdge(point, square), 0.01)
+	require.Greater(t, GetDistanceToPolygon(point, square), 1.0)
+}
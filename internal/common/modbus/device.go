@@ -0,0 +1,105 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Device is the slave polled by the register map
+type Device struct {
+	Client    *Client
+	SlaveId   byte
+	Registers []Register
+}
+
+// Poll reads all registers, the values are keyed by the register name
+func (d *Device) Poll() (values map[string]interface{}, err error) {
+	values = make(map[string]interface{}, len(d.Registers))
+	for _, b := range blocks(d.Registers) {
+		var words []uint16
+		switch b.function {
+		case FuncReadCoils, FuncReadDiscreteInputs:
+			var bits []bool
+			if bits, err = d.Client.readBits(d.SlaveId, b.function, b.address, b.count); err != nil {
+				return
+			}
+			words = make([]uint16, len(bits))
+			for i, bit := range bits {
+				if bit {
+					words[i] = 1
+				}
+			}
+		default:
+			if words, err = d.Client.readRegisters(d.SlaveId, b.function, b.address, b.count); err != nil {
+				return
+			}
+		}
+		for _, r := range b.registers {
+			offset := r.Address - b.address
+			values[r.Name] = r.Decode(words[offset : offset+r.Count()])
+		}
+	}
+	return
+}
+
+// Write the value to the writable register
+func (d *Device) Write(name string, value interface{}) error {
+	var register *Register
+	for i := range d.Registers {
+		if d.Registers[i].Name == name {
+			register = &d.Registers[i]
+			break
+		}
+	}
+	if register == nil {
+		return fmt.Errorf("register %s not found", name)
+	}
+	if !register.Writable {
+		return fmt.Errorf("register %s is not writable", name)
+	}
+	words, err := register.Encode(value)
+	if err != nil {
+		return err
+	}
+	switch {
+	case register.Function == FuncReadCoils:
+		return d.Client.WriteSingleCoil(d.SlaveId, register.Address, words[0] != 0)
+	case len(words) == 1:
+		return d.Client.WriteSingleRegister(d.SlaveId, register.Address, words[0])
+	default:
+		return d.Client.WriteMultipleRegisters(d.SlaveId, register.Address, words)
+	}
+}
+
+// Run polls the device every interval until the context is done, the first poll is immediate
+func (d *Device) Run(ctx context.Context, interval time.Duration, handler func(values map[string]interface{}, err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		handler(d.Poll())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
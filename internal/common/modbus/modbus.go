@@ -0,0 +1,290 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+// Function codes
+const (
+	FuncReadCoils              = byte(0x01)
+	FuncReadDiscreteInputs     = byte(0x02)
+	FuncReadHoldingRegisters   = byte(0x03)
+	FuncReadInputRegisters     = byte(0x04)
+	FuncWriteSingleCoil        = byte(0x05)
+	FuncWriteSingleRegister    = byte(0x06)
+	FuncWriteMultipleCoils     = byte(0x0F)
+	FuncWriteMultipleRegisters = byte(0x10)
+	FuncReadWriteMultipleRegs  = byte(0x17)
+)
+
+const (
+	maxReadBits       = 2000
+	maxReadRegisters  = 125
+	maxWriteBits      = 1968
+	maxWriteRegisters = 123
+	maxReadWriteRegs  = 121
+)
+
+// Exception is the error answered by the slave
+type Exception struct {
+	Function byte
+	Code     byte
+}
+
+func (e *Exception) Error() string {
+	var text string
+	switch e.Code {
+	case 1:
+		text = "illegal function"
+	case 2:
+		text = "illegal data address"
+	case 3:
+		text = "illegal data value"
+	case 4:
+		text = "server device failure"
+	case 6:
+		text = "server device busy"
+	default:
+		text = "exception"
+	}
+	return fmt.Sprintf("modbus function 0x%02x: %s (%d)", e.Function, text, e.Code)
+}
+
+// Transport sends the pdu, the function code with the data, to the slave and returns the response pdu
+type Transport interface {
+	Send(slaveId byte, pdu []byte) ([]byte, error)
+	Close() error
+}
+
+// Client is the modbus master, the requests are serialized
+type Client struct {
+	transport Transport
+	sync.Mutex
+}
+
+// NewClient ...
+func NewClient(transport Transport) *Client {
+	return &Client{
+		transport: transport,
+	}
+}
+
+// Close ...
+func (c *Client) Close() error {
+	c.Lock()
+	defer c.Unlock()
+	return c.transport.Close()
+}
+
+func (c *Client) send(slaveId byte, pdu []byte) (resp []byte, err error) {
+	c.Lock()
+	resp, err = c.transport.Send(slaveId, pdu)
+	c.Unlock()
+	if err != nil {
+		return
+	}
+	switch {
+	case len(resp) == 2 && resp[0] == pdu[0]|0x80:
+		err = &Exception{Function: pdu[0], Code: resp[1]}
+	case len(resp) < 2 || resp[0] != pdu[0]:
+		err = fmt.Errorf("modbus function 0x%02x: bad response % x", pdu[0], resp)
+	}
+	return
+}
+
+// ReadCoils ...
+func (c *Client) ReadCoils(slaveId byte, address, quantity uint16) ([]bool, error) {
+	return c.readBits(slaveId, FuncReadCoils, address, quantity)
+}
+
+// ReadDiscreteInputs ...
+func (c *Client) ReadDiscreteInputs(slaveId byte, address, quantity uint16) ([]bool, error) {
+	return c.readBits(slaveId, FuncReadDiscreteInputs, address, quantity)
+}
+
+// ReadHoldingRegisters ...
+func (c *Client) ReadHoldingRegisters(slaveId byte, address, quantity uint16) ([]uint16, error) {
+	return c.readRegisters(slaveId, FuncReadHoldingRegisters, address, quantity)
+}
+
+// ReadInputRegisters ...
+func (c *Client) ReadInputRegisters(slaveId byte, address, quantity uint16) ([]uint16, error) {
+	return c.readRegisters(slaveId, FuncReadInputRegisters, address, quantity)
+}
+
+// WriteSingleCoil ...
+func (c *Client) WriteSingleCoil(slaveId byte, address uint16, value bool) (err error) {
+	var v uint16
+	if value {
+		v = 0xFF00
+	}
+	_, err = c.send(slaveId, request(FuncWriteSingleCoil, address, v))
+	return
+}
+
+// WriteSingleRegister ...
+func (c *Client) WriteSingleRegister(slaveId byte, address, value uint16) (err error) {
+	_, err = c.send(slaveId, request(FuncWriteSingleRegister, address, value))
+	return
+}
+
+// WriteMultipleCoils ...
+func (c *Client) WriteMultipleCoils(slaveId byte, address uint16, values []bool) (err error) {
+	if len(values) == 0 || len(values) > maxWriteBits {
+		return fmt.Errorf("modbus: bad quantity %d", len(values))
+	}
+	data := make([]byte, (len(values)+7)/8)
+	for i, value := range values {
+		if value {
+			data[i/8] |= 1 << (i % 8)
+		}
+	}
+	pdu := append(request(FuncWriteMultipleCoils, address, uint16(len(values))), byte(len(data)))
+	_, err = c.send(slaveId, append(pdu, data...))
+	return
+}
+
+// WriteMultipleRegisters ...
+func (c *Client) WriteMultipleRegisters(slaveId byte, address uint16, values []uint16) (err error) {
+	if len(values) == 0 || len(values) > maxWriteRegisters {
+		return fmt.Errorf("modbus: bad quantity %d", len(values))
+	}
+	pdu := append(request(FuncWriteMultipleRegisters, address, uint16(len(values))), byte(len(values)*2))
+	for _, value := range values {
+		pdu = binary.BigEndian.AppendUint16(pdu, value)
+	}
+	_, err = c.send(slaveId, pdu)
+	return
+}
+
+// ReadWriteMultipleRegisters writes the values and reads the registers in one request
+func (c *Client) ReadWriteMultipleRegisters(slaveId byte, readAddress, quantity, writeAddress uint16, values []uint16) (result []uint16, err error) {
+	if quantity == 0 || quantity > maxReadRegisters {
+		return nil, fmt.Errorf("modbus: bad quantity %d", quantity)
+	}
+	if len(values) == 0 || len(values) > maxReadWriteRegs {
+		return nil, fmt.Errorf("modbus: bad quantity %d", len(values))
+	}
+	pdu := request(FuncReadWriteMultipleRegs, readAddress, quantity)
+	pdu = binary.BigEndian.AppendUint16(pdu, writeAddress)
+	pdu = binary.BigEndian.AppendUint16(pdu, uint16(len(values)))
+	pdu = append(pdu, byte(len(values)*2))
+	for _, value := range values {
+		pdu = binary.BigEndian.AppendUint16(pdu, value)
+	}
+	var resp []byte
+	if resp, err = c.send(slaveId, pdu); err != nil {
+		return
+	}
+	return decodeRegisters(FuncReadWriteMultipleRegs, resp, quantity)
+}
+
+// Execute calls the function by name, the bits are returned as 0 and 1, used by the script bindings
+func (c *Client) Execute(slaveId byte, function string, address, count uint16, command []uint16) (result []uint16, err error) {
+	var bits []bool
+	switch function {
+	case "ReadCoils":
+		bits, err = c.ReadCoils(slaveId, address, count)
+	case "ReadDiscreteInputs":
+		bits, err = c.ReadDiscreteInputs(slaveId, address, count)
+	case "ReadHoldingRegisters":
+		result, err = c.ReadHoldingRegisters(slaveId, address, count)
+	case "ReadInputRegisters":
+		result, err = c.ReadInputRegisters(slaveId, address, count)
+	case "WriteSingleCoil", "WriteSingleRegister":
+		if len(command) != 1 {
+			return nil, fmt.Errorf("%s: one value expected", function)
+		}
+		if function == "WriteSingleCoil" {
+			err = c.WriteSingleCoil(slaveId, address, command[0] != 0)
+		} else {
+			err = c.WriteSingleRegister(slaveId, address, command[0])
+		}
+	case "WriteMultipleCoils":
+		values := make([]bool, len(command))
+		for i, value := range command {
+			values[i] = value != 0
+		}
+		err = c.WriteMultipleCoils(slaveId, address, values)
+	case "WriteMultipleRegisters":
+		err = c.WriteMultipleRegisters(slaveId, address, command)
+	case "ReadWriteMultipleRegisters":
+		result, err = c.ReadWriteMultipleRegisters(slaveId, address, count, address, command)
+	default:
+		err = fmt.Errorf("function %s is not supported", function)
+	}
+	for _, bit := range bits {
+		var value uint16
+		if bit {
+			value = 1
+		}
+		result = append(result, value)
+	}
+	return
+}
+
+func (c *Client) readBits(slaveId, function byte, address, quantity uint16) (values []bool, err error) {
+	if quantity == 0 || quantity > maxReadBits {
+		return nil, fmt.Errorf("modbus: bad quantity %d", quantity)
+	}
+	var resp []byte
+	if resp, err = c.send(slaveId, request(function, address, quantity)); err != nil {
+		return
+	}
+	if len(resp) < 2 || int(resp[1]) != (int(quantity)+7)/8 || len(resp) != 2+int(resp[1]) {
+		return nil, fmt.Errorf("modbus function 0x%02x: bad response length %d", function, len(resp))
+	}
+	values = make([]bool, quantity)
+	for i := range values {
+		values[i] = resp[2+i/8]&(1<<(i%8)) != 0
+	}
+	return
+}
+
+func (c *Client) readRegisters(slaveId, function byte, address, quantity uint16) (values []uint16, err error) {
+	if quantity == 0 || quantity > maxReadRegisters {
+		return nil, fmt.Errorf("modbus: bad quantity %d", quantity)
+	}
+	var resp []byte
+	if resp, err = c.send(slaveId, request(function, address, quantity)); err != nil {
+		return
+	}
+	return decodeRegisters(function, resp, quantity)
+}
+
+func decodeRegisters(function byte, resp []byte, quantity uint16) ([]uint16, error) {
+	if len(resp) < 2 || int(resp[1]) != int(quantity)*2 || len(resp) != 2+int(resp[1]) {
+		return nil, fmt.Errorf("modbus function 0x%02x: bad response length %d", function, len(resp))
+	}
+	values := make([]uint16, quantity)
+	for i := range values {
+		values[i] = binary.BigEndian.Uint16(resp[2+i*2:])
+	}
+	return values, nil
+}
+
+func request(function byte, address, value uint16) []byte {
+	pdu := []byte{function}
+	pdu = binary.BigEndian.AppendUint16(pdu, address)
+	return binary.BigEndian.AppendUint16(pdu, value)
+}
@@ -0,0 +1,428 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// simulator is the in memory slave
+type simulator struct {
+	sync.Mutex
+	coils     [64]bool
+	inputs    [64]bool
+	holding   [64]uint16
+	inputRegs [64]uint16
+}
+
+func (s *simulator) handle(pdu []byte) []byte {
+	s.Lock()
+	defer s.Unlock()
+
+	exception := func(code byte) []byte {
+		return []byte{pdu[0] | 0x80, code}
+	}
+	address := int(binary.BigEndian.Uint16(pdu[1:]))
+	value := binary.BigEndian.Uint16(pdu[3:])
+	count := int(value)
+
+	switch pdu[0] {
+	case FuncReadCoils, FuncReadDiscreteInputs:
+		bits := s.coils[:]
+		if pdu[0] == FuncReadDiscreteInputs {
+			bits = s.inputs[:]
+		}
+		if address+count > len(bits) {
+			return exception(2)
+		}
+		resp := []byte{pdu[0], byte((count + 7) / 8)}
+		resp = append(resp, make([]byte, resp[1])...)
+		for i := 0; i < count; i++ {
+			if bits[address+i] {
+				resp[2+i/8] |= 1 << (i % 8)
+			}
+		}
+		return resp
+	case FuncReadHoldingRegisters, FuncReadInputRegisters:
+		regs := s.holding[:]
+		if pdu[0] == FuncReadInputRegisters {
+			regs = s.inputRegs[:]
+		}
+		if address+count > len(regs) {
+			return exception(2)
+		}
+		resp := []byte{pdu[0], byte(count * 2)}
+		for i := 0; i < count; i++ {
+			resp = binary.BigEndian.AppendUint16(resp, regs[address+i])
+		}
+		return resp
+	case FuncWriteSingleCoil:
+		if address >= len(s.coils) {
+			return exception(2)
+		}
+		s.coils[address] = value == 0xFF00
+		return pdu
+	case FuncWriteSingleRegister:
+		if address >= len(s.holding) {
+			return exception(2)
+		}
+		s.holding[address] = value
+		return pdu
+	case FuncWriteMultipleCoils:
+		if address+count > len(s.coils) {
+			return exception(2)
+		}
+		for i := 0; i < count; i++ {
+			s.coils[address+i] = pdu[6+i/8]&(1<<(i%8)) != 0
+		}
+		return pdu[:5]
+	case FuncReadWriteMultipleRegs:
+		writeAddress := int(binary.BigEndian.Uint16(pdu[5:]))
+		writeCount := int(binary.BigEndian.Uint16(pdu[7:]))
+		if address+count > len(s.holding) || writeAddress+writeCount > len(s.holding) {
+			return exception(2)
+		}
+		for i := 0; i < writeCount; i++ {
+			s.holding[writeAddress+i] = binary.BigEndian.Uint16(pdu[10+i*2:])
+		}
+		resp := []byte{pdu[0], byte(count * 2)}
+		for i := 0; i < count; i++ {
+			resp = binary.BigEndian.AppendUint16(resp, s.holding[address+i])
+		}
+		return resp
+	case FuncWriteMultipleRegisters:
+		if address+count > len(s.holding) {
+			return exception(2)
+		}
+		for i := 0; i < count; i++ {
+			s.holding[address+i] = binary.BigEndian.Uint16(pdu[6+i*2:])
+		}
+		return pdu[:5]
+	}
+	return exception(1)
+}
+
+func (s *simulator) serveTCP(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				for {
+					header := make([]byte, 7)
+					if _, err := io.ReadFull(conn, header); err != nil {
+						return
+					}
+					pdu := make([]byte, binary.BigEndian.Uint16(header[4:])-1)
+					if _, err := io.ReadFull(conn, pdu); err != nil {
+						return
+					}
+					resp := s.handle(pdu)
+					binary.BigEndian.PutUint16(header[4:], uint16(len(resp)+1))
+					if _, err := conn.Write(append(header, resp...)); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func (s *simulator) serveRTU(conn net.Conn) {
+	defer conn.Close()
+	for {
+		frame := make([]byte, 256)
+		n, err := conn.Read(frame)
+		if err != nil {
+			return
+		}
+		frame = frame[:n]
+		crc := crc16(frame[:n-2])
+		if frame[n-2] != byte(crc) || frame[n-1] != byte(crc>>8) {
+			continue
+		}
+		resp := append([]byte{frame[0]}, s.handle(frame[1:n-2])...)
+		crc = crc16(resp)
+		resp = append(resp, byte(crc), byte(crc>>8))
+		// the serial line delivers the frame in parts
+		for _, part := range [][]byte{resp[:2], resp[2:]} {
+			if _, err = conn.Write(part); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestClientTCP(t *testing.T) {
+	sim := &simulator{}
+	client := NewClient(NewTCPTransport(sim.serveTCP(t), time.Second))
+	defer client.Close()
+
+	require.NoError(t, client.WriteMultipleRegisters(1, 10, []uint16{1, 2, 3}))
+	values, err := client.ReadHoldingRegisters(1, 10, 3)
+	require.NoError(t, err)
+	require.Equal(t, []uint16{1, 2, 3}, values)
+
+	require.NoError(t, client.WriteSingleRegister(1, 11, 42))
+	result, err := client.Execute(1, "ReadHoldingRegisters", 11, 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, []uint16{42}, result)
+
+	require.NoError(t, client.WriteMultipleCoils(1, 3, []bool{true, false, true}))
+	require.NoError(t, client.WriteSingleCoil(1, 4, true))
+	bits, err := client.ReadCoils(1, 2, 4)
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, true, true, true}, bits)
+
+	result, err = client.Execute(1, "ReadWriteMultipleRegisters", 20, 2, []uint16{5, 6})
+	require.NoError(t, err)
+	require.Equal(t, []uint16{5, 6}, result)
+
+	result, err = client.Execute(1, "ReadCoils", 3, 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, []uint16{1, 1}, result)
+
+	_, err = client.ReadInputRegisters(1, 60, 10)
+	require.Equal(t, &Exception{Function: FuncReadInputRegisters, Code: 2}, err)
+
+	_, err = client.Execute(1, "Unknown", 0, 1, nil)
+	require.Error(t, err)
+}
+
+func TestClientTCPReconnect(t *testing.T) {
+	sim := &simulator{}
+	transport := NewTCPTransport(sim.serveTCP(t), time.Second)
+	client := NewClient(transport)
+	defer client.Close()
+
+	_, err := client.ReadHoldingRegisters(1, 0, 1)
+	require.NoError(t, err)
+
+	// the broken connection is reopened by the next request
+	_ = transport.conn.Close()
+	_, err = client.ReadHoldingRegisters(1, 0, 1)
+	require.Error(t, err)
+	_, err = client.ReadHoldingRegisters(1, 0, 1)
+	require.NoError(t, err)
+}
+
+func TestClientRTU(t *testing.T) {
+	sim := &simulator{}
+	open := func() (io.ReadWriteCloser, error) {
+		client, server := net.Pipe()
+		go sim.serveRTU(server)
+		return client, nil
+	}
+	client := NewClient(NewRTUTransport(open, time.Second, 0))
+	defer client.Close()
+
+	require.NoError(t, client.WriteSingleRegister(1, 5, 0x1234))
+	values, err := client.ReadHoldingRegisters(1, 5, 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint16{0x1234}, values)
+
+	require.NoError(t, client.WriteSingleCoil(1, 0, true))
+	bits, err := client.ReadCoils(1, 0, 2)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, false}, bits)
+
+	_, err = client.ReadHoldingRegisters(1, 63, 2)
+	require.Equal(t, &Exception{Function: FuncReadHoldingRegisters, Code: 2}, err)
+}
+
+func TestCRC16(t *testing.T) {
+	// read holding registers, slave 1, address 0, quantity 1
+	crc := crc16([]byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x01})
+	require.Equal(t, []byte{0x84, 0x0A}, []byte{byte(crc), byte(crc >> 8)})
+}
+
+func TestRegisters(t *testing.T) {
+	registers, err := ParseRegisters(`[
+		{"name": "temperature", "address": 0, "type": "int16", "scale": 0.1},
+		{"name": "energy", "address": 1, "type": "uint32", "word_order": "little"},
+		{"name": "power", "address": 3, "type": "float32", "function": 4},
+		{"name": "setpoint", "address": 10, "writable": true},
+		{"name": "relay", "address": 2, "function": 1, "writable": true},
+		{"name": "door", "address": 0, "function": 2}
+	]`)
+	require.NoError(t, err)
+	require.Equal(t, TypeUint16, registers[3].Type)
+	require.Equal(t, TypeBool, registers[4].Type)
+
+	_, err = ParseRegisters(`[{"name": "a", "function": 2, "type": "int16"}]`)
+	require.Error(t, err)
+	_, err = ParseRegisters(`[{"name": "a", "function": 4, "writable": true}]`)
+	require.Error(t, err)
+	_, err = ParseRegisters(`[{"name": "a"}, {"name": "a", "address": 1}]`)
+	require.Error(t, err)
+	_, err = ParseRegisters(`[{"name": "a", "type": "int8"}]`)
+	require.Error(t, err)
+
+	sim := &simulator{}
+	sim.holding[0] = uint16(0xFF38) // -200
+	sim.holding[1] = 0x5678
+	sim.holding[2] = 0x1234
+	sim.inputRegs[3] = 0x4048
+	sim.inputRegs[4] = 0xF5C3
+	sim.inputs[0] = true
+
+	device := &Device{
+		Client:    NewClient(NewTCPTransport(sim.serveTCP(t), time.Second)),
+		SlaveId:   1,
+		Registers: registers,
+	}
+	defer device.Client.Close()
+
+	values, err := device.Poll()
+	require.NoError(t, err)
+	require.InDelta(t, -20.0, values["temperature"], 0.0001)
+	require.Equal(t, int64(0x12345678), values["energy"])
+	require.InDelta(t, 3.14, values["power"], 0.0001)
+	require.Equal(t, int64(0), values["setpoint"])
+	require.Equal(t, false, values["relay"])
+	require.Equal(t, true, values["door"])
+
+	require.NoError(t, device.Write("setpoint", 215))
+	require.NoError(t, device.Write("relay", true))
+	require.Error(t, device.Write("temperature", 1))
+	require.Error(t, device.Write("setpoint", -1))
+	require.Error(t, device.Write("unknown", 1))
+
+	values, err = device.Poll()
+	require.NoError(t, err)
+	require.Equal(t, int64(215), values["setpoint"])
+	require.Equal(t, true, values["relay"])
+}
+
+func TestRegisterOrder(t *testing.T) {
+	r := Register{Name: "value", Type: TypeFloat32, Scale: 1, ByteOrder: OrderLittle, WordOrder: OrderLittle}
+	words, err := r.Encode(1.5)
+	require.NoError(t, err)
+	require.Equal(t, []uint16{0x0000, 0xC03F}, words)
+	require.Equal(t, 1.5, r.Decode(words))
+
+	r = Register{Name: "value", Type: TypeInt64, Scale: 1, ByteOrder: OrderBig, WordOrder: OrderBig}
+	words, err = r.Encode("-2")
+	require.NoError(t, err)
+	require.Equal(t, []uint16{0xFFFF, 0xFFFF, 0xFFFF, 0xFFFE}, words)
+	require.Equal(t, int64(-2), r.Decode(words))
+}
+
+func TestBlocks(t *testing.T) {
+	registers, err := ParseRegisters(`[
+		{"name": "a", "address": 0},
+		{"name": "b", "address": 1, "type": "uint32"},
+		{"name": "c", "address": 10},
+		{"name": "d", "address": 120, "type": "uint64"},
+		{"name": "e", "address": 122},
+		{"name": "f", "address": 0, "function": 4}
+	]`)
+	require.NoError(t, err)
+
+	result := blocks(registers)
+	require.Len(t, result, 4)
+	require.Equal(t, uint16(0), result[0].address)
+	require.Equal(t, uint16(3), result[0].count)
+	require.Equal(t, uint16(10), result[1].address)
+	require.Equal(t, uint16(120), result[2].address)
+	require.Equal(t, uint16(4), result[2].count)
+	require.Len(t, result[2].registers, 2)
+	require.Equal(t, FuncReadInputRegisters, result[3].function)
+}
+
+func TestDeviceRun(t *testing.T) {
+	sim := &simulator{}
+	sim.holding[0] = 7
+
+	registers, err := ParseRegisters(`[{"name": "value", "address": 0}]`)
+	require.NoError(t, err)
+	device := &Device{
+		Client:    NewClient(NewTCPTransport(sim.serveTCP(t), time.Second)),
+		SlaveId:   1,
+		Registers: registers,
+	}
+	defer device.Client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polled := make(chan map[string]interface{}, 10)
+	go device.Run(ctx, time.Millisecond*10, func(values map[string]interface{}, err error) {
+		if err != nil {
+			return
+		}
+		select {
+		case polled <- values:
+		default:
+		}
+	})
+
+	require.Equal(t, int64(7), (<-polled)["value"])
+	sim.Lock()
+	sim.holding[0] = 8
+	sim.Unlock()
+	require.Eventually(t, func() bool {
+		return (<-polled)["value"] == int64(8)
+	}, time.Second, time.Millisecond)
+}
+
+func TestRegisterRange(t *testing.T) {
+	r := Register{Name: "value", Type: TypeInt16, Scale: 1, ByteOrder: OrderBig, WordOrder: OrderBig}
+	_, err := r.Encode(math.MaxInt16)
+	require.NoError(t, err)
+	_, err = r.Encode(math.MaxInt16 + 1)
+	require.Error(t, err)
+
+	// float64(math.MaxInt64) is 1<<63, it overflows the register
+	r = Register{Name: "value", Type: TypeInt64, Scale: 1, ByteOrder: OrderBig, WordOrder: OrderBig}
+	_, err = r.Encode(float64(math.MaxInt64))
+	require.Error(t, err)
+	words, err := r.Encode(math.Nextafter(1<<63, 0))
+	require.NoError(t, err)
+	require.Equal(t, []uint16{0x7FFF, 0xFFFF, 0xFFFF, 0xFC00}, words)
+	words, err = r.Encode(float64(math.MinInt64))
+	require.NoError(t, err)
+	require.Equal(t, []uint16{0x8000, 0x0000, 0x0000, 0x0000}, words)
+
+	r = Register{Name: "value", Type: TypeUint64, Scale: 1, ByteOrder: OrderBig, WordOrder: OrderBig}
+	_, err = r.Encode(float64(math.MaxUint64))
+	require.Error(t, err)
+	words, err = r.Encode(math.Nextafter(1<<64, 0))
+	require.NoError(t, err)
+	require.Equal(t, []uint16{0xFFFF, 0xFFFF, 0xFFFF, 0xF800}, words)
+	_, err = r.Encode(-1)
+	require.Error(t, err)
+}
@@ -0,0 +1,382 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/e154/smart-home/pkg/common"
+)
+
+// DataType ...
+type DataType string
+
+const (
+	// TypeBool ...
+	TypeBool = DataType("bool")
+	// TypeInt16 ...
+	TypeInt16 = DataType("int16")
+	// TypeUint16 ...
+	TypeUint16 = DataType("uint16")
+	// TypeInt32 ...
+	TypeInt32 = DataType("int32")
+	// TypeUint32 ...
+	TypeUint32 = DataType("uint32")
+	// TypeFloat32 ...
+	TypeFloat32 = DataType("float32")
+	// TypeInt64 ...
+	TypeInt64 = DataType("int64")
+	// TypeUint64 ...
+	TypeUint64 = DataType("uint64")
+	// TypeFloat64 ...
+	TypeFloat64 = DataType("float64")
+)
+
+const (
+	// OrderBig ...
+	OrderBig = "big"
+	// OrderLittle ...
+	OrderLittle = "little"
+)
+
+// Register describes the value of the register map, read into the attribute with the same name
+type Register struct {
+	Name    string `json:"name"`
+	Address uint16 `json:"address"`
+	// Function is the read function code: 1 coils, 2 discrete inputs, 3 holding registers, 4 input registers
+	Function  byte     `json:"function"`
+	Type      DataType `json:"type"`
+	Scale     float64  `json:"scale"`
+	ByteOrder string   `json:"byte_order"`
+	WordOrder string   `json:"word_order"`
+	Writable  bool     `json:"writable"`
+}
+
+// ParseRegisters parses the json register map and fills the defaults
+func ParseRegisters(data string) (registers []Register, err error) {
+	if data == "" {
+		return
+	}
+	if err = json.Unmarshal([]byte(data), &registers); err != nil {
+		return nil, fmt.Errorf("registers: %w", err)
+	}
+	names := make(map[string]struct{}, len(registers))
+	for i := range registers {
+		r := &registers[i]
+		if r.Name == "" {
+			return nil, fmt.Errorf("registers: register %d has no name", i)
+		}
+		if _, ok := names[r.Name]; ok {
+			return nil, fmt.Errorf("registers: duplicate name %s", r.Name)
+		}
+		names[r.Name] = struct{}{}
+		if r.Function == 0 {
+			r.Function = FuncReadHoldingRegisters
+		}
+		if r.Function > FuncReadInputRegisters {
+			return nil, fmt.Errorf("registers: %s: bad function %d", r.Name, r.Function)
+		}
+		if r.Type == "" {
+			r.Type = TypeUint16
+			if r.isBit() {
+				r.Type = TypeBool
+			}
+		}
+		if r.Scale == 0 {
+			r.Scale = 1
+		}
+		if r.ByteOrder == "" {
+			r.ByteOrder = OrderBig
+		}
+		if r.WordOrder == "" {
+			r.WordOrder = OrderBig
+		}
+		switch {
+		case r.Count() == 0:
+			return nil, fmt.Errorf("registers: %s: bad type %s", r.Name, r.Type)
+		case r.isBit() && r.Type != TypeBool:
+			return nil, fmt.Errorf("registers: %s: type %s is not allowed for the bits", r.Name, r.Type)
+		case r.ByteOrder != OrderBig && r.ByteOrder != OrderLittle:
+			return nil, fmt.Errorf("registers: %s: bad byte order %s", r.Name, r.ByteOrder)
+		case r.WordOrder != OrderBig && r.WordOrder != OrderLittle:
+			return nil, fmt.Errorf("registers: %s: bad word order %s", r.Name, r.WordOrder)
+		case r.Writable && r.Function != FuncReadCoils && r.Function != FuncReadHoldingRegisters:
+			return nil, fmt.Errorf("registers: %s: only coils and holding registers are writable", r.Name)
+		case int(r.Address)+int(r.Count()) > math.MaxUint16+1:
+			return nil, fmt.Errorf("registers: %s: bad address %d", r.Name, r.Address)
+		}
+	}
+	return
+}
+
+func (r Register) isBit() bool {
+	return r.Function == FuncReadCoils || r.Function == FuncReadDiscreteInputs
+}
+
+// Count of the bits or registers occupied by the value
+func (r Register) Count() uint16 {
+	switch r.Type {
+	case TypeBool, TypeInt16, TypeUint16:
+		return 1
+	case TypeInt32, TypeUint32, TypeFloat32:
+		return 2
+	case TypeInt64, TypeUint64, TypeFloat64:
+		return 4
+	}
+	return 0
+}
+
+// AttributeType of the decoded value
+func (r Register) AttributeType() common.AttributeType {
+	switch {
+	case r.Type == TypeBool:
+		return common.AttributeBool
+	case r.Type == TypeFloat32 || r.Type == TypeFloat64 || r.Scale != 1:
+		return common.AttributeFloat
+	}
+	return common.AttributeInt
+}
+
+// Decode the registers to bool, int64 or float64, the scale is applied
+func (r Register) Decode(words []uint16) interface{} {
+	if r.Type == TypeBool {
+		return words[0] != 0
+	}
+	raw := r.bytes(words)
+	var value float64
+	switch r.Type {
+	case TypeInt16:
+		value = float64(int16(binary.BigEndian.Uint16(raw)))
+	case TypeUint16:
+		value = float64(binary.BigEndian.Uint16(raw))
+	case TypeInt32:
+		value = float64(int32(binary.BigEndian.Uint32(raw)))
+	case TypeUint32:
+		value = float64(binary.BigEndian.Uint32(raw))
+	case TypeFloat32:
+		value = float64(math.Float32frombits(binary.BigEndian.Uint32(raw)))
+	case TypeInt64:
+		if r.Scale == 1 {
+			return int64(binary.BigEndian.Uint64(raw))
+		}
+		value = float64(int64(binary.BigEndian.Uint64(raw)))
+	case TypeUint64:
+		value = float64(binary.BigEndian.Uint64(raw))
+	case TypeFloat64:
+		value = math.Float64frombits(binary.BigEndian.Uint64(raw))
+	}
+	if r.AttributeType() == common.AttributeFloat {
+		return value * r.Scale
+	}
+	return int64(value)
+}
+
+// Encode the value to the registers, the scale is removed
+func (r Register) Encode(value interface{}) ([]uint16, error) {
+	if r.Type == TypeBool {
+		b, err := toBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		if b {
+			return []uint16{1}, nil
+		}
+		return []uint16{0}, nil
+	}
+
+	v, err := toFloat(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", r.Name, err)
+	}
+	v /= r.Scale
+	if r.Type != TypeFloat32 && r.Type != TypeFloat64 {
+		v = math.Round(v)
+	}
+
+	var min, max float64
+	switch r.Type {
+	case TypeInt16:
+		min, max = math.MinInt16, math.MaxInt16
+	case TypeUint16:
+		min, max = 0, math.MaxUint16
+	case TypeInt32:
+		min, max = math.MinInt32, math.MaxInt32
+	case TypeUint32:
+		min, max = 0, math.MaxUint32
+	case TypeInt64:
+		min, max = math.MinInt64, 1<<63
+	case TypeUint64:
+		min, max = 0, 1<<64
+	case TypeFloat32:
+		min, max = -math.MaxFloat32, math.MaxFloat32
+	default:
+		min, max = -math.MaxFloat64, math.MaxFloat64
+	}
+	// math.MaxInt64 and math.MaxUint64 are not exact in float64, the upper bounds of the 64-bit types are exclusive
+	exclusive := r.Type == TypeInt64 || r.Type == TypeUint64
+	if v < min || v > max || exclusive && v == max || math.IsNaN(v) {
+		return nil, fmt.Errorf("%s: value %v is out of range", r.Name, value)
+	}
+
+	raw := make([]byte, r.Count()*2)
+	switch r.Type {
+	case TypeInt16:
+		binary.BigEndian.PutUint16(raw, uint16(int16(v)))
+	case TypeUint16:
+		binary.BigEndian.PutUint16(raw, uint16(v))
+	case TypeInt32:
+		binary.BigEndian.PutUint32(raw, uint32(int32(v)))
+	case TypeUint32:
+		binary.BigEndian.PutUint32(raw, uint32(v))
+	case TypeFloat32:
+		binary.BigEndian.PutUint32(raw, math.Float32bits(float32(v)))
+	case TypeInt64:
+		binary.BigEndian.PutUint64(raw, uint64(int64(v)))
+	case TypeUint64:
+		binary.BigEndian.PutUint64(raw, uint64(v))
+	case TypeFloat64:
+		binary.BigEndian.PutUint64(raw, math.Float64bits(v))
+	}
+	return r.words(raw), nil
+}
+
+// bytes of the value in the big endian order
+func (r Register) bytes(words []uint16) []byte {
+	raw := make([]byte, 0, len(words)*2)
+	for i := range words {
+		word := words[i]
+		if r.WordOrder == OrderLittle {
+			word = words[len(words)-1-i]
+		}
+		if r.ByteOrder == OrderLittle {
+			raw = binary.LittleEndian.AppendUint16(raw, word)
+		} else {
+			raw = binary.BigEndian.AppendUint16(raw, word)
+		}
+	}
+	return raw
+}
+
+// words is the reverse of bytes
+func (r Register) words(raw []byte) []uint16 {
+	words := make([]uint16, len(raw)/2)
+	for i := range words {
+		word := binary.BigEndian.Uint16(raw[i*2:])
+		if r.ByteOrder == OrderLittle {
+			word = binary.LittleEndian.Uint16(raw[i*2:])
+		}
+		if r.WordOrder == OrderLittle {
+			words[len(words)-1-i] = word
+		} else {
+			words[i] = word
+		}
+	}
+	return words
+}
+
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	}
+	f, err := toFloat(value)
+	return f != 0, err
+}
+
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+	return 0, fmt.Errorf("unsupported value %v", value)
+}
+
+// block is the range of the registers read by one request
+type block struct {
+	function  byte
+	address   uint16
+	count     uint16
+	registers []Register
+}
+
+// blocks groups the registers of the same function into the contiguous reads
+func blocks(registers []Register) (result []*block) {
+	sorted := make([]Register, len(registers))
+	copy(sorted, registers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Function != sorted[j].Function {
+			return sorted[i].Function < sorted[j].Function
+		}
+		return sorted[i].Address < sorted[j].Address
+	})
+
+	var current *block
+	for _, r := range sorted {
+		limit := uint32(maxReadRegisters)
+		if r.isBit() {
+			limit = maxReadBits
+		}
+		end := uint32(r.Address) + uint32(r.Count())
+		if current != nil && current.function == r.Function &&
+			uint32(r.Address) <= uint32(current.address)+uint32(current.count) &&
+			end-uint32(current.address) <= limit {
+			if end > uint32(current.address)+uint32(current.count) {
+				current.count = uint16(end - uint32(current.address))
+			}
+			current.registers = append(current.registers, r)
+			continue
+		}
+		current = &block{
+			function:  r.Function,
+			address:   r.Address,
+			count:     r.Count(),
+			registers: []Register{r},
+		}
+		result = append(result, current)
+	}
+	return
+}
@@ -0,0 +1,139 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const rtuMaxLength = 256
+
+// RTUTransport is the modbus rtu transport over the serial line
+type RTUTransport struct {
+	open    func() (io.ReadWriteCloser, error)
+	timeout time.Duration
+	delay   time.Duration
+	port    io.ReadWriteCloser
+}
+
+// NewRTUTransport, the delay is the pause between the frames
+func NewRTUTransport(open func() (io.ReadWriteCloser, error), timeout, delay time.Duration) *RTUTransport {
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	return &RTUTransport{
+		open:    open,
+		timeout: timeout,
+		delay:   delay,
+	}
+}
+
+// Send ...
+func (t *RTUTransport) Send(slaveId byte, pdu []byte) (resp []byte, err error) {
+	if t.port == nil {
+		if t.port, err = t.open(); err != nil {
+			t.port = nil
+			return
+		}
+	}
+	if t.delay > 0 {
+		time.Sleep(t.delay)
+	}
+	if resp, err = t.send(slaveId, pdu); err != nil {
+		_ = t.Close()
+	}
+	return
+}
+
+func (t *RTUTransport) send(slaveId byte, pdu []byte) ([]byte, error) {
+	adu := append([]byte{slaveId}, pdu...)
+	crc := crc16(adu)
+	adu = append(adu, byte(crc), byte(crc>>8))
+
+	if _, err := t.port.Write(adu); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(t.timeout)
+	frame := make([]byte, 0, rtuMaxLength)
+	length := 5
+	for len(frame) < length {
+		if time.Now().After(deadline) {
+			return nil, errors.New("modbus rtu: timeout")
+		}
+		buf := make([]byte, length-len(frame))
+		n, err := t.port.Read(buf)
+		frame = append(frame, buf[:n]...)
+		if err != nil && !(errors.Is(err, io.EOF) && n > 0) {
+			return nil, err
+		}
+		if len(frame) >= 3 {
+			length = responseLength(frame)
+		}
+	}
+
+	if frame[0] != slaveId {
+		return nil, fmt.Errorf("modbus rtu: unexpected slave id %d", frame[0])
+	}
+	crc = crc16(frame[:length-2])
+	if frame[length-2] != byte(crc) || frame[length-1] != byte(crc>>8) {
+		return nil, errors.New("modbus rtu: bad crc")
+	}
+	return frame[1 : length-2], nil
+}
+
+// Close ...
+func (t *RTUTransport) Close() (err error) {
+	if t.port != nil {
+		err = t.port.Close()
+		t.port = nil
+	}
+	return
+}
+
+// responseLength of the frame by the function code and the byte count
+func responseLength(frame []byte) int {
+	function := frame[1]
+	switch {
+	case function&0x80 != 0:
+		return 5
+	case function <= FuncReadInputRegisters || function == FuncReadWriteMultipleRegs:
+		return 3 + int(frame[2]) + 2
+	default:
+		return 8
+	}
+}
+
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,52 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import "strings"
+
+// SerialConfig ...
+type SerialConfig struct {
+	Device   string
+	BaudRate int
+	DataBits int
+	StopBits int
+	// Parity is N, E, O or none, even, odd
+	Parity string
+}
+
+func (c SerialConfig) withDefaults() SerialConfig {
+	if c.BaudRate == 0 {
+		c.BaudRate = 19200
+	}
+	if c.DataBits == 0 {
+		c.DataBits = 8
+	}
+	if c.StopBits == 0 {
+		c.StopBits = 1
+	}
+	switch strings.ToLower(c.Parity) {
+	case "", "n", "none":
+		c.Parity = "N"
+	case "e", "even":
+		c.Parity = "E"
+	case "o", "odd":
+		c.Parity = "O"
+	}
+	return c
+}
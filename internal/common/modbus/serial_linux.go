@@ -0,0 +1,106 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+//go:build linux
+// +build linux
+
+package modbus
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+var baudRates = map[int]uint32{
+	1200:   unix.B1200,
+	2400:   unix.B2400,
+	4800:   unix.B4800,
+	9600:   unix.B9600,
+	19200:  unix.B19200,
+	38400:  unix.B38400,
+	57600:  unix.B57600,
+	115200: unix.B115200,
+	230400: unix.B230400,
+}
+
+// OpenSerial opens the serial device in the raw mode
+func OpenSerial(config SerialConfig) (io.ReadWriteCloser, error) {
+	config = config.withDefaults()
+
+	baud, ok := baudRates[config.BaudRate]
+	if !ok {
+		return nil, fmt.Errorf("unsupported baud rate %d", config.BaudRate)
+	}
+
+	var cflag uint32 = unix.CREAD | unix.CLOCAL | baud
+	switch config.DataBits {
+	case 5:
+		cflag |= unix.CS5
+	case 6:
+		cflag |= unix.CS6
+	case 7:
+		cflag |= unix.CS7
+	case 8:
+		cflag |= unix.CS8
+	default:
+		return nil, fmt.Errorf("unsupported data bits %d", config.DataBits)
+	}
+	switch config.StopBits {
+	case 1:
+	case 2:
+		cflag |= unix.CSTOPB
+	default:
+		return nil, fmt.Errorf("unsupported stop bits %d", config.StopBits)
+	}
+	switch config.Parity {
+	case "N":
+	case "E":
+		cflag |= unix.PARENB
+	case "O":
+		cflag |= unix.PARENB | unix.PARODD
+	default:
+		return nil, fmt.Errorf("unsupported parity %s", config.Parity)
+	}
+
+	file, err := os.OpenFile(config.Device, unix.O_RDWR|unix.O_NOCTTY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// the read returns after 0.1s without the data, the transport checks the timeout itself
+	termios := &unix.Termios{
+		Cflag:  cflag,
+		Ispeed: baud,
+		Ospeed: baud,
+	}
+	termios.Cc[unix.VMIN] = 0
+	termios.Cc[unix.VTIME] = 1
+
+	fd := int(file.Fd())
+	if err = unix.IoctlSetTermios(fd, unix.TCSETS, termios); err == nil {
+		err = unix.SetNonblock(fd, false)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return file, nil
+}
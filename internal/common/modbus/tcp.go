@@ -0,0 +1,111 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+const tcpMaxLength = 260
+
+// TCPTransport is the modbus tcp transport, the connection is reopened after an error
+type TCPTransport struct {
+	address       string
+	timeout       time.Duration
+	conn          net.Conn
+	transactionId uint16
+}
+
+// NewTCPTransport ...
+func NewTCPTransport(address string, timeout time.Duration) *TCPTransport {
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	return &TCPTransport{
+		address: address,
+		timeout: timeout,
+	}
+}
+
+// Send ...
+func (t *TCPTransport) Send(slaveId byte, pdu []byte) (resp []byte, err error) {
+	if t.conn == nil {
+		if t.conn, err = net.DialTimeout("tcp", t.address, t.timeout); err != nil {
+			t.conn = nil
+			return
+		}
+	}
+	if resp, err = t.send(slaveId, pdu); err != nil {
+		_ = t.Close()
+	}
+	return
+}
+
+func (t *TCPTransport) send(slaveId byte, pdu []byte) ([]byte, error) {
+	t.transactionId++
+
+	adu := make([]byte, 7, 7+len(pdu))
+	binary.BigEndian.PutUint16(adu[0:], t.transactionId)
+	binary.BigEndian.PutUint16(adu[4:], uint16(len(pdu)+1))
+	adu[6] = slaveId
+	adu = append(adu, pdu...)
+
+	if err := t.conn.SetDeadline(time.Now().Add(t.timeout)); err != nil {
+		return nil, err
+	}
+	if _, err := t.conn.Write(adu); err != nil {
+		return nil, err
+	}
+
+	for {
+		header := make([]byte, 7)
+		if _, err := io.ReadFull(t.conn, header); err != nil {
+			return nil, err
+		}
+		length := int(binary.BigEndian.Uint16(header[4:]))
+		if length < 2 || length+6 > tcpMaxLength {
+			return nil, fmt.Errorf("modbus tcp: bad length %d", length)
+		}
+		body := make([]byte, length-1)
+		if _, err := io.ReadFull(t.conn, body); err != nil {
+			return nil, err
+		}
+		// skip the late answers of the timed out requests
+		if binary.BigEndian.Uint16(header[0:]) != t.transactionId {
+			continue
+		}
+		if header[6] != slaveId {
+			return nil, fmt.Errorf("modbus tcp: unexpected unit id %d", header[6])
+		}
+		return body, nil
+	}
+}
+
+// Close ...
+func (t *TCPTransport) Close() (err error) {
+	if t.conn != nil {
+		err = t.conn.Close()
+		t.conn = nil
+	}
+	return
+}
@@ -0,0 +1,73 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCode(t *testing.T) {
+
+	// the test vectors of RFC 6238 (SHA1), the last 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for ts, expected := range vectors {
+		code, err := Code(secret, time.Unix(ts, 0))
+		require.NoError(t, err)
+		require.Equal(t, expected, code, ts)
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	secret := NewSecret()
+	now := time.Now()
+
+	code, err := Code(secret, now)
+	require.NoError(t, err)
+	require.True(t, Validate(secret, code, now))
+
+	// the neighbour period is accepted
+	require.True(t, Validate(secret, code, now.Add(Period*time.Second)))
+	require.False(t, Validate(secret, code, now.Add(3*Period*time.Second)))
+
+	counter, ok := Counter(secret, code, now.Add(Period*time.Second))
+	require.True(t, ok)
+	require.Equal(t, now.Unix()/Period, counter)
+
+	require.False(t, Validate(secret, "", now))
+	require.False(t, Validate(secret, "12345", now))
+	require.False(t, Validate("not base32!", code, now))
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Smart home", "user@mail.com", "JBSWY3DPEHPK3PXP")
+	require.Equal(t, "otpauth://totp/Smart%20home:user@mail.com?digits=6&issuer=Smart+home&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
@@ -0,0 +1,128 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ApiTokens ...
+type ApiTokens struct {
+	*Common
+}
+
+// ApiToken ...
+type ApiToken struct {
+	Id         int64 `gorm:"primary_key"`
+	UserId     int64
+	Name       string
+	Token      string
+	Scopes     json.RawMessage `gorm:"type:jsonb;not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time `gorm:"<-:create"`
+}
+
+// TableName ...
+func (d *ApiToken) TableName() string {
+	return "api_tokens"
+}
+
+// Add ...
+func (n ApiTokens) Add(ctx context.Context, token *ApiToken) (id int64, err error) {
+	if err = n.DB(ctx).Create(&token).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			err = fmt.Errorf("%s: %w", fmt.Sprintf("name \"%s\"", token.Name), apperr.ErrApiTokenExists)
+			return
+		}
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrApiTokenAdd)
+		return
+	}
+	id = token.Id
+	return
+}
+
+// GetById ...
+func (n ApiTokens) GetById(ctx context.Context, id int64) (token *ApiToken, err error) {
+	token = &ApiToken{}
+	if err = n.DB(ctx).Model(token).Where("id = ?", id).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("%s: %w", fmt.Sprintf("id \"%d\"", id), apperr.ErrApiTokenNotFound)
+			return
+		}
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrApiTokenGet)
+	}
+	return
+}
+
+// GetByToken ...
+func (n ApiTokens) GetByToken(ctx context.Context, hash string) (token *ApiToken, err error) {
+	token = &ApiToken{}
+	if err = n.DB(ctx).Model(token).Where("token = ?", hash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("%s: %w", "token", apperr.ErrApiTokenNotFound)
+			return
+		}
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrApiTokenGet)
+	}
+	return
+}
+
+// List ...
+func (n ApiTokens) List(ctx context.Context, userId int64) (list []*ApiToken, err error) {
+	list = make([]*ApiToken, 0)
+	err = n.DB(ctx).Model(&ApiToken{}).
+		Where("user_id = ?", userId).
+		Order("id").
+		Find(&list).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrApiTokenList)
+	}
+	return
+}
+
+// Touch ...
+func (n ApiTokens) Touch(ctx context.Context, id int64, lastUsed time.Time) (err error) {
+	err = n.DB(ctx).Model(&ApiToken{Id: id}).
+		Update("last_used_at", lastUsed).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrApiTokenUpdate)
+	}
+	return
+}
+
+// Delete ...
+func (n ApiTokens) Delete(ctx context.Context, id int64) (err error) {
+	if err = n.DB(ctx).Delete(&ApiToken{}, "id = ?", id).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrApiTokenDelete)
+	}
+	return
+}
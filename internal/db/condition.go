@@ -20,6 +20,7 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -39,6 +40,7 @@ type Condition struct {
 	Name        string
 	Script      *Script
 	ScriptId    *int64
+	Rule        json.RawMessage `gorm:"type:jsonb"`
 	AreaId      *int64
 	Area        *Area
 	Description string
@@ -87,6 +89,7 @@ func (t Conditions) Update(ctx context.Context, m *Condition) (err error) {
 		"name":        m.Name,
 		"description": m.Description,
 		"script_id":   m.ScriptId,
+		"rule":        m.Rule,
 		"area_id":     m.AreaId,
 	}
 
@@ -0,0 +1,127 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	pkgCommon "github.com/e154/smart-home/pkg/common"
+	"gorm.io/gorm"
+)
+
+// EntityPermissions ...
+type EntityPermissions struct {
+	*Common
+}
+
+// EntityPermission ...
+type EntityPermission struct {
+	Id        int64 `gorm:"primary_key"`
+	RoleName  string
+	EntityId  *pkgCommon.EntityId
+	AreaId    *int64
+	Tag       *string
+	Level     pkgCommon.EntityAccessLevel
+	CreatedAt time.Time `gorm:"<-:create"`
+}
+
+// TableName ...
+func (d *EntityPermission) TableName() string {
+	return "entity_permissions"
+}
+
+// Add ...
+func (n EntityPermissions) Add(ctx context.Context, permission *EntityPermission) (id int64, err error) {
+	if err = n.DB(ctx).Create(&permission).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrEntityPermissionAdd)
+		return
+	}
+	id = permission.Id
+	return
+}
+
+// GetById ...
+func (n EntityPermissions) GetById(ctx context.Context, id int64) (permission *EntityPermission, err error) {
+	permission = &EntityPermission{}
+	if err = n.DB(ctx).Model(permission).Where("id = ?", id).First(&permission).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("%s: %w", fmt.Sprintf("id \"%d\"", id), apperr.ErrEntityPermissionNotFound)
+			return
+		}
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrEntityPermissionGet)
+	}
+	return
+}
+
+// Delete ...
+func (n EntityPermissions) Delete(ctx context.Context, id int64) (err error) {
+	if err = n.DB(ctx).Delete(&EntityPermission{}, "id = ?", id).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrEntityPermissionDelete)
+	}
+	return
+}
+
+// List returns the permissions of the role only
+func (n EntityPermissions) List(ctx context.Context, roleName string) (list []*EntityPermission, err error) {
+	list = make([]*EntityPermission, 0)
+	err = n.DB(ctx).Model(&EntityPermission{}).
+		Where("role_name = ?", roleName).
+		Order("id").
+		Find(&list).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrEntityPermissionList)
+	}
+	return
+}
+
+// GetAllPermissions returns the permissions of the role and its parents, the own permissions go first
+func (n EntityPermissions) GetAllPermissions(ctx context.Context, roleName string) (list []*EntityPermission, err error) {
+
+	list = make([]*EntityPermission, 0)
+	err = n.DB(ctx).Raw(`
+WITH RECURSIVE r AS (
+    SELECT name, parent, 1 AS level
+    FROM roles
+    WHERE name = ?
+
+        UNION
+
+        SELECT roles.name, roles.parent, r.level + 1 AS level
+        FROM roles
+               JOIN r
+                 ON roles.name = r.parent
+    )
+
+SELECT p.*
+FROM r
+join entity_permissions p on p.role_name = r.name
+order by r.level, p.id;
+`, roleName).
+		Scan(&list).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrEntityPermissionList)
+	}
+	return
+}
@@ -50,6 +50,13 @@ func (d *EntityStorage) TableName() string {
 	return "entity_storage"
 }
 
+// EntityStorageSample ...
+type EntityStorageSample struct {
+	EntityId  pkgCommon.EntityId
+	CreatedAt time.Time
+	Value     *string
+}
+
 // Add ...
 func (n *EntityStorages) Add(ctx context.Context, v *EntityStorage) (id int64, err error) {
 	if err = n.DB(ctx).Create(&v).Error; err != nil {
@@ -149,6 +156,39 @@ func (n *EntityStorages) List(ctx context.Context, limit, offset int, orderBy, s
 	return
 }
 
+// Samples returns the state or the attribute at the path for every stored row in the range,
+// preceded by the last row before the range, ordered by entity and time
+func (n *EntityStorages) Samples(ctx context.Context, entityIds []pkgCommon.EntityId, path []string, from, to time.Time) (list []*EntityStorageSample, err error) {
+
+	value := "state"
+	var args []interface{}
+	if len(path) > 0 {
+		value = "attributes #>> CAST(? AS text[])"
+		args = append(args, fmt.Sprintf("{%s}", strings.Join(path, ",")))
+	}
+
+	q := fmt.Sprintf(`(SELECT DISTINCT ON (entity_id) entity_id, created_at, %[1]s AS value
+FROM entity_storage
+WHERE entity_id IN (?) AND created_at < ?
+ORDER BY entity_id, created_at DESC)
+UNION ALL
+(SELECT entity_id, created_at, %[1]s AS value
+FROM entity_storage
+WHERE entity_id IN (?) AND created_at >= ? AND created_at < ?)
+ORDER BY entity_id, created_at`, value)
+
+	params := append([]interface{}{}, args...)
+	params = append(params, entityIds, from.UTC())
+	params = append(params, args...)
+	params = append(params, entityIds, from.UTC(), to.UTC())
+
+	list = make([]*EntityStorageSample, 0)
+	if err = n.DB(ctx).Raw(q, params...).Scan(&list).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrEntityStorageList)
+	}
+	return
+}
+
 // DeleteOldest ...
 func (n *EntityStorages) DeleteOldest(ctx context.Context, days int) (err error) {
 	storage := &EntityStorage{}
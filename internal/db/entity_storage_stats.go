@@ -0,0 +1,121 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	pkgCommon "github.com/e154/smart-home/pkg/common"
+)
+
+// EntityStorageStats ...
+type EntityStorageStats struct {
+	*Common
+}
+
+// EntityStorageStat ...
+type EntityStorageStat struct {
+	EntityId   pkgCommon.EntityId
+	Resolution string
+	Name       string
+	Time       time.Time
+	Min        float64
+	Max        float64
+	Mean       float64
+	Sum        float64
+	Count      int64
+}
+
+// TableName ...
+func (d *EntityStorageStat) TableName() string {
+	return "entity_storage_stats"
+}
+
+// Rollup folds the numeric attributes of the raw entity storage into hourly statistics
+// and the hourly statistics into daily ones, each entity starts from its own last stored period.
+func (n EntityStorageStats) Rollup(ctx context.Context) (err error) {
+
+	const hourly = `WITH w AS (SELECT entity_id, max(time) AS time FROM entity_storage_stats WHERE resolution = 'hour' GROUP BY entity_id)
+INSERT INTO entity_storage_stats (entity_id, resolution, name, time, min, max, mean, sum, count)
+SELECT c.entity_id, 'hour', kv.key, date_trunc('hour', c.created_at) AS bucket,
+       min((kv.value #>> '{}')::float8), max((kv.value #>> '{}')::float8),
+       avg((kv.value #>> '{}')::float8), sum((kv.value #>> '{}')::float8), count(*)
+FROM entity_storage c
+LEFT JOIN w ON w.entity_id = c.entity_id
+CROSS JOIN LATERAL jsonb_each(c.attributes) kv
+WHERE c.entity_id IS NOT NULL
+  AND jsonb_typeof(kv.value) = 'number'
+  AND c.created_at >= COALESCE(w.time, '-infinity'::timestamptz)
+GROUP BY c.entity_id, kv.key, bucket
+ON CONFLICT (entity_id, resolution, name, time) DO UPDATE
+SET min = excluded.min, max = excluded.max, mean = excluded.mean, sum = excluded.sum, count = excluded.count`
+
+	const daily = `WITH w AS (SELECT entity_id, max(time) AS time FROM entity_storage_stats WHERE resolution = 'day' GROUP BY entity_id)
+INSERT INTO entity_storage_stats (entity_id, resolution, name, time, min, max, mean, sum, count)
+SELECT c.entity_id, 'day', c.name, date_trunc('day', c.time) AS bucket,
+       min(c.min), max(c.max), sum(c.sum) / sum(c.count), sum(c.sum), sum(c.count)
+FROM entity_storage_stats c
+LEFT JOIN w ON w.entity_id = c.entity_id
+WHERE c.resolution = 'hour'
+  AND c.time >= COALESCE(w.time, '-infinity'::timestamptz)
+GROUP BY c.entity_id, c.name, bucket
+ON CONFLICT (entity_id, resolution, name, time) DO UPDATE
+SET min = excluded.min, max = excluded.max, mean = excluded.mean, sum = excluded.sum, count = excluded.count`
+
+	for _, q := range []string{hourly, daily} {
+		if err = n.DB(ctx).Exec(q).Error; err != nil {
+			err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrEntityStorageAdd)
+			return
+		}
+	}
+	return
+}
+
+// List ...
+func (n EntityStorageStats) List(ctx context.Context, entityId pkgCommon.EntityId, resolution string, names []string, from, to time.Time) (list []*EntityStorageStat, err error) {
+
+	list = make([]*EntityStorageStat, 0)
+
+	q := n.DB(ctx).Model(&EntityStorageStat{}).
+		Where("entity_id = ? and resolution = ?", entityId, resolution).
+		Where("time >= date_trunc(?, ?::timestamptz) and time < ?", resolution, from.UTC(), to.UTC())
+
+	if len(names) > 0 {
+		q = q.Where("name in (?)", names)
+	}
+
+	if err = q.Order("time asc, name asc").Find(&list).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrEntityStorageList)
+	}
+	return
+}
+
+// DeleteOldest ...
+func (n EntityStorageStats) DeleteOldest(ctx context.Context, resolution string, days int) (err error) {
+	if days <= 0 {
+		return
+	}
+	err = n.DB(ctx).Delete(&EntityStorageStat{}, "resolution = ? and time < ?", resolution, time.Now().AddDate(0, 0, -days)).Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrEntityStorageDelete)
+	}
+	return
+}
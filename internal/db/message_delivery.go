@@ -20,6 +20,7 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -42,9 +43,13 @@ type MessageDelivery struct {
 	Address            string
 	EntityId           *pkgCommon.EntityId
 	Status             string
-	ErrorMessageStatus *string   `gorm:"column:error_system_code"`
-	ErrorMessageBody   *string   `gorm:"column:error_system_message"`
-	CreatedAt          time.Time `gorm:"<-:create"`
+	ErrorMessageStatus *string `gorm:"column:error_system_code"`
+	ErrorMessageBody   *string `gorm:"column:error_system_message"`
+	Attempts           int
+	NextAttemptAt      *time.Time
+	AttemptLog         json.RawMessage `gorm:"type:jsonb;not null"`
+	Fallback           json.RawMessage `gorm:"type:jsonb;not null"`
+	CreatedAt          time.Time       `gorm:"<-:create"`
 	UpdatedAt          time.Time
 }
 
@@ -133,6 +138,9 @@ func (n *MessageDeliveries) SetStatus(ctx context.Context, msg *MessageDelivery)
 			"status":               msg.Status,
 			"error_system_code":    msg.ErrorMessageStatus,
 			"error_system_message": msg.ErrorMessageBody,
+			"attempts":             msg.Attempts,
+			"next_attempt_at":      msg.NextAttemptAt,
+			"attempt_log":          msg.AttemptLog,
 		}).Error
 	if err != nil {
 		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrMessageDeliveryUpdate)
@@ -140,6 +148,34 @@ func (n *MessageDeliveries) SetStatus(ctx context.Context, msg *MessageDelivery)
 	return
 }
 
+// GetQueued the uncompleted deliveries of the provider whose attempt is due, the entity id is empty for the plugin providers
+func (n *MessageDeliveries) GetQueued(ctx context.Context, messageType string, entityId *pkgCommon.EntityId, now time.Time, limit int) (list []*MessageDelivery, err error) {
+
+	list = make([]*MessageDelivery, 0)
+	q := n.DB(ctx).Model(&MessageDelivery{}).
+		Joins(`left join messages on messages.id = message_deliveries.message_id`).
+		Where("message_deliveries.status in ('in_progress', 'new')").
+		Where("(message_deliveries.next_attempt_at isnull or message_deliveries.next_attempt_at <= ?)", now).
+		Where("messages.type = ?", messageType)
+
+	if entityId != nil {
+		q = q.Where("message_deliveries.entity_id = ?", entityId)
+	} else {
+		q = q.Where("message_deliveries.entity_id isnull")
+	}
+
+	err = q.
+		Order("message_deliveries.id asc").
+		Limit(limit).
+		Preload("Message").
+		Find(&list).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrMessageDeliveryList)
+	}
+	return
+}
+
 // Delete ...
 func (n *MessageDeliveries) Delete(ctx context.Context, id int64) (err error) {
 	if err = n.DB(ctx).Delete(&MessageDelivery{Id: id}).Error; err != nil {
@@ -92,6 +92,8 @@ func (n *MetricBuckets) List(ctx context.Context, metricId int64, optionItems []
 		case pkgCommon.MetricRange30d, pkgCommon.MetricRange1m:
 			interval = "7 minutes"
 			from = to.Add(-24 * 30 * time.Hour)
+		case pkgCommon.MetricRange1y:
+			from = to.AddDate(-1, 0, 0)
 		default:
 			err = fmt.Errorf("%s: %w", fmt.Sprintf("unknown filter %s", metricRange), apperr.ErrMetricBucketGet)
 			return
@@ -112,6 +114,23 @@ func (n *MetricBuckets) List(ctx context.Context, metricId int64, optionItems []
 		interval = fmt.Sprintf("%d seconds", num)
 	}
 
+	stats := MetricBucketStats{Common: n.Common}
+
+	// long ranges are served from the rollups
+	if resolution := pkgCommon.StatsResolutionByRange(from, to); resolution != pkgCommon.StatsResolutionRaw {
+		return stats.List(ctx, metricId, resolution.String(), optionItems, from, to)
+	}
+
+	if list, err = n.listRaw(ctx, metricId, str, interval, num, from, to); err != nil || len(list) > 0 {
+		return
+	}
+
+	// raw rows may already be removed by the retention policy
+	return stats.List(ctx, metricId, pkgCommon.StatsResolutionHour.String(), optionItems, from, to)
+}
+
+func (n *MetricBuckets) listRaw(ctx context.Context, metricId int64, str, interval string, num int64, from, to time.Time) (list []*MetricBucket, err error) {
+
 	list = make([]*MetricBucket, 0)
 
 	// c.time between ? and ?
@@ -147,6 +166,31 @@ LIMIT 3600`
 	return
 }
 
+// GetLast returns the latest bucket of each metric
+func (n *MetricBuckets) GetLast(ctx context.Context, metricIds []int64) (list []*MetricBucket, err error) {
+
+	list = make([]*MetricBucket, 0)
+
+	if len(metricIds) == 0 {
+		return
+	}
+
+	q := `SELECT b.metric_id, b.time, b.value
+FROM metrics m
+CROSS JOIN LATERAL (
+    SELECT c.metric_id, c.time, c.value
+    FROM metric_bucket c
+    WHERE c.metric_id = m.id
+    ORDER BY c.time DESC
+    LIMIT 1
+) b
+WHERE m.id in (?)`
+	if err = n.DB(ctx).Raw(q, metricIds).Scan(&list).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrMetricBucketGet)
+	}
+	return
+}
+
 // DeleteOldest ...
 func (n *MetricBuckets) DeleteOldest(ctx context.Context, days int) (err error) {
 	bucket := &MetricBucket{}
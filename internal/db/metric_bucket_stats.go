@@ -0,0 +1,130 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+)
+
+// MetricBucketStats ...
+type MetricBucketStats struct {
+	*Common
+}
+
+// MetricBucketStat ...
+type MetricBucketStat struct {
+	MetricId   int64
+	Resolution string
+	Name       string
+	Time       time.Time
+	Min        float64
+	Max        float64
+	Mean       float64
+	Sum        float64
+	Count      int64
+}
+
+// TableName ...
+func (d *MetricBucketStat) TableName() string {
+	return "metric_bucket_stats"
+}
+
+// Rollup folds the raw buckets into hourly statistics and the hourly statistics into daily ones.
+// Each pass starts from the last (possibly incomplete) period already stored for the metric, so it is
+// cheap to run often, catches up automatically after a downtime and picks up the new metrics from the start.
+func (n MetricBucketStats) Rollup(ctx context.Context) (err error) {
+
+	const hourly = `WITH w AS (SELECT metric_id, max(time) AS time FROM metric_bucket_stats WHERE resolution = 'hour' GROUP BY metric_id)
+INSERT INTO metric_bucket_stats (metric_id, resolution, name, time, min, max, mean, sum, count)
+SELECT c.metric_id, 'hour', kv.key, date_trunc('hour', c.time) AS bucket,
+       min((kv.value #>> '{}')::float8), max((kv.value #>> '{}')::float8),
+       avg((kv.value #>> '{}')::float8), sum((kv.value #>> '{}')::float8), count(*)
+FROM metric_bucket c
+LEFT JOIN w ON w.metric_id = c.metric_id
+CROSS JOIN LATERAL jsonb_each(c.value) kv
+WHERE jsonb_typeof(kv.value) = 'number'
+  AND c.time >= COALESCE(w.time, '-infinity'::timestamptz)
+GROUP BY c.metric_id, kv.key, bucket
+ON CONFLICT (metric_id, resolution, name, time) DO UPDATE
+SET min = excluded.min, max = excluded.max, mean = excluded.mean, sum = excluded.sum, count = excluded.count`
+
+	const daily = `WITH w AS (SELECT metric_id, max(time) AS time FROM metric_bucket_stats WHERE resolution = 'day' GROUP BY metric_id)
+INSERT INTO metric_bucket_stats (metric_id, resolution, name, time, min, max, mean, sum, count)
+SELECT c.metric_id, 'day', c.name, date_trunc('day', c.time) AS bucket,
+       min(c.min), max(c.max), sum(c.sum) / sum(c.count), sum(c.sum), sum(c.count)
+FROM metric_bucket_stats c
+LEFT JOIN w ON w.metric_id = c.metric_id
+WHERE c.resolution = 'hour'
+  AND c.time >= COALESCE(w.time, '-infinity'::timestamptz)
+GROUP BY c.metric_id, c.name, bucket
+ON CONFLICT (metric_id, resolution, name, time) DO UPDATE
+SET min = excluded.min, max = excluded.max, mean = excluded.mean, sum = excluded.sum, count = excluded.count`
+
+	for _, q := range []string{hourly, daily} {
+		if err = n.DB(ctx).Exec(q).Error; err != nil {
+			err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrMetricBucketAdd)
+			return
+		}
+	}
+	return
+}
+
+// List returns the mean value of each item per period
+func (n MetricBucketStats) List(ctx context.Context, metricId int64, resolution string, optionItems []string, from, to time.Time) (list []*MetricBucket, err error) {
+
+	list = make([]*MetricBucket, 0)
+
+	if len(optionItems) == 0 {
+		return
+	}
+
+	q := `SELECT c.time, json_object_agg(c.name, trunc(c.mean::numeric, 2)) AS value
+FROM metric_bucket_stats c
+WHERE c.metric_id = ? and c.resolution = ? and c.name in (?) and c.time >= date_trunc(?, ?::timestamptz) and c.time < ?
+GROUP BY c.time
+ORDER BY c.time ASC
+LIMIT 3600`
+	if err = n.DB(ctx).Raw(q, metricId, resolution, optionItems, resolution, from.UTC(), to.UTC()).Scan(&list).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrMetricBucketGet)
+	}
+	return
+}
+
+// DeleteOldest ...
+func (n MetricBucketStats) DeleteOldest(ctx context.Context, resolution string, days int) (err error) {
+	if days <= 0 {
+		return
+	}
+	err = n.DB(ctx).Delete(&MetricBucketStat{}, "resolution = ? and time < ?", resolution, time.Now().AddDate(0, 0, -days)).Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrMetricBucketDelete)
+	}
+	return
+}
+
+// DeleteByMetricId ...
+func (n MetricBucketStats) DeleteByMetricId(ctx context.Context, metricId int64) (err error) {
+	if err = n.DB(ctx).Delete(&MetricBucketStat{}, "metric_id = ?", metricId).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrMetricBucketDelete)
+	}
+	return
+}
@@ -42,6 +42,7 @@ type Role struct {
 	RoleName    sql.NullString `gorm:"column:parent"`
 	Children    []*Role
 	Permissions []*Permission
+	RequireTotp bool
 	CreatedAt   time.Time `gorm:"<-:create"`
 	UpdatedAt   time.Time
 }
@@ -81,8 +82,9 @@ func (n Roles) GetByName(ctx context.Context, name string) (role *Role, err erro
 // Update ...
 func (n Roles) Update(ctx context.Context, m *Role) (err error) {
 	err = n.DB(ctx).Model(&Role{Name: m.Name}).Updates(map[string]interface{}{
-		"description": m.Description,
-		"parent":      m.RoleName,
+		"description":  m.Description,
+		"parent":       m.RoleName,
+		"require_totp": m.RequireTotp,
 	}).Error
 	if err != nil {
 		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrRoleUpdate)
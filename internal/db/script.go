@@ -57,6 +57,7 @@ type Script struct {
 	Source      string
 	Description string
 	Compiled    string
+	Timeout     int
 	Versions    []*ScriptVersion
 	CreatedAt   time.Time `gorm:"<-:create"`
 	UpdatedAt   time.Time
@@ -168,6 +169,7 @@ func (n Scripts) Update(ctx context.Context, script *Script) (err error) {
 		"lang":        script.Lang,
 		"source":      script.Source,
 		"compiled":    script.Compiled,
+		"timeout":     script.Timeout,
 	}).Error
 	if err != nil {
 		var pgErr *pgconn.PgError
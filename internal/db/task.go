@@ -20,6 +20,7 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -41,9 +42,12 @@ type Task struct {
 	Description string
 	Enabled     bool
 	Condition   pkgCommon.ConditionType
-	Conditions  []*Condition `gorm:"many2many:task_conditions;"`
-	Actions     []*Action    `gorm:"many2many:task_actions;"`
-	Triggers    []*Trigger   `gorm:"many2many:task_triggers;"`
+	Mode        pkgCommon.TaskMode
+	MaxRuns     int
+	Steps       json.RawMessage `gorm:"type:jsonb;not null"`
+	Conditions  []*Condition    `gorm:"many2many:task_conditions;"`
+	Actions     []*Action       `gorm:"many2many:task_actions;"`
+	Triggers    []*Trigger      `gorm:"many2many:task_triggers;"`
 	AreaId      *int64
 	Area        *Area
 	CreatedAt   time.Time `gorm:"<-:create"`
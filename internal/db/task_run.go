@@ -0,0 +1,128 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	pkgCommon "github.com/e154/smart-home/pkg/common"
+	"gorm.io/gorm"
+)
+
+// TaskRuns ...
+type TaskRuns struct {
+	*Common
+}
+
+// TaskRun ...
+type TaskRun struct {
+	Id        int64 `gorm:"primary_key"`
+	TaskId    int64
+	TriggerId *int64
+	EntityId  *pkgCommon.EntityId
+	Payload   json.RawMessage `gorm:"type:jsonb;not null"`
+	Status    pkgCommon.TaskRunStatus
+	Error     string
+	Trace     json.RawMessage `gorm:"type:jsonb;not null"`
+	Start     time.Time
+	End       *time.Time
+}
+
+// TableName ...
+func (d *TaskRun) TableName() string {
+	return "task_runs"
+}
+
+// Add ...
+func (n TaskRuns) Add(ctx context.Context, run *TaskRun) (id int64, err error) {
+	if err = n.DB(ctx).Create(&run).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrTaskRunAdd)
+		return
+	}
+	id = run.Id
+	return
+}
+
+// GetById ...
+func (n TaskRuns) GetById(ctx context.Context, id int64) (run *TaskRun, err error) {
+	run = &TaskRun{}
+	if err = n.DB(ctx).Model(run).Where("id = ?", id).First(&run).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("%s: %w", fmt.Sprintf("id \"%d\"", id), apperr.ErrTaskRunNotFound)
+			return
+		}
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrTaskRunGet)
+	}
+	return
+}
+
+// List ...
+func (n *TaskRuns) List(ctx context.Context, limit, offset int, orderBy, sort string, taskId int64) (list []*TaskRun, total int64, err error) {
+
+	list = make([]*TaskRun, 0)
+	q := n.DB(ctx).Model(&TaskRun{}).
+		Where("task_id = ?", taskId)
+
+	if err = q.Count(&total).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrTaskRunList)
+		return
+	}
+
+	if sort != "" && orderBy != "" {
+		q = q.
+			Order(fmt.Sprintf("%s %s", sort, orderBy))
+	}
+
+	// the trace is only needed for the detailed view
+	q = q.
+		Omit("Trace").
+		Limit(limit).
+		Offset(offset)
+
+	if err = q.Find(&list).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrTaskRunList)
+	}
+
+	return
+}
+
+// DeleteOldest ...
+func (n *TaskRuns) DeleteOldest(ctx context.Context, days int) (err error) {
+	run := &TaskRun{}
+	if err = n.DB(ctx).Last(&run).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+			return
+		}
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrTaskRunDelete)
+		return
+	}
+	err = n.DB(ctx).Delete(&TaskRun{},
+		fmt.Sprintf(`start < CAST('%s' AS DATE) - interval '%d days'`,
+			run.Start.UTC().Format("2006-01-02 15:04:05"), days)).Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrTaskRunDelete)
+	}
+	return
+}
@@ -0,0 +1,175 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	"gorm.io/gorm"
+)
+
+// UserSessions ...
+type UserSessions struct {
+	*Common
+}
+
+// UserSession ...
+type UserSession struct {
+	Id            int64 `gorm:"primary_key"`
+	UserId        int64
+	RefreshToken  string
+	PreviousToken *string
+	Device        string
+	Ip            string
+	CreatedAt     time.Time `gorm:"<-:create"`
+	LastSeenAt    time.Time
+	ExpiresAt     time.Time
+	RevokedAt     *time.Time
+}
+
+// TableName ...
+func (d *UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// Add ...
+func (n UserSessions) Add(ctx context.Context, session *UserSession) (id int64, err error) {
+	if err = n.DB(ctx).Create(&session).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserSessionAdd)
+		return
+	}
+	id = session.Id
+	return
+}
+
+// GetById ...
+func (n UserSessions) GetById(ctx context.Context, id int64) (session *UserSession, err error) {
+	session = &UserSession{}
+	if err = n.DB(ctx).Model(session).Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("%s: %w", fmt.Sprintf("id \"%d\"", id), apperr.ErrUserSessionNotFound)
+			return
+		}
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserSessionGet)
+	}
+	return
+}
+
+// GetByToken returns the session with the current or the previous refresh token
+func (n UserSessions) GetByToken(ctx context.Context, token string) (session *UserSession, err error) {
+	session = &UserSession{}
+	err = n.DB(ctx).Model(session).
+		Where("refresh_token = ? or previous_token = ?", token, token).
+		First(&session).
+		Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("%s: %w", "token", apperr.ErrUserSessionNotFound)
+			return
+		}
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserSessionGet)
+	}
+	return
+}
+
+// Rotate updates the session only while its refresh token is the token,
+// so only one of the concurrent rotations of the same token succeeds
+func (n UserSessions) Rotate(ctx context.Context, session *UserSession, token string) (ok bool, err error) {
+	q := map[string]interface{}{
+		"refresh_token":  session.RefreshToken,
+		"previous_token": session.PreviousToken,
+		"ip":             session.Ip,
+		"last_seen_at":   session.LastSeenAt,
+		"expires_at":     session.ExpiresAt,
+	}
+	result := n.DB(ctx).Model(&UserSession{}).
+		Where("id = ? and refresh_token = ? and revoked_at is null", session.Id, token).
+		Updates(q)
+	if err = result.Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserSessionUpdate)
+		return
+	}
+	ok = result.RowsAffected == 1
+	return
+}
+
+// Touch ...
+func (n UserSessions) Touch(ctx context.Context, id int64, lastSeen time.Time) (err error) {
+	err = n.DB(ctx).Model(&UserSession{Id: id}).
+		Update("last_seen_at", lastSeen).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserSessionUpdate)
+	}
+	return
+}
+
+// Revoke ...
+func (n UserSessions) Revoke(ctx context.Context, id int64) (err error) {
+	err = n.DB(ctx).Model(&UserSession{}).
+		Where("id = ? and revoked_at is null", id).
+		Update("revoked_at", time.Now()).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserSessionRevoke)
+	}
+	return
+}
+
+// RevokeAll ...
+func (n UserSessions) RevokeAll(ctx context.Context, userId int64) (err error) {
+	err = n.DB(ctx).Model(&UserSession{}).
+		Where("user_id = ? and revoked_at is null", userId).
+		Update("revoked_at", time.Now()).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserSessionRevoke)
+	}
+	return
+}
+
+// List returns the active sessions of the user
+func (n UserSessions) List(ctx context.Context, userId int64) (list []*UserSession, err error) {
+	list = make([]*UserSession, 0)
+	err = n.DB(ctx).Model(&UserSession{}).
+		Where("user_id = ? and revoked_at is null and expires_at > ?", userId, time.Now()).
+		Order("last_seen_at desc").
+		Find(&list).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserSessionList)
+	}
+	return
+}
+
+// DeleteOld removes the sessions expired or revoked before the time
+func (n UserSessions) DeleteOld(ctx context.Context, before time.Time) (err error) {
+	err = n.DB(ctx).
+		Where("expires_at < ? or revoked_at < ?", before, before).
+		Delete(&UserSession{}).
+		Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserSessionRevoke)
+	}
+	return
+}
@@ -0,0 +1,85 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserTotps ...
+type UserTotps struct {
+	*Common
+}
+
+// UserTotp ...
+type UserTotp struct {
+	UserId        int64 `gorm:"primary_key"`
+	Secret        string
+	Enabled       bool
+	RecoveryCodes json.RawMessage `gorm:"type:jsonb;not null"`
+	LastCounter   int64
+	CreatedAt     time.Time `gorm:"<-:create"`
+	UpdatedAt     time.Time
+}
+
+// TableName ...
+func (d *UserTotp) TableName() string {
+	return "user_totp"
+}
+
+// Save ...
+func (n UserTotps) Save(ctx context.Context, totp *UserTotp) (err error) {
+	err = n.DB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled", "recovery_codes", "last_counter", "updated_at"}),
+	}).Create(totp).Error
+	if err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserTotpSave)
+	}
+	return
+}
+
+// GetByUserId ...
+func (n UserTotps) GetByUserId(ctx context.Context, userId int64) (totp *UserTotp, err error) {
+	totp = &UserTotp{}
+	if err = n.DB(ctx).Model(totp).Where("user_id = ?", userId).First(&totp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("%s: %w", fmt.Sprintf("user id \"%d\"", userId), apperr.ErrUserTotpNotFound)
+			return
+		}
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserTotpGet)
+	}
+	return
+}
+
+// Delete ...
+func (n UserTotps) Delete(ctx context.Context, userId int64) (err error) {
+	if err = n.DB(ctx).Delete(&UserTotp{}, "user_id = ?", userId).Error; err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUserTotpDelete)
+	}
+	return
+}
@@ -42,6 +42,7 @@ type Variable struct {
 	Name      string `gorm:"primary_key"`
 	Value     string
 	System    bool
+	Encrypted bool
 	EntityId  *pkgCommon.EntityId
 	Tags      []*Tag    `gorm:"many2many:variable_tags;"`
 	CreatedAt time.Time `gorm:"<-:create"`
@@ -58,7 +59,7 @@ func (n *Variables) CreateOrUpdate(ctx context.Context, v Variable) (err error)
 
 	err = n.DB(ctx).Omit("Tags.*").Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "name"}},
-		DoUpdates: clause.AssignmentColumns([]string{"value", "system", "entity_id", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"value", "system", "encrypted", "entity_id", "updated_at"}),
 	}).Create(&v).Error
 
 	if err != nil {
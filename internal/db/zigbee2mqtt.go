@@ -41,6 +41,7 @@ type Zigbee2mqtt struct {
 	Devices           []*Zigbee2mqttDevice
 	EncryptedPassword string
 	PermitJoin        bool
+	AutoDiscovery     bool
 	BaseTopic         string
 	CreatedAt         time.Time `gorm:"<-:create"`
 	UpdatedAt         time.Time
@@ -82,6 +83,7 @@ func (z Zigbee2mqtts) Update(ctx context.Context, m *Zigbee2mqtt) (err error) {
 		"Name":               m.Name,
 		"Login":              m.Login,
 		"PermitJoin":         m.PermitJoin,
+		"AutoDiscovery":      m.AutoDiscovery,
 		"BaseTopic":          m.BaseTopic,
 		"encrypted_password": m.EncryptedPassword,
 	}
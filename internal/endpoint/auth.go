@@ -24,9 +24,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/e154/smart-home/internal/common"
 	"github.com/e154/smart-home/internal/plugins/email"
 	"github.com/e154/smart-home/internal/plugins/notify"
 	notifyCommon "github.com/e154/smart-home/internal/plugins/notify/common"
+	"github.com/e154/smart-home/internal/system/jwt_manager"
 	"github.com/e154/smart-home/internal/system/rbac/access_list"
 	"github.com/e154/smart-home/pkg/apperr"
 	"github.com/e154/smart-home/pkg/events"
@@ -50,8 +52,9 @@ func NewAuthEndpoint(common *CommonEndpoint) *AuthEndpoint {
 	}
 }
 
-// SignIn ...
-func (a *AuthEndpoint) SignIn(ctx context.Context, email, password string, ip string) (user *models.User, accessToken string, err error) {
+// SignIn creates the session of the device, the client keeps the refresh token to get new access tokens.
+// The user with the enabled two-factor authentication also passes the code or the recovery code.
+func (a *AuthEndpoint) SignIn(ctx context.Context, email, password, totpCode, ip, device string) (user *models.User, tokens *models.AuthTokens, err error) {
 
 	if user, err = a.adaptors.User.GetByEmail(ctx, email); err != nil {
 		err = fmt.Errorf("%s: %w", fmt.Sprintf("email %s", email), apperr.ErrUnauthorized)
@@ -64,11 +67,43 @@ func (a *AuthEndpoint) SignIn(ctx context.Context, email, password string, ip st
 		return
 	}
 
-	if accessToken, err = a.jwtManager.Generate(user, false); err != nil {
+	var userTotp *models.UserTotp
+	if userTotp, err = a.adaptors.UserTotp.GetByUserId(ctx, user.Id); err != nil {
+		if !errors.Is(err, apperr.ErrUserTotpNotFound) {
+			return
+		}
+		err = nil
+	} else if userTotp.Enabled {
+		if err = a.verifyTotp(ctx, userTotp, totpCode); err != nil {
+			return
+		}
+	}
+
+	now := time.Now()
+
+	// the sessions expired or revoked long ago are not needed anymore
+	if err = a.adaptors.UserSession.DeleteOld(ctx, now.Add(-jwt_manager.RefreshTokenTTL)); err != nil {
+		log.Error(err.Error())
+	}
+
+	refreshToken := common.RandomToken(32)
+	session := &models.UserSession{
+		UserId:       user.Id,
+		RefreshToken: common.HashToken(refreshToken),
+		Device:       device,
+		Ip:           ip,
+		LastSeenAt:   now,
+		ExpiresAt:    now.Add(jwt_manager.RefreshTokenTTL),
+	}
+	if session.Id, err = a.adaptors.UserSession.Add(ctx, session); err != nil {
 		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUnauthorized)
 		return
 	}
 
+	if tokens, err = a.sessionTokens(user, session.Id, refreshToken); err != nil {
+		return
+	}
+
 	if err = a.adaptors.User.SignIn(ctx, user, ip); err != nil {
 		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUnauthorized)
 		return
@@ -83,8 +118,77 @@ func (a *AuthEndpoint) SignIn(ctx context.Context, email, password string, ip st
 	return
 }
 
-// SignOut ...
-func (a *AuthEndpoint) SignOut(ctx context.Context, user *models.User) (err error) {
+// RefreshToken rotates the refresh token of the session and returns the new access token.
+// The reuse of the rotated token means the token is stolen, the session is revoked.
+func (a *AuthEndpoint) RefreshToken(ctx context.Context, refreshToken, ip string) (user *models.User, tokens *models.AuthTokens, err error) {
+
+	if refreshToken == "" {
+		err = apperr.ErrUnauthorized
+		return
+	}
+
+	hash := common.HashToken(refreshToken)
+
+	var session *models.UserSession
+	if session, err = a.adaptors.UserSession.GetByToken(ctx, hash); err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUnauthorized)
+		return
+	}
+
+	now := time.Now()
+	if session.RefreshToken != hash {
+		log.Warnf("reuse of the refresh token, session %d of user %d is revoked", session.Id, session.UserId)
+		_ = a.revokeSession(ctx, session.UserId, session.Id)
+		err = apperr.ErrUserSessionRevoked
+		return
+	}
+	if !session.IsActive(now) {
+		err = apperr.ErrUserSessionRevoked
+		return
+	}
+
+	if user, err = a.adaptors.User.GetById(ctx, session.UserId); err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUnauthorized)
+		return
+	}
+	if user.Status == "blocked" && user.Id != AdminId {
+		err = apperr.ErrAccountIsBlocked
+		return
+	}
+
+	refreshToken = common.RandomToken(32)
+	session.PreviousToken = &hash
+	session.RefreshToken = common.HashToken(refreshToken)
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(jwt_manager.RefreshTokenTTL)
+	if ip != "" {
+		session.Ip = ip
+	}
+	var ok bool
+	if ok, err = a.adaptors.UserSession.Rotate(ctx, session, hash); err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUnauthorized)
+		return
+	}
+	if !ok {
+		// the token is rotated by the concurrent request
+		log.Warnf("reuse of the refresh token, session %d of user %d is revoked", session.Id, session.UserId)
+		_ = a.revokeSession(ctx, session.UserId, session.Id)
+		err = apperr.ErrUserSessionRevoked
+		return
+	}
+
+	tokens, err = a.sessionTokens(user, session.Id, refreshToken)
+
+	return
+}
+
+// SignOut revokes the session of the access token
+func (a *AuthEndpoint) SignOut(ctx context.Context, user *models.User, accessToken string) (err error) {
+	if claims, _ := a.jwtManager.Verify(accessToken); claims != nil && claims.SessionId != 0 {
+		if err = a.revokeSession(ctx, user.Id, claims.SessionId); err != nil {
+			return
+		}
+	}
 	err = a.adaptors.User.ClearToken(ctx, user)
 	if err != nil {
 		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrNotAllowed)
@@ -93,6 +197,65 @@ func (a *AuthEndpoint) SignOut(ctx context.Context, user *models.User) (err erro
 	return
 }
 
+// Sessions returns the active sessions of the user, current is the session of the access token
+func (a *AuthEndpoint) Sessions(ctx context.Context, user *models.User, accessToken string) (list []*models.UserSession, current int64, err error) {
+	if list, err = a.adaptors.UserSession.List(ctx, user.Id); err != nil {
+		return
+	}
+	if claims, _ := a.jwtManager.Verify(accessToken); claims != nil {
+		current = claims.SessionId
+	}
+	return
+}
+
+// RevokeSession ...
+func (a *AuthEndpoint) RevokeSession(ctx context.Context, user *models.User, id int64) (err error) {
+	var session *models.UserSession
+	if session, err = a.adaptors.UserSession.GetById(ctx, id); err != nil {
+		return
+	}
+	if session.UserId != user.Id {
+		err = fmt.Errorf("%s: %w", fmt.Sprintf("id \"%d\"", id), apperr.ErrUserSessionNotFound)
+		return
+	}
+	err = a.revokeSession(ctx, user.Id, id)
+	return
+}
+
+// RevokeAllSessions ...
+func (a *AuthEndpoint) RevokeAllSessions(ctx context.Context, user *models.User) (err error) {
+	if err = a.adaptors.UserSession.RevokeAll(ctx, user.Id); err != nil {
+		return
+	}
+	log.Infof("all sessions of user %s are revoked", user.Email)
+	a.eventBus.Publish(fmt.Sprintf("system/users/%d", user.Id), events.EventUserSessionRevoked{
+		UserId: user.Id,
+	})
+	return
+}
+
+func (a *AuthEndpoint) revokeSession(ctx context.Context, userId, id int64) (err error) {
+	if err = a.adaptors.UserSession.Revoke(ctx, id); err != nil {
+		return
+	}
+	a.eventBus.Publish(fmt.Sprintf("system/users/%d", userId), events.EventUserSessionRevoked{
+		UserId:    userId,
+		SessionId: id,
+	})
+	return
+}
+
+func (a *AuthEndpoint) sessionTokens(user *models.User, sessionId int64, refreshToken string) (tokens *models.AuthTokens, err error) {
+	tokens = &models.AuthTokens{
+		RefreshToken: refreshToken,
+		SessionId:    sessionId,
+	}
+	if tokens.AccessToken, tokens.AccessTokenExpiresAt, err = a.jwtManager.GenerateSession(user, sessionId, false); err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrUnauthorized)
+	}
+	return
+}
+
 // PasswordReset ...
 func (a *AuthEndpoint) PasswordReset(ctx context.Context, userEmail string, token, newPassword *string) (err error) {
 
@@ -116,6 +279,8 @@ func (a *AuthEndpoint) PasswordReset(ctx context.Context, userEmail string, toke
 		user.ResetPasswordSentAt = nil
 		if err = a.adaptors.User.Update(ctx, user); err == nil {
 			log.Warnf("The password for the %s user has just been updated", user.Email)
+			// the sessions signed in with the old password are not trusted
+			err = a.RevokeAllSessions(ctx, user)
 		}
 
 		return
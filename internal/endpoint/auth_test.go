@@ -0,0 +1,128 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/e154/bus"
+	"github.com/e154/smart-home/internal/common"
+	"github.com/e154/smart-home/internal/system/jwt_manager"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+type userRepo struct {
+	adaptors.UserRepo
+	user *m.User
+}
+
+func (r *userRepo) GetById(_ context.Context, _ int64) (*m.User, error) {
+	return r.user, nil
+}
+
+type userSessionRepo struct {
+	adaptors.UserSessionRepo
+	sync.Mutex
+	session *m.UserSession
+	// the readers wait for each other to rotate the same token at once
+	readers sync.WaitGroup
+}
+
+func (r *userSessionRepo) GetByToken(_ context.Context, _ string) (*m.UserSession, error) {
+	r.Lock()
+	session := *r.session
+	r.Unlock()
+	r.readers.Done()
+	r.readers.Wait()
+	return &session, nil
+}
+
+func (r *userSessionRepo) Rotate(_ context.Context, session *m.UserSession, token string) (bool, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.session.RefreshToken != token || r.session.RevokedAt != nil {
+		return false, nil
+	}
+	saved := *session
+	r.session = &saved
+	return true, nil
+}
+
+func (r *userSessionRepo) Revoke(_ context.Context, _ int64) error {
+	r.Lock()
+	defer r.Unlock()
+	now := time.Now()
+	r.session.RevokedAt = &now
+	return nil
+}
+
+type jwtManager struct {
+	jwt_manager.JwtManager
+}
+
+func (j *jwtManager) GenerateSession(_ *m.User, _ int64, _ bool) (string, time.Time, error) {
+	return "access", time.Now().Add(time.Minute), nil
+}
+
+func TestRefreshTokenConcurrent(t *testing.T) {
+
+	refreshToken := common.RandomToken(32)
+	sessions := &userSessionRepo{session: &m.UserSession{
+		Id:           1,
+		UserId:       2,
+		RefreshToken: common.HashToken(refreshToken),
+		LastSeenAt:   time.Now(),
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}}
+	endpoint := NewAuthEndpoint(&CommonEndpoint{
+		adaptors: &adaptors.Adaptors{
+			User:        &userRepo{user: &m.User{Id: 2, Status: "active"}},
+			UserSession: sessions,
+		},
+		eventBus:   bus.NewBus(),
+		jwtManager: &jwtManager{},
+	})
+
+	// both requests pass the token check before any of them rotates it
+	sessions.readers.Add(2)
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, _, err := endpoint.RefreshToken(context.Background(), refreshToken, "")
+			errs <- err
+		}()
+	}
+
+	var revoked int
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			require.ErrorIs(t, err, apperr.ErrUserSessionRevoked)
+			revoked++
+		}
+	}
+	require.Equal(t, 1, revoked)
+	require.NotNil(t, sessions.session.RevokedAt)
+}
@@ -20,25 +20,44 @@ package endpoint
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/e154/bus"
+	"github.com/e154/smart-home/internal/common"
+	"github.com/e154/smart-home/internal/common/totp"
 	"github.com/e154/smart-home/internal/system/automation"
 	"github.com/e154/smart-home/internal/system/cache"
 	"github.com/e154/smart-home/internal/system/jwt_manager"
 	"github.com/e154/smart-home/internal/system/rbac/access_list"
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
 	"github.com/e154/smart-home/internal/system/validation"
 	"github.com/e154/smart-home/internal/system/zigbee2mqtt"
 	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
+	pkgCommon "github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 	"github.com/e154/smart-home/pkg/mqtt"
 	"github.com/e154/smart-home/pkg/plugins"
 	"github.com/e154/smart-home/pkg/scripts"
 )
 
+const (
+	// totpMaxFailures the invalid codes in a row before the user is locked out
+	totpMaxFailures = 5
+	totpLockout     = 5 * time.Minute
+)
+
+// totpMx the code is checked and marked used at once
+var totpMx sync.Mutex
+
 // CommonEndpoint ...
 type CommonEndpoint struct {
 	adaptors      *adaptors.Adaptors
 	accessList    access_list.AccessListService
+	entityAccess  entity_access.EntityAccessService
 	scriptService scripts.ScriptService
 	zigbee2mqtt   zigbee2mqtt.Zigbee2mqtt
 	eventBus      bus.Bus
@@ -54,6 +73,7 @@ type CommonEndpoint struct {
 // NewCommonEndpoint ...
 func NewCommonEndpoint(adaptors *adaptors.Adaptors,
 	accessList access_list.AccessListService,
+	entityAccess entity_access.EntityAccessService,
 	scriptService scripts.ScriptService,
 	zigbee2mqtt zigbee2mqtt.Zigbee2mqtt,
 	eventBus bus.Bus,
@@ -68,6 +88,7 @@ func NewCommonEndpoint(adaptors *adaptors.Adaptors,
 	return &CommonEndpoint{
 		adaptors:      adaptors,
 		accessList:    accessList,
+		entityAccess:  entityAccess,
 		scriptService: scriptService,
 		zigbee2mqtt:   zigbee2mqtt,
 		eventBus:      eventBus,
@@ -91,3 +112,98 @@ func (c *CommonEndpoint) checkSuperUser(ctx context.Context) (decline bool) {
 
 	return !root
 }
+
+func (c *CommonEndpoint) currentUser(ctx context.Context) *m.User {
+	user, _ := ctx.Value("currentUser").(*m.User)
+	return user
+}
+
+// verifyTotp checks the code of the authenticator application or uses up the recovery code.
+// The code is accepted once, the user is locked out for a while after too many failures.
+func (c *CommonEndpoint) verifyTotp(ctx context.Context, userTotp *m.UserTotp, code string) (err error) {
+	if code == "" {
+		return apperr.ErrUserTotpRequired
+	}
+
+	totpMx.Lock()
+	defer totpMx.Unlock()
+
+	key := fmt.Sprintf("totp_failures_%d", userTotp.UserId)
+	var failures int
+	if value, _ := c.cache.Get(ctx, key); value != nil {
+		failures, _ = value.(int)
+	}
+	if failures >= totpMaxFailures {
+		return apperr.ErrUserTotpThrottled
+	}
+
+	defer func() {
+		if err == nil {
+			_ = c.cache.Delete(ctx, key)
+			return
+		}
+		if errors.Is(err, apperr.ErrUserTotpInvalid) {
+			_ = c.cache.Put(ctx, key, failures+1, totpLockout)
+			log.Warnf("invalid two-factor authentication code of user id:(%d), %d failures", userTotp.UserId, failures+1)
+		}
+	}()
+
+	if counter, ok := totp.Counter(userTotp.Secret, code, time.Now()); ok {
+		if counter <= userTotp.LastCounter {
+			// the code was used already
+			return apperr.ErrUserTotpInvalid
+		}
+		userTotp.LastCounter = counter
+		return c.adaptors.UserTotp.Save(ctx, userTotp)
+	}
+
+	hash := common.HashToken(code)
+	for i, recoveryCode := range userTotp.RecoveryCodes {
+		if recoveryCode != hash {
+			continue
+		}
+		userTotp.RecoveryCodes = append(userTotp.RecoveryCodes[:i], userTotp.RecoveryCodes[i+1:]...)
+		if err = c.adaptors.UserTotp.Save(ctx, userTotp); err != nil {
+			return
+		}
+		log.Warnf("recovery code used by user id:(%d), %d codes left", userTotp.UserId, len(userTotp.RecoveryCodes))
+		return
+	}
+
+	return apperr.ErrUserTotpInvalid
+}
+
+// entityRules the entity permissions of the current user, nil if the user has access to all entities
+func (c *CommonEndpoint) entityRules(ctx context.Context) *entity_access.Rules {
+	user := c.currentUser(ctx)
+	if user == nil {
+		return nil
+	}
+	return c.entityAccess.Rules(ctx, user)
+}
+
+func (c *CommonEndpoint) checkEntityAccess(ctx context.Context, entity *m.Entity, level pkgCommon.EntityAccessLevel) error {
+	user := c.currentUser(ctx)
+	if user == nil {
+		return nil
+	}
+	return c.entityAccess.CheckEntity(ctx, user, entity, level)
+}
+
+// readableEntityIds filters the entities the rules allow to read, all readable entities if the list is empty
+func (c *CommonEndpoint) readableEntityIds(ctx context.Context, rules *entity_access.Rules, ids []pkgCommon.EntityId) (result []pkgCommon.EntityId, err error) {
+	var list []*m.Entity
+	if len(ids) == 0 {
+		list, _, err = c.adaptors.Entity.ListPlain(ctx, 999, 0, "desc", "id", false, nil, nil, nil, nil)
+	} else {
+		list, err = c.adaptors.Entity.GetByIds(ctx, ids)
+	}
+	if err != nil {
+		return
+	}
+	result = make([]pkgCommon.EntityId, 0, len(list))
+	for _, entity := range rules.Filter(list, pkgCommon.EntityAccessRead) {
+		result = append(result, entity.Id)
+	}
+	return
+}
@@ -0,0 +1,79 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/internal/common/totp"
+	"github.com/e154/smart-home/internal/system/cache"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+type userTotpRepo struct {
+	adaptors.UserTotpRepo
+	saved *m.UserTotp
+}
+
+func (r *userTotpRepo) Save(_ context.Context, userTotp *m.UserTotp) error {
+	saved := *userTotp
+	r.saved = &saved
+	return nil
+}
+
+func TestVerifyTotp(t *testing.T) {
+
+	repo := &userTotpRepo{}
+	memory, err := cache.NewCache("memory", `{"interval":60}`)
+	require.NoError(t, err)
+	c := &CommonEndpoint{
+		adaptors: &adaptors.Adaptors{UserTotp: repo},
+		cache:    memory,
+	}
+	ctx := context.Background()
+	userTotp := &m.UserTotp{UserId: 2, Secret: totp.NewSecret(), Enabled: true}
+
+	now := time.Now()
+	code, err := totp.Code(userTotp.Secret, now)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, c.verifyTotp(ctx, userTotp, ""), apperr.ErrUserTotpRequired)
+	require.NoError(t, c.verifyTotp(ctx, userTotp, code))
+	require.Equal(t, now.Unix()/totp.Period, repo.saved.LastCounter)
+
+	// the code is accepted once
+	require.ErrorIs(t, c.verifyTotp(ctx, userTotp, code), apperr.ErrUserTotpInvalid)
+
+	// the user is locked out after the failures in a row, the valid code is not checked
+	for i := 1; i < totpMaxFailures; i++ {
+		require.ErrorIs(t, c.verifyTotp(ctx, userTotp, "000000"), apperr.ErrUserTotpInvalid)
+	}
+	userTotp.LastCounter = 0
+	require.ErrorIs(t, c.verifyTotp(ctx, userTotp, code), apperr.ErrUserTotpThrottled)
+
+	// the other users are not affected
+	other := &m.UserTotp{UserId: 3, Secret: userTotp.Secret, Enabled: true}
+	require.NoError(t, c.verifyTotp(ctx, other, code))
+}
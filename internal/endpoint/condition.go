@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"github.com/e154/smart-home/internal/common"
+	"github.com/e154/smart-home/internal/system/automation"
 	"github.com/e154/smart-home/pkg/apperr"
 	"github.com/e154/smart-home/pkg/events"
 	m "github.com/e154/smart-home/pkg/models"
@@ -49,6 +50,11 @@ func (n *ConditionEndpoint) Add(ctx context.Context, condition *m.Condition) (re
 		return
 	}
 
+	if err = automation.ValidateConditionRule(condition.Rule); err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrValidation)
+		return
+	}
+
 	if condition.Id, err = n.adaptors.Condition.Add(ctx, condition); err != nil {
 		return
 	}
@@ -88,6 +94,11 @@ func (n *ConditionEndpoint) Update(ctx context.Context, params *m.Condition) (re
 		return
 	}
 
+	if err = automation.ValidateConditionRule(params.Rule); err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrValidation)
+		return
+	}
+
 	if err = n.adaptors.Condition.Update(ctx, params); err != nil {
 		return
 	}
@@ -0,0 +1,1156 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/scripts"
+
+	"github.com/e154/bus"
+)
+
+// ConfigArchiveEndpoint exports and imports the configuration objects,
+// the metric data and the logs are not touched
+type ConfigArchiveEndpoint struct {
+	*CommonEndpoint
+}
+
+// NewConfigArchiveEndpoint ...
+func NewConfigArchiveEndpoint(common *CommonEndpoint) *ConfigArchiveEndpoint {
+	return &ConfigArchiveEndpoint{
+		CommonEndpoint: common,
+	}
+}
+
+// Export ...
+func (n *ConfigArchiveEndpoint) Export(ctx context.Context, params *m.ConfigExportParams) (archive *m.ConfigArchive, err error) {
+
+	c := &archiveCollector{
+		adaptors: n.adaptors,
+		archive: &m.ConfigArchive{
+			Version:   m.ConfigArchiveVersion,
+			CreatedAt: time.Now(),
+		},
+		visited: make(map[string]struct{}),
+	}
+
+	for _, id := range params.Scripts {
+		if err = c.addScript(ctx, common.Int64(id)); err != nil {
+			return
+		}
+	}
+	for _, id := range params.Areas {
+		if err = c.addArea(ctx, common.Int64(id)); err != nil {
+			return
+		}
+	}
+	for _, id := range params.Entities {
+		if err = c.addEntity(ctx, id); err != nil {
+			return
+		}
+	}
+	for _, name := range params.Variables {
+		if err = c.addVariable(ctx, name); err != nil {
+			return
+		}
+	}
+	for _, id := range params.Conditions {
+		if err = c.addCondition(ctx, common.Int64(id)); err != nil {
+			return
+		}
+	}
+	for _, id := range params.Triggers {
+		if err = c.addTrigger(ctx, id); err != nil {
+			return
+		}
+	}
+	for _, id := range params.Actions {
+		if err = c.addAction(ctx, common.Int64(id)); err != nil {
+			return
+		}
+	}
+	for _, id := range params.Tasks {
+		if err = c.addTask(ctx, id); err != nil {
+			return
+		}
+	}
+	for _, id := range params.Dashboards {
+		if err = c.addDashboard(ctx, id); err != nil {
+			return
+		}
+	}
+
+	archive = c.archive
+
+	log.Infof("exported %d scripts, %d areas, %d entities, %d variables, %d conditions, %d triggers, %d actions, %d tasks, %d dashboards",
+		len(archive.Scripts), len(archive.Areas), len(archive.Entities), len(archive.Variables), len(archive.Conditions),
+		len(archive.Triggers), len(archive.Actions), len(archive.Tasks), len(archive.Dashboards))
+
+	return
+}
+
+// Import adds the objects of the archive, the references between the objects are remapped to the new identifiers
+func (n *ConfigArchiveEndpoint) Import(ctx context.Context, archive *m.ConfigArchive, conflict common.ImportConflict) (result *m.ConfigImportResult, err error) {
+
+	switch conflict {
+	case "":
+		conflict = common.ImportConflictSkip
+	case common.ImportConflictSkip, common.ImportConflictOverwrite, common.ImportConflictRename:
+	default:
+		err = fmt.Errorf("unknown conflict strategy \"%s\": %w", conflict, apperr.ErrInvalidRequest)
+		return
+	}
+
+	// scripts are compiled before the changes
+	for _, script := range archive.Scripts {
+		var engine scripts.Engine
+		if engine, err = n.scriptService.NewEngine(script); err != nil {
+			err = fmt.Errorf("script %s: %s: %w", script.Name, err.Error(), apperr.ErrInvalidRequest)
+			return
+		}
+		if err = engine.Compile(); err != nil {
+			err = fmt.Errorf("script %s: %s: %w", script.Name, err.Error(), apperr.ErrInvalidRequest)
+			return
+		}
+	}
+
+	var i *archiveImporter
+	err = n.adaptors.Transaction.Do(ctx, func(ctx context.Context) error {
+		i = newArchiveImporter(n.adaptors, conflict)
+		return i.importArchive(ctx, archive)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the runtime is notified after the commit
+	for _, publish := range i.events {
+		publish(n.eventBus)
+	}
+
+	result = &m.ConfigImportResult{
+		Items: i.items,
+	}
+
+	log.Infof("imported %d objects, conflict strategy \"%s\"", len(i.items), conflict)
+
+	return
+}
+
+// archiveCollector adds the object and the objects it refers to once
+type archiveCollector struct {
+	adaptors *adaptors.Adaptors
+	archive  *m.ConfigArchive
+	visited  map[string]struct{}
+}
+
+func (c *archiveCollector) visit(kind string, id interface{}) bool {
+	key := fmt.Sprintf("%s:%v", kind, id)
+	if _, ok := c.visited[key]; ok {
+		return false
+	}
+	c.visited[key] = struct{}{}
+	return true
+}
+
+func (c *archiveCollector) addScript(ctx context.Context, id *int64) (err error) {
+	if id == nil || !c.visit("script", *id) {
+		return
+	}
+	var script *m.Script
+	if script, err = c.adaptors.Script.GetById(ctx, *id); err != nil {
+		return
+	}
+	script.Versions = nil
+	script.Info = nil
+	c.archive.Scripts = append(c.archive.Scripts, script)
+	return
+}
+
+func (c *archiveCollector) addArea(ctx context.Context, id *int64) (err error) {
+	if id == nil || !c.visit("area", *id) {
+		return
+	}
+	var area *m.Area
+	if area, err = c.adaptors.Area.GetById(ctx, *id); err != nil {
+		return
+	}
+	c.archive.Areas = append(c.archive.Areas, area)
+	return
+}
+
+func (c *archiveCollector) addEntity(ctx context.Context, id common.EntityId) (err error) {
+	if !c.visit("entity", id) {
+		return
+	}
+	var entity *m.Entity
+	if entity, err = c.adaptors.Entity.GetById(ctx, id); err != nil {
+		return
+	}
+
+	// the parent is imported before the child
+	if entity.ParentId != nil {
+		if err = c.addEntity(ctx, *entity.ParentId); err != nil {
+			return
+		}
+	}
+	if err = c.addArea(ctx, entity.AreaId); err != nil {
+		return
+	}
+	for i, script := range entity.Scripts {
+		if err = c.addScript(ctx, common.Int64(script.Id)); err != nil {
+			return
+		}
+		entity.Scripts[i] = &m.Script{Id: script.Id, Name: script.Name}
+	}
+	for _, action := range entity.Actions {
+		if err = c.addScript(ctx, action.ScriptId); err != nil {
+			return
+		}
+		action.Script = nil
+		action.Image = nil
+		action.ImageId = nil
+	}
+	for _, state := range entity.States {
+		state.Image = nil
+		state.ImageId = nil
+	}
+	for _, metric := range entity.Metrics {
+		metric.Data = nil
+		metric.Ranges = nil
+	}
+
+	// the images are not part of the archive
+	entity.Image = nil
+	entity.ImageId = nil
+	entity.Area = nil
+	entity.Storage = nil
+	entity.IsLoaded = false
+
+	c.archive.Entities = append(c.archive.Entities, entity)
+	return
+}
+
+func (c *archiveCollector) addVariable(ctx context.Context, name string) (err error) {
+	if !c.visit("variable", name) {
+		return
+	}
+	var variable m.Variable
+	if variable, err = c.adaptors.Variable.GetByName(ctx, name); err != nil {
+		return
+	}
+	variable.Value = variable.MaskedValue()
+	c.archive.Variables = append(c.archive.Variables, variable)
+	return
+}
+
+func (c *archiveCollector) addCondition(ctx context.Context, id *int64) (err error) {
+	if id == nil || !c.visit("condition", *id) {
+		return
+	}
+	var condition *m.Condition
+	if condition, err = c.adaptors.Condition.GetById(ctx, *id); err != nil {
+		return
+	}
+	if err = c.addScript(ctx, condition.ScriptId); err != nil {
+		return
+	}
+	if err = c.addArea(ctx, condition.AreaId); err != nil {
+		return
+	}
+	condition.Script = nil
+	condition.Area = nil
+	c.archive.Conditions = append(c.archive.Conditions, condition)
+	return
+}
+
+func (c *archiveCollector) addTrigger(ctx context.Context, id int64) (err error) {
+	if !c.visit("trigger", id) {
+		return
+	}
+	var trigger *m.Trigger
+	if trigger, err = c.adaptors.Trigger.GetById(ctx, id); err != nil {
+		return
+	}
+	if err = c.addScript(ctx, trigger.ScriptId); err != nil {
+		return
+	}
+	if err = c.addArea(ctx, trigger.AreaId); err != nil {
+		return
+	}
+	for i, entity := range trigger.Entities {
+		trigger.Entities[i] = &m.Entity{Id: entity.Id}
+	}
+	trigger.Script = nil
+	trigger.Area = nil
+	trigger.IsLoaded = false
+	c.archive.Triggers = append(c.archive.Triggers, trigger)
+	return
+}
+
+func (c *archiveCollector) addAction(ctx context.Context, id *int64) (err error) {
+	if id == nil || !c.visit("action", *id) {
+		return
+	}
+	var action *m.Action
+	if action, err = c.adaptors.Action.GetById(ctx, *id); err != nil {
+		return
+	}
+	if err = c.addScript(ctx, action.ScriptId); err != nil {
+		return
+	}
+	if err = c.addArea(ctx, action.AreaId); err != nil {
+		return
+	}
+	action.Script = nil
+	action.Entity = nil
+	action.Area = nil
+	c.archive.Actions = append(c.archive.Actions, action)
+	return
+}
+
+func (c *archiveCollector) addTask(ctx context.Context, id int64) (err error) {
+	if !c.visit("task", id) {
+		return
+	}
+	var task *m.Task
+	if task, err = c.adaptors.Task.GetById(ctx, id); err != nil {
+		return
+	}
+	if err = c.addArea(ctx, task.AreaId); err != nil {
+		return
+	}
+	for i, trigger := range task.Triggers {
+		if err = c.addTrigger(ctx, trigger.Id); err != nil {
+			return
+		}
+		task.Triggers[i] = &m.Trigger{Id: trigger.Id, Name: trigger.Name}
+	}
+	for i, condition := range task.Conditions {
+		if err = c.addCondition(ctx, common.Int64(condition.Id)); err != nil {
+			return
+		}
+		task.Conditions[i] = &m.Condition{Id: condition.Id, Name: condition.Name}
+	}
+	for i, action := range task.Actions {
+		if err = c.addAction(ctx, common.Int64(action.Id)); err != nil {
+			return
+		}
+		task.Actions[i] = &m.Action{Id: action.Id, Name: action.Name}
+	}
+	m.WalkTaskSteps(task.Steps, func(step *m.TaskStep) {
+		if err == nil {
+			err = c.addAction(ctx, step.ActionId)
+		}
+		if err == nil {
+			err = c.addCondition(ctx, step.ConditionId)
+		}
+	})
+	if err != nil {
+		return
+	}
+	task.Area = nil
+	task.Telemetry = nil
+	task.IsLoaded = false
+	c.archive.Tasks = append(c.archive.Tasks, task)
+	return
+}
+
+func (c *archiveCollector) addDashboard(ctx context.Context, id int64) (err error) {
+	if !c.visit("dashboard", id) {
+		return
+	}
+	var board *m.Dashboard
+	if board, err = c.adaptors.Dashboard.GetById(ctx, id); err != nil {
+		return
+	}
+	if err = c.addArea(ctx, board.AreaId); err != nil {
+		return
+	}
+	board.Area = nil
+	board.Entities = nil
+	for _, tab := range board.Tabs {
+		tab.Dashboard = nil
+		tab.Entities = nil
+		for _, card := range tab.Cards {
+			card.DashboardTab = nil
+			card.Entities = nil
+			card.Entity = nil
+			for _, item := range card.Items {
+				item.DashboardCard = nil
+				item.Entity = nil
+			}
+		}
+	}
+	c.archive.Dashboards = append(c.archive.Dashboards, board)
+	return
+}
+
+// archiveImporter keeps the identifiers of the source instance and the new ones
+type archiveImporter struct {
+	adaptors   *adaptors.Adaptors
+	conflict   common.ImportConflict
+	scripts    map[int64]int64
+	areas      map[int64]int64
+	conditions map[int64]int64
+	triggers   map[int64]int64
+	actions    map[int64]int64
+	entities   map[common.EntityId]common.EntityId
+	items      []*m.ConfigImportItem
+	events     []func(eventBus bus.Bus)
+}
+
+func newArchiveImporter(adaptors *adaptors.Adaptors, conflict common.ImportConflict) *archiveImporter {
+	return &archiveImporter{
+		adaptors:   adaptors,
+		conflict:   conflict,
+		scripts:    make(map[int64]int64),
+		areas:      make(map[int64]int64),
+		conditions: make(map[int64]int64),
+		triggers:   make(map[int64]int64),
+		actions:    make(map[int64]int64),
+		entities:   make(map[common.EntityId]common.EntityId),
+	}
+}
+
+func (i *archiveImporter) importArchive(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	var steps = []func(context.Context, *m.ConfigArchive) error{
+		i.importScripts,
+		i.importAreas,
+		i.importEntities,
+		i.importVariables,
+		i.importConditions,
+		i.importTriggers,
+		i.importActions,
+		i.importTasks,
+		i.importDashboards,
+	}
+	for _, step := range steps {
+		if err = step(ctx, archive); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// status returns what happens to the object, exists reports the conflict
+func (i *archiveImporter) status(exists bool) common.ImportStatus {
+	if !exists {
+		return common.ImportStatusCreated
+	}
+	switch i.conflict {
+	case common.ImportConflictOverwrite:
+		return common.ImportStatusUpdated
+	case common.ImportConflictRename:
+		return common.ImportStatusRenamed
+	}
+	return common.ImportStatusSkipped
+}
+
+func (i *archiveImporter) add(kind, name string, oldId, newId interface{}, status common.ImportStatus) {
+	i.items = append(i.items, &m.ConfigImportItem{
+		Type:   kind,
+		Name:   name,
+		OldId:  fmt.Sprint(oldId),
+		NewId:  fmt.Sprint(newId),
+		Status: status,
+	})
+}
+
+func (i *archiveImporter) publish(topic string, msg interface{}) {
+	i.events = append(i.events, func(eventBus bus.Bus) {
+		eventBus.Publish(topic, msg)
+	})
+}
+
+// uniqueName adds the suffix and the counter until the name is free
+func uniqueName(name, suffix string, exists func(string) bool) string {
+	newName := name + suffix
+	for n := 2; exists(newName); n++ {
+		newName = fmt.Sprintf("%s%s%d", name, suffix, n)
+	}
+	return newName
+}
+
+func (i *archiveImporter) scriptId(id *int64) (*int64, error) {
+	return remapId("script", i.scripts, id)
+}
+
+func (i *archiveImporter) areaId(id *int64) (*int64, error) {
+	return remapId("area", i.areas, id)
+}
+
+func remapId(kind string, ids map[int64]int64, id *int64) (*int64, error) {
+	if id == nil {
+		return nil, nil
+	}
+	newId, ok := ids[*id]
+	if !ok {
+		return nil, fmt.Errorf("%s id:%d is not in the archive: %w", kind, *id, apperr.ErrInvalidRequest)
+	}
+	return common.Int64(newId), nil
+}
+
+// entityId returns the new identifier of the imported entity,
+// the entities that are not in the archive are expected to exist
+func (i *archiveImporter) entityId(id *common.EntityId) *common.EntityId {
+	if id == nil {
+		return nil
+	}
+	if newId, ok := i.entities[*id]; ok {
+		return newId.Ptr()
+	}
+	return id
+}
+
+func (i *archiveImporter) importScripts(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	exists := func(name string) bool {
+		_, err := i.adaptors.Script.GetByName(ctx, name)
+		return err == nil
+	}
+	for _, script := range archive.Scripts {
+		oldId := script.Id
+		existing, getErr := i.adaptors.Script.GetByName(ctx, script.Name)
+		status := i.status(getErr == nil)
+		switch status {
+		case common.ImportStatusSkipped:
+			script.Id = existing.Id
+		case common.ImportStatusUpdated:
+			script.Id = existing.Id
+			if err = i.adaptors.Script.Update(ctx, script); err != nil {
+				return
+			}
+			i.publish(fmt.Sprintf("system/models/scripts/%d", script.Id), events.EventUpdatedScriptModel{
+				Common:    events.Common{Owner: events.OwnerUser},
+				ScriptId:  script.Id,
+				Script:    script,
+				OldScript: existing,
+			})
+		default:
+			if status == common.ImportStatusRenamed {
+				script.Name = uniqueName(script.Name, " [IMPORTED]", exists)
+			}
+			script.Id = 0
+			if script.Id, err = i.adaptors.Script.Add(ctx, script); err != nil {
+				return
+			}
+			i.publish(fmt.Sprintf("system/models/scripts/%d", script.Id), events.EventCreatedScriptModel{
+				Common:   events.Common{Owner: events.OwnerUser},
+				ScriptId: script.Id,
+				Script:   script,
+			})
+		}
+		i.scripts[oldId] = script.Id
+		i.add("script", script.Name, oldId, script.Id, status)
+	}
+	return
+}
+
+func (i *archiveImporter) importAreas(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	exists := func(name string) bool {
+		_, err := i.adaptors.Area.GetByName(ctx, name)
+		return err == nil
+	}
+	for _, area := range archive.Areas {
+		oldId := area.Id
+		existing, getErr := i.adaptors.Area.GetByName(ctx, area.Name)
+		status := i.status(getErr == nil)
+		switch status {
+		case common.ImportStatusSkipped:
+			area.Id = existing.Id
+		case common.ImportStatusUpdated:
+			area.Id = existing.Id
+			if err = i.adaptors.Area.Update(ctx, area); err != nil {
+				return
+			}
+		default:
+			if status == common.ImportStatusRenamed {
+				area.Name = uniqueName(area.Name, " [IMPORTED]", exists)
+			}
+			area.Id = 0
+			if area.Id, err = i.adaptors.Area.Add(ctx, area); err != nil {
+				return
+			}
+		}
+		i.areas[oldId] = area.Id
+		i.add("area", area.Name, oldId, area.Id, status)
+	}
+	return
+}
+
+func (i *archiveImporter) importEntities(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	exists := func(name string) bool {
+		_, err := i.adaptors.Entity.GetById(ctx, common.EntityId(name))
+		return err == nil
+	}
+	for _, entity := range archive.Entities {
+		oldId := entity.Id
+		existing, getErr := i.adaptors.Entity.GetById(ctx, entity.Id)
+		status := i.status(getErr == nil)
+		if status == common.ImportStatusSkipped {
+			i.entities[oldId] = oldId
+			i.add("entity", oldId.String(), oldId, oldId, status)
+			continue
+		}
+		if status == common.ImportStatusRenamed {
+			entity.Id = common.EntityId(uniqueName(oldId.String(), "_imported", exists))
+		}
+		i.entities[oldId] = entity.Id
+
+		if entity.AreaId, err = i.areaId(entity.AreaId); err != nil {
+			return
+		}
+		entity.ParentId = i.entityId(entity.ParentId)
+		for _, script := range entity.Scripts {
+			var id *int64
+			if id, err = i.scriptId(common.Int64(script.Id)); err != nil {
+				return
+			}
+			script.Id = *id
+		}
+		for _, action := range entity.Actions {
+			action.Id = 0
+			action.EntityId = entity.Id
+			if action.ScriptId, err = i.scriptId(action.ScriptId); err != nil {
+				return
+			}
+		}
+		for _, state := range entity.States {
+			state.Id = 0
+			state.EntityId = entity.Id
+		}
+		for _, tag := range entity.Tags {
+			var foundedTag *m.Tag
+			if foundedTag, err = i.adaptors.Tag.GetByName(ctx, tag.Name); err == nil {
+				tag.Id = foundedTag.Id
+			} else {
+				tag.Id = 0
+				if tag.Id, err = i.adaptors.Tag.Add(ctx, tag); err != nil {
+					return
+				}
+			}
+		}
+
+		if status == common.ImportStatusUpdated {
+			if err = i.adaptors.EntityAction.DeleteByEntityId(ctx, entity.Id); err != nil {
+				return
+			}
+			if err = i.adaptors.EntityState.DeleteByEntityId(ctx, entity.Id); err != nil {
+				return
+			}
+			if err = i.adaptors.Entity.DeleteScripts(ctx, entity.Id); err != nil {
+				return
+			}
+			if err = i.adaptors.Entity.DeleteTags(ctx, entity.Id); err != nil {
+				return
+			}
+			// the existing metrics keep their data
+			entity.Metrics = existing.Metrics
+			if existing.Image != nil {
+				entity.ImageId = common.Int64(existing.Image.Id)
+			}
+			if err = i.adaptors.Entity.Update(ctx, entity); err != nil {
+				return
+			}
+			i.publish("system/models/entities/"+entity.Id.String(), events.EventUpdatedEntityModel{
+				EntityId: entity.Id,
+			})
+		} else {
+			for _, metric := range entity.Metrics {
+				metric.Id = 0
+				if metric.Id, err = i.adaptors.Metric.Add(ctx, metric); err != nil {
+					return
+				}
+			}
+			if err = i.adaptors.Entity.Add(ctx, entity); err != nil {
+				return
+			}
+			i.publish("system/models/entities/"+entity.Id.String(), events.EventCreatedEntityModel{
+				EntityId: entity.Id,
+			})
+		}
+		i.add("entity", entity.Id.String(), oldId, entity.Id, status)
+	}
+	return
+}
+
+func (i *archiveImporter) importVariables(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	exists := func(name string) bool {
+		_, err := i.adaptors.Variable.GetByName(ctx, name)
+		return err == nil
+	}
+	for _, variable := range archive.Variables {
+		oldName := variable.Name
+		exist, getErr := i.adaptors.Variable.GetByName(ctx, variable.Name)
+		status := i.status(getErr == nil)
+		if variable.Encrypted && variable.Value == m.EncryptedVariableMask {
+			// the archive never carries secrets, keep the local value
+			variable.Value = exist.Value
+		}
+		if status != common.ImportStatusSkipped {
+			if status == common.ImportStatusRenamed {
+				variable.Name = uniqueName(variable.Name, "_imported", exists)
+			}
+			variable.EntityId = i.entityId(variable.EntityId)
+			if err = i.adaptors.Variable.CreateOrUpdate(ctx, variable); err != nil {
+				return
+			}
+			i.publish(fmt.Sprintf("system/models/variables/%s", variable.Name), events.EventUpdatedVariableModel{
+				Name:  variable.Name,
+				Value: variable.MaskedValue(),
+			})
+		}
+		i.add("variable", variable.Name, oldName, variable.Name, status)
+	}
+	return
+}
+
+func (i *archiveImporter) importConditions(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	list, _, err := i.adaptors.Condition.List(ctx, 999, 0, "desc", "id", nil)
+	if err != nil {
+		return
+	}
+	var names = make(map[string]int64)
+	for _, condition := range list {
+		names[condition.Name] = condition.Id
+	}
+	exists := func(name string) bool {
+		_, ok := names[name]
+		return ok
+	}
+
+	for _, condition := range archive.Conditions {
+		oldId := condition.Id
+		existingId, ok := names[condition.Name]
+		status := i.status(ok)
+		if status == common.ImportStatusSkipped {
+			i.conditions[oldId] = existingId
+			i.add("condition", condition.Name, oldId, existingId, status)
+			continue
+		}
+		if condition.ScriptId, err = i.scriptId(condition.ScriptId); err != nil {
+			return
+		}
+		if condition.AreaId, err = i.areaId(condition.AreaId); err != nil {
+			return
+		}
+		i.remapRule(condition.Rule)
+
+		if status == common.ImportStatusUpdated {
+			condition.Id = existingId
+			if err = i.adaptors.Condition.Update(ctx, condition); err != nil {
+				return
+			}
+			i.publish(fmt.Sprintf("system/models/conditions/%d", condition.Id), events.EventUpdatedConditionModel{
+				Id: condition.Id,
+			})
+		} else {
+			if status == common.ImportStatusRenamed {
+				condition.Name = uniqueName(condition.Name, " [IMPORTED]", exists)
+			}
+			condition.Id = 0
+			if condition.Id, err = i.adaptors.Condition.Add(ctx, condition); err != nil {
+				return
+			}
+			names[condition.Name] = condition.Id
+			i.publish(fmt.Sprintf("system/models/conditions/%d", condition.Id), events.EventAddedConditionModel{
+				Id: condition.Id,
+			})
+		}
+		i.conditions[oldId] = condition.Id
+		i.add("condition", condition.Name, oldId, condition.Id, status)
+	}
+	return
+}
+
+func (i *archiveImporter) remapRule(rule *m.ConditionRule) {
+	if rule == nil {
+		return
+	}
+	rule.EntityId = i.entityId(rule.EntityId)
+	for _, nested := range rule.Rules {
+		i.remapRule(nested)
+	}
+}
+
+func (i *archiveImporter) importTriggers(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	list, _, err := i.adaptors.Trigger.ListPlain(ctx, 999, 0, "desc", "id", false, nil)
+	if err != nil {
+		return
+	}
+	var names = make(map[string]int64)
+	for _, trigger := range list {
+		names[trigger.Name] = trigger.Id
+	}
+	exists := func(name string) bool {
+		_, ok := names[name]
+		return ok
+	}
+
+	for _, trigger := range archive.Triggers {
+		oldId := trigger.Id
+		existingId, ok := names[trigger.Name]
+		status := i.status(ok)
+		if status == common.ImportStatusSkipped {
+			i.triggers[oldId] = existingId
+			i.add("trigger", trigger.Name, oldId, existingId, status)
+			continue
+		}
+		var scriptId, areaId *int64
+		if scriptId, err = i.scriptId(trigger.ScriptId); err != nil {
+			return
+		}
+		if areaId, err = i.areaId(trigger.AreaId); err != nil {
+			return
+		}
+		var entityIds = make([]string, 0, len(trigger.Entities))
+		for _, entity := range trigger.Entities {
+			entityIds = append(entityIds, i.entityId(&entity.Id).String())
+		}
+
+		if status == common.ImportStatusUpdated {
+			trigger.Id = existingId
+			if err = i.adaptors.Trigger.DeleteEntity(ctx, trigger.Id); err != nil {
+				return
+			}
+			err = i.adaptors.Trigger.Update(ctx, &m.UpdateTrigger{
+				Id:          trigger.Id,
+				Name:        trigger.Name,
+				PluginName:  trigger.PluginName,
+				Description: trigger.Description,
+				EntityIds:   entityIds,
+				ScriptId:    scriptId,
+				Payload:     trigger.Payload,
+				AreaId:      areaId,
+				Enabled:     trigger.Enabled,
+			})
+			if err != nil {
+				return
+			}
+			i.publish(fmt.Sprintf("system/models/triggers/%d", trigger.Id), events.EventUpdatedTriggerModel{
+				Id: trigger.Id,
+			})
+		} else {
+			if status == common.ImportStatusRenamed {
+				trigger.Name = uniqueName(trigger.Name, " [IMPORTED]", exists)
+			}
+			trigger.Id, err = i.adaptors.Trigger.Add(ctx, &m.NewTrigger{
+				Name:        trigger.Name,
+				PluginName:  trigger.PluginName,
+				Description: trigger.Description,
+				EntityIds:   entityIds,
+				ScriptId:    scriptId,
+				Payload:     trigger.Payload,
+				AreaId:      areaId,
+				Enabled:     trigger.Enabled,
+			})
+			if err != nil {
+				return
+			}
+			names[trigger.Name] = trigger.Id
+			i.publish(fmt.Sprintf("system/models/triggers/%d", trigger.Id), events.EventCreatedTriggerModel{
+				Id: trigger.Id,
+			})
+		}
+		i.triggers[oldId] = trigger.Id
+		i.add("trigger", trigger.Name, oldId, trigger.Id, status)
+	}
+	return
+}
+
+func (i *archiveImporter) importActions(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	list, _, err := i.adaptors.Action.List(ctx, 999, 0, "desc", "id", nil)
+	if err != nil {
+		return
+	}
+	var names = make(map[string]int64)
+	for _, action := range list {
+		names[action.Name] = action.Id
+	}
+	exists := func(name string) bool {
+		_, ok := names[name]
+		return ok
+	}
+
+	for _, action := range archive.Actions {
+		oldId := action.Id
+		existingId, ok := names[action.Name]
+		status := i.status(ok)
+		if status == common.ImportStatusSkipped {
+			i.actions[oldId] = existingId
+			i.add("action", action.Name, oldId, existingId, status)
+			continue
+		}
+		if action.ScriptId, err = i.scriptId(action.ScriptId); err != nil {
+			return
+		}
+		if action.AreaId, err = i.areaId(action.AreaId); err != nil {
+			return
+		}
+		action.EntityId = i.entityId(action.EntityId)
+
+		if status == common.ImportStatusUpdated {
+			action.Id = existingId
+			if err = i.adaptors.Action.Update(ctx, action); err != nil {
+				return
+			}
+			i.publish(fmt.Sprintf("system/models/actions/%d", action.Id), events.EventUpdatedActionModel{
+				Id:     action.Id,
+				Action: action,
+			})
+		} else {
+			if status == common.ImportStatusRenamed {
+				action.Name = uniqueName(action.Name, " [IMPORTED]", exists)
+			}
+			action.Id = 0
+			if action.Id, err = i.adaptors.Action.Add(ctx, action); err != nil {
+				return
+			}
+			names[action.Name] = action.Id
+			i.publish(fmt.Sprintf("system/models/actions/%d", action.Id), events.EventAddedActionModel{
+				Id: action.Id,
+			})
+		}
+		i.actions[oldId] = action.Id
+		i.add("action", action.Name, oldId, action.Id, status)
+	}
+	return
+}
+
+func (i *archiveImporter) importTasks(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	list, _, err := i.adaptors.Task.List(ctx, 999, 0, "desc", "id", false)
+	if err != nil {
+		return
+	}
+	var names = make(map[string]int64)
+	for _, task := range list {
+		names[task.Name] = task.Id
+	}
+	exists := func(name string) bool {
+		_, ok := names[name]
+		return ok
+	}
+
+	for _, task := range archive.Tasks {
+		oldId := task.Id
+		existingId, ok := names[task.Name]
+		status := i.status(ok)
+		if status == common.ImportStatusSkipped {
+			i.add("task", task.Name, oldId, existingId, status)
+			continue
+		}
+
+		newTask := &m.NewTask{
+			Name:        task.Name,
+			Description: task.Description,
+			Condition:   task.Condition,
+			Mode:        task.Mode,
+			MaxRuns:     task.MaxRuns,
+			Steps:       task.Steps,
+			Enabled:     task.Enabled,
+		}
+		if newTask.AreaId, err = i.areaId(task.AreaId); err != nil {
+			return
+		}
+		for _, trigger := range task.Triggers {
+			var id *int64
+			if id, err = remapId("trigger", i.triggers, common.Int64(trigger.Id)); err != nil {
+				return
+			}
+			newTask.TriggerIds = append(newTask.TriggerIds, *id)
+		}
+		for _, condition := range task.Conditions {
+			var id *int64
+			if id, err = remapId("condition", i.conditions, common.Int64(condition.Id)); err != nil {
+				return
+			}
+			newTask.ConditionIds = append(newTask.ConditionIds, *id)
+		}
+		for _, action := range task.Actions {
+			var id *int64
+			if id, err = remapId("action", i.actions, common.Int64(action.Id)); err != nil {
+				return
+			}
+			newTask.ActionIds = append(newTask.ActionIds, *id)
+		}
+		m.WalkTaskSteps(newTask.Steps, func(step *m.TaskStep) {
+			if err == nil {
+				step.ActionId, err = remapId("action", i.actions, step.ActionId)
+			}
+			if err == nil {
+				step.ConditionId, err = remapId("condition", i.conditions, step.ConditionId)
+			}
+			step.EntityId = i.entityId(step.EntityId)
+		})
+		if err != nil {
+			return
+		}
+
+		if status == common.ImportStatusUpdated {
+			task.Id = existingId
+			if err = i.updateTask(ctx, task.Id, newTask); err != nil {
+				return
+			}
+			i.publish(fmt.Sprintf("system/models/tasks/%d", task.Id), events.EventUpdatedTaskModel{
+				Id: task.Id,
+			})
+		} else {
+			if status == common.ImportStatusRenamed {
+				newTask.Name = uniqueName(newTask.Name, " [IMPORTED]", exists)
+			}
+			if task.Id, err = i.adaptors.Task.Add(ctx, newTask); err != nil {
+				return
+			}
+			names[newTask.Name] = task.Id
+			i.publish(fmt.Sprintf("system/models/tasks/%d", task.Id), events.EventCreatedTaskModel{
+				Id: task.Id,
+			})
+		}
+		i.add("task", newTask.Name, oldId, task.Id, status)
+	}
+	return
+}
+
+func (i *archiveImporter) updateTask(ctx context.Context, id int64, params *m.NewTask) (err error) {
+	if err = i.adaptors.Task.DeleteTrigger(ctx, id); err != nil {
+		return
+	}
+	if err = i.adaptors.Task.DeleteCondition(ctx, id); err != nil {
+		return
+	}
+	if err = i.adaptors.Task.DeleteAction(ctx, id); err != nil {
+		return
+	}
+
+	task := &m.Task{
+		Id:          id,
+		Name:        params.Name,
+		Description: params.Description,
+		Enabled:     params.Enabled,
+		Condition:   params.Condition,
+		Mode:        params.Mode,
+		MaxRuns:     params.MaxRuns,
+		AreaId:      params.AreaId,
+		Steps:       params.Steps,
+	}
+	for _, id := range params.TriggerIds {
+		task.Triggers = append(task.Triggers, &m.Trigger{Id: id})
+	}
+	for _, id := range params.ConditionIds {
+		task.Conditions = append(task.Conditions, &m.Condition{Id: id})
+	}
+	for _, id := range params.ActionIds {
+		task.Actions = append(task.Actions, &m.Action{Id: id})
+	}
+	return i.adaptors.Task.Update(ctx, task)
+}
+
+func (i *archiveImporter) importDashboards(ctx context.Context, archive *m.ConfigArchive) (err error) {
+	list, _, err := i.adaptors.Dashboard.List(ctx, 999, 0, "desc", "id")
+	if err != nil {
+		return
+	}
+	var names = make(map[string]int64)
+	for _, board := range list {
+		names[board.Name] = board.Id
+	}
+	exists := func(name string) bool {
+		_, ok := names[name]
+		return ok
+	}
+
+	for _, board := range archive.Dashboards {
+		oldId := board.Id
+		existingId, ok := names[board.Name]
+		status := i.status(ok)
+		if status == common.ImportStatusSkipped {
+			i.add("dashboard", board.Name, oldId, existingId, status)
+			continue
+		}
+		if board.AreaId, err = i.areaId(board.AreaId); err != nil {
+			return
+		}
+
+		if status == common.ImportStatusUpdated {
+			var existing *m.Dashboard
+			if existing, err = i.adaptors.Dashboard.GetById(ctx, existingId); err != nil {
+				return
+			}
+			// the cards and the items are removed with the tabs
+			for _, tab := range existing.Tabs {
+				if err = i.adaptors.DashboardTab.Delete(ctx, tab.Id); err != nil {
+					return
+				}
+			}
+			board.Id = existingId
+			if err = i.adaptors.Dashboard.Update(ctx, board); err != nil {
+				return
+			}
+		} else {
+			if status == common.ImportStatusRenamed {
+				board.Name = uniqueName(board.Name, " [IMPORTED]", exists)
+			}
+			board.Id = 0
+			if board.Id, err = i.adaptors.Dashboard.Add(ctx, board); err != nil {
+				return
+			}
+			names[board.Name] = board.Id
+		}
+		if err = i.addDashboardTabs(ctx, board); err != nil {
+			return
+		}
+		i.add("dashboard", board.Name, oldId, board.Id, status)
+	}
+	return
+}
+
+func (i *archiveImporter) addDashboardTabs(ctx context.Context, board *m.Dashboard) (err error) {
+	for _, tab := range board.Tabs {
+		tab.Id = 0
+		tab.DashboardId = board.Id
+		if tab.Id, err = i.adaptors.DashboardTab.Add(ctx, tab); err != nil {
+			return
+		}
+		for _, card := range tab.Cards {
+			card.Id = 0
+			card.DashboardTabId = tab.Id
+			card.EntityId = i.entityId(card.EntityId)
+			if card.Id, err = i.adaptors.DashboardCard.Add(ctx, card); err != nil {
+				return
+			}
+			for _, item := range card.Items {
+				item.Id = 0
+				item.DashboardCardId = card.Id
+				item.EntityId = i.entityId(item.EntityId)
+				if item.Id, err = i.adaptors.DashboardCardItem.Add(ctx, item); err != nil {
+					return
+				}
+			}
+		}
+	}
+	return
+}
@@ -21,8 +21,10 @@ package endpoint
 import (
 	"context"
 	"errors"
+	"slices"
 
 	"github.com/e154/smart-home/internal/common"
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
 	"github.com/e154/smart-home/pkg/apperr"
 	pkgCommon "github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/models"
@@ -71,7 +73,13 @@ func (d *DashboardEndpoint) GetById(ctx context.Context, id int64) (board *model
 		return
 	}
 
-	err = d.preloadEntities(ctx, board)
+	if err = d.preloadEntities(ctx, board); err != nil {
+		return
+	}
+
+	if rules := d.entityRules(ctx); rules != nil {
+		d.hideEntities(board, rules)
+	}
 
 	return
 }
@@ -187,6 +195,35 @@ func (d *DashboardEndpoint) preloadEntities(ctx context.Context, board *models.D
 	return
 }
 
+// hideEntities removes the cards and the items of the entities the user may not read
+func (d *DashboardEndpoint) hideEntities(board *models.Dashboard, rules *entity_access.Rules) {
+
+	allowed := func(entityId *pkgCommon.EntityId) bool {
+		if entityId == nil {
+			return true
+		}
+		entity, ok := board.Entities[*entityId]
+		return ok && entity != nil && rules.Allowed(entity, pkgCommon.EntityAccessRead)
+	}
+
+	for _, tab := range board.Tabs {
+		tab.Cards = slices.DeleteFunc(tab.Cards, func(card *models.DashboardCard) bool {
+			return !allowed(card.EntityId)
+		})
+		for _, card := range tab.Cards {
+			card.Items = slices.DeleteFunc(card.Items, func(item *models.DashboardCardItem) bool {
+				return !allowed(item.EntityId)
+			})
+		}
+	}
+
+	for entityId, entity := range board.Entities {
+		if entity == nil || !rules.Allowed(entity, pkgCommon.EntityAccessRead) {
+			delete(board.Entities, entityId)
+		}
+	}
+}
+
 // Import ...
 func (d *DashboardEndpoint) Import(ctx context.Context, board *models.Dashboard) (result *models.Dashboard, err error) {
 
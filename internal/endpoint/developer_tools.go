@@ -55,8 +55,12 @@ func (d DeveloperToolsEndpoint) StateList(ctx context.Context) (states []m.Entit
 // EntitySetState ...
 func (d DeveloperToolsEndpoint) EntitySetState(ctx context.Context, entityId string, newState *string, attrs map[string]interface{}) (err error) {
 
-	_, err = d.adaptors.Entity.GetById(ctx, pkgCommon.EntityId(entityId))
-	if err != nil {
+	var entity *m.Entity
+	if entity, err = d.adaptors.Entity.GetById(ctx, pkgCommon.EntityId(entityId)); err != nil {
+		return
+	}
+
+	if err = d.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessControl); err != nil {
 		return
 	}
 
@@ -103,8 +107,12 @@ func (d *DeveloperToolsEndpoint) TaskCallAction(ctx context.Context, id int64, n
 // ReloadEntity ...
 func (d *DeveloperToolsEndpoint) ReloadEntity(ctx context.Context, id pkgCommon.EntityId) (err error) {
 
-	_, err = d.adaptors.Entity.GetById(ctx, id)
-	if err != nil {
+	var entity *m.Entity
+	if entity, err = d.adaptors.Entity.GetById(ctx, id); err != nil {
+		return
+	}
+
+	if err = d.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessEdit); err != nil {
 		return
 	}
 
@@ -118,8 +126,12 @@ func (d *DeveloperToolsEndpoint) ReloadEntity(ctx context.Context, id pkgCommon.
 // EntitySetStateName ...
 func (d *DeveloperToolsEndpoint) EntitySetStateName(ctx context.Context, id pkgCommon.EntityId, name string) (err error) {
 
-	_, err = d.adaptors.Entity.GetById(ctx, id)
-	if err != nil {
+	var entity *m.Entity
+	if entity, err = d.adaptors.Entity.GetById(ctx, id); err != nil {
+		return
+	}
+
+	if err = d.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessControl); err != nil {
 		return
 	}
 
@@ -0,0 +1,135 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/e154/smart-home/internal/system/discovery"
+	"github.com/e154/smart-home/pkg/apperr"
+	pkgCommon "github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// DiscoveryEndpoint ...
+type DiscoveryEndpoint struct {
+	*CommonEndpoint
+	entity    *EntityEndpoint
+	discovery discovery.Discovery
+}
+
+// NewDiscoveryEndpoint ...
+func NewDiscoveryEndpoint(common *CommonEndpoint, discovery discovery.Discovery) *DiscoveryEndpoint {
+	return &DiscoveryEndpoint{
+		CommonEndpoint: common,
+		entity:         NewEntityEndpoint(common),
+		discovery:      discovery,
+	}
+}
+
+// GetList the discovered devices that are not configured yet, the device is configured
+// while the entity accepted for it or its proposed entity exists
+func (n *DiscoveryEndpoint) GetList(ctx context.Context) (list []*discovery.Device, err error) {
+	list = make([]*discovery.Device, 0)
+	for _, device := range n.discovery.Devices() {
+		if device.Plugin == "" {
+			continue
+		}
+		if _, ok := n.acceptedEntity(ctx, device.Id); ok {
+			continue
+		}
+		if _, err = n.adaptors.Entity.GetById(ctx, discovery.NewEntity(device).Id); err == nil {
+			continue
+		}
+		list = append(list, device)
+	}
+	err = nil
+	return
+}
+
+// acceptedEntity returns the entity accepted for the device if it still exists
+func (n *DiscoveryEndpoint) acceptedEntity(ctx context.Context, id string) (entityId pkgCommon.EntityId, ok bool) {
+	if entityId, ok = n.discovery.Accepted(id); !ok {
+		return
+	}
+	_, err := n.adaptors.Entity.GetById(ctx, entityId)
+	ok = err == nil
+	return
+}
+
+// Scan ...
+func (n *DiscoveryEndpoint) Scan(_ context.Context) {
+	n.discovery.Scan()
+}
+
+// Accept adds the entity of the matching plugin for the device,
+// the name replaces the proposed name part of the entity id
+func (n *DiscoveryEndpoint) Accept(ctx context.Context, id string, name, description *string, areaId *int64) (result *m.Entity, err error) {
+
+	var device *discovery.Device
+	if device, err = n.discovery.GetDevice(id); err != nil {
+		return
+	}
+
+	if !n.supervisor.PluginIsLoaded(device.Plugin) {
+		err = fmt.Errorf("%s: %w", device.Plugin, apperr.ErrPluginNotLoaded)
+		return
+	}
+
+	if entityId, ok := n.acceptedEntity(ctx, device.Id); ok {
+		err = fmt.Errorf("%s: %w", entityId, discovery.ErrEntityExists)
+		return
+	}
+
+	entity := discovery.NewEntity(device)
+	if name != nil && *name != "" {
+		entity.Id = pkgCommon.EntityId(fmt.Sprintf("%s.%s", device.Plugin, *name))
+	}
+	if description != nil && *description != "" {
+		entity.Description = *description
+	}
+	entity.AreaId = areaId
+
+	if _, err = n.adaptors.Entity.GetById(ctx, entity.Id); err == nil {
+		err = fmt.Errorf("%s: %w", entity.Id, discovery.ErrEntityExists)
+		return
+	}
+
+	if result, err = n.entity.Add(ctx, entity); err != nil {
+		return
+	}
+
+	if err := n.discovery.Accept(ctx, device.Id, result.Id); err != nil {
+		log.Error(err.Error())
+	}
+
+	return
+}
+
+// Ignore ...
+func (n *DiscoveryEndpoint) Ignore(ctx context.Context, id string) (err error) {
+	if err = n.discovery.Ignore(ctx, id); err != nil {
+		if !errors.Is(err, apperr.ErrNotFound) {
+			err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrInternal)
+		}
+	}
+	return
+}
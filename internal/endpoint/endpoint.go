@@ -20,6 +20,8 @@ package endpoint
 
 import (
 	"github.com/e154/smart-home/internal/system/backup"
+	"github.com/e154/smart-home/internal/system/discovery"
+	"github.com/e154/smart-home/internal/system/exporter"
 	"github.com/e154/smart-home/internal/system/stream"
 	"github.com/e154/smart-home/pkg/logger"
 )
@@ -63,10 +65,17 @@ type Endpoint struct {
 	Backup            *BackupEndpoint
 	Stream            *StreamEndpoint
 	Automation        *AutomationEndpoint
+	ConfigArchive     *ConfigArchiveEndpoint
+	Exporter          *ExporterEndpoint
+	Discovery         *DiscoveryEndpoint
 }
 
 // NewEndpoint ...
-func NewEndpoint(backup *backup.Backup, stream *stream.Stream, common *CommonEndpoint) *Endpoint {
+func NewEndpoint(backup *backup.Backup,
+	stream *stream.Stream,
+	exporter exporter.Exporter,
+	discovery discovery.Discovery,
+	common *CommonEndpoint) *Endpoint {
 	return &Endpoint{
 		AlexaSkill:        NewAlexaSkillEndpoint(common),
 		Auth:              NewAuthEndpoint(common),
@@ -101,5 +110,8 @@ func NewEndpoint(backup *backup.Backup, stream *stream.Stream, common *CommonEnd
 		Backup:            NewBackupEndpoint(common, backup),
 		Stream:            NewStreamEndpoint(common, stream),
 		Automation:        NewAutomationEndpoint(common),
+		ConfigArchive:     NewConfigArchiveEndpoint(common),
+		Exporter:          NewExporterEndpoint(common, exporter),
+		Discovery:         NewDiscoveryEndpoint(common, discovery),
 	}
 }
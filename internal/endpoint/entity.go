@@ -51,6 +51,10 @@ func (n *EntityEndpoint) Add(ctx context.Context, entity *models.Entity) (result
 		return
 	}
 
+	if err = n.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessEdit); err != nil {
+		return
+	}
+
 	err = n.adaptors.Transaction.Do(ctx, func(ctx context.Context) error {
 
 		for _, tag := range entity.Tags {
@@ -90,6 +94,10 @@ func (n *EntityEndpoint) Add(ctx context.Context, entity *models.Entity) (result
 // Import ...
 func (n *EntityEndpoint) Import(ctx context.Context, entity *models.Entity) (err error) {
 
+	if err = n.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessEdit); err != nil {
+		return
+	}
+
 	for _, action := range entity.Actions {
 		if action.Script != nil {
 			var engine scripts.Engine
@@ -226,6 +234,9 @@ func (n *EntityEndpoint) GetById(ctx context.Context, id pkgCommon.EntityId) (re
 	if result, err = n.adaptors.Entity.GetById(ctx, id); err != nil {
 		return
 	}
+	if err = n.checkEntityAccess(ctx, result, pkgCommon.EntityAccessRead); err != nil {
+		return nil, err
+	}
 	result.IsLoaded = n.supervisor.EntityIsLoaded(id)
 	return
 }
@@ -238,6 +249,10 @@ func (n *EntityEndpoint) Update(ctx context.Context, params *models.Entity) (res
 		return
 	}
 
+	if err = n.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessEdit); err != nil {
+		return
+	}
+
 	entity.Description = params.Description
 	entity.PluginName = params.PluginName
 	entity.Icon = params.Icon
@@ -255,6 +270,11 @@ func (n *EntityEndpoint) Update(ctx context.Context, params *models.Entity) (res
 	entity.RestoreState = params.RestoreState
 	entity.AutoLoad = params.AutoLoad
 
+	// the entity must not be moved out of the permissions
+	if err = n.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessEdit); err != nil {
+		return
+	}
+
 	if ok, errs := n.validation.Valid(entity); !ok {
 		err = apperr.ErrValidation
 		apperr.SetValidationErrors(err, errs)
@@ -357,10 +377,21 @@ func (n *EntityEndpoint) Update(ctx context.Context, params *models.Entity) (res
 
 // List ...
 func (n *EntityEndpoint) List(ctx context.Context, pagination common.PageParams, query, plugin *string, areaId *int64, tags *[]string) (entities []*models.Entity, total int64, err error) {
-	entities, total, err = n.adaptors.Entity.ListPlain(ctx, pagination.Limit, pagination.Offset, pagination.Order,
-		pagination.SortBy, false, query, plugin, areaId, tags)
-	if err != nil {
-		return
+	if rules := n.entityRules(ctx); rules != nil {
+		// the permissions are checked in memory, so the page is cut from the whole list
+		if entities, _, err = n.adaptors.Entity.ListPlain(ctx, 999, 0, pagination.Order,
+			pagination.SortBy, false, query, plugin, areaId, tags); err != nil {
+			return
+		}
+		entities = rules.Filter(entities, pkgCommon.EntityAccessRead)
+		total = int64(len(entities))
+		entities = entities[min(pagination.Offset, total):min(pagination.Offset+pagination.Limit, total)]
+	} else {
+		entities, total, err = n.adaptors.Entity.ListPlain(ctx, pagination.Limit, pagination.Offset, pagination.Order,
+			pagination.SortBy, false, query, plugin, areaId, tags)
+		if err != nil {
+			return
+		}
 	}
 	for _, entity := range entities {
 		entity.IsLoaded = n.supervisor.EntityIsLoaded(entity.Id)
@@ -382,6 +413,10 @@ func (n *EntityEndpoint) Delete(ctx context.Context, id pkgCommon.EntityId) (err
 			return err
 		}
 
+		if err = n.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessEdit); err != nil {
+			return err
+		}
+
 		for _, metric := range entity.Metrics {
 			if err = n.adaptors.Metric.Delete(ctx, metric.Id); err != nil {
 				return err
@@ -406,7 +441,22 @@ func (n *EntityEndpoint) Delete(ctx context.Context, id pkgCommon.EntityId) (err
 // Search ...
 func (n *EntityEndpoint) Search(ctx context.Context, query string, limit, offset int64) (result []*models.Entity, total int64, err error) {
 
-	result, total, err = n.adaptors.Entity.Search(ctx, query, limit, offset)
+	if result, total, err = n.adaptors.Entity.Search(ctx, query, limit, offset); err != nil {
+		return
+	}
+
+	if rules := n.entityRules(ctx); rules != nil && len(result) > 0 {
+		// the search result has no tags
+		ids := make([]pkgCommon.EntityId, 0, len(result))
+		for _, entity := range result {
+			ids = append(ids, entity.Id)
+		}
+		if result, err = n.adaptors.Entity.GetByIds(ctx, ids); err != nil {
+			return
+		}
+		result = rules.Filter(result, pkgCommon.EntityAccessRead)
+		total = int64(len(result))
+	}
 	return
 }
 
@@ -424,6 +474,10 @@ func (n *EntityEndpoint) Enable(ctx context.Context, id pkgCommon.EntityId) (err
 		return
 	}
 
+	if err = n.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessEdit); err != nil {
+		return
+	}
+
 	if err = n.adaptors.Entity.UpdateAutoload(ctx, entity.Id, true); err != nil {
 		return
 	}
@@ -451,6 +505,10 @@ func (n *EntityEndpoint) Disable(ctx context.Context, id pkgCommon.EntityId) (er
 		return
 	}
 
+	if err = n.checkEntityAccess(ctx, entity, pkgCommon.EntityAccessEdit); err != nil {
+		return
+	}
+
 	if err = n.adaptors.Entity.UpdateAutoload(ctx, entity.Id, false); err != nil {
 		return
 	}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/e154/smart-home/internal/common"
+	"github.com/e154/smart-home/pkg/apperr"
 	pkgCommon "github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/models"
 )
@@ -44,6 +45,16 @@ func NewEntityStorageEndpoint(common *CommonEndpoint) *EntityStorageEndpoint {
 func (i *EntityStorageEndpoint) GetList(ctx context.Context, entityIds []pkgCommon.EntityId, pagination common.PageParams,
 	startDate, endDate *time.Time) (result *models.EntityStorageList, total int64, err error) {
 
+	if rules := i.entityRules(ctx); rules != nil {
+		if entityIds, err = i.readableEntityIds(ctx, rules, entityIds); err != nil {
+			return
+		}
+		if len(entityIds) == 0 {
+			result = &models.EntityStorageList{}
+			return
+		}
+	}
+
 	keyResult := fmt.Sprintf("entity_storage_%d_%d_%s_%s_%v_%s_%s", pagination.Limit, pagination.Offset,
 		pagination.Order, pagination.SortBy, entityIds, startDate, endDate)
 
@@ -116,3 +127,26 @@ func (i *EntityStorageEndpoint) GetList(ctx context.Context, entityIds []pkgComm
 
 	return
 }
+
+// History ...
+func (i *EntityStorageEndpoint) History(ctx context.Context, query *models.EntityHistoryQuery) (result []*models.EntityHistoryPoint, err error) {
+
+	if len(query.EntityIds) == 0 {
+		err = fmt.Errorf("%s: %w", "empty entity list", apperr.ErrInvalidRequest)
+		return
+	}
+
+	if rules := i.entityRules(ctx); rules != nil {
+		if query.EntityIds, err = i.readableEntityIds(ctx, rules, query.EntityIds); err != nil {
+			return
+		}
+		if len(query.EntityIds) == 0 {
+			result = make([]*models.EntityHistoryPoint, 0)
+			return
+		}
+	}
+
+	result, err = i.adaptors.EntityStorage.History(ctx, query)
+
+	return
+}
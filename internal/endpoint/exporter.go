@@ -0,0 +1,48 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package endpoint
+
+import (
+	"context"
+
+	"github.com/e154/smart-home/internal/system/exporter"
+)
+
+// ExporterEndpoint ...
+type ExporterEndpoint struct {
+	*CommonEndpoint
+	exporter exporter.Exporter
+}
+
+// NewExporterEndpoint ...
+func NewExporterEndpoint(common *CommonEndpoint, exporter exporter.Exporter) *ExporterEndpoint {
+	return &ExporterEndpoint{
+		CommonEndpoint: common,
+		exporter:       exporter,
+	}
+}
+
+// Samples returns the samples of the entities readable by the current user
+func (e *ExporterEndpoint) Samples(ctx context.Context, tags []string, area *string) (samples exporter.Samples, err error) {
+	samples, err = e.exporter.Samples(ctx, exporter.Filter{
+		Tags:  tags,
+		Area:  area,
+		Rules: e.entityRules(ctx),
+	})
+	return
+}
@@ -20,9 +20,12 @@ package endpoint
 
 import (
 	"context"
+	"slices"
 
+	"github.com/e154/smart-home/pkg/apperr"
 	"github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
 )
 
 // InteractEndpoint ...
@@ -42,7 +45,12 @@ func (d InteractEndpoint) EntityCallAction(ctx context.Context, entityId *string
 
 	if entityId != nil {
 		id := common.EntityId(*entityId)
-		if _, err = d.adaptors.Entity.GetById(ctx, id); err != nil {
+		var entity *m.Entity
+		if entity, err = d.adaptors.Entity.GetById(ctx, id); err != nil {
+			return
+		}
+
+		if err = d.checkEntityAccess(ctx, entity, common.EntityAccessControl); err != nil {
 			return
 		}
 
@@ -55,6 +63,36 @@ func (d InteractEndpoint) EntityCallAction(ctx context.Context, entityId *string
 		return
 	}
 
+	if rules := d.entityRules(ctx); rules != nil {
+		// the restricted user may not call the action of all entities
+		if areaId == nil && len(tags) == 0 {
+			err = apperr.ErrEntityAccessForbidden
+			return
+		}
+		// the call is limited to the entities of the area and with all the tags the user may control
+		var list []*m.Entity
+		if list, _, err = d.adaptors.Entity.ListPlain(ctx, 999, 0, "desc", "id", false, nil, nil, areaId, nil); err != nil {
+			return
+		}
+		list = slices.DeleteFunc(list, func(entity *m.Entity) bool {
+			for _, tag := range tags {
+				if !slices.ContainsFunc(entity.Tags, func(t *m.Tag) bool { return t.Name == tag }) {
+					return true
+				}
+			}
+			return false
+		})
+		for _, entity := range rules.Filter(list, common.EntityAccessControl) {
+			d.eventBus.Publish("system/entities/"+entity.Id.String(), events.EventCallEntityAction{
+				PluginName: common.String(entity.Id.PluginName()),
+				EntityId:   entity.Id.Ptr(),
+				ActionName: actionName,
+				Args:       args,
+			})
+		}
+		return
+	}
+
 	d.eventBus.Publish("system/entities/", events.EventCallEntityAction{
 		ActionName: actionName,
 		Args:       args,
@@ -0,0 +1,116 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package endpoint
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/e154/bus"
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"go.uber.org/fx/fxtest"
+)
+
+type entityRepo struct {
+	adaptors.EntityRepo
+	list map[common.EntityId]*m.Entity
+}
+
+func (r *entityRepo) GetById(_ context.Context, id common.EntityId, _ ...bool) (*m.Entity, error) {
+	if entity, ok := r.list[id]; ok {
+		return entity, nil
+	}
+	return nil, apperr.ErrEntityNotFound
+}
+
+type entityPermissionRepo struct {
+	adaptors.EntityPermissionRepo
+	list []*m.EntityPermission
+}
+
+func (r *entityPermissionRepo) GetAllPermissions(_ context.Context, roleName string) (list []*m.EntityPermission, _ error) {
+	for _, permission := range r.list {
+		if permission.RoleName == roleName {
+			list = append(list, permission)
+		}
+	}
+	return
+}
+
+func TestInteractEndpointEntityAccess(t *testing.T) {
+
+	lampId := common.EntityId("zigbee2mqtt.lamp")
+	doorId := common.EntityId("sensor.door")
+
+	a := &adaptors.Adaptors{
+		Entity: &entityRepo{list: map[common.EntityId]*m.Entity{
+			lampId: {Id: lampId},
+			doorId: {Id: doorId},
+		}},
+		EntityPermission: &entityPermissionRepo{list: []*m.EntityPermission{
+			{RoleName: "guest", EntityId: &lampId, Level: common.EntityAccessControl},
+			{RoleName: "guest", EntityId: &doorId, Level: common.EntityAccessRead},
+		}},
+	}
+	eventBus := bus.NewBus()
+	endpoint := NewInteractEndpoint(&CommonEndpoint{
+		adaptors:     a,
+		eventBus:     eventBus,
+		entityAccess: entity_access.NewEntityAccessService(fxtest.NewLifecycle(t), a, eventBus),
+	})
+
+	calls := atomic.NewInt32(0)
+	handler := func(_ string, msg interface{}) {
+		if _, ok := msg.(events.EventCallEntityAction); ok {
+			calls.Inc()
+		}
+	}
+	require.NoError(t, eventBus.Subscribe("system/entities/+", handler))
+	defer func() { _ = eventBus.Unsubscribe("system/entities/+", handler) }()
+
+	guest := context.WithValue(context.Background(), "currentUser", &m.User{Id: 2, RoleName: "guest"})
+
+	// the role may read the door only
+	err := endpoint.EntityCallAction(guest, common.String(doorId.String()), "OPEN", nil, nil, nil)
+	require.ErrorIs(t, err, apperr.ErrEntityAccessForbidden)
+
+	err = endpoint.EntityCallAction(guest, common.String(lampId.String()), "ON", nil, nil, nil)
+	require.NoError(t, err)
+
+	// the call of all entities is refused
+	err = endpoint.EntityCallAction(guest, nil, "ON", nil, nil, nil)
+	require.ErrorIs(t, err, apperr.ErrEntityAccessForbidden)
+
+	// the system calls are not limited
+	err = endpoint.EntityCallAction(context.Background(), common.String(doorId.String()), "OPEN", nil, nil, nil)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return calls.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+}
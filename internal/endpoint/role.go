@@ -26,6 +26,7 @@ import (
 	"github.com/e154/smart-home/internal/common"
 	"github.com/e154/smart-home/internal/system/rbac/access_list"
 	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/events"
 	"github.com/e154/smart-home/pkg/models"
 )
 
@@ -85,6 +86,8 @@ func (n *RoleEndpoint) Update(ctx context.Context, params *models.Role) (result
 		return
 	}
 
+	role.RequireTotp = params.RequireTotp
+
 	if params.Parent.Name == "" {
 		role.Parent = nil
 	} else {
@@ -111,6 +114,10 @@ func (n *RoleEndpoint) Update(ctx context.Context, params *models.Role) (result
 		return
 	}
 
+	n.eventBus.Publish("system/models/roles/"+role.Name, events.EventUpdatedRoleModel{
+		Name: role.Name,
+	})
+
 	log.Infof("updated role %s", params.Name)
 
 	return
@@ -140,6 +147,10 @@ func (n *RoleEndpoint) Delete(ctx context.Context, name string) (err error) {
 		return
 	}
 
+	n.eventBus.Publish("system/models/roles/"+name, events.EventUpdatedRoleModel{
+		Name: name,
+	})
+
 	log.Infof("role %s was deleted", name)
 
 	return
@@ -232,3 +243,88 @@ func (n *RoleEndpoint) UpdateAccessList(ctx context.Context, roleName string, ac
 
 	return
 }
+
+// GetEntityPermissions ...
+func (n *RoleEndpoint) GetEntityPermissions(ctx context.Context, roleName string) (list []*models.EntityPermission, err error) {
+
+	if _, err = n.adaptors.Role.GetByName(ctx, roleName); err != nil {
+		return
+	}
+
+	list, err = n.adaptors.EntityPermission.List(ctx, roleName)
+
+	return
+}
+
+// AddEntityPermission ...
+func (n *RoleEndpoint) AddEntityPermission(ctx context.Context, params *models.EntityPermission) (result *models.EntityPermission, err error) {
+
+	if params.RoleName == "admin" {
+		err = apperr.ErrRoleUpdateForbidden
+		return
+	}
+
+	if ok, errs := n.validation.Valid(params); !ok {
+		err = apperr.ErrValidation
+		apperr.SetValidationErrors(err, errs)
+		return
+	}
+
+	var targets int
+	for _, set := range []bool{params.EntityId != nil, params.AreaId != nil, params.Tag != nil} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		err = fmt.Errorf("one of entity_id, area_id or tag is required: %w", apperr.ErrInvalidRequest)
+		return
+	}
+
+	if _, err = n.adaptors.Role.GetByName(ctx, params.RoleName); err != nil {
+		return
+	}
+
+	var id int64
+	if id, err = n.adaptors.EntityPermission.Add(ctx, params); err != nil {
+		return
+	}
+
+	if result, err = n.adaptors.EntityPermission.GetById(ctx, id); err != nil {
+		return
+	}
+
+	n.eventBus.Publish("system/models/roles/"+params.RoleName, events.EventUpdatedRoleModel{
+		Name: params.RoleName,
+	})
+
+	log.Infof("added entity permission id:(%d) for role %s", id, params.RoleName)
+
+	return
+}
+
+// DeleteEntityPermission ...
+func (n *RoleEndpoint) DeleteEntityPermission(ctx context.Context, roleName string, id int64) (err error) {
+
+	var permission *models.EntityPermission
+	if permission, err = n.adaptors.EntityPermission.GetById(ctx, id); err != nil {
+		return
+	}
+
+	if permission.RoleName != roleName {
+		err = fmt.Errorf("id \"%d\": %w", id, apperr.ErrEntityPermissionNotFound)
+		return
+	}
+
+	if err = n.adaptors.EntityPermission.Delete(ctx, id); err != nil {
+		return
+	}
+
+	n.eventBus.Publish("system/models/roles/"+roleName, events.EventUpdatedRoleModel{
+		Name: roleName,
+	})
+
+	log.Infof("entity permission id:(%d) of role %s was deleted", id, roleName)
+
+	return
+}
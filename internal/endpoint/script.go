@@ -20,6 +20,7 @@ package endpoint
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -225,6 +226,10 @@ func (n *ScriptEndpoint) DeleteScriptById(ctx context.Context, scriptId int64) (
 // Execute ...
 func (n *ScriptEndpoint) Execute(ctx context.Context, scriptId int64) (result string, err error) {
 
+	if err = n.checkScriptAccess(ctx); err != nil {
+		return
+	}
+
 	var script *models.Script
 	script, err = n.adaptors.Script.GetById(ctx, scriptId)
 	if err != nil {
@@ -238,7 +243,7 @@ func (n *ScriptEndpoint) Execute(ctx context.Context, scriptId int64) (result st
 	}
 
 	result, err = engine.DoFull()
-	if err != nil {
+	if err != nil && !errors.Is(err, apperr.ErrInternal) {
 		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrInternal)
 	}
 
@@ -248,6 +253,10 @@ func (n *ScriptEndpoint) Execute(ctx context.Context, scriptId int64) (result st
 // ExecuteSource ...
 func (n *ScriptEndpoint) ExecuteSource(ctx context.Context, script *models.Script) (result string, err error) {
 
+	if err = n.checkScriptAccess(ctx); err != nil {
+		return
+	}
+
 	var engine scripts2.Engine
 	if engine, err = n.scriptService.NewEngine(script); err != nil {
 		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrInternal)
@@ -260,13 +269,21 @@ func (n *ScriptEndpoint) ExecuteSource(ctx context.Context, script *models.Scrip
 	}
 
 	result, err = engine.DoFull()
-	if err != nil {
+	if err != nil && !errors.Is(err, apperr.ErrInternal) {
 		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrInternal)
 	}
 
 	return
 }
 
+// checkScriptAccess the script may call any entity, so the user limited by the entity permissions can't run it
+func (n *ScriptEndpoint) checkScriptAccess(ctx context.Context) error {
+	if n.entityRules(ctx) != nil {
+		return fmt.Errorf("script execution: %w", apperr.ErrEntityAccessForbidden)
+	}
+	return nil
+}
+
 // Search ...
 func (n *ScriptEndpoint) Search(ctx context.Context, query string, limit, offset int64) (devices []*models.Script, total int64, err error) {
 
@@ -44,7 +44,13 @@ func NewStreamEndpoint(common *CommonEndpoint, stream *stream.Stream) *StreamEnd
 	}
 }
 
-func (s *StreamEndpoint) Subscribe(ctx echo.Context, currentUser *m.User) error {
+func (s *StreamEndpoint) Subscribe(ctx echo.Context, currentUser *m.User, accessToken string) error {
+
+	// the connection is closed when the session of the token is revoked
+	var sessionId int64
+	if claims, _ := s.jwtManager.Verify(accessToken); claims != nil {
+		sessionId = claims.SessionId
+	}
 
 	upgrader.CheckOrigin = func(r *http.Request) bool {
 		return true
@@ -55,7 +61,7 @@ func (s *StreamEndpoint) Subscribe(ctx echo.Context, currentUser *m.User) error
 	}
 	defer ws.Close()
 
-	s.stream.NewConnection(ws, currentUser)
+	s.stream.NewConnection(ws, currentUser, sessionId)
 
 	return nil
 }
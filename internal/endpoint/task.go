@@ -169,7 +169,10 @@ func (n *TaskEndpoint) Update(ctx context.Context, task *models.UpdateTask) (res
 			Description: task.Description,
 			Enabled:     task.Enabled,
 			Condition:   task.Condition,
+			Mode:        task.Mode,
+			MaxRuns:     task.MaxRuns,
 			AreaId:      task.AreaId,
+			Steps:       task.Steps,
 		}
 
 		//triggers
@@ -273,3 +276,43 @@ func (n *TaskEndpoint) List(ctx context.Context, pagination common.PageParams) (
 	}
 	return
 }
+
+// RunList ...
+func (n *TaskEndpoint) RunList(ctx context.Context, taskId int64, pagination common.PageParams) (runs []*models.TaskRun, total int64, err error) {
+
+	if _, err = n.adaptors.Task.GetById(ctx, taskId); err != nil {
+		return
+	}
+
+	runs, total, err = n.adaptors.TaskRun.List(ctx, pagination.Limit, pagination.Offset, pagination.Order, pagination.SortBy, taskId)
+	return
+}
+
+// GetRunById ...
+func (n *TaskEndpoint) GetRunById(ctx context.Context, id int64) (run *models.TaskRun, err error) {
+	run, err = n.adaptors.TaskRun.GetById(ctx, id)
+	return
+}
+
+// ReplayRun ...
+func (n *TaskEndpoint) ReplayRun(ctx context.Context, id int64) (err error) {
+
+	var run *models.TaskRun
+	if run, err = n.adaptors.TaskRun.GetById(ctx, id); err != nil {
+		return
+	}
+
+	if !n.automation.TaskIsLoaded(run.TaskId) {
+		err = fmt.Errorf("task id:%d: %w", run.TaskId, apperr.ErrTaskNotLoaded)
+		return
+	}
+
+	n.eventBus.Publish(fmt.Sprintf("system/automation/tasks/%d", run.TaskId), events.CommandReplayTaskRun{
+		Id:  run.TaskId,
+		Run: run,
+	})
+
+	log.Infof("replay task id:(%d) run id:(%d)", run.TaskId, run.Id)
+
+	return
+}
@@ -20,12 +20,20 @@ package endpoint
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/e154/smart-home/internal/common"
+	"github.com/e154/smart-home/internal/common/totp"
 	"github.com/e154/smart-home/pkg/apperr"
 	"github.com/e154/smart-home/pkg/models"
 )
 
+const (
+	totpIssuer         = "Smart home"
+	recoveryCodesCount = 10
+)
+
 // UserEndpoint ...
 type UserEndpoint struct {
 	*CommonEndpoint
@@ -212,3 +220,233 @@ func (n *UserEndpoint) UpdateStatus(ctx context.Context, userId int64, newStatus
 
 	return
 }
+
+// GetApiTokens ...
+func (n *UserEndpoint) GetApiTokens(ctx context.Context, userId int64) (list []*models.ApiToken, err error) {
+
+	if err = n.checkCredentialsAccess(ctx, userId); err != nil {
+		return
+	}
+
+	list, err = n.adaptors.ApiToken.List(ctx, userId)
+
+	return
+}
+
+// AddApiToken the plain token is returned only once, the hash of the token is stored
+func (n *UserEndpoint) AddApiToken(ctx context.Context, userId int64, params *models.ApiToken) (result *models.ApiToken, token string, err error) {
+
+	if err = n.checkCredentialsAccess(ctx, userId); err != nil {
+		return
+	}
+
+	if ok, errs := n.validation.Valid(params); !ok {
+		err = apperr.ErrValidation
+		apperr.SetValidationErrors(err, errs)
+		return
+	}
+
+	accessList := n.accessList.List(ctx)
+	for _, scope := range params.Scopes {
+		if !accessList.HasScope(scope) {
+			err = fmt.Errorf("%s: %w", fmt.Sprintf("scope \"%s\"", scope), apperr.ErrInvalidRequest)
+			return
+		}
+	}
+
+	if params.ExpiresAt != nil && !params.ExpiresAt.After(time.Now()) {
+		err = fmt.Errorf("%s: %w", "expires_at in the past", apperr.ErrInvalidRequest)
+		return
+	}
+
+	token = models.ApiTokenPrefix + common.RandomToken(32)
+	apiToken := &models.ApiToken{
+		UserId:    userId,
+		Name:      params.Name,
+		Token:     common.HashToken(token),
+		Scopes:    params.Scopes,
+		ExpiresAt: params.ExpiresAt,
+	}
+
+	var id int64
+	if id, err = n.adaptors.ApiToken.Add(ctx, apiToken); err != nil {
+		return
+	}
+
+	result, err = n.adaptors.ApiToken.GetById(ctx, id)
+
+	log.Infof("added api token \"%s\" for user id:(%d)", apiToken.Name, userId)
+
+	return
+}
+
+// DeleteApiToken ...
+func (n *UserEndpoint) DeleteApiToken(ctx context.Context, userId, id int64) (err error) {
+
+	if err = n.checkCredentialsAccess(ctx, userId); err != nil {
+		return
+	}
+
+	var token *models.ApiToken
+	if token, err = n.adaptors.ApiToken.GetById(ctx, id); err != nil {
+		return
+	}
+
+	if token.UserId != userId {
+		err = fmt.Errorf("%s: %w", fmt.Sprintf("id \"%d\"", id), apperr.ErrApiTokenNotFound)
+		return
+	}
+
+	if err = n.adaptors.ApiToken.Delete(ctx, id); err != nil {
+		return
+	}
+
+	log.Infof("deleted api token \"%s\" of user id:(%d)", token.Name, userId)
+
+	return
+}
+
+// EnrollTotp generates the new secret, the two-factor authentication is enabled after the code is confirmed
+func (n *UserEndpoint) EnrollTotp(ctx context.Context, userId int64) (enrolment *models.TotpEnrolment, err error) {
+
+	if err = n.checkCredentialsAccess(ctx, userId); err != nil {
+		return
+	}
+
+	var user *models.User
+	if user, err = n.adaptors.User.GetById(ctx, userId); err != nil {
+		return
+	}
+
+	var userTotp *models.UserTotp
+	if userTotp, err = n.adaptors.UserTotp.GetByUserId(ctx, userId); err == nil && userTotp.Enabled {
+		err = apperr.ErrUserTotpAlreadyEnabled
+		return
+	}
+
+	secret := totp.NewSecret()
+	if err = n.adaptors.UserTotp.Save(ctx, &models.UserTotp{
+		UserId: userId,
+		Secret: secret,
+	}); err != nil {
+		return
+	}
+
+	enrolment = &models.TotpEnrolment{
+		Secret: secret,
+		Uri:    totp.URI(totpIssuer, user.Email, secret),
+	}
+
+	return
+}
+
+// ConfirmTotp enables the two-factor authentication and returns the recovery codes
+func (n *UserEndpoint) ConfirmTotp(ctx context.Context, userId int64, code string) (recoveryCodes []string, err error) {
+
+	if err = n.checkCredentialsAccess(ctx, userId); err != nil {
+		return
+	}
+
+	var userTotp *models.UserTotp
+	if userTotp, err = n.adaptors.UserTotp.GetByUserId(ctx, userId); err != nil {
+		return
+	}
+
+	if userTotp.Enabled {
+		err = apperr.ErrUserTotpAlreadyEnabled
+		return
+	}
+
+	if err = n.verifyTotp(ctx, userTotp, code); err != nil {
+		return
+	}
+
+	recoveryCodes, userTotp.RecoveryCodes = newRecoveryCodes()
+	userTotp.Enabled = true
+	if err = n.adaptors.UserTotp.Save(ctx, userTotp); err != nil {
+		return
+	}
+
+	log.Infof("two-factor authentication enabled for user id:(%d)", userId)
+
+	return
+}
+
+// DisableTotp the administrator can disable the two-factor authentication without the code
+func (n *UserEndpoint) DisableTotp(ctx context.Context, userId int64, code string) (err error) {
+
+	if err = n.checkCredentialsAccess(ctx, userId); err != nil {
+		return
+	}
+
+	var userTotp *models.UserTotp
+	if userTotp, err = n.adaptors.UserTotp.GetByUserId(ctx, userId); err != nil {
+		return
+	}
+
+	currentUser := n.currentUser(ctx)
+	if currentUser == nil || currentUser.Id == userId || !isAdmin(currentUser) {
+		if err = n.verifyTotp(ctx, userTotp, code); err != nil {
+			return
+		}
+	}
+
+	if err = n.adaptors.UserTotp.Delete(ctx, userId); err != nil {
+		return
+	}
+
+	log.Warnf("two-factor authentication disabled for user id:(%d)", userId)
+
+	return
+}
+
+// RegenerateRecoveryCodes the old recovery codes are not valid anymore
+func (n *UserEndpoint) RegenerateRecoveryCodes(ctx context.Context, userId int64, code string) (recoveryCodes []string, err error) {
+
+	if err = n.checkCredentialsAccess(ctx, userId); err != nil {
+		return
+	}
+
+	var userTotp *models.UserTotp
+	if userTotp, err = n.adaptors.UserTotp.GetByUserId(ctx, userId); err != nil {
+		return
+	}
+
+	if !userTotp.Enabled {
+		err = apperr.ErrUserTotpNotFound
+		return
+	}
+
+	if !totp.Validate(userTotp.Secret, code, time.Now()) {
+		err = apperr.ErrUserTotpInvalid
+		return
+	}
+
+	recoveryCodes, userTotp.RecoveryCodes = newRecoveryCodes()
+	err = n.adaptors.UserTotp.Save(ctx, userTotp)
+
+	return
+}
+
+// checkCredentialsAccess the user manages own credentials, the administrator manages the credentials of all users
+func (n *UserEndpoint) checkCredentialsAccess(ctx context.Context, userId int64) error {
+	currentUser := n.currentUser(ctx)
+	if currentUser == nil || currentUser.Id == userId || isAdmin(currentUser) {
+		return nil
+	}
+	return apperr.ErrUserCredentialsForbidden
+}
+
+func isAdmin(user *models.User) bool {
+	return user.Id == AdminId || user.RoleName == "admin"
+}
+
+// newRecoveryCodes returns the plain codes for the user and the hashes to store
+func newRecoveryCodes() (codes, hashes []string) {
+	for i := 0; i < recoveryCodesCount; i++ {
+		code := common.RandomToken(5)
+		codes = append(codes, code)
+		hashes = append(hashes, common.HashToken(code))
+	}
+	return
+}
@@ -50,13 +50,20 @@ func (v *VariableEndpoint) Add(ctx context.Context, variable m.Variable) (err er
 		return
 	}
 
+	if exist, _err := v.adaptors.Variable.GetByName(ctx, variable.Name); _err == nil && exist.Encrypted {
+		variable.Encrypted = true
+		if variable.Value == m.EncryptedVariableMask {
+			variable.Value = exist.Value
+		}
+	}
+
 	if err = v.CreateOrUpdate(ctx, variable); err != nil {
 		return
 	}
 
 	v.eventBus.Publish(fmt.Sprintf("system/models/variables/%s", variable.Name), events.EventUpdatedVariableModel{
 		Name:  variable.Name,
-		Value: variable.Value,
+		Value: variable.MaskedValue(),
 	})
 
 	log.Infof("added or updated variable %s", variable.Name)
@@ -90,7 +97,9 @@ func (v *VariableEndpoint) Update(ctx context.Context, _variable m.Variable) (er
 			return
 		}
 		oldName = variable.Name
-		variable.Value = _variable.Value
+		if !variable.Encrypted || _variable.Value != m.EncryptedVariableMask {
+			variable.Value = _variable.Value
+		}
 		variable.Tags = _variable.Tags
 	} else {
 		variable.Name = _variable.Name
@@ -104,7 +113,7 @@ func (v *VariableEndpoint) Update(ctx context.Context, _variable m.Variable) (er
 
 	v.eventBus.Publish(fmt.Sprintf("system/models/variables/%s", variable.Name), events.EventUpdatedVariableModel{
 		Name:  variable.Name,
-		Value: variable.Value,
+		Value: variable.MaskedValue(),
 	})
 
 	if oldName != variable.Name {
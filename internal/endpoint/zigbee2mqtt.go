@@ -94,6 +94,7 @@ func (n *Zigbee2mqttEndpoint) UpdateBridge(ctx context.Context, params *models.Z
 	bridge.Login = params.Login
 	bridge.Password = params.Password
 	bridge.PermitJoin = params.PermitJoin
+	bridge.AutoDiscovery = params.AutoDiscovery
 
 	if ok, errs := n.validation.Valid(params); !ok {
 		err = apperr.ErrValidation
@@ -252,3 +253,97 @@ func (n *Zigbee2mqttEndpoint) DeviceList(ctx context.Context, bridgeId int64, pa
 
 	return
 }
+
+// AddGroup ...
+func (n *Zigbee2mqttEndpoint) AddGroup(ctx context.Context, bridgeId int64, name string) (err error) {
+
+	if name == "" {
+		err = fmt.Errorf("empty group name: %w", apperr.ErrInvalidRequest)
+		return
+	}
+
+	err = n.bridgeRequestError(n.zigbee2mqtt.AddGroup(ctx, bridgeId, name))
+
+	return
+}
+
+// RemoveGroup ...
+func (n *Zigbee2mqttEndpoint) RemoveGroup(ctx context.Context, bridgeId int64, name string) (err error) {
+
+	err = n.bridgeRequestError(n.zigbee2mqtt.RemoveGroup(ctx, bridgeId, name))
+
+	return
+}
+
+// GroupAddMember ...
+func (n *Zigbee2mqttEndpoint) GroupAddMember(ctx context.Context, bridgeId int64, group, device string) (err error) {
+
+	err = n.bridgeRequestError(n.zigbee2mqtt.GroupAddMember(ctx, bridgeId, group, device))
+
+	return
+}
+
+// GroupRemoveMember ...
+func (n *Zigbee2mqttEndpoint) GroupRemoveMember(ctx context.Context, bridgeId int64, group, device string) (err error) {
+
+	err = n.bridgeRequestError(n.zigbee2mqtt.GroupRemoveMember(ctx, bridgeId, group, device))
+
+	return
+}
+
+// DeviceOtaCheck ...
+func (n *Zigbee2mqttEndpoint) DeviceOtaCheck(ctx context.Context, bridgeId int64, friendlyName string) (updateAvailable bool, err error) {
+
+	updateAvailable, err = n.zigbee2mqtt.DeviceOtaCheck(ctx, bridgeId, friendlyName)
+	err = n.bridgeRequestError(err)
+
+	return
+}
+
+// DeviceOtaUpdate starts the update, the progress is sent to the clients of the stream
+func (n *Zigbee2mqttEndpoint) DeviceOtaUpdate(ctx context.Context, bridgeId int64, friendlyName string) (err error) {
+
+	err = n.bridgeRequestError(n.zigbee2mqtt.DeviceOtaUpdate(ctx, bridgeId, friendlyName))
+
+	return
+}
+
+// DeviceOptions ...
+func (n *Zigbee2mqttEndpoint) DeviceOptions(ctx context.Context, bridgeId int64, friendlyName string, options map[string]interface{}) (err error) {
+
+	if len(options) == 0 {
+		err = fmt.Errorf("empty options: %w", apperr.ErrInvalidRequest)
+		return
+	}
+
+	err = n.bridgeRequestError(n.zigbee2mqtt.DeviceOptions(ctx, bridgeId, friendlyName, options))
+
+	return
+}
+
+// DeviceBind ...
+func (n *Zigbee2mqttEndpoint) DeviceBind(ctx context.Context, bridgeId int64, from, to string, clusters []string) (err error) {
+
+	err = n.bridgeRequestError(n.zigbee2mqtt.DeviceBind(ctx, bridgeId, from, to, clusters))
+
+	return
+}
+
+// DeviceUnbind ...
+func (n *Zigbee2mqttEndpoint) DeviceUnbind(ctx context.Context, bridgeId int64, from, to string, clusters []string) (err error) {
+
+	err = n.bridgeRequestError(n.zigbee2mqtt.DeviceUnbind(ctx, bridgeId, from, to, clusters))
+
+	return
+}
+
+// bridgeRequestError the errors of the bridge keep their kind, the unknown errors are internal
+func (n *Zigbee2mqttEndpoint) bridgeRequestError(err error) error {
+	if err == nil ||
+		errors.Is(err, apperr.ErrNotFound) ||
+		errors.Is(err, apperr.ErrInvalidRequest) ||
+		errors.Is(err, apperr.ErrInternal) {
+		return err
+	}
+	return fmt.Errorf("%s: %w", err.Error(), apperr.ErrInternal)
+}
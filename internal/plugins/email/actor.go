@@ -56,7 +56,7 @@ func NewActor(entity *m.Entity,
 		Smtp:      entity.Settings[AttrSmtp].String(),
 		Port:      entity.Settings[AttrPort].Int64(),
 		Sender:    entity.Settings[AttrSender].String(),
-		notify:    notify2.NewNotify(service.Adaptors()),
+		notify:    notify2.NewNotify(service.Adaptors(), service.EventBus()),
 	}
 
 	return actor
@@ -70,7 +70,7 @@ func (e *Actor) Destroy() {
 // Spawn ...
 func (e *Actor) Spawn() {
 	e.Service.EventBus().Subscribe(notify2.TopicNotify, e.eventHandler, false)
-	e.notify.Start()
+	e.notify.Start(e, Name, &e.Id)
 }
 
 // Send ...
@@ -66,8 +66,8 @@ func (p *plugin) Load(ctx context.Context, service plugins.Service) (err error)
 	if err = p.Plugin.Load(ctx, service, nil); err != nil {
 		return
 	}
-	p.notify = notify2.NewNotify(service.Adaptors())
-	p.notify.Start()
+	p.notify = notify2.NewNotify(service.Adaptors(), service.EventBus())
+	p.notify.Start(p, Name, nil)
 	_ = p.Service.EventBus().Subscribe(notify2.TopicNotify, p.eventHandler, false)
 	return
 }
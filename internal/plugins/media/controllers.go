@@ -19,22 +19,32 @@
 package media
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/deepch/vdk/format/mp4f"
 	"github.com/e154/smart-home/internal/plugins/media/server"
+	"github.com/e154/smart-home/pkg/apperr"
 	"github.com/gobwas/ws"
 	"github.com/gobwas/ws/wsutil"
 )
 
 // ControllerMedia ...
 type ControllerMedia struct {
+	media *server.Media
 }
 
 // NewControllerMedia ...
-func NewControllerMedia() *ControllerMedia {
-	return &ControllerMedia{}
+func NewControllerMedia(media *server.Media) *ControllerMedia {
+	return &ControllerMedia{
+		media: media,
+	}
 }
 
 func (c ControllerMedia) StreamMSE(w http.ResponseWriter, r *http.Request) {
@@ -187,3 +197,98 @@ func (c ControllerMedia) StreamHLSLLM4Segment(w http.ResponseWriter, r *http.Req
 
 func (c ControllerMedia) StreamHLSLLM4Fragment(w http.ResponseWriter, r *http.Request) {
 }
+
+// RecordTimeline returns the recorded segments of the range, the last day by default
+func (c ControllerMedia) RecordTimeline(w http.ResponseWriter, r *http.Request) {
+	segments, err := c.recordSegments(r)
+	if err != nil {
+		c.recordError(w, err)
+		return
+	}
+	if segments == nil {
+		segments = []server.RecordSegment{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"segments": segments,
+	})
+}
+
+// RecordPlaylist returns the vod hls playlist of the range
+func (c ControllerMedia) RecordPlaylist(w http.ResponseWriter, r *http.Request) {
+	segments, err := c.recordSegments(r)
+	if err != nil {
+		c.recordError(w, err)
+		return
+	}
+	if len(segments) == 0 {
+		c.recordError(w, server.ErrorRecordNotFound)
+		return
+	}
+	// the players do not pass the token to the segment urls
+	var query string
+	if token := r.URL.Query().Get("access_token"); token != "" {
+		query = url.Values{"access_token": []string{token}}.Encode()
+	}
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	_, _ = w.Write([]byte(server.RecordPlaylist(segments, query)))
+}
+
+// RecordSegment returns the segment as mpeg-ts for the playlist or as the mp4 file
+func (c ControllerMedia) RecordSegment(w http.ResponseWriter, r *http.Request) {
+	segment := r.PathValue("segment")
+	ext := filepath.Ext(segment)
+	if ext != ".ts" && ext != ".mp4" {
+		c.recordError(w, server.ErrorRecordInvalidPath)
+		return
+	}
+
+	path, err := server.RecordSegmentPath(c.media.RecordsDir(), r.PathValue("entity_id"), r.PathValue("channel"), strings.TrimSuffix(segment, ext)+".mp4")
+	if err != nil {
+		c.recordError(w, err)
+		return
+	}
+
+	if ext == ".mp4" {
+		http.ServeFile(w, r, path)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = server.RecordSegmentTS(path, &buf); err != nil {
+		log.Error(err.Error())
+		c.recordError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "video/mp2t")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (c ControllerMedia) recordSegments(r *http.Request) ([]server.RecordSegment, error) {
+	to := time.Now()
+	if value := r.URL.Query().Get("to"); value != "" {
+		var err error
+		if to, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, server.ErrorRecordInvalidPath
+		}
+	}
+	from := to.Add(-24 * time.Hour)
+	if value := r.URL.Query().Get("from"); value != "" {
+		var err error
+		if from, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, server.ErrorRecordInvalidPath
+		}
+	}
+	return server.RecordSegments(c.media.RecordsDir(), r.PathValue("entity_id"), r.PathValue("channel"), from, to)
+}
+
+func (c ControllerMedia) recordError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperr.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, apperr.ErrInvalidRequest):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -23,8 +23,10 @@ import (
 	"embed"
 	"net/http"
 
+	"github.com/e154/smart-home/internal/common"
 	"github.com/e154/smart-home/internal/plugins/media/server"
 	"github.com/e154/smart-home/internal/system/supervisor"
+	pkgCommon "github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/logger"
 	m "github.com/e154/smart-home/pkg/models"
 	"github.com/e154/smart-home/pkg/plugins"
@@ -65,12 +67,12 @@ func (p *plugin) Load(ctx context.Context, service plugins.Service) (err error)
 		return
 	}
 
-	p.server = server.NewMedia(service.EventBus())
+	p.server = server.NewMedia(service.EventBus(), common.RecordsPath())
 	if err = p.server.Start(); err != nil {
 		return
 	}
 
-	controller := NewControllerMedia()
+	controller := NewControllerMedia(p.server)
 	p.router = http.NewServeMux()
 
 	//a.echo.Any("/stream/:entity_id/channel/:channel/mse", a.echoFilter.Auth(a.controllers.StreamMSE)) //Auth
@@ -79,7 +81,15 @@ func (p *plugin) Load(ctx context.Context, service plugins.Service) (err error)
 	//a.echo.Any("/stream/:entity_id/channel/:channel/hlsll/live/segment/:segment/:any", a.controllers.Media.StreamHLSLLM4Segment)
 	//a.echo.Any("/stream/:entity_id/channel/:channel/hlsll/live/fragment/:segment/:fragment/:any", a.controllers.Media.StreamHLSLLM4Fragment)
 
-	p.router.Handle("/media/{entity_id}/channel/{channel}/mse", p.Service.HttpAccessFilter().Auth(http.HandlerFunc(controller.StreamMSE)))
+	filter := p.Service.HttpAccessFilter()
+	entityAuth := func(handler http.HandlerFunc) http.Handler {
+		return filter.Auth(filter.EntityAuth(pkgCommon.EntityAccessRead, handler))
+	}
+
+	p.router.Handle("/media/{entity_id}/channel/{channel}/mse", entityAuth(controller.StreamMSE))
+	p.router.Handle("GET /media/{entity_id}/channel/{channel}/records", entityAuth(controller.RecordTimeline))
+	p.router.Handle("GET /media/{entity_id}/channel/{channel}/records/index.m3u8", entityAuth(controller.RecordPlaylist))
+	p.router.Handle("GET /media/{entity_id}/channel/{channel}/records/segment/{segment}", entityAuth(controller.RecordSegment))
 
 	return nil
 }
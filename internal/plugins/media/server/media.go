@@ -20,6 +20,7 @@ package server
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/e154/smart-home/pkg/logger"
 
@@ -31,13 +32,18 @@ var (
 )
 
 type Media struct {
-	storage  *StorageST
-	eventBus bus.Bus
+	storage     *StorageST
+	eventBus    bus.Bus
+	recordsDir  string
+	recordersMu sync.Mutex
+	recorders   map[string]*Recorder
 }
 
-func NewMedia(eventBus bus.Bus) *Media {
+func NewMedia(eventBus bus.Bus, recordsDir string) *Media {
 	rtsp := &Media{
-		eventBus: eventBus,
+		eventBus:   eventBus,
+		recordsDir: recordsDir,
+		recorders:  make(map[string]*Recorder),
 	}
 
 	return rtsp
@@ -54,6 +60,13 @@ func (r *Media) Start() (err error) {
 func (r *Media) Shutdown() (err error) {
 	_ = r.eventBus.Unsubscribe("system/media/#", r.eventHandler)
 
+	r.recordersMu.Lock()
+	for name, recorder := range r.recorders {
+		recorder.Stop()
+		delete(r.recorders, name)
+	}
+	r.recordersMu.Unlock()
+
 	Storage.StopAll()
 	return
 }
@@ -66,6 +79,51 @@ func (r *Media) eventHandler(_ string, message interface{}) {
 		go r.eventRemoveList(event)
 	case EventUpdateList:
 		go r.eventUpdateList(event)
+	case EventMotion:
+		if recorder := r.recorder(event.Name); recorder != nil {
+			recorder.SetMotion(event.State)
+		}
+	case EventRecordClip:
+		if recorder := r.recorder(event.Name); recorder != nil {
+			recorder.RecordClip(event.Duration)
+		}
+	}
+}
+
+// RecordsDir ...
+func (r *Media) RecordsDir() string {
+	return r.recordsDir
+}
+
+func (r *Media) recorder(name string) *Recorder {
+	r.recordersMu.Lock()
+	defer r.recordersMu.Unlock()
+	return r.recorders[name]
+}
+
+func (r *Media) updateRecorder(name string, config RecordConfig) {
+	r.recordersMu.Lock()
+	defer r.recordersMu.Unlock()
+	if recorder, ok := r.recorders[name]; ok {
+		recorder.SetConfig(config)
+		return
+	}
+	recorder, err := NewRecorder(r.recordsDir, name, recordChannel, config)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	recorder.Start()
+	r.recorders[name] = recorder
+}
+
+func (r *Media) removeRecorder(name string) {
+	r.recordersMu.Lock()
+	recorder, ok := r.recorders[name]
+	delete(r.recorders, name)
+	r.recordersMu.Unlock()
+	if ok {
+		recorder.Stop()
 	}
 }
 
@@ -73,6 +131,7 @@ func (r *Media) eventRemoveList(event EventRemoveList) {
 	if event.Name == "" {
 		return
 	}
+	r.removeRecorder(event.Name)
 	if err := Storage.StreamDelete(event.Name); err != nil {
 		log.Error(err.Error())
 	}
@@ -101,4 +160,8 @@ func (r *Media) eventUpdateList(event EventUpdateList) {
 			log.Error(err.Error())
 		}
 	}
+
+	if event.Record != nil {
+		r.updateRecorder(event.Name, *event.Record)
+	}
 }
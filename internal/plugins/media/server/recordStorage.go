@@ -0,0 +1,225 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deepch/vdk/format/mp4"
+	"github.com/deepch/vdk/format/ts"
+
+	"github.com/e154/smart-home/pkg/apperr"
+)
+
+var (
+	ErrorRecordNotFound    = apperr.ErrorWithCode("RECORD_NOT_FOUND", "record not found", apperr.ErrNotFound)
+	ErrorRecordInvalidPath = apperr.ErrorWithCode("RECORD_INVALID_PATH", "record invalid path", apperr.ErrInvalidRequest)
+)
+
+var (
+	recordNameRe    = regexp.MustCompile(`^[\w\-.]+$`)
+	recordSegmentRe = regexp.MustCompile(`^(\d+)_(\d+)\.mp4$`)
+)
+
+// RecordSegment is the finished recording file, the name holds the start and the duration in milliseconds
+type RecordSegment struct {
+	Name     string    `json:"name"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Duration float64   `json:"duration"`
+	Size     int64     `json:"size"`
+}
+
+// RecordDir of the stream channel
+func RecordDir(root, streamID, channelID string) (string, error) {
+	for _, name := range []string{streamID, channelID} {
+		if !recordNameRe.MatchString(name) || name == "." || name == ".." {
+			return "", ErrorRecordInvalidPath
+		}
+	}
+	return filepath.Join(root, streamID, channelID), nil
+}
+
+// RecordSegmentPath of the segment file, the name is validated
+func RecordSegmentPath(root, streamID, channelID, name string) (string, error) {
+	dir, err := RecordDir(root, streamID, channelID)
+	if err != nil {
+		return "", err
+	}
+	if !recordSegmentRe.MatchString(name) {
+		return "", ErrorRecordInvalidPath
+	}
+	path := filepath.Join(dir, name)
+	if !fileExist(path) {
+		return "", ErrorRecordNotFound
+	}
+	return path, nil
+}
+
+// RecordSegments overlapping the range, sorted by the start
+func RecordSegments(root, streamID, channelID string, from, to time.Time) (segments []RecordSegment, err error) {
+	var dir string
+	if dir, err = RecordDir(root, streamID, channelID); err != nil {
+		return
+	}
+	var all []RecordSegment
+	if all, err = readSegments(dir); err != nil {
+		return
+	}
+	for _, segment := range all {
+		if segment.End.Before(from) || segment.Start.After(to) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return
+}
+
+// RecordPlaylist is the vod hls playlist of the segments, the query is added to the segment urls
+func RecordPlaylist(segments []RecordSegment, query string) string {
+	var target float64 = 1
+	for _, segment := range segments {
+		target = math.Max(target, math.Ceil(segment.Duration))
+	}
+	if query != "" {
+		query = "?" + query
+	}
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-PLAYLIST-TYPE:VOD\n#EXT-X-MEDIA-SEQUENCE:0\n")
+	b.WriteString("#EXT-X-TARGETDURATION:" + strconv.Itoa(int(target)) + "\n")
+	for i, segment := range segments {
+		// the timestamps of every segment start from zero
+		if i > 0 {
+			b.WriteString("#EXT-X-DISCONTINUITY\n")
+		}
+		b.WriteString("#EXT-X-PROGRAM-DATE-TIME:" + segment.Start.UTC().Format("2006-01-02T15:04:05.000Z") + "\n")
+		b.WriteString("#EXTINF:" + strconv.FormatFloat(segment.Duration, 'f', 3, 64) + ",\n")
+		b.WriteString("segment/" + strings.TrimSuffix(segment.Name, ".mp4") + ".ts" + query + "\n")
+	}
+	b.WriteString("#EXT-X-ENDLIST\n")
+	return b.String()
+}
+
+// RecordSegmentTS remuxes the mp4 segment to mpeg-ts
+func RecordSegmentTS(path string, w io.Writer) (err error) {
+	var file *os.File
+	if file, err = os.Open(path); err != nil {
+		return
+	}
+	defer file.Close()
+
+	demuxer := mp4.NewDemuxer(file)
+	muxer := ts.NewMuxer(w)
+	streams, err := demuxer.Streams()
+	if err != nil {
+		return
+	}
+	if err = muxer.WriteHeader(streams); err != nil {
+		return
+	}
+	for {
+		pkt, err := demuxer.ReadPacket()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err = muxer.WritePacket(pkt); err != nil {
+			return err
+		}
+	}
+	return muxer.WriteTrailer()
+}
+
+func readSegments(dir string) (segments []RecordSegment, err error) {
+	var entries []os.DirEntry
+	if entries, err = os.ReadDir(dir); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	for _, entry := range entries {
+		match := recordSegmentRe.FindStringSubmatch(entry.Name())
+		if match == nil || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		start, _ := strconv.ParseInt(match[1], 10, 64)
+		duration, _ := strconv.ParseInt(match[2], 10, 64)
+		segments = append(segments, RecordSegment{
+			Name:     entry.Name(),
+			Start:    time.UnixMilli(start),
+			End:      time.UnixMilli(start + duration),
+			Duration: float64(duration) / 1000,
+			Size:     info.Size(),
+		})
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Start.Before(segments[j].Start)
+	})
+	return
+}
+
+func segmentName(start time.Time, duration time.Duration) string {
+	return fmt.Sprintf("%d_%d.mp4", start.UnixMilli(), duration.Milliseconds())
+}
+
+// cleanRecords removes the segments older than the max age, then the oldest ones above the max size
+func cleanRecords(dir string, maxAge time.Duration, maxSize int64, now time.Time) (err error) {
+	var segments []RecordSegment
+	if segments, err = readSegments(dir); err != nil {
+		return
+	}
+	var total int64
+	for _, segment := range segments {
+		total += segment.Size
+	}
+	for _, segment := range segments {
+		expired := maxAge > 0 && segment.End.Before(now.Add(-maxAge))
+		oversize := maxSize > 0 && total > maxSize
+		if !expired && !oversize {
+			break
+		}
+		if err = os.Remove(filepath.Join(dir, segment.Name)); err != nil {
+			return
+		}
+		total -= segment.Size
+	}
+	return
+}
+
+func fileExist(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
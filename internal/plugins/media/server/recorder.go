@@ -0,0 +1,449 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/deepch/vdk/av"
+	"github.com/deepch/vdk/format/mp4"
+)
+
+const (
+	// recordChannel is the recorded channel of the stream
+	recordChannel = "0"
+	// recordNoPacketTimeout the client is added again after the channel was edited or restarted
+	recordNoPacketTimeout = 20 * time.Second
+	// recordMaxBufferPackets limits the pre-roll buffer of the streams with the long gop
+	recordMaxBufferPackets = 20000
+	// DefaultSegmentDuration ...
+	DefaultSegmentDuration = time.Minute
+)
+
+// Recorder writes the packets of the channel to the mp4 segments, continuously or by the motion and the clips
+type Recorder struct {
+	streamID    string
+	channelID   string
+	dir         string
+	mu          sync.Mutex
+	config      RecordConfig
+	motion      bool
+	recordUntil time.Time
+	quit        chan struct{}
+	done        chan struct{}
+	stopOnce    sync.Once
+	// owned by the run loop
+	cid        string
+	packets    chan *av.Packet
+	lastPacket time.Time
+	buffer     gopBuffer
+	segment    *segmentWriter
+	lastErr    string
+}
+
+// NewRecorder ...
+func NewRecorder(root, streamID, channelID string, config RecordConfig) (*Recorder, error) {
+	dir, err := RecordDir(root, streamID, channelID)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{
+		streamID:  streamID,
+		channelID: channelID,
+		dir:       dir,
+		config:    config,
+		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Start ...
+func (r *Recorder) Start() {
+	r.removeUnfinished()
+	go r.run()
+}
+
+// Stop closes the current segment
+func (r *Recorder) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.quit)
+		<-r.done
+	})
+}
+
+// SetConfig ...
+func (r *Recorder) SetConfig(config RecordConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.config = config
+}
+
+// SetMotion the post-roll starts when the motion ends
+func (r *Recorder) SetMotion(state bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.motion && !state {
+		r.extend(time.Now().Add(r.config.PostRoll))
+	}
+	r.motion = state
+}
+
+// RecordClip records the channel for the duration, the mode is not checked
+func (r *Recorder) RecordClip(duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.extend(time.Now().Add(duration))
+}
+
+func (r *Recorder) extend(until time.Time) {
+	if until.After(r.recordUntil) {
+		r.recordUntil = until
+	}
+}
+
+func (r *Recorder) getConfig() RecordConfig {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.config
+}
+
+func (r *Recorder) recording(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.config.Mode == RecordModeContinuous ||
+		(r.config.Mode == RecordModeMotion && r.motion) ||
+		now.Before(r.recordUntil)
+}
+
+// needStream the motion mode keeps the stream for the pre-roll
+func (r *Recorder) needStream(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.config.Mode == RecordModeContinuous ||
+		r.config.Mode == RecordModeMotion ||
+		now.Before(r.recordUntil)
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var cleaned time.Time
+	for {
+		select {
+		case <-r.quit:
+			r.closeSegment()
+			r.unsubscribe()
+			return
+		case pkt := <-r.packets:
+			now := time.Now()
+			r.lastPacket = now
+			r.handlePacket(*pkt, now)
+		case now := <-ticker.C:
+			r.check(now)
+			if now.Sub(cleaned) >= time.Minute {
+				cleaned = now
+				r.clean(now)
+			}
+		}
+	}
+}
+
+func (r *Recorder) check(now time.Time) {
+	if !r.needStream(now) {
+		r.closeSegment()
+		r.unsubscribe()
+		r.buffer.reset()
+		return
+	}
+	if r.segment != nil && !r.recording(now) {
+		r.closeSegment()
+	}
+	if r.cid != "" && now.Sub(r.lastPacket) > recordNoPacketTimeout {
+		r.closeSegment()
+		r.unsubscribe()
+		r.buffer.reset()
+	}
+	if r.cid == "" {
+		r.subscribe(now)
+		return
+	}
+	// keep the on demand stream alive
+	Storage.StreamChannelExist(r.streamID, r.channelID)
+	Storage.StreamChannelRun(r.streamID, r.channelID)
+}
+
+func (r *Recorder) subscribe(now time.Time) {
+	cid, packets, _, err := Storage.ClientAdd(r.streamID, r.channelID, MSE)
+	if err != nil {
+		return
+	}
+	r.cid = cid
+	r.packets = packets
+	r.lastPacket = now
+	Storage.StreamChannelRun(r.streamID, r.channelID)
+}
+
+func (r *Recorder) unsubscribe() {
+	if r.cid == "" {
+		return
+	}
+	Storage.ClientDelete(r.streamID, r.cid, r.channelID)
+	r.cid = ""
+	r.packets = nil
+}
+
+func (r *Recorder) handlePacket(pkt av.Packet, now time.Time) {
+	if r.segment != nil && r.segment.restarted(pkt) {
+		r.closeSegment()
+	}
+
+	if !r.recording(now) {
+		r.closeSegment()
+		r.buffer.add(pkt, now, r.getConfig().PreRoll)
+		return
+	}
+
+	if r.segment != nil && pkt.IsKeyFrame && now.Sub(r.segment.opened) >= r.segmentDuration() {
+		r.closeSegment()
+	}
+
+	if r.segment == nil {
+		r.buffer.add(pkt, now, r.getConfig().PreRoll)
+		start, packets := r.buffer.flush()
+		if len(packets) == 0 {
+			return
+		}
+		r.openSegment(start, now, packets)
+		return
+	}
+
+	if err := r.segment.write(pkt); err != nil {
+		r.logError(err)
+		r.closeSegment()
+	}
+}
+
+func (r *Recorder) segmentDuration() time.Duration {
+	if duration := r.getConfig().SegmentDuration; duration > 0 {
+		return duration
+	}
+	return DefaultSegmentDuration
+}
+
+func (r *Recorder) openSegment(start, now time.Time, packets []av.Packet) {
+	codecs, err := Storage.StreamChannelCodecs(r.streamID, r.channelID)
+	if err != nil {
+		r.logError(err)
+		return
+	}
+	if r.segment, err = newSegmentWriter(r.dir, start, now, codecs); err != nil {
+		r.logError(err)
+		return
+	}
+	for _, pkt := range packets {
+		if err = r.segment.write(pkt); err != nil {
+			r.logError(err)
+			r.closeSegment()
+			return
+		}
+	}
+}
+
+func (r *Recorder) closeSegment() {
+	if r.segment == nil {
+		return
+	}
+	if err := r.segment.close(); err != nil {
+		r.logError(err)
+	}
+	r.segment = nil
+}
+
+func (r *Recorder) clean(now time.Time) {
+	config := r.getConfig()
+	if config.MaxAge == 0 && config.MaxSize == 0 {
+		return
+	}
+	if err := cleanRecords(r.dir, config.MaxAge, config.MaxSize, now); err != nil {
+		r.logError(err)
+	}
+}
+
+// removeUnfinished segments are left after the crash, they have no index and can not be played
+func (r *Recorder) removeUnfinished() {
+	files, _ := filepath.Glob(filepath.Join(r.dir, "*.part"))
+	for _, file := range files {
+		_ = os.Remove(file)
+	}
+}
+
+// logError the same error is logged once
+func (r *Recorder) logError(err error) {
+	if err.Error() == r.lastErr {
+		return
+	}
+	r.lastErr = err.Error()
+	log.Errorf("record %s/%s: %s", r.streamID, r.channelID, err.Error())
+}
+
+// gopBuffer holds the groups of pictures of the pre-roll, every group starts with the key frame
+type gopBuffer struct {
+	gops  []gop
+	count int
+}
+
+type gop struct {
+	start   time.Time
+	packets []av.Packet
+}
+
+func (b *gopBuffer) add(pkt av.Packet, now time.Time, preRoll time.Duration) {
+	if pkt.IsKeyFrame {
+		b.gops = append(b.gops, gop{start: now})
+	}
+	if len(b.gops) == 0 {
+		return
+	}
+	last := &b.gops[len(b.gops)-1]
+	last.packets = append(last.packets, pkt)
+	b.count++
+
+	for len(b.gops) > 1 && !b.gops[1].start.After(now.Add(-preRoll)) {
+		b.count -= len(b.gops[0].packets)
+		b.gops = b.gops[1:]
+	}
+	if b.count > recordMaxBufferPackets {
+		b.reset()
+	}
+}
+
+func (b *gopBuffer) flush() (start time.Time, packets []av.Packet) {
+	if len(b.gops) == 0 {
+		return
+	}
+	start = b.gops[0].start
+	packets = make([]av.Packet, 0, b.count)
+	for _, item := range b.gops {
+		packets = append(packets, item.packets...)
+	}
+	b.reset()
+	return
+}
+
+func (b *gopBuffer) reset() {
+	b.gops = nil
+	b.count = 0
+}
+
+// segmentWriter writes the part file, it is renamed to the segment name when closed
+type segmentWriter struct {
+	dir      string
+	path     string
+	file     *os.File
+	muxer    *mp4.Muxer
+	start    time.Time
+	opened   time.Time
+	base     time.Duration
+	end      time.Duration
+	written  bool
+	idx      map[int8]int8
+	lastTime map[int8]time.Duration
+}
+
+func newSegmentWriter(dir string, start, now time.Time, codecs []av.CodecData) (w *segmentWriter, err error) {
+	w = &segmentWriter{
+		dir:      dir,
+		start:    start,
+		opened:   now,
+		idx:      make(map[int8]int8),
+		lastTime: make(map[int8]time.Duration),
+	}
+
+	// the codecs not supported by the mp4 container are skipped
+	var streams []av.CodecData
+	for i, codec := range codecs {
+		switch codec.Type() {
+		case av.H264, av.H265, av.AAC:
+			w.idx[int8(i)] = int8(len(streams))
+			streams = append(streams, codec)
+		}
+	}
+	if len(streams) == 0 {
+		return nil, ErrorStreamChannelCodecNotFound
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	w.path = filepath.Join(dir, segmentName(start, 0)+".part")
+	if w.file, err = os.Create(w.path); err != nil {
+		return nil, err
+	}
+	w.muxer = mp4.NewMuxer(w.file)
+	w.muxer.NegativeTsMakeZero = true
+	if err = w.muxer.WriteHeader(streams); err != nil {
+		_ = w.file.Close()
+		_ = os.Remove(w.path)
+		return nil, err
+	}
+	return
+}
+
+// restarted the timestamps are reset when the stream is reconnected
+func (w *segmentWriter) restarted(pkt av.Packet) bool {
+	last, ok := w.lastTime[pkt.Idx]
+	return ok && pkt.Time < last
+}
+
+func (w *segmentWriter) write(pkt av.Packet) error {
+	idx, ok := w.idx[pkt.Idx]
+	if !ok {
+		return nil
+	}
+	if !w.written {
+		w.base = pkt.Time
+		w.written = true
+	}
+	w.lastTime[pkt.Idx] = pkt.Time
+	if end := pkt.Time - w.base + pkt.Duration; end > w.end {
+		w.end = end
+	}
+	pkt.Idx = idx
+	pkt.Time -= w.base
+	return w.muxer.WritePacket(pkt)
+}
+
+func (w *segmentWriter) close() (err error) {
+	if err = w.muxer.WriteTrailer(); err == nil {
+		err = w.file.Close()
+	} else {
+		_ = w.file.Close()
+	}
+	if err != nil || !w.written || w.end <= 0 {
+		_ = os.Remove(w.path)
+		return
+	}
+	return os.Rename(w.path, filepath.Join(w.dir, segmentName(w.start, w.end)))
+}
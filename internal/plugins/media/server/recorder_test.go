@@ -0,0 +1,199 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package server
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deepch/vdk/av"
+	"github.com/deepch/vdk/codec/aacparser"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecordStream(t *testing.T, name string) {
+	codec, err := aacparser.NewCodecDataFromMPEG4AudioConfig(aacparser.MPEG4AudioConfig{
+		SampleRate:    44100,
+		ChannelLayout: av.CH_MONO,
+		ObjectType:    aacparser.AOT_AAC_LC,
+	})
+	require.NoError(t, err)
+
+	Storage.mutex.Lock()
+	Storage.Streams[name] = StreamST{
+		Name: name,
+		Channels: map[string]ChannelST{
+			recordChannel: Storage.StreamChannelMake(ChannelST{OnDemand: true}),
+		},
+	}
+	Storage.mutex.Unlock()
+	Storage.StreamChannelCodecsUpdate(name, recordChannel, []av.CodecData{codec}, nil)
+
+	t.Cleanup(func() {
+		Storage.mutex.Lock()
+		delete(Storage.Streams, name)
+		Storage.mutex.Unlock()
+	})
+}
+
+// testPacket the stream has the key frame every second
+func testPacket(i int) av.Packet {
+	return av.Packet{
+		IsKeyFrame: i%10 == 0,
+		Time:       time.Duration(i) * 100 * time.Millisecond,
+		Duration:   100 * time.Millisecond,
+		Data:       bytes.Repeat([]byte{byte(i)}, 32),
+	}
+}
+
+func TestRecorderMotion(t *testing.T) {
+	root := t.TempDir()
+	testRecordStream(t, "onvif.camera")
+
+	recorder, err := NewRecorder(root, "onvif.camera", recordChannel, RecordConfig{
+		Mode:     RecordModeMotion,
+		PreRoll:  2 * time.Second,
+		PostRoll: time.Second,
+	})
+	require.NoError(t, err)
+
+	base := time.Now().Add(-time.Minute)
+	now := func(i int) time.Time {
+		return base.Add(time.Duration(i) * 100 * time.Millisecond)
+	}
+
+	// pre-roll, only the last gops are buffered
+	for i := 0; i < 50; i++ {
+		recorder.handlePacket(testPacket(i), now(i))
+	}
+	require.Nil(t, recorder.segment)
+
+	recorder.SetMotion(true)
+	for i := 50; i < 80; i++ {
+		recorder.handlePacket(testPacket(i), now(i))
+	}
+	require.NotNil(t, recorder.segment)
+
+	// the post-roll ends in a second from now, the packets are older
+	recorder.SetMotion(false)
+	recorder.handlePacket(testPacket(80), now(80))
+	require.NotNil(t, recorder.segment)
+	recorder.handlePacket(testPacket(81), time.Now().Add(2*time.Second))
+	require.Nil(t, recorder.segment)
+
+	segments, err := RecordSegments(root, "onvif.camera", recordChannel, base, time.Now())
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	require.Equal(t, now(30).UnixMilli(), segments[0].Start.UnixMilli())
+	require.Equal(t, 5.1, segments[0].Duration)
+
+	path, err := RecordSegmentPath(root, "onvif.camera", recordChannel, segments[0].Name)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, RecordSegmentTS(path, &buf))
+	require.NotZero(t, buf.Len())
+	require.Equal(t, byte(0x47), buf.Bytes()[0])
+}
+
+func TestRecorderSegments(t *testing.T) {
+	root := t.TempDir()
+	testRecordStream(t, "onvif.door")
+
+	recorder, err := NewRecorder(root, "onvif.door", recordChannel, RecordConfig{
+		Mode:            RecordModeContinuous,
+		SegmentDuration: 2 * time.Second,
+	})
+	require.NoError(t, err)
+
+	base := time.Now().Add(-time.Minute)
+	for i := 0; i < 65; i++ {
+		recorder.handlePacket(testPacket(i), base.Add(time.Duration(i)*100*time.Millisecond))
+	}
+	recorder.closeSegment()
+
+	segments, err := RecordSegments(root, "onvif.door", recordChannel, base, time.Now())
+	require.NoError(t, err)
+	require.Len(t, segments, 4)
+	for _, segment := range segments[:3] {
+		require.Equal(t, 2.0, segment.Duration)
+	}
+	require.Equal(t, 0.5, segments[3].Duration)
+
+	// the stream restart closes the segment
+	recorder.handlePacket(testPacket(0), time.Now())
+	recorder.handlePacket(testPacket(1), time.Now())
+	recorder.handlePacket(testPacket(0), time.Now())
+	recorder.closeSegment()
+
+	segments, err = RecordSegments(root, "onvif.door", recordChannel, base, time.Now())
+	require.NoError(t, err)
+	require.Len(t, segments, 6)
+}
+
+func TestRecordRetention(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		name := segmentName(now.Add(-time.Duration(5-i)*time.Hour), time.Minute)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), make([]byte, 100), 0644))
+	}
+
+	require.NoError(t, cleanRecords(dir, 3*time.Hour+30*time.Minute, 0, now))
+	segments, err := readSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, segments, 3)
+
+	require.NoError(t, cleanRecords(dir, 0, 250, now))
+	segments, err = readSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+	require.Equal(t, now.Add(-2*time.Hour).UnixMilli(), segments[0].Start.UnixMilli())
+}
+
+func TestRecordPlaylist(t *testing.T) {
+	start := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	segments := []RecordSegment{
+		{Name: segmentName(start, 60*time.Second), Start: start, Duration: 60},
+		{Name: segmentName(start.Add(time.Hour), 12500*time.Millisecond), Start: start.Add(time.Hour), Duration: 12.5},
+	}
+
+	playlist := RecordPlaylist(segments, "access_token=token")
+	require.True(t, strings.HasPrefix(playlist, "#EXTM3U\n"))
+	require.Contains(t, playlist, "#EXT-X-TARGETDURATION:60\n")
+	require.Contains(t, playlist, "#EXT-X-PROGRAM-DATE-TIME:2026-10-17T11:00:00.000Z\n#EXTINF:12.500,\n")
+	require.Contains(t, playlist, "segment/1792231200000_60000.ts?access_token=token\n")
+	require.Equal(t, 1, strings.Count(playlist, "#EXT-X-DISCONTINUITY"))
+	require.True(t, strings.HasSuffix(playlist, "#EXT-X-ENDLIST\n"))
+}
+
+func TestRecordPath(t *testing.T) {
+	root := t.TempDir()
+
+	_, err := RecordDir(root, "..", recordChannel)
+	require.ErrorIs(t, err, ErrorRecordInvalidPath)
+	_, err = RecordSegmentPath(root, "onvif.camera", recordChannel, "../secret.mp4")
+	require.ErrorIs(t, err, ErrorRecordInvalidPath)
+	_, err = RecordSegmentPath(root, "onvif.camera", recordChannel, "1_1.mp4")
+	require.ErrorIs(t, err, ErrorRecordNotFound)
+}
@@ -18,11 +18,46 @@
 
 package server
 
+import "time"
+
 type EventUpdateList struct {
-	Name     string   `json:"name"`
-	Channels []string `json:"channels"`
+	Name     string        `json:"name"`
+	Channels []string      `json:"channels"`
+	Record   *RecordConfig `json:"record,omitempty"`
 }
 
 type EventRemoveList struct {
 	Name string `json:"name"`
 }
+
+// EventMotion starts and stops the motion recording
+type EventMotion struct {
+	Name  string `json:"name"`
+	State bool   `json:"state"`
+}
+
+// EventRecordClip records the first channel for the duration regardless of the mode
+type EventRecordClip struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+const (
+	// RecordModeOff ...
+	RecordModeOff = "off"
+	// RecordModeContinuous ...
+	RecordModeContinuous = "continuous"
+	// RecordModeMotion ...
+	RecordModeMotion = "motion"
+)
+
+// RecordConfig of the first channel of the stream
+type RecordConfig struct {
+	Mode            string        `json:"mode"`
+	PreRoll         time.Duration `json:"pre_roll"`
+	PostRoll        time.Duration `json:"post_roll"`
+	SegmentDuration time.Duration `json:"segment_duration"`
+	// MaxAge and MaxSize in bytes limit the stored segments, zero is unlimited
+	MaxAge  time.Duration `json:"max_age"`
+	MaxSize int64         `json:"max_size"`
+}
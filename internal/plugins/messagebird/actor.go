@@ -62,7 +62,7 @@ func NewActor(entity *m.Entity,
 		BaseActor:   supervisor.NewBaseActor(entity, service),
 		AccessToken: token,
 		Name:        name,
-		notify:      notify2.NewNotify(service.Adaptors()),
+		notify:      notify2.NewNotify(service.Adaptors(), service.EventBus()),
 		balanceLock: &sync.Mutex{},
 	}
 
@@ -76,7 +76,7 @@ func (e *Actor) Destroy() {
 
 func (e *Actor) Spawn() {
 	e.Service.EventBus().Subscribe(notify2.TopicNotify, e.eventHandler, false)
-	e.notify.Start()
+	e.notify.Start(e, Name, &e.Id)
 }
 
 // Send ...
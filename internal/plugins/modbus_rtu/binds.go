@@ -57,6 +57,13 @@ func NewModbusRtu(eventBus bus.Bus, actor *Actor) (modbus modbusRtu) {
 		// time metric
 		startTime := time.Now()
 
+		// native master
+		if actor.client != nil {
+			result.Result, err = actor.client.Execute(actor.slaveId, f, address, count, command)
+			result.Time = time.Since(startTime).Seconds()
+			return
+		}
+
 		// set callback func
 		ch := make(chan node.MessageResponse)
 		defer close(ch)
@@ -48,6 +48,21 @@ const (
 	AttrSleep = "sleep"
 	// AttrParity ...
 	AttrParity = "parity"
+	// AttrDevice is the serial device path, the native master is used when it is set
+	AttrDevice = "device"
+	// AttrRegisters is the json register map
+	AttrRegisters = "registers"
+	// AttrPollInterval in seconds
+	AttrPollInterval = "poll_interval"
+)
+
+const (
+	// ActionWrite writes the action args, register name to value, to the writable registers
+	ActionWrite = "write"
+	// DefaultPollInterval ...
+	DefaultPollInterval = 10
+	// DefaultTimeout ...
+	DefaultTimeout = 1000
 )
 
 // NewAttr ...
@@ -86,6 +101,19 @@ func NewSettings() m.Attributes {
 			Name: AttrParity,
 			Type: common.AttributeString,
 		},
+		AttrDevice: {
+			Name: AttrDevice,
+			Type: common.AttributeString,
+		},
+		AttrRegisters: {
+			Name: AttrRegisters,
+			Type: common.AttributeString,
+		},
+		AttrPollInterval: {
+			Name:  AttrPollInterval,
+			Type:  common.AttributeInt,
+			Value: DefaultPollInterval,
+		},
 	}
 }
 
@@ -19,9 +19,13 @@
 package modbus_tcp
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
+	"github.com/e154/smart-home/internal/common/modbus"
 	"github.com/e154/smart-home/internal/plugins/node"
 	"github.com/e154/smart-home/internal/system/supervisor"
 	"github.com/e154/smart-home/pkg/events"
@@ -34,6 +38,14 @@ type Actor struct {
 	*supervisor.BaseActor
 	actionPool chan events.EventCallEntityAction
 	stateMu    *sync.Mutex
+	// native master, nil when the requests are sent via the node
+	client       *modbus.Client
+	device       *modbus.Device
+	slaveId      byte
+	pollInterval time.Duration
+	cancel       context.CancelFunc
+	values       map[string]interface{}
+	lastErr      string
 }
 
 // NewActor ...
@@ -54,6 +66,8 @@ func NewActor(entity *m.Entity,
 		actor.Setts = NewSettings()
 	}
 
+	actor.initNative()
+
 	actor.DeserializeAttr(entity.Attributes.Serialize())
 
 	// Actions
@@ -78,8 +92,108 @@ func NewActor(entity *m.Entity,
 	return actor
 }
 
+func (e *Actor) Spawn() {
+	if e.device != nil && len(e.device.Registers) > 0 {
+		var ctx context.Context
+		ctx, e.cancel = context.WithCancel(context.Background())
+		go e.device.Run(ctx, e.pollInterval, e.update)
+	}
+	e.BaseActor.Spawn()
+}
+
 func (e *Actor) Destroy() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	if e.client != nil {
+		_ = e.client.Close()
+	}
+}
+
+// initNative creates the master when the address of the slave is set and adds the register attributes
+func (e *Actor) initNative() {
+	var address string
+	if e.Setts[AttrAddress] != nil {
+		address = e.Setts[AttrAddress].String()
+	}
+	if address == "" {
+		return
+	}
+
+	var timeout, pollInterval int64 = DefaultTimeout, DefaultPollInterval
+	if e.Setts[AttrTimeout] != nil && e.Setts[AttrTimeout].Int64() > 0 {
+		timeout = e.Setts[AttrTimeout].Int64()
+	}
+	if e.Setts[AttrPollInterval] != nil && e.Setts[AttrPollInterval].Int64() > 0 {
+		pollInterval = e.Setts[AttrPollInterval].Int64()
+	}
+	if e.Setts[AttrSlaveId] != nil {
+		e.slaveId = byte(e.Setts[AttrSlaveId].Int64())
+	}
+	e.pollInterval = time.Duration(pollInterval) * time.Second
+
+	var registers []modbus.Register
+	if e.Setts[AttrRegisters] != nil {
+		var err error
+		if registers, err = modbus.ParseRegisters(e.Setts[AttrRegisters].String()); err != nil {
+			log.Error(fmt.Errorf("entity id: %s: %w", e.Id, err).Error())
+		}
+	}
+
+	e.client = modbus.NewClient(modbus.NewTCPTransport(address, time.Duration(timeout)*time.Millisecond))
+	e.device = &modbus.Device{
+		Client:    e.client,
+		SlaveId:   e.slaveId,
+		Registers: registers,
+	}
 
+	if e.Attrs == nil {
+		e.Attrs = m.Attributes{}
+	}
+	for _, register := range registers {
+		if _, ok := e.Attrs[register.Name]; !ok {
+			e.Attrs[register.Name] = &m.Attribute{
+				Name: register.Name,
+				Type: register.AttributeType(),
+			}
+		}
+	}
+}
+
+// update saves the polled values when they are changed, the same error is logged once
+func (e *Actor) update(values map[string]interface{}, err error) {
+	e.stateMu.Lock()
+	defer e.stateMu.Unlock()
+
+	if err != nil {
+		if err.Error() != e.lastErr {
+			log.Error(fmt.Errorf("entity id: %s: %w", e.Id, err).Error())
+		}
+		e.lastErr = err.Error()
+		return
+	}
+	if e.lastErr != "" {
+		log.Infof("entity id: %s: connection restored", e.Id)
+		e.lastErr = ""
+	}
+
+	if reflect.DeepEqual(values, e.values) {
+		return
+	}
+	e.values = values
+
+	e.DeserializeAttr(values)
+	e.SaveState(false, true)
+}
+
+// write the action args to the registers and poll the new values
+func (e *Actor) write(args map[string]interface{}) {
+	for name, value := range args {
+		if err := e.device.Write(name, value); err != nil {
+			log.Error(fmt.Errorf("entity id: %s: %w", e.Id, err).Error())
+		}
+	}
+	e.update(e.device.Poll())
 }
 
 // SetState ...
@@ -97,6 +211,10 @@ func (e *Actor) addAction(event events.EventCallEntityAction) {
 }
 
 func (e *Actor) runAction(msg events.EventCallEntityAction) {
+	if e.device != nil && msg.ActionName == ActionWrite {
+		e.write(msg.Args)
+		return
+	}
 	if action, ok := e.Actions[msg.ActionName]; ok {
 		if action.ScriptEngine != nil && action.ScriptEngine.Engine() != nil {
 			if _, err := action.ScriptEngine.Engine().AssertFunction(FuncEntityAction, e.Id, action.Name, msg.Args); err != nil {
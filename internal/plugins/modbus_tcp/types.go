@@ -38,6 +38,23 @@ const (
 	AttrSlaveId = "slave_id"
 	// AttrAddressPort ...
 	AttrAddressPort = "address_port"
+	// AttrAddress is the host:port of the slave, the native master is used when it is set
+	AttrAddress = "address"
+	// AttrRegisters is the json register map
+	AttrRegisters = "registers"
+	// AttrPollInterval in seconds
+	AttrPollInterval = "poll_interval"
+	// AttrTimeout in milliseconds
+	AttrTimeout = "timeout"
+)
+
+const (
+	// ActionWrite writes the action args, register name to value, to the writable registers
+	ActionWrite = "write"
+	// DefaultPollInterval ...
+	DefaultPollInterval = 10
+	// DefaultTimeout ...
+	DefaultTimeout = 1000
 )
 
 // NewAttr ...
@@ -56,6 +73,24 @@ func NewSettings() m.Attributes {
 			Name: AttrAddressPort,
 			Type: common.AttributeInt,
 		},
+		AttrAddress: {
+			Name: AttrAddress,
+			Type: common.AttributeString,
+		},
+		AttrRegisters: {
+			Name: AttrRegisters,
+			Type: common.AttributeString,
+		},
+		AttrPollInterval: {
+			Name:  AttrPollInterval,
+			Type:  common.AttributeInt,
+			Value: DefaultPollInterval,
+		},
+		AttrTimeout: {
+			Name:  AttrTimeout,
+			Type:  common.AttributeInt,
+			Value: DefaultTimeout,
+		},
 	}
 }
 
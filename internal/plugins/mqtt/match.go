@@ -0,0 +1,139 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// PayloadMatch checks the payload of the message
+type PayloadMatch struct {
+	match    string
+	payload  string
+	jsonPath []string
+	re       *regexp.Regexp
+}
+
+// NewPayloadMatch reads the match options from the trigger payload,
+// the payload is compared exactly if the match is not set
+func NewPayloadMatch(options m.Attributes) (match *PayloadMatch, err error) {
+	match = &PayloadMatch{}
+	if attr, ok := options[TriggerOptionMatch]; ok && attr != nil && attr.Value != nil {
+		match.match = attr.String()
+	}
+	if attr, ok := options[TriggerOptionPayload]; ok && attr != nil && attr.Value != nil {
+		match.payload = attr.String()
+	}
+
+	switch match.match {
+	case "":
+		if match.payload != "" {
+			match.match = MatchExact
+		}
+	case MatchExact:
+	case MatchRegex:
+		if match.re, err = regexp.Compile(match.payload); err != nil {
+			err = fmt.Errorf("bad payload regex: %w", err)
+			return
+		}
+	case MatchJson:
+		if attr, ok := options[TriggerOptionJsonPath]; ok && attr != nil && attr.Value != nil {
+			match.jsonPath = splitJsonPath(attr.String())
+		}
+		if len(match.jsonPath) == 0 {
+			err = fmt.Errorf("json path is required")
+			return
+		}
+	default:
+		err = fmt.Errorf("unknown payload match \"%s\"", match.match)
+	}
+	return
+}
+
+// Match returns the value of the json path if the payload matches
+func (p *PayloadMatch) Match(payload []byte) (value interface{}, ok bool) {
+	switch p.match {
+	case MatchExact:
+		ok = string(payload) == p.payload
+	case MatchRegex:
+		ok = p.re.Match(payload)
+	case MatchJson:
+		var data interface{}
+		if err := json.Unmarshal(payload, &data); err != nil {
+			return
+		}
+		if value, ok = jsonPathValue(data, p.jsonPath); !ok {
+			return
+		}
+		// without the payload it is enough that the value exists
+		ok = p.payload == "" || jsonValueString(value) == p.payload
+	default:
+		ok = true
+	}
+	return
+}
+
+// splitJsonPath splits "$.sensors[0].value" into the keys and the indexes
+func splitJsonPath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	var keys []string
+	for _, key := range strings.Split(path, ".") {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func jsonPathValue(data interface{}, keys []string) (interface{}, bool) {
+	for _, key := range keys {
+		switch v := data.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if data, ok = v[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			data = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+// jsonValueString returns the string as is, other values are encoded to json
+func jsonValueString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
@@ -29,6 +29,7 @@ import (
 	m "github.com/e154/smart-home/pkg/models"
 	"github.com/e154/smart-home/pkg/mqtt"
 	"github.com/e154/smart-home/pkg/plugins"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
 )
 
 var (
@@ -49,6 +50,7 @@ type plugin struct {
 	*plugins.Plugin
 	mqttServ   mqtt.MqttServ
 	mqttClient mqtt.MqttCli
+	registrar  triggers.IRegistrar
 }
 
 // New ...
@@ -73,6 +75,16 @@ func (p *plugin) Load(ctx context.Context, service plugins.Service) (err error)
 		log.Error(err.Error())
 	}
 
+	// register trigger, the trigger has its own client, the topics of the actors are not affected
+	if triggersPlugin, ok := service.Plugins()[triggers.Name]; ok {
+		if p.registrar, ok = triggersPlugin.(triggers.IRegistrar); ok {
+			if err = p.registrar.RegisterTrigger(NewTrigger(p.mqttServ.NewClient(triggerClientName))); err != nil {
+				log.Error(err.Error())
+				return
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -85,6 +97,13 @@ func (p plugin) Unload(ctx context.Context) (err error) {
 	p.mqttServ.RemoveClient("plugins.mqtt")
 	_ = p.Service.EventBus().Unsubscribe("system/entities/+", p.eventHandler)
 
+	if p.registrar != nil {
+		if err = p.registrar.UnregisterTrigger(Name); err != nil {
+			log.Error(err.Error())
+		}
+	}
+	p.mqttServ.RemoveClient(triggerClientName)
+
 	return
 }
 
@@ -120,13 +139,14 @@ func (p *plugin) eventHandler(_ string, msg interface{}) {
 
 // Depends ...
 func (p *plugin) Depends() []string {
-	return nil
+	return []string{"triggers"}
 }
 
 // Options ...
 func (p *plugin) Options() m.PluginOptions {
 	return m.PluginOptions{
-		Triggers:           false,
+		Triggers:           true,
+		TriggerParams:      NewTriggerParams(),
 		Actors:             true,
 		ActorCustomAttrs:   true,
 		ActorCustomActions: true,
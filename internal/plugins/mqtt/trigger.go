@@ -0,0 +1,136 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package mqtt
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/e154/smart-home/pkg/mqtt"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+)
+
+var _ triggers.ITrigger = (*Trigger)(nil)
+
+// Trigger fires when the message is published to the topic
+type Trigger struct {
+	client       mqtt.MqttCli
+	functionName string
+	name         string
+	subscribers  *triggers.Subscriptions[string, *PayloadMatch]
+	// guards the subscriptions of the client
+	sync.Mutex
+}
+
+// NewTrigger ...
+func NewTrigger(client mqtt.MqttCli) triggers.ITrigger {
+	return &Trigger{
+		client:       client,
+		subscribers:  triggers.NewSubscriptions[string, *PayloadMatch](),
+		functionName: TriggerFunctionName,
+		name:         Name,
+	}
+}
+
+// Name ...
+func (t *Trigger) Name() string {
+	return t.name
+}
+
+// AsyncAttach ...
+func (t *Trigger) AsyncAttach(wg *sync.WaitGroup) {
+	wg.Done()
+}
+
+func (t *Trigger) messageHandler(filter string) mqtt.MessageHandler {
+	return func(_ mqtt.MqttCli, msg mqtt.Message) {
+		for _, sub := range t.subscribers.Get(filter) {
+			value, ok := sub.Options.Match(msg.Payload)
+			if !ok {
+				continue
+			}
+			message := TriggerMqttMessage{
+				Topic:   msg.Topic,
+				Payload: string(msg.Payload),
+				Value:   value,
+			}
+			sub.Call(msg.Topic, message)
+		}
+	}
+}
+
+// Subscribe ...
+func (t *Trigger) Subscribe(options triggers.Subscriber) error {
+	filter, err := t.topic(options)
+	if err != nil {
+		return err
+	}
+	match, err := NewPayloadMatch(options.Payload)
+	if err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	// one subscription of the client per topic filter
+	if len(t.subscribers.Get(filter)) == 0 {
+		if err = t.client.Subscribe(filter, t.messageHandler(filter)); err != nil {
+			return err
+		}
+		log.Infof("trigger '%s' subscribe topic '%s'", t.name, filter)
+	}
+	t.subscribers.Add(filter, options.Handler, match)
+
+	return nil
+}
+
+// Unsubscribe ...
+func (t *Trigger) Unsubscribe(options triggers.Subscriber) error {
+	filter, err := t.topic(options)
+	if err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if _, empty := t.subscribers.Remove(filter, options.Handler); empty {
+		t.client.Unsubscribe(filter)
+		log.Infof("trigger '%s' unsubscribe topic '%s'", t.name, filter)
+	}
+
+	return nil
+}
+
+// FunctionName ...
+func (t *Trigger) FunctionName() string {
+	return t.functionName
+}
+
+func (t *Trigger) topic(options triggers.Subscriber) (string, error) {
+	if options.Payload == nil {
+		return "", fmt.Errorf("trigger '%s' without topic", t.name)
+	}
+	attr, ok := options.Payload[TriggerOptionTopic]
+	if !ok || attr == nil || attr.String() == "" {
+		return "", fmt.Errorf("trigger '%s' without topic", t.name)
+	}
+	return attr.String(), nil
+}
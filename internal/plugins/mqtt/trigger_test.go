@@ -0,0 +1,123 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package mqtt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DrmagicE/gmqtt/server"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/mqtt"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClient struct {
+	handlers map[string]mqtt.MessageHandler
+}
+
+func (c *fakeClient) Publish(string, []byte) error { return nil }
+func (c *fakeClient) Subscribe(topic string, handler mqtt.MessageHandler) error {
+	c.handlers[topic] = handler
+	return nil
+}
+func (c *fakeClient) Unsubscribe(topic string) { delete(c.handlers, topic) }
+func (c *fakeClient) UnsubscribeAll()          {}
+func (c *fakeClient) OnMsgArrived(context.Context, server.Client, *server.MsgArrivedRequest) {
+}
+
+func options(values map[string]string) m.Attributes {
+	attrs := NewTriggerParams().Attributes
+	for name, value := range values {
+		attrs[name].Value = value
+	}
+	return attrs
+}
+
+func TestPayloadMatch(t *testing.T) {
+
+	match := func(values map[string]string, payload string) (interface{}, bool) {
+		p, err := NewPayloadMatch(options(values))
+		require.NoError(t, err)
+		return p.Match([]byte(payload))
+	}
+
+	_, ok := match(nil, "anything")
+	require.True(t, ok)
+
+	_, ok = match(map[string]string{TriggerOptionPayload: "ON"}, "ON")
+	require.True(t, ok)
+	_, ok = match(map[string]string{TriggerOptionPayload: "ON"}, "OFF")
+	require.False(t, ok)
+
+	_, ok = match(map[string]string{TriggerOptionMatch: MatchRegex, TriggerOptionPayload: "^temp=[0-9]+$"}, "temp=21")
+	require.True(t, ok)
+	_, ok = match(map[string]string{TriggerOptionMatch: MatchRegex, TriggerOptionPayload: "^temp=[0-9]+$"}, "temp=")
+	require.False(t, ok)
+
+	const payload = `{"state":"ON","sensors":[{"value":21.5},{"value":true}]}`
+	value, ok := match(map[string]string{TriggerOptionMatch: MatchJson, TriggerOptionJsonPath: "$.state", TriggerOptionPayload: "ON"}, payload)
+	require.True(t, ok)
+	require.Equal(t, "ON", value)
+	value, ok = match(map[string]string{TriggerOptionMatch: MatchJson, TriggerOptionJsonPath: "sensors[0].value"}, payload)
+	require.True(t, ok)
+	require.Equal(t, 21.5, value)
+	_, ok = match(map[string]string{TriggerOptionMatch: MatchJson, TriggerOptionJsonPath: "$.sensors[1].value", TriggerOptionPayload: "true"}, payload)
+	require.True(t, ok)
+	_, ok = match(map[string]string{TriggerOptionMatch: MatchJson, TriggerOptionJsonPath: "$.sensors[2].value"}, payload)
+	require.False(t, ok)
+	_, ok = match(map[string]string{TriggerOptionMatch: MatchJson, TriggerOptionJsonPath: "$.state"}, "not json")
+	require.False(t, ok)
+
+	_, err := NewPayloadMatch(options(map[string]string{TriggerOptionMatch: MatchJson}))
+	require.Error(t, err)
+	_, err = NewPayloadMatch(options(map[string]string{TriggerOptionMatch: MatchRegex, TriggerOptionPayload: "("}))
+	require.Error(t, err)
+	_, err = NewPayloadMatch(options(map[string]string{TriggerOptionMatch: "unknown"}))
+	require.Error(t, err)
+}
+
+func TestTrigger(t *testing.T) {
+
+	client := &fakeClient{handlers: make(map[string]mqtt.MessageHandler)}
+	tr := NewTrigger(client)
+
+	var messages []TriggerMqttMessage
+	handler := func(_ string, msg interface{}) {
+		messages = append(messages, msg.(TriggerMqttMessage))
+	}
+	subscriber := triggers.Subscriber{
+		Handler: handler,
+		Payload: options(map[string]string{TriggerOptionTopic: "home/+/temperature"}),
+	}
+
+	require.Error(t, tr.Subscribe(triggers.Subscriber{Handler: handler}))
+	require.NoError(t, tr.Subscribe(subscriber))
+	require.Contains(t, client.handlers, "home/+/temperature")
+
+	client.handlers["home/+/temperature"](client, mqtt.Message{Topic: "home/kitchen/temperature", Payload: []byte("21")})
+	require.Len(t, messages, 1)
+	require.Equal(t, "home/kitchen/temperature", messages[0].Topic)
+	require.Equal(t, "21", messages[0].Payload)
+
+	require.NoError(t, tr.Unsubscribe(subscriber))
+	require.Empty(t, client.handlers)
+}
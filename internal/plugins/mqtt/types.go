@@ -51,3 +51,59 @@ func NewSettings() m.Attributes {
 		},
 	}
 }
+
+const (
+	// TriggerFunctionName ...
+	TriggerFunctionName = "automationTriggerMqtt"
+	triggerClientName   = "plugins.mqtt.trigger"
+
+	// TriggerOptionTopic topic filter, the wildcards + and # are allowed
+	TriggerOptionTopic = "topic"
+	// TriggerOptionMatch payload match: exact, regex or json
+	TriggerOptionMatch = "match"
+	// TriggerOptionPayload expected payload, regular expression or value of the json path
+	TriggerOptionPayload = "payload"
+	// TriggerOptionJsonPath path of the value in the json payload, e.g. "$.state" or "sensors[0].value"
+	TriggerOptionJsonPath = "json_path"
+)
+
+const (
+	// MatchExact ...
+	MatchExact = "exact"
+	// MatchRegex ...
+	MatchRegex = "regex"
+	// MatchJson ...
+	MatchJson = "json"
+)
+
+// NewTriggerParams ...
+func NewTriggerParams() m.TriggerParams {
+	return m.TriggerParams{
+		Script: true,
+		Attributes: m.Attributes{
+			TriggerOptionTopic: {
+				Name: TriggerOptionTopic,
+				Type: common.AttributeString,
+			},
+			TriggerOptionMatch: {
+				Name: TriggerOptionMatch,
+				Type: common.AttributeString,
+			},
+			TriggerOptionPayload: {
+				Name: TriggerOptionPayload,
+				Type: common.AttributeString,
+			},
+			TriggerOptionJsonPath: {
+				Name: TriggerOptionJsonPath,
+				Type: common.AttributeString,
+			},
+		},
+	}
+}
+
+// TriggerMqttMessage ...
+type TriggerMqttMessage struct {
+	Topic   string      `json:"topic"`
+	Payload string      `json:"payload"`
+	Value   interface{} `json:"value,omitempty"`
+}
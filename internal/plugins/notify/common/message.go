@@ -19,13 +19,31 @@
 package common
 
 import (
+	"strings"
+
 	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 )
 
 // Message ...
 type Message struct {
-	Type       string           `json:"type"`
-	EntityId   *common.EntityId `json:"entity_id"`
-	Attributes m.AttributeValue `json:"attributes"`
+	Type       string              `json:"type"`
+	EntityId   *common.EntityId    `json:"entity_id"`
+	Attributes m.AttributeValue    `json:"attributes"`
+	Fallback   []m.MessageFallback `json:"fallback"`
+}
+
+// AddFallback the message is sent by the provider when the previous providers could not deliver it,
+// the provider is the entity id or the plugin name for the providers without entities
+func (msg *Message) AddFallback(provider string, attributes m.AttributeValue) {
+	fallback := m.MessageFallback{
+		Type:       provider,
+		Attributes: attributes,
+	}
+	if strings.Contains(provider, ".") {
+		id := common.EntityId(provider)
+		fallback.Type = id.PluginName()
+		fallback.EntityId = &id
+	}
+	msg.Fallback = append(msg.Fallback, fallback)
 }
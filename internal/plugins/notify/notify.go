@@ -19,46 +19,116 @@
 package notify
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/alitto/pond"
+	"github.com/e154/bus"
 	"github.com/e154/smart-home/internal/plugins/notify/common"
 	"github.com/e154/smart-home/pkg/adaptors"
+	pkgCommon "github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 )
 
 const (
 	maxWorkers = 5
 	minWorkers = 1
+
+	// queueInterval how often the provider checks the deliveries waiting for the next attempt
+	queueInterval = time.Second * 10
+	queueLimit    = 20
+	// sendLease the delivery is not taken from the queue again while the attempt is in progress
+	sendLease = time.Minute * 5
 )
 
 type Notify struct {
 	adaptors *adaptors.Adaptors
+	eventBus bus.Bus
 	pool     *pond.WorkerPool
+	policy   RetryPolicy
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
 }
 
 // NewNotify ...
-func NewNotify(adaptors *adaptors.Adaptors) *Notify {
+func NewNotify(adaptors *adaptors.Adaptors, eventBus bus.Bus) *Notify {
 	return &Notify{
 		adaptors: adaptors,
+		eventBus: eventBus,
 		pool:     pond.New(maxWorkers, 0, pond.MinWorkers(minWorkers)),
+		policy:   NewRetryPolicy(nil),
 	}
 }
 
-func (n *Notify) Start() {
+// Start the queue of the provider, the deliveries left in progress, also before the restart, are sent again.
+// The entity id is empty for the providers without entities.
+func (n *Notify) Start(provider Provider, messageType string, entityId *pkgCommon.EntityId) {
 	n.pool.RunningWorkers()
+
+	if plugin, err := n.adaptors.Plugin.GetByName(context.Background(), Name); err == nil {
+		settings := NewSettings()
+		if _, err = settings.Deserialize(plugin.Settings); err != nil {
+			log.Warn(err.Error())
+		}
+		n.policy = NewRetryPolicy(settings)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n.cancel = cancel
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		ticker := time.NewTicker(queueInterval)
+		defer ticker.Stop()
+		for {
+			n.sendQueued(ctx, provider, messageType, entityId)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
 }
 
 func (n *Notify) Shutdown() {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	n.wg.Wait()
 	n.pool.StopAndWait()
 }
 
 func (n *Notify) Send(msg *m.MessageDelivery, provider Provider) {
 	n.pool.Submit(func() {
-		NewWorker(n.adaptors).Send(msg, provider)
+		NewWorker(n.adaptors, n.eventBus, n.policy).Send(msg, provider)
 	})
 }
 
 func (n *Notify) SaveAndSend(msg common.Message, provider Provider) {
 	n.pool.Submit(func() {
-		NewWorker(n.adaptors).SaveAndSend(msg, provider)
+		NewWorker(n.adaptors, n.eventBus, n.policy).SaveAndSend(msg, provider)
 	})
 }
+
+func (n *Notify) sendQueued(ctx context.Context, provider Provider, messageType string, entityId *pkgCommon.EntityId) {
+
+	now := time.Now()
+	list, err := n.adaptors.MessageDelivery.GetQueued(ctx, messageType, entityId, now, queueLimit)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	lease := now.Add(sendLease)
+	for _, msg := range list {
+		msg.NextAttemptAt = &lease
+		if err = n.adaptors.MessageDelivery.SetStatus(ctx, msg); err != nil {
+			log.Error(err.Error())
+			continue
+		}
+		n.Send(msg, provider)
+	}
+}
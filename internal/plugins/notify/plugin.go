@@ -24,6 +24,7 @@ import (
 
 	"github.com/e154/smart-home/internal/system/supervisor"
 	"github.com/e154/smart-home/pkg/logger"
+	m "github.com/e154/smart-home/pkg/models"
 	"github.com/e154/smart-home/pkg/plugins"
 )
 
@@ -88,3 +89,10 @@ func (p *plugin) Name() string {
 func (p *plugin) Depends() []string {
 	return nil
 }
+
+// Options ...
+func (p *plugin) Options() m.PluginOptions {
+	return m.PluginOptions{
+		Setts: NewSettings(),
+	}
+}
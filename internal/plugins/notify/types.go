@@ -19,7 +19,10 @@
 package notify
 
 import (
+	"time"
+
 	"github.com/e154/smart-home/internal/plugins/notify/common"
+	pkgCommon "github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 )
 
@@ -28,8 +31,78 @@ const (
 	Name = "notify"
 	// TopicNotify ...
 	TopicNotify = "system/plugins/notify"
+
+	// AttrRetryAttempts the number of the delivery attempts
+	AttrRetryAttempts = "retry_attempts"
+	// AttrRetryInterval the delay before the second attempt in seconds, doubled for each next attempt
+	AttrRetryInterval = "retry_interval"
+	// AttrRetryMaxInterval the max delay between the attempts in seconds
+	AttrRetryMaxInterval = "retry_max_interval"
+
+	DefaultRetryAttempts    int64 = 5
+	DefaultRetryInterval    int64 = 30
+	DefaultRetryMaxInterval int64 = 3600
 )
 
+// NewSettings ...
+func NewSettings() m.Attributes {
+	return m.Attributes{
+		AttrRetryAttempts: {
+			Name:  AttrRetryAttempts,
+			Type:  pkgCommon.AttributeInt,
+			Value: DefaultRetryAttempts,
+		},
+		AttrRetryInterval: {
+			Name:  AttrRetryInterval,
+			Type:  pkgCommon.AttributeInt,
+			Value: DefaultRetryInterval,
+		},
+		AttrRetryMaxInterval: {
+			Name:  AttrRetryMaxInterval,
+			Type:  pkgCommon.AttributeInt,
+			Value: DefaultRetryMaxInterval,
+		},
+	}
+}
+
+// RetryPolicy ...
+type RetryPolicy struct {
+	Attempts    int
+	Interval    time.Duration
+	MaxInterval time.Duration
+}
+
+// NewRetryPolicy ...
+func NewRetryPolicy(settings m.Attributes) RetryPolicy {
+	policy := RetryPolicy{
+		Attempts:    int(DefaultRetryAttempts),
+		Interval:    time.Duration(DefaultRetryInterval) * time.Second,
+		MaxInterval: time.Duration(DefaultRetryMaxInterval) * time.Second,
+	}
+	if attr, ok := settings[AttrRetryAttempts]; ok && attr.Int64() > 0 {
+		policy.Attempts = int(attr.Int64())
+	}
+	if attr, ok := settings[AttrRetryInterval]; ok && attr.Int64() > 0 {
+		policy.Interval = time.Duration(attr.Int64()) * time.Second
+	}
+	if attr, ok := settings[AttrRetryMaxInterval]; ok && attr.Int64() > 0 {
+		policy.MaxInterval = time.Duration(attr.Int64()) * time.Second
+	}
+	return policy
+}
+
+// Backoff the delay after the failed attempt, the attempts are counted from one
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.Interval
+	for i := 1; i < attempt && delay < p.MaxInterval; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxInterval {
+		delay = p.MaxInterval
+	}
+	return delay
+}
+
 // Stat ...
 type Stat struct {
 	Workers int `json:"workers"`
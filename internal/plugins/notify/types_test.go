@@ -0,0 +1,59 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package notify
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+
+	policy := NewRetryPolicy(nil)
+	require.Equal(t, int(DefaultRetryAttempts), policy.Attempts)
+
+	settings := NewSettings()
+	settings[AttrRetryAttempts].Value = int64(3)
+	settings[AttrRetryInterval].Value = int64(10)
+	settings[AttrRetryMaxInterval].Value = int64(60)
+	policy = NewRetryPolicy(settings)
+
+	require.Equal(t, 3, policy.Attempts)
+	require.Equal(t, time.Second*10, policy.Backoff(1))
+	require.Equal(t, time.Second*20, policy.Backoff(2))
+	require.Equal(t, time.Second*40, policy.Backoff(3))
+	require.Equal(t, time.Second*60, policy.Backoff(4))
+	require.Equal(t, time.Second*60, policy.Backoff(100))
+}
+
+func TestMessageFallback(t *testing.T) {
+
+	msg := NewMessage()
+	msg.AddFallback("email.google", m.AttributeValue{"addresses": "john.smith@example.com"})
+	msg.AddFallback("html5_notify", m.AttributeValue{"body": "body"})
+
+	require.Len(t, msg.Fallback, 2)
+	require.Equal(t, "email", msg.Fallback[0].Type)
+	require.Equal(t, "email.google", msg.Fallback[0].EntityId.String())
+	require.Equal(t, "html5_notify", msg.Fallback[1].Type)
+	require.Nil(t, msg.Fallback[1].EntityId)
+}
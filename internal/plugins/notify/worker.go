@@ -20,7 +20,9 @@ package notify
 
 import (
 	"context"
+	"time"
 
+	"github.com/e154/bus"
 	notifyCommon "github.com/e154/smart-home/internal/plugins/notify/common"
 	"github.com/e154/smart-home/pkg/adaptors"
 	"github.com/e154/smart-home/pkg/common"
@@ -29,13 +31,17 @@ import (
 
 // Worker ...
 type Worker struct {
-	adaptor *adaptors.Adaptors
+	adaptor  *adaptors.Adaptors
+	eventBus bus.Bus
+	policy   RetryPolicy
 }
 
 // NewWorker ...
-func NewWorker(adaptor *adaptors.Adaptors) *Worker {
+func NewWorker(adaptor *adaptors.Adaptors, eventBus bus.Bus, policy RetryPolicy) *Worker {
 	return &Worker{
-		adaptor: adaptor,
+		adaptor:  adaptor,
+		eventBus: eventBus,
+		policy:   policy,
 	}
 }
 
@@ -44,13 +50,18 @@ func (n *Worker) SaveAndSend(msg notifyCommon.Message, provider Provider) {
 
 	var err error
 
+	// the delivery is not taken from the queue while the first attempt is in progress
+	lease := time.Now().Add(sendLease)
+
 	for _, address := range addresses {
 		messageDelivery := &m.MessageDelivery{
-			Message:   message,
-			MessageId: message.Id,
-			EntityId:  msg.EntityId,
-			Status:    m.MessageStatusInProgress,
-			Address:   address,
+			Message:       message,
+			MessageId:     message.Id,
+			EntityId:      msg.EntityId,
+			Status:        m.MessageStatusInProgress,
+			Address:       address,
+			NextAttemptAt: &lease,
+			Fallback:      msg.Fallback,
 		}
 		if messageDelivery.Id, err = n.adaptor.MessageDelivery.Add(context.Background(), messageDelivery); err != nil {
 			log.Error(err.Error())
@@ -59,13 +70,57 @@ func (n *Worker) SaveAndSend(msg notifyCommon.Message, provider Provider) {
 	}
 }
 
+// Send the failed delivery stays in progress until the next attempt, after the last attempt
+// the message is passed to the fallback provider
 func (n *Worker) Send(msg *m.MessageDelivery, provider Provider) {
 
+	now := time.Now()
+	msg.Attempts++
+	attempt := m.MessageDeliveryAttempt{
+		Attempt:   msg.Attempts,
+		CreatedAt: now,
+	}
+
 	if err := provider.Send(msg.Address, msg.Message); err != nil {
-		msg.Status = m.MessageStatusError
-		msg.ErrorMessageBody = common.String(err.Error())
+		attempt.Error = common.String(err.Error())
+		msg.ErrorMessageBody = attempt.Error
+		if msg.Attempts < n.policy.Attempts {
+			nextAttemptAt := now.Add(n.policy.Backoff(msg.Attempts))
+			msg.Status = m.MessageStatusInProgress
+			msg.NextAttemptAt = &nextAttemptAt
+			log.Warnf("delivery id:(%d) failed, attempt %d of %d, next attempt at %s: %s",
+				msg.Id, msg.Attempts, n.policy.Attempts, nextAttemptAt.Format(time.RFC3339), err.Error())
+		} else {
+			msg.Status = m.MessageStatusError
+			msg.NextAttemptAt = nil
+			log.Errorf("delivery id:(%d) failed after %d attempts: %s", msg.Id, msg.Attempts, err.Error())
+		}
 	} else {
 		msg.Status = m.MessageStatusSucceed
+		msg.NextAttemptAt = nil
+		msg.ErrorMessageBody = nil
 	}
+	msg.AttemptLog = append(msg.AttemptLog, attempt)
+
 	_ = n.adaptor.MessageDelivery.SetStatus(context.Background(), msg)
+
+	if msg.Status == m.MessageStatusError {
+		n.fallback(msg)
+	}
+}
+
+func (n *Worker) fallback(msg *m.MessageDelivery) {
+	if len(msg.Fallback) == 0 || n.eventBus == nil {
+		return
+	}
+
+	next := msg.Fallback[0]
+	log.Infof("delivery id:(%d) is passed to the fallback provider \"%s\"", msg.Id, next.Type)
+
+	n.eventBus.Publish(TopicNotify, notifyCommon.Message{
+		Type:       next.Type,
+		EntityId:   next.EntityId,
+		Attributes: next.Attributes,
+		Fallback:   msg.Fallback[1:],
+	})
 }
@@ -0,0 +1,129 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+// EventStateChanged
+
+package numeric_state
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+
+	"github.com/e154/bus"
+)
+
+var _ triggers.ITrigger = (*Trigger)(nil)
+
+// Trigger ...
+type Trigger struct {
+	eventBus     bus.Bus
+	functionName string
+	name         string
+	subscribers  *triggers.Subscriptions[common.EntityId, Threshold]
+}
+
+// NewTrigger ...
+func NewTrigger(eventBus bus.Bus) triggers.ITrigger {
+	return &Trigger{
+		eventBus:     eventBus,
+		subscribers:  triggers.NewSubscriptions[common.EntityId, Threshold](),
+		functionName: FunctionName,
+		name:         Name,
+	}
+}
+
+// Name ...
+func (t *Trigger) Name() string {
+	return t.name
+}
+
+// AsyncAttach ...
+func (t *Trigger) AsyncAttach(wg *sync.WaitGroup) {
+
+	if err := t.eventBus.Subscribe("system/entities/+", t.eventHandler); err != nil {
+		log.Error(err.Error())
+	}
+
+	wg.Done()
+}
+
+func (t *Trigger) eventHandler(_ string, event interface{}) {
+	v, ok := event.(events.EventStateChanged)
+	if !ok {
+		return
+	}
+
+	for _, sub := range t.subscribers.Get(v.EntityId) {
+		threshold := sub.Options
+		value, ok := v.NewState.Number(threshold.Attribute)
+		if !ok || !threshold.Match(value) {
+			continue
+		}
+		// fires only when the value crosses the threshold
+		oldValue, ok := v.OldState.Number(threshold.Attribute)
+		if ok && threshold.Match(oldValue) {
+			continue
+		}
+		message := TriggerNumericStateMessage{
+			EntityId:  v.EntityId,
+			Attribute: threshold.Attribute,
+			Value:     value,
+			Above:     threshold.Above,
+			Below:     threshold.Below,
+		}
+		if ok {
+			message.OldValue = common.Float64(oldValue)
+		}
+		sub.Call(v.EntityId.String(), message)
+	}
+}
+
+// Subscribe ...
+func (t *Trigger) Subscribe(options triggers.Subscriber) error {
+	if options.EntityId == nil {
+		return fmt.Errorf("entity id is nil")
+	}
+	threshold, err := NewThreshold(options.Payload)
+	if err != nil {
+		return err
+	}
+
+	t.subscribers.Add(*options.EntityId, options.Handler, threshold)
+
+	return nil
+}
+
+// Unsubscribe ...
+func (t *Trigger) Unsubscribe(options triggers.Subscriber) error {
+	if options.EntityId == nil {
+		return fmt.Errorf("entity id is nil")
+	}
+
+	t.subscribers.Remove(*options.EntityId, options.Handler)
+
+	return nil
+}
+
+// FunctionName ...
+func (t *Trigger) FunctionName() string {
+	return t.functionName
+}
@@ -0,0 +1,88 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package numeric_state
+
+import (
+	"testing"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+
+	"github.com/e154/bus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrigger(t *testing.T) {
+
+	var entityId = common.EntityId("sensor.temperature")
+
+	trigger := NewTrigger(bus.NewBus()).(*Trigger)
+
+	var messages []TriggerNumericStateMessage
+	subscriber := triggers.Subscriber{
+		EntityId: &entityId,
+		Payload: m.Attributes{
+			AttrAttribute: {Name: AttrAttribute, Type: common.AttributeString, Value: "temperature"},
+			AttrAbove:     {Name: AttrAbove, Type: common.AttributeFloat, Value: 25.0},
+		},
+		Handler: func(_ string, msg interface{}) {
+			messages = append(messages, msg.(TriggerNumericStateMessage))
+		},
+	}
+	require.NoError(t, trigger.Subscribe(subscriber))
+
+	changed := func(oldValue, newValue interface{}) {
+		trigger.eventHandler("", events.EventStateChanged{
+			EntityId: entityId,
+			OldState: events.EventEntityState{Attributes: m.Attributes{
+				"temperature": {Name: "temperature", Type: common.AttributeFloat, Value: oldValue},
+			}},
+			NewState: events.EventEntityState{Attributes: m.Attributes{
+				"temperature": {Name: "temperature", Type: common.AttributeFloat, Value: newValue},
+			}},
+		})
+	}
+
+	changed(20.0, 24.0)
+	require.Len(t, messages, 0)
+
+	// crossed the threshold
+	changed(24.0, 26.0)
+	require.Len(t, messages, 1)
+	require.Equal(t, 26.0, messages[0].Value)
+	require.Equal(t, 24.0, *messages[0].OldValue)
+
+	// still above the threshold
+	changed(26.0, 27.0)
+	require.Len(t, messages, 1)
+
+	changed(27.0, 20.0)
+	changed(20.0, 30.0)
+	require.Len(t, messages, 2)
+
+	require.NoError(t, trigger.Unsubscribe(subscriber))
+	changed(20.0, 30.0)
+	require.Len(t, messages, 2)
+
+	// the threshold is required
+	subscriber.Payload = m.Attributes{}
+	require.Error(t, trigger.Subscribe(subscriber))
+}
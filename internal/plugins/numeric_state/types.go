@@ -0,0 +1,98 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package numeric_state
+
+import (
+	"fmt"
+
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+const (
+	Name         = "numeric_state"
+	FunctionName = "automationTriggerNumericState"
+	Version      = "0.0.1"
+
+	// AttrAttribute entity attribute, the entity value is used if empty
+	AttrAttribute = "attribute"
+	// AttrAbove ...
+	AttrAbove = "above"
+	// AttrBelow ...
+	AttrBelow = "below"
+)
+
+func NewTriggerParams() m.TriggerParams {
+	return m.TriggerParams{
+		Script:   true,
+		Entities: true,
+		Attributes: m.Attributes{
+			AttrAttribute: {
+				Name: AttrAttribute,
+				Type: common.AttributeString,
+			},
+			AttrAbove: {
+				Name: AttrAbove,
+				Type: common.AttributeFloat,
+			},
+			AttrBelow: {
+				Name: AttrBelow,
+				Type: common.AttributeFloat,
+			},
+		},
+	}
+}
+
+// Threshold ...
+type Threshold struct {
+	Attribute string
+	Above     *float64
+	Below     *float64
+}
+
+// NewThreshold reads the thresholds from the trigger payload
+func NewThreshold(payload m.Attributes) (threshold Threshold, err error) {
+	if attr, ok := payload[AttrAttribute]; ok && attr != nil && attr.Value != nil {
+		threshold.Attribute = attr.String()
+	}
+	if attr, ok := payload[AttrAbove]; ok && attr != nil && attr.Value != nil {
+		threshold.Above = common.Float64(attr.Float64())
+	}
+	if attr, ok := payload[AttrBelow]; ok && attr != nil && attr.Value != nil {
+		threshold.Below = common.Float64(attr.Float64())
+	}
+	if threshold.Above == nil && threshold.Below == nil {
+		err = fmt.Errorf("above or below attribute is required")
+	}
+	return
+}
+
+// Match ...
+func (t Threshold) Match(value float64) bool {
+	return common.InRange(value, t.Above, t.Below)
+}
+
+type TriggerNumericStateMessage struct {
+	EntityId  common.EntityId `json:"entity_id"`
+	Attribute string          `json:"attribute"`
+	Value     float64         `json:"value"`
+	OldValue  *float64        `json:"old_value"`
+	Above     *float64        `json:"above"`
+	Below     *float64        `json:"below"`
+}
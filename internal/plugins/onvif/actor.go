@@ -20,6 +20,9 @@ package onvif
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/e154/smart-home/internal/plugins/media/server"
 	"github.com/e154/smart-home/internal/system/supervisor"
@@ -104,6 +107,13 @@ func (a *Actor) addAction(event events.EventCallEntityAction) {
 }
 
 func (a *Actor) runAction(msg events.EventCallEntityAction) {
+	if msg.ActionName == ActionRecordClip {
+		a.recordClip(msg.Args)
+		return
+	}
+	if a.ptzAction(msg.ActionName, msg.Args) {
+		return
+	}
 	if action, ok := a.Actions[msg.ActionName]; ok {
 		if action.ScriptEngine != nil && action.ScriptEngine.Engine() != nil {
 			if _, err := action.ScriptEngine.Engine().AssertFunction(FuncEntityAction, a.Id, action.Name, msg.Args); err != nil {
@@ -154,6 +164,114 @@ func (a *Actor) prepareMotionAlarm(event *MotionAlarm) {
 		},
 		StorageSave: true,
 	})
+	a.Service.EventBus().Publish("system/media", server.EventMotion{
+		Name:  a.Id.String(),
+		State: event.State,
+	})
+}
+
+// recordClip the duration arg is in seconds
+func (a *Actor) recordClip(args map[string]interface{}) {
+	duration := int64(DefaultClipDuration)
+	if value, ok := args["duration"]; ok {
+		if v := (m.Attribute{Value: value}).Int64(); v > 0 {
+			duration = v
+		}
+	}
+	a.Service.EventBus().Publish("system/media", server.EventRecordClip{
+		Name:     a.Id.String(),
+		Duration: time.Duration(duration) * time.Second,
+	})
+}
+
+// ptzAction runs the native camera control actions, returns false for any other action
+func (a *Actor) ptzAction(name string, args map[string]interface{}) bool {
+	var err error
+	switch name {
+	case ActionGotoPreset:
+		err = a.client.GotoPreset(stringArg(args, "preset"))
+	case ActionSetPreset:
+		_, err = a.client.SetPreset(stringArg(args, "name"))
+	case ActionRemovePreset:
+		err = a.client.RemovePreset(stringArg(args, "preset"))
+	case ActionAbsoluteMove:
+		if _, ok := args["zoom"]; ok {
+			err = a.client.AbsoluteMove(floatArg(args, "x"), floatArg(args, "y"), floatArg(args, "zoom"))
+		} else {
+			err = a.client.AbsoluteMove(floatArg(args, "x"), floatArg(args, "y"))
+		}
+	case ActionRelativeMove:
+		err = a.client.RelativeMove(floatArg(args, "x"), floatArg(args, "y"), floatArg(args, "zoom"))
+	case ActionZoom:
+		err = a.client.Zoom(floatArg(args, "zoom"))
+	case ActionGotoHome:
+		err = a.client.GotoHomePosition()
+	case ActionSetHome:
+		err = a.client.SetHomePosition()
+	case ActionStartTour:
+		var presets []string
+		switch v := args["presets"].(type) {
+		case string:
+			for _, preset := range strings.Split(v, ",") {
+				if preset = strings.TrimSpace(preset); preset != "" {
+					presets = append(presets, preset)
+				}
+			}
+		default:
+			presets = m.Attribute{Value: v}.ArrayString()
+		}
+		dwell := time.Duration(floatArg(args, "dwell") * float32(time.Second))
+		err = a.client.StartTour(presets, dwell)
+	case ActionStopTour:
+		a.client.StopTour()
+	default:
+		return false
+	}
+	if err != nil {
+		log.Error(fmt.Errorf("entity id: %s, action %s: %w", a.Id, name, err).Error())
+	}
+	return true
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	if value, ok := args[name]; ok && value != nil {
+		return m.Attribute{Value: value}.String()
+	}
+	return ""
+}
+
+func floatArg(args map[string]interface{}, name string) float32 {
+	switch v := args[name].(type) {
+	case nil:
+		return 0
+	case string:
+		value, _ := strconv.ParseFloat(strings.TrimSpace(v), 32)
+		return float32(value)
+	default:
+		attr := &m.Attribute{Value: v}
+		return float32(attr.Float64())
+	}
+}
+
+func (a *Actor) recordConfig() *server.RecordConfig {
+	value := func(name string) int64 {
+		if a.Setts[name] == nil {
+			return 0
+		}
+		return a.Setts[name].Int64()
+	}
+	config := &server.RecordConfig{
+		Mode:            server.RecordModeOff,
+		PreRoll:         time.Duration(value(AttrRecordPreRoll)) * time.Second,
+		PostRoll:        time.Duration(value(AttrRecordPostRoll)) * time.Second,
+		SegmentDuration: time.Duration(value(AttrRecordSegment)) * time.Second,
+		MaxAge:          time.Duration(value(AttrRecordMaxAge)) * 24 * time.Hour,
+		MaxSize:         value(AttrRecordMaxSize) << 20,
+	}
+	if a.Setts[AttrRecordMode] != nil && a.Setts[AttrRecordMode].String() != "" {
+		config.Mode = a.Setts[AttrRecordMode].String()
+	}
+	return config
 }
 
 func (a *Actor) prepareStreamList(event *StreamList) {
@@ -161,6 +279,7 @@ func (a *Actor) prepareStreamList(event *StreamList) {
 	a.Service.EventBus().Publish("system/media", server.EventUpdateList{
 		Name:     a.Id.String(),
 		Channels: event.List,
+		Record:   a.recordConfig(),
 	})
 }
 
@@ -61,6 +61,9 @@ type Client struct {
 	isStarted                   atomic.Bool
 	quit                        chan struct{}
 	wg                          sync.WaitGroup
+	tourLock                    sync.Mutex
+	tourCancel                  context.CancelFunc
+	tourDone                    chan struct{}
 	actorHandler                func(interface{})
 }
 
@@ -146,6 +149,7 @@ func (s *Client) Start(username, password, address string, port int64, requireAu
 }
 
 func (s *Client) Shutdown() (err error) {
+	s.StopTour()
 	if !s.isStarted.Load() {
 		return
 	}
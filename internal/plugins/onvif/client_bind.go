@@ -18,6 +18,8 @@
 
 package onvif
 
+import "time"
+
 type ClientBind struct {
 	client *Client
 }
@@ -33,3 +35,60 @@ func (c *ClientBind) ContinuousMove(X, Y float32) {
 func (c *ClientBind) StopContinuousMove() {
 	c.client.StopContinuousMove()
 }
+
+// GetPresets ...
+func (c *ClientBind) GetPresets() []Preset {
+	presets, _ := c.client.GetPresets()
+	return presets
+}
+
+// GotoPreset preset is a token or a name
+func (c *ClientBind) GotoPreset(preset string) bool {
+	return c.client.GotoPreset(preset) == nil
+}
+
+// SetPreset returns the preset token
+func (c *ClientBind) SetPreset(name string) string {
+	token, _ := c.client.SetPreset(name)
+	return token
+}
+
+// RemovePreset preset is a token or a name
+func (c *ClientBind) RemovePreset(preset string) bool {
+	return c.client.RemovePreset(preset) == nil
+}
+
+// AbsoluteMove the zoom is optional
+func (c *ClientBind) AbsoluteMove(X, Y float32, zoom ...float32) bool {
+	return c.client.AbsoluteMove(X, Y, zoom...) == nil
+}
+
+// RelativeMove the zoom is optional
+func (c *ClientBind) RelativeMove(X, Y float32, zoom ...float32) bool {
+	return c.client.RelativeMove(X, Y, zoom...) == nil
+}
+
+// Zoom ...
+func (c *ClientBind) Zoom(zoom float32) bool {
+	return c.client.Zoom(zoom) == nil
+}
+
+// GotoHomePosition ...
+func (c *ClientBind) GotoHomePosition() bool {
+	return c.client.GotoHomePosition() == nil
+}
+
+// SetHomePosition ...
+func (c *ClientBind) SetHomePosition() bool {
+	return c.client.SetHomePosition() == nil
+}
+
+// StartTour dwell is in seconds
+func (c *ClientBind) StartTour(presets []string, dwell float32) bool {
+	return c.client.StartTour(presets, time.Duration(dwell*float32(time.Second))) == nil
+}
+
+// StopTour ...
+func (c *ClientBind) StopTour() {
+	c.client.StopTour()
+}
@@ -0,0 +1,368 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package onvif
+
+import (
+	"context"
+	"time"
+
+	"github.com/eyetowers/gonvif/pkg/generated/onvif/www_onvif_org/ver10/schema"
+	ptzWsdl "github.com/eyetowers/gonvif/pkg/generated/onvif/www_onvif_org/ver20/ptz/wsdl"
+)
+
+// Preset ...
+type Preset struct {
+	Token string `json:"token"`
+	Name  string `json:"name"`
+}
+
+func (s *Client) ptz() (ptzWsdl.PTZ, error) {
+	if s.cli == nil {
+		return nil, ErrNotConnected
+	}
+	return s.cli.PTZ()
+}
+
+func (s *Client) profileToken() *schema.ReferenceToken {
+	var profileToken *schema.ReferenceToken
+	if len(s.mediaProfiles) > profileIndex {
+		profileToken = s.mediaProfiles[profileIndex].Token
+	}
+	if len(s.media2Profiles) > profileIndex {
+		profileToken = s.media2Profiles[profileIndex].Token
+	}
+	return profileToken
+}
+
+func (s *Client) spaces() *schema.PTZSpaces {
+	if s.pTZConfigurationOptions == nil {
+		return nil
+	}
+	return s.pTZConfigurationOptions.Spaces
+}
+
+// GetPresets ...
+func (s *Client) GetPresets() ([]Preset, error) {
+	ptz, err := s.ptz()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ptz.GetPresets(&ptzWsdl.GetPresets{
+		ProfileToken: s.profileToken(),
+	})
+	if err != nil {
+		log.Warn(err.Error())
+		return nil, err
+	}
+	var list = make([]Preset, 0, len(resp.Preset))
+	for _, preset := range resp.Preset {
+		if preset == nil || preset.Token == nil {
+			continue
+		}
+		item := Preset{Token: string(*preset.Token)}
+		if preset.Name != nil {
+			item.Name = string(*preset.Name)
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// findPreset resolves a preset by token or by name
+func (s *Client) findPreset(preset string) (*schema.ReferenceToken, error) {
+	presets, err := s.GetPresets()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range presets {
+		if item.Token == preset || item.Name == preset {
+			token := schema.ReferenceToken(item.Token)
+			return &token, nil
+		}
+	}
+	return nil, ErrPresetNotFound
+}
+
+// GotoPreset preset is a token or a name
+func (s *Client) GotoPreset(preset string) error {
+	ptz, err := s.ptz()
+	if err != nil {
+		return err
+	}
+	token, err := s.findPreset(preset)
+	if err != nil {
+		return err
+	}
+	_, err = ptz.GotoPreset(&ptzWsdl.GotoPreset{
+		ProfileToken: s.profileToken(),
+		PresetToken:  token,
+	})
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	return err
+}
+
+// SetPreset saves the current position, a preset with the same name is overwritten
+func (s *Client) SetPreset(name string) (string, error) {
+	ptz, err := s.ptz()
+	if err != nil {
+		return "", err
+	}
+	token, err := s.findPreset(name)
+	if err != nil && err != ErrPresetNotFound {
+		return "", err
+	}
+	resp, err := ptz.SetPreset(&ptzWsdl.SetPreset{
+		ProfileToken: s.profileToken(),
+		PresetName:   name,
+		PresetToken:  token,
+	})
+	if err != nil {
+		log.Warn(err.Error())
+		return "", err
+	}
+	if resp.PresetToken == nil {
+		return "", nil
+	}
+	return string(*resp.PresetToken), nil
+}
+
+// RemovePreset preset is a token or a name
+func (s *Client) RemovePreset(preset string) error {
+	ptz, err := s.ptz()
+	if err != nil {
+		return err
+	}
+	token, err := s.findPreset(preset)
+	if err != nil {
+		return err
+	}
+	_, err = ptz.RemovePreset(&ptzWsdl.RemovePreset{
+		ProfileToken: s.profileToken(),
+		PresetToken:  token,
+	})
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	return err
+}
+
+// AbsoluteMove moves to the position, the zoom is left as is unless given.
+// The values are clamped to the camera ranges.
+func (s *Client) AbsoluteMove(X, Y float32, zoom ...float32) error {
+	ptz, err := s.ptz()
+	if err != nil {
+		return err
+	}
+	position := &schema.PTZVector{
+		PanTilt: &schema.Vector2D{X: X, Y: Y},
+	}
+	if len(zoom) > 0 {
+		position.Zoom = &schema.Vector1D{X: zoom[0]}
+	}
+	if spaces := s.spaces(); spaces != nil {
+		clamp2D(position.PanTilt, spaces.AbsolutePanTiltPositionSpace)
+		clamp1D(position.Zoom, spaces.AbsoluteZoomPositionSpace)
+	}
+	_, err = ptz.AbsoluteMove(&ptzWsdl.AbsoluteMove{
+		ProfileToken: s.profileToken(),
+		Position:     position,
+	})
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	return err
+}
+
+// RelativeMove moves by the translation, the values are clamped to the camera ranges
+func (s *Client) RelativeMove(X, Y float32, zoom ...float32) error {
+
+	translation := &schema.PTZVector{}
+	if X != 0 || Y != 0 {
+		translation.PanTilt = &schema.Vector2D{X: X, Y: Y}
+	}
+	if len(zoom) > 0 && zoom[0] != 0 {
+		translation.Zoom = &schema.Vector1D{X: zoom[0]}
+	}
+	if translation.PanTilt == nil && translation.Zoom == nil {
+		return nil
+	}
+
+	ptz, err := s.ptz()
+	if err != nil {
+		return err
+	}
+	if spaces := s.spaces(); spaces != nil {
+		clamp2D(translation.PanTilt, spaces.RelativePanTiltTranslationSpace)
+		clamp1D(translation.Zoom, spaces.RelativeZoomTranslationSpace)
+	}
+	_, err = ptz.RelativeMove(&ptzWsdl.RelativeMove{
+		ProfileToken: s.profileToken(),
+		Translation:  translation,
+	})
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	return err
+}
+
+// Zoom changes the zoom only, the pan and tilt stay as they are
+func (s *Client) Zoom(zoom float32) error {
+	ptz, err := s.ptz()
+	if err != nil {
+		return err
+	}
+	position := &schema.PTZVector{
+		Zoom: &schema.Vector1D{X: zoom},
+	}
+	if spaces := s.spaces(); spaces != nil {
+		clamp1D(position.Zoom, spaces.AbsoluteZoomPositionSpace)
+	}
+	_, err = ptz.AbsoluteMove(&ptzWsdl.AbsoluteMove{
+		ProfileToken: s.profileToken(),
+		Position:     position,
+	})
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	return err
+}
+
+// GotoHomePosition ...
+func (s *Client) GotoHomePosition() error {
+	ptz, err := s.ptz()
+	if err != nil {
+		return err
+	}
+	_, err = ptz.GotoHomePosition(&ptzWsdl.GotoHomePosition{
+		ProfileToken: s.profileToken(),
+	})
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	return err
+}
+
+// SetHomePosition saves the current position as home
+func (s *Client) SetHomePosition() error {
+	ptz, err := s.ptz()
+	if err != nil {
+		return err
+	}
+	_, err = ptz.SetHomePosition(&ptzWsdl.SetHomePosition{
+		ProfileToken: s.profileToken(),
+	})
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	return err
+}
+
+// StartTour visits the presets in a loop, staying dwell at each of them, until StopTour is called.
+// A running tour is replaced.
+func (s *Client) StartTour(presets []string, dwell time.Duration) error {
+	if len(presets) == 0 {
+		return ErrEmptyTour
+	}
+	if dwell <= 0 {
+		dwell = DefaultTourDwell * time.Second
+	}
+
+	s.tourLock.Lock()
+	defer s.tourLock.Unlock()
+
+	s.stopTour()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.tourCancel = cancel
+	s.tourDone = done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(dwell)
+		defer ticker.Stop()
+		for i := 0; ; i = (i + 1) % len(presets) {
+			if err := s.GotoPreset(presets[i]); err != nil {
+				log.Warnf("tour, preset \"%s\": %s", presets[i], err.Error())
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StopTour ...
+func (s *Client) StopTour() {
+	s.tourLock.Lock()
+	defer s.tourLock.Unlock()
+	s.stopTour()
+}
+
+func (s *Client) stopTour() {
+	if s.tourCancel == nil {
+		return
+	}
+	s.tourCancel()
+	<-s.tourDone
+	s.tourCancel, s.tourDone = nil, nil
+}
+
+// TourActive ...
+func (s *Client) TourActive() bool {
+	s.tourLock.Lock()
+	defer s.tourLock.Unlock()
+	return s.tourCancel != nil
+}
+
+func clamp(value float32, r *schema.FloatRange) float32 {
+	if r == nil {
+		return value
+	}
+	if value > r.Max {
+		return r.Max
+	}
+	if value < r.Min {
+		return r.Min
+	}
+	return value
+}
+
+func clamp2D(vector *schema.Vector2D, spaces []*schema.Space2DDescription) {
+	if vector == nil || len(spaces) <= profileIndex || spaces[profileIndex] == nil {
+		return
+	}
+	vector.X = clamp(vector.X, spaces[profileIndex].XRange)
+	vector.Y = clamp(vector.Y, spaces[profileIndex].YRange)
+	vector.Space = spaces[profileIndex].URI
+}
+
+func clamp1D(vector *schema.Vector1D, spaces []*schema.Space1DDescription) {
+	if vector == nil || len(spaces) <= profileIndex || spaces[profileIndex] == nil {
+		return
+	}
+	vector.X = clamp(vector.X, spaces[profileIndex].XRange)
+	vector.Space = spaces[profileIndex].URI
+}
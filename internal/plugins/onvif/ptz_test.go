@@ -0,0 +1,256 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package onvif
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/internal/system/supervisor"
+
+	"github.com/eyetowers/gonvif/pkg/generated/onvif/www_onvif_org/ver10/schema"
+	ptzWsdl "github.com/eyetowers/gonvif/pkg/generated/onvif/www_onvif_org/ver20/ptz/wsdl"
+	"github.com/eyetowers/gonvif/pkg/gonvif"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient returns the fake ptz service of the camera
+type fakeClient struct {
+	gonvif.Client
+	camera *fakeCamera
+}
+
+func (f *fakeClient) PTZ() (ptzWsdl.PTZ, error) {
+	return f.camera, nil
+}
+
+// fakeCamera records the ptz requests
+type fakeCamera struct {
+	ptzWsdl.PTZ
+	sync.Mutex
+	presets []*schema.PTZPreset
+	calls   []string
+	moves   []*schema.PTZVector
+}
+
+func (f *fakeCamera) call(name string, vector *schema.PTZVector) {
+	f.Lock()
+	defer f.Unlock()
+	f.calls = append(f.calls, name)
+	if vector != nil {
+		f.moves = append(f.moves, vector)
+	}
+}
+
+func (f *fakeCamera) Calls() []string {
+	f.Lock()
+	defer f.Unlock()
+	return append([]string{}, f.calls...)
+}
+
+func (f *fakeCamera) GetPresets(_ *ptzWsdl.GetPresets) (*ptzWsdl.GetPresetsResponse, error) {
+	return &ptzWsdl.GetPresetsResponse{Preset: f.presets}, nil
+}
+
+func (f *fakeCamera) GotoPreset(request *ptzWsdl.GotoPreset) (*ptzWsdl.GotoPresetResponse, error) {
+	f.call("GotoPreset:"+string(*request.PresetToken), nil)
+	return &ptzWsdl.GotoPresetResponse{}, nil
+}
+
+func (f *fakeCamera) SetPreset(request *ptzWsdl.SetPreset) (*ptzWsdl.SetPresetResponse, error) {
+	f.call("SetPreset:"+string(request.PresetName), nil)
+	token := schema.ReferenceToken("3")
+	return &ptzWsdl.SetPresetResponse{PresetToken: &token}, nil
+}
+
+func (f *fakeCamera) RemovePreset(request *ptzWsdl.RemovePreset) (*ptzWsdl.RemovePresetResponse, error) {
+	f.call("RemovePreset:"+string(*request.PresetToken), nil)
+	return &ptzWsdl.RemovePresetResponse{}, nil
+}
+
+func (f *fakeCamera) AbsoluteMove(request *ptzWsdl.AbsoluteMove) (*ptzWsdl.AbsoluteMoveResponse, error) {
+	f.call("AbsoluteMove", request.Position)
+	return &ptzWsdl.AbsoluteMoveResponse{}, nil
+}
+
+func (f *fakeCamera) RelativeMove(request *ptzWsdl.RelativeMove) (*ptzWsdl.RelativeMoveResponse, error) {
+	f.call("RelativeMove", request.Translation)
+	return &ptzWsdl.RelativeMoveResponse{}, nil
+}
+
+func (f *fakeCamera) GotoHomePosition(_ *ptzWsdl.GotoHomePosition) (*ptzWsdl.GotoHomePositionResponse, error) {
+	f.call("GotoHomePosition", nil)
+	return &ptzWsdl.GotoHomePositionResponse{}, nil
+}
+
+func (f *fakeCamera) SetHomePosition(_ *ptzWsdl.SetHomePosition) (*ptzWsdl.SetHomePositionResponse, error) {
+	f.call("SetHomePosition", nil)
+	return &ptzWsdl.SetHomePositionResponse{}, nil
+}
+
+func newFakeCamera() (*fakeCamera, *Client) {
+	preset := func(token, name string) *schema.PTZPreset {
+		t := schema.ReferenceToken(token)
+		n := schema.Name(name)
+		return &schema.PTZPreset{Token: &t, Name: &n}
+	}
+	camera := &fakeCamera{
+		presets: []*schema.PTZPreset{preset("1", "door"), preset("2", "gate")},
+	}
+	client := NewClient(nil)
+	client.cli = &fakeClient{camera: camera}
+	client.pTZConfigurationOptions = &schema.PTZConfigurationOptions{
+		Spaces: &schema.PTZSpaces{
+			AbsolutePanTiltPositionSpace: []*schema.Space2DDescription{{
+				URI:    "absolute",
+				XRange: &schema.FloatRange{Min: -1, Max: 1},
+				YRange: &schema.FloatRange{Min: -1, Max: 1},
+			}},
+			AbsoluteZoomPositionSpace: []*schema.Space1DDescription{{
+				URI:    "zoom",
+				XRange: &schema.FloatRange{Min: 0, Max: 1},
+			}},
+		},
+	}
+	return camera, client
+}
+
+func TestClamp(t *testing.T) {
+
+	r := &schema.FloatRange{Min: -1, Max: 1}
+	require.Equal(t, float32(1), clamp(2, r))
+	require.Equal(t, float32(-1), clamp(-2, r))
+	require.Equal(t, float32(0.5), clamp(0.5, r))
+	require.Equal(t, float32(2), clamp(2, nil))
+
+	vector := &schema.Vector2D{X: 5, Y: -5}
+	clamp2D(vector, []*schema.Space2DDescription{{URI: "space", XRange: r, YRange: &schema.FloatRange{Min: 0, Max: 1}}})
+	require.Equal(t, &schema.Vector2D{X: 1, Y: 0, Space: "space"}, vector)
+
+	// the camera without the spaces
+	vector = &schema.Vector2D{X: 5, Y: -5}
+	clamp2D(vector, nil)
+	require.Equal(t, &schema.Vector2D{X: 5, Y: -5}, vector)
+	clamp2D(nil, []*schema.Space2DDescription{{XRange: r}})
+}
+
+func TestArgs(t *testing.T) {
+
+	args := map[string]interface{}{
+		"float":  0.5,
+		"int":    2,
+		"string": " 0.25 ",
+		"wrong":  "abc",
+		"name":   "door",
+		"nil":    nil,
+	}
+	require.Equal(t, float32(0.5), floatArg(args, "float"))
+	require.Equal(t, float32(2), floatArg(args, "int"))
+	require.Equal(t, float32(0.25), floatArg(args, "string"))
+	require.Equal(t, float32(0), floatArg(args, "wrong"))
+	require.Equal(t, float32(0), floatArg(args, "nil"))
+	require.Equal(t, float32(0), floatArg(args, "missing"))
+
+	require.Equal(t, "door", stringArg(args, "name"))
+	require.Equal(t, "", stringArg(args, "nil"))
+	require.Equal(t, "", stringArg(args, "missing"))
+}
+
+func TestTour(t *testing.T) {
+
+	camera, client := newFakeCamera()
+
+	require.ErrorIs(t, client.StartTour(nil, time.Second), ErrEmptyTour)
+	require.False(t, client.TourActive())
+
+	require.NoError(t, client.StartTour([]string{"door", "gate"}, 10*time.Millisecond))
+	require.True(t, client.TourActive())
+	require.Eventually(t, func() bool {
+		calls := camera.Calls()
+		return len(calls) >= 3 && calls[0] == "GotoPreset:1" && calls[1] == "GotoPreset:2" && calls[2] == "GotoPreset:1"
+	}, time.Second, 5*time.Millisecond)
+
+	// the running tour is replaced
+	require.NoError(t, client.StartTour([]string{"gate"}, time.Hour))
+	require.True(t, client.TourActive())
+	require.Eventually(t, func() bool {
+		calls := camera.Calls()
+		return calls[len(calls)-1] == "GotoPreset:2"
+	}, time.Second, 5*time.Millisecond)
+
+	client.StopTour()
+	require.False(t, client.TourActive())
+	calls := len(camera.Calls())
+	time.Sleep(30 * time.Millisecond)
+	require.Len(t, camera.Calls(), calls)
+
+	// stopping twice is fine
+	client.StopTour()
+}
+
+func TestPtzAction(t *testing.T) {
+
+	camera, client := newFakeCamera()
+	actor := &Actor{
+		BaseActor: &supervisor.BaseActor{Id: "onvif.camera"},
+		client:    client,
+	}
+
+	for _, item := range []struct {
+		action string
+		args   map[string]interface{}
+		call   string
+	}{
+		{ActionGotoPreset, map[string]interface{}{"preset": "gate"}, "GotoPreset:2"},
+		{ActionSetPreset, map[string]interface{}{"name": "window"}, "SetPreset:window"},
+		{ActionRemovePreset, map[string]interface{}{"preset": "1"}, "RemovePreset:1"},
+		{ActionAbsoluteMove, map[string]interface{}{"x": 2, "y": "-0.5"}, "AbsoluteMove"},
+		{ActionRelativeMove, map[string]interface{}{"x": 0.1}, "RelativeMove"},
+		{ActionZoom, map[string]interface{}{"zoom": 3}, "AbsoluteMove"},
+		{ActionGotoHome, nil, "GotoHomePosition"},
+		{ActionSetHome, nil, "SetHomePosition"},
+	} {
+		before := len(camera.Calls())
+		require.True(t, actor.ptzAction(item.action, item.args), item.action)
+		calls := camera.Calls()
+		require.Len(t, calls, before+1, item.action)
+		require.Equal(t, item.call, calls[before], item.action)
+	}
+
+	// the values are clamped to the camera ranges, the zoom is left as is
+	require.Equal(t, &schema.PTZVector{PanTilt: &schema.Vector2D{X: 1, Y: -0.5, Space: "absolute"}}, camera.moves[0])
+	require.Equal(t, &schema.PTZVector{PanTilt: &schema.Vector2D{X: 0.1}}, camera.moves[1])
+	require.Equal(t, &schema.PTZVector{Zoom: &schema.Vector1D{X: 1, Space: "zoom"}}, camera.moves[2])
+
+	// the unknown preset is not sent to the camera
+	before := len(camera.Calls())
+	require.True(t, actor.ptzAction(ActionGotoPreset, map[string]interface{}{"preset": "roof"}))
+	require.Len(t, camera.Calls(), before)
+
+	// the tour, the presets are the list or the comma separated string
+	require.True(t, actor.ptzAction(ActionStartTour, map[string]interface{}{"presets": "door, gate", "dwell": 3600}))
+	require.True(t, client.TourActive())
+	require.True(t, actor.ptzAction(ActionStartTour, map[string]interface{}{"presets": []interface{}{"gate"}}))
+	require.True(t, client.TourActive())
+	require.True(t, actor.ptzAction(ActionStopTour, nil))
+	require.False(t, client.TourActive())
+
+	require.False(t, actor.ptzAction("ENABLE_MOTION", nil))
+}
@@ -19,8 +19,10 @@
 package onvif
 
 import (
+	"errors"
 	"time"
 
+	"github.com/e154/smart-home/internal/plugins/media/server"
 	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 	"github.com/e154/smart-home/pkg/plugins"
@@ -44,8 +46,37 @@ const (
 	AttrMotion     = "motion"
 	AttrMotionTime = "motionTime"
 
+	AttrRecordMode     = "recordMode"
+	AttrRecordPreRoll  = "recordPreRoll"
+	AttrRecordPostRoll = "recordPostRoll"
+	AttrRecordSegment  = "recordSegment"
+	AttrRecordMaxAge   = "recordMaxAge"
+	AttrRecordMaxSize  = "recordMaxSize"
+
 	ActionContinuousMove     = "continuousMove"
 	ActionStopContinuousMove = "stopContinuousMove"
+	ActionRecordClip         = "recordClip"
+	ActionGotoPreset         = "gotoPreset"
+	ActionSetPreset          = "setPreset"
+	ActionRemovePreset       = "removePreset"
+	ActionAbsoluteMove       = "absoluteMove"
+	ActionRelativeMove       = "relativeMove"
+	ActionZoom               = "zoom"
+	ActionGotoHome           = "gotoHome"
+	ActionSetHome            = "setHome"
+	ActionStartTour          = "startTour"
+	ActionStopTour           = "stopTour"
+
+	// DefaultClipDuration in seconds
+	DefaultClipDuration = 30
+	// DefaultTourDwell in seconds
+	DefaultTourDwell = 10
+)
+
+var (
+	ErrNotConnected   = errors.New("camera not connected")
+	ErrPresetNotFound = errors.New("preset not found")
+	ErrEmptyTour      = errors.New("tour has no presets")
 )
 
 // NewAttr ...
@@ -90,6 +121,35 @@ func NewSettings() map[string]*m.Attribute {
 			Type:  common.AttributeBool,
 			Value: true,
 		},
+		AttrRecordMode: {
+			Name:  AttrRecordMode,
+			Type:  common.AttributeString,
+			Value: server.RecordModeOff,
+		},
+		AttrRecordPreRoll: {
+			Name:  AttrRecordPreRoll,
+			Type:  common.AttributeInt,
+			Value: 5,
+		},
+		AttrRecordPostRoll: {
+			Name:  AttrRecordPostRoll,
+			Type:  common.AttributeInt,
+			Value: 10,
+		},
+		AttrRecordSegment: {
+			Name:  AttrRecordSegment,
+			Type:  common.AttributeInt,
+			Value: 60,
+		},
+		AttrRecordMaxAge: {
+			Name:  AttrRecordMaxAge,
+			Type:  common.AttributeInt,
+			Value: 7,
+		},
+		AttrRecordMaxSize: {
+			Name: AttrRecordMaxSize,
+			Type: common.AttributeInt,
+		},
 	}
 }
 
@@ -120,6 +180,50 @@ func NewActions() map[string]plugins.ActorAction {
 			Name:        ActionStopContinuousMove,
 			Description: "camera control",
 		},
+		ActionRecordClip: {
+			Name:        ActionRecordClip,
+			Description: "record clip, args: duration in seconds",
+		},
+		ActionGotoPreset: {
+			Name:        ActionGotoPreset,
+			Description: "go to preset, args: preset (token or name)",
+		},
+		ActionSetPreset: {
+			Name:        ActionSetPreset,
+			Description: "save current position as preset, args: name",
+		},
+		ActionRemovePreset: {
+			Name:        ActionRemovePreset,
+			Description: "remove preset, args: preset (token or name)",
+		},
+		ActionAbsoluteMove: {
+			Name:        ActionAbsoluteMove,
+			Description: "move to position, args: x, y, zoom",
+		},
+		ActionRelativeMove: {
+			Name:        ActionRelativeMove,
+			Description: "move by offset, args: x, y, zoom",
+		},
+		ActionZoom: {
+			Name:        ActionZoom,
+			Description: "set zoom, args: zoom",
+		},
+		ActionGotoHome: {
+			Name:        ActionGotoHome,
+			Description: "go to home position",
+		},
+		ActionSetHome: {
+			Name:        ActionSetHome,
+			Description: "save current position as home",
+		},
+		ActionStartTour: {
+			Name:        ActionStartTour,
+			Description: "patrol presets, args: presets, dwell in seconds",
+		},
+		ActionStopTour: {
+			Name:        ActionStopTour,
+			Description: "stop patrol",
+		},
 	}
 }
 
@@ -54,7 +54,7 @@ func NewActor(entity *m.Entity,
 		actionPool:  make(chan events.EventCallEntityAction, 1000),
 		isStarted:   atomic.NewBool(false),
 		AccessToken: settings[AttrToken].Decrypt(),
-		notify:      notify2.NewNotify(service.Adaptors()),
+		notify:      notify2.NewNotify(service.Adaptors(), service.EventBus()),
 	}
 
 	if actor.Attrs == nil {
@@ -102,7 +102,7 @@ func (e *Actor) Spawn() {
 	}
 
 	_ = e.Service.EventBus().Subscribe(notify2.TopicNotify, e.eventHandler, false)
-	e.notify.Start()
+	e.notify.Start(e, Name, &e.Id)
 
 	e.BaseActor.Spawn()
 }
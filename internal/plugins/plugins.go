@@ -41,13 +41,16 @@ import (
 	_ "github.com/e154/smart-home/internal/plugins/neural_network"
 	_ "github.com/e154/smart-home/internal/plugins/node"
 	_ "github.com/e154/smart-home/internal/plugins/notify"
+	_ "github.com/e154/smart-home/internal/plugins/numeric_state"
 	_ "github.com/e154/smart-home/internal/plugins/onvif"
 	_ "github.com/e154/smart-home/internal/plugins/pachka"
+	_ "github.com/e154/smart-home/internal/plugins/presence"
 	_ "github.com/e154/smart-home/internal/plugins/scene"
 	_ "github.com/e154/smart-home/internal/plugins/sensor"
 	_ "github.com/e154/smart-home/internal/plugins/slack"
 	_ "github.com/e154/smart-home/internal/plugins/speedtest"
 	_ "github.com/e154/smart-home/internal/plugins/state_change"
+	_ "github.com/e154/smart-home/internal/plugins/state_held"
 	_ "github.com/e154/smart-home/internal/plugins/sun"
 	_ "github.com/e154/smart-home/internal/plugins/system"
 	_ "github.com/e154/smart-home/internal/plugins/telegram"
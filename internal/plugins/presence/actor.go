@@ -0,0 +1,233 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package presence
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/e154/smart-home/internal/common/location"
+	"github.com/e154/smart-home/internal/system/supervisor"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/mqtt"
+	"github.com/e154/smart-home/pkg/plugins"
+)
+
+// Actor ...
+type Actor struct {
+	*supervisor.BaseActor
+	token       string
+	topic       string
+	homeZone    string
+	maxAccuracy float64
+	mqttClient  mqtt.MqttCli
+	zones       func() []Zone
+	updateLock  *sync.Mutex
+	tracker     *Tracker
+}
+
+// NewActor ...
+func NewActor(entity *m.Entity,
+	service plugins.Service,
+	mqttClient mqtt.MqttCli,
+	zones func() []Zone) *Actor {
+
+	settings := NewSettings()
+	_, _ = settings.Deserialize(entity.Settings.Serialize())
+
+	actor := &Actor{
+		BaseActor:   supervisor.NewBaseActor(entity, service),
+		token:       settings[SettingToken].Decrypt(),
+		topic:       settings[SettingTopic].String(),
+		homeZone:    settings[SettingHomeZone].String(),
+		maxAccuracy: float64(settings[SettingMaxAccuracy].Int64()),
+		mqttClient:  mqttClient,
+		zones:       zones,
+		updateLock:  &sync.Mutex{},
+	}
+
+	if actor.Attrs == nil {
+		actor.Attrs = NewAttr()
+	}
+
+	if actor.Setts == nil {
+		actor.Setts = NewSettings()
+	}
+
+	if actor.homeZone == "" {
+		actor.homeZone = DefaultHomeZone
+	}
+
+	// the restored zones are not entered again after the restart
+	var inside []string
+	if attr, ok := actor.Attrs[AttrZones]; ok && attr.String() != "" {
+		inside = strings.Split(attr.String(), ",")
+	}
+	actor.tracker = NewTracker(float64(settings[SettingHysteresis].Int64()), inside)
+
+	return actor
+}
+
+// Spawn ...
+func (e *Actor) Spawn() {
+	if e.topic == "" || e.mqttClient == nil {
+		return
+	}
+	if err := e.mqttClient.Subscribe(e.topic, e.mqttOnPublish); err != nil {
+		log.Error(err.Error())
+	}
+}
+
+// Destroy ...
+func (e *Actor) Destroy() {
+	if e.topic == "" || e.mqttClient == nil {
+		return
+	}
+	e.mqttClient.Unsubscribe(e.topic)
+}
+
+func (e *Actor) mqttOnPublish(_ mqtt.MqttCli, message mqtt.Message) {
+	location, ok, err := ParseLocation(message.Payload)
+	if err != nil {
+		log.Warnf("%s: %s", e.Id, err.Error())
+		return
+	}
+	if ok {
+		e.update(location, SourceMqtt)
+	}
+}
+
+// ServeHTTP answers with an empty json list, which is what OwnTracks expects
+func (e *Actor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if !e.checkToken(r) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	location, ok, err := ParseLocation(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ok {
+		e.update(location, SourceHttp)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte("[]"))
+}
+
+// checkToken the token is passed as a bearer token, a basic auth password or the access_token query
+func (e *Actor) checkToken(r *http.Request) bool {
+	if e.token == "" {
+		return false
+	}
+	token := r.URL.Query().Get("access_token")
+	if _, password, ok := r.BasicAuth(); ok {
+		token = password
+	}
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		token = strings.TrimPrefix(header, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(e.token)) == 1
+}
+
+func (e *Actor) update(loc Location, source string) {
+
+	if e.maxAccuracy > 0 && loc.Accuracy > e.maxAccuracy {
+		log.Debugf("%s: the accuracy %.0fm is ignored", e.Id, loc.Accuracy)
+		return
+	}
+
+	zones := e.zones()
+
+	e.updateLock.Lock()
+	defer e.updateLock.Unlock()
+
+	entered, left := e.tracker.Update(zones, loc.Point, loc.Accuracy)
+	current := e.tracker.Zones()
+
+	state, zone := StateAway, ""
+	switch {
+	case e.tracker.Inside(e.homeZone):
+		state, zone = StateHome, e.homeZone
+	case len(current) > 0:
+		state, zone = StateZone, current[0]
+	}
+
+	attributeValues := m.AttributeValue{
+		AttrLatitude:  loc.Point.Lat,
+		AttrLongitude: loc.Point.Lon,
+		AttrAccuracy:  loc.Accuracy,
+		AttrZone:      zone,
+		AttrZones:     strings.Join(current, ","),
+		AttrSource:    source,
+	}
+	if loc.Battery != nil {
+		attributeValues[AttrBattery] = *loc.Battery
+	}
+	for _, item := range zones {
+		if item.Name != e.homeZone || len(item.Polygon) < 3 {
+			continue
+		}
+		var distance float64
+		if state != StateHome {
+			distance = location.GetDistanceToPolygonEdge(loc.Point, item.Polygon) * 1000
+		}
+		attributeValues[AttrDistance] = distance
+	}
+
+	e.SetActorState(&state)
+	e.DeserializeAttr(attributeValues)
+	e.SaveState(false, true)
+
+	publish := func(event, zone string) {
+		e.Service.EventBus().Publish(fmt.Sprintf("system/plugins/presence/%s", e.Id), EventZoneChanged{
+			EntityId: e.Id,
+			Event:    event,
+			Zone:     zone,
+			State:    state,
+			Point:    loc.Point,
+			Time:     loc.Time,
+		})
+	}
+	for _, name := range left {
+		publish(EventLeave, name)
+	}
+	for _, name := range entered {
+		publish(EventEnter, name)
+	}
+}
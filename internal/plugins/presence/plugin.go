@@ -0,0 +1,208 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package presence
+
+import (
+	"context"
+	"embed"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/e154/smart-home/internal/system/supervisor"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/logger"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/mqtt"
+	"github.com/e154/smart-home/pkg/plugins"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+)
+
+var (
+	log = logger.MustGetLogger("plugins.presence")
+)
+
+var _ plugins.Pluggable = (*plugin)(nil)
+
+//go:embed Readme.md
+//go:embed Readme.ru.md
+var F embed.FS
+
+func init() {
+	supervisor.RegisterPlugin(Name, New)
+}
+
+const (
+	mqttClientName = "plugins.presence"
+	// zonesInterval the areas are reloaded with the interval, the changes are picked up without the restart
+	zonesInterval = time.Minute
+)
+
+type plugin struct {
+	*plugins.Plugin
+	mqttServ   mqtt.MqttServ
+	mqttClient mqtt.MqttCli
+	registrar  triggers.IRegistrar
+	trigger    *Trigger
+	zonesLock  *sync.RWMutex
+	zones      []Zone
+	quit       chan struct{}
+}
+
+// New ...
+func New() plugins.Pluggable {
+	p := &plugin{
+		Plugin:    plugins.NewPlugin(),
+		zonesLock: &sync.RWMutex{},
+	}
+	p.F = F
+	return p
+}
+
+// Load ...
+func (p *plugin) Load(ctx context.Context, service plugins.Service) (err error) {
+
+	// the actors are spawned on load, they need the client and the zones
+	p.mqttServ = service.MqttServ()
+	p.mqttClient = p.mqttServ.NewClient(mqttClientName)
+	p.loadZones(ctx, service)
+
+	if err = p.Plugin.Load(ctx, service, p.ActorConstructor); err != nil {
+		return
+	}
+
+	p.quit = make(chan struct{})
+	go func(quit chan struct{}) {
+		ticker := time.NewTicker(zonesInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.loadZones(context.Background(), service)
+			case <-quit:
+				return
+			}
+		}
+	}(p.quit)
+
+	// register trigger
+	if triggersPlugin, ok := service.Plugins()[triggers.Name]; ok {
+		if p.registrar, ok = triggersPlugin.(triggers.IRegistrar); ok {
+			p.trigger = NewTrigger(p.Service.EventBus())
+			if err = p.registrar.RegisterTrigger(p.trigger); err != nil {
+				log.Error(err.Error())
+				return
+			}
+		}
+	}
+
+	return nil
+}
+
+// Unload ...
+func (p *plugin) Unload(ctx context.Context) (err error) {
+	if err = p.Plugin.Unload(ctx); err != nil {
+		return
+	}
+
+	if p.quit != nil {
+		close(p.quit)
+		p.quit = nil
+	}
+
+	p.mqttServ.RemoveClient(mqttClientName)
+
+	if p.trigger != nil {
+		p.trigger.Detach()
+	}
+	if p.registrar != nil {
+		if err = p.registrar.UnregisterTrigger(Name); err != nil {
+			log.Error(err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ActorConstructor ...
+func (p *plugin) ActorConstructor(entity *m.Entity) (actor plugins.PluginActor, err error) {
+	actor = NewActor(entity, p.Service, p.mqttClient, p.getZones)
+	return
+}
+
+// Name ...
+func (p *plugin) Name() string {
+	return Name
+}
+
+// Depends ...
+func (p *plugin) Depends() []string {
+	return []string{"triggers"}
+}
+
+// Options ...
+func (p *plugin) Options() m.PluginOptions {
+	return m.PluginOptions{
+		Actors:        true,
+		ActorAttrs:    NewAttr(),
+		ActorSetts:    NewSettings(),
+		ActorStates:   plugins.ToEntityStateShort(NewStates()),
+		Triggers:      true,
+		TriggerParams: NewTriggerParams(),
+	}
+}
+
+// ServeHTTP the reports are sent to /presence/{entity_id}
+func (p *plugin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	entityId := strings.Trim(strings.TrimPrefix(r.URL.Path, "/"+Name), "/")
+	value, ok := p.Actors.Load(common.EntityId(entityId))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	value.(*Actor).ServeHTTP(w, r)
+}
+
+func (p *plugin) loadZones(ctx context.Context, service plugins.Service) {
+	list, _, err := service.Adaptors().Area.List(ctx, 999, 0, "asc", "name")
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	zones := make([]Zone, 0, len(list))
+	for _, area := range list {
+		if len(area.Polygon) < 3 {
+			continue
+		}
+		zones = append(zones, Zone{Name: area.Name, Polygon: area.Polygon})
+	}
+
+	p.zonesLock.Lock()
+	p.zones = zones
+	p.zonesLock.Unlock()
+}
+
+func (p *plugin) getZones() []Zone {
+	p.zonesLock.RLock()
+	defer p.zonesLock.RUnlock()
+	return p.zones
+}
@@ -0,0 +1,182 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package presence
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+
+	"github.com/e154/bus"
+	"github.com/stretchr/testify/require"
+)
+
+var home = Zone{
+	Name: "home",
+	Polygon: []m.Point{
+		{Lon: 37.600, Lat: 55.700},
+		{Lon: 37.610, Lat: 55.700},
+		{Lon: 37.610, Lat: 55.710},
+		{Lon: 37.600, Lat: 55.710},
+	},
+}
+
+var work = Zone{
+	Name: "work",
+	Polygon: []m.Point{
+		{Lon: 37.700, Lat: 55.700},
+		{Lon: 37.710, Lat: 55.700},
+		{Lon: 37.710, Lat: 55.710},
+		{Lon: 37.700, Lat: 55.710},
+	},
+}
+
+func TestTracker(t *testing.T) {
+
+	tracker := NewTracker(100, nil)
+	zones := []Zone{home, work}
+
+	entered, left := tracker.Update(zones, m.Point{Lon: 37.605, Lat: 55.705}, 10)
+	require.Equal(t, []string{"home"}, entered)
+	require.Empty(t, left)
+
+	// about 60 meters east of the edge, inside the hysteresis
+	entered, left = tracker.Update(zones, m.Point{Lon: 37.611, Lat: 55.705}, 10)
+	require.Empty(t, entered)
+	require.Empty(t, left)
+	require.True(t, tracker.Inside("home"))
+
+	// the bad accuracy widens the hysteresis
+	_, left = tracker.Update(zones, m.Point{Lon: 37.613, Lat: 55.705}, 300)
+	require.Empty(t, left)
+
+	_, left = tracker.Update(zones, m.Point{Lon: 37.613, Lat: 55.705}, 10)
+	require.Equal(t, []string{"home"}, left)
+
+	entered, _ = tracker.Update(zones, m.Point{Lon: 37.705, Lat: 55.705}, 10)
+	require.Equal(t, []string{"work"}, entered)
+	require.Equal(t, []string{"work"}, tracker.Zones())
+
+	// the removed zone is left
+	_, left = tracker.Update([]Zone{home}, m.Point{Lon: 37.705, Lat: 55.705}, 10)
+	require.Equal(t, []string{"work"}, left)
+	require.Empty(t, tracker.Zones())
+
+	// the restored zones are not entered again
+	tracker = NewTracker(100, []string{"home"})
+	entered, _ = tracker.Update(zones, m.Point{Lon: 37.605, Lat: 55.705}, 10)
+	require.Empty(t, entered)
+}
+
+func TestParseLocation(t *testing.T) {
+
+	location, ok, err := ParseLocation([]byte(`{"_type":"location","lat":55.7,"lon":37.6,"acc":12,"batt":80,"tst":1700000000}`))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, m.Point{Lat: 55.7, Lon: 37.6}, location.Point)
+	require.Equal(t, 12.0, location.Accuracy)
+	require.Equal(t, int64(80), *location.Battery)
+	require.Equal(t, time.Unix(1700000000, 0), location.Time)
+
+	location, ok, err = ParseLocation([]byte(`{"latitude":55.7,"longitude":37.6}`))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Nil(t, location.Battery)
+
+	_, ok, err = ParseLocation([]byte(`{"_type":"waypoint","lat":55.7,"lon":37.6}`))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, err = ParseLocation([]byte(`{"lat":95,"lon":37.6}`))
+	require.Error(t, err)
+
+	_, _, err = ParseLocation([]byte(`[]`))
+	require.Error(t, err)
+}
+
+func TestTrigger(t *testing.T) {
+
+	alex := common.EntityId("presence.alex")
+	kate := common.EntityId("presence.kate")
+
+	trigger := NewTrigger(bus.NewBus())
+
+	var messages []EventZoneChanged
+	subscriber := triggers.Subscriber{
+		EntityId: &alex,
+		Payload: m.Attributes{
+			TriggerOptionZone:  {Name: TriggerOptionZone, Type: common.AttributeString, Value: "home"},
+			TriggerOptionEvent: {Name: TriggerOptionEvent, Type: common.AttributeString, Value: EventEnter},
+		},
+		Handler: func(_ string, msg interface{}) {
+			messages = append(messages, msg.(EventZoneChanged))
+		},
+	}
+	require.NoError(t, trigger.Subscribe(subscriber))
+
+	trigger.eventHandler("", EventZoneChanged{EntityId: alex, Event: EventEnter, Zone: "home"})
+	trigger.eventHandler("", EventZoneChanged{EntityId: alex, Event: EventLeave, Zone: "home"})
+	trigger.eventHandler("", EventZoneChanged{EntityId: alex, Event: EventEnter, Zone: "work"})
+	trigger.eventHandler("", EventZoneChanged{EntityId: kate, Event: EventEnter, Zone: "home"})
+	require.Len(t, messages, 1)
+	require.Equal(t, alex, messages[0].EntityId)
+
+	require.NoError(t, trigger.Unsubscribe(subscriber))
+	trigger.eventHandler("", EventZoneChanged{EntityId: alex, Event: EventEnter, Zone: "home"})
+	require.Len(t, messages, 1)
+
+	// any device
+	subscriber.EntityId = nil
+	require.NoError(t, trigger.Subscribe(subscriber))
+	trigger.eventHandler("", EventZoneChanged{EntityId: kate, Event: EventEnter, Zone: "home"})
+	require.Len(t, messages, 2)
+	require.Equal(t, kate, messages[1].EntityId)
+	require.NoError(t, trigger.Unsubscribe(subscriber))
+	trigger.eventHandler("", EventZoneChanged{EntityId: kate, Event: EventEnter, Zone: "home"})
+	require.Len(t, messages, 2)
+
+	require.Error(t, trigger.Subscribe(triggers.Subscriber{
+		Payload: m.Attributes{
+			TriggerOptionEvent: {Name: TriggerOptionEvent, Type: common.AttributeString, Value: "stay"},
+		},
+		Handler: func(_ string, msg interface{}) {},
+	}))
+}
+
+func TestServeHTTP(t *testing.T) {
+
+	actor := &Actor{token: "secret"}
+	serve := func(token, body string) int {
+		r := httptest.NewRequest(http.MethodPost, "/presence", strings.NewReader(body))
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		actor.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, serve("wrong", `{"_type":"lwt"}`))
+	require.Equal(t, http.StatusOK, serve("secret", `{"_type":"lwt"}`))
+	require.Equal(t, http.StatusRequestEntityTooLarge, serve("secret", strings.Repeat("a", MaxBodySize+1)))
+}
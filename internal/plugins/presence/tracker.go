@@ -0,0 +1,105 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package presence
+
+import (
+	"math"
+	"sort"
+
+	"github.com/e154/smart-home/internal/common/location"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// Zone ...
+type Zone struct {
+	Name    string
+	Polygon []m.Point
+}
+
+// Tracker keeps the zones the device is inside, a zone is entered as soon as the point is inside
+// the polygon and left when the point is farther than the hysteresis from its edge
+type Tracker struct {
+	hysteresis float64
+	inside     map[string]struct{}
+}
+
+// NewTracker ...
+func NewTracker(hysteresis float64, inside []string) *Tracker {
+	t := &Tracker{
+		hysteresis: hysteresis,
+		inside:     make(map[string]struct{}),
+	}
+	for _, name := range inside {
+		t.inside[name] = struct{}{}
+	}
+	return t
+}
+
+// Update the accuracy widens the hysteresis, the zones removed while inside are left
+func (t *Tracker) Update(zones []Zone, point m.Point, accuracy float64) (entered, left []string) {
+
+	margin := math.Max(t.hysteresis, accuracy)
+
+	known := make(map[string]struct{}, len(zones))
+	for _, zone := range zones {
+		if len(zone.Polygon) < 3 {
+			continue
+		}
+		known[zone.Name] = struct{}{}
+
+		_, wasInside := t.inside[zone.Name]
+		inside := location.PointInsidePolygon(point, zone.Polygon)
+		switch {
+		case !wasInside && inside:
+			t.inside[zone.Name] = struct{}{}
+			entered = append(entered, zone.Name)
+		case wasInside && !inside && location.GetDistanceToPolygonEdge(point, zone.Polygon)*1000 > margin:
+			delete(t.inside, zone.Name)
+			left = append(left, zone.Name)
+		}
+	}
+
+	for name := range t.inside {
+		if _, ok := known[name]; !ok {
+			delete(t.inside, name)
+			left = append(left, name)
+		}
+	}
+
+	sort.Strings(entered)
+	sort.Strings(left)
+
+	return
+}
+
+// Zones ...
+func (t *Tracker) Zones() []string {
+	list := make([]string, 0, len(t.inside))
+	for name := range t.inside {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Inside ...
+func (t *Tracker) Inside(name string) bool {
+	_, ok := t.inside[name]
+	return ok
+}
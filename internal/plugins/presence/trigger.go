@@ -0,0 +1,137 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package presence
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+
+	"github.com/e154/bus"
+)
+
+var _ triggers.ITrigger = (*Trigger)(nil)
+
+// zoneOptions the zone and the event of the subscription, empty matches any
+type zoneOptions struct {
+	zone  string
+	event string
+}
+
+func (o zoneOptions) match(event EventZoneChanged) bool {
+	if o.zone != "" && o.zone != event.Zone {
+		return false
+	}
+	return o.event == "" || o.event == event.Event
+}
+
+// Trigger starts the tasks when the tracked device enters or leaves a zone
+type Trigger struct {
+	eventBus     bus.Bus
+	functionName string
+	name         string
+	// the subscriptions without the entity are kept by the empty key
+	subscriptions *triggers.Subscriptions[common.EntityId, zoneOptions]
+}
+
+// NewTrigger ...
+func NewTrigger(eventBus bus.Bus) *Trigger {
+	return &Trigger{
+		eventBus:      eventBus,
+		functionName:  TriggerFunctionName,
+		name:          Name,
+		subscriptions: triggers.NewSubscriptions[common.EntityId, zoneOptions](),
+	}
+}
+
+// Name ...
+func (t *Trigger) Name() string {
+	return t.name
+}
+
+// AsyncAttach ...
+func (t *Trigger) AsyncAttach(wg *sync.WaitGroup) {
+
+	if err := t.eventBus.Subscribe("system/plugins/presence/+", t.eventHandler); err != nil {
+		log.Error(err.Error())
+	}
+
+	wg.Done()
+}
+
+// Detach ...
+func (t *Trigger) Detach() {
+	_ = t.eventBus.Unsubscribe("system/plugins/presence/+", t.eventHandler)
+}
+
+func (t *Trigger) eventHandler(_ string, event interface{}) {
+	v, ok := event.(EventZoneChanged)
+	if !ok {
+		return
+	}
+
+	for _, key := range []common.EntityId{v.EntityId, ""} {
+		for _, sub := range t.subscriptions.Get(key) {
+			if sub.Options.match(v) {
+				sub.Call(v.EntityId.String(), v)
+			}
+		}
+	}
+}
+
+// Subscribe ...
+func (t *Trigger) Subscribe(options triggers.Subscriber) error {
+
+	var opts zoneOptions
+	if attr, ok := options.Payload[TriggerOptionZone]; ok && attr != nil && attr.Value != nil {
+		opts.zone = attr.String()
+	}
+	if attr, ok := options.Payload[TriggerOptionEvent]; ok && attr != nil && attr.Value != nil {
+		opts.event = attr.String()
+	}
+	switch opts.event {
+	case "", EventEnter, EventLeave:
+	default:
+		return fmt.Errorf("unknown event \"%s\"", opts.event)
+	}
+
+	t.subscriptions.Add(subscriptionKey(options), options.Handler, opts)
+
+	return nil
+}
+
+// Unsubscribe ...
+func (t *Trigger) Unsubscribe(options triggers.Subscriber) error {
+	t.subscriptions.Remove(subscriptionKey(options), options.Handler)
+	return nil
+}
+
+func subscriptionKey(options triggers.Subscriber) common.EntityId {
+	if options.EntityId == nil {
+		return ""
+	}
+	return *options.EntityId
+}
+
+// FunctionName ...
+func (t *Trigger) FunctionName() string {
+	return t.functionName
+}
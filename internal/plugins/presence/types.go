@@ -0,0 +1,286 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package presence
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/plugins"
+)
+
+const (
+	// Name ...
+	Name = "presence"
+	// TriggerFunctionName ...
+	TriggerFunctionName = "automationTriggerPresence"
+)
+
+const (
+	// SettingToken bearer token or basic auth password of the http reports, the reports are disabled if empty
+	SettingToken = "token"
+	// SettingTopic mqtt topic of the reports, e.g. "owntracks/alex/phone"
+	SettingTopic = "topic"
+	// SettingHomeZone name of the home area
+	SettingHomeZone = "home_zone"
+	// SettingHysteresis meters the point must be outside the zone to leave it
+	SettingHysteresis = "hysteresis"
+	// SettingMaxAccuracy meters, the less accurate points are ignored
+	SettingMaxAccuracy = "max_accuracy"
+
+	// DefaultHomeZone ...
+	DefaultHomeZone = "home"
+	// DefaultHysteresis ...
+	DefaultHysteresis = 50
+	// DefaultMaxAccuracy ...
+	DefaultMaxAccuracy = 500
+)
+
+const (
+	// AttrLatitude ...
+	AttrLatitude = "latitude"
+	// AttrLongitude ...
+	AttrLongitude = "longitude"
+	// AttrAccuracy meters
+	AttrAccuracy = "accuracy"
+	// AttrBattery percent
+	AttrBattery = "battery"
+	// AttrZone the home zone or the first of the zones
+	AttrZone = "zone"
+	// AttrZones all zones the point is inside, separated by comma
+	AttrZones = "zones"
+	// AttrDistance meters to the home zone, zero inside
+	AttrDistance = "distance"
+	// AttrSource http or mqtt
+	AttrSource = "source"
+)
+
+const (
+	// StateHome ...
+	StateHome = "home"
+	// StateAway ...
+	StateAway = "away"
+	// StateZone ...
+	StateZone = "zone"
+)
+
+const (
+	// SourceHttp ...
+	SourceHttp = "http"
+	// SourceMqtt ...
+	SourceMqtt = "mqtt"
+
+	// MaxBodySize the maximum size of the location request body (bytes)
+	MaxBodySize = 8 << 10
+)
+
+// NewSettings ...
+func NewSettings() m.Attributes {
+	return m.Attributes{
+		SettingToken: {
+			Name: SettingToken,
+			Type: common.AttributeEncrypted,
+		},
+		SettingTopic: {
+			Name: SettingTopic,
+			Type: common.AttributeString,
+		},
+		SettingHomeZone: {
+			Name:  SettingHomeZone,
+			Type:  common.AttributeString,
+			Value: DefaultHomeZone,
+		},
+		SettingHysteresis: {
+			Name:  SettingHysteresis,
+			Type:  common.AttributeInt,
+			Value: DefaultHysteresis,
+		},
+		SettingMaxAccuracy: {
+			Name:  SettingMaxAccuracy,
+			Type:  common.AttributeInt,
+			Value: DefaultMaxAccuracy,
+		},
+	}
+}
+
+// NewAttr ...
+func NewAttr() m.Attributes {
+	return m.Attributes{
+		AttrLatitude: {
+			Name: AttrLatitude,
+			Type: common.AttributeFloat,
+		},
+		AttrLongitude: {
+			Name: AttrLongitude,
+			Type: common.AttributeFloat,
+		},
+		AttrAccuracy: {
+			Name: AttrAccuracy,
+			Type: common.AttributeFloat,
+		},
+		AttrBattery: {
+			Name: AttrBattery,
+			Type: common.AttributeInt,
+		},
+		AttrZone: {
+			Name: AttrZone,
+			Type: common.AttributeString,
+		},
+		AttrZones: {
+			Name: AttrZones,
+			Type: common.AttributeString,
+		},
+		AttrDistance: {
+			Name: AttrDistance,
+			Type: common.AttributeFloat,
+		},
+		AttrSource: {
+			Name: AttrSource,
+			Type: common.AttributeString,
+		},
+	}
+}
+
+// NewStates ...
+func NewStates() (states map[string]plugins.ActorState) {
+	states = map[string]plugins.ActorState{
+		StateHome: {
+			Name:        StateHome,
+			Description: "inside the home zone",
+		},
+		StateAway: {
+			Name:        StateAway,
+			Description: "outside of all zones",
+		},
+		StateZone: {
+			Name:        StateZone,
+			Description: "inside a zone other than home",
+		},
+	}
+	return
+}
+
+const (
+	// TriggerOptionZone area name, any zone if empty
+	TriggerOptionZone = "zone"
+	// TriggerOptionEvent enter or leave, both if empty
+	TriggerOptionEvent = "event"
+
+	// EventEnter ...
+	EventEnter = "enter"
+	// EventLeave ...
+	EventLeave = "leave"
+)
+
+// NewTriggerParams ...
+func NewTriggerParams() m.TriggerParams {
+	return m.TriggerParams{
+		Script:   true,
+		Entities: true,
+		Attributes: m.Attributes{
+			TriggerOptionZone: {
+				Name: TriggerOptionZone,
+				Type: common.AttributeString,
+			},
+			TriggerOptionEvent: {
+				Name: TriggerOptionEvent,
+				Type: common.AttributeString,
+			},
+		},
+	}
+}
+
+// EventZoneChanged is published on the "system/plugins/presence/{entity_id}" topic,
+// the trigger passes it to the task as is
+type EventZoneChanged struct {
+	EntityId common.EntityId `json:"entity_id"`
+	Event    string          `json:"event"`
+	Zone     string          `json:"zone"`
+	State    string          `json:"state"`
+	Point    m.Point         `json:"point"`
+	Time     time.Time       `json:"time"`
+}
+
+// Location is a report of the device, the OwnTracks location message or the plain
+// latitude, longitude, accuracy and battery fields are accepted
+type Location struct {
+	Point    m.Point
+	Accuracy float64
+	Battery  *int64
+	Time     time.Time
+}
+
+// ParseLocation returns false for the messages without a location, e.g. the OwnTracks waypoints
+func ParseLocation(data []byte) (location Location, ok bool, err error) {
+
+	var msg struct {
+		Type      string   `json:"_type"`
+		Lat       *float64 `json:"lat"`
+		Lon       *float64 `json:"lon"`
+		Acc       *float64 `json:"acc"`
+		Batt      *int64   `json:"batt"`
+		Tst       int64    `json:"tst"`
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+		Accuracy  *float64 `json:"accuracy"`
+		Battery   *int64   `json:"battery"`
+	}
+	if err = json.Unmarshal(data, &msg); err != nil {
+		err = fmt.Errorf("bad location: %w", err)
+		return
+	}
+	if msg.Type != "" && msg.Type != "location" {
+		return
+	}
+
+	lat, lon, acc, batt := msg.Lat, msg.Lon, msg.Acc, msg.Batt
+	if lat == nil || lon == nil {
+		lat, lon = msg.Latitude, msg.Longitude
+	}
+	if acc == nil {
+		acc = msg.Accuracy
+	}
+	if batt == nil {
+		batt = msg.Battery
+	}
+	if lat == nil || lon == nil {
+		return
+	}
+	if *lat < -90 || *lat > 90 || *lon < -180 || *lon > 180 {
+		err = fmt.Errorf("bad location: %f %f", *lat, *lon)
+		return
+	}
+
+	location = Location{
+		Point:   m.Point{Lat: *lat, Lon: *lon},
+		Battery: batt,
+		Time:    time.Now(),
+	}
+	if acc != nil {
+		location.Accuracy = *acc
+	}
+	if msg.Tst > 0 {
+		location.Time = time.Unix(msg.Tst, 0)
+	}
+	ok = true
+	return
+}
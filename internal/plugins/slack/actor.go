@@ -48,7 +48,7 @@ func NewActor(entity *m.Entity,
 
 	actor := &Actor{
 		BaseActor: supervisor.NewBaseActor(entity, service),
-		notify:    notify2.NewNotify(service.Adaptors()),
+		notify:    notify2.NewNotify(service.Adaptors(), service.EventBus()),
 		UserName:  entity.Settings[AttrUserName].String(),
 		Token:     token,
 		api:       slack.New(token),
@@ -63,7 +63,7 @@ func (e *Actor) Destroy() {
 }
 
 func (e *Actor) Spawn() {
-	e.notify.Start()
+	e.notify.Start(e, Name, &e.Id)
 	e.Service.EventBus().Subscribe(notify2.TopicNotify, e.eventHandler, false)
 }
 
@@ -0,0 +1,214 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+// EventStateChanged
+
+package state_held
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	"github.com/e154/smart-home/pkg/plugins"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+
+	"github.com/e154/bus"
+)
+
+var _ triggers.ITrigger = (*Trigger)(nil)
+
+type subscribe struct {
+	entityId common.EntityId
+	hold     Hold
+	// the timer is set while the state is held, fired is set until the state reverts
+	timer *time.Timer
+	gen   uint64
+	fired bool
+	since time.Time
+	// the handler was unsubscribed, the event may come after the removal
+	removed bool
+}
+
+// Trigger ...
+type Trigger struct {
+	eventBus     bus.Bus
+	supervisor   plugins.Supervisor
+	functionName string
+	name         string
+	subscribers  *triggers.Subscriptions[common.EntityId, *subscribe]
+	// guards the timers of the subscriptions
+	sync.Mutex
+}
+
+// NewTrigger ...
+func NewTrigger(eventBus bus.Bus, supervisor plugins.Supervisor) triggers.ITrigger {
+	return &Trigger{
+		eventBus:     eventBus,
+		supervisor:   supervisor,
+		subscribers:  triggers.NewSubscriptions[common.EntityId, *subscribe](),
+		functionName: FunctionName,
+		name:         Name,
+	}
+}
+
+// Name ...
+func (t *Trigger) Name() string {
+	return t.name
+}
+
+// AsyncAttach ...
+func (t *Trigger) AsyncAttach(wg *sync.WaitGroup) {
+
+	if err := t.eventBus.Subscribe("system/entities/+", t.eventHandler); err != nil {
+		log.Error(err.Error())
+	}
+
+	wg.Done()
+}
+
+func (t *Trigger) eventHandler(_ string, event interface{}) {
+	v, ok := event.(events.EventStateChanged)
+	if !ok {
+		return
+	}
+
+	subscribers := t.subscribers.Get(v.EntityId)
+
+	t.Lock()
+	defer t.Unlock()
+
+	for _, sub := range subscribers {
+		t.update(sub, v.NewState)
+	}
+}
+
+// update starts the timer when the state matches, the state revert cancels it
+func (t *Trigger) update(subscription *triggers.Subscription[*subscribe], state events.EventEntityState) {
+	sub := subscription.Options
+	if sub.removed {
+		return
+	}
+	if !sub.hold.Match(state) {
+		if sub.timer != nil {
+			sub.timer.Stop()
+		}
+		sub.timer = nil
+		sub.fired = false
+		return
+	}
+	if sub.timer != nil || sub.fired {
+		return
+	}
+
+	sub.since = time.Now()
+	sub.gen++
+	gen := sub.gen
+	sub.timer = time.AfterFunc(sub.hold.Duration, func() {
+		t.fire(subscription, gen, state)
+	})
+}
+
+func (t *Trigger) fire(subscription *triggers.Subscription[*subscribe], gen uint64, state events.EventEntityState) {
+	sub := subscription.Options
+
+	t.Lock()
+	// the state was reverted or the subscriber was removed
+	if sub.timer == nil || sub.gen != gen {
+		t.Unlock()
+		return
+	}
+	sub.timer = nil
+	sub.fired = true
+	message := TriggerStateHeldMessage{
+		EntityId: sub.entityId,
+		State:    state.StateName(),
+		Value:    state.Value,
+		Duration: int64(sub.hold.Duration / time.Second),
+		Since:    sub.since,
+	}
+	if sub.hold.Threshold != nil {
+		message.Attribute = sub.hold.Threshold.Attribute
+		if value, ok := state.Number(sub.hold.Threshold.Attribute); ok {
+			message.Value = value
+		}
+	}
+	t.Unlock()
+
+	subscription.Call(sub.entityId.String(), message)
+}
+
+// Subscribe ...
+func (t *Trigger) Subscribe(options triggers.Subscriber) error {
+	if options.EntityId == nil {
+		return fmt.Errorf("entity id is nil")
+	}
+	hold, err := NewHold(options.Payload)
+	if err != nil {
+		return err
+	}
+
+	subscription := t.subscribers.Add(*options.EntityId, options.Handler, &subscribe{
+		entityId: *options.EntityId,
+		hold:     hold,
+	})
+
+	t.Lock()
+	defer t.Unlock()
+
+	// the entity may already be in the state
+	if t.supervisor != nil {
+		if actor, err := t.supervisor.GetActorById(*options.EntityId); err == nil {
+			if state := actor.GetCurrentState(); state != nil {
+				t.update(subscription, *state)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Unsubscribe ...
+func (t *Trigger) Unsubscribe(options triggers.Subscriber) error {
+	if options.EntityId == nil {
+		return fmt.Errorf("entity id is nil")
+	}
+
+	removed, _ := t.subscribers.Remove(*options.EntityId, options.Handler)
+
+	t.Lock()
+	defer t.Unlock()
+
+	for _, subscription := range removed {
+		sub := subscription.Options
+		sub.removed = true
+		if sub.timer != nil {
+			sub.timer.Stop()
+			sub.timer = nil
+		}
+	}
+
+	return nil
+}
+
+// FunctionName ...
+func (t *Trigger) FunctionName() string {
+	return t.functionName
+}
@@ -0,0 +1,113 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package state_held
+
+import (
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+
+	"github.com/e154/bus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestTrigger(t *testing.T) {
+
+	var entityId = common.EntityId("binary_sensor.door")
+
+	trigger := NewTrigger(bus.NewBus(), nil).(*Trigger)
+
+	counter := atomic.NewInt32(0)
+	subscriber := triggers.Subscriber{
+		EntityId: &entityId,
+		Payload: m.Attributes{
+			AttrState:    {Name: AttrState, Type: common.AttributeString, Value: "open"},
+			AttrDuration: {Name: AttrDuration, Type: common.AttributeInt, Value: 1},
+		},
+		Handler: func(_ string, msg interface{}) {
+			require.Equal(t, "open", msg.(TriggerStateHeldMessage).State)
+			counter.Inc()
+		},
+	}
+	require.NoError(t, trigger.Subscribe(subscriber))
+
+	changed := func(state string) {
+		trigger.eventHandler("", events.EventStateChanged{
+			EntityId: entityId,
+			NewState: events.EventEntityState{State: &events.EntityState{Name: state}},
+		})
+	}
+
+	// the state reverts before the duration
+	changed("open")
+	time.Sleep(time.Millisecond * 500)
+	changed("closed")
+	time.Sleep(time.Millisecond * 700)
+	require.Equal(t, int32(0), counter.Load())
+
+	// the state is held, the repeated events do not restart the timer
+	changed("open")
+	time.Sleep(time.Millisecond * 500)
+	changed("open")
+	require.Eventually(t, func() bool { return counter.Load() == 1 }, time.Second, time.Millisecond*10)
+
+	// fires once until the state reverts
+	changed("open")
+	time.Sleep(time.Millisecond * 1200)
+	require.Equal(t, int32(1), counter.Load())
+
+	// unsubscribe cancels the timer
+	changed("closed")
+	changed("open")
+	require.NoError(t, trigger.Unsubscribe(subscriber))
+	time.Sleep(time.Millisecond * 1200)
+	require.Equal(t, int32(1), counter.Load())
+}
+
+func TestNewHold(t *testing.T) {
+
+	_, err := NewHold(m.Attributes{
+		AttrState: {Name: AttrState, Type: common.AttributeString, Value: "open"},
+	})
+	require.Error(t, err)
+
+	_, err = NewHold(m.Attributes{
+		AttrDuration: {Name: AttrDuration, Type: common.AttributeInt, Value: 10},
+	})
+	require.Error(t, err)
+
+	hold, err := NewHold(m.Attributes{
+		AttrDuration: {Name: AttrDuration, Type: common.AttributeInt, Value: 10},
+		"attribute":  {Name: "attribute", Type: common.AttributeString, Value: "temperature"},
+		"below":      {Name: "below", Type: common.AttributeFloat, Value: 5.0},
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Second*10, hold.Duration)
+	require.True(t, hold.Match(events.EventEntityState{Attributes: m.Attributes{
+		"temperature": {Name: "temperature", Type: common.AttributeFloat, Value: 3.0},
+	}}))
+	require.False(t, hold.Match(events.EventEntityState{Attributes: m.Attributes{
+		"temperature": {Name: "temperature", Type: common.AttributeFloat, Value: 7.0},
+	}}))
+}
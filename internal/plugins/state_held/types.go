@@ -0,0 +1,127 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package state_held
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/internal/plugins/numeric_state"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+const (
+	Name         = "state_held"
+	FunctionName = "automationTriggerStateHeld"
+	Version      = "0.0.1"
+
+	// AttrState ...
+	AttrState = "state"
+	// AttrDuration seconds
+	AttrDuration = "duration"
+)
+
+func NewTriggerParams() m.TriggerParams {
+	return m.TriggerParams{
+		Script:   true,
+		Entities: true,
+		Required: []string{AttrDuration},
+		Attributes: m.Attributes{
+			AttrState: {
+				Name: AttrState,
+				Type: common.AttributeString,
+			},
+			numeric_state.AttrAttribute: {
+				Name: numeric_state.AttrAttribute,
+				Type: common.AttributeString,
+			},
+			numeric_state.AttrAbove: {
+				Name: numeric_state.AttrAbove,
+				Type: common.AttributeFloat,
+			},
+			numeric_state.AttrBelow: {
+				Name: numeric_state.AttrBelow,
+				Type: common.AttributeFloat,
+			},
+			AttrDuration: {
+				Name: AttrDuration,
+				Type: common.AttributeInt,
+			},
+		},
+	}
+}
+
+// Hold describes the state which must be held for the duration
+type Hold struct {
+	State     string
+	Threshold *numeric_state.Threshold
+	Duration  time.Duration
+}
+
+// NewHold reads the hold params from the trigger payload
+func NewHold(payload m.Attributes) (hold Hold, err error) {
+	if attr, ok := payload[AttrDuration]; ok && attr != nil && attr.Value != nil {
+		hold.Duration = time.Duration(attr.Int64()) * time.Second
+	}
+	if hold.Duration <= 0 {
+		err = fmt.Errorf("duration attribute is required")
+		return
+	}
+	if attr, ok := payload[AttrState]; ok && attr != nil && attr.Value != nil {
+		hold.State = attr.String()
+	}
+	_, above := payload[numeric_state.AttrAbove]
+	_, below := payload[numeric_state.AttrBelow]
+	if above || below {
+		var threshold numeric_state.Threshold
+		if threshold, err = numeric_state.NewThreshold(payload); err != nil {
+			return
+		}
+		hold.Threshold = &threshold
+	}
+	if hold.State == "" && hold.Threshold == nil {
+		err = fmt.Errorf("state or threshold attribute is required")
+	}
+	return
+}
+
+// Match ...
+func (h Hold) Match(state events.EventEntityState) bool {
+	if h.State != "" && state.StateName() != h.State {
+		return false
+	}
+	if h.Threshold != nil {
+		value, ok := state.Number(h.Threshold.Attribute)
+		if !ok || !h.Threshold.Match(value) {
+			return false
+		}
+	}
+	return true
+}
+
+type TriggerStateHeldMessage struct {
+	EntityId  common.EntityId `json:"entity_id"`
+	State     string          `json:"state"`
+	Attribute string          `json:"attribute"`
+	Value     interface{}     `json:"value"`
+	Duration  int64           `json:"duration"`
+	Since     time.Time       `json:"since"`
+}
@@ -48,7 +48,7 @@ const (
 	// AttrAzimuth ...
 	AttrAzimuth = "azimuth"
 	// AttrElevation ...
-	AttrElevation = "elevation"
+	AttrElevation = common.SunAttrElevation
 	// AttrSunrise ...
 	AttrSunrise = "sunrise"
 	// AttrSunset ...
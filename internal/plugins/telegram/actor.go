@@ -61,7 +61,7 @@ func NewActor(entity *m.Entity,
 		actionPool:  make(chan events.EventCallEntityAction, 1000),
 		isStarted:   atomic.NewBool(false),
 		AccessToken: settings[AttrToken].Decrypt(),
-		notify:      notify.NewNotify(service.Adaptors()),
+		notify:      notify.NewNotify(service.Adaptors(), service.EventBus()),
 	}
 
 	if actor.Attrs == nil {
@@ -128,7 +128,7 @@ func (e *Actor) Spawn() {
 	}
 
 	_ = e.Service.EventBus().Subscribe(notify.TopicNotify, e.eventHandler, false)
-	e.notify.Start()
+	e.notify.Start(e, Name, &e.Id)
 
 	e.BaseActor.Spawn()
 }
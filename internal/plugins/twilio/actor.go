@@ -59,7 +59,7 @@ func NewActor(entity *m.Entity,
 		sid:       sid,
 		from:      entity.Settings[AttrFrom].String(),
 		authToken: authToken,
-		notify:    notify2.NewNotify(service.Adaptors()),
+		notify:    notify2.NewNotify(service.Adaptors(), service.EventBus()),
 	}
 
 	return actor
@@ -72,7 +72,7 @@ func (e *Actor) Destroy() {
 
 func (e *Actor) Spawn() {
 	_ = e.Service.EventBus().Subscribe(notify2.TopicNotify, e.eventHandler, false)
-	e.notify.Start()
+	e.notify.Start(e, Name, &e.Id)
 }
 
 // Send ...
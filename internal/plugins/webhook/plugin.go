@@ -28,6 +28,7 @@ import (
 	"github.com/e154/smart-home/pkg/logger"
 	m "github.com/e154/smart-home/pkg/models"
 	"github.com/e154/smart-home/pkg/plugins"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
 )
 
 var (
@@ -46,7 +47,9 @@ func init() {
 
 type plugin struct {
 	*plugins.Plugin
-	ticker *time.Ticker
+	ticker    *time.Ticker
+	registrar triggers.IRegistrar
+	trigger   *Trigger
 }
 
 // New ...
@@ -64,6 +67,36 @@ func (p *plugin) Load(ctx context.Context, service plugins.Service) (err error)
 		return
 	}
 
+	// register trigger
+	if triggersPlugin, ok := service.Plugins()[triggers.Name]; ok {
+		if p.registrar, ok = triggersPlugin.(triggers.IRegistrar); ok {
+			p.trigger = NewTrigger(p.Service.EventBus())
+			if err = p.registrar.RegisterTrigger(p.trigger); err != nil {
+				log.Error(err.Error())
+				return
+			}
+		}
+	}
+
+	return nil
+}
+
+// Unload ...
+func (p *plugin) Unload(ctx context.Context) (err error) {
+	if err = p.Plugin.Unload(ctx); err != nil {
+		return
+	}
+
+	if p.trigger != nil {
+		p.trigger.Detach()
+	}
+	if p.registrar != nil {
+		if err = p.registrar.UnregisterTrigger(Name); err != nil {
+			log.Error(err.Error())
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -80,7 +113,7 @@ func (p *plugin) Name() string {
 
 // Depends ...
 func (p *plugin) Depends() []string {
-	return nil
+	return []string{"triggers"}
 }
 
 // Options ...
@@ -91,11 +124,17 @@ func (p *plugin) Options() m.PluginOptions {
 		ActorAttrs:       NewAttr(),
 		ActorSetts:       NewSettings(),
 		ActorStates:      plugins.ToEntityStateShort(NewStates()),
+		Triggers:         true,
+		TriggerParams:    NewTriggerParams(),
 	}
 }
 
 // ServeHTTP ...
 func (p *plugin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// the triggers are served first, the actors get the rest of the requests
+	if p.trigger != nil && p.trigger.ServeHTTP(w, r) {
+		return
+	}
 	p.Actors.Range(func(key, value any) bool {
 		actor := value.(*Actor)
 		actor.ServeHTTP(w, r)
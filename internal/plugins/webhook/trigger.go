@@ -0,0 +1,421 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/e154/smart-home/internal/common/urlpath"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+
+	"github.com/e154/bus"
+	"github.com/google/uuid"
+)
+
+var _ triggers.ITrigger = (*Trigger)(nil)
+
+// the credentials of the request are not passed to the task, the payload is saved in the run history
+var (
+	secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", SignatureHeader}
+	secretQuery   = []string{"access_token"}
+)
+
+type subscribe struct {
+	path     urlpath.Path
+	token    string
+	secret   string
+	methods  []string
+	response bool
+	timeout  time.Duration
+}
+
+// Trigger starts the tasks by the http request
+type Trigger struct {
+	eventBus     bus.Bus
+	functionName string
+	name         string
+	subscribers  *triggers.Subscriptions[string, *subscribe]
+	sync.Mutex
+	waiters map[string]*waiter
+}
+
+// waiter is the request waiting for the task run
+type waiter struct {
+	runs     chan *m.TaskRun
+	rejected chan struct{}
+}
+
+// NewTrigger ...
+func NewTrigger(eventBus bus.Bus) *Trigger {
+	return &Trigger{
+		eventBus:     eventBus,
+		functionName: TriggerFunctionName,
+		name:         Name,
+		subscribers:  triggers.NewSubscriptions[string, *subscribe](),
+		waiters:      make(map[string]*waiter),
+	}
+}
+
+// Name ...
+func (t *Trigger) Name() string {
+	return t.name
+}
+
+// AsyncAttach ...
+func (t *Trigger) AsyncAttach(wg *sync.WaitGroup) {
+
+	if err := t.eventBus.Subscribe("system/automation/tasks/+", t.eventHandler); err != nil {
+		log.Error(err.Error())
+	}
+	if err := t.eventBus.Subscribe("system/automation/triggers/+", t.eventHandler); err != nil {
+		log.Error(err.Error())
+	}
+
+	wg.Done()
+}
+
+// Detach ...
+func (t *Trigger) Detach() {
+	_ = t.eventBus.Unsubscribe("system/automation/tasks/+", t.eventHandler)
+	_ = t.eventBus.Unsubscribe("system/automation/triggers/+", t.eventHandler)
+}
+
+// eventHandler passes the finished run or the rejection of the trigger script to the waiting request
+func (t *Trigger) eventHandler(_ string, event interface{}) {
+	switch v := event.(type) {
+	case events.EventTaskRunFinished:
+		if v.Run == nil {
+			return
+		}
+		var payload struct {
+			Payload struct {
+				RequestId string `json:"request_id"`
+			} `json:"payload"`
+		}
+		if err := json.Unmarshal(v.Run.Payload, &payload); err != nil {
+			return
+		}
+		if w := t.waiter(payload.Payload.RequestId); w != nil {
+			select {
+			case w.runs <- v.Run:
+			default:
+			}
+		}
+	case events.EventTriggerNotPassed:
+		if v.Args == nil {
+			return
+		}
+		message, ok := v.Args.Payload.(TriggerWebhookMessage)
+		if !ok {
+			return
+		}
+		if w := t.waiter(message.RequestId); w != nil {
+			select {
+			case w.rejected <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (t *Trigger) waiter(requestId string) *waiter {
+	if requestId == "" {
+		return nil
+	}
+	t.Lock()
+	defer t.Unlock()
+	return t.waiters[requestId]
+}
+
+// ServeHTTP returns false if there is no trigger with the path of the request
+func (t *Trigger) ServeHTTP(w http.ResponseWriter, r *http.Request) bool {
+
+	var matched []*triggers.Subscription[*subscribe]
+	var params map[string]string
+	for _, sub := range t.subscribers.All() {
+		if match, ok := sub.Options.path.Match(r.URL.Path); ok {
+			matched = append(matched, sub)
+			params = match.Params
+		}
+	}
+	if len(matched) == 0 {
+		return false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return true
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return true
+	}
+
+	var allowed []*triggers.Subscription[*subscribe]
+	var status = http.StatusUnauthorized
+	for _, sub := range matched {
+		if !sub.Options.checkMethod(r.Method) {
+			status = http.StatusMethodNotAllowed
+			continue
+		}
+		if !sub.Options.checkToken(r) || !sub.Options.checkSignature(r, body) {
+			continue
+		}
+		allowed = append(allowed, sub)
+	}
+	if len(allowed) == 0 {
+		http.Error(w, http.StatusText(status), status)
+		return true
+	}
+
+	message := newMessage(r, params, body)
+
+	// the first finished run is returned
+	var response bool
+	var timeout time.Duration
+	for _, sub := range allowed {
+		if sub.Options.response {
+			response = true
+			timeout = max(timeout, sub.Options.timeout)
+		}
+	}
+	var wt *waiter
+	if response {
+		wt = &waiter{
+			runs:     make(chan *m.TaskRun, 1),
+			rejected: make(chan struct{}, len(allowed)),
+		}
+		t.Lock()
+		t.waiters[message.RequestId] = wt
+		t.Unlock()
+		defer func() {
+			t.Lock()
+			delete(t.waiters, message.RequestId)
+			t.Unlock()
+		}()
+	}
+
+	for _, sub := range allowed {
+		sub.Call(message.Path, message)
+	}
+
+	if !response {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+		return true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var rejected int
+	for {
+		select {
+		case run := <-wt.runs:
+			writeRun(w, run)
+		case <-wt.rejected:
+			// the request is answered when the scripts of all triggers rejected it
+			if rejected++; rejected < len(allowed) {
+				continue
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case <-timer.C:
+			http.Error(w, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		case <-r.Context().Done():
+		}
+		return true
+	}
+}
+
+// Subscribe ...
+func (t *Trigger) Subscribe(options triggers.Subscriber) error {
+	sub, err := newSubscribe(options)
+	if err != nil {
+		return err
+	}
+
+	t.subscribers.Add(triggerPath(options), options.Handler, sub)
+	log.Infof("trigger '%s' subscribe path '%s'", t.name, triggerPath(options))
+
+	return nil
+}
+
+// Unsubscribe ...
+func (t *Trigger) Unsubscribe(options triggers.Subscriber) error {
+	t.subscribers.Remove(triggerPath(options), options.Handler)
+	return nil
+}
+
+// FunctionName ...
+func (t *Trigger) FunctionName() string {
+	return t.functionName
+}
+
+func triggerPath(options triggers.Subscriber) string {
+	if attr, ok := options.Payload[TriggerOptionPath]; ok && attr != nil && attr.Value != nil {
+		return attr.String()
+	}
+	return ""
+}
+
+func newSubscribe(options triggers.Subscriber) (*subscribe, error) {
+	path := triggerPath(options)
+	if path == "" {
+		return nil, fmt.Errorf("trigger '%s' without path", Name)
+	}
+
+	sub := &subscribe{
+		path:    urlpath.New(path),
+		timeout: DefaultResponseTimeout * time.Second,
+	}
+	if attr, ok := options.Payload[TriggerOptionToken]; ok && attr != nil && attr.Value != nil {
+		sub.token = attr.Decrypt()
+	}
+	if attr, ok := options.Payload[TriggerOptionSecret]; ok && attr != nil && attr.Value != nil {
+		sub.secret = attr.Decrypt()
+	}
+	if attr, ok := options.Payload[TriggerOptionMethods]; ok && attr != nil && attr.Value != nil {
+		for _, method := range strings.Split(attr.String(), ",") {
+			if method = strings.ToUpper(strings.TrimSpace(method)); method != "" {
+				sub.methods = append(sub.methods, method)
+			}
+		}
+	}
+	if attr, ok := options.Payload[TriggerOptionResponse]; ok && attr != nil && attr.Value != nil {
+		sub.response = attr.Bool()
+	}
+	if attr, ok := options.Payload[TriggerOptionTimeout]; ok && attr != nil && attr.Value != nil && attr.Int64() > 0 {
+		sub.timeout = time.Duration(attr.Int64()) * time.Second
+	}
+	return sub, nil
+}
+
+func (s *subscribe) checkMethod(method string) bool {
+	return len(s.methods) == 0 || slices.Contains(s.methods, method)
+}
+
+func (s *subscribe) checkToken(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	token := getAccessToken(r)
+	token = strings.TrimPrefix(token, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// checkSignature checks the hmac sha256 of the body, the "sha256=" prefix of the signature is optional
+func (s *subscribe) checkSignature(r *http.Request, body []byte) bool {
+	if s.secret == "" {
+		return true
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(SignatureHeader), "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(s.secret))
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+func newMessage(r *http.Request, params map[string]string, body []byte) TriggerWebhookMessage {
+	message := TriggerWebhookMessage{
+		RequestId: uuid.NewString(),
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Params:    params,
+		Query:     r.URL.Query(),
+		Headers:   make(map[string]string),
+	}
+	for k, v := range r.Header {
+		if slices.Contains(secretHeaders, k) {
+			continue
+		}
+		message.Headers[k] = strings.Join(v, ",")
+	}
+	for _, k := range secretQuery {
+		delete(message.Query, k)
+	}
+
+	if len(body) == 0 {
+		return message
+	}
+	message.Body = string(body)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err == nil {
+			message.Body = value
+		}
+	case "application/x-www-form-urlencoded":
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		if err := r.ParseForm(); err == nil {
+			message.Body = r.PostForm
+		}
+	}
+	return message
+}
+
+// writeRun writes the result of the last action, the result is returned as is if it is json
+func writeRun(w http.ResponseWriter, run *m.TaskRun) {
+	switch run.Status {
+	case common.TaskRunCompleted:
+	case common.TaskRunFailed:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": run.Error})
+		return
+	default:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var result = "null"
+	for i := len(run.Trace) - 1; i >= 0; i-- {
+		if run.Trace[i].Type == common.StepTypeAction.String() && run.Trace[i].Result != "" {
+			result = run.Trace[i].Result
+			break
+		}
+	}
+	if !json.Valid([]byte(result)) {
+		b, _ := json.Marshal(result)
+		result = string(b)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(result))
+}
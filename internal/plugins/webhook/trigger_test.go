@@ -0,0 +1,193 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/plugins/triggers"
+
+	"github.com/stretchr/testify/require"
+)
+
+func options(values map[string]interface{}) m.Attributes {
+	attrs := NewTriggerParams().Attributes
+	for name, value := range values {
+		attrs[name].Value = value
+	}
+	return attrs
+}
+
+func TestTrigger(t *testing.T) {
+
+	serve := func(tr *Trigger, r *http.Request) (*httptest.ResponseRecorder, bool) {
+		w := httptest.NewRecorder()
+		ok := tr.ServeHTTP(w, r)
+		return w, ok
+	}
+
+	t.Run("auth", func(t *testing.T) {
+		tr := NewTrigger(nil)
+		var messages []TriggerWebhookMessage
+		require.NoError(t, tr.Subscribe(triggers.Subscriber{
+			Handler: func(_ string, msg interface{}) {
+				messages = append(messages, msg.(TriggerWebhookMessage))
+			},
+			Payload: options(map[string]interface{}{
+				TriggerOptionPath:    "/webhook/door/:action",
+				TriggerOptionToken:   "secret-token",
+				TriggerOptionMethods: "post, put",
+			}),
+		}))
+
+		_, ok := serve(tr, httptest.NewRequest(http.MethodPost, "/webhook/window/open", nil))
+		require.False(t, ok)
+
+		w, ok := serve(tr, httptest.NewRequest(http.MethodGet, "/webhook/door/open?access_token=secret-token", nil))
+		require.True(t, ok)
+		require.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+		w, _ = serve(tr, httptest.NewRequest(http.MethodPost, "/webhook/door/open", nil))
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Empty(t, messages)
+
+		r := httptest.NewRequest(http.MethodPost, "/webhook/door/open?id=1", strings.NewReader(`{"value":1}`))
+		r.Header.Set("Authorization", "Bearer secret-token")
+		r.Header.Set("Content-Type", "application/json")
+		w, _ = serve(tr, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, messages, 1)
+		require.Equal(t, "open", messages[0].Params["action"])
+		require.Equal(t, []string{"1"}, messages[0].Query["id"])
+		require.Equal(t, map[string]interface{}{"value": float64(1)}, messages[0].Body)
+		require.NotEmpty(t, messages[0].RequestId)
+
+		// the credentials are not passed to the task
+		w, _ = serve(tr, httptest.NewRequest(http.MethodPost, "/webhook/door/open?access_token=secret-token&id=2", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, messages, 2)
+		require.NotContains(t, messages[1].Query, "access_token")
+		require.NotContains(t, messages[0].Headers, "Authorization")
+		require.Equal(t, "application/json", messages[0].Headers["Content-Type"])
+	})
+
+	t.Run("body size", func(t *testing.T) {
+		tr := NewTrigger(nil)
+		require.NoError(t, tr.Subscribe(triggers.Subscriber{
+			Handler: func(_ string, msg interface{}) {},
+			Payload: options(map[string]interface{}{
+				TriggerOptionPath: "/webhook/upload",
+			}),
+		}))
+
+		r := httptest.NewRequest(http.MethodPost, "/webhook/upload", strings.NewReader(strings.Repeat("a", MaxBodySize+1)))
+		w, _ := serve(tr, r)
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+
+	t.Run("signature", func(t *testing.T) {
+		tr := NewTrigger(nil)
+		var calls int
+		require.NoError(t, tr.Subscribe(triggers.Subscriber{
+			Handler: func(_ string, msg interface{}) { calls++ },
+			Payload: options(map[string]interface{}{
+				TriggerOptionPath:   "/webhook/github",
+				TriggerOptionSecret: "key",
+			}),
+		}))
+
+		const body = `{"action":"push"}`
+		mac := hmac.New(sha256.New, []byte("key"))
+		mac.Write([]byte(body))
+
+		r := httptest.NewRequest(http.MethodPost, "/webhook/github", strings.NewReader(body))
+		r.Header.Set(SignatureHeader, "sha256=00")
+		w, _ := serve(tr, r)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		r = httptest.NewRequest(http.MethodPost, "/webhook/github", strings.NewReader(body))
+		r.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		w, _ = serve(tr, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("response", func(t *testing.T) {
+		tr := NewTrigger(nil)
+		handler := func(_ string, msg interface{}) {
+			payload, _ := json.Marshal(&events.TriggerMessage{Payload: msg})
+			tr.eventHandler("", events.EventTaskRunFinished{Run: &m.TaskRun{
+				Status:  common.TaskRunCompleted,
+				Payload: payload,
+				Trace: []*m.TaskRunStep{
+					{Type: common.StepTypeAction.String(), Result: `{"open":true}`},
+					{Type: common.StepTypeDelay.String()},
+				},
+			}})
+		}
+		subscriber := triggers.Subscriber{
+			Handler: handler,
+			Payload: options(map[string]interface{}{
+				TriggerOptionPath:     "/webhook/state",
+				TriggerOptionResponse: true,
+			}),
+		}
+		require.NoError(t, tr.Subscribe(subscriber))
+
+		w, _ := serve(tr, httptest.NewRequest(http.MethodGet, "/webhook/state", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, `{"open":true}`, w.Body.String())
+
+		require.NoError(t, tr.Unsubscribe(subscriber))
+		_, ok := serve(tr, httptest.NewRequest(http.MethodGet, "/webhook/state", nil))
+		require.False(t, ok)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		tr := NewTrigger(nil)
+		// the trigger script rejects the message
+		handler := func(_ string, msg interface{}) {
+			tr.eventHandler("", events.EventTriggerNotPassed{Args: &events.TriggerMessage{Payload: msg}})
+		}
+		require.NoError(t, tr.Subscribe(triggers.Subscriber{
+			Handler: handler,
+			Payload: options(map[string]interface{}{
+				TriggerOptionPath:     "/webhook/state",
+				TriggerOptionResponse: true,
+				TriggerOptionTimeout:  60,
+			}),
+		}))
+
+		started := time.Now()
+		w, _ := serve(tr, httptest.NewRequest(http.MethodGet, "/webhook/state", nil))
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Less(t, time.Since(started), time.Second*5)
+	})
+}
@@ -29,8 +29,6 @@ const (
 	Name = "webhook"
 )
 
-const ()
-
 const (
 	AttrToken = "token"
 	AttrPath  = "path"
@@ -96,3 +94,72 @@ func NewSettings() m.Attributes {
 func NewStates() (states map[string]plugins.ActorState) {
 	return nil
 }
+
+const (
+	// TriggerFunctionName ...
+	TriggerFunctionName = "automationTriggerWebhook"
+
+	// TriggerOptionPath url path of the webhook, e.g. "/webhook/door/:action"
+	TriggerOptionPath = "path"
+	// TriggerOptionToken bearer token, passed in the authorization header or the access_token query
+	TriggerOptionToken = "token"
+	// TriggerOptionSecret hmac sha256 secret of the body, the signature is passed in the X-Signature-256 header
+	TriggerOptionSecret = "secret"
+	// TriggerOptionMethods allowed methods separated by comma, any method if empty
+	TriggerOptionMethods = "methods"
+	// TriggerOptionResponse the request waits for the task, the result of the last action is returned
+	TriggerOptionResponse = "response"
+	// TriggerOptionTimeout response timeout (sec)
+	TriggerOptionTimeout = "timeout"
+
+	// SignatureHeader ...
+	SignatureHeader = "X-Signature-256"
+	// DefaultResponseTimeout ...
+	DefaultResponseTimeout = 10
+	// MaxBodySize the maximum size of the request body (bytes)
+	MaxBodySize = 1 << 20
+)
+
+// NewTriggerParams ...
+func NewTriggerParams() m.TriggerParams {
+	return m.TriggerParams{
+		Script: true,
+		Attributes: m.Attributes{
+			TriggerOptionPath: {
+				Name: TriggerOptionPath,
+				Type: common.AttributeString,
+			},
+			TriggerOptionToken: {
+				Name: TriggerOptionToken,
+				Type: common.AttributeEncrypted,
+			},
+			TriggerOptionSecret: {
+				Name: TriggerOptionSecret,
+				Type: common.AttributeEncrypted,
+			},
+			TriggerOptionMethods: {
+				Name: TriggerOptionMethods,
+				Type: common.AttributeString,
+			},
+			TriggerOptionResponse: {
+				Name: TriggerOptionResponse,
+				Type: common.AttributeBool,
+			},
+			TriggerOptionTimeout: {
+				Name: TriggerOptionTimeout,
+				Type: common.AttributeInt,
+			},
+		},
+	}
+}
+
+// TriggerWebhookMessage ...
+type TriggerWebhookMessage struct {
+	RequestId string              `json:"request_id"`
+	Method    string              `json:"method"`
+	Path      string              `json:"path"`
+	Params    map[string]string   `json:"params"`
+	Query     map[string][]string `json:"query"`
+	Headers   map[string]string   `json:"headers"`
+	Body      interface{}         `json:"body"`
+}
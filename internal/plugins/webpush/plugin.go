@@ -69,8 +69,8 @@ func (p *plugin) Load(ctx context.Context, service plugins.Service) (err error)
 		return
 	}
 
-	p.notify = notify2.NewNotify(service.Adaptors())
-	p.notify.Start()
+	p.notify = notify2.NewNotify(service.Adaptors(), service.EventBus())
+	p.notify.Start(p, Name, nil)
 
 	// load settings
 	var settings m.Attributes
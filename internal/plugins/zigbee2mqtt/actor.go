@@ -20,10 +20,12 @@ package zigbee2mqtt
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
 
 	"github.com/e154/smart-home/internal/system/supervisor"
+	z2m "github.com/e154/smart-home/internal/system/zigbee2mqtt"
 	"github.com/e154/smart-home/pkg/events"
 	m "github.com/e154/smart-home/pkg/models"
 	"github.com/e154/smart-home/pkg/mqtt"
@@ -38,6 +40,11 @@ type Actor struct {
 	actionPool        chan events.EventCallEntityAction
 	newMsgMu          *sync.Mutex
 	stateMu           *sync.Mutex
+	// the entity created by the bridge from the exposes of the device
+	discovered  bool
+	definition  z2m.EventDeviceInfoDef
+	mqttClient  mqtt.MqttCli
+	deviceTopic string
 }
 
 // NewActor ...
@@ -56,6 +63,13 @@ func NewActor(entity *m.Entity,
 		newMsgMu:          &sync.Mutex{},
 		stateMu:           &sync.Mutex{},
 		zigbee2mqttDevice: zigbee2mqttDevice,
+		discovered:        z2m.IsDiscovered(entity),
+	}
+
+	if actor.discovered {
+		info := z2m.DeviceInfo{}
+		_ = json.Unmarshal(zigbee2mqttDevice.Payload, &info)
+		actor.definition = info.Definition
 	}
 
 	// mqtt worker
@@ -103,6 +117,14 @@ func (e *Actor) mqttNewMessage(message *Message) {
 	e.newMsgMu.Lock()
 	defer e.newMsgMu.Unlock()
 
+	if e.discovered && message.Topic == e.deviceTopic {
+		e.updateFromPayload(message)
+	}
+
+	if e.ScriptsEngine == nil {
+		return
+	}
+
 	if _, err := e.ScriptsEngine.AssertFunction(FuncZigbee2mqttEvent, message); err != nil {
 		log.Error(err.Error())
 		return
@@ -115,6 +137,12 @@ func (e *Actor) addAction(event events.EventCallEntityAction) {
 
 func (e *Actor) runAction(msg events.EventCallEntityAction) {
 	if action, ok := e.Actions[msg.ActionName]; ok {
+		if e.discovered && action.ScriptEngine == nil {
+			if payload, ok := e.definition.ActionPayload(msg.ActionName, msg.Args); ok {
+				e.publishSet(payload)
+				return
+			}
+		}
 		if action.ScriptEngine != nil && action.ScriptEngine.Engine() != nil {
 			if _, err := action.ScriptEngine.Engine().AssertFunction(FuncEntityAction, e.Id, action.Name, msg.Args); err != nil {
 				log.Error(fmt.Errorf("entity id: %s: %w", e.Id, err).Error())
@@ -128,3 +156,31 @@ func (e *Actor) runAction(msg events.EventCallEntityAction) {
 		}
 	}
 }
+
+// updateFromPayload updates the attributes and the state of the discovered entity from the state of the device
+func (e *Actor) updateFromPayload(message *Message) {
+
+	values := make(m.AttributeValue)
+	if err := json.Unmarshal([]byte(message.Payload), &values); err != nil {
+		return
+	}
+
+	e.stateMu.Lock()
+	defer e.stateMu.Unlock()
+
+	e.DeserializeAttr(values)
+	if state, ok := values["state"].(string); ok {
+		e.SetActorState(&state)
+	}
+	e.SaveState(false, true)
+}
+
+func (e *Actor) publishSet(payload map[string]interface{}) {
+	if e.mqttClient == nil {
+		return
+	}
+	b, _ := json.Marshal(payload)
+	if err := e.mqttClient.Publish(e.deviceTopic+"/set", b); err != nil {
+		log.Error(fmt.Errorf("entity id: %s: %w", e.Id, err).Error())
+	}
+}
@@ -103,6 +103,9 @@ func (p *plugin) ActorConstructor(entity *m.Entity) (plugins.PluginActor, error)
 		return nil, err
 	}
 
+	actor.mqttClient = p.mqttClient
+	actor.deviceTopic = fmt.Sprintf("%s/%s", br.BaseTopic, actor.zigbee2mqttDevice.Id)
+
 	if _, ok := p.mqttSubs.Load(br.Id); !ok {
 		_ = p.mqttClient.Subscribe(p.topic(br.BaseTopic), p.mqttOnPublish)
 		p.mqttSubs.Store(br.Id, nil)
@@ -19,6 +19,7 @@
 package automation
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -85,19 +86,28 @@ func (a *Action) Remove() {
 	_ = a.eventBus.Unsubscribe(fmt.Sprintf("system/automation/actions/%d", a.model.Id), a.actionHandler)
 }
 
-// Run ...
-func (a *Action) Run(entityId *common.EntityId) (result string, err error) {
+// Run the script is interrupted when the run context is cancelled
+func (a *Action) Run(ctx context.Context, entityId *common.EntityId) (result string, err error) {
 	a.Lock()
 	defer a.Unlock()
 
 	//log.Infof("run action")
 
 	if a.scriptEngine != nil {
-		if result, err = a.scriptEngine.Engine().AssertFunction(ActionFunc, entityId); err != nil {
+		engine := a.scriptEngine.Engine()
+		stop := interruptOnCancel(ctx, engine)
+		result, err = engine.AssertFunction(ActionFunc, entityId)
+		stop()
+		if err != nil {
 			log.Error(err.Error())
 		}
 	}
 
+	if ctx.Err() != nil {
+		err = ctx.Err()
+		return
+	}
+
 	if a.model.EntityId != nil && a.model.EntityActionName != nil {
 		id := *a.model.EntityId
 		action := *a.model.EntityActionName
@@ -118,6 +128,30 @@ func (a *Action) Run(entityId *common.EntityId) (result string, err error) {
 func (a *Action) actionHandler(_ string, msg interface{}) {
 	switch msg.(type) {
 	case events.EventCallAction:
-		a.Run(nil)
+		a.Run(context.Background(), nil)
+	}
+}
+
+// interruptOnCancel interrupts the engine when the context is cancelled,
+// the returned func waits for the watcher, so no interrupt is sent after it
+func interruptOnCancel(ctx context.Context, engine scripts.Engine) (stop func()) {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-ctx.Done():
+			engine.Interrupt(ctx.Err())
+		case <-done:
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
 	}
 }
@@ -20,17 +20,37 @@ package automation
 
 import (
 	"context"
+	"strconv"
 
+	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/plugins"
 	"github.com/e154/smart-home/pkg/scripts"
 
+	"github.com/e154/bus"
 	"go.uber.org/atomic"
 )
 
 // NewCondition ...
 func NewCondition(scriptService scripts.ScriptService,
+	eventBus bus.Bus,
+	supervisor plugins.Supervisor,
 	model *m.Condition) (condition *Condition, err error) {
 
+	// native condition
+	if model.Rule != nil {
+		var rule conditionRule
+		if rule, err = newConditionRule(eventBus, supervisor, model, model.Rule); err != nil {
+			return
+		}
+		condition = &Condition{
+			model:      model,
+			lastStatus: atomic.NewBool(false),
+			rule:       rule,
+		}
+		return
+	}
+
 	var scriptEngine scripts.EngineWatcher
 
 	if model.Script != nil {
@@ -60,17 +80,32 @@ type Condition struct {
 	model        *m.Condition
 	lastStatus   *atomic.Bool
 	scriptEngine scripts.EngineWatcher
+	rule         conditionRule
 }
 
 func (r *Condition) Stop() {
 	if r.scriptEngine != nil {
 		r.scriptEngine.Stop()
 	}
+	if r.rule != nil {
+		r.rule.Stop()
+	}
 }
 
 // Check ...
 func (r *Condition) Check(ctx context.Context) (result string, err error) {
 
+	if r.rule != nil {
+		entityId, _ := ctx.Value("entityId").(*common.EntityId)
+		var state bool
+		if state, err = r.rule.Check(entityId); err != nil {
+			log.Error(err.Error())
+		}
+		r.lastStatus.Store(state)
+		result = strconv.FormatBool(state)
+		return
+	}
+
 	if r.scriptEngine != nil && r.scriptEngine.Engine() != nil {
 		if result, err = r.scriptEngine.Engine().AssertFunction(ConditionFunc, ctx.Value("entityId")); err != nil {
 			log.Error(err.Error())
@@ -40,6 +40,7 @@ func NewConditionGroup(t common.ConditionType) *ConditionGroup {
 // ConditionGroup ...
 type ConditionGroup struct {
 	rules      []*Condition
+	groups     []*ConditionGroup
 	t          common.ConditionType
 	lastStatus *atomic.Bool
 	sync.Mutex
@@ -50,62 +51,123 @@ func (c *ConditionGroup) AddCondition(condition *Condition) {
 	c.rules = append(c.rules, condition)
 }
 
+// AddGroup adds the nested group, it is checked together with the conditions
+func (c *ConditionGroup) AddGroup(group *ConditionGroup) {
+	c.groups = append(c.groups, group)
+}
+
 func (c *ConditionGroup) Stop() {
 	for _, condition := range c.rules {
 		condition.Stop()
 	}
+	for _, group := range c.groups {
+		group.Stop()
+	}
+}
+
+// ConditionResult ...
+type ConditionResult struct {
+	Id      int64
+	Status  bool
+	Error   error
+	Started time.Time
+	Elapsed time.Duration
 }
 
 // Check ...
-func (c *ConditionGroup) Check(entityId *common.EntityId) (state bool, err error) {
+func (c *ConditionGroup) Check(entityId *common.EntityId) (state bool, results []ConditionResult, err error) {
 	c.Lock()
 	defer func() {
 		c.lastStatus.Store(state)
 		c.Unlock()
 	}()
 
-	var total = len(c.rules)
+	var total = len(c.rules) + len(c.groups)
 	if total == 0 {
 		state = true
 		return
 	}
 
+	results = make([]ConditionResult, len(c.rules))
+	statuses := make([]bool, total)
+	errs := make([]error, len(c.groups))
+	nested := make([][]ConditionResult, len(c.groups))
+
 	wg := &sync.WaitGroup{}
 	wg.Add(total)
 
-	for _, r := range c.rules {
-		go func(condition *Condition) {
+	for i, r := range c.rules {
+		go func(i int, condition *Condition) {
 			defer wg.Done()
+			started := time.Now()
 			bg, _ := context.WithTimeout(context.Background(), time.Second)
 			ctx := context.WithValue(bg, "entityId", entityId)
-			if _, err = condition.Check(ctx); err != nil {
-				log.Error(err.Error())
+			var checkErr error
+			if _, checkErr = condition.Check(ctx); checkErr != nil {
+				log.Error(checkErr.Error())
+			}
+			results[i] = ConditionResult{
+				Id:      condition.model.Id,
+				Status:  condition.Status(),
+				Error:   checkErr,
+				Started: started,
+				Elapsed: time.Since(started),
 			}
-		}(r)
+			statuses[i] = results[i].Status
+		}(i, r)
+	}
+
+	for i, g := range c.groups {
+		go func(i int, group *ConditionGroup) {
+			defer wg.Done()
+			statuses[len(c.rules)+i], nested[i], errs[i] = group.Check(entityId)
+		}(i, g)
 	}
 
 	wg.Wait()
 
-	switch c.t {
+	for i := range c.groups {
+		results = append(results, nested[i]...)
+		if errs[i] != nil {
+			err = errs[i]
+		}
+	}
+
+	for _, r := range results[:len(c.rules)] {
+		if r.Error != nil {
+			err = r.Error
+		}
+	}
+
+	state = combineStatuses(c.t, statuses)
+
+	return
+}
+
+// combineStatuses ...
+func combineStatuses(t common.ConditionType, statuses []bool) bool {
+	switch t {
 	case common.ConditionOr:
-		for _, r := range c.rules {
-			if r.Status() {
-				state = true
-				return
+		for _, status := range statuses {
+			if status {
+				return true
 			}
 		}
-
+		return false
 	case common.ConditionAnd:
-		state = true
-		for _, r := range c.rules {
-			if !r.Status() {
-				state = false
+		for _, status := range statuses {
+			if !status {
+				return false
 			}
-			if !state {
-				return
+		}
+		return true
+	case common.ConditionNot:
+		for _, status := range statuses {
+			if status {
+				return false
 			}
 		}
+		return true
 	}
-
-	return
+	return false
 }
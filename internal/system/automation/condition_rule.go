@@ -0,0 +1,418 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package automation
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/plugins"
+
+	"github.com/e154/bus"
+	"go.uber.org/atomic"
+)
+
+var (
+	// ErrConditionRule ...
+	ErrConditionRule = errors.New("bad condition rule")
+	// ErrNotNumeric ...
+	ErrNotNumeric = errors.New("value is not numeric")
+)
+
+// conditionRule is the native condition evaluated without the script
+type conditionRule interface {
+	Check(entityId *common.EntityId) (bool, error)
+	Stop()
+}
+
+// ValidateConditionRule checks the combinations of the rule fields the tags can't express
+func ValidateConditionRule(rule *m.ConditionRule) error {
+	if rule == nil {
+		return nil
+	}
+	switch rule.Type {
+	case common.ConditionRuleNumeric:
+		// without the entity the hold time could be measured only between the checks
+		if rule.For > 0 && rule.EntityId == nil {
+			return fmt.Errorf("numeric rule with \"for\" without entity id: %w", ErrConditionRule)
+		}
+	case common.ConditionRuleSun:
+		if rule.EntityId == nil {
+			return fmt.Errorf("sun rule without entity id: %w", ErrConditionRule)
+		}
+	}
+	for _, nested := range rule.Rules {
+		if err := ValidateConditionRule(nested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newConditionRule(eventBus bus.Bus, supervisor plugins.Supervisor, model *m.Condition, rule *m.ConditionRule) (conditionRule, error) {
+	switch rule.Type {
+	case common.ConditionRuleState:
+		return &stateRule{rule: rule, supervisor: supervisor}, nil
+	case common.ConditionRuleNumeric:
+		if err := ValidateConditionRule(rule); err != nil {
+			return nil, err
+		}
+		return newNumericRule(eventBus, supervisor, rule, rule.Attribute)
+	case common.ConditionRuleSun:
+		if err := ValidateConditionRule(rule); err != nil {
+			return nil, err
+		}
+		return newNumericRule(eventBus, supervisor, rule, common.SunAttrElevation)
+	case common.ConditionRuleTime:
+		return newTimeRule(rule)
+	case common.ConditionRuleTemplate:
+		return newTemplateRule(supervisor, rule)
+	case common.ConditionRuleAnd, common.ConditionRuleOr, common.ConditionRuleNot:
+		return newGroupRule(eventBus, supervisor, model, rule)
+	}
+	return nil, fmt.Errorf("unknown rule type \"%s\": %w", rule.Type, ErrConditionRule)
+}
+
+// ruleEntityId returns the entity of the rule, the rule without the entity uses the entity of the trigger
+func ruleEntityId(rule *m.ConditionRule, entityId *common.EntityId) (*common.EntityId, error) {
+	if rule.EntityId != nil {
+		return rule.EntityId, nil
+	}
+	if entityId != nil {
+		return entityId, nil
+	}
+	return nil, fmt.Errorf("%s rule without entity id: %w", rule.Type, ErrConditionRule)
+}
+
+func currentState(supervisor plugins.Supervisor, entityId common.EntityId) (*events.EventEntityState, error) {
+	if supervisor == nil {
+		return nil, fmt.Errorf("entity %s: supervisor not available: %w", entityId, ErrConditionRule)
+	}
+	actor, err := supervisor.GetActorById(entityId)
+	if err != nil {
+		return nil, err
+	}
+	return actor.GetCurrentState(), nil
+}
+
+// stateRule compares the entity state, the state must be held for the given time
+type stateRule struct {
+	rule       *m.ConditionRule
+	supervisor plugins.Supervisor
+}
+
+// Check ...
+func (r *stateRule) Check(entityId *common.EntityId) (bool, error) {
+	id, err := ruleEntityId(r.rule, entityId)
+	if err != nil {
+		return false, err
+	}
+	state, err := currentState(r.supervisor, *id)
+	if err != nil {
+		return false, err
+	}
+
+	ok := state.StateName() == r.rule.State
+	if r.rule.NotEqual {
+		ok = !ok
+	}
+	if !ok || r.rule.For == 0 {
+		return ok, nil
+	}
+
+	changed := state.LastChanged
+	if changed == nil {
+		changed = state.LastUpdated
+	}
+	if changed == nil {
+		return false, nil
+	}
+	return time.Since(*changed) >= time.Duration(r.rule.For)*time.Millisecond, nil
+}
+
+// Stop ...
+func (r *stateRule) Stop() {}
+
+// numericRule compares the entity value or attribute with the thresholds,
+// the value must be held in the range for the given time
+type numericRule struct {
+	rule       *m.ConditionRule
+	attribute  string
+	eventBus   bus.Bus
+	supervisor plugins.Supervisor
+	sync.Mutex
+	since time.Time
+}
+
+func newNumericRule(eventBus bus.Bus, supervisor plugins.Supervisor, rule *m.ConditionRule, attribute string) (*numericRule, error) {
+	if rule.Above == nil && rule.Below == nil {
+		return nil, fmt.Errorf("%s rule without thresholds: %w", rule.Type, ErrConditionRule)
+	}
+	r := &numericRule{
+		rule:       rule,
+		attribute:  attribute,
+		eventBus:   eventBus,
+		supervisor: supervisor,
+	}
+	// the changes are tracked only for the known entity
+	if r.rule.For > 0 && r.rule.EntityId != nil && r.eventBus != nil {
+		_ = r.eventBus.Subscribe("system/entities/"+r.rule.EntityId.String(), r.eventHandler, false)
+	}
+	return r, nil
+}
+
+// Check ...
+func (r *numericRule) Check(entityId *common.EntityId) (bool, error) {
+	id, err := ruleEntityId(r.rule, entityId)
+	if err != nil {
+		return false, err
+	}
+	state, err := currentState(r.supervisor, *id)
+	if err != nil {
+		return false, err
+	}
+	value, err := stateValue(state, r.attribute)
+	if err != nil {
+		return false, fmt.Errorf("entity %s: %w", id, err)
+	}
+	return r.held(common.InRange(value, r.rule.Above, r.rule.Below)), nil
+}
+
+// held saves the time when the value entered the range
+func (r *numericRule) held(ok bool) bool {
+	if r.rule.For == 0 {
+		return ok
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if !ok {
+		r.since = time.Time{}
+		return false
+	}
+	if r.since.IsZero() {
+		r.since = time.Now()
+	}
+	return time.Since(r.since) >= time.Duration(r.rule.For)*time.Millisecond
+}
+
+func (r *numericRule) eventHandler(_ string, msg interface{}) {
+	v, ok := msg.(events.EventStateChanged)
+	if !ok || r.rule.EntityId == nil || v.EntityId != *r.rule.EntityId {
+		return
+	}
+	value, err := stateValue(&v.NewState, r.attribute)
+	if err != nil {
+		return
+	}
+	r.held(common.InRange(value, r.rule.Above, r.rule.Below))
+}
+
+// Stop ...
+func (r *numericRule) Stop() {
+	if r.rule.For > 0 && r.rule.EntityId != nil && r.eventBus != nil {
+		_ = r.eventBus.Unsubscribe("system/entities/"+r.rule.EntityId.String(), r.eventHandler)
+	}
+}
+
+// stateValue returns the attribute of the state, the value of the state without the attribute
+func stateValue(state *events.EventEntityState, attribute string) (float64, error) {
+	value, ok := state.Number(attribute)
+	if !ok {
+		return 0, fmt.Errorf("attribute \"%s\" value %v: %w", attribute, state.Value, ErrNotNumeric)
+	}
+	return value, nil
+}
+
+// timeRule checks the time of day and the weekday
+type timeRule struct {
+	after, before *int
+	weekdays      []int
+	now           func() time.Time
+}
+
+func newTimeRule(rule *m.ConditionRule) (*timeRule, error) {
+	r := &timeRule{
+		weekdays: rule.Weekdays,
+		now:      time.Now,
+	}
+	var err error
+	if r.after, err = parseDayTime(rule.After); err != nil {
+		return nil, err
+	}
+	if r.before, err = parseDayTime(rule.Before); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// parseDayTime returns the minutes since midnight
+func parseDayTime(s string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return nil, fmt.Errorf("time \"%s\": %w", s, ErrConditionRule)
+	}
+	minutes := t.Hour()*60 + t.Minute()
+	return &minutes, nil
+}
+
+// Check ...
+func (r *timeRule) Check(_ *common.EntityId) (bool, error) {
+	now := r.now()
+	if len(r.weekdays) > 0 && !slices.Contains(r.weekdays, int(now.Weekday())) {
+		return false, nil
+	}
+
+	current := now.Hour()*60 + now.Minute()
+	switch {
+	case r.after != nil && r.before != nil:
+		if *r.after <= *r.before {
+			return current >= *r.after && current < *r.before, nil
+		}
+		// the window crosses midnight
+		return current >= *r.after || current < *r.before, nil
+	case r.after != nil:
+		return current >= *r.after, nil
+	case r.before != nil:
+		return current < *r.before, nil
+	}
+	return true, nil
+}
+
+// Stop ...
+func (r *timeRule) Stop() {}
+
+// templateRule renders the template, the rule passes if the result is "true"
+type templateRule struct {
+	tpl        *template.Template
+	supervisor plugins.Supervisor
+}
+
+func newTemplateRule(supervisor plugins.Supervisor, rule *m.ConditionRule) (*templateRule, error) {
+	r := &templateRule{
+		supervisor: supervisor,
+	}
+	tpl, err := template.New("condition").Funcs(template.FuncMap{
+		"state": r.state,
+		"value": r.value,
+		"attr":  r.attr,
+	}).Parse(rule.Template)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err.Error(), ErrConditionRule)
+	}
+	r.tpl = tpl
+	return r, nil
+}
+
+// Check ...
+func (r *templateRule) Check(entityId *common.EntityId) (bool, error) {
+	var data = map[string]interface{}{
+		"EntityId": "",
+	}
+	if entityId != nil {
+		data["EntityId"] = entityId.String()
+	}
+	var buf bytes.Buffer
+	if err := r.tpl.Execute(&buf, data); err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(buf.String()) == "true", nil
+}
+
+func (r *templateRule) state(id string) (string, error) {
+	state, err := currentState(r.supervisor, common.EntityId(id))
+	if err != nil || state.State == nil {
+		return "", err
+	}
+	return state.State.Name, nil
+}
+
+func (r *templateRule) value(id string) (interface{}, error) {
+	state, err := currentState(r.supervisor, common.EntityId(id))
+	if err != nil {
+		return nil, err
+	}
+	return state.Value, nil
+}
+
+func (r *templateRule) attr(id, name string) (interface{}, error) {
+	state, err := currentState(r.supervisor, common.EntityId(id))
+	if err != nil {
+		return nil, err
+	}
+	if attr, ok := state.Attributes[name]; ok && attr != nil {
+		return attr.Value, nil
+	}
+	return nil, nil
+}
+
+// Stop ...
+func (r *templateRule) Stop() {}
+
+// groupRule combines the nested rules with and, or, not
+type groupRule struct {
+	group *ConditionGroup
+}
+
+func newGroupRule(eventBus bus.Bus, supervisor plugins.Supervisor, model *m.Condition, rule *m.ConditionRule) (*groupRule, error) {
+	r := &groupRule{
+		group: NewConditionGroup(common.ConditionType(rule.Type)),
+	}
+	for _, nested := range rule.Rules {
+		child, err := newConditionRule(eventBus, supervisor, model, nested)
+		if err != nil {
+			r.Stop()
+			return nil, err
+		}
+		if g, ok := child.(*groupRule); ok {
+			r.group.AddGroup(g.group)
+			continue
+		}
+		r.group.AddCondition(&Condition{
+			model:      model,
+			lastStatus: atomic.NewBool(false),
+			rule:       child,
+		})
+	}
+	return r, nil
+}
+
+// Check ...
+func (r *groupRule) Check(entityId *common.EntityId) (bool, error) {
+	state, _, err := r.group.Check(entityId)
+	return state, err
+}
+
+// Stop ...
+func (r *groupRule) Stop() {
+	r.group.Stop()
+}
@@ -0,0 +1,187 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package automation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeRule(t *testing.T) {
+
+	// 2026-10-17 is saturday
+	at := func(hour, minute int) func() time.Time {
+		return func() time.Time {
+			return time.Date(2026, 10, 17, hour, minute, 0, 0, time.Local)
+		}
+	}
+
+	check := func(rule *m.ConditionRule, now func() time.Time) bool {
+		r, err := newTimeRule(rule)
+		require.NoError(t, err)
+		r.now = now
+		ok, err := r.Check(nil)
+		require.NoError(t, err)
+		return ok
+	}
+
+	rule := &m.ConditionRule{Type: common.ConditionRuleTime, After: "08:00", Before: "18:30"}
+	require.True(t, check(rule, at(8, 0)))
+	require.True(t, check(rule, at(18, 29)))
+	require.False(t, check(rule, at(18, 30)))
+	require.False(t, check(rule, at(7, 59)))
+
+	// the window crosses midnight
+	rule = &m.ConditionRule{Type: common.ConditionRuleTime, After: "22:00", Before: "06:00"}
+	require.True(t, check(rule, at(23, 0)))
+	require.True(t, check(rule, at(5, 59)))
+	require.False(t, check(rule, at(12, 0)))
+
+	rule = &m.ConditionRule{Type: common.ConditionRuleTime, Weekdays: []int{0, 6}}
+	require.True(t, check(rule, at(12, 0)))
+	rule.Weekdays = []int{1, 2, 3, 4, 5}
+	require.False(t, check(rule, at(12, 0)))
+
+	_, err := newTimeRule(&m.ConditionRule{Type: common.ConditionRuleTime, After: "25:00"})
+	require.ErrorIs(t, err, ErrConditionRule)
+}
+
+func TestNumericRule(t *testing.T) {
+
+	require.True(t, common.InRange(26, common.Float64(25), nil))
+	require.False(t, common.InRange(25, common.Float64(25), nil))
+	require.True(t, common.InRange(10, common.Float64(5), common.Float64(20)))
+	require.False(t, common.InRange(20, nil, common.Float64(20)))
+
+	for _, v := range []interface{}{int(3), int64(3), float32(3), "3", " 3 "} {
+		value, err := stateValue(&events.EventEntityState{Value: v}, "")
+		require.NoError(t, err)
+		require.Equal(t, float64(3), value)
+	}
+	_, err := stateValue(&events.EventEntityState{Value: "on"}, "")
+	require.ErrorIs(t, err, ErrNotNumeric)
+
+	_, err = newNumericRule(nil, nil, &m.ConditionRule{Type: common.ConditionRuleNumeric}, "")
+	require.ErrorIs(t, err, ErrConditionRule)
+
+	var entityId = common.EntityId("sensor.temperature")
+	r, err := newNumericRule(nil, nil, &m.ConditionRule{
+		Type:     common.ConditionRuleNumeric,
+		EntityId: &entityId,
+		Above:    common.Float64(25),
+		For:      50,
+	}, "")
+	require.NoError(t, err)
+
+	changed := func(value interface{}) {
+		r.eventHandler("", events.EventStateChanged{
+			EntityId: entityId,
+			NewState: events.EventEntityState{Value: value},
+		})
+	}
+
+	changed(26)
+	require.False(t, r.held(true))
+	time.Sleep(time.Millisecond * 60)
+	require.True(t, r.held(true))
+
+	// the value left the range
+	changed(20)
+	changed(27)
+	require.False(t, r.held(true))
+}
+
+func TestGroupRule(t *testing.T) {
+
+	model := &m.Condition{Id: 1}
+	always := &m.ConditionRule{Type: common.ConditionRuleTime}
+	template := func(s string) *m.ConditionRule {
+		return &m.ConditionRule{Type: common.ConditionRuleTemplate, Template: s}
+	}
+
+	check := func(rule *m.ConditionRule) bool {
+		condition, err := NewCondition(nil, nil, nil, &m.Condition{Id: model.Id, Rule: rule})
+		require.NoError(t, err)
+		defer condition.Stop()
+		var entityId = common.EntityId("binary_sensor.door")
+		result, err := condition.Check(context.WithValue(context.Background(), "entityId", &entityId))
+		require.NoError(t, err)
+		require.Equal(t, result == "true", condition.Status())
+		return condition.Status()
+	}
+
+	require.True(t, check(template(`{{ eq .EntityId "binary_sensor.door" }}`)))
+	require.False(t, check(template(`{{ eq .EntityId "binary_sensor.window" }}`)))
+
+	require.True(t, check(&m.ConditionRule{Type: common.ConditionRuleAnd, Rules: []*m.ConditionRule{
+		always, template("true"),
+	}}))
+	require.False(t, check(&m.ConditionRule{Type: common.ConditionRuleAnd, Rules: []*m.ConditionRule{
+		always, template("false"),
+	}}))
+	require.True(t, check(&m.ConditionRule{Type: common.ConditionRuleOr, Rules: []*m.ConditionRule{
+		template("false"), always,
+	}}))
+	require.True(t, check(&m.ConditionRule{Type: common.ConditionRuleNot, Rules: []*m.ConditionRule{
+		template("false"),
+	}}))
+
+	// nested groups
+	require.True(t, check(&m.ConditionRule{Type: common.ConditionRuleAnd, Rules: []*m.ConditionRule{
+		always,
+		{Type: common.ConditionRuleNot, Rules: []*m.ConditionRule{
+			template("false"),
+			{Type: common.ConditionRuleAnd, Rules: []*m.ConditionRule{always, template("false")}},
+		}},
+	}}))
+
+	_, err := NewCondition(nil, nil, nil, &m.Condition{Rule: &m.ConditionRule{Type: "unknown"}})
+	require.ErrorIs(t, err, ErrConditionRule)
+}
+
+func TestValidateConditionRule(t *testing.T) {
+
+	entityId := common.EntityId("sensor.temp")
+
+	rule := &m.ConditionRule{Type: common.ConditionRuleNumeric, Above: common.Float64(25), For: 1000}
+	require.ErrorIs(t, ValidateConditionRule(rule), ErrConditionRule)
+	rule.EntityId = &entityId
+	require.NoError(t, ValidateConditionRule(rule))
+
+	// the rule without the hold time uses the entity of the trigger
+	rule = &m.ConditionRule{Type: common.ConditionRuleNumeric, Above: common.Float64(25)}
+	require.NoError(t, ValidateConditionRule(rule))
+
+	rule = &m.ConditionRule{Type: common.ConditionRuleSun, Below: common.Float64(-6)}
+	require.ErrorIs(t, ValidateConditionRule(rule), ErrConditionRule)
+
+	// nested rules are checked
+	rule = &m.ConditionRule{Type: common.ConditionRuleAnd, Rules: []*m.ConditionRule{
+		{Type: common.ConditionRuleState, State: "on", For: 1000},
+		{Type: common.ConditionRuleNumeric, Below: common.Float64(5), For: 1000},
+	}}
+	require.ErrorIs(t, ValidateConditionRule(rule), ErrConditionRule)
+}
@@ -21,11 +21,14 @@ package automation
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
 
+	"github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/common/telemetry"
 	"github.com/e154/smart-home/pkg/events"
 	"github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/plugins"
 	"github.com/e154/smart-home/pkg/scripts"
 
 	"go.uber.org/atomic"
@@ -43,6 +46,12 @@ type Task struct {
 	scriptService  scripts.ScriptService
 	actionsMx      sync.Mutex
 	actions        map[int64]*Action
+	runner         *TaskRunner
+	supervisor     plugins.Supervisor
+	stateWaiter    *stateWaiter
+	// conditions of the if and repeat steps
+	stepConditionsMx sync.Mutex
+	stepConditions   map[int64]*Condition
 	sync.Mutex
 	telemetry telemetry.Telemetry
 }
@@ -50,13 +59,17 @@ type Task struct {
 // NewTask ...
 func NewTask(eventBus bus.Bus,
 	scriptService scripts.ScriptService,
+	supervisor plugins.Supervisor,
 	model *models.Task) *Task {
 	return &Task{
-		model:         model,
-		eventBus:      eventBus,
-		enabled:       atomic.NewBool(false),
-		scriptService: scriptService,
-		actions:       make(map[int64]*Action),
+		model:          model,
+		eventBus:       eventBus,
+		enabled:        atomic.NewBool(false),
+		scriptService:  scriptService,
+		supervisor:     supervisor,
+		actions:        make(map[int64]*Action),
+		stateWaiter:    newStateWaiter(),
+		stepConditions: make(map[int64]*Condition),
 	}
 }
 
@@ -83,10 +96,36 @@ func (t *Task) eventHandler(_ string, msg interface{}) {
 	case events.EventRemovedTaskModel:
 		t.removeAction(v.Id)
 	case events.EventTriggerCompleted:
-		t.prepareEventFromTrigger(v)
+		t.run(v)
 	}
 }
 
+// Replay runs the task with the trigger payload of the recorded run
+func (t *Task) Replay(run *models.TaskRun) {
+	if !t.enabled.Load() {
+		return
+	}
+	t.run(triggerEventFromRun(run))
+}
+
+func (t *Task) run(v events.EventTriggerCompleted) {
+	ok := t.runner.Run(func(ctx context.Context) {
+		t.prepareEventFromTrigger(ctx, v)
+	})
+	if ok {
+		return
+	}
+	log.Warnf("task %d: run skipped, mode(%s) max runs(%d)", t.model.Id, t.model.Mode, t.model.MaxRuns)
+	t.publishRun(newRunTrace(t.model.Id, v).finish(common.TaskRunSkipped, nil))
+}
+
+func (t *Task) publishRun(run *models.TaskRun) {
+	t.eventBus.Publish(fmt.Sprintf("system/automation/tasks/%d", t.model.Id), events.EventTaskRunFinished{
+		Id:  t.model.Id,
+		Run: run,
+	})
+}
+
 // Start ...
 func (t *Task) Start() {
 	if t.enabled.Load() {
@@ -96,6 +135,8 @@ func (t *Task) Start() {
 
 	//log.Infof("task %d start", t.Id())
 
+	t.runner = NewTaskRunner(t.model.Mode, t.model.MaxRuns)
+
 	// add actions
 	for _, model := range t.model.Actions {
 		t.addAction(model)
@@ -106,7 +147,7 @@ func (t *Task) Start() {
 
 	// add conditions
 	for _, model := range t.model.Conditions {
-		condition, err := NewCondition(t.scriptService, model)
+		condition, err := NewCondition(t.scriptService, t.eventBus, t.supervisor, model)
 		if err != nil {
 			log.Error(err.Error())
 			continue
@@ -114,6 +155,24 @@ func (t *Task) Start() {
 		t.conditionGroup.AddCondition(condition)
 	}
 
+	// add steps
+	models.WalkTaskSteps(t.model.Steps, func(step *models.TaskStep) {
+		if step.Action != nil {
+			t.actionsMx.Lock()
+			_, ok := t.actions[step.Action.Id]
+			t.actionsMx.Unlock()
+			if !ok {
+				t.addAction(step.Action)
+			}
+		}
+		if step.Condition != nil {
+			t.addStepCondition(step.Condition)
+		}
+		if step.Type == common.StepTypeWait && step.EntityId != nil {
+			_ = t.eventBus.Subscribe("system/entities/"+step.EntityId.String(), t.stateWaiter.eventHandler, false)
+		}
+	})
+
 	// add triggers
 	for _, model := range t.model.Triggers {
 		t.eventBus.Subscribe(fmt.Sprintf("system/automation/triggers/%d", model.Id), t.eventHandler, false)
@@ -131,18 +190,39 @@ func (t *Task) Stop() {
 	}
 	t.enabled.Store(false)
 	//log.Infof("task %d stopped", t.Id())
+
+	// cancel active runs
+	t.runner.Stop()
+
 	for _, model := range t.model.Triggers {
 		t.eventBus.Unsubscribe(fmt.Sprintf("system/automation/triggers/%d", model.Id), t.eventHandler)
 	}
-	for _, action := range t.actions {
-		action.Remove()
-		t.eventBus.Unsubscribe(fmt.Sprintf("system/models/actions/%d", action.model.Id), t.eventHandler)
+	t.actionsMx.Lock()
+	for id, action := range t.actions {
+		if action != nil {
+			action.Remove()
+		}
+		t.eventBus.Unsubscribe(fmt.Sprintf("system/models/actions/%d", id), t.eventHandler)
 	}
 	t.actions = make(map[int64]*Action)
+	t.actionsMx.Unlock()
 
 	t.conditionGroup.Stop()
 	t.conditionGroup = nil
 
+	t.stepConditionsMx.Lock()
+	for _, condition := range t.stepConditions {
+		condition.Stop()
+	}
+	t.stepConditions = make(map[int64]*Condition)
+	t.stepConditionsMx.Unlock()
+
+	models.WalkTaskSteps(t.model.Steps, func(step *models.TaskStep) {
+		if step.Type == common.StepTypeWait && step.EntityId != nil {
+			_ = t.eventBus.Unsubscribe("system/entities/"+step.EntityId.String(), t.stateWaiter.eventHandler)
+		}
+	})
+
 	t.eventBus.Publish(fmt.Sprintf("system/automation/tasks/%d", t.model.Id), events.EventTaskUnloaded{
 		Id: t.model.Id,
 	})
@@ -167,11 +247,26 @@ func (t *Task) addAction(model *models.Action) {
 	t.eventBus.Subscribe(fmt.Sprintf("system/models/actions/%d", model.Id), t.eventHandler, false)
 }
 
+func (t *Task) addStepCondition(model *models.Condition) {
+	t.stepConditionsMx.Lock()
+	defer t.stepConditionsMx.Unlock()
+
+	if _, ok := t.stepConditions[model.Id]; ok {
+		return
+	}
+	condition, err := NewCondition(t.scriptService, t.eventBus, t.supervisor, model)
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+	t.stepConditions[model.Id] = condition
+}
+
 func (t *Task) removeAction(id int64) {
 	t.actionsMx.Lock()
 	defer t.actionsMx.Unlock()
 
-	if a, ok := t.actions[id]; ok {
+	if a, ok := t.actions[id]; ok && a != nil {
 		a.Remove()
 	}
 	delete(t.actions, id)
@@ -179,44 +274,71 @@ func (t *Task) removeAction(id int64) {
 	_ = t.eventBus.Unsubscribe(fmt.Sprintf("system/models/actions/%d", id), t.eventHandler)
 }
 
-func (t *Task) prepareEventFromTrigger(v events.EventTriggerCompleted) {
+// sequence returns the steps of the task, the task without steps runs its actions one by one
+func (t *Task) sequence() []*models.TaskStep {
+	if len(t.model.Steps) > 0 {
+		return t.model.Steps
+	}
+	steps := make([]*models.TaskStep, 0, len(t.model.Actions))
+	for _, action := range t.model.Actions {
+		steps = append(steps, &models.TaskStep{
+			Type:     common.StepTypeAction,
+			ActionId: common.Int64(action.Id),
+		})
+	}
+	return steps
+}
+
+func (t *Task) prepareEventFromTrigger(runCtx context.Context, v events.EventTriggerCompleted) {
 	taskCtx, taskSpan := telemetry.Start(v.Ctx, "task")
 	taskSpan.SetAttributes("id", t.model.Id)
 
+	trace := newRunTrace(t.model.Id, v)
+	runCtx = withRunTrace(runCtx, trace)
+
+	var status = common.TaskRunCompleted
 	var actionCtx context.Context
+	var err error
 	defer func() {
 		taskSpan.End()
 
 		t.Lock()
 		t.telemetry = telemetry.Unpack(actionCtx)
 		t.Unlock()
+
+		t.publishRun(trace.finish(status, err))
 	}()
 
 	conditionsCtx, span := telemetry.Start(taskCtx, "conditions")
-	result, err := t.conditionGroup.Check(v.EntityId)
+	result, conditions, err := t.conditionGroup.Check(v.EntityId)
+	for _, condition := range conditions {
+		trace.add(&models.TaskRunStep{
+			Type:     "condition",
+			Id:       common.Int64(condition.Id),
+			Level:    span.Level + 1,
+			Result:   strconv.FormatBool(condition.Status),
+			Start:    condition.Started,
+			Duration: condition.Elapsed,
+		}, condition.Error)
+	}
 	if err != nil || !result {
 		if err != nil {
 			span.SetStatus(telemetry.Error, err.Error())
 		}
 		span.End()
+		status = common.TaskRunNotPassed
 		return
 	}
 	span.End()
 
-	t.actionsMx.Lock()
-	for _, action := range t.actions {
-		if actionCtx != nil {
-			conditionsCtx = actionCtx
+	if actionCtx, err = t.runSteps(runCtx, conditionsCtx, t.sequence(), v.EntityId); err != nil {
+		if runCtx.Err() != nil {
+			log.Infof("task %d: run was cancelled", t.model.Id)
+			status = common.TaskRunCancelled
+			return
 		}
-		actionCtx, span = telemetry.Start(conditionsCtx, "actions")
-		span.SetAttributes("id", action.model.Id)
-		if _, err = action.Run(v.EntityId); err != nil {
-			log.Error(err.Error())
-			span.SetStatus(telemetry.Error, err.Error())
-		}
-		span.End()
+		log.Error(err.Error())
 	}
-	t.actionsMx.Unlock()
 
 	//fmt.Println("time spent", timeSpent.Microseconds())
 	t.eventBus.Publish(fmt.Sprintf("system/automation/tasks/%d", t.model.Id), events.EventTaskCompleted{
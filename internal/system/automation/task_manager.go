@@ -155,12 +155,39 @@ func (a *taskManager) eventHandler(_ string, msg interface{}) {
 		go a.updateTask(v.Id)
 	case events.EventRemovedTaskModel:
 		go a.unloadTask(v.Id)
+
+	case events.EventTaskRunFinished:
+		go a.saveTaskRun(v.Run)
+	case events.CommandReplayTaskRun:
+		go a.replayTaskRun(v.Id, v.Run)
+	}
+}
+
+func (a *taskManager) saveTaskRun(run *m.TaskRun) {
+	if run == nil {
+		return
+	}
+	if _, err := a.adaptors.TaskRun.Add(context.Background(), run); err != nil {
+		log.Error(err.Error())
+	}
+}
+
+func (a *taskManager) replayTaskRun(id int64, run *m.TaskRun) {
+	a.Lock()
+	task, ok := a.tasks[id]
+	a.Unlock()
+	if !ok || run == nil {
+		log.Warnf("task %d is not loaded, replay skipped", id)
+		return
 	}
+	log.Infof("replay task %d run %d", id, run.Id)
+	task.Replay(run)
 }
 
 // addTask ...
 func (a *taskManager) addTask(model *m.Task) {
-	task := NewTask(a.eventBus, a.scriptService, model)
+	a.loadSteps(model)
+	task := NewTask(a.eventBus, a.scriptService, a.supervisor, model)
 	a.taskCount.Inc()
 	log.Infof("add task name(%s) id(%d)", task.Name(), task.Id())
 	a.Lock()
@@ -169,6 +196,39 @@ func (a *taskManager) addTask(model *m.Task) {
 	task.Start()
 }
 
+// loadSteps loads the actions and conditions used by the steps of the task
+func (a *taskManager) loadSteps(model *m.Task) {
+	if len(model.Steps) == 0 {
+		return
+	}
+
+	var actions = make(map[int64]*m.Action)
+	for _, action := range model.Actions {
+		actions[action.Id] = action
+	}
+	var conditions = make(map[int64]*m.Condition)
+
+	var err error
+	m.WalkTaskSteps(model.Steps, func(step *m.TaskStep) {
+		if step.ActionId != nil {
+			if _, ok := actions[*step.ActionId]; !ok {
+				if actions[*step.ActionId], err = a.adaptors.Action.GetById(context.Background(), *step.ActionId); err != nil {
+					log.Error(err.Error())
+				}
+			}
+			step.Action = actions[*step.ActionId]
+		}
+		if step.ConditionId != nil {
+			if _, ok := conditions[*step.ConditionId]; !ok {
+				if conditions[*step.ConditionId], err = a.adaptors.Condition.GetById(context.Background(), *step.ConditionId); err != nil {
+					log.Error(err.Error())
+				}
+			}
+			step.Condition = conditions[*step.ConditionId]
+		}
+	})
+}
+
 func (a *taskManager) unloadTask(id int64) {
 	a.Lock()
 	defer a.Unlock()
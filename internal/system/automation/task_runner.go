@@ -0,0 +1,169 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package automation
+
+import (
+	"context"
+	"sync"
+
+	"github.com/e154/smart-home/pkg/common"
+)
+
+// DefaultMaxRuns ...
+const DefaultMaxRuns = 10
+
+// RunFunc ...
+type RunFunc func(ctx context.Context)
+
+type taskRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// TaskRunner decides what happens when the task is fired again
+// while the previous run is still going
+type TaskRunner struct {
+	mode    common.TaskMode
+	maxRuns int
+	sync.Mutex
+	lastId  uint64
+	runs    map[uint64]*taskRun
+	queue   []RunFunc
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewTaskRunner ...
+func NewTaskRunner(mode common.TaskMode, maxRuns int) *TaskRunner {
+	if mode == "" {
+		mode = common.TaskModeSingle
+	}
+	if maxRuns <= 0 {
+		maxRuns = DefaultMaxRuns
+	}
+	return &TaskRunner{
+		mode:    mode,
+		maxRuns: maxRuns,
+		runs:    make(map[uint64]*taskRun),
+	}
+}
+
+// Run starts f according to the mode of the task, returns false if the run was skipped
+func (r *TaskRunner) Run(f RunFunc) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.stopped {
+		return false
+	}
+
+	switch r.mode {
+	case common.TaskModeRestart:
+		var prev = make([]chan struct{}, 0, len(r.runs))
+		for _, run := range r.runs {
+			run.cancel()
+			prev = append(prev, run.done)
+		}
+		r.start(func(ctx context.Context) {
+			for _, done := range prev {
+				<-done
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			f(ctx)
+		})
+	case common.TaskModeQueued:
+		if len(r.runs)+len(r.queue) >= r.maxRuns {
+			return false
+		}
+		if len(r.runs) > 0 {
+			r.queue = append(r.queue, f)
+			return true
+		}
+		r.start(f)
+	case common.TaskModeParallel:
+		if len(r.runs) >= r.maxRuns {
+			return false
+		}
+		r.start(f)
+	default:
+		if len(r.runs) > 0 {
+			return false
+		}
+		r.start(f)
+	}
+
+	return true
+}
+
+// Runs returns the number of the active and queued runs
+func (r *TaskRunner) Runs() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.runs) + len(r.queue)
+}
+
+// Stop cancels all runs and waits for them to finish
+func (r *TaskRunner) Stop() {
+	r.Lock()
+	r.stopped = true
+	r.queue = nil
+	for _, run := range r.runs {
+		run.cancel()
+	}
+	r.Unlock()
+
+	r.wg.Wait()
+}
+
+func (r *TaskRunner) start(f RunFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.lastId++
+	id := r.lastId
+	run := &taskRun{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	r.runs[id] = run
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer close(run.done)
+		f(ctx)
+		cancel()
+		r.finish(id)
+	}()
+}
+
+func (r *TaskRunner) finish(id uint64) {
+	r.Lock()
+	defer r.Unlock()
+
+	delete(r.runs, id)
+
+	if r.stopped || len(r.queue) == 0 {
+		return
+	}
+
+	f := r.queue[0]
+	r.queue = r.queue[1:]
+	r.start(f)
+}
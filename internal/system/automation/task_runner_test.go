@@ -0,0 +1,132 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package automation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func blockingRun(counter *atomic.Int32, release chan struct{}) RunFunc {
+	return func(ctx context.Context) {
+		counter.Inc()
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+	}
+}
+
+func TestTaskRunnerSingle(t *testing.T) {
+	runner := NewTaskRunner(common.TaskModeSingle, 0)
+	counter := atomic.NewInt32(0)
+	release := make(chan struct{})
+
+	require.True(t, runner.Run(blockingRun(counter, release)))
+	require.False(t, runner.Run(blockingRun(counter, release)))
+
+	close(release)
+	require.Eventually(t, func() bool { return runner.Runs() == 0 }, time.Second, time.Millisecond)
+	require.Equal(t, int32(1), counter.Load())
+
+	require.True(t, runner.Run(func(ctx context.Context) {}))
+	runner.Stop()
+}
+
+func TestTaskRunnerRestart(t *testing.T) {
+	runner := NewTaskRunner(common.TaskModeRestart, 0)
+	cancelled := atomic.NewBool(false)
+	started := make(chan struct{})
+
+	require.True(t, runner.Run(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		cancelled.Store(true)
+	}))
+	<-started
+
+	// the previous run must be finished before the new one
+	finished := make(chan bool, 1)
+	require.True(t, runner.Run(func(ctx context.Context) {
+		finished <- cancelled.Load()
+	}))
+
+	select {
+	case ok := <-finished:
+		require.True(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("restarted run was not executed")
+	}
+	runner.Stop()
+}
+
+func TestTaskRunnerQueued(t *testing.T) {
+	runner := NewTaskRunner(common.TaskModeQueued, 3)
+
+	var mx sync.Mutex
+	var order []int
+	release := make(chan struct{})
+	run := func(i int) RunFunc {
+		return func(ctx context.Context) {
+			if i == 0 {
+				<-release
+			}
+			mx.Lock()
+			order = append(order, i)
+			mx.Unlock()
+		}
+	}
+
+	require.True(t, runner.Run(run(0)))
+	require.True(t, runner.Run(run(1)))
+	require.True(t, runner.Run(run(2)))
+	require.False(t, runner.Run(run(3)))
+	require.Equal(t, 3, runner.Runs())
+
+	close(release)
+	require.Eventually(t, func() bool { return runner.Runs() == 0 }, time.Second, time.Millisecond)
+
+	mx.Lock()
+	require.Equal(t, []int{0, 1, 2}, order)
+	mx.Unlock()
+	runner.Stop()
+}
+
+func TestTaskRunnerParallel(t *testing.T) {
+	runner := NewTaskRunner(common.TaskModeParallel, 2)
+	counter := atomic.NewInt32(0)
+	release := make(chan struct{})
+
+	require.True(t, runner.Run(blockingRun(counter, release)))
+	require.True(t, runner.Run(blockingRun(counter, release)))
+	require.False(t, runner.Run(blockingRun(counter, release)))
+	require.Eventually(t, func() bool { return counter.Load() == 2 }, time.Second, time.Millisecond)
+
+	// stop cancels the active runs through the context
+	runner.Stop()
+	require.Equal(t, 0, runner.Runs())
+	require.False(t, runner.Run(blockingRun(counter, release)))
+}
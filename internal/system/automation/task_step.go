@@ -0,0 +1,308 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/common/telemetry"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// DefaultMaxIterations limits the repeat step without the max iterations
+const DefaultMaxIterations = 1000
+
+var (
+	// ErrWaitTimeout ...
+	ErrWaitTimeout = errors.New("wait timeout")
+	// ErrStepNotFound ...
+	ErrStepNotFound = errors.New("step not found")
+)
+
+// runSteps executes the steps one after another, every step gets its own telemetry span.
+// The returned context holds the span of the last step.
+func (t *Task) runSteps(runCtx, spanCtx context.Context, steps []*m.TaskStep, entityId *common.EntityId) (context.Context, error) {
+	for _, step := range steps {
+		if err := runCtx.Err(); err != nil {
+			return spanCtx, err
+		}
+
+		var span *telemetry.Span
+		var started = time.Now()
+		spanCtx, span = telemetry.Start(spanCtx, step.Type.String())
+		if step.ActionId != nil {
+			span.SetAttributes("id", *step.ActionId)
+		}
+		result, err := t.runStep(runCtx, spanCtx, step, entityId)
+		if err != nil {
+			span.SetStatus(telemetry.Error, err.Error())
+		}
+		span.End()
+
+		if trace := runTraceFromContext(runCtx); trace != nil {
+			id := step.ActionId
+			if id == nil {
+				id = step.ConditionId
+			}
+			trace.add(&m.TaskRunStep{
+				Type:     step.Type.String(),
+				Id:       id,
+				Level:    span.Level,
+				Result:   result,
+				Start:    started,
+				Duration: span.TimeEstimate(),
+			}, err)
+		}
+
+		if err == nil {
+			continue
+		}
+
+		// the failed action does not break the sequence
+		if step.Type == common.StepTypeAction && !errors.Is(err, ErrStepNotFound) {
+			log.Error(err.Error())
+			continue
+		}
+		return spanCtx, err
+	}
+	return spanCtx, nil
+}
+
+// runStep executes the step, the result is saved in the trace of the run
+func (t *Task) runStep(runCtx, spanCtx context.Context, step *m.TaskStep, entityId *common.EntityId) (result string, err error) {
+	switch step.Type {
+	case common.StepTypeAction:
+		result, err = t.stepAction(runCtx, step, entityId)
+	case common.StepTypeDelay:
+		err = t.stepDelay(runCtx, step)
+	case common.StepTypeWait:
+		err = t.stepWait(runCtx, step)
+	case common.StepTypeIf:
+		result, err = t.stepIf(runCtx, spanCtx, step, entityId)
+	case common.StepTypeRepeat:
+		result, err = t.stepRepeat(runCtx, spanCtx, step, entityId)
+	case common.StepTypeParallel:
+		err = t.stepParallel(runCtx, spanCtx, step, entityId)
+	default:
+		err = fmt.Errorf("unknown step type \"%s\"", step.Type)
+	}
+	return
+}
+
+func (t *Task) stepAction(runCtx context.Context, step *m.TaskStep, entityId *common.EntityId) (result string, err error) {
+	if step.ActionId == nil {
+		err = fmt.Errorf("action step without action id: %w", ErrStepNotFound)
+		return
+	}
+
+	t.actionsMx.Lock()
+	action, ok := t.actions[*step.ActionId]
+	t.actionsMx.Unlock()
+	if !ok || action == nil {
+		err = fmt.Errorf("action id:%d: %w", *step.ActionId, ErrStepNotFound)
+		return
+	}
+
+	result, err = action.Run(runCtx, entityId)
+	return
+}
+
+func (t *Task) stepDelay(runCtx context.Context, step *m.TaskStep) error {
+	timer := time.NewTimer(time.Duration(step.Delay) * time.Millisecond)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-runCtx.Done():
+		return runCtx.Err()
+	}
+}
+
+func (t *Task) stepWait(runCtx context.Context, step *m.TaskStep) error {
+	if step.EntityId == nil {
+		return fmt.Errorf("wait step without entity id: %w", ErrStepNotFound)
+	}
+
+	ch := t.stateWaiter.Add(*step.EntityId, step.State)
+	defer t.stateWaiter.Remove(*step.EntityId, ch)
+
+	// the entity may already be in the desired state
+	if t.supervisor != nil {
+		if entity, err := t.supervisor.GetEntityById(*step.EntityId); err == nil {
+			if entity.State != nil && entity.State.Name == step.State {
+				return nil
+			}
+		}
+	}
+
+	var timeout <-chan time.Time
+	if step.Timeout > 0 {
+		timer := time.NewTimer(time.Duration(step.Timeout) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-ch:
+		return nil
+	case <-timeout:
+		if step.ContinueOnTimeout {
+			return nil
+		}
+		return fmt.Errorf("entity %s state \"%s\": %w", step.EntityId, step.State, ErrWaitTimeout)
+	case <-runCtx.Done():
+		return runCtx.Err()
+	}
+}
+
+func (t *Task) stepIf(runCtx, spanCtx context.Context, step *m.TaskStep, entityId *common.EntityId) (branch string, err error) {
+	var ok bool
+	if ok, err = t.checkStepCondition(step, entityId); err != nil {
+		return
+	}
+	if ok {
+		branch = "then"
+		_, err = t.runSteps(runCtx, spanCtx, step.Then, entityId)
+	} else {
+		branch = "else"
+		_, err = t.runSteps(runCtx, spanCtx, step.Else, entityId)
+	}
+	return
+}
+
+func (t *Task) stepRepeat(runCtx, spanCtx context.Context, step *m.TaskStep, entityId *common.EntityId) (iterations string, err error) {
+	var maxIterations = step.MaxIterations
+	if maxIterations == 0 {
+		maxIterations = DefaultMaxIterations
+	}
+
+	var done bool
+	for i := 1; i <= maxIterations; i++ {
+		iterations = strconv.Itoa(i)
+		if spanCtx, err = t.runSteps(runCtx, spanCtx, step.Steps, entityId); err != nil {
+			return
+		}
+		if step.ConditionId == nil {
+			continue
+		}
+		if done, err = t.checkStepCondition(step, entityId); err != nil || done {
+			return
+		}
+	}
+	return
+}
+
+func (t *Task) stepParallel(runCtx, spanCtx context.Context, step *m.TaskStep, entityId *common.EntityId) error {
+	var wg sync.WaitGroup
+	var errs = make([]error, len(step.Steps))
+	for i, nested := range step.Steps {
+		wg.Add(1)
+		go func(i int, nested *m.TaskStep) {
+			defer wg.Done()
+			_, errs[i] = t.runSteps(runCtx, spanCtx, []*m.TaskStep{nested}, entityId)
+		}(i, nested)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (t *Task) checkStepCondition(step *m.TaskStep, entityId *common.EntityId) (bool, error) {
+	if step.ConditionId == nil {
+		return false, fmt.Errorf("%s step without condition id: %w", step.Type, ErrStepNotFound)
+	}
+
+	t.stepConditionsMx.Lock()
+	condition, ok := t.stepConditions[*step.ConditionId]
+	t.stepConditionsMx.Unlock()
+	if !ok {
+		return false, fmt.Errorf("condition id:%d: %w", *step.ConditionId, ErrStepNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := condition.Check(context.WithValue(ctx, "entityId", entityId)); err != nil {
+		return false, err
+	}
+	return condition.Status(), nil
+}
+
+// stateWaiter notifies the wait steps about the entity state changes
+type stateWaiter struct {
+	sync.Mutex
+	waiters map[common.EntityId]map[chan struct{}]string
+}
+
+func newStateWaiter() *stateWaiter {
+	return &stateWaiter{
+		waiters: make(map[common.EntityId]map[chan struct{}]string),
+	}
+}
+
+// Add ...
+func (w *stateWaiter) Add(entityId common.EntityId, state string) chan struct{} {
+	w.Lock()
+	defer w.Unlock()
+
+	ch := make(chan struct{}, 1)
+	if _, ok := w.waiters[entityId]; !ok {
+		w.waiters[entityId] = make(map[chan struct{}]string)
+	}
+	w.waiters[entityId][ch] = state
+	return ch
+}
+
+// Remove ...
+func (w *stateWaiter) Remove(entityId common.EntityId, ch chan struct{}) {
+	w.Lock()
+	defer w.Unlock()
+
+	delete(w.waiters[entityId], ch)
+	if len(w.waiters[entityId]) == 0 {
+		delete(w.waiters, entityId)
+	}
+}
+
+func (w *stateWaiter) eventHandler(_ string, msg interface{}) {
+	v, ok := msg.(events.EventStateChanged)
+	if !ok || v.NewState.State == nil {
+		return
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	for ch, state := range w.waiters[v.EntityId] {
+		if state != v.NewState.State.Name {
+			continue
+		}
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
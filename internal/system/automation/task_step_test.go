@@ -0,0 +1,136 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package automation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/common/telemetry"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/e154/bus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskSteps(t *testing.T) {
+
+	var entityId = common.EntityId("sensor.door")
+
+	newTask := func(steps ...*m.TaskStep) *Task {
+		eventBus := bus.NewBus()
+		task := NewTask(eventBus, nil, nil, &m.Task{Id: 1, Steps: steps})
+		_ = eventBus.Subscribe("system/entities/"+entityId.String(), task.stateWaiter.eventHandler, false)
+		return task
+	}
+
+	t.Run("delay", func(t *testing.T) {
+		task := newTask()
+		started := time.Now()
+		_, err := task.runSteps(context.Background(), context.Background(), []*m.TaskStep{
+			{Type: common.StepTypeDelay, Delay: 50},
+			{Type: common.StepTypeDelay, Delay: 50},
+		}, nil)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, time.Since(started), time.Millisecond*100)
+	})
+
+	t.Run("delay cancelled", func(t *testing.T) {
+		task := newTask()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+		_, err := task.runSteps(ctx, context.Background(), []*m.TaskStep{
+			{Type: common.StepTypeDelay, Delay: 10000},
+		}, nil)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		task := newTask()
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			task.eventBus.Publish("system/entities/"+entityId.String(), events.EventStateChanged{
+				EntityId: entityId,
+				NewState: events.EventEntityState{
+					State: &events.EntityState{Name: "open"},
+				},
+			})
+		}()
+		_, err := task.runSteps(context.Background(), context.Background(), []*m.TaskStep{
+			{Type: common.StepTypeWait, EntityId: entityId.Ptr(), State: "open", Timeout: 1000},
+		}, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("wait timeout", func(t *testing.T) {
+		task := newTask()
+		steps := []*m.TaskStep{
+			{Type: common.StepTypeWait, EntityId: entityId.Ptr(), State: "open", Timeout: 50},
+		}
+		_, err := task.runSteps(context.Background(), context.Background(), steps, nil)
+		require.ErrorIs(t, err, ErrWaitTimeout)
+
+		steps[0].ContinueOnTimeout = true
+		_, err = task.runSteps(context.Background(), context.Background(), steps, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		task := newTask()
+		started := time.Now()
+		_, err := task.runSteps(context.Background(), context.Background(), []*m.TaskStep{
+			{Type: common.StepTypeParallel, Steps: []*m.TaskStep{
+				{Type: common.StepTypeDelay, Delay: 100},
+				{Type: common.StepTypeDelay, Delay: 100},
+				{Type: common.StepTypeDelay, Delay: 100},
+			}},
+		}, nil)
+		require.NoError(t, err)
+		require.Less(t, time.Since(started), time.Millisecond*250)
+	})
+
+	t.Run("unknown references", func(t *testing.T) {
+		task := newTask()
+		_, err := task.runSteps(context.Background(), context.Background(), []*m.TaskStep{
+			{Type: common.StepTypeAction, ActionId: common.Int64(1)},
+		}, nil)
+		require.True(t, errors.Is(err, ErrStepNotFound))
+
+		_, err = task.runSteps(context.Background(), context.Background(), []*m.TaskStep{
+			{Type: common.StepTypeIf, ConditionId: common.Int64(1)},
+		}, nil)
+		require.True(t, errors.Is(err, ErrStepNotFound))
+	})
+
+	t.Run("telemetry", func(t *testing.T) {
+		task := newTask()
+		ctx, span := telemetry.Start(context.Background(), "task")
+		ctx, err := task.runSteps(context.Background(), ctx, []*m.TaskStep{
+			{Type: common.StepTypeDelay, Delay: 1},
+			{Type: common.StepTypeDelay, Delay: 1},
+		}, nil)
+		span.End()
+		require.NoError(t, err)
+		require.Len(t, telemetry.Unpack(ctx), 3)
+	})
+}
@@ -0,0 +1,112 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+type runTraceKey struct{}
+
+// runTrace collects the conditions and steps executed during the task run
+type runTrace struct {
+	sync.Mutex
+	run    *m.TaskRun
+	failed bool
+}
+
+func newRunTrace(taskId int64, v events.EventTriggerCompleted) *runTrace {
+	run := &m.TaskRun{
+		TaskId:   taskId,
+		EntityId: v.EntityId,
+		Start:    time.Now(),
+		Trace:    make([]*m.TaskRunStep, 0),
+	}
+	if v.Id != 0 {
+		run.TriggerId = common.Int64(v.Id)
+	}
+	if v.Args != nil {
+		run.Payload, _ = json.Marshal(v.Args)
+	}
+	return &runTrace{
+		run: run,
+	}
+}
+
+func withRunTrace(ctx context.Context, trace *runTrace) context.Context {
+	return context.WithValue(ctx, runTraceKey{}, trace)
+}
+
+func runTraceFromContext(ctx context.Context) *runTrace {
+	trace, _ := ctx.Value(runTraceKey{}).(*runTrace)
+	return trace
+}
+
+// add appends a record about the condition or step
+func (r *runTrace) add(item *m.TaskRunStep, err error) {
+	if err != nil {
+		item.Error = err.Error()
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	if err != nil {
+		r.failed = true
+	}
+	r.run.Trace = append(r.run.Trace, item)
+}
+
+// finish closes the run, the failed status has priority over the completed one
+func (r *runTrace) finish(status common.TaskRunStatus, err error) *m.TaskRun {
+	r.Lock()
+	defer r.Unlock()
+
+	if status == common.TaskRunCompleted && (r.failed || err != nil) {
+		status = common.TaskRunFailed
+	}
+	r.run.Status = status
+	if err != nil {
+		r.run.Error = err.Error()
+	}
+	r.run.End = common.Time(time.Now())
+	return r.run
+}
+
+// triggerEventFromRun restores the trigger event from the recorded run
+func triggerEventFromRun(run *m.TaskRun) (v events.EventTriggerCompleted) {
+	v = events.EventTriggerCompleted{
+		Id:       common.Int64Value(run.TriggerId),
+		EntityId: run.EntityId,
+		Ctx:      context.Background(),
+	}
+	if len(run.Payload) > 0 {
+		args := &events.TriggerMessage{}
+		if err := json.Unmarshal(run.Payload, args); err == nil {
+			v.Args = args
+		}
+	}
+	return
+}
@@ -0,0 +1,87 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package automation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTrace(t *testing.T) {
+
+	var entityId = common.EntityId("sensor.door")
+
+	t.Run("replay payload", func(t *testing.T) {
+		trace := newRunTrace(1, events.EventTriggerCompleted{
+			Id:       2,
+			EntityId: entityId.Ptr(),
+			Args: &events.TriggerMessage{
+				Payload:     "open",
+				TriggerName: "state_change",
+				EntityId:    entityId.Ptr(),
+			},
+		})
+		run := trace.finish(common.TaskRunCompleted, nil)
+		require.Equal(t, common.TaskRunCompleted, run.Status)
+		require.NotNil(t, run.End)
+
+		v := triggerEventFromRun(run)
+		require.Equal(t, int64(2), v.Id)
+		require.Equal(t, entityId, *v.EntityId)
+		require.NotNil(t, v.Args)
+		require.Equal(t, "open", v.Args.Payload)
+		require.Equal(t, "state_change", v.Args.TriggerName)
+	})
+
+	t.Run("failed step", func(t *testing.T) {
+		trace := newRunTrace(1, events.EventTriggerCompleted{})
+		trace.add(&m.TaskRunStep{Type: common.StepTypeDelay.String()}, nil)
+		trace.add(&m.TaskRunStep{Type: common.StepTypeAction.String()}, errors.New("boom"))
+		run := trace.finish(common.TaskRunCompleted, nil)
+		require.Equal(t, common.TaskRunFailed, run.Status)
+		require.Len(t, run.Trace, 2)
+		require.Equal(t, "boom", run.Trace[1].Error)
+		require.Nil(t, run.TriggerId)
+	})
+
+	t.Run("steps trace", func(t *testing.T) {
+		task := NewTask(nil, nil, nil, &m.Task{Id: 1})
+		trace := newRunTrace(1, events.EventTriggerCompleted{})
+		_, err := task.runSteps(withRunTrace(context.Background(), trace), context.Background(), []*m.TaskStep{
+			{Type: common.StepTypeRepeat, MaxIterations: 2, Steps: []*m.TaskStep{
+				{Type: common.StepTypeDelay, Delay: 1},
+			}},
+		}, nil)
+		require.NoError(t, err)
+
+		run := trace.finish(common.TaskRunCompleted, nil)
+		require.Equal(t, common.TaskRunCompleted, run.Status)
+		require.Len(t, run.Trace, 3)
+		require.Equal(t, "repeat", run.Trace[2].Type)
+		require.Equal(t, "2", run.Trace[2].Result)
+		require.Less(t, run.Trace[2].Level, run.Trace[0].Level)
+	})
+}
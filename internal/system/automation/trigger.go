@@ -161,6 +161,10 @@ func (tr *Trigger) genSubscriber(entityId *common.EntityId, check func(msg inter
 			result, err := check(args)
 			span.End()
 			if err != nil || !result {
+				tr.eventBus.Publish(fmt.Sprintf("system/automation/triggers/%d", tr.model.Id), events.EventTriggerNotPassed{
+					Id:   tr.model.Id,
+					Args: args,
+				})
 				return
 			}
 			//fmt.Println("call trigger", tr.model.Name, tr.triggerPlugin.Name())
@@ -22,6 +22,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
@@ -30,6 +31,8 @@ import (
 	"path/filepath"
 	"runtime/debug"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/e154/smart-home/internal/common"
@@ -37,6 +40,7 @@ import (
 	notifyCommon "github.com/e154/smart-home/internal/plugins/notify/common"
 	"github.com/e154/smart-home/pkg/apperr"
 	commonPkg "github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/common/encryptor"
 	"github.com/e154/smart-home/pkg/events"
 	"github.com/e154/smart-home/pkg/logger"
 	m "github.com/e154/smart-home/pkg/models"
@@ -135,11 +139,34 @@ func (b *Backup) New(scheduler bool) (err error) {
 		return
 	}
 
-	backupName := fmt.Sprintf("%s.zip", time.Now().UTC().Format("2006-01-02T15:04:05.999"))
+	storage := path.Join("data", "file_storage")
+	manifest := &Manifest{}
+	if manifest.Files, err = scanFiles(storage); err != nil {
+		return
+	}
+
+	// the incremental snapshot holds the files changed since the parent snapshot
+	if parent := b.parentManifest(db, scheduler); parent != nil {
+		manifest.Parent = snapshotKey(parent.Name)
+		manifest.Chain = parent.Chain + 1
+		storage = path.Join(tmpDir, "file_storage")
+		_ = os.RemoveAll(storage)
+		if err = stageChanged(path.Join("data", "file_storage"), storage, manifest.Files, parent); err != nil {
+			return
+		}
+	}
+
+	backupName := snapshotName(time.Now().UTC().Format("2006-01-02T15:04:05.999"), manifest.Parent)
+	manifest.Name = backupName
+	if err = writeManifest(path.Join(tmpDir, ManifestName), manifest); err != nil {
+		return
+	}
+
 	err = commonPkg.Zipit([]string{
-		path.Join("data", "file_storage"),
 		path.Join(tmpDir, "data.sql"),
 		path.Join(tmpDir, "scheme.sql"),
+		path.Join(tmpDir, ManifestName),
+		storage,
 	},
 		path.Join(b.cfg.Path, backupName))
 	if err != nil {
@@ -148,6 +175,22 @@ func (b *Backup) New(scheduler bool) (err error) {
 
 	_ = os.RemoveAll(tmpDir)
 
+	if key := getVariable(db, "backupEncryptionKey"); key != "" {
+		zipFile := path.Join(b.cfg.Path, backupName)
+		if err = EncryptFile(zipFile, zipFile+EncryptedExt, key); err != nil {
+			return
+		}
+		_ = os.Remove(zipFile)
+		backupName += EncryptedExt
+	}
+
+	manifest.Name = backupName
+	if err = writeManifest(path.Join(b.cfg.Path, stateName), manifest); err != nil {
+		return
+	}
+
+	b.upload(db, backupName)
+
 	log.Infof("Snapshot %s successfully created", backupName)
 
 	b.eventBus.Publish("system/services/backup", events.EventCreatedBackup{
@@ -282,35 +325,14 @@ func (b *Backup) ApplyChanges() (err error) {
 func (b *Backup) RestoreFile(name string) (err error) {
 	log.Infof("restore backup file %s", name)
 
-	var list []*m.Backup
-	if list, _, err = b.List(context.Background(), 999, 0, "", ""); err != nil {
-		return
-	}
-
-	var exist bool
-	for _, file := range list {
-		if name == file.Name {
-			exist = true
-			break
-		}
-	}
-
-	if !exist {
-		err = apperr.ErrBackupNotFound
-		return
-	}
-
-	file := path.Join(b.cfg.Path, name)
-
-	_, err = os.Stat(file)
-	if os.IsNotExist(err) {
-		err = fmt.Errorf("path %s: %w", file, apperr.ErrBackupNotFound)
+	tmpDir := path.Join(os.TempDir(), "smart_home")
+	_ = os.RemoveAll(path.Join(tmpDir, "file_storage"))
+	_ = os.Remove(path.Join(tmpDir, ManifestName))
+	if err = b.unpack(name, tmpDir); err != nil {
 		return
 	}
 
-	tmpDir := path.Join(os.TempDir(), "smart_home")
-	if err = commonPkg.Unzip(file, tmpDir); err != nil {
-		err = fmt.Errorf("%s: failed unzip file %s", err.Error(), file)
+	if err = b.restoreChain(tmpDir); err != nil {
 		return
 	}
 
@@ -392,6 +414,138 @@ func (b *Backup) RestoreFile(name string) (err error) {
 	return
 }
 
+// unpack downloads the snapshot if it is missing in the local storage, decrypts and unzips it
+func (b *Backup) unpack(name, dir string) (err error) {
+	var list []*m.Backup
+	if list, _, err = b.List(context.Background(), 999, 0, "", ""); err != nil {
+		return
+	}
+
+	var exist bool
+	for _, file := range list {
+		if name == file.Name {
+			exist = true
+			break
+		}
+	}
+
+	if !exist {
+		if err = b.download(name); err != nil {
+			return
+		}
+	}
+
+	file := path.Join(b.cfg.Path, name)
+
+	_, err = os.Stat(file)
+	if os.IsNotExist(err) {
+		err = fmt.Errorf("path %s: %w", file, apperr.ErrBackupNotFound)
+		return
+	}
+
+	if strings.HasSuffix(name, EncryptedExt) {
+		var key string
+		if key, err = b.variable("backupEncryptionKey"); err != nil {
+			return
+		}
+		decrypted := path.Join(os.TempDir(), strings.TrimSuffix(name, EncryptedExt))
+		log.Infof("decrypt backup file %s", name)
+		if err = DecryptFile(file, decrypted, key); err != nil {
+			return
+		}
+		defer os.Remove(decrypted)
+		file = decrypted
+	}
+
+	if err = commonPkg.Unzip(file, dir); err != nil {
+		err = fmt.Errorf("%s: failed unzip file %s", err.Error(), file)
+	}
+	return
+}
+
+// restoreChain completes the file storage of the incremental snapshot unpacked to the dir,
+// the missing files are taken from the parents, the newest parent first
+func (b *Backup) restoreChain(dir string) (err error) {
+	var manifest *Manifest
+	if manifest, err = readManifest(path.Join(dir, ManifestName)); err != nil || manifest == nil {
+		return
+	}
+
+	storage := path.Join(dir, "file_storage")
+	parentDir := path.Join(os.TempDir(), "smart_home_parent")
+	defer os.RemoveAll(parentDir)
+
+	for parent := manifest.Parent; parent != ""; {
+		var name string
+		if name, err = b.resolve(parent); err != nil {
+			return
+		}
+		log.Infof("restore files from the parent snapshot %s", name)
+		_ = os.RemoveAll(parentDir)
+		if err = b.unpack(name, parentDir); err != nil {
+			return
+		}
+		if err = fillMissing(path.Join(parentDir, "file_storage"), storage, manifest); err != nil {
+			return
+		}
+		var parentManifest *Manifest
+		if parentManifest, err = readManifest(path.Join(parentDir, ManifestName)); err != nil {
+			return
+		}
+		if parentManifest == nil {
+			break
+		}
+		parent = parentManifest.Parent
+	}
+
+	return checkFiles(storage, manifest)
+}
+
+// resolve returns the name of the snapshot by its key from the local storage or the targets
+func (b *Backup) resolve(key string) (name string, err error) {
+	var list []*m.Backup
+	if list, _, err = b.List(context.Background(), 999, 0, "", ""); err != nil {
+		return
+	}
+	var targets []BackupTarget
+	if targets, err = b.targets(); err != nil {
+		return
+	}
+	for i := -1; i < len(targets); i++ {
+		if i >= 0 {
+			if list, err = targets[i].List(context.Background()); err != nil {
+				log.Warnf("target %s: %s", targets[i].Name(), err.Error())
+				continue
+			}
+		}
+		for _, file := range list {
+			if snapshotKey(file.Name) == key {
+				name = file.Name
+				return
+			}
+		}
+	}
+	err = fmt.Errorf("parent snapshot %s: %w", key, apperr.ErrBackupNotFound)
+	return
+}
+
+// parentManifest returns the last snapshot if the scheduled snapshot continues its chain,
+// a full snapshot is created every backupFullEvery snapshots
+func (b *Backup) parentManifest(db *gorm.DB, scheduler bool) *Manifest {
+	if !scheduler {
+		return nil
+	}
+	fullEvery, _ := strconv.Atoi(getVariable(db, "backupFullEvery"))
+	state, err := readManifest(path.Join(b.cfg.Path, stateName))
+	if err != nil || state == nil || state.Chain+1 >= fullEvery {
+		return nil
+	}
+	if _, err = os.Stat(path.Join(b.cfg.Path, state.Name)); err != nil {
+		return nil
+	}
+	return state
+}
+
 // Delete ...
 func (b *Backup) Delete(name string) (err error) {
 	log.Infof("remove file %s", name)
@@ -476,27 +630,151 @@ func (b *Backup) UploadBackup(ctx context.Context, reader *bufio.Reader, fileNam
 	return
 }
 
-func (b *Backup) ClearStorage(num int64) (err error) {
-	log.Infof("clear storage, maximum number of backups %d ...", num)
+// upload copies the snapshot to the off-site targets
+func (b *Backup) upload(db *gorm.DB, name string) {
+	targets, err := NewTargets(getVariable(db, "backupTargets"))
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
 
-	var list []*m.Backup
-	var total int64
-	if list, total, err = b.List(context.Background(), 0, 0, "", ""); err != nil {
+	file := path.Join(b.cfg.Path, name)
+	for _, target := range targets {
+		log.Infof("upload snapshot %s to target %s", name, target.Name())
+		if err = target.Upload(context.Background(), file, name); err != nil {
+			log.Errorf("target %s: %s", target.Name(), err.Error())
+			continue
+		}
+		b.eventBus.Publish("system/services/backup", events.EventUploadedBackupToTarget{
+			Name:   name,
+			Target: target.Name(),
+		})
+	}
+}
+
+// download copies the snapshot from the first target that has it
+func (b *Backup) download(name string) (err error) {
+	var targets []BackupTarget
+	if targets, err = b.targets(); err != nil {
 		return
 	}
+	for _, target := range targets {
+		log.Infof("download snapshot %s from target %s", name, target.Name())
+		if err = target.Download(context.Background(), name, path.Join(b.cfg.Path, name)); err == nil {
+			return
+		}
+		log.Warnf("target %s: %s", target.Name(), err.Error())
+	}
+	err = apperr.ErrBackupNotFound
+	return
+}
 
-	if total <= num {
+// targets returns the off-site targets from the settings
+func (b *Backup) targets() (targets []BackupTarget, err error) {
+	var value string
+	if value, err = b.variable("backupTargets"); err != nil {
 		return
 	}
+	targets, err = NewTargets(value)
+	return
+}
 
-	var diff = list[num:]
-	for _, file := range diff {
-		b.Delete(file.Name)
+// variable reads the settings, the backup does not depend on the adaptors
+func (b *Backup) variable(name string) (value string, err error) {
+	var db *gorm.DB
+	if db, err = gorm.Open(postgres.Open(b.cfg.String()), &gorm.Config{}); err != nil {
+		return
 	}
+	defer func() {
+		if _db, err := db.DB(); err == nil {
+			_ = _db.Close()
+		}
+	}()
 
+	value = getVariable(db, name)
 	return
 }
 
+// ClearStorage removes the snapshots by the retention policy from the local storage and the targets
+func (b *Backup) ClearStorage(retention Retention) (err error) {
+	if retention.IsGFS() {
+		log.Infof("clear storage, keep daily %d, weekly %d, monthly %d ...", retention.Daily, retention.Weekly, retention.Monthly)
+	} else {
+		log.Infof("clear storage, maximum number of backups %d ...", retention.Num)
+	}
+
+	var list []*m.Backup
+	if list, _, err = b.List(context.Background(), 0, 0, "", ""); err != nil {
+		return
+	}
+
+	for _, file := range retention.Expired(list) {
+		_ = b.Delete(file.Name)
+	}
+
+	var targets []BackupTarget
+	if targets, err = b.targets(); err != nil {
+		return
+	}
+	for _, target := range targets {
+		if list, err = target.List(context.Background()); err != nil {
+			log.Errorf("target %s: %s", target.Name(), err.Error())
+			continue
+		}
+		for _, file := range retention.Expired(list) {
+			log.Infof("remove file %s from target %s", file.Name, target.Name())
+			if err = target.Delete(context.Background(), file.Name); err != nil {
+				log.Errorf("target %s: %s", target.Name(), err.Error())
+			}
+		}
+	}
+	err = nil
+
+	return
+}
+
+func getVariable(db *gorm.DB, name string) (value string) {
+	var variable struct {
+		Value     string
+		Encrypted bool
+	}
+	if err := db.Raw(`SELECT value, encrypted FROM variables WHERE name = ?`, name).Scan(&variable).Error; err != nil {
+		// the schema before the encrypted variables
+		db.Raw(`SELECT value FROM variables WHERE name = ?`, name).Scan(&value)
+		return
+	}
+	if !variable.Encrypted || variable.Value == "" {
+		return variable.Value
+	}
+	// the cli commands do not load the server key, take it from the database
+	var key string
+	db.Raw(`SELECT value FROM variables WHERE name = 'encryptor'`).Scan(&key)
+	var err error
+	if value, err = decryptVariable(key, variable.Value); err != nil {
+		log.Errorf("variable %s: %s", name, err.Error())
+	}
+	return
+}
+
+func decryptVariable(key, value string) (string, error) {
+	k, err := hex.DecodeString(key)
+	if err != nil {
+		return "", err
+	}
+	e, err := encryptor.New(k)
+	if err != nil {
+		return "", err
+	}
+	b, err := hex.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	if b, err = e.Decrypt(b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 func (b *Backup) RestoreFromChunks() {
 
 	inputPattern := "*_part*.dat"
@@ -608,7 +886,12 @@ func (b *Backup) eventHandler(_ string, message interface{}) {
 		}()
 	case events.CommandClearStorage:
 		go func() {
-			if err := b.ClearStorage(v.Num); err != nil {
+			if err := b.ClearStorage(Retention{
+				Num:     v.Num,
+				Daily:   v.Daily,
+				Weekly:  v.Weekly,
+				Monthly: v.Monthly,
+			}); err != nil {
 				log.Error(err.Error())
 			}
 		}()
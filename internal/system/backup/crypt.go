@@ -0,0 +1,179 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// EncryptedExt is the extension of the encrypted snapshot
+const EncryptedExt = ".enc"
+
+const (
+	cryptChunkSize = 64 * 1024
+	cryptSaltSize  = 16
+	cryptPrefixLen = 4
+)
+
+var (
+	cryptMagic = []byte("SHBK\x01")
+	// ErrDecrypt ...
+	ErrDecrypt = errors.New("failed to decrypt the snapshot")
+)
+
+// The file is encrypted with AES-256-GCM by chunks, the key is derived from the passphrase by scrypt.
+// Header: magic, salt, nonce prefix. The nonce of the chunk is the prefix and the chunk number,
+// the last chunk is authenticated with the additional data, so the truncated file is detected.
+
+// EncryptFile ...
+func EncryptFile(src, dst, passphrase string) (err error) {
+	var in, out *os.File
+	if in, err = os.Open(src); err != nil {
+		return
+	}
+	defer in.Close()
+	if out, err = os.Create(dst); err != nil {
+		return
+	}
+	defer out.Close()
+
+	var header = make([]byte, len(cryptMagic)+cryptSaltSize+cryptPrefixLen)
+	copy(header, cryptMagic)
+	if _, err = rand.Read(header[len(cryptMagic):]); err != nil {
+		return
+	}
+	salt := header[len(cryptMagic) : len(cryptMagic)+cryptSaltSize]
+	prefix := header[len(cryptMagic)+cryptSaltSize:]
+
+	var aead cipher.AEAD
+	if aead, err = newCryptAEAD(passphrase, salt); err != nil {
+		return
+	}
+	if _, err = out.Write(header); err != nil {
+		return
+	}
+
+	reader := bufio.NewReaderSize(in, cryptChunkSize)
+	var buf = make([]byte, cryptChunkSize)
+	for counter := uint64(0); ; counter++ {
+		var n int
+		n, err = io.ReadFull(reader, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return
+		}
+		_, peekErr := reader.Peek(1)
+		last := peekErr != nil
+		if _, err = out.Write(aead.Seal(nil, chunkNonce(prefix, counter), buf[:n], chunkAD(last))); err != nil {
+			return
+		}
+		if last {
+			return
+		}
+	}
+}
+
+// DecryptFile ...
+func DecryptFile(src, dst, passphrase string) (err error) {
+	var in, out *os.File
+	if in, err = os.Open(src); err != nil {
+		return
+	}
+	defer in.Close()
+
+	reader := bufio.NewReaderSize(in, cryptChunkSize+aes.BlockSize)
+	var header = make([]byte, len(cryptMagic)+cryptSaltSize+cryptPrefixLen)
+	if _, err = io.ReadFull(reader, header); err != nil || !bytes.Equal(header[:len(cryptMagic)], cryptMagic) {
+		return fmt.Errorf("bad header: %w", ErrDecrypt)
+	}
+	salt := header[len(cryptMagic) : len(cryptMagic)+cryptSaltSize]
+	prefix := header[len(cryptMagic)+cryptSaltSize:]
+
+	var aead cipher.AEAD
+	if aead, err = newCryptAEAD(passphrase, salt); err != nil {
+		return
+	}
+
+	if out, err = os.Create(dst); err != nil {
+		return
+	}
+	defer func() {
+		_ = out.Close()
+		if err != nil {
+			_ = os.Remove(dst)
+		}
+	}()
+
+	var buf = make([]byte, cryptChunkSize+aead.Overhead())
+	for counter := uint64(0); ; counter++ {
+		var n int
+		n, err = io.ReadFull(reader, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return
+		}
+		_, peekErr := reader.Peek(1)
+		last := peekErr != nil
+		var chunk []byte
+		if chunk, err = aead.Open(nil, chunkNonce(prefix, counter), buf[:n], chunkAD(last)); err != nil {
+			return fmt.Errorf("chunk %d: %w", counter, ErrDecrypt)
+		}
+		if _, err = out.Write(chunk); err != nil {
+			return
+		}
+		if last {
+			return
+		}
+	}
+}
+
+func newCryptAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, counter uint64) []byte {
+	nonce := make([]byte, cryptPrefixLen+8)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint64(nonce[cryptPrefixLen:], counter)
+	return nonce
+}
+
+func chunkAD(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
@@ -0,0 +1,83 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/e154/smart-home/pkg/common/encryptor"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrypt(t *testing.T) {
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "backup.zip")
+	enc := filepath.Join(dir, "backup.zip"+EncryptedExt)
+	dst := filepath.Join(dir, "restored.zip")
+
+	for _, size := range []int{0, 100, cryptChunkSize, cryptChunkSize*2 + 17} {
+		data := make([]byte, size)
+		_, _ = rand.Read(data)
+		require.NoError(t, os.WriteFile(src, data, 0644))
+
+		require.NoError(t, EncryptFile(src, enc, "passphrase"))
+		encrypted, err := os.ReadFile(enc)
+		require.NoError(t, err)
+		if size > 0 {
+			require.False(t, bytes.Contains(encrypted, data))
+		}
+
+		require.NoError(t, DecryptFile(enc, dst, "passphrase"))
+		restored, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		require.Equal(t, data, restored)
+
+		require.ErrorIs(t, DecryptFile(enc, dst, "wrong"), ErrDecrypt)
+		require.NoFileExists(t, dst)
+	}
+
+	// the file is truncated at the chunk boundary
+	encrypted, err := os.ReadFile(enc)
+	require.NoError(t, err)
+	header := len(cryptMagic) + cryptSaltSize + cryptPrefixLen
+	require.NoError(t, os.WriteFile(enc, encrypted[:header+cryptChunkSize+16], 0644))
+	require.ErrorIs(t, DecryptFile(enc, dst, "passphrase"), ErrDecrypt)
+}
+
+func TestDecryptVariable(t *testing.T) {
+
+	key := encryptor.GenKey()
+	encryptor.SetKey(key)
+	value, err := encryptor.Encrypt("passphrase")
+	require.NoError(t, err)
+
+	decrypted, err := decryptVariable(hex.EncodeToString(key), value)
+	require.NoError(t, err)
+	require.Equal(t, "passphrase", decrypted)
+
+	_, err = decryptVariable(hex.EncodeToString(encryptor.GenKey()), value)
+	require.Error(t, err)
+}
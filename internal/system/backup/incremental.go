@@ -0,0 +1,188 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ManifestName is the file of the snapshot that describes the file storage
+	ManifestName = "manifest.json"
+	// parentSep separates the key of the incremental snapshot from the key of its parent
+	parentSep = "_from_"
+	// stateName keeps the manifest of the last snapshot in the local storage
+	stateName = ".manifest.json"
+)
+
+// Manifest lists the files of the file storage with their hashes. The incremental snapshot
+// holds the changed files only, the other files are taken from the parent snapshots.
+type Manifest struct {
+	Name   string            `json:"name"`
+	Parent string            `json:"parent,omitempty"`
+	Chain  int               `json:"chain"`
+	Files  map[string]string `json:"files"`
+}
+
+// snapshotKey returns the creation time of the snapshot the parents are referred by
+func snapshotKey(name string) string {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, EncryptedExt), ".zip")
+	if i := strings.Index(name, parentSep); i >= 0 {
+		return name[:i]
+	}
+	return name
+}
+
+// snapshotParent returns the key of the parent snapshot, empty for the full snapshot
+func snapshotParent(name string) string {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, EncryptedExt), ".zip")
+	if i := strings.Index(name, parentSep); i >= 0 {
+		return name[i+len(parentSep):]
+	}
+	return ""
+}
+
+// snapshotName returns the name of the snapshot created at the key time
+func snapshotName(key, parentKey string) string {
+	if parentKey == "" {
+		return key + ".zip"
+	}
+	return key + parentSep + parentKey + ".zip"
+}
+
+// scanFiles returns the files of the dir with their hashes
+func scanFiles(dir string) (files map[string]string, err error) {
+	files = make(map[string]string)
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && file == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		var rel string
+		if rel, err = filepath.Rel(dir, file); err != nil {
+			return err
+		}
+		var hash string
+		if hash, err = fileHash(file); err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = hash
+		return nil
+	})
+	return
+}
+
+func fileHash(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// stageChanged copies the files that differ from the parent manifest
+func stageChanged(src, dst string, files map[string]string, parent *Manifest) (err error) {
+	if err = os.MkdirAll(dst, 0755); err != nil {
+		return
+	}
+	for file, hash := range files {
+		if parent.Files[file] == hash {
+			continue
+		}
+		to := path.Join(dst, file)
+		if err = os.MkdirAll(path.Dir(to), 0755); err != nil {
+			return
+		}
+		if err = CopyFile(path.Join(src, file), to); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// fillMissing copies the files of the manifest that are missing in dst from the parent snapshot
+func fillMissing(src, dst string, manifest *Manifest) (err error) {
+	for file := range manifest.Files {
+		to := path.Join(dst, file)
+		if _, err = os.Stat(to); err == nil {
+			continue
+		}
+		from := path.Join(src, file)
+		if _, err = os.Stat(from); err != nil {
+			continue
+		}
+		if err = os.MkdirAll(path.Dir(to), 0755); err != nil {
+			return
+		}
+		if err = CopyFile(from, to); err != nil {
+			return
+		}
+	}
+	return nil
+}
+
+// checkFiles verifies that the restored file storage has every file of the manifest
+func checkFiles(dir string, manifest *Manifest) error {
+	for file := range manifest.Files {
+		if _, err := os.Stat(path.Join(dir, file)); err != nil {
+			return fmt.Errorf("snapshot %s: the file %s is missing in the chain", manifest.Name, file)
+		}
+	}
+	return nil
+}
+
+// readManifest returns nil if the snapshot was created without the manifest
+func readManifest(file string) (manifest *Manifest, err error) {
+	var b []byte
+	if b, err = os.ReadFile(file); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	manifest = &Manifest{}
+	err = json.Unmarshal(b, manifest)
+	return
+}
+
+func writeManifest(file string, manifest *Manifest) (err error) {
+	var b []byte
+	if b, err = json.Marshal(manifest); err != nil {
+		return
+	}
+	return os.WriteFile(file, b, 0644)
+}
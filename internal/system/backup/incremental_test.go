@@ -0,0 +1,101 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotName(t *testing.T) {
+
+	name := snapshotName("2026-10-17T03:00:00.5", "")
+	require.Equal(t, "2026-10-17T03:00:00.5.zip", name)
+	require.Equal(t, "2026-10-17T03:00:00.5", snapshotKey(name+EncryptedExt))
+	require.Empty(t, snapshotParent(name))
+
+	name = snapshotName("2026-10-18T03:00:00", "2026-10-17T03:00:00.5")
+	require.Equal(t, "2026-10-18T03:00:00", snapshotKey(name+EncryptedExt))
+	require.Equal(t, "2026-10-17T03:00:00.5", snapshotParent(name+EncryptedExt))
+}
+
+func TestIncrementalFiles(t *testing.T) {
+
+	dir := t.TempDir()
+	storage := filepath.Join(dir, "file_storage")
+	write := func(name, data string) {
+		file := filepath.Join(storage, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte(data), 0644))
+	}
+
+	// the full snapshot
+	write("a.txt", "a")
+	write("b/b.txt", "b")
+	write("c.txt", "c")
+	files, err := scanFiles(storage)
+	require.NoError(t, err)
+	full := &Manifest{Name: "full.zip", Files: files}
+	fullDir := filepath.Join(dir, "full")
+	require.NoError(t, Copy(storage, fullDir))
+
+	// the incremental snapshot has the changed and the new files only
+	write("b/b.txt", "b2")
+	write("d.txt", "d")
+	require.NoError(t, os.Remove(filepath.Join(storage, "c.txt")))
+	files, err = scanFiles(storage)
+	require.NoError(t, err)
+	inc := &Manifest{Name: "inc.zip", Parent: "full", Chain: 1, Files: files}
+	incDir := filepath.Join(dir, "inc")
+	require.NoError(t, stageChanged(storage, incDir, files, full))
+
+	staged, err := scanFiles(incDir)
+	require.NoError(t, err)
+	require.Len(t, staged, 2)
+	require.Contains(t, staged, "b/b.txt")
+	require.Contains(t, staged, "d.txt")
+
+	// the chain is broken without the parent
+	require.Error(t, checkFiles(incDir, inc))
+
+	// restore
+	require.NoError(t, fillMissing(fullDir, incDir, inc))
+	require.NoError(t, checkFiles(incDir, inc))
+	restored, err := scanFiles(incDir)
+	require.NoError(t, err)
+	require.Equal(t, inc.Files, restored)
+
+	// the manifest
+	file := filepath.Join(dir, ManifestName)
+	require.NoError(t, writeManifest(file, inc))
+	manifest, err := readManifest(file)
+	require.NoError(t, err)
+	require.Equal(t, inc, manifest)
+
+	manifest, err = readManifest(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	require.Nil(t, manifest)
+
+	files, err = scanFiles(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
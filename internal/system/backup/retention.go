@@ -0,0 +1,106 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"fmt"
+	"sort"
+
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// Retention is the grandfather-father-son policy, the newest snapshot of the day,
+// the week and the month is kept. The last Num snapshots are kept if the policy is not set.
+// The parents of the kept incremental snapshots are kept as well.
+type Retention struct {
+	Num     int64
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+// IsGFS ...
+func (r Retention) IsGFS() bool {
+	return r.Daily > 0 || r.Weekly > 0 || r.Monthly > 0
+}
+
+// Expired returns the snapshots to be removed
+func (r Retention) Expired(list m.Backups) (expired m.Backups) {
+	list = append(m.Backups{}, list...)
+	sort.Sort(list)
+
+	var keep = make(map[*m.Backup]struct{})
+	if !r.IsGFS() {
+		if r.Num <= 0 || int64(len(list)) <= r.Num {
+			return
+		}
+		for _, b := range list[:r.Num] {
+			keep[b] = struct{}{}
+		}
+	}
+
+	var period = func(count int, key func(b *m.Backup) string) {
+		var keys = make(map[string]struct{})
+		for _, b := range list {
+			if len(keys) >= count {
+				return
+			}
+			k := key(b)
+			if _, ok := keys[k]; ok {
+				continue
+			}
+			keys[k] = struct{}{}
+			keep[b] = struct{}{}
+		}
+	}
+	period(r.Daily, func(b *m.Backup) string {
+		return b.ModTime.Format("2006-01-02")
+	})
+	period(r.Weekly, func(b *m.Backup) string {
+		year, week := b.ModTime.ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	})
+	period(r.Monthly, func(b *m.Backup) string {
+		return b.ModTime.Format("2006-01")
+	})
+
+	// the incremental snapshot can not be restored without its parents
+	var keys = make(map[string]*m.Backup)
+	for _, b := range list {
+		keys[snapshotKey(b.Name)] = b
+	}
+	for _, b := range list {
+		if _, ok := keep[b]; !ok {
+			continue
+		}
+		for parent := snapshotParent(b.Name); parent != ""; parent = snapshotParent(keys[parent].Name) {
+			if _, ok := keys[parent]; !ok {
+				break
+			}
+			keep[keys[parent]] = struct{}{}
+		}
+	}
+
+	for _, b := range list {
+		if _, ok := keep[b]; !ok {
+			expired = append(expired, b)
+		}
+	}
+	return
+}
@@ -0,0 +1,106 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetention(t *testing.T) {
+
+	// a snapshot every day at noon for 100 days, 2026-10-17 is saturday
+	var list m.Backups
+	var now = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 100; i++ {
+		list = append(list, &m.Backup{
+			Name:    now.AddDate(0, 0, -i).Format("2006-01-02"),
+			ModTime: now.AddDate(0, 0, -i),
+		})
+	}
+	// the second snapshot of the last day
+	list = append(list, &m.Backup{
+		Name:    "2026-10-17-morning",
+		ModTime: now.Add(-time.Hour),
+	})
+
+	kept := func(r Retention) map[string]bool {
+		var names = make(map[string]bool)
+		for _, b := range list {
+			names[b.Name] = true
+		}
+		for _, b := range r.Expired(list) {
+			delete(names, b.Name)
+		}
+		return names
+	}
+
+	names := kept(Retention{Num: 5})
+	require.Len(t, names, 5)
+	require.True(t, names["2026-10-17"])
+	require.True(t, names["2026-10-17-morning"])
+
+	names = kept(Retention{Daily: 3})
+	require.Equal(t, map[string]bool{"2026-10-17": true, "2026-10-16": true, "2026-10-15": true}, names)
+
+	// the newest snapshot of the week, the weeks start on monday
+	names = kept(Retention{Weekly: 2})
+	require.Equal(t, map[string]bool{"2026-10-17": true, "2026-10-11": true}, names)
+
+	names = kept(Retention{Monthly: 3})
+	require.Equal(t, map[string]bool{"2026-10-17": true, "2026-09-30": true, "2026-08-31": true}, names)
+
+	names = kept(Retention{Daily: 7, Weekly: 4, Monthly: 12})
+	// 7 days, 2 more weeks (2026-10-11 is sunday), 3 more months
+	require.Len(t, names, 12)
+	require.False(t, names["2026-10-17-morning"])
+	require.True(t, names["2026-07-31"])
+
+	require.Empty(t, Retention{}.Expired(list))
+}
+
+func TestRetentionChain(t *testing.T) {
+
+	// a full snapshot on monday and the incremental snapshots every next day
+	var list m.Backups
+	var day = time.Date(2026, 10, 12, 3, 0, 0, 0, time.UTC)
+	var parent string
+	for i := 0; i < 5; i++ {
+		key := day.AddDate(0, 0, i).Format("2006-01-02T15:04:05.999")
+		list = append(list, &m.Backup{
+			Name:    snapshotName(key, parent) + EncryptedExt,
+			ModTime: day.AddDate(0, 0, i),
+		})
+		parent = key
+	}
+
+	expired := Retention{Num: 1}.Expired(list)
+	require.Empty(t, expired)
+
+	// the chain of the kept snapshot is cut at the third one
+	list[2].Name = snapshotName(snapshotKey(list[2].Name), "")
+	expired = Retention{Num: 1}.Expired(list)
+	require.Len(t, expired, 2)
+	require.Equal(t, list[1].Name, expired[0].Name)
+	require.Equal(t, list[0].Name, expired[1].Name)
+}
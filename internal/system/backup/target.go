@@ -0,0 +1,107 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+const (
+	// TargetLocal ...
+	TargetLocal = "local"
+	// TargetS3 ...
+	TargetS3 = "s3"
+	// TargetWebdav ...
+	TargetWebdav = "webdav"
+	// TargetSftp ...
+	TargetSftp = "sftp"
+)
+
+// BackupTarget is the off-site storage of the snapshots
+type BackupTarget interface {
+	Name() string
+	Upload(ctx context.Context, file, name string) error
+	Download(ctx context.Context, name, file string) error
+	List(ctx context.Context) (m.Backups, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// TargetConfig ...
+type TargetConfig struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Path the local directory, the remote directory or the prefix of the object
+	Path string `json:"path"`
+	// Endpoint the host:port of s3 and sftp, the url of webdav
+	Endpoint string `json:"endpoint"`
+	// s3
+	Bucket    string `json:"bucket"`
+	Region    string `json:"region"`
+	AccessKey string `json:"access_key"`
+	SecretKey string `json:"secret_key"`
+	UseSSL    bool   `json:"use_ssl"`
+	// webdav and sftp
+	User     string `json:"user"`
+	Password string `json:"password"`
+	// sftp, the host key in the authorized_keys format, required
+	PrivateKey string `json:"private_key"`
+	HostKey    string `json:"host_key"`
+}
+
+// NewTarget ...
+func NewTarget(cfg TargetConfig) (BackupTarget, error) {
+	if cfg.Name == "" {
+		cfg.Name = cfg.Type
+	}
+	switch cfg.Type {
+	case TargetLocal:
+		return NewLocalTarget(cfg)
+	case TargetS3:
+		return NewS3Target(cfg)
+	case TargetWebdav:
+		return NewWebdavTarget(cfg)
+	case TargetSftp:
+		return NewSftpTarget(cfg)
+	}
+	return nil, fmt.Errorf("unknown backup target \"%s\"", cfg.Type)
+}
+
+// NewTargets parses the json list of the targets
+func NewTargets(data string) (targets []BackupTarget, err error) {
+	if data == "" {
+		return
+	}
+	var configs []TargetConfig
+	if err = json.Unmarshal([]byte(data), &configs); err != nil {
+		err = fmt.Errorf("%s: bad backup targets", err.Error())
+		return
+	}
+	for _, cfg := range configs {
+		var target BackupTarget
+		if target, err = NewTarget(cfg); err != nil {
+			return
+		}
+		targets = append(targets, target)
+	}
+	return
+}
@@ -0,0 +1,97 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+var _ BackupTarget = (*LocalTarget)(nil)
+
+// LocalTarget copies the snapshots to the directory, e.g. the mounted network drive
+type LocalTarget struct {
+	name string
+	dir  string
+}
+
+// NewLocalTarget ...
+func NewLocalTarget(cfg TargetConfig) (*LocalTarget, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("target %s: path is required", cfg.Name)
+	}
+	return &LocalTarget{
+		name: cfg.Name,
+		dir:  cfg.Path,
+	}, nil
+}
+
+// Name ...
+func (t *LocalTarget) Name() string {
+	return t.name
+}
+
+// Upload ...
+func (t *LocalTarget) Upload(_ context.Context, file, name string) (err error) {
+	if err = os.MkdirAll(t.dir, 0755); err != nil {
+		return
+	}
+	return CopyFile(file, filepath.Join(t.dir, filepath.Base(name)))
+}
+
+// Download ...
+func (t *LocalTarget) Download(_ context.Context, name, file string) error {
+	return CopyFile(filepath.Join(t.dir, filepath.Base(name)), file)
+}
+
+// List ...
+func (t *LocalTarget) List(_ context.Context) (list m.Backups, err error) {
+	var entries []os.DirEntry
+	if entries, err = os.ReadDir(t.dir); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name()[0:1] == "." {
+			continue
+		}
+		var info os.FileInfo
+		if info, err = entry.Info(); err != nil {
+			return
+		}
+		list = append(list, &m.Backup{
+			Name:     info.Name(),
+			Size:     info.Size(),
+			FileMode: info.Mode(),
+			ModTime:  info.ModTime(),
+		})
+	}
+	return
+}
+
+// Delete ...
+func (t *LocalTarget) Delete(_ context.Context, name string) error {
+	return os.Remove(filepath.Join(t.dir, filepath.Base(name)))
+}
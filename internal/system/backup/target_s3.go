@@ -0,0 +1,126 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+var _ BackupTarget = (*S3Target)(nil)
+
+// S3Target uploads the snapshots to the s3 compatible storage
+type S3Target struct {
+	name   string
+	bucket string
+	prefix string
+	client *minio.Client
+}
+
+// NewS3Target ...
+func NewS3Target(cfg TargetConfig) (*S3Target, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("target %s: endpoint and bucket are required", cfg.Name)
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %w", cfg.Name, err)
+	}
+	var prefix = strings.Trim(cfg.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3Target{
+		name:   cfg.Name,
+		bucket: cfg.Bucket,
+		prefix: prefix,
+		client: client,
+	}, nil
+}
+
+// Name ...
+func (t *S3Target) Name() string {
+	return t.name
+}
+
+// Upload ...
+func (t *S3Target) Upload(ctx context.Context, file, name string) (err error) {
+	var exists bool
+	if exists, err = t.client.BucketExists(ctx, t.bucket); err != nil {
+		return
+	}
+	if !exists {
+		if err = t.client.MakeBucket(ctx, t.bucket, minio.MakeBucketOptions{}); err != nil {
+			return
+		}
+	}
+	_, err = t.client.FPutObject(ctx, t.bucket, t.key(name), file, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	return
+}
+
+// Download ...
+func (t *S3Target) Download(ctx context.Context, name, file string) error {
+	return t.client.FGetObject(ctx, t.bucket, t.key(name), file, minio.GetObjectOptions{})
+}
+
+// List ...
+func (t *S3Target) List(ctx context.Context) (list m.Backups, err error) {
+	for object := range t.client.ListObjects(ctx, t.bucket, minio.ListObjectsOptions{Prefix: t.prefix}) {
+		if object.Err != nil {
+			// the bucket is created by the first upload
+			if minio.ToErrorResponse(object.Err).Code != "NoSuchBucket" {
+				err = object.Err
+			}
+			return
+		}
+		name := strings.TrimPrefix(object.Key, t.prefix)
+		if name == "" || strings.Contains(name, "/") {
+			continue
+		}
+		list = append(list, &m.Backup{
+			Name:     name,
+			Size:     object.Size,
+			MimeType: object.ContentType,
+			ModTime:  object.LastModified,
+		})
+	}
+	return
+}
+
+// Delete ...
+func (t *S3Target) Delete(ctx context.Context, name string) error {
+	return t.client.RemoveObject(ctx, t.bucket, t.key(name), minio.RemoveObjectOptions{})
+}
+
+func (t *S3Target) key(name string) string {
+	return t.prefix + path.Base(name)
+}
@@ -0,0 +1,195 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+var _ BackupTarget = (*SftpTarget)(nil)
+
+// SftpTarget uploads the snapshots to the sftp server
+type SftpTarget struct {
+	name   string
+	addr   string
+	dir    string
+	config *ssh.ClientConfig
+}
+
+// NewSftpTarget ...
+func NewSftpTarget(cfg TargetConfig) (*SftpTarget, error) {
+	if cfg.Endpoint == "" || cfg.User == "" {
+		return nil, fmt.Errorf("target %s: endpoint and user are required", cfg.Name)
+	}
+
+	var auth []ssh.AuthMethod
+	if cfg.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(cfg.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("target %s: %w", cfg.Name, err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if cfg.Password != "" {
+		auth = append(auth, ssh.Password(cfg.Password))
+	}
+
+	if cfg.HostKey == "" {
+		return nil, fmt.Errorf("target %s: host_key is required", cfg.Name)
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(cfg.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %w", cfg.Name, err)
+	}
+
+	var dir = cfg.Path
+	if dir == "" {
+		dir = "."
+	}
+
+	return &SftpTarget{
+		name: cfg.Name,
+		addr: cfg.Endpoint,
+		dir:  dir,
+		config: &ssh.ClientConfig{
+			User:            cfg.User,
+			Auth:            auth,
+			HostKeyCallback: ssh.FixedHostKey(key),
+		},
+	}, nil
+}
+
+// Name ...
+func (t *SftpTarget) Name() string {
+	return t.name
+}
+
+// Upload ...
+func (t *SftpTarget) Upload(ctx context.Context, file, name string) error {
+	return t.session(ctx, func(client *sftp.Client) (err error) {
+		if err = client.MkdirAll(t.dir); err != nil {
+			return
+		}
+
+		var src *os.File
+		if src, err = os.Open(file); err != nil {
+			return
+		}
+		defer src.Close()
+
+		var dst *sftp.File
+		if dst, err = client.Create(path.Join(t.dir, path.Base(name))); err != nil {
+			return
+		}
+		defer dst.Close()
+
+		_, err = io.Copy(dst, src)
+		return
+	})
+}
+
+// Download ...
+func (t *SftpTarget) Download(ctx context.Context, name, file string) error {
+	return t.session(ctx, func(client *sftp.Client) (err error) {
+		var src *sftp.File
+		if src, err = client.Open(path.Join(t.dir, path.Base(name))); err != nil {
+			return
+		}
+		defer src.Close()
+
+		var dst *os.File
+		if dst, err = os.Create(file); err != nil {
+			return
+		}
+		defer dst.Close()
+
+		_, err = io.Copy(dst, src)
+		return
+	})
+}
+
+// List ...
+func (t *SftpTarget) List(ctx context.Context) (list m.Backups, err error) {
+	err = t.session(ctx, func(client *sftp.Client) error {
+		files, err := client.ReadDir(t.dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		for _, info := range files {
+			if info.IsDir() || info.Name()[0:1] == "." {
+				continue
+			}
+			list = append(list, &m.Backup{
+				Name:     info.Name(),
+				Size:     info.Size(),
+				FileMode: info.Mode(),
+				ModTime:  info.ModTime(),
+			})
+		}
+		return nil
+	})
+	return
+}
+
+// Delete ...
+func (t *SftpTarget) Delete(ctx context.Context, name string) error {
+	return t.session(ctx, func(client *sftp.Client) error {
+		return client.Remove(path.Join(t.dir, path.Base(name)))
+	})
+}
+
+// session opens the connection for the single operation
+func (t *SftpTarget) session(ctx context.Context, f func(client *sftp.Client) error) (err error) {
+	var conn *ssh.Client
+	if conn, err = ssh.Dial("tcp", t.addr, t.config); err != nil {
+		return fmt.Errorf("target %s: %w", t.name, err)
+	}
+	defer conn.Close()
+
+	var client *sftp.Client
+	if client, err = sftp.NewClient(conn); err != nil {
+		return fmt.Errorf("target %s: %w", t.name, err)
+	}
+	defer client.Close()
+
+	// the connection is closed if the context is cancelled
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	return f(client)
+}
@@ -0,0 +1,185 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/webdav"
+)
+
+func testTarget(t *testing.T, target BackupTarget) {
+	ctx := context.Background()
+
+	file := filepath.Join(t.TempDir(), "2026-10-17T00:00:00.zip")
+	require.NoError(t, os.WriteFile(file, []byte("snapshot"), 0644))
+
+	list, err := target.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	require.NoError(t, target.Upload(ctx, file, filepath.Base(file)))
+	list, err = target.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, filepath.Base(file), list[0].Name)
+	require.Equal(t, int64(8), list[0].Size)
+	require.False(t, list[0].ModTime.IsZero())
+
+	downloaded := filepath.Join(t.TempDir(), "downloaded.zip")
+	require.NoError(t, target.Download(ctx, list[0].Name, downloaded))
+	data, err := os.ReadFile(downloaded)
+	require.NoError(t, err)
+	require.Equal(t, "snapshot", string(data))
+
+	require.NoError(t, target.Delete(ctx, list[0].Name))
+	list, err = target.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+func TestTargets(t *testing.T) {
+
+	t.Run("local", func(t *testing.T) {
+		target, err := NewTarget(TargetConfig{Type: TargetLocal, Path: filepath.Join(t.TempDir(), "backups")})
+		require.NoError(t, err)
+		require.Equal(t, TargetLocal, target.Name())
+		testTarget(t, target)
+	})
+
+	t.Run("webdav", func(t *testing.T) {
+		server := httptest.NewServer(&webdav.Handler{
+			FileSystem: webdav.NewMemFS(),
+			LockSystem: webdav.NewMemLS(),
+		})
+		defer server.Close()
+
+		target, err := NewTarget(TargetConfig{Type: TargetWebdav, Name: "nas", Endpoint: server.URL, Path: "smart-home"})
+		require.NoError(t, err)
+		testTarget(t, target)
+	})
+
+	t.Run("sftp", func(t *testing.T) {
+		addr, hostKey := startSftpServer(t)
+
+		target, err := NewTarget(TargetConfig{
+			Type:     TargetSftp,
+			Endpoint: addr,
+			Path:     filepath.Join(t.TempDir(), "backups"),
+			User:     "smart-home",
+			Password: "secret",
+			HostKey:  hostKey,
+		})
+		require.NoError(t, err)
+		testTarget(t, target)
+
+		target, err = NewTarget(TargetConfig{Type: TargetSftp, Endpoint: addr, User: "smart-home", Password: "wrong", HostKey: hostKey})
+		require.NoError(t, err)
+		_, err = target.List(context.Background())
+		require.Error(t, err)
+
+		// the unknown host is never trusted
+		_, err = NewTarget(TargetConfig{Type: TargetSftp, Endpoint: addr, User: "smart-home", Password: "secret"})
+		require.Error(t, err)
+
+		_, otherHostKey := startSftpServer(t)
+		target, err = NewTarget(TargetConfig{Type: TargetSftp, Endpoint: addr, User: "smart-home", Password: "secret", HostKey: otherHostKey})
+		require.NoError(t, err)
+		_, err = target.List(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		targets, err := NewTargets(`[{"type":"local","path":"/tmp/backups"},{"type":"s3","endpoint":"127.0.0.1:9000","bucket":"backups"}]`)
+		require.NoError(t, err)
+		require.Len(t, targets, 2)
+
+		_, err = NewTargets(`[{"type":"ftp"}]`)
+		require.Error(t, err)
+		_, err = NewTargets(`[{"type":"s3"}]`)
+		require.Error(t, err)
+	})
+}
+
+// startSftpServer serves the local file system, the host key is returned in the authorized_keys format
+func startSftpServer(t *testing.T) (string, string) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "smart-home" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, os.ErrPermission
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, channels, requests, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(requests)
+				for newChannel := range channels {
+					channel, requests, err := newChannel.Accept()
+					if err != nil {
+						return
+					}
+					go func() {
+						for req := range requests {
+							_ = req.Reply(req.Type == "subsystem" && string(req.Payload[4:]) == "sftp", nil)
+						}
+					}()
+					server, err := sftp.NewServer(channel)
+					if err != nil {
+						return
+					}
+					_ = server.Serve()
+					_ = server.Close()
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+}
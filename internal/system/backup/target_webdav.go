@@ -0,0 +1,222 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package backup
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+var _ BackupTarget = (*WebdavTarget)(nil)
+
+// WebdavTarget uploads the snapshots to the webdav server
+type WebdavTarget struct {
+	name     string
+	url      *url.URL
+	user     string
+	password string
+	client   *http.Client
+}
+
+// NewWebdavTarget ...
+func NewWebdavTarget(cfg TargetConfig) (*WebdavTarget, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("target %s: endpoint is required", cfg.Name)
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %w", cfg.Name, err)
+	}
+	u.Path = path.Join("/", u.Path, cfg.Path) + "/"
+	return &WebdavTarget{
+		name:     cfg.Name,
+		url:      u,
+		user:     cfg.User,
+		password: cfg.Password,
+		client:   &http.Client{},
+	}, nil
+}
+
+// Name ...
+func (t *WebdavTarget) Name() string {
+	return t.name
+}
+
+// Upload ...
+func (t *WebdavTarget) Upload(ctx context.Context, file, name string) (err error) {
+	// the directory may already exist
+	if _, err = t.do(ctx, "MKCOL", t.url.String(), nil, nil); err != nil {
+		return
+	}
+
+	var f *os.File
+	if f, err = os.Open(file); err != nil {
+		return
+	}
+	defer f.Close()
+
+	var resp *http.Response
+	if resp, err = t.do(ctx, http.MethodPut, t.fileUrl(name), f, nil); err != nil {
+		return
+	}
+	return checkStatus(resp, http.StatusOK, http.StatusCreated, http.StatusNoContent)
+}
+
+// Download ...
+func (t *WebdavTarget) Download(ctx context.Context, name, file string) (err error) {
+	req, err := t.request(ctx, http.MethodGet, t.fileUrl(name), nil, nil)
+	if err != nil {
+		return
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("target %s: %s", t.name, resp.Status)
+	}
+
+	var f *os.File
+	if f, err = os.Create(file); err != nil {
+		return
+	}
+	defer f.Close()
+	_, err = io.Copy(f, resp.Body)
+	return
+}
+
+type webdavMultistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ContentLength string    `xml:"getcontentlength"`
+				LastModified  string    `xml:"getlastmodified"`
+				ContentType   string    `xml:"getcontenttype"`
+				ResourceType  *struct{} `xml:"resourcetype>collection"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// List ...
+func (t *WebdavTarget) List(ctx context.Context) (list m.Backups, err error) {
+	req, err := t.request(ctx, "PROPFIND", t.url.String(), strings.NewReader(`<?xml version="1.0" encoding="utf-8"?>
+<propfind xmlns="DAV:"><prop><getcontentlength/><getlastmodified/><getcontenttype/><resourcetype/></prop></propfind>`),
+		map[string]string{"Depth": "1", "Content-Type": "application/xml"})
+	if err != nil {
+		return
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		err = fmt.Errorf("target %s: %s", t.name, resp.Status)
+		return
+	}
+
+	var result webdavMultistatus
+	if err = xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return
+	}
+	for _, item := range result.Responses {
+		href, _ := url.PathUnescape(item.Href)
+		name := path.Base(strings.TrimSuffix(href, "/"))
+		if len(item.Propstat) == 0 || item.Propstat[0].Prop.ResourceType != nil || name == "" || name[0:1] == "." {
+			continue
+		}
+		prop := item.Propstat[0].Prop
+		backup := &m.Backup{
+			Name:     name,
+			MimeType: prop.ContentType,
+		}
+		backup.Size, _ = strconv.ParseInt(prop.ContentLength, 10, 64)
+		backup.ModTime, _ = time.Parse(http.TimeFormat, prop.LastModified)
+		list = append(list, backup)
+	}
+	return
+}
+
+// Delete ...
+func (t *WebdavTarget) Delete(ctx context.Context, name string) (err error) {
+	var resp *http.Response
+	if resp, err = t.do(ctx, http.MethodDelete, t.fileUrl(name), nil, nil); err != nil {
+		return
+	}
+	return checkStatus(resp, http.StatusOK, http.StatusNoContent)
+}
+
+func (t *WebdavTarget) fileUrl(name string) string {
+	return t.url.JoinPath(path.Base(name)).String()
+}
+
+func (t *WebdavTarget) request(ctx context.Context, method, u string, body io.Reader, headers map[string]string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if t.user != "" {
+		req.SetBasicAuth(t.user, t.password)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// do sends the request, the body of the response is discarded
+func (t *WebdavTarget) do(ctx context.Context, method, u string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := t.request(ctx, method, u, body, headers)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+func checkStatus(resp *http.Response, codes ...int) error {
+	for _, code := range codes {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s: %s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status)
+}
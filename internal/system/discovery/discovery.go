@@ -0,0 +1,446 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/e154/bus"
+	"go.uber.org/fx"
+
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	"github.com/e154/smart-home/pkg/logger"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+var (
+	log = logger.MustGetLogger("discovery")
+)
+
+const (
+	varInterval = "discoveryInterval"
+	varIgnored  = "discoveryIgnored"
+	varAccepted = "discoveryAccepted"
+	// the search is repeated with the interval in seconds, 0 disables the discovery
+	defaultInterval = 300
+	minInterval     = 30 * time.Second
+	// the device is removed when it is not seen for the ttlIntervals searches
+	ttlIntervals    = 3
+	describeTimeout = 5 * time.Second
+)
+
+var _ Discovery = (*discovery)(nil)
+
+type discovery struct {
+	adaptors   *adaptors.Adaptors
+	eventBus   bus.Bus
+	httpClient *http.Client
+	lock       sync.Mutex
+	devices    map[string]*Device
+	ignored    map[string]struct{}
+	// the entities added for the devices, by the device id
+	accepted map[string]common.EntityId
+	// the UPnP descriptions being fetched
+	pending map[string]struct{}
+	scan    chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewDiscovery ...
+func NewDiscovery(lc fx.Lifecycle,
+	adaptors *adaptors.Adaptors,
+	eventBus bus.Bus) Discovery {
+	d := newDiscovery(adaptors, eventBus)
+
+	lc.Append(fx.Hook{
+		OnStart: d.Start,
+		OnStop:  d.Shutdown,
+	})
+
+	return d
+}
+
+func newDiscovery(adaptors *adaptors.Adaptors, eventBus bus.Bus) *discovery {
+	return &discovery{
+		adaptors:   adaptors,
+		eventBus:   eventBus,
+		httpClient: &http.Client{Timeout: describeTimeout},
+		devices:    make(map[string]*Device),
+		ignored:    make(map[string]struct{}),
+		accepted:   make(map[string]common.EntityId),
+		pending:    make(map[string]struct{}),
+		scan:       make(chan struct{}, 1),
+	}
+}
+
+// Start ...
+func (d *discovery) Start(_ context.Context) error {
+
+	interval := time.Duration(d.getNumber(varInterval, defaultInterval)) * time.Second
+	if interval <= 0 {
+		log.Info("disabled")
+		return nil
+	}
+	if interval < minInterval {
+		interval = minInterval
+	}
+
+	d.loadIgnored()
+	d.loadAccepted()
+
+	var ctx context.Context
+	ctx, d.cancel = context.WithCancel(context.Background())
+
+	d.wg.Add(2)
+	go func() {
+		defer d.wg.Done()
+		d.ssdpListen(ctx)
+	}()
+	go func() {
+		defer d.wg.Done()
+		d.searchLoop(ctx, interval)
+	}()
+
+	d.eventBus.Publish("system/services/discovery", events.EventServiceStarted{Service: "Discovery"})
+	log.Info("started ...")
+
+	return nil
+}
+
+// Shutdown ...
+func (d *discovery) Shutdown(_ context.Context) error {
+
+	if d.cancel == nil {
+		return nil
+	}
+	d.cancel()
+	d.wg.Wait()
+	d.cancel = nil
+
+	d.eventBus.Publish("system/services/discovery", events.EventServiceStopped{Service: "Discovery"})
+	log.Info("shutdown ...")
+
+	return nil
+}
+
+// Scan ...
+func (d *discovery) Scan() {
+	select {
+	case d.scan <- struct{}{}:
+	default:
+	}
+}
+
+// Devices returns the copies of the found devices except the ignored ones
+func (d *discovery) Devices() []*Device {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var list = make([]*Device, 0, len(d.devices))
+	for id, device := range d.devices {
+		if _, ok := d.ignored[id]; ok {
+			continue
+		}
+		list = append(list, device.copy())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].FirstSeen.Before(list[j].FirstSeen) ||
+			list[i].FirstSeen.Equal(list[j].FirstSeen) && list[i].Id < list[j].Id
+	})
+	return list
+}
+
+// GetDevice ...
+func (d *discovery) GetDevice(id string) (*Device, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	device, ok := d.devices[id]
+	if !ok {
+		return nil, ErrDeviceNotFound
+	}
+	if _, ok = d.ignored[id]; ok {
+		return nil, ErrDeviceNotFound
+	}
+	return device.copy(), nil
+}
+
+// Ignore ...
+func (d *discovery) Ignore(ctx context.Context, id string) error {
+	d.lock.Lock()
+	if _, ok := d.devices[id]; !ok {
+		d.lock.Unlock()
+		return ErrDeviceNotFound
+	}
+	d.ignored[id] = struct{}{}
+	var ignored = make([]string, 0, len(d.ignored))
+	for id := range d.ignored {
+		ignored = append(ignored, id)
+	}
+	d.lock.Unlock()
+
+	sort.Strings(ignored)
+	variable := m.Variable{
+		Name:   varIgnored,
+		System: true,
+	}
+	if err := variable.SetObj(ignored); err != nil {
+		return err
+	}
+	return d.adaptors.Variable.CreateOrUpdate(ctx, variable)
+}
+
+// Accepted returns the entity added for the device
+func (d *discovery) Accepted(id string) (entityId common.EntityId, ok bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	entityId, ok = d.accepted[id]
+	return
+}
+
+// Accept ...
+func (d *discovery) Accept(ctx context.Context, id string, entityId common.EntityId) error {
+	d.lock.Lock()
+	d.accepted[id] = entityId
+	var accepted = make(map[string]common.EntityId, len(d.accepted))
+	for id, entityId := range d.accepted {
+		accepted[id] = entityId
+	}
+	d.lock.Unlock()
+
+	variable := m.Variable{
+		Name:   varAccepted,
+		System: true,
+	}
+	if err := variable.SetObj(accepted); err != nil {
+		return err
+	}
+	return d.adaptors.Variable.CreateOrUpdate(ctx, variable)
+}
+
+func (d *discovery) searchLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		d.search(ctx)
+		d.expire(time.Now().Add(-interval * ttlIntervals))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.scan:
+		}
+	}
+}
+
+// search runs the mDNS browsers and the SSDP search together and waits for them
+func (d *discovery) search(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, service := range mdnsServices {
+		wg.Add(1)
+		go func(service string) {
+			defer wg.Done()
+			d.mdnsBrowse(ctx, service)
+		}(service)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.ssdpSearch(ctx)
+	}()
+	wg.Wait()
+}
+
+// addService adds the service to the device of the address, the known service is replaced
+func (d *discovery) addService(address string, service *Service) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	key := serviceKey(service)
+	id := deviceId(address)
+	device, ok := d.devices[id]
+	if !ok {
+		device = &Device{
+			Id:        id,
+			Address:   address,
+			FirstSeen: service.LastSeen,
+		}
+		d.devices[id] = device
+		log.Infof("found device %s (%s %s)", address, service.Source, service.Type)
+	}
+	device.LastSeen = service.LastSeen
+
+	var replaced bool
+	for i, item := range device.Services {
+		if serviceKey(item) == key {
+			device.Services[i] = service
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		device.Services = append(device.Services, service)
+	}
+	classify(device)
+}
+
+// touchService updates the time of the known service, returns false if the service is unknown
+func (d *discovery) touchService(address, key string, now time.Time) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	device, ok := d.devices[deviceId(address)]
+	if !ok {
+		return false
+	}
+	for _, service := range device.Services {
+		if serviceKey(service) == key {
+			service.LastSeen = now
+			device.LastSeen = now
+			return true
+		}
+	}
+	return false
+}
+
+// removeService removes the service with the key from all devices, the device without services is removed
+func (d *discovery) removeService(key string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for id, device := range d.devices {
+		var services = device.Services[:0]
+		for _, service := range device.Services {
+			if serviceKey(service) != key {
+				services = append(services, service)
+			}
+		}
+		if len(services) == len(device.Services) {
+			continue
+		}
+		device.Services = services
+		if len(services) == 0 {
+			delete(d.devices, id)
+			continue
+		}
+		classify(device)
+	}
+}
+
+// expire removes the services not seen since the time
+func (d *discovery) expire(since time.Time) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for id, device := range d.devices {
+		var services = device.Services[:0]
+		for _, service := range device.Services {
+			if !service.LastSeen.Before(since) {
+				services = append(services, service)
+			}
+		}
+		if len(services) == len(device.Services) {
+			continue
+		}
+		device.Services = services
+		if len(services) == 0 {
+			log.Infof("device %s is gone", device.Address)
+			delete(d.devices, id)
+			continue
+		}
+		classify(device)
+	}
+}
+
+func (d *discovery) loadIgnored() {
+	variable, err := d.adaptors.Variable.GetByName(context.Background(), varIgnored)
+	if err != nil || variable.Value == "" {
+		return
+	}
+	var ignored []string
+	if err = variable.GetObj(&ignored); err != nil {
+		log.Warn(err.Error())
+		return
+	}
+	d.lock.Lock()
+	for _, id := range ignored {
+		d.ignored[id] = struct{}{}
+	}
+	d.lock.Unlock()
+}
+
+func (d *discovery) loadAccepted() {
+	variable, err := d.adaptors.Variable.GetByName(context.Background(), varAccepted)
+	if err != nil || variable.Value == "" {
+		return
+	}
+	var accepted map[string]common.EntityId
+	if err = variable.GetObj(&accepted); err != nil {
+		log.Warn(err.Error())
+		return
+	}
+	d.lock.Lock()
+	for id, entityId := range accepted {
+		d.accepted[id] = entityId
+	}
+	d.lock.Unlock()
+}
+
+func (d *discovery) getNumber(varName string, def int) int {
+	if variable, err := d.adaptors.Variable.GetByName(context.Background(), varName); err == nil {
+		var num int
+		if num, err = strconv.Atoi(variable.Value); err == nil {
+			return num
+		}
+	}
+	return def
+}
+
+func (device *Device) copy() *Device {
+	cp := *device
+	cp.Services = make([]*Service, 0, len(device.Services))
+	for _, service := range device.Services {
+		s := *service
+		s.Txt = make(map[string]string, len(service.Txt))
+		for k, v := range service.Txt {
+			s.Txt[k] = v
+		}
+		cp.Services = append(cp.Services, &s)
+	}
+	return &cp
+}
+
+func deviceId(address string) string {
+	return strings.NewReplacer(".", "_", ":", "_").Replace(address)
+}
+
+func serviceKey(service *Service) string {
+	return string(service.Source) + "/" + service.key
+}
@@ -0,0 +1,226 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+func TestClassify(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+
+	t.Run("shelly", func(t *testing.T) {
+		d := newDiscovery(nil, nil)
+		d.addService("192.168.1.20", &Service{Source: SourceMdns, Type: "_http._tcp", Name: "shellyplus1pm-a8032ab12345", Port: 80,
+			Txt: parseTxt([]string{"gen=2", "app=Plus1PM"}), LastSeen: now, key: "_http._tcp/shellyplus1pm-a8032ab12345"})
+
+		device, err := d.GetDevice("192_168_1_20")
+		require.NoError(t, err)
+		require.Equal(t, KindShelly, device.Kind)
+		require.Equal(t, "sensor", device.Plugin)
+		require.Equal(t, "shellyplus1pm-a8032ab12345", device.Name)
+		require.Equal(t, "Plus1PM", device.Model)
+		require.Equal(t, 80, device.Port)
+	})
+
+	t.Run("the specific rule wins", func(t *testing.T) {
+		d := newDiscovery(nil, nil)
+		d.addService("192.168.1.30", &Service{Source: SourceMdns, Type: "_http._tcp", Name: "kitchen", Port: 80, LastSeen: now, key: "_http._tcp/kitchen"})
+		d.addService("192.168.1.30", &Service{Source: SourceMdns, Type: "_esphomelib._tcp", Name: "kitchen", Port: 6053,
+			Txt: parseTxt([]string{"friendly_name=Kitchen Sensor", "board=esp32dev", "novalue"}), LastSeen: now, key: "_esphomelib._tcp/kitchen"})
+
+		device, err := d.GetDevice("192_168_1_30")
+		require.NoError(t, err)
+		require.Equal(t, KindEsphome, device.Kind)
+		require.Equal(t, "Kitchen Sensor", device.Name)
+		require.Equal(t, "esp32dev", device.Model)
+		require.Equal(t, 6053, device.Port)
+		require.Len(t, device.Services, 2)
+		require.Contains(t, device.Services[1].Txt, "novalue")
+	})
+
+	t.Run("printer", func(t *testing.T) {
+		d := newDiscovery(nil, nil)
+		d.addService("192.168.1.40", &Service{Source: SourceSsdp, Type: "urn:schemas-upnp-org:device:Printer:1", Name: "Office printer", Port: 80,
+			Txt: map[string]string{"manufacturer": "HP", "modelName": "LaserJet"}, LastSeen: now, key: "uuid:1"})
+
+		device, err := d.GetDevice("192_168_1_40")
+		require.NoError(t, err)
+		require.Equal(t, KindPrinter, device.Kind)
+		require.Equal(t, "HP", device.Manufacturer)
+		require.Equal(t, "LaserJet", device.Model)
+	})
+}
+
+func TestNewEntity(t *testing.T) {
+
+	device := &Device{
+		Id:      "192_168_1_50",
+		Name:    "Living room: lamp",
+		Kind:    KindHttp,
+		Plugin:  "sensor",
+		Address: "192.168.1.50",
+		Port:    8080,
+	}
+
+	entity := NewEntity(device)
+	require.Equal(t, "sensor.living_room_lamp", entity.Id.String())
+	require.Equal(t, "sensor", entity.PluginName)
+	require.Equal(t, "Living room: lamp", entity.Description)
+	require.True(t, entity.AutoLoad)
+	require.Equal(t, "192.168.1.50", entity.Settings["address"].String())
+	require.Equal(t, int64(8080), entity.Settings["port"].Int64())
+	require.Equal(t, "http://192.168.1.50:8080/", entity.Settings["url"].String())
+
+	device.Name = "---"
+	require.Equal(t, "192_168_1_50", EntityName(device))
+}
+
+func TestExpire(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+
+	d := newDiscovery(nil, nil)
+	d.addService("192.168.1.60", &Service{Source: SourceMdns, Type: "_googlecast._tcp", Name: "tv", LastSeen: now, key: "a"})
+	d.addService("192.168.1.60", &Service{Source: SourceMdns, Type: "_http._tcp", Name: "tv", LastSeen: now.Add(time.Minute), key: "b"})
+	d.addService("192.168.1.61", &Service{Source: SourceMdns, Type: "_http._tcp", Name: "nas", LastSeen: now, key: "c"})
+
+	d.expire(now.Add(time.Second))
+
+	devices := d.Devices()
+	require.Len(t, devices, 1)
+	require.Equal(t, KindHttp, devices[0].Kind)
+	require.Len(t, devices[0].Services, 1)
+
+	require.True(t, d.touchService("192.168.1.60", "mdns/b", now.Add(time.Hour)))
+	require.False(t, d.touchService("192.168.1.60", "mdns/a", now.Add(time.Hour)))
+
+	d.removeService("mdns/b")
+	require.Len(t, d.Devices(), 0)
+
+	_, err := d.GetDevice("192_168_1_60")
+	require.ErrorIs(t, err, ErrDeviceNotFound)
+}
+
+func TestSsdp(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <specVersion><major>1</major><minor>0</minor></specVersion>
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:MediaRenderer:1</deviceType>
+    <friendlyName>Living Room TV</friendlyName>
+    <manufacturer>ACME</manufacturer>
+    <modelName>TV-42</modelName>
+    <presentationURL>/web</presentationURL>
+  </device>
+</root>`))
+	}))
+	defer server.Close()
+
+	location := server.URL + "/description.xml"
+	uri, _ := url.Parse(location)
+
+	msg, err := parseSsdp([]byte(fmt.Sprintf("HTTP/1.1 200 OK\r\nCACHE-CONTROL: max-age=1800\r\n"+
+		"LOCATION: %s\r\nST: upnp:rootdevice\r\nUSN: uuid:4d696e69::upnp:rootdevice\r\nSERVER: Linux UPnP/1.0\r\n\r\n", location)))
+	require.NoError(t, err)
+	require.Equal(t, "HTTP/1.1 200 OK", msg.StartLine)
+	require.Equal(t, "uuid:4d696e69", msg.uuid())
+
+	d := newDiscovery(nil, nil)
+
+	// the description is only fetched from the address of the sender
+	require.Nil(t, d.ssdpService(context.Background(), "192.168.1.70", msg.uuid(), location, msg))
+
+	service := d.ssdpService(context.Background(), uri.Hostname(), msg.uuid(), location, msg)
+	require.NotNil(t, service)
+	require.Equal(t, "urn:schemas-upnp-org:device:MediaRenderer:1", service.Type)
+	require.Equal(t, "Living Room TV", service.Name)
+	require.Equal(t, "Linux UPnP/1.0", service.Txt["server"])
+
+	d.addService(uri.Hostname(), service)
+	device, err := d.GetDevice(deviceId(uri.Hostname()))
+	require.NoError(t, err)
+	require.Equal(t, KindUpnp, device.Kind)
+	require.Equal(t, "Living Room TV", device.Name)
+	require.Equal(t, "ACME", device.Manufacturer)
+	require.Equal(t, "TV-42", device.Model)
+	require.Equal(t, server.URL+"/web", NewEntity(device).Settings["url"].String())
+
+	// the byebye removes the device
+	d.removeService("ssdp/uuid:4d696e69")
+	require.Len(t, d.Devices(), 0)
+}
+
+type variableRepo struct {
+	adaptors.VariableRepo
+	variables map[string]m.Variable
+}
+
+func (r *variableRepo) CreateOrUpdate(_ context.Context, ver m.Variable) error {
+	r.variables[ver.Name] = ver
+	return nil
+}
+
+func (r *variableRepo) GetByName(_ context.Context, name string) (ver m.Variable, err error) {
+	var ok bool
+	if ver, ok = r.variables[name]; !ok {
+		err = apperr.ErrNotFound
+	}
+	return
+}
+
+func TestAccept(t *testing.T) {
+
+	repo := &variableRepo{variables: make(map[string]m.Variable)}
+	d := newDiscovery(&adaptors.Adaptors{Variable: repo}, nil)
+
+	_, ok := d.Accepted("shellyplus1pm-a8032ab12345")
+	require.False(t, ok)
+
+	require.NoError(t, d.Accept(context.Background(), "shellyplus1pm-a8032ab12345", "shelly.kitchen"))
+
+	entityId, ok := d.Accepted("shellyplus1pm-a8032ab12345")
+	require.True(t, ok)
+	require.Equal(t, common.EntityId("shelly.kitchen"), entityId)
+	require.True(t, repo.variables[varAccepted].System)
+
+	// the accepted devices survive the restart
+	d = newDiscovery(&adaptors.Adaptors{Variable: repo}, nil)
+	d.loadAccepted()
+
+	entityId, ok = d.Accepted("shellyplus1pm-a8032ab12345")
+	require.True(t, ok)
+	require.Equal(t, common.EntityId("shelly.kitchen"), entityId)
+}
@@ -0,0 +1,102 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package discovery
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grandcat/zeroconf"
+)
+
+const (
+	mdnsDomain    = "local."
+	browseTimeout = 10 * time.Second
+)
+
+// mdnsServices the browsed service types
+var mdnsServices = []string{
+	"_shelly._tcp",
+	"_esphomelib._tcp",
+	"_googlecast._tcp",
+	"_ipp._tcp",
+	"_ipps._tcp",
+	"_http._tcp",
+}
+
+// mdnsBrowse collects the instances of the service type during the browseTimeout
+func (d *discovery) mdnsBrowse(ctx context.Context, service string) {
+	resolver, err := zeroconf.NewResolver(zeroconf.SelectIPTraffic(zeroconf.IPv4))
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, browseTimeout)
+	defer cancel()
+
+	entries := make(chan *zeroconf.ServiceEntry, 32)
+	if err = resolver.Browse(ctx, service, mdnsDomain, entries); err != nil {
+		log.Warn(err.Error())
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry, ok := <-entries:
+			if !ok {
+				return
+			}
+			if entry == nil || len(entry.AddrIPv4) == 0 {
+				continue
+			}
+			address, service := mdnsService(entry, time.Now())
+			d.addService(address, service)
+		}
+	}
+}
+
+func mdnsService(entry *zeroconf.ServiceEntry, now time.Time) (string, *Service) {
+	name := strings.ReplaceAll(entry.Instance, `\`, "")
+	return entry.AddrIPv4[0].String(), &Service{
+		Source:   SourceMdns,
+		Type:     entry.Service,
+		Name:     name,
+		Host:     strings.TrimSuffix(entry.HostName, "."),
+		Port:     entry.Port,
+		Txt:      parseTxt(entry.Text),
+		LastSeen: now,
+		key:      entry.Service + "/" + name,
+	}
+}
+
+// parseTxt the "key=value" records, the record without the value is kept with the empty value
+func parseTxt(records []string) map[string]string {
+	txt := make(map[string]string, len(records))
+	for _, record := range records {
+		key, value, _ := strings.Cut(record, "=")
+		if key != "" {
+			txt[key] = value
+		}
+	}
+	return txt
+}
@@ -0,0 +1,221 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package discovery
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+const (
+	// the generic plugin, the device is controlled with the entity scripts
+	sensorPlugin = "sensor"
+)
+
+// rule selects the kind and the plugin of the device by one of its services, the first matching rule wins
+type rule struct {
+	kind   Kind
+	plugin string
+	match  func(service *Service) bool
+}
+
+var rules = []rule{
+	{
+		kind:   KindShelly,
+		plugin: sensorPlugin,
+		match: func(service *Service) bool {
+			return service.Type == "_shelly._tcp" ||
+				(service.Source == SourceMdns && strings.HasPrefix(strings.ToLower(service.Name), "shelly"))
+		},
+	},
+	{
+		kind:   KindEsphome,
+		plugin: sensorPlugin,
+		match: func(service *Service) bool {
+			return service.Type == "_esphomelib._tcp"
+		},
+	},
+	{
+		kind:   KindChromecast,
+		plugin: sensorPlugin,
+		match: func(service *Service) bool {
+			return service.Type == "_googlecast._tcp"
+		},
+	},
+	{
+		kind:   KindPrinter,
+		plugin: sensorPlugin,
+		match: func(service *Service) bool {
+			return service.Type == "_ipp._tcp" || service.Type == "_ipps._tcp" ||
+				strings.HasPrefix(service.Type, "urn:schemas-upnp-org:device:Printer:")
+		},
+	},
+	{
+		kind:   KindUpnp,
+		plugin: sensorPlugin,
+		match: func(service *Service) bool {
+			return service.Source == SourceSsdp
+		},
+	},
+	{
+		kind:   KindHttp,
+		plugin: sensorPlugin,
+		match: func(service *Service) bool {
+			return service.Type == "_http._tcp"
+		},
+	},
+}
+
+// classify fills the kind, the plugin and the description of the device from its services
+func classify(device *Device) {
+	var primary *Service
+LOOP:
+	for _, r := range rules {
+		for _, service := range device.Services {
+			if r.match(service) {
+				device.Kind = r.kind
+				device.Plugin = r.plugin
+				primary = service
+				break LOOP
+			}
+		}
+	}
+	if primary == nil {
+		device.Kind = ""
+		device.Plugin = ""
+		device.Port = 0
+		if len(device.Services) > 0 {
+			primary = device.Services[0]
+		}
+	} else {
+		device.Port = primary.Port
+	}
+
+	device.Name = txtValue(device.Services, "fn", "friendly_name", "friendlyName")
+	if device.Name == "" && primary != nil {
+		device.Name = primary.Name
+	}
+	if device.Name == "" {
+		device.Name = device.Address
+	}
+	device.Model = txtValue(device.Services, "md", "ty", "model", "modelName", "app", "board")
+	device.Manufacturer = txtValue(device.Services, "manufacturer", "usb_MFG")
+}
+
+func txtValue(services []*Service, keys ...string) string {
+	for _, key := range keys {
+		for _, service := range services {
+			if value := service.Txt[key]; value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// EntityName the name part of the proposed entity id
+func EntityName(device *Device) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(device.Name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "_")
+	if name == "" {
+		name = device.Id
+	}
+	return name
+}
+
+// NewEntity proposes the entity of the matching plugin, the connection is kept in the settings
+func NewEntity(device *Device) *m.Entity {
+	settings := m.Attributes{
+		"kind": {
+			Name:  "kind",
+			Type:  common.AttributeString,
+			Value: string(device.Kind),
+		},
+		"address": {
+			Name:  "address",
+			Type:  common.AttributeString,
+			Value: device.Address,
+		},
+		"port": {
+			Name:  "port",
+			Type:  common.AttributeInt,
+			Value: int64(device.Port),
+		},
+	}
+	if uri := deviceUrl(device); uri != "" {
+		settings["url"] = &m.Attribute{
+			Name:  "url",
+			Type:  common.AttributeString,
+			Value: uri,
+		}
+	}
+	if device.Model != "" {
+		settings["model"] = &m.Attribute{
+			Name:  "model",
+			Type:  common.AttributeString,
+			Value: device.Model,
+		}
+	}
+	if device.Manufacturer != "" {
+		settings["manufacturer"] = &m.Attribute{
+			Name:  "manufacturer",
+			Type:  common.AttributeString,
+			Value: device.Manufacturer,
+		}
+	}
+	return &m.Entity{
+		Id:          common.EntityId(fmt.Sprintf("%s.%s", device.Plugin, EntityName(device))),
+		PluginName:  device.Plugin,
+		Description: device.Name,
+		Settings:    settings,
+		AutoLoad:    true,
+	}
+}
+
+// deviceUrl the web interface of the device if it has one
+func deviceUrl(device *Device) string {
+	if value := txtValue(device.Services, "presentationURL"); value != "" {
+		if uri, err := url.Parse(value); err == nil && uri.IsAbs() {
+			return uri.String()
+		}
+	}
+	switch device.Kind {
+	case KindShelly, KindHttp:
+		if device.Port == 0 || device.Port == 80 {
+			return "http://" + device.Address + "/"
+		}
+		return "http://" + device.Address + ":" + strconv.Itoa(device.Port) + "/"
+	}
+	return ""
+}
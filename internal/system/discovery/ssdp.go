@@ -0,0 +1,280 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ssdpAddress    = "239.255.255.250:1900"
+	ssdpRootDevice = "upnp:rootdevice"
+	// the devices answer during the MX seconds
+	ssdpMx           = 3
+	ssdpReadTimeout  = (ssdpMx + 1) * time.Second
+	ssdpMaxDatagram  = 8192
+	descriptionLimit = 1 << 20
+)
+
+// ssdpMessage the NOTIFY request or the M-SEARCH response
+type ssdpMessage struct {
+	// StartLine "NOTIFY * HTTP/1.1" or "HTTP/1.1 200 OK"
+	StartLine string
+	Header    textproto.MIMEHeader
+}
+
+func parseSsdp(data []byte) (*ssdpMessage, error) {
+	reader := textproto.NewReader(bufio.NewReader(bytes.NewReader(data)))
+	line, err := reader.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+	header, err := reader.ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return &ssdpMessage{StartLine: line, Header: header}, nil
+}
+
+// uuid the device part of the unique service name, "uuid:xxx::upnp:rootdevice" -> "uuid:xxx"
+func (msg *ssdpMessage) uuid() string {
+	usn, _, _ := strings.Cut(msg.Header.Get("USN"), "::")
+	return usn
+}
+
+// ssdpListen receives the announcements of the devices
+func (d *discovery) ssdpListen(ctx context.Context) {
+	group, err := net.ResolveUDPAddr("udp4", ssdpAddress)
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		log.Warnf("ssdp listener: %s", err.Error())
+		return
+	}
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+
+	buf := make([]byte, ssdpMaxDatagram)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			default:
+				log.Warn(err.Error())
+			}
+			return
+		}
+		msg, err := parseSsdp(buf[:n])
+		if err != nil || !strings.HasPrefix(msg.StartLine, "NOTIFY ") {
+			continue
+		}
+		switch msg.Header.Get("NTS") {
+		case "ssdp:alive":
+			if msg.Header.Get("NT") == ssdpRootDevice {
+				d.ssdpHandle(ctx, src.IP.String(), msg)
+			}
+		case "ssdp:byebye":
+			if uuid := msg.uuid(); uuid != "" {
+				d.removeService(string(SourceSsdp) + "/" + uuid)
+			}
+		}
+	}
+}
+
+// ssdpSearch sends the M-SEARCH request and collects the answers
+func (d *discovery) ssdpSearch(ctx context.Context) {
+	group, err := net.ResolveUDPAddr("udp4", ssdpAddress)
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		log.Warn(err.Error())
+		return
+	}
+	defer conn.Close()
+
+	request := fmt.Sprintf("M-SEARCH * HTTP/1.1\r\nHOST: %s\r\nMAN: \"ssdp:discover\"\r\nMX: %d\r\nST: %s\r\n\r\n",
+		ssdpAddress, ssdpMx, ssdpRootDevice)
+	if _, err = conn.WriteToUDP([]byte(request), group); err != nil {
+		log.Warnf("ssdp search: %s", err.Error())
+		return
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(ssdpReadTimeout))
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.SetReadDeadline(time.Now())
+		case <-time.After(ssdpReadTimeout):
+		}
+	}()
+
+	buf := make([]byte, ssdpMaxDatagram)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		msg, err := parseSsdp(buf[:n])
+		if err != nil || !strings.HasPrefix(msg.StartLine, "HTTP/1.1 200") {
+			continue
+		}
+		d.ssdpHandle(ctx, src.IP.String(), msg)
+	}
+}
+
+// ssdpHandle updates the known device or fetches the description of the new one
+func (d *discovery) ssdpHandle(ctx context.Context, address string, msg *ssdpMessage) {
+	uuid := msg.uuid()
+	location := msg.Header.Get("LOCATION")
+	if uuid == "" || location == "" {
+		return
+	}
+	if d.touchService(address, string(SourceSsdp)+"/"+uuid, time.Now()) {
+		return
+	}
+
+	d.lock.Lock()
+	if _, ok := d.pending[uuid]; ok {
+		d.lock.Unlock()
+		return
+	}
+	d.pending[uuid] = struct{}{}
+	d.lock.Unlock()
+
+	go func() {
+		defer func() {
+			d.lock.Lock()
+			delete(d.pending, uuid)
+			d.lock.Unlock()
+		}()
+		service := d.ssdpService(ctx, address, uuid, location, msg)
+		if service != nil {
+			d.addService(address, service)
+		}
+	}()
+}
+
+// upnpDescription the root device of the UPnP description
+type upnpDescription struct {
+	Device struct {
+		DeviceType      string `xml:"deviceType"`
+		FriendlyName    string `xml:"friendlyName"`
+		Manufacturer    string `xml:"manufacturer"`
+		ModelName       string `xml:"modelName"`
+		ModelNumber     string `xml:"modelNumber"`
+		SerialNumber    string `xml:"serialNumber"`
+		PresentationURL string `xml:"presentationURL"`
+	} `xml:"device"`
+}
+
+// ssdpService describes the device by its UPnP description, the description is only
+// fetched from the address of the device
+func (d *discovery) ssdpService(ctx context.Context, address, uuid, location string, msg *ssdpMessage) *Service {
+	uri, err := url.Parse(location)
+	if err != nil || (uri.Scheme != "http" && uri.Scheme != "https") || uri.Hostname() != address {
+		return nil
+	}
+
+	service := &Service{
+		Source:   SourceSsdp,
+		Type:     msg.Header.Get("ST"),
+		Location: location,
+		Txt:      make(map[string]string),
+		LastSeen: time.Now(),
+		key:      uuid,
+	}
+	if service.Type == "" {
+		service.Type = msg.Header.Get("NT")
+	}
+	if server := msg.Header.Get("SERVER"); server != "" {
+		service.Txt["server"] = server
+	}
+	if port, err := strconv.Atoi(uri.Port()); err == nil {
+		service.Port = port
+	}
+
+	description, err := d.describe(ctx, location)
+	if err != nil {
+		log.Debugf("ssdp description %s: %s", location, err.Error())
+		return service
+	}
+
+	service.Type = description.Device.DeviceType
+	service.Name = description.Device.FriendlyName
+	// the relative presentation url is resolved against the location
+	if presentation, err := url.Parse(strings.TrimSpace(description.Device.PresentationURL)); err == nil && presentation.String() != "" {
+		description.Device.PresentationURL = uri.ResolveReference(presentation).String()
+	}
+	for key, value := range map[string]string{
+		"friendlyName":    description.Device.FriendlyName,
+		"manufacturer":    description.Device.Manufacturer,
+		"modelName":       description.Device.ModelName,
+		"modelNumber":     description.Device.ModelNumber,
+		"serialNumber":    description.Device.SerialNumber,
+		"presentationURL": description.Device.PresentationURL,
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			service.Txt[key] = value
+		}
+	}
+	return service
+}
+
+func (d *discovery) describe(ctx context.Context, location string) (*upnpDescription, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+
+	description := &upnpDescription{}
+	if err = xml.NewDecoder(io.LimitReader(resp.Body, descriptionLimit)).Decode(description); err != nil {
+		return nil, err
+	}
+	return description, nil
+}
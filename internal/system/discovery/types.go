@@ -0,0 +1,103 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package discovery
+
+import (
+	"context"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+)
+
+var (
+	ErrDeviceNotFound = apperr.ErrorWithCode("DISCOVERY_DEVICE_NOT_FOUND_ERROR", "discovered device is not found", apperr.ErrNotFound)
+	ErrEntityExists   = apperr.ErrorWithCode("DISCOVERY_ENTITY_EXISTS", "entity already exists", apperr.ErrInvalidRequest)
+)
+
+// Discovery browses the local network with mDNS and SSDP and keeps the list of the found devices
+type Discovery interface {
+	Start(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+	// Scan starts the search without waiting for the next interval
+	Scan()
+	Devices() []*Device
+	GetDevice(id string) (*Device, error)
+	// Ignore hides the device, the ignored devices are kept in the "discoveryIgnored" variable
+	Ignore(ctx context.Context, id string) error
+	// Accepted returns the entity added for the device
+	Accepted(id string) (common.EntityId, bool)
+	// Accept records the entity added for the device, the accepted devices are kept in the "discoveryAccepted" variable
+	Accept(ctx context.Context, id string, entityId common.EntityId) error
+}
+
+// Source ...
+type Source string
+
+const (
+	// SourceMdns ...
+	SourceMdns = Source("mdns")
+	// SourceSsdp ...
+	SourceSsdp = Source("ssdp")
+)
+
+// Kind of the device, it selects the plugin of the proposed entity
+type Kind string
+
+const (
+	KindShelly     = Kind("shelly")
+	KindEsphome    = Kind("esphome")
+	KindChromecast = Kind("chromecast")
+	KindPrinter    = Kind("printer")
+	KindUpnp       = Kind("upnp")
+	KindHttp       = Kind("http")
+)
+
+// Service is the single announcement of the device
+type Service struct {
+	Source Source `json:"source"`
+	// Type the mDNS service type (_http._tcp) or the UPnP device type
+	Type string `json:"type"`
+	// Name the mDNS instance or the UPnP friendly name
+	Name string `json:"name"`
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// Location the UPnP description url
+	Location string `json:"location,omitempty"`
+	// Txt the mDNS TXT records or the fields of the UPnP description
+	Txt      map[string]string `json:"txt"`
+	LastSeen time.Time         `json:"last_seen"`
+	// key identifies the service within the source
+	key string
+}
+
+// Device groups the services announced from the same address
+type Device struct {
+	Id           string     `json:"id"`
+	Name         string     `json:"name"`
+	Kind         Kind       `json:"kind"`
+	Plugin       string     `json:"plugin"`
+	Address      string     `json:"address"`
+	Port         int        `json:"port"`
+	Model        string     `json:"model"`
+	Manufacturer string     `json:"manufacturer"`
+	Services     []*Service `json:"services"`
+	FirstSeen    time.Time  `json:"first_seen"`
+	LastSeen     time.Time  `json:"last_seen"`
+}
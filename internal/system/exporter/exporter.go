@@ -0,0 +1,454 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/e154/bus"
+	"go.uber.org/atomic"
+	"go.uber.org/fx"
+
+	"github.com/e154/smart-home/internal/system/mqtt"
+	"github.com/e154/smart-home/internal/system/scripts"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	"github.com/e154/smart-home/pkg/logger"
+	m "github.com/e154/smart-home/pkg/models"
+	mqttTypes "github.com/e154/smart-home/pkg/mqtt"
+	"github.com/e154/smart-home/pkg/plugins"
+)
+
+var (
+	log = logger.MustGetLogger("exporter")
+)
+
+const (
+	// the entities are exported in one page
+	entitiesLimit = 999
+	// the minimal interval of the influx push
+	minPushInterval = 10 * time.Second
+	pushTimeout     = 30 * time.Second
+)
+
+var _ Exporter = (*exporter)(nil)
+
+type exporter struct {
+	adaptors      *adaptors.Adaptors
+	supervisor    plugins.Supervisor
+	eventBus      bus.Bus
+	mqtt          mqttTypes.MqttServ
+	httpClient    *http.Client
+	tasksFired    atomic.Uint64
+	triggersFired atomic.Uint64
+	cancel        context.CancelFunc
+}
+
+// NewExporter ...
+func NewExporter(lc fx.Lifecycle,
+	adaptors *adaptors.Adaptors,
+	supervisor plugins.Supervisor,
+	eventBus bus.Bus,
+	mqtt mqttTypes.MqttServ) Exporter {
+	e := &exporter{
+		adaptors:   adaptors,
+		supervisor: supervisor,
+		eventBus:   eventBus,
+		mqtt:       mqtt,
+		httpClient: &http.Client{Timeout: pushTimeout},
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: e.Start,
+		OnStop:  e.Shutdown,
+	})
+
+	return e
+}
+
+// Start ...
+func (e *exporter) Start(_ context.Context) error {
+
+	_ = e.eventBus.Subscribe("system/automation/tasks/+", e.eventHandler)
+	_ = e.eventBus.Subscribe("system/automation/triggers/+", e.eventHandler)
+
+	var ctx context.Context
+	ctx, e.cancel = context.WithCancel(context.Background())
+	go e.pushLoop(ctx)
+
+	e.eventBus.Publish("system/services/exporter", events.EventServiceStarted{Service: "Exporter"})
+	log.Info("started ...")
+
+	return nil
+}
+
+// Shutdown ...
+func (e *exporter) Shutdown(_ context.Context) error {
+
+	_ = e.eventBus.Unsubscribe("system/automation/tasks/+", e.eventHandler)
+	_ = e.eventBus.Unsubscribe("system/automation/triggers/+", e.eventHandler)
+
+	if e.cancel != nil {
+		e.cancel()
+	}
+
+	e.eventBus.Publish("system/services/exporter", events.EventServiceStopped{Service: "Exporter"})
+	log.Info("shutdown ...")
+
+	return nil
+}
+
+func (e *exporter) eventHandler(_ string, message interface{}) {
+	switch message.(type) {
+	case events.EventTaskCompleted:
+		e.tasksFired.Inc()
+	case events.EventTriggerCompleted:
+		e.triggersFired.Inc()
+	}
+}
+
+// Samples collects the entity states, attributes and metrics together with the internal counters
+func (e *exporter) Samples(ctx context.Context, filter Filter) (samples Samples, err error) {
+
+	if samples, err = e.entitySamples(ctx, filter); err != nil {
+		return
+	}
+
+	samples = append(samples, e.systemSamples(ctx)...)
+
+	return
+}
+
+func (e *exporter) entitySamples(ctx context.Context, filter Filter) (samples Samples, err error) {
+
+	var areaId *int64
+	if filter.Area != nil {
+		var area *m.Area
+		if area, err = e.adaptors.Area.GetByName(ctx, *filter.Area); err != nil {
+			return
+		}
+		areaId = common.Int64(area.Id)
+	}
+
+	var tags *[]string
+	if len(filter.Tags) > 0 {
+		tags = &filter.Tags
+	}
+
+	var list []*m.Entity
+	if list, _, err = e.adaptors.Entity.ListPlain(ctx, entitiesLimit, 0, "asc", "id", false, nil, nil, areaId, tags); err != nil {
+		return
+	}
+
+	var metricIds []int64
+	var metricLabels = make(map[int64][]Label)
+
+	for _, entity := range filter.Rules.Filter(list, common.EntityAccessRead) {
+		actor, err := e.supervisor.GetActorById(entity.Id)
+		if err != nil {
+			// the entity is not loaded
+			continue
+		}
+
+		var areaName string
+		if entity.Area != nil {
+			areaName = entity.Area.Name
+		}
+
+		labels := []Label{
+			{Name: "entity_id", Value: entity.Id.String()},
+			{Name: "plugin", Value: entity.PluginName},
+			{Name: "area", Value: areaName},
+		}
+
+		info := actor.Info()
+		if info.State != nil {
+			samples = append(samples, &Sample{
+				Name:   "smart_home_entity_state",
+				Help:   "The current state of the entity, always 1",
+				Type:   Gauge,
+				Labels: withLabel(labels, "state", info.State.Name),
+				Value:  1,
+			})
+		}
+
+		if value, ok := entityValue(info); ok {
+			samples = append(samples, &Sample{
+				Name:   "smart_home_entity_value",
+				Help:   "The numeric state of the entity",
+				Type:   Gauge,
+				Labels: labels,
+				Value:  value,
+			})
+		}
+
+		attributes := actor.Attributes()
+		names := make([]string, 0, len(attributes))
+		for name := range attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value, ok := attributeValue(attributes[name])
+			if !ok {
+				continue
+			}
+			samples = append(samples, &Sample{
+				Name:   "smart_home_entity_attribute",
+				Help:   "The numeric attribute of the entity, booleans are exported as 0 and 1",
+				Type:   Gauge,
+				Labels: withLabel(labels, "attribute", name),
+				Value:  value,
+			})
+		}
+
+		for _, metric := range actor.Metrics() {
+			if metric.Id == 0 {
+				continue
+			}
+			metricIds = append(metricIds, metric.Id)
+			metricLabels[metric.Id] = withLabel(labels, "metric", metric.Name)
+		}
+	}
+
+	var items []*m.MetricDataItem
+	if items, err = e.adaptors.MetricBucket.GetLast(ctx, metricIds); err != nil {
+		return
+	}
+
+	for _, item := range items {
+		names := make([]string, 0, len(item.Value))
+		for name := range item.Value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value, ok := common.ToFloat64(item.Value[name])
+			if !ok {
+				continue
+			}
+			samples = append(samples, &Sample{
+				Name:   "smart_home_entity_metric",
+				Help:   "The last value of the entity metric",
+				Type:   Gauge,
+				Labels: withLabel(metricLabels[item.MetricId], "item", name),
+				Value:  value,
+				Time:   common.Time(item.Time),
+			})
+		}
+	}
+
+	return
+}
+
+func (e *exporter) systemSamples(ctx context.Context) (samples Samples) {
+
+	samples = Samples{
+		{
+			Name:  "smart_home_scripts_run_total",
+			Help:  "The number of script runs since the start",
+			Type:  Counter,
+			Value: float64(scripts.RunCounter()),
+		},
+		{
+			Name:  "smart_home_tasks_fired_total",
+			Help:  "The number of automation tasks that passed the conditions since the start",
+			Type:  Counter,
+			Value: float64(e.tasksFired.Load()),
+		},
+		{
+			Name:  "smart_home_triggers_fired_total",
+			Help:  "The number of automation triggers fired since the start",
+			Type:  Counter,
+			Value: float64(e.triggersFired.Load()),
+		},
+	}
+
+	if stats, total, err := e.eventBus.Stat(ctx, entitiesLimit, 0, "", ""); err == nil {
+		samples = append(samples, &Sample{
+			Name:  "smart_home_bus_topics",
+			Help:  "The number of the event bus topics",
+			Type:  Gauge,
+			Value: float64(total),
+		})
+		for _, stat := range stats {
+			labels := []Label{{Name: "topic", Value: stat.Topic}}
+			samples = append(samples, &Sample{
+				Name:   "smart_home_bus_subscribers",
+				Help:   "The number of the topic subscribers",
+				Type:   Gauge,
+				Labels: labels,
+				Value:  float64(stat.Subscribers),
+			}, &Sample{
+				Name:   "smart_home_bus_messages_per_second",
+				Help:   "The rate of the messages published to the topic",
+				Type:   Gauge,
+				Labels: labels,
+				Value:  stat.Rps,
+			}, &Sample{
+				Name:   "smart_home_bus_handler_seconds",
+				Help:   "The average time of the message handling, a growing value means the subscribers queue up",
+				Type:   Gauge,
+				Labels: labels,
+				Value:  stat.Avg.Seconds(),
+			})
+		}
+	}
+
+	if admin, ok := e.mqtt.(mqtt.MqttServAdmin); ok {
+		if _, total, err := admin.Admin().GetClients(1, 0); err == nil {
+			samples = append(samples, &Sample{
+				Name:  "smart_home_mqtt_clients",
+				Help:  "The number of the clients connected to the mqtt server",
+				Type:  Gauge,
+				Value: float64(total),
+			})
+		}
+	}
+
+	return
+}
+
+// pushLoop sends the samples to InfluxDB, the settings are read before each push
+func (e *exporter) pushLoop(ctx context.Context) {
+	for {
+		interval := time.Duration(e.getNumber("exportInfluxInterval", 60)) * time.Second
+		if interval < minPushInterval {
+			interval = minPushInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		if err := e.push(ctx); err != nil {
+			log.Error(err.Error())
+		}
+	}
+}
+
+func (e *exporter) push(ctx context.Context) (err error) {
+
+	url := e.getString("exportInfluxUrl", "")
+	if url == "" || e.getNumber("exportInfluxInterval", 60) <= 0 {
+		return
+	}
+
+	filter := Filter{}
+	for _, tag := range strings.Split(e.getString("exportInfluxTags", ""), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	if area := strings.TrimSpace(e.getString("exportInfluxArea", "")); area != "" {
+		filter.Area = common.String(area)
+	}
+
+	var samples Samples
+	if samples, err = e.Samples(ctx, filter); err != nil {
+		return
+	}
+
+	body := &bytes.Buffer{}
+	if err = WriteInflux(body, samples, time.Now()); err != nil {
+		return
+	}
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, body); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if token := e.getString("exportInfluxToken", ""); token != "" {
+		req.Header.Set("Authorization", "Token "+token)
+	}
+
+	var resp *http.Response
+	if resp, err = e.httpClient.Do(req); err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		err = fmt.Errorf("influx push: unexpected status %s", resp.Status)
+	}
+
+	return
+}
+
+func (e *exporter) getNumber(varName string, def int) int {
+	if variable, err := e.adaptors.Variable.GetByName(context.Background(), varName); err == nil {
+		var num int
+		if num, err = strconv.Atoi(variable.Value); err == nil {
+			return num
+		}
+	}
+	return def
+}
+
+func (e *exporter) getString(varName, def string) string {
+	if variable, err := e.adaptors.Variable.GetByName(context.Background(), varName); err == nil {
+		return variable.Value
+	}
+	return def
+}
+
+func withLabel(labels []Label, name, value string) []Label {
+	result := make([]Label, len(labels), len(labels)+1)
+	copy(result, labels)
+	return append(result, Label{Name: name, Value: value})
+}
+
+// entityValue the value of the actor or the state name if it is a number
+func entityValue(info plugins.ActorInfo) (float64, bool) {
+	if value, ok := common.ToFloat64(info.Value); ok {
+		return value, true
+	}
+	if info.State != nil {
+		return common.ToFloat64(info.State.Name)
+	}
+	return 0, false
+}
+
+func attributeValue(attr *m.Attribute) (float64, bool) {
+	if attr == nil || attr.Value == nil {
+		return 0, false
+	}
+	switch attr.Type {
+	case common.AttributeInt, common.AttributeFloat:
+		return common.ToFloat64(attr.Value)
+	case common.AttributeBool:
+		if attr.Bool() {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
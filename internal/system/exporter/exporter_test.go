@@ -0,0 +1,101 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package exporter
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/e154/smart-home/pkg/events"
+)
+
+func testSamples() Samples {
+	tm := time.Unix(1700000000, 0)
+	return Samples{
+		{
+			Name:   "smart_home_entity_attribute",
+			Help:   "The numeric attribute",
+			Type:   Gauge,
+			Labels: []Label{{Name: "entity_id", Value: "sensor.t1"}, {Name: "area", Value: "living room"}, {Name: "attribute", Value: "temperature"}},
+			Value:  21.5,
+		},
+		{
+			Name:  "smart_home_scripts_run_total",
+			Help:  "The number of script runs",
+			Type:  Counter,
+			Value: 3,
+		},
+		{
+			Name:   "smart_home_entity_attribute",
+			Help:   "The numeric attribute",
+			Type:   Gauge,
+			Labels: []Label{{Name: "entity_id", Value: "sensor.t2"}, {Name: "area", Value: ""}, {Name: "attribute", Value: `say "hi"`}},
+			Value:  1,
+			Time:   &tm,
+		},
+		{
+			Name:  "smart_home_entity_value",
+			Type:  Gauge,
+			Value: math.NaN(),
+		},
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WritePrometheus(buf, testSamples()))
+
+	require.Equal(t, `# HELP smart_home_entity_attribute The numeric attribute
+# TYPE smart_home_entity_attribute gauge
+smart_home_entity_attribute{entity_id="sensor.t1",area="living room",attribute="temperature"} 21.5
+smart_home_entity_attribute{entity_id="sensor.t2",area="",attribute="say \"hi\""} 1
+# HELP smart_home_scripts_run_total The number of script runs
+# TYPE smart_home_scripts_run_total counter
+smart_home_scripts_run_total 3
+# TYPE smart_home_entity_value gauge
+smart_home_entity_value NaN
+`, buf.String())
+}
+
+func TestWriteInflux(t *testing.T) {
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteInflux(buf, testSamples(), time.Unix(1800000000, 0)))
+
+	require.Equal(t, `smart_home_entity_attribute,entity_id=sensor.t1,area=living\ room,attribute=temperature value=21.5 1800000000000000000
+smart_home_scripts_run_total value=3 1800000000000000000
+smart_home_entity_attribute,entity_id=sensor.t2,attribute=say\ "hi" value=1 1700000000000000000
+`, buf.String())
+}
+
+func TestCounters(t *testing.T) {
+
+	e := &exporter{}
+	e.eventHandler("system/automation/tasks/1", events.EventTaskCompleted{Id: 1})
+	e.eventHandler("system/automation/tasks/1", events.EventTaskCompleted{Id: 1})
+	e.eventHandler("system/automation/triggers/2", events.EventTriggerCompleted{Id: 2})
+	e.eventHandler("system/automation/tasks/1", events.EventTaskRunFinished{Id: 1})
+
+	require.Equal(t, uint64(2), e.tasksFired.Load())
+	require.Equal(t, uint64(1), e.triggersFired.Load())
+}
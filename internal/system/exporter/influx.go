@@ -0,0 +1,65 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package exporter
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	measurementReplacer = strings.NewReplacer(`,`, `\,`, ` `, `\ `, "\n", `\n`)
+	tagReplacer         = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`)
+)
+
+// WriteInflux writes the samples in the InfluxDB line protocol, one line per sample
+// with the metric name as the measurement, the labels as the tags and the single "value" field.
+// The samples without the time get the time of the export, NaN and infinite values are skipped.
+func WriteInflux(w io.Writer, samples Samples, now time.Time) (err error) {
+
+	buf := bufio.NewWriter(w)
+
+	for _, sample := range samples {
+		if math.IsNaN(sample.Value) || math.IsInf(sample.Value, 0) {
+			continue
+		}
+
+		buf.WriteString(measurementReplacer.Replace(sample.Name))
+		for _, label := range sample.Labels {
+			// the empty tag values are not allowed
+			if label.Value == "" {
+				continue
+			}
+			buf.WriteString("," + tagReplacer.Replace(label.Name) + "=" + tagReplacer.Replace(label.Value))
+		}
+
+		tm := now
+		if sample.Time != nil {
+			tm = *sample.Time
+		}
+
+		buf.WriteString(" value=" + strconv.FormatFloat(sample.Value, 'f', -1, 64))
+		buf.WriteString(" " + strconv.FormatInt(tm.UnixNano(), 10) + "\n")
+	}
+
+	return buf.Flush()
+}
@@ -0,0 +1,91 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package exporter
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ContentTypePrometheus ...
+const ContentTypePrometheus = "text/plain; version=0.0.4; charset=utf-8"
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus writes the samples in the Prometheus text exposition format.
+// The samples of one metric are written together under a single HELP and TYPE header,
+// the timestamps are omitted so that Prometheus uses the time of the scrape.
+func WritePrometheus(w io.Writer, samples Samples) (err error) {
+
+	buf := bufio.NewWriter(w)
+
+	for _, group := range samples.groups() {
+		first := group[0]
+		if first.Help != "" {
+			buf.WriteString("# HELP " + first.Name + " " + strings.ReplaceAll(first.Help, "\n", " ") + "\n")
+		}
+		buf.WriteString("# TYPE " + first.Name + " " + string(first.Type) + "\n")
+
+		for _, sample := range group {
+			buf.WriteString(sample.Name)
+			if len(sample.Labels) > 0 {
+				buf.WriteByte('{')
+				for i, label := range sample.Labels {
+					if i > 0 {
+						buf.WriteByte(',')
+					}
+					buf.WriteString(label.Name + `="` + labelValueReplacer.Replace(label.Value) + `"`)
+				}
+				buf.WriteByte('}')
+			}
+			buf.WriteString(" " + formatPrometheusValue(sample.Value) + "\n")
+		}
+	}
+
+	return buf.Flush()
+}
+
+// groups returns the samples grouped by the metric name in the order of the first appearance
+func (s Samples) groups() (groups []Samples) {
+	index := make(map[string]int)
+	for _, sample := range s {
+		i, ok := index[sample.Name]
+		if !ok {
+			i = len(groups)
+			index[sample.Name] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], sample)
+	}
+	return
+}
+
+func formatPrometheusValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,72 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package exporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
+)
+
+// Exporter ...
+type Exporter interface {
+	Start(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+	Samples(ctx context.Context, filter Filter) (Samples, error)
+}
+
+// Filter limits the exported entities, the internal counters are always exported
+type Filter struct {
+	// Tags the entity has at least one of the tags
+	Tags []string
+	// Area the name of the entity area
+	Area *string
+	// Rules the entity permissions of the user, nil if all entities are readable
+	Rules *entity_access.Rules
+}
+
+// SampleType ...
+type SampleType string
+
+const (
+	// Gauge ...
+	Gauge = SampleType("gauge")
+	// Counter ...
+	Counter = SampleType("counter")
+)
+
+// Label ...
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is the single value of the metric
+type Sample struct {
+	Name   string
+	Help   string
+	Type   SampleType
+	Labels []Label
+	Value  float64
+	// Time of the measurement, the time of the export if nil
+	Time *time.Time
+}
+
+// Samples ...
+type Samples []*Sample
@@ -177,12 +177,13 @@ func (c *Connection) serve(ctx context.Context) {
 				return
 			}
 			var user *m.User
-			user, err = c.pool.GetUser(accessToken)
+			var sessionId int64
+			user, sessionId, err = c.pool.GetUser(accessToken)
 			if err != nil {
 				log.Warn(apperr.ErrAccessDenied.Error())
 				return
 			}
-			c.stream.NewConnection(c.ws, user)
+			c.stream.NewConnection(c.ws, user, sessionId)
 			return
 		}
 
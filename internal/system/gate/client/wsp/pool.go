@@ -175,12 +175,13 @@ func (p *Pool) Size() (poolSize *PoolSize) {
 	return
 }
 
-func (p *Pool) GetUser(accessToken string) (user *m.User, err error) {
+func (p *Pool) GetUser(accessToken string) (user *m.User, sessionId int64, err error) {
 
 	claims, err := p.jwtManager.Verify(accessToken)
 	if err != nil {
 		return
 	}
+	sessionId = claims.SessionId
 
 	user, err = p.adaptors.User.GetById(context.Background(), claims.UserId)
 	if err != nil {
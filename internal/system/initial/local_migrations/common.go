@@ -58,6 +58,22 @@ func (c *Common) addPlugin(ctx context.Context, name string, enabled, system, ac
 	})
 }
 
+// addPluginTriggers sets the trigger flag of the plugin, the state of the installed plugin is kept
+func (c *Common) addPluginTriggers(ctx context.Context, name string, enabled, system, actor bool, version string) error {
+	plugin, err := c.adaptors.Plugin.GetByName(ctx, name)
+	if err != nil {
+		plugin = &models.Plugin{
+			Name:    name,
+			Version: version,
+			Enabled: enabled,
+			System:  system,
+			Actor:   actor,
+		}
+	}
+	plugin.Triggers = true
+	return c.adaptors.Plugin.CreateOrUpdate(ctx, plugin)
+}
+
 func (n *Common) removePlugin(ctx context.Context, name string) error {
 	return n.adaptors.Plugin.Delete(ctx, name)
 }
@@ -0,0 +1,52 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package local_migrations
+
+import (
+	"context"
+
+	. "github.com/e154/smart-home/internal/system/initial/assertions"
+	"github.com/e154/smart-home/pkg/adaptors"
+)
+
+type MigrationBackupTargets struct {
+	adaptors *adaptors.Adaptors
+}
+
+func NewMigrationBackupTargets(adaptors *adaptors.Adaptors) *MigrationBackupTargets {
+	return &MigrationBackupTargets{
+		adaptors: adaptors,
+	}
+}
+
+func (n *MigrationBackupTargets) Up(ctx context.Context) error {
+
+	err := AddVariableIfNotExist(n.adaptors, ctx, "backupKeepDaily", "0")
+	So(err, ShouldBeNil)
+	err = AddVariableIfNotExist(n.adaptors, ctx, "backupKeepWeekly", "0")
+	So(err, ShouldBeNil)
+	err = AddVariableIfNotExist(n.adaptors, ctx, "backupKeepMonthly", "0")
+	So(err, ShouldBeNil)
+	err = AddVariableIfNotExist(n.adaptors, ctx, "backupEncryptionKey", "")
+	So(err, ShouldBeNil)
+	err = AddVariableIfNotExist(n.adaptors, ctx, "backupTargets", "")
+	So(err, ShouldBeNil)
+
+	return nil
+}
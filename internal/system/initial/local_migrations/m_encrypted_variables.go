@@ -0,0 +1,61 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package local_migrations
+
+import (
+	"context"
+	"encoding/hex"
+
+	. "github.com/e154/smart-home/internal/system/initial/assertions"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common/encryptor"
+)
+
+type MigrationEncryptedVariables struct {
+	adaptors *adaptors.Adaptors
+}
+
+func NewMigrationEncryptedVariables(adaptors *adaptors.Adaptors) *MigrationEncryptedVariables {
+	return &MigrationEncryptedVariables{
+		adaptors: adaptors,
+	}
+}
+
+func (n *MigrationEncryptedVariables) Up(ctx context.Context) error {
+
+	// the migrations run before the server loads the key
+	key, err := n.adaptors.Variable.GetByName(ctx, "encryptor")
+	So(err, ShouldBeNil)
+	val, err := hex.DecodeString(key.Value)
+	So(err, ShouldBeNil)
+	encryptor.SetKey(val)
+
+	for _, name := range []string{"backupEncryptionKey", "backupTargets"} {
+		variable, err := n.adaptors.Variable.GetByName(ctx, name)
+		So(err, ShouldBeNil)
+		if variable.Encrypted {
+			continue
+		}
+		variable.Encrypted = true
+		err = n.adaptors.Variable.CreateOrUpdate(ctx, variable)
+		So(err, ShouldBeNil)
+	}
+
+	return nil
+}
@@ -75,7 +75,7 @@ func (n *MigrationJavascript) Up(ctx context.Context) error {
 		script.Source = strings.ReplaceAll(script.Source, "Action.callAction", "CallAction")
 
 		script.Source = strings.ReplaceAll(script.Source, "supervisor.callScene", "CallScene")
-		engine, err = scripts.NewEngine(script, nil, nil, nil)
+		engine, err = scripts.NewEngine(script, nil, nil, nil, scripts.Limits{}, nil)
 		So(err, ShouldBeNil)
 
 		err = engine.Compile()
@@ -55,7 +55,7 @@ func (n *MigrationJavascriptV2) Up(ctx context.Context) error {
 		script.Source = strings.ReplaceAll(script.Source, "CallAction", "EntityCallAction")
 		script.Source = strings.ReplaceAll(script.Source, "CallScene", "EntityCallScene")
 
-		engine, err = scripts.NewEngine(script, nil, nil, nil)
+		engine, err = scripts.NewEngine(script, nil, nil, nil, scripts.Limits{}, nil)
 		So(err, ShouldBeNil)
 
 		err = engine.Compile()
@@ -45,6 +45,8 @@ func (n *MigrationScheduler) Up(ctx context.Context) error {
 	So(err, ShouldBeNil)
 	err = AddVariableIfNotExist(n.adaptors, ctx, "clearRunHistoryDays", "60")
 	So(err, ShouldBeNil)
+	err = AddVariableIfNotExist(n.adaptors, ctx, "clearTaskRunsDays", "60")
+	So(err, ShouldBeNil)
 
 	return nil
 }
@@ -21,12 +21,16 @@ package jwt_manager
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/e154/smart-home/internal/common"
 	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
 	pkgCommon "github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
 	"github.com/e154/smart-home/pkg/logger"
 	"github.com/e154/smart-home/pkg/models"
 
@@ -40,25 +44,42 @@ var (
 	log = logger.MustGetLogger("jwt")
 )
 
+// sessionCheckInterval the state of the session is read from the database not more often
+const sessionCheckInterval = 30 * time.Second
+
+type sessionState struct {
+	userId    int64
+	active    bool
+	checkedAt time.Time
+}
+
 type jwtManager struct {
 	adaptors      *adaptors.Adaptors
 	tokenDuration time.Duration
 	hmacKey       []byte
 	eventBus      bus.Bus
+	sessionsMx    sync.Mutex
+	sessions      map[int64]sessionState
 }
 
 // NewJwtManager ...
 func NewJwtManager(lc fx.Lifecycle,
-	adaptors *adaptors.Adaptors) JwtManager {
+	adaptors *adaptors.Adaptors,
+	eventBus bus.Bus) JwtManager {
 
 	mananger := &jwtManager{
 		adaptors: adaptors,
+		eventBus: eventBus,
+		sessions: make(map[int64]sessionState),
 	}
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			return mananger.Start()
 		},
+		OnStop: func(ctx context.Context) error {
+			return mananger.Shutdown()
+		},
 	})
 
 	return mananger
@@ -66,10 +87,17 @@ func NewJwtManager(lc fx.Lifecycle,
 
 // Start ...
 func (j *jwtManager) Start() (err error) {
+	_ = j.eventBus.Subscribe("system/users/+", j.eventHandler)
 	_, err = j.getSecretKey()
 	return
 }
 
+// Shutdown ...
+func (j *jwtManager) Shutdown() (err error) {
+	_ = j.eventBus.Unsubscribe("system/users/+", j.eventHandler)
+	return
+}
+
 // Generate ...
 func (j *jwtManager) Generate(user *models.User, root bool, opts ...*time.Time) (accessToken string, err error) {
 
@@ -83,6 +111,24 @@ func (j *jwtManager) Generate(user *models.User, root bool, opts ...*time.Time)
 		exp = pkgCommon.Int64(now.AddDate(0, 1, 0).Unix())
 	}
 
+	accessToken, err = j.generate(user, root, now, *exp, 0)
+
+	return
+}
+
+// GenerateSession returns the short-lived access token of the session
+func (j *jwtManager) GenerateSession(user *models.User, sessionId int64, root bool) (accessToken string, expiresAt time.Time, err error) {
+
+	now := time.Now()
+	expiresAt = now.Add(AccessTokenTTL)
+
+	accessToken, err = j.generate(user, root, now, expiresAt.Unix(), sessionId)
+
+	return
+}
+
+func (j *jwtManager) generate(user *models.User, root bool, now time.Time, exp int64, sessionId int64) (accessToken string, err error) {
+
 	data := jwt.MapClaims{
 		"exp":  exp,
 		"iat":  now.Unix(),
@@ -93,6 +139,9 @@ func (j *jwtManager) Generate(user *models.User, root bool, opts ...*time.Time)
 		"r":    user.RoleName,
 		"root": root,
 	}
+	if sessionId != 0 {
+		data["s"] = sessionId
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, data)
 
@@ -128,14 +177,70 @@ func (j *jwtManager) Verify(accessToken string) (claims *UserClaims, err error)
 		}
 
 		claims = &UserClaims{}
-		err = common.Copy(claims, mapClaims, common.JsonEngine)
+		if err = common.Copy(claims, mapClaims, common.JsonEngine); err != nil {
+			return
+		}
 	} else {
 		return nil, ErrInvalidTokenClaims
 	}
 
+	// the tokens without the session are not revocable
+	if claims.SessionId != 0 && !j.sessionActive(claims.UserId, claims.SessionId) {
+		return nil, ErrSessionRevoked
+	}
+
 	return
 }
 
+func (j *jwtManager) sessionActive(userId, sessionId int64) bool {
+	now := time.Now()
+
+	j.sessionsMx.Lock()
+	state, ok := j.sessions[sessionId]
+	j.sessionsMx.Unlock()
+
+	if ok && now.Sub(state.checkedAt) < sessionCheckInterval {
+		return state.active
+	}
+
+	state = sessionState{userId: userId, checkedAt: now}
+	session, err := j.adaptors.UserSession.GetById(context.Background(), sessionId)
+	switch {
+	case err == nil:
+		state.active = session.UserId == userId && session.IsActive(now)
+	case errors.Is(err, apperr.ErrUserSessionNotFound):
+	default:
+		// the token can not be checked, the state is not cached
+		log.Error(err.Error())
+		return false
+	}
+
+	if state.active {
+		if err = j.adaptors.UserSession.Touch(context.Background(), sessionId, now); err != nil {
+			log.Error(err.Error())
+		}
+	}
+
+	j.sessionsMx.Lock()
+	j.sessions[sessionId] = state
+	j.sessionsMx.Unlock()
+
+	return state.active
+}
+
+func (j *jwtManager) eventHandler(_ string, message interface{}) {
+	switch v := message.(type) {
+	case events.EventUserSessionRevoked:
+		j.sessionsMx.Lock()
+		defer j.sessionsMx.Unlock()
+		for id, state := range j.sessions {
+			if id == v.SessionId || (v.SessionId == 0 && state.userId == v.UserId) {
+				delete(j.sessions, id)
+			}
+		}
+	}
+}
+
 func (j *jwtManager) getSecretKey() (hmacKey []byte, err error) {
 
 	if j.hmacKey != nil && len(j.hmacKey) > 0 {
@@ -32,11 +32,21 @@ var (
 	ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
 	// ErrInvalidTokenClaims ...
 	ErrInvalidTokenClaims = errors.New("invalid token claims")
+	// ErrSessionRevoked ...
+	ErrSessionRevoked = errors.New("session is revoked or expired")
+)
+
+const (
+	// AccessTokenTTL the lifetime of the access token of the session
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL the session expires if the refresh token is not used in this time
+	RefreshTokenTTL = 30 * 24 * time.Hour
 )
 
 // JwtManager ...
 type JwtManager interface {
 	Generate(*m.User, bool, ...*time.Time) (accessToken string, err error)
+	GenerateSession(user *m.User, sessionId int64, root bool) (accessToken string, expiresAt time.Time, err error)
 	Verify(string) (claims *UserClaims, err error)
 	SetHmacKey(hmacKey []byte)
 }
@@ -47,4 +57,6 @@ type UserClaims struct {
 	Username string `json:"n,omitempty"`
 	RoleName string `json:"r,omitempty"`
 	Root     bool   `json:"root,omitempty"`
+	// SessionId the token of the session is valid until the session is revoked
+	SessionId int64 `json:"s,omitempty"`
 }
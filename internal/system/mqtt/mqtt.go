@@ -28,6 +28,7 @@ import (
 
 	"github.com/e154/smart-home/internal/system/logging"
 	"github.com/e154/smart-home/internal/system/mqtt/admin"
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
 	"github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/events"
 	"github.com/e154/smart-home/pkg/logger"
@@ -67,6 +68,7 @@ type Mqtt struct {
 	scriptService scripts.ScriptService
 	eventBus      bus.Bus
 	zeroconf      *zeroconf.Server
+	entityAccess  entity_access.EntityAccessService
 }
 
 // NewMqtt ...
@@ -74,7 +76,8 @@ func NewMqtt(lc fx.Lifecycle,
 	cfg *Config,
 	authenticator mqttType.MqttAuthenticator,
 	scriptService scripts.ScriptService,
-	eventBus bus.Bus) (mqtt mqttType.MqttServ) {
+	eventBus bus.Bus,
+	entityAccess entity_access.EntityAccessService) (mqtt mqttType.MqttServ) {
 
 	mqtt = &Mqtt{
 		cfg:           cfg,
@@ -84,6 +87,7 @@ func NewMqtt(lc fx.Lifecycle,
 		admin:         admin.New(),
 		scriptService: scriptService,
 		eventBus:      eventBus,
+		entityAccess:  entityAccess,
 	}
 
 	lc.Append(fx.Hook{
@@ -153,6 +157,7 @@ func (m *Mqtt) Start() {
 		server.WithHook(server.Hooks{
 			OnBasicAuth:  m.onBasicAuth,
 			OnMsgArrived: m.onMsgArrived,
+			OnSubscribe:  m.onSubscribe,
 			OnConnected: func(ctx context.Context, client server.Client) {
 				m.eventBus.Publish("system/services/mqtt", events.EventMqttNewClient{
 					ClientId: client.ClientOptions().ClientID,
@@ -187,6 +192,16 @@ func (m *Mqtt) Start() {
 
 // OnMsgArrived ...
 func (m *Mqtt) onMsgArrived(ctx context.Context, client server.Client, msg *server.MsgArrivedRequest) (err error) {
+	// the user may publish to the topics of the entities with the control access only
+	if client != nil && msg.Message != nil {
+		username := client.ClientOptions().Username
+		if err = m.entityAccess.CheckTopic(ctx, username, msg.Message.Topic, common.EntityAccessControl); err != nil {
+			log.Warnf("publish %s, user '%s': %s", msg.Message.Topic, username, err.Error())
+			msg.Drop()
+			return codes.NewError(codes.NotAuthorized)
+		}
+	}
+
 	m.clientsLock.Lock()
 	defer m.clientsLock.Unlock()
 
@@ -197,6 +212,18 @@ func (m *Mqtt) onMsgArrived(ctx context.Context, client server.Client, msg *serv
 	return
 }
 
+// onSubscribe rejects the topic filters of the entities the user may not read
+func (m *Mqtt) onSubscribe(ctx context.Context, client server.Client, req *server.SubscribeRequest) (err error) {
+	username := client.ClientOptions().Username
+	for topic := range req.Subscriptions {
+		if err = m.entityAccess.CheckTopic(ctx, username, topic, common.EntityAccessRead); err != nil {
+			log.Warnf("subscribe %s, user '%s': %s", topic, username, err.Error())
+			req.Reject(topic, codes.NewError(codes.NotAuthorized))
+		}
+	}
+	return nil
+}
+
 // OnConnect ...
 func (m *Mqtt) onBasicAuth(ctx context.Context, client server.Client, req *server.ConnectRequest) (err error) {
 	log.Debugf("connect client version %v ...", client.Version())
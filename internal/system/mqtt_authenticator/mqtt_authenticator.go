@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/e154/smart-home/internal/system/cache"
+	"github.com/e154/smart-home/internal/system/jwt_manager"
 	"github.com/e154/smart-home/pkg/adaptors"
 	"github.com/e154/smart-home/pkg/apperr"
 	"github.com/e154/smart-home/pkg/logger"
@@ -41,19 +42,22 @@ var _ mqtt.MqttAuthenticator = (*Authenticator)(nil)
 
 // Authenticator ...
 type Authenticator struct {
-	adaptors  *adaptors.Adaptors
-	cache     cache.Cache
-	handlerMu *sync.Mutex
-	handlers  []reflect.Value
+	adaptors   *adaptors.Adaptors
+	jwtManager jwt_manager.JwtManager
+	cache      cache.Cache
+	handlerMu  *sync.Mutex
+	handlers   []reflect.Value
 }
 
 // NewAuthenticator ...
-func NewAuthenticator(adaptors *adaptors.Adaptors) mqtt.MqttAuthenticator {
+func NewAuthenticator(adaptors *adaptors.Adaptors,
+	jwtManager jwt_manager.JwtManager) mqtt.MqttAuthenticator {
 	bm, _ := cache.NewCache("memory", `{"interval":60}`)
 	return &Authenticator{
-		adaptors:  adaptors,
-		cache:     bm,
-		handlerMu: &sync.Mutex{},
+		adaptors:   adaptors,
+		jwtManager: jwtManager,
+		cache:      bm,
+		handlerMu:  &sync.Mutex{},
 	}
 }
 
@@ -65,9 +69,17 @@ func (a *Authenticator) Authenticate(login string, pass interface{}) (err error)
 		err = apperr.ErrBadLoginOrPassword
 	}
 
+	// the access token of the user as the password, it is not cached to check the revocation of the session
+	if claims, _ := a.jwtManager.Verify(password); claims != nil {
+		if claims.Username != login {
+			err = apperr.ErrBadLoginOrPassword
+		}
+		return
+	}
+
 	var value interface{}
 	if value, err = a.cache.Get(context.Background(), login); value != nil {
-		if password == pass.(string) {
+		if cached, ok := value.(string); ok && cached == password {
 			return
 		}
 	}
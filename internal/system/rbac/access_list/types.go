@@ -18,6 +18,10 @@
 
 package access_list
 
+import (
+	"strings"
+)
+
 // AccessItem ...
 type AccessItem struct {
 	Actions     []string `json:"actions"`
@@ -41,3 +45,42 @@ type AccessList map[string]AccessLevels
 func NewAccessList() AccessList {
 	return make(map[string]AccessLevels)
 }
+
+// Scoped returns the levels allowed by the scopes, the scope is "package" or "package:level".
+// The empty scopes allow everything.
+func (a AccessList) Scoped(scopes []string) AccessList {
+	if len(scopes) == 0 {
+		return a
+	}
+	result := NewAccessList()
+	for _, scope := range scopes {
+		pack, level, _ := strings.Cut(scope, ":")
+		levels, ok := a[pack]
+		if !ok {
+			continue
+		}
+		if result[pack] == nil {
+			result[pack] = NewAccessLevels()
+		}
+		for name, item := range levels {
+			if level == "" || level == name {
+				result[pack][name] = item
+			}
+		}
+	}
+	return result
+}
+
+// HasScope checks that the scope refers to the existing package or level
+func (a AccessList) HasScope(scope string) bool {
+	pack, level, _ := strings.Cut(scope, ":")
+	levels, ok := a[pack]
+	if !ok {
+		return false
+	}
+	if level == "" {
+		return true
+	}
+	_, ok = levels[level]
+	return ok
+}
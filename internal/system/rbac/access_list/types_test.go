@@ -0,0 +1,60 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package access_list
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessListScoped(t *testing.T) {
+
+	list := AccessList{
+		"user": AccessLevels{
+			"read":   AccessItem{Actions: []string{"/v1/users"}, Method: "get"},
+			"update": AccessItem{Actions: []string{"/v1/user/[0-9]+"}, Method: "put"},
+		},
+		"entity": AccessLevels{
+			"read": AccessItem{Actions: []string{"/v1/entities"}, Method: "get"},
+		},
+	}
+
+	// empty scopes
+	require.Equal(t, list, list.Scoped(nil))
+
+	// package
+	scoped := list.Scoped([]string{"entity"})
+	require.Len(t, scoped, 1)
+	require.Len(t, scoped["entity"], 1)
+
+	// level
+	scoped = list.Scoped([]string{"user:read", "foo:bar"})
+	require.Len(t, scoped, 1)
+	require.Contains(t, scoped["user"], "read")
+	require.NotContains(t, scoped["user"], "update")
+
+	// unknown scope
+	require.Empty(t, list.Scoped([]string{"foo"}))
+
+	require.True(t, list.HasScope("user"))
+	require.True(t, list.HasScope("user:update"))
+	require.False(t, list.HasScope("user:delete"))
+	require.False(t, list.HasScope("foo"))
+}
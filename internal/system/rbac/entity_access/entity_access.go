@@ -0,0 +1,241 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package entity_access
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/e154/smart-home/internal/system/cache"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	"github.com/e154/smart-home/pkg/logger"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/e154/bus"
+	"go.uber.org/fx"
+)
+
+var (
+	log = logger.MustGetLogger("entity_access")
+)
+
+// AttrSubscribeTopic the entity setting with the mqtt topic of the entity
+const AttrSubscribeTopic = "subscribe_topic"
+
+// EntityAccessService checks the entity permissions of the user.
+// The nil user is the system itself (automation, scripts, plugins) and has access to everything.
+type EntityAccessService interface {
+	// Rules returns nil if the user has access to all entities
+	Rules(ctx context.Context, user *m.User) *Rules
+	Check(ctx context.Context, user *m.User, entityId common.EntityId, level common.EntityAccessLevel) error
+	CheckEntity(ctx context.Context, user *m.User, entity *m.Entity, level common.EntityAccessLevel) error
+	// CheckTopic checks the entities bound to the mqtt topic, the login may be not a user
+	CheckTopic(ctx context.Context, login, topic string, level common.EntityAccessLevel) error
+}
+
+type entityTopic struct {
+	entity *m.Entity
+	topic  string
+}
+
+// loginUser the user of the mqtt login, nil for the device credentials
+type loginUser struct {
+	user *m.User
+}
+
+// entityAccessService ...
+type entityAccessService struct {
+	adaptors *adaptors.Adaptors
+	eventBus bus.Bus
+	logins   cache.Cache
+	mx       sync.Mutex
+	roles    map[string]*Rules
+	topics   []entityTopic
+	loaded   bool
+}
+
+// NewEntityAccessService ...
+func NewEntityAccessService(lc fx.Lifecycle,
+	adaptors *adaptors.Adaptors,
+	eventBus bus.Bus) EntityAccessService {
+	logins, _ := cache.NewCache("memory", `{"interval":60}`)
+	service := &entityAccessService{
+		adaptors: adaptors,
+		eventBus: eventBus,
+		logins:   logins,
+		roles:    make(map[string]*Rules),
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) (err error) {
+			_ = service.eventBus.Subscribe("system/models/roles/+", service.eventHandler)
+			_ = service.eventBus.Subscribe("system/models/entities/+", service.eventHandler)
+			return nil
+		},
+		OnStop: func(ctx context.Context) (err error) {
+			_ = service.eventBus.Unsubscribe("system/models/roles/+", service.eventHandler)
+			_ = service.eventBus.Unsubscribe("system/models/entities/+", service.eventHandler)
+			return nil
+		},
+	})
+
+	return service
+}
+
+// Rules ...
+func (a *entityAccessService) Rules(ctx context.Context, user *m.User) *Rules {
+	if user == nil || user.Id == 1 {
+		return nil
+	}
+	roleName := user.RoleName
+	if user.Role != nil {
+		roleName = user.Role.Name
+	}
+	if roleName == "admin" {
+		return nil
+	}
+
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	if rules, ok := a.roles[roleName]; ok {
+		return rules
+	}
+
+	list, err := a.adaptors.EntityPermission.GetAllPermissions(ctx, roleName)
+	if err != nil {
+		// deny everything until the permissions can be read
+		log.Error(err.Error())
+		return NewRules(nil)
+	}
+
+	var rules *Rules
+	if len(list) > 0 {
+		rules = NewRules(list)
+	}
+	a.roles[roleName] = rules
+
+	return rules
+}
+
+// Check ...
+func (a *entityAccessService) Check(ctx context.Context, user *m.User, entityId common.EntityId, level common.EntityAccessLevel) error {
+	rules := a.Rules(ctx, user)
+	if rules == nil {
+		return nil
+	}
+	entity, err := a.adaptors.Entity.GetById(ctx, entityId)
+	if err != nil {
+		return err
+	}
+	return a.check(rules, entity, level)
+}
+
+// CheckEntity ...
+func (a *entityAccessService) CheckEntity(ctx context.Context, user *m.User, entity *m.Entity, level common.EntityAccessLevel) error {
+	return a.check(a.Rules(ctx, user), entity, level)
+}
+
+// CheckTopic ...
+func (a *entityAccessService) CheckTopic(ctx context.Context, login, topic string, level common.EntityAccessLevel) error {
+	user := a.userByLogin(ctx, login)
+	rules := a.Rules(ctx, user)
+	if rules == nil {
+		return nil
+	}
+	for _, item := range a.entityTopics(ctx) {
+		if !topicsOverlap(item.topic, topic) {
+			continue
+		}
+		if err := a.check(rules, item.entity, level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *entityAccessService) check(rules *Rules, entity *m.Entity, level common.EntityAccessLevel) error {
+	if rules.Allowed(entity, level) {
+		return nil
+	}
+	return fmt.Errorf("%s %s: %w", level, entity.Id, apperr.ErrEntityAccessForbidden)
+}
+
+func (a *entityAccessService) userByLogin(ctx context.Context, login string) *m.User {
+	if value, _ := a.logins.Get(ctx, login); value != nil {
+		if item, ok := value.(*loginUser); ok {
+			return item.user
+		}
+	}
+	user, err := a.adaptors.User.GetByNickname(ctx, login)
+	if err != nil {
+		if user, err = a.adaptors.User.GetByEmail(ctx, login); err != nil {
+			// the device credentials, not a user
+			user = nil
+		}
+	}
+	_ = a.logins.Put(ctx, login, &loginUser{user: user}, 60*time.Second)
+	return user
+}
+
+func (a *entityAccessService) entityTopics(ctx context.Context) []entityTopic {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	if a.loaded {
+		return a.topics
+	}
+
+	list, _, err := a.adaptors.Entity.List(ctx, 999, 0, "desc", "id", false, nil, nil, nil)
+	if err != nil {
+		log.Error(err.Error())
+		return nil
+	}
+
+	a.topics = a.topics[:0]
+	for _, entity := range list {
+		if attr, ok := entity.Settings[AttrSubscribeTopic]; ok && attr.String() != "" {
+			a.topics = append(a.topics, entityTopic{entity: entity, topic: attr.String()})
+		}
+	}
+	a.loaded = true
+
+	return a.topics
+}
+
+func (a *entityAccessService) eventHandler(_ string, message interface{}) {
+	switch message.(type) {
+	case events.EventUpdatedRoleModel:
+		// the children of the role are affected too
+		a.mx.Lock()
+		a.roles = make(map[string]*Rules)
+		a.mx.Unlock()
+	case events.EventCreatedEntityModel,
+		events.EventUpdatedEntityModel,
+		events.CommandUnloadEntity:
+		a.mx.Lock()
+		a.loaded = false
+		a.mx.Unlock()
+	}
+}
@@ -0,0 +1,142 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package entity_access
+
+import (
+	"strings"
+
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// Rules are the entity permissions of the role.
+// The permission of the entity overrides the area and tag permissions, otherwise the highest of them applies.
+// Nil rules allow everything.
+type Rules struct {
+	entities map[common.EntityId]common.EntityAccessLevel
+	areas    map[int64]common.EntityAccessLevel
+	tags     map[string]common.EntityAccessLevel
+}
+
+// NewRules the list is ordered from the role to its parents, the first permission of the target wins
+func NewRules(list []*m.EntityPermission) *Rules {
+	r := &Rules{
+		entities: make(map[common.EntityId]common.EntityAccessLevel),
+		areas:    make(map[int64]common.EntityAccessLevel),
+		tags:     make(map[string]common.EntityAccessLevel),
+	}
+	for _, permission := range list {
+		switch {
+		case permission.EntityId != nil:
+			if _, ok := r.entities[*permission.EntityId]; !ok {
+				r.entities[*permission.EntityId] = permission.Level
+			}
+		case permission.AreaId != nil:
+			if _, ok := r.areas[*permission.AreaId]; !ok {
+				r.areas[*permission.AreaId] = permission.Level
+			}
+		case permission.Tag != nil:
+			if _, ok := r.tags[*permission.Tag]; !ok {
+				r.tags[*permission.Tag] = permission.Level
+			}
+		}
+	}
+	return r
+}
+
+// Level ...
+func (r *Rules) Level(entityId common.EntityId, areaId *int64, tags []string) (level common.EntityAccessLevel) {
+	if r == nil {
+		return common.EntityAccessEdit
+	}
+
+	if level, ok := r.entities[entityId]; ok {
+		return level
+	}
+
+	level = common.EntityAccessNone
+	if areaId != nil {
+		if l, ok := r.areas[*areaId]; ok && l.Includes(level) {
+			level = l
+		}
+	}
+	for _, tag := range tags {
+		if l, ok := r.tags[tag]; ok && l.Includes(level) {
+			level = l
+		}
+	}
+	return
+}
+
+// Allowed ...
+func (r *Rules) Allowed(entity *m.Entity, level common.EntityAccessLevel) bool {
+	if r == nil {
+		return true
+	}
+
+	areaId := entity.AreaId
+	if areaId == nil && entity.Area != nil {
+		areaId = &entity.Area.Id
+	}
+	tags := make([]string, 0, len(entity.Tags))
+	for _, tag := range entity.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return r.Level(entity.Id, areaId, tags).Includes(level)
+}
+
+// Filter returns the entities with the level of access
+func (r *Rules) Filter(list []*m.Entity, level common.EntityAccessLevel) []*m.Entity {
+	if r == nil {
+		return list
+	}
+	result := make([]*m.Entity, 0, len(list))
+	for _, entity := range list {
+		if r.Allowed(entity, level) {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
+
+// topicsOverlap checks whether the mqtt topic filters may match the same topic
+func topicsOverlap(a, b string) bool {
+	left, right := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		if left[i] == "#" || right[i] == "#" {
+			return true
+		}
+		if left[i] == "+" || right[i] == "+" || left[i] == right[i] {
+			continue
+		}
+		return false
+	}
+	if len(left) == len(right) {
+		return true
+	}
+	// "a/#" matches "a" too
+	if len(left) == len(right)+1 {
+		return left[len(right)] == "#"
+	}
+	if len(right) == len(left)+1 {
+		return right[len(left)] == "#"
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package entity_access
+
+import (
+	"testing"
+
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules(t *testing.T) {
+
+	kitchen := int64(1)
+	hall := int64(2)
+	light := "light"
+	lampId := common.EntityId("zigbee2mqtt.lamp")
+	doorId := common.EntityId("sensor.door")
+
+	// the list is ordered from the role to its parents
+	rules := NewRules([]*m.EntityPermission{
+		{EntityId: &doorId, Level: common.EntityAccessNone},
+		{AreaId: &hall, Level: common.EntityAccessControl},
+		{Tag: &light, Level: common.EntityAccessRead},
+		{AreaId: &kitchen, Level: common.EntityAccessRead},
+		{Tag: &light, Level: common.EntityAccessEdit},
+	})
+
+	// the entity permission overrides the area
+	require.Equal(t, common.EntityAccessNone, rules.Level(doorId, &hall, nil))
+	// the highest of the area and the tags
+	require.Equal(t, common.EntityAccessControl, rules.Level(lampId, &hall, []string{light}))
+	require.Equal(t, common.EntityAccessRead, rules.Level(lampId, &kitchen, []string{light}))
+	// nothing matches
+	require.Equal(t, common.EntityAccessNone, rules.Level("sensor.temp", nil, []string{"climate"}))
+
+	lamp := &m.Entity{Id: lampId, AreaId: &kitchen, Tags: []*m.Tag{{Name: light}}}
+	door := &m.Entity{Id: doorId, Area: &m.Area{Id: hall}}
+	require.True(t, rules.Allowed(lamp, common.EntityAccessRead))
+	require.False(t, rules.Allowed(lamp, common.EntityAccessControl))
+	require.False(t, rules.Allowed(door, common.EntityAccessRead))
+	require.Equal(t, []*m.Entity{lamp}, rules.Filter([]*m.Entity{lamp, door}, common.EntityAccessRead))
+
+	// nil rules allow everything
+	var empty *Rules
+	require.True(t, empty.Allowed(door, common.EntityAccessEdit))
+	require.Len(t, empty.Filter([]*m.Entity{lamp, door}, common.EntityAccessEdit), 2)
+}
+
+func TestTopicsOverlap(t *testing.T) {
+	require.True(t, topicsOverlap("zigbee2mqtt/lamp", "zigbee2mqtt/lamp"))
+	require.True(t, topicsOverlap("zigbee2mqtt/lamp", "zigbee2mqtt/+"))
+	require.True(t, topicsOverlap("zigbee2mqtt/lamp/set", "#"))
+	require.True(t, topicsOverlap("zigbee2mqtt/#", "zigbee2mqtt"))
+	require.True(t, topicsOverlap("+/lamp", "zigbee2mqtt/+"))
+	require.False(t, topicsOverlap("zigbee2mqtt/lamp", "zigbee2mqtt/door"))
+	require.False(t, topicsOverlap("zigbee2mqtt/lamp", "zigbee2mqtt/lamp/set"))
+	require.False(t, topicsOverlap("zigbee2mqtt/+", "owntracks/phone"))
+}
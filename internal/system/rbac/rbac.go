@@ -23,7 +23,10 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/e154/smart-home/internal/common"
 	"github.com/e154/smart-home/internal/system/jwt_manager"
 	"github.com/e154/smart-home/internal/system/rbac/access_list"
 	"github.com/e154/smart-home/pkg/adaptors"
@@ -39,6 +42,8 @@ var (
 
 var (
 	log = logger.MustGetLogger("rbac")
+
+	totpAllowedPath = regexp.MustCompile(`^/v1/(user/[0-9]+/totp(/.*)?|signout)$`)
 )
 
 type Authenticator struct {
@@ -66,6 +71,10 @@ func (a *Authenticator) AuthREST(ctx context.Context, accessToken string, reques
 		return nil, false, apperr.ErrUnauthorized
 	}
 
+	if strings.HasPrefix(accessToken, m.ApiTokenPrefix) {
+		return a.authApiToken(ctx, accessToken, requestURI, method)
+	}
+
 	claims, err := a.jwtManager.Verify(accessToken)
 	if err != nil {
 		return nil, false, apperr.ErrUnauthorized
@@ -77,6 +86,9 @@ func (a *Authenticator) AuthREST(ctx context.Context, accessToken string, reques
 		if err != nil {
 			return nil, false, apperr.ErrUnauthorized
 		}
+		if err = a.checkTotp(ctx, user, requestURI.Path); err != nil {
+			return nil, false, err
+		}
 		return user, claims.Root, nil
 	}
 
@@ -91,6 +103,9 @@ func (a *Authenticator) AuthREST(ctx context.Context, accessToken string, reques
 		if err != nil {
 			return nil, false, apperr.ErrUnauthorized
 		}
+		if err = a.checkTotp(ctx, user, requestURI.Path); err != nil {
+			return nil, false, err
+		}
 		return user, claims.Root, nil
 	}
 
@@ -99,6 +114,65 @@ func (a *Authenticator) AuthREST(ctx context.Context, accessToken string, reques
 	return nil, false, apperr.ErrUnauthorized
 }
 
+// authApiToken the personal api token works in place of the jwt, the access is limited by the token scopes
+func (a *Authenticator) authApiToken(ctx context.Context, accessToken string, requestURI *url.URL, method string) (*m.User, bool, error) {
+
+	token, err := a.adaptors.ApiToken.GetByToken(ctx, common.HashToken(accessToken))
+	if err != nil {
+		return nil, false, apperr.ErrUnauthorized
+	}
+
+	now := time.Now()
+	if token.IsExpired(now) {
+		return nil, false, apperr.ErrApiTokenExpired
+	}
+
+	user, err := a.adaptors.User.GetById(ctx, token.UserId)
+	if err != nil || user.Status == "blocked" {
+		return nil, false, apperr.ErrUnauthorized
+	}
+
+	var accessList access_list.AccessList
+	if user.Id == 1 || user.RoleName == "admin" {
+		accessList = *a.accessListService.List(ctx)
+	} else if accessList, err = a.accessListService.GetFullAccessList(ctx, user.RoleName); err != nil {
+		return nil, false, apperr.ErrUnauthorized
+	}
+
+	if !a.accessDecision(requestURI.Path, method, accessList.Scoped(token.Scopes)) {
+		log.Warnf(fmt.Sprintf("access denied: api token(%d) [%s] url(%s)", token.Id, method, requestURI.Path))
+		return nil, false, apperr.ErrUnauthorized
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > time.Minute {
+		if err = a.adaptors.ApiToken.Touch(ctx, token.Id, now); err != nil {
+			log.Warn(err.Error())
+		}
+	}
+
+	return user, false, nil
+}
+
+// checkTotp the role may require the two-factor authentication, until the user has enrolled
+// only the enrolment and the sign out are allowed
+func (a *Authenticator) checkTotp(ctx context.Context, user *m.User, path string) error {
+
+	if user.Role == nil || !user.Role.RequireTotp {
+		return nil
+	}
+
+	if totpAllowedPath.MatchString(path) {
+		return nil
+	}
+
+	totp, err := a.adaptors.UserTotp.GetByUserId(ctx, user.Id)
+	if err == nil && totp.Enabled {
+		return nil
+	}
+
+	return apperr.ErrUserTotpEnrolmentRequired
+}
+
 func (a *Authenticator) AuthPlain(login, pass string) (*m.User, error) {
 
 	user, err := a.adaptors.User.GetByNickname(context.Background(), login)
@@ -19,6 +19,7 @@
 package rbac_echo
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
@@ -56,11 +57,15 @@ func (f *EchoAccessFilter) Auth(next echo.HandlerFunc) echo.HandlerFunc {
 
 		user, root, err := f.Authenticator.AuthREST(c.Request().Context(), accessToken, requestURI, method)
 		if err != nil {
-			return f.HTTP401(c, apperr.ErrUnauthorized)
+			return f.HTTP401(c, err)
 		}
 		if user != nil {
 			c.Set("currentUser", user)
 			c.Set("root", f.config.RootMode || root)
+			// the endpoints read the user from the request context
+			ctx := context.WithValue(c.Request().Context(), "currentUser", user)
+			ctx = context.WithValue(ctx, "root", f.config.RootMode || root)
+			c.SetRequest(c.Request().WithContext(ctx))
 			return next(c)
 		}
 
@@ -69,7 +74,7 @@ func (f *EchoAccessFilter) Auth(next echo.HandlerFunc) echo.HandlerFunc {
 }
 
 func (f *EchoAccessFilter) getAccessToken(c echo.Context) (accessToken string) {
-	accessToken = c.Request().Header.Get("authorization")
+	accessToken = strings.TrimPrefix(c.Request().Header.Get("authorization"), "Bearer ")
 	if accessToken != "" {
 		return
 	}
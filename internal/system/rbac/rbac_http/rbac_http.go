@@ -20,10 +20,13 @@ package rbac_http
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
 	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 	"github.com/e154/smart-home/pkg/plugins"
 )
@@ -33,12 +36,16 @@ var _ plugins.HttpAccessFilter = (*HttpAccessFilter)(nil)
 type HttpAccessFilter struct {
 	config        *m.AppConfig
 	Authenticator plugins.Authorization
+	entityAccess  entity_access.EntityAccessService
 }
 
-func NewHttpAccessFilter(config *m.AppConfig, authenticator plugins.Authorization) plugins.HttpAccessFilter {
+func NewHttpAccessFilter(config *m.AppConfig,
+	authenticator plugins.Authorization,
+	entityAccess entity_access.EntityAccessService) plugins.HttpAccessFilter {
 	return &HttpAccessFilter{
 		config:        config,
 		Authenticator: authenticator,
+		entityAccess:  entityAccess,
 	}
 }
 
@@ -56,19 +63,46 @@ func (f *HttpAccessFilter) Auth(next http.Handler) http.Handler {
 
 		user, root, err := f.Authenticator.AuthREST(r.Context(), accessToken, requestURI, method)
 		if err != nil {
-			f.HTTP401(w, apperr.ErrUnauthorized)
+			f.HTTP401(w, err)
 			return
 		}
 		if user == nil {
 			f.HTTP401(w, apperr.ErrUnauthorized)
+			return
 		}
 
 		ctx := context.WithValue(r.Context(), "currentUser", user)
-		ctx = context.WithValue(r.Context(), "root", f.config.RootMode || root)
+		ctx = context.WithValue(ctx, "root", f.config.RootMode || root)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// EntityAuth ...
+func (f *HttpAccessFilter) EntityAuth(level common.EntityAccessLevel, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		user, _ := r.Context().Value("currentUser").(*m.User)
+		if user == nil {
+			f.HTTP401(w, apperr.ErrUnauthorized)
+			return
+		}
+
+		entityId := common.EntityId(r.PathValue("entity_id"))
+		if err := f.entityAccess.Check(r.Context(), user, entityId, level); err != nil {
+			switch {
+			case errors.Is(err, apperr.ErrAccessForbidden):
+				http.Error(w, "FORBIDDEN", http.StatusForbidden)
+			case errors.Is(err, apperr.ErrNotFound):
+				http.Error(w, "NOT FOUND", http.StatusNotFound)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (f *HttpAccessFilter) getAccessToken(r *http.Request) (accessToken string) {
 	accessToken = r.Header.Get("authorization")
 	if accessToken != "" {
@@ -0,0 +1,122 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package rbac_http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/e154/bus"
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx/fxtest"
+)
+
+type authorization struct {
+	users map[string]*m.User
+}
+
+func (a *authorization) AuthPlain(_, _ string) (*m.User, error) {
+	return nil, apperr.ErrUnauthorized
+}
+
+func (a *authorization) AuthREST(_ context.Context, accessToken string, _ *url.URL, _ string) (*m.User, bool, error) {
+	if user, ok := a.users[accessToken]; ok {
+		return user, false, nil
+	}
+	return nil, false, apperr.ErrUnauthorized
+}
+
+type entityRepo struct {
+	adaptors.EntityRepo
+	list map[common.EntityId]*m.Entity
+}
+
+func (r *entityRepo) GetById(_ context.Context, id common.EntityId, _ ...bool) (*m.Entity, error) {
+	if entity, ok := r.list[id]; ok {
+		return entity, nil
+	}
+	return nil, apperr.ErrEntityNotFound
+}
+
+type entityPermissionRepo struct {
+	adaptors.EntityPermissionRepo
+	list []*m.EntityPermission
+}
+
+func (r *entityPermissionRepo) GetAllPermissions(_ context.Context, roleName string) (list []*m.EntityPermission, _ error) {
+	for _, permission := range r.list {
+		if permission.RoleName == roleName {
+			list = append(list, permission)
+		}
+	}
+	return
+}
+
+func TestEntityAuth(t *testing.T) {
+
+	hallId := common.EntityId("onvif.hall")
+	yardId := common.EntityId("onvif.yard")
+
+	a := &adaptors.Adaptors{
+		Entity: &entityRepo{list: map[common.EntityId]*m.Entity{
+			hallId: {Id: hallId},
+			yardId: {Id: yardId},
+		}},
+		EntityPermission: &entityPermissionRepo{list: []*m.EntityPermission{
+			{RoleName: "guest", EntityId: &hallId, Level: common.EntityAccessRead},
+		}},
+	}
+	filter := NewHttpAccessFilter(&m.AppConfig{},
+		&authorization{users: map[string]*m.User{
+			"guest": {Id: 2, RoleName: "guest"},
+			"admin": {Id: 3, RoleName: "admin"},
+		}},
+		entity_access.NewEntityAccessService(fxtest.NewLifecycle(t), a, bus.NewBus()))
+
+	router := http.NewServeMux()
+	router.Handle("GET /media/{entity_id}/channel/{channel}/records", filter.Auth(filter.EntityAuth(common.EntityAccessRead,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))))
+
+	get := func(token string, entityId common.EntityId) int {
+		r := httptest.NewRequest(http.MethodGet, "/media/"+entityId.String()+"/channel/0/records", nil)
+		r.Header.Set("authorization", token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// the guest may watch the hall camera only
+	require.Equal(t, http.StatusOK, get("guest", hallId))
+	require.Equal(t, http.StatusForbidden, get("guest", yardId))
+	require.Equal(t, http.StatusNotFound, get("guest", "onvif.garage"))
+
+	require.Equal(t, http.StatusOK, get("admin", yardId))
+	require.Equal(t, http.StatusUnauthorized, get("", hallId))
+}
@@ -21,6 +21,7 @@ package scheduler
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/e154/smart-home/pkg/adaptors"
 	"github.com/e154/smart-home/pkg/common"
@@ -41,10 +42,10 @@ var (
 var _ scheduler.Scheduler = (*Scheduler)(nil)
 
 type Scheduler struct {
-	adaptors    *adaptors.Adaptors
-	cron        *cron.Cron
-	eventBus    bus.Bus
-	backupEntry cron.EntryID
+	adaptors      *adaptors.Adaptors
+	cron          *cron.Cron
+	eventBus      bus.Bus
+	backupEntries []cron.EntryID
 }
 
 func NewScheduler(lc fx.Lifecycle,
@@ -75,6 +76,20 @@ func (c *Scheduler) Start(ctx context.Context) error {
 			cron.SecondOptional|cron.Minute|cron.Hour|cron.Dom|cron.Month|cron.Dow|cron.Descriptor,
 		)))
 
+	// every five minutes
+	_, _ = c.cron.AddFunc("0 */5 * * * *", func() {
+		go func() {
+			if err := c.adaptors.MetricBucket.Rollup(context.Background()); err != nil {
+				log.Error(err.Error())
+			}
+		}()
+		go func() {
+			if err := c.adaptors.EntityStorage.Rollup(context.Background()); err != nil {
+				log.Error(err.Error())
+			}
+		}()
+	})
+
 	// every hour
 	_, _ = c.cron.AddFunc("0 0 * * * *", func() {
 		go func() {
@@ -82,6 +97,12 @@ func (c *Scheduler) Start(ctx context.Context) error {
 			if err := c.adaptors.MetricBucket.DeleteOldest(context.Background(), c.getNumber("clearMetricsDays", 60)); err != nil {
 				log.Error(err.Error())
 			}
+			if err := c.adaptors.MetricBucket.DeleteOldestStats(context.Background(), common.StatsResolutionHour, c.getNumber("clearMetricsHourlyDays", 365)); err != nil {
+				log.Error(err.Error())
+			}
+			if err := c.adaptors.MetricBucket.DeleteOldestStats(context.Background(), common.StatsResolutionDay, c.getNumber("clearMetricsDailyDays", 3650)); err != nil {
+				log.Error(err.Error())
+			}
 		}()
 		go func() {
 			//log.Info("deleting obsolete log entries ...")
@@ -94,6 +115,12 @@ func (c *Scheduler) Start(ctx context.Context) error {
 			if err := c.adaptors.EntityStorage.DeleteOldest(context.Background(), c.getNumber("clearEntityStorageDays", 60)); err != nil {
 				log.Error(err.Error())
 			}
+			if err := c.adaptors.EntityStorage.DeleteOldestStats(context.Background(), common.StatsResolutionHour, c.getNumber("clearEntityStorageHourlyDays", 365)); err != nil {
+				log.Error(err.Error())
+			}
+			if err := c.adaptors.EntityStorage.DeleteOldestStats(context.Background(), common.StatsResolutionDay, c.getNumber("clearEntityStorageDailyDays", 3650)); err != nil {
+				log.Error(err.Error())
+			}
 		}()
 		go func() {
 			//log.Info("deleting obsolete run history entries ...")
@@ -101,6 +128,12 @@ func (c *Scheduler) Start(ctx context.Context) error {
 				log.Error(err.Error())
 			}
 		}()
+		go func() {
+			//log.Info("deleting obsolete task run entries ...")
+			if err := c.adaptors.TaskRun.DeleteOldest(context.Background(), c.getNumber("clearTaskRunsDays", 60)); err != nil {
+				log.Error(err.Error())
+			}
+		}()
 	})
 
 	c.updateBackupScheduler()
@@ -158,19 +191,26 @@ func (c *Scheduler) getString(varName, def string) string {
 
 func (c *Scheduler) updateBackupScheduler() {
 
-	if c.backupEntry != 0 {
-		c.cron.Remove(c.backupEntry)
-		c.backupEntry = 0
+	for _, entry := range c.backupEntries {
+		c.cron.Remove(entry)
 	}
+	c.backupEntries = c.backupEntries[:0]
 
-	var err error
-	c.backupEntry, err = c.cron.AddFunc(c.getString("createBackupAt", "0 0 0 * * *"), func() {
-		c.eventBus.Publish("system/services/backup", events.CommandCreateBackup{
-			Scheduler: true,
+	// the expressions are separated by semicolon
+	for _, spec := range strings.Split(c.getString("createBackupAt", "0 0 0 * * *"), ";") {
+		if spec = strings.TrimSpace(spec); spec == "" {
+			continue
+		}
+		entry, err := c.cron.AddFunc(spec, func() {
+			c.eventBus.Publish("system/services/backup", events.CommandCreateBackup{
+				Scheduler: true,
+			})
 		})
-	})
-	if err != nil {
-		log.Error(err.Error())
+		if err != nil {
+			log.Errorf("backup schedule \"%s\": %s", spec, err.Error())
+			continue
+		}
+		c.backupEntries = append(c.backupEntries, entry)
 	}
 }
 
@@ -182,7 +222,10 @@ func (c *Scheduler) eventHandler(_ string, message interface{}) {
 		}
 	case events.EventCreatedBackup:
 		c.eventBus.Publish("system/services/backup", events.CommandClearStorage{
-			Num: int64(c.getNumber("maximumNumberOfBackups", 60)),
+			Num:     int64(c.getNumber("maximumNumberOfBackups", 60)),
+			Daily:   c.getNumber("backupKeepDaily", 0),
+			Weekly:  c.getNumber("backupKeepWeekly", 0),
+			Monthly: c.getNumber("backupKeepMonthly", 0),
 		})
 
 		if !v.Scheduler {
@@ -0,0 +1,123 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// History ...
+type History struct {
+	adaptors *adaptors.Adaptors
+}
+
+// NewHistoryBind ...
+func NewHistoryBind(adaptors *adaptors.Adaptors) *History {
+	return &History{
+		adaptors: adaptors,
+	}
+}
+
+// HistoryQueryRequest ...
+type HistoryQueryRequest struct {
+	EntityIds   []string `json:"entity_ids"`
+	Attribute   string   `json:"attribute"`
+	Aggregation string   `json:"aggregation"`
+	Interval    string   `json:"interval"`
+	State       string   `json:"state"`
+	From        string   `json:"from"`
+	To          string   `json:"to"`
+}
+
+// HistoryQueryResponse ...
+type HistoryQueryResponse struct {
+	Items []*m.EntityHistoryPoint `json:"items"`
+	Error error                   `json:"error"`
+}
+
+// Query ...
+func (h *History) Query(request HistoryQueryRequest) *HistoryQueryResponse {
+	items, err := h.query(request)
+	return &HistoryQueryResponse{
+		Items: items,
+		Error: err,
+	}
+}
+
+// DurationInState returns the seconds the entity spent in the state, from and to default to the current day
+func (h *History) DurationInState(entityId, state, from, to string) float64 {
+	items, err := h.query(HistoryQueryRequest{
+		EntityIds:   []string{entityId},
+		Aggregation: common.HistoryAggregationDuration.String(),
+		State:       state,
+		From:        from,
+		To:          to,
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return 0
+	}
+	if len(items) == 0 {
+		return 0
+	}
+	seconds, _ := items[0].Value.(float64)
+	return seconds
+}
+
+func (h *History) query(request HistoryQueryRequest) (items []*m.EntityHistoryPoint, err error) {
+
+	query := &m.EntityHistoryQuery{
+		Attribute:   request.Attribute,
+		Aggregation: common.HistoryAggregation(request.Aggregation),
+		State:       request.State,
+	}
+
+	for _, entityId := range request.EntityIds {
+		query.EntityIds = append(query.EntityIds, common.EntityId(entityId))
+	}
+
+	if request.Interval != "" {
+		if query.Interval, err = time.ParseDuration(request.Interval); err != nil {
+			err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrInvalidRequest)
+			return
+		}
+	}
+	if request.From != "" {
+		if query.From, err = time.Parse(time.RFC3339, request.From); err != nil {
+			err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrInvalidRequest)
+			return
+		}
+	}
+	if request.To != "" {
+		if query.To, err = time.Parse(time.RFC3339, request.To); err != nil {
+			err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrInvalidRequest)
+			return
+		}
+	}
+
+	items, err = h.adaptors.EntityStorage.History(context.Background(), query)
+	return
+}
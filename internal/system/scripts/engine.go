@@ -27,9 +27,11 @@ import (
 	"github.com/e154/smart-home/internal/system/scripts/require"
 	"github.com/e154/smart-home/pkg/apperr"
 	. "github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
 	m "github.com/e154/smart-home/pkg/models"
 	"github.com/e154/smart-home/pkg/scripts"
 
+	"github.com/e154/bus"
 	"github.com/hashicorp/go-multierror"
 	"go.uber.org/atomic"
 )
@@ -44,10 +46,12 @@ type Engine struct {
 	IsRun      atomic.Bool
 	functions  *Pull
 	structures *Pull
+	limits     Limits
+	eventBus   bus.Bus
 }
 
 // NewEngine ...
-func NewEngine(s *m.Script, functions, structures *Pull, loader require.SourceLoader) (engine *Engine, err error) {
+func NewEngine(s *m.Script, functions, structures *Pull, loader require.SourceLoader, limits Limits, eventBus bus.Bus) (engine *Engine, err error) {
 
 	if s == nil {
 		s = &m.Script{
@@ -68,6 +72,8 @@ func NewEngine(s *m.Script, functions, structures *Pull, loader require.SourceLo
 		buf:        make([]string, 0),
 		functions:  functions,
 		structures: structures,
+		limits:     limits.WithScript(s),
+		eventBus:   eventBus,
 	}
 
 	if s.Lang == "" {
@@ -111,12 +117,14 @@ func (s *Engine) EvalString(str ...string) (result string, errs error) {
 	var err error
 	if len(str) == 0 {
 		if result, err = s.script.Do(); err != nil {
+			s.interrupted(err)
 			errs = multierror.Append(err, errs)
 		}
 		return
 	}
 	for _, st := range str {
 		if result, err = s.script.EvalString(st); err != nil {
+			s.interrupted(err)
 			errs = multierror.Append(err, errs)
 		}
 	}
@@ -136,6 +144,7 @@ func (s *Engine) EvalScript(script *m.Script) (result string, err error) {
 		}
 		result, err = s.script.RunProgram(programName)
 	}
+	s.interrupted(err)
 	return
 }
 
@@ -149,6 +158,7 @@ func (s *Engine) DoFull() (res string, err error) {
 	var result string
 	result, err = s.script.Do()
 	if err != nil {
+		s.interrupted(err)
 		err = fmt.Errorf("do full: %w", err)
 		return
 	}
@@ -165,8 +175,10 @@ func (s *Engine) DoFull() (res string, err error) {
 }
 
 // Do ...
-func (s *Engine) Do() (string, error) {
-	return s.script.Do()
+func (s *Engine) Do() (result string, err error) {
+	result, err = s.script.Do()
+	s.interrupted(err)
+	return
 }
 
 // AssertFunction ...
@@ -178,6 +190,7 @@ func (s *Engine) AssertFunction(f string, arg ...interface{}) (result string, er
 
 	result, err = s.script.AssertFunction(f, arg...)
 	if err != nil {
+		s.interrupted(err)
 		if s.ScriptId() != 0 {
 			err = fmt.Errorf("script id:%d: %w", s.ScriptId(), err)
 			return
@@ -218,3 +231,27 @@ func (s *Engine) ScriptId() int64 {
 func (s *Engine) Script() *m.Script {
 	return s.model
 }
+
+// Interrupt stops the running script
+func (s *Engine) Interrupt(v interface{}) {
+	s.script.Interrupt(v)
+}
+
+// interrupted records the run of the script that was stopped by one of the limits
+func (s *Engine) interrupted(err error) {
+	if !IsInterrupted(err) {
+		return
+	}
+
+	// the script service saves the run in the log table
+	if s.eventBus == nil {
+		log.Errorf("script id: %d was interrupted: %s", s.ScriptId(), err.Error())
+		return
+	}
+
+	s.eventBus.Publish(fmt.Sprintf("system/scripts/%d", s.ScriptId()), events.EventScriptInterrupted{
+		ScriptId: s.ScriptId(),
+		Code:     apperr.Code(err),
+		Error:    err.Error(),
+	})
+}
@@ -82,8 +82,9 @@ func (w *EngineWatcher) Spawn(f func(engine scripts.Engine)) {
 	defer w.mx.RUnlock()
 
 	w.engine, _ = w.scriptService.NewEngine(&m.Script{
-		Id:   w.script.Id,
-		Lang: common.ScriptLangJavascript,
+		Id:      w.script.Id,
+		Lang:    common.ScriptLangJavascript,
+		Timeout: w.script.Timeout,
 	})
 	w.structures.Range(func(key, value interface{}) bool {
 		w.engine.PushStruct(key.(string), value)
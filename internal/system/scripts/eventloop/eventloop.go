@@ -48,6 +48,7 @@ type interval struct {
 type EventLoop struct {
 	vm       *goja.Runtime
 	jobChan  chan func()
+	quit     chan struct{}
 	jobCount int32
 	running  bool
 }
@@ -58,6 +59,7 @@ func NewEventLoop(vm *goja.Runtime, loader require.SourceLoader) *EventLoop {
 	loop := &EventLoop{
 		vm:      vm,
 		jobChan: make(chan func()),
+		quit:    make(chan struct{}, 1),
 	}
 
 	reg := require.NewRegistryWithLoader(loader)
@@ -101,10 +103,23 @@ func (loop *EventLoop) setInterval(call goja.FunctionCall) goja.Value {
 // of the function.
 // Do NOT use this function while the loop is already running. Use RunOnLoop() instead.
 func (loop *EventLoop) Run(fn func(*goja.Runtime)) {
+	select {
+	case <-loop.quit:
+	default:
+	}
 	fn(loop.vm)
 	loop.run()
 }
 
+// Terminate breaks the loop that was started with Run() without waiting for the delayed jobs.
+// It is safe to call it from another goroutine.
+func (loop *EventLoop) Terminate() {
+	select {
+	case loop.quit <- struct{}{}:
+	default:
+	}
+}
+
 // Start the event loop in the background. The loop continues to run until Stop() is called.
 func (loop *EventLoop) Start() {
 	go loop.runInBackground()
@@ -137,11 +152,15 @@ func (loop *EventLoop) RunOnLoop(fn func(*goja.Runtime)) {
 func (loop *EventLoop) run() {
 	loop.running = true
 	for loop.running && loop.jobCount > 0 {
-		job, ok := <-loop.jobChan
-		if !ok {
-			break
+		select {
+		case job, ok := <-loop.jobChan:
+			if !ok {
+				return
+			}
+			job()
+		case <-loop.quit:
+			return
 		}
-		job()
 	}
 }
 
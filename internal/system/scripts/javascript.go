@@ -20,6 +20,7 @@ package scripts
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"strings"
@@ -30,11 +31,21 @@ import (
 	"github.com/e154/smart-home/internal/system/scripts/require"
 	"github.com/e154/smart-home/pkg/apperr"
 	. "github.com/e154/smart-home/pkg/common"
+
+	"go.uber.org/atomic"
 )
 
 //go:embed *.js
 var scriptsAsset embed.FS
 
+// runs the number of script runs since the start, the nested calls are not counted
+var runs atomic.Uint64
+
+// RunCounter ...
+func RunCounter() uint64 {
+	return runs.Load()
+}
+
 // Javascript ...
 type Javascript struct {
 	engine       *Engine
@@ -45,6 +56,8 @@ type Javascript struct {
 	lockPrograms sync.Mutex
 	programs     map[string]*goja.Program
 	loader       require.SourceLoader
+	guarded      atomic.Bool
+	interruptMx  sync.Mutex
 }
 
 // NewJavascript ...
@@ -62,6 +75,9 @@ func (j *Javascript) Init() (err error) {
 	j.vm = goja.New()
 	j.vm.SetFieldNameMapper(goja.TagFieldNameMapper("json", true))
 	//j.vm.SetFieldNameMapper(goja.UncapFieldNameMapper())
+	if j.engine.limits.MaxCallStackSize > 0 {
+		j.vm.SetMaxCallStackSize(j.engine.limits.MaxCallStackSize)
+	}
 	j.loop = eventloop.NewEventLoop(j.vm, j.loader)
 
 	j.bind()
@@ -141,7 +157,7 @@ func (j *Javascript) GetCompiler() error {
 
 func (j *Javascript) tsCompile() (result goja.Value, err error) {
 
-	if _, err = j.EvalString(j.compiler); err != nil {
+	if _, err = j.evalCompiler(); err != nil {
 		return
 	}
 
@@ -181,7 +197,7 @@ try {
 
 func (j *Javascript) coffeeCompile() (result goja.Value, err error) {
 
-	if _, err = j.EvalString(j.compiler); err != nil {
+	if _, err = j.evalCompiler(); err != nil {
 		return
 	}
 
@@ -202,6 +218,19 @@ func (j *Javascript) coffeeCompile() (result goja.Value, err error) {
 	return
 }
 
+// evalCompiler loads the compiler source, it runs without the script limits
+func (j *Javascript) evalCompiler() (result string, err error) {
+
+	var program *goja.Program
+	if program, err = goja.Compile("", j.compiler, false); err != nil {
+		return
+	}
+
+	result, err = j.run(program)
+
+	return
+}
+
 // Do ...
 func (j *Javascript) Do() (result string, err error) {
 	result, err = j.unsafeRun(j.program)
@@ -232,7 +261,11 @@ func (j *Javascript) AssertFunction(f string, args ...interface{}) (result strin
 		for _, arg := range args {
 			gojaArgs = append(gojaArgs, j.vm.ToValue(arg))
 		}
-		if value, err = assertFunc(goja.Undefined(), gojaArgs...); err != nil {
+		stop := j.guard()
+		value, err = assertFunc(goja.Undefined(), gojaArgs...)
+		stop()
+		if err != nil {
+			err = limitError(err)
 			return
 		}
 		result = value.String()
@@ -329,6 +362,20 @@ func (j *Javascript) unsafeRun(program *goja.Program) (result string, err error)
 		return
 	}
 
+	stop := j.guard()
+	defer stop()
+
+	result, err = j.run(program)
+
+	return
+}
+
+func (j *Javascript) run(program *goja.Program) (result string, err error) {
+
+	if program == nil {
+		return
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
 			log.Warn("Recovered script: ", j.engine.model.Id)
@@ -349,7 +396,7 @@ func (j *Javascript) unsafeRun(program *goja.Program) (result string, err error)
 	wg.Wait()
 
 	if err != nil {
-		err = fmt.Errorf("unsafeRun: %w", err)
+		err = fmt.Errorf("unsafeRun: %w", limitError(err))
 		return
 	}
 
@@ -357,3 +404,44 @@ func (j *Javascript) unsafeRun(program *goja.Program) (result string, err error)
 
 	return
 }
+
+// guard starts the watchdog of the script limits, only the outermost call is watched
+func (j *Javascript) guard() (stop func()) {
+	if !j.guarded.CompareAndSwap(false, true) {
+		return func() {}
+	}
+	runs.Inc()
+
+	w := startWatchdog(j.engine.limits, func(v interface{}) {
+		j.vm.Interrupt(v)
+		j.loop.Terminate()
+	})
+
+	return func() {
+		w.Stop()
+		j.interruptMx.Lock()
+		j.vm.ClearInterrupt()
+		j.guarded.Store(false)
+		j.interruptMx.Unlock()
+	}
+}
+
+// Interrupt stops the running script, it does nothing if no script is running
+func (j *Javascript) Interrupt(v interface{}) {
+	j.interruptMx.Lock()
+	defer j.interruptMx.Unlock()
+
+	if !j.guarded.Load() {
+		return
+	}
+	j.vm.Interrupt(v)
+	j.loop.Terminate()
+}
+
+func limitError(err error) error {
+	var overflow *goja.StackOverflowError
+	if errors.As(err, &overflow) {
+		return fmt.Errorf("%s: %w", overflow.Error(), apperr.ErrScriptStackOverflow)
+	}
+	return err
+}
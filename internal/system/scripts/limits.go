@@ -0,0 +1,143 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"errors"
+	"runtime"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+const memoryCheckInterval = time.Millisecond * 100
+
+// Limits restricts the resources available to a single script run.
+// Zero values disable the corresponding limit.
+type Limits struct {
+	// Timeout wall-clock time of a single run
+	Timeout time.Duration
+	// MaxCallStackSize maximum function call depth
+	MaxCallStackSize int
+	// MemoryLimit maximum heap growth in bytes during a single run.
+	// The heap is shared by the whole process, so the check is approximate.
+	MemoryLimit uint64
+}
+
+// NewLimits ...
+func NewLimits(cfg *m.AppConfig) Limits {
+	if cfg == nil {
+		return Limits{}
+	}
+	return Limits{
+		Timeout:          time.Duration(cfg.ScriptTimeout) * time.Second,
+		MaxCallStackSize: cfg.ScriptMaxCallStackSize,
+		MemoryLimit:      uint64(cfg.ScriptMemoryLimit) * 1024 * 1024,
+	}
+}
+
+// WithScript returns limits with the script timeout applied, if it is set
+func (l Limits) WithScript(script *m.Script) Limits {
+	if script != nil && script.Timeout > 0 {
+		l.Timeout = time.Duration(script.Timeout) * time.Second
+	}
+	return l
+}
+
+// IsInterrupted reports whether the script was stopped by one of the limits
+func IsInterrupted(err error) bool {
+	return errors.Is(err, apperr.ErrScriptTimeout) ||
+		errors.Is(err, apperr.ErrScriptStackOverflow) ||
+		errors.Is(err, apperr.ErrScriptMemoryLimit)
+}
+
+// watchdog interrupts the running script when the time or memory limit is exceeded
+type watchdog struct {
+	limits    Limits
+	interrupt func(v interface{})
+	done      chan struct{}
+	finished  chan struct{}
+}
+
+func startWatchdog(limits Limits, interrupt func(v interface{})) *watchdog {
+	w := &watchdog{
+		limits:    limits,
+		interrupt: interrupt,
+		done:      make(chan struct{}),
+		finished:  make(chan struct{}),
+	}
+	if limits.Timeout == 0 && limits.MemoryLimit == 0 {
+		close(w.finished)
+		return w
+	}
+	go w.run()
+	return w
+}
+
+func (w *watchdog) run() {
+	defer close(w.finished)
+
+	var timeout <-chan time.Time
+	if w.limits.Timeout > 0 {
+		timer := time.NewTimer(w.limits.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var tick <-chan time.Time
+	var startAlloc uint64
+	if w.limits.MemoryLimit > 0 {
+		ticker := time.NewTicker(memoryCheckInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+		startAlloc = heapAlloc()
+	}
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-timeout:
+			w.interrupt(apperr.ErrScriptTimeout)
+			return
+		case <-tick:
+			if alloc := heapAlloc(); alloc > startAlloc && alloc-startAlloc > w.limits.MemoryLimit {
+				w.interrupt(apperr.ErrScriptMemoryLimit)
+				return
+			}
+		}
+	}
+}
+
+// Stop waits until the watchdog goroutine exits, after that no interrupt can be sent
+func (w *watchdog) Stop() {
+	select {
+	case <-w.finished:
+	default:
+		close(w.done)
+		<-w.finished
+	}
+}
+
+func heapAlloc() uint64 {
+	var stat runtime.MemStats
+	runtime.ReadMemStats(&stat)
+	return stat.HeapAlloc
+}
@@ -0,0 +1,104 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package scripts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLimits(t *testing.T) {
+
+	newEngine := func(src string, limits Limits) *Engine {
+		engine, err := NewEngine(&m.Script{
+			Lang:   common.ScriptLangJavascript,
+			Source: src,
+		}, nil, nil, nil, limits, nil)
+		So(err, ShouldBeNil)
+		So(engine.Compile(), ShouldBeNil)
+		return engine
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		Convey("endless loop", t, func(ctx C) {
+			engine := newEngine(`while(true){}`, Limits{Timeout: time.Millisecond * 200})
+
+			_, err := engine.DoFull()
+			ctx.So(errors.Is(err, apperr.ErrScriptTimeout), ShouldBeTrue)
+			ctx.So(apperr.Code(err), ShouldEqual, "SCRIPT_TIMEOUT_ERROR")
+
+			// the engine is reusable after interrupt
+			res, err := engine.EvalString(`1 + 1`)
+			ctx.So(err, ShouldBeNil)
+			ctx.So(res, ShouldEqual, "2")
+		})
+
+		Convey("endless loop in function", t, func(ctx C) {
+			engine := newEngine(`function loop() { while(true){} }`, Limits{Timeout: time.Millisecond * 200})
+			_, err := engine.Do()
+			ctx.So(err, ShouldBeNil)
+
+			_, err = engine.AssertFunction("loop")
+			ctx.So(errors.Is(err, apperr.ErrScriptTimeout), ShouldBeTrue)
+		})
+
+		Convey("script timeout overrides the default", t, func(ctx C) {
+			limits := Limits{Timeout: time.Second}.WithScript(&m.Script{Timeout: 5})
+			ctx.So(limits.Timeout, ShouldEqual, time.Second*5)
+		})
+	})
+
+	t.Run("call stack", func(t *testing.T) {
+		Convey("endless recursion", t, func(ctx C) {
+			engine := newEngine(`function f() { return f(); }; f();`, Limits{MaxCallStackSize: 100})
+
+			_, err := engine.DoFull()
+			ctx.So(errors.Is(err, apperr.ErrScriptStackOverflow), ShouldBeTrue)
+		})
+	})
+
+	t.Run("interrupt", func(t *testing.T) {
+		Convey("interrupt the running function", t, func(ctx C) {
+			engine := newEngine(`function loop() { while(true){} }`, Limits{})
+			_, err := engine.Do()
+			ctx.So(err, ShouldBeNil)
+
+			// nothing is running, the next run is not affected
+			engine.Interrupt(context.Canceled)
+			res, err := engine.EvalString(`1 + 1`)
+			ctx.So(err, ShouldBeNil)
+			ctx.So(res, ShouldEqual, "2")
+
+			time.AfterFunc(time.Millisecond*200, func() {
+				engine.Interrupt(context.Canceled)
+			})
+			_, err = engine.AssertFunction("loop")
+			ctx.So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			ctx.So(IsInterrupted(err), ShouldBeFalse)
+		})
+	})
+}
@@ -20,11 +20,14 @@ package scripts
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/e154/smart-home/internal/system/scripts/bind"
 	"github.com/e154/smart-home/internal/system/storage"
 	"github.com/e154/smart-home/internal/system/validation"
 	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/common/encryptor"
 	"github.com/e154/smart-home/pkg/events"
 	"github.com/e154/smart-home/pkg/logger"
@@ -48,6 +51,7 @@ type scriptService struct {
 	eventBus   bus.Bus
 	adaptors   *adaptors.Adaptors
 	validation *validation.Validate
+	limits     Limits
 }
 
 // NewScriptService ...
@@ -66,16 +70,19 @@ func NewScriptService(lc fx.Lifecycle,
 		eventBus:   eventBus,
 		adaptors:   adaptors,
 		validation: validation,
+		limits:     NewLimits(cfg),
 	}
 
 	s.bind()
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) (err error) {
+			_ = eventBus.Subscribe("system/scripts/+", s.eventHandler)
 			eventBus.Publish("system/services/scripts", events.EventServiceStarted{Service: "Scripts"})
 			return
 		},
 		OnStop: func(ctx context.Context) (err error) {
+			_ = eventBus.Unsubscribe("system/scripts/+", s.eventHandler)
 			eventBus.Publish("system/services/scripts", events.EventServiceStopped{Service: "Scripts"})
 			return
 		},
@@ -84,9 +91,29 @@ func NewScriptService(lc fx.Lifecycle,
 	return s
 }
 
+func (s *scriptService) eventHandler(_ string, message interface{}) {
+	switch v := message.(type) {
+	case events.EventScriptInterrupted:
+		s.saveInterrupted(v)
+	}
+}
+
+// saveInterrupted records the run of the script killed by one of the limits in the log table
+func (s *scriptService) saveInterrupted(event events.EventScriptInterrupted) {
+	record := &models.Log{
+		Body:      fmt.Sprintf("script id: %d was interrupted (%s): %s", event.ScriptId, event.Code, event.Error),
+		Level:     common.LogLevelError,
+		Owner:     "scripts",
+		CreatedAt: time.Now(),
+	}
+	if _, err := s.adaptors.Log.Add(context.Background(), record); err != nil {
+		log.Error(err.Error())
+	}
+}
+
 // NewEngine ...
 func (s *scriptService) NewEngine(scr *models.Script) (scripts.Engine, error) {
-	return NewEngine(scr, s.structures, s.functions, s.SourceLoader)
+	return NewEngine(scr, s.structures, s.functions, s.SourceLoader, s.limits, s.eventBus)
 }
 
 // NewEngineWatcher ...
@@ -138,6 +165,7 @@ func (s *scriptService) bind() {
 	s.PushFunctions("Decrypt", encryptor.DecryptBind)
 	s.PushStruct("Storage", bind.NewStorageBind(s.storage))
 	s.PushStruct("Variables", bind.NewVariable(s.adaptors, s.validation, s.eventBus))
+	s.PushStruct("History", bind.NewHistoryBind(s.adaptors))
 	s.PushStruct("http", bind.NewHttpBind())
 	s.PushStruct("HTTP", bind.NewHttpBind())
 }
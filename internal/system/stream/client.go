@@ -33,7 +33,10 @@ type Client struct {
 	user      *m.User
 	sessionId string
 	*sync.Mutex
-	ws *websocket.Conn
+	ws     *websocket.Conn
+	filter *Filter
+	// authSessionId the session of the access token, zero if the token has no session
+	authSessionId int64
 }
 
 // NewClient ...
@@ -43,6 +46,7 @@ func NewClient(ws *websocket.Conn, user *m.User, sessionId string) *Client {
 		user:      user,
 		Mutex:     &sync.Mutex{},
 		sessionId: sessionId,
+		filter:    NewFilter(),
 	}
 }
 
@@ -100,3 +104,7 @@ func (c *Client) GetUser() *m.User {
 func (c *Client) SessionID() string {
 	return c.sessionId
 }
+
+func (c *Client) Filter() *Filter {
+	return c.filter
+}
@@ -0,0 +1,71 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common"
+)
+
+// entityCache keeps the area and the tags of the entities for the subscription filters and the entity permissions,
+// the item is dropped when the entity model changes
+type entityCache struct {
+	adaptors *adaptors.Adaptors
+	items    sync.Map
+}
+
+func newEntityCache(adaptors *adaptors.Adaptors) *entityCache {
+	return &entityCache{
+		adaptors: adaptors,
+	}
+}
+
+// Get ...
+func (c *entityCache) Get(entityId common.EntityId) *EntityInfo {
+	if v, ok := c.items.Load(entityId); ok {
+		return v.(*EntityInfo)
+	}
+
+	info := &EntityInfo{Id: entityId}
+	if c.adaptors != nil {
+		entity, err := c.adaptors.Entity.GetById(context.Background(), entityId)
+		if err != nil {
+			log.Warnf("entity %s: %s", entityId, err.Error())
+		} else {
+			if entity.Area != nil {
+				info.Area = entity.Area.Name
+				info.AreaId = &entity.Area.Id
+			}
+			for _, tag := range entity.Tags {
+				info.Tags = append(info.Tags, tag.Name)
+			}
+		}
+	}
+
+	c.items.Store(entityId, info)
+	return info
+}
+
+// Delete ...
+func (c *entityCache) Delete(entityId common.EntityId) {
+	c.items.Delete(entityId)
+}
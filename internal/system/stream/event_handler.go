@@ -22,19 +22,40 @@ import (
 	"encoding/json"
 
 	"github.com/e154/smart-home/internal/plugins/webpush"
+	"github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/events"
 )
 
 type eventHandler struct {
 	broadcast     func(query string, message []byte)
 	directMessage func(userID int64, sessionID string, query string, message []byte)
+	publish       func(msg *eventMessage)
+	entityUpdated func(entityId common.EntityId)
+	// sessionRevoked closes the connections of the revoked session, the zero session id means all sessions of the user
+	sessionRevoked func(userId, sessionId int64)
+}
+
+// eventMessage is the event for the clients, filtered by the subscriptions of the client
+type eventMessage struct {
+	query    string
+	entityId *common.EntityId
+	body     []byte
+	// state changes are sent to the subscribed clients as the diff, nil if nothing changed
+	isState bool
+	diff    []byte
 }
 
 func NewEventHandler(broadcast func(query string, message []byte),
-	directMessage func(userID int64, sessionID string, query string, message []byte)) *eventHandler {
+	directMessage func(userID int64, sessionID string, query string, message []byte),
+	publish func(msg *eventMessage),
+	entityUpdated func(entityId common.EntityId),
+	sessionRevoked func(userId, sessionId int64)) *eventHandler {
 	return &eventHandler{
-		broadcast:     broadcast,
-		directMessage: directMessage,
+		broadcast:      broadcast,
+		directMessage:  directMessage,
+		publish:        publish,
+		entityUpdated:  entityUpdated,
+		sessionRevoked: sessionRevoked,
 	}
 }
 
@@ -47,11 +68,14 @@ func (e *eventHandler) eventHandler(_ string, message interface{}) {
 		go e.eventStateChangedHandler(message)
 	case events.EventLastStateChanged:
 		go e.eventStateChangedHandler(message)
-	//case events.EventCreatedEntityModel:
-	//case events.EventUpdatedEntityModel:
+	case events.EventCreatedEntityModel:
+		e.entityUpdated(v.EntityId)
+	case events.EventUpdatedEntityModel:
+		e.entityUpdated(v.EntityId)
 	case events.EventUpdatedMetric:
 		go e.event(message)
-	//case events.CommandUnloadEntity:
+	case events.CommandUnloadEntity:
+		e.entityUpdated(v.EntityId)
 	case events.EventEntityLoaded:
 		go e.event(message)
 	case events.EventEntityUnloaded:
@@ -68,6 +92,10 @@ func (e *eventHandler) eventHandler(_ string, message interface{}) {
 	case events.EventDirectMessage:
 		go e.eventDirectMessage(v.UserID, v.SessionID, v.Query, v.Message)
 
+	// users
+	case events.EventUserSessionRevoked:
+		e.sessionRevoked(v.UserId, v.SessionId)
+
 	// plugins
 	case events.EventPluginLoaded,
 		events.EventPluginUnloaded:
@@ -76,7 +104,8 @@ func (e *eventHandler) eventHandler(_ string, message interface{}) {
 	// tasks
 	case events.EventTaskLoaded,
 		events.EventTaskUnloaded,
-		events.EventTaskCompleted:
+		events.EventTaskCompleted,
+		events.EventTaskRunFinished:
 		go e.event(message)
 
 	// triggers
@@ -97,9 +126,15 @@ func (e *eventHandler) eventHandler(_ string, message interface{}) {
 	case events.EventCreatedBackup,
 		events.EventRemovedBackup,
 		events.EventUploadedBackup,
+		events.EventUploadedBackupToTarget,
 		events.EventStartedRestore:
 		go e.event(message)
 
+	// zigbee2mqtt
+	case events.EventZigbee2mqttOtaProgress,
+		events.EventZigbee2mqttOtaFinished:
+		go e.event(message)
+
 	// variables
 	case events.EventRemovedVariableModel,
 		events.EventUpdatedVariableModel:
@@ -108,7 +143,8 @@ func (e *eventHandler) eventHandler(_ string, message interface{}) {
 	// scripts
 	case events.EventUpdatedScriptModel,
 		events.EventRemovedScriptModel,
-		events.EventCreatedScriptModel:
+		events.EventCreatedScriptModel,
+		events.EventScriptInterrupted:
 		go e.event(message)
 
 	// version
@@ -148,9 +184,27 @@ func (e *eventHandler) eventUserDevices(event webpush.EventUserDevices) {
 }
 
 func (e *eventHandler) eventStateChangedHandler(msg interface{}) {
-	//todo optimize
-	b, _ := json.Marshal(msg)
-	e.broadcast("state_changed", b)
+	var diff *EventStateDiff
+	switch v := msg.(type) {
+	case events.EventStateChanged:
+		diff = NewStateDiff(v.PluginName, v.EntityId, v.OldState, v.NewState)
+	case events.EventLastStateChanged:
+		// the reply to the request of the last state, the client gets the whole state
+		diff = NewStateDiff(v.PluginName, v.EntityId, events.EventEntityState{}, v.NewState)
+	default:
+		return
+	}
+
+	event := &eventMessage{
+		query:    "state_changed",
+		entityId: diff.EntityId.Ptr(),
+		isState:  true,
+	}
+	event.body, _ = json.Marshal(msg)
+	if !diff.IsEmpty() {
+		event.diff, _ = json.Marshal(diff)
+	}
+	e.publish(event)
 }
 
 func (e *eventHandler) eventDirectMessage(userID int64, sessionID string, query string, msg interface{}) {
@@ -159,6 +213,17 @@ func (e *eventHandler) eventDirectMessage(userID int64, sessionID string, query
 }
 
 func (e *eventHandler) event(msg interface{}) {
-	b, _ := json.Marshal(msg)
-	e.broadcast(events.EventName(msg), b)
+	event := &eventMessage{
+		query: events.EventName(msg),
+	}
+	switch v := msg.(type) {
+	case events.EventUpdatedMetric:
+		event.entityId = v.EntityId.Ptr()
+	case events.EventEntityLoaded:
+		event.entityId = v.EntityId.Ptr()
+	case events.EventEntityUnloaded:
+		event.entityId = v.EntityId.Ptr()
+	}
+	event.body, _ = json.Marshal(msg)
+	e.publish(event)
 }
@@ -0,0 +1,195 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package stream
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/e154/smart-home/pkg/common"
+)
+
+// Subscription is the body of the event_subscribe and event_unsubscribe commands
+type Subscription struct {
+	EntityIds []common.EntityId `json:"entity_ids,omitempty"`
+	Plugins   []string          `json:"plugins,omitempty"`
+	Areas     []string          `json:"areas,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Events    []string          `json:"events,omitempty"`
+}
+
+// IsEmpty ...
+func (s Subscription) IsEmpty() bool {
+	return len(s.EntityIds) == 0 && len(s.Plugins) == 0 && len(s.Areas) == 0 &&
+		len(s.Tags) == 0 && len(s.Events) == 0
+}
+
+// EntityInfo is the entity the event is about
+type EntityInfo struct {
+	Id     common.EntityId
+	Area   string
+	AreaId *int64
+	Tags   []string
+}
+
+// Filter keeps the subscriptions of the client.
+// The client without subscriptions receives all events as before,
+// otherwise the event must be of the subscribed type (if any)
+// and about the subscribed entity (if any), the events without the entity pass by the type only.
+type Filter struct {
+	sync.RWMutex
+	entityIds map[common.EntityId]struct{}
+	plugins   map[string]struct{}
+	areas     map[string]struct{}
+	tags      map[string]struct{}
+	events    map[string]struct{}
+}
+
+// NewFilter ...
+func NewFilter() *Filter {
+	return &Filter{
+		entityIds: make(map[common.EntityId]struct{}),
+		plugins:   make(map[string]struct{}),
+		areas:     make(map[string]struct{}),
+		tags:      make(map[string]struct{}),
+		events:    make(map[string]struct{}),
+	}
+}
+
+// Subscribe ...
+func (f *Filter) Subscribe(sub Subscription) {
+	f.Lock()
+	defer f.Unlock()
+	for _, id := range sub.EntityIds {
+		f.entityIds[id] = struct{}{}
+	}
+	add(f.plugins, sub.Plugins)
+	add(f.areas, sub.Areas)
+	add(f.tags, sub.Tags)
+	add(f.events, sub.Events)
+}
+
+// Unsubscribe removes the items of the subscription, the empty subscription removes everything
+func (f *Filter) Unsubscribe(sub Subscription) {
+	f.Lock()
+	defer f.Unlock()
+	if sub.IsEmpty() {
+		f.entityIds = make(map[common.EntityId]struct{})
+		f.plugins = make(map[string]struct{})
+		f.areas = make(map[string]struct{})
+		f.tags = make(map[string]struct{})
+		f.events = make(map[string]struct{})
+		return
+	}
+	for _, id := range sub.EntityIds {
+		delete(f.entityIds, id)
+	}
+	remove(f.plugins, sub.Plugins)
+	remove(f.areas, sub.Areas)
+	remove(f.tags, sub.Tags)
+	remove(f.events, sub.Events)
+}
+
+// Subscription returns the current subscriptions
+func (f *Filter) Subscription() (sub Subscription) {
+	f.RLock()
+	defer f.RUnlock()
+	for id := range f.entityIds {
+		sub.EntityIds = append(sub.EntityIds, id)
+	}
+	sort.Slice(sub.EntityIds, func(i, j int) bool {
+		return sub.EntityIds[i] < sub.EntityIds[j]
+	})
+	sub.Plugins = keys(f.plugins)
+	sub.Areas = keys(f.areas)
+	sub.Tags = keys(f.tags)
+	sub.Events = keys(f.events)
+	return
+}
+
+// IsEmpty ...
+func (f *Filter) IsEmpty() bool {
+	f.RLock()
+	defer f.RUnlock()
+	return len(f.events) == 0 && !f.hasEntities()
+}
+
+// NeedsEntityInfo the area and the tags of the entity are required to match the event
+func (f *Filter) NeedsEntityInfo() bool {
+	f.RLock()
+	defer f.RUnlock()
+	return len(f.areas) > 0 || len(f.tags) > 0
+}
+
+// Match ...
+func (f *Filter) Match(event string, entity *EntityInfo) bool {
+	f.RLock()
+	defer f.RUnlock()
+
+	if len(f.events) > 0 {
+		if _, ok := f.events[event]; !ok {
+			return false
+		}
+	}
+
+	if entity == nil || !f.hasEntities() {
+		return true
+	}
+
+	if _, ok := f.entityIds[entity.Id]; ok {
+		return true
+	}
+	if _, ok := f.plugins[entity.Id.PluginName()]; ok {
+		return true
+	}
+	if _, ok := f.areas[entity.Area]; ok && entity.Area != "" {
+		return true
+	}
+	for _, tag := range entity.Tags {
+		if _, ok := f.tags[tag]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *Filter) hasEntities() bool {
+	return len(f.entityIds) > 0 || len(f.plugins) > 0 || len(f.areas) > 0 || len(f.tags) > 0
+}
+
+func add(set map[string]struct{}, items []string) {
+	for _, item := range items {
+		set[item] = struct{}{}
+	}
+}
+
+func remove(set map[string]struct{}, items []string) {
+	for _, item := range items {
+		delete(set, item)
+	}
+}
+
+func keys(set map[string]struct{}) (list []string) {
+	for key := range set {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+	return
+}
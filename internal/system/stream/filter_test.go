@@ -0,0 +1,114 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package stream
+
+import (
+	"testing"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+
+	lamp := &EntityInfo{Id: "zigbee2mqtt.lamp", Area: "kitchen", Tags: []string{"light"}}
+	door := &EntityInfo{Id: "sensor.door", Area: "hall"}
+
+	filter := NewFilter()
+	require.True(t, filter.IsEmpty())
+	require.True(t, filter.Match("state_changed", door))
+
+	filter.Subscribe(Subscription{EntityIds: []common.EntityId{"sensor.door"}})
+	require.True(t, filter.Match("state_changed", door))
+	require.False(t, filter.Match("state_changed", lamp))
+	// the events without the entity pass
+	require.True(t, filter.Match("event_task_completed", nil))
+
+	filter.Subscribe(Subscription{Plugins: []string{"zigbee2mqtt"}})
+	require.True(t, filter.Match("state_changed", lamp))
+
+	filter.Unsubscribe(Subscription{Plugins: []string{"zigbee2mqtt"}})
+	require.False(t, filter.Match("state_changed", lamp))
+	require.False(t, filter.NeedsEntityInfo())
+
+	filter.Subscribe(Subscription{Tags: []string{"light"}})
+	require.True(t, filter.NeedsEntityInfo())
+	require.True(t, filter.Match("state_changed", lamp))
+
+	filter.Unsubscribe(Subscription{Tags: []string{"light"}})
+	filter.Subscribe(Subscription{Areas: []string{"kitchen"}})
+	require.True(t, filter.Match("state_changed", lamp))
+
+	filter.Subscribe(Subscription{Events: []string{"state_changed"}})
+	require.True(t, filter.Match("state_changed", door))
+	require.False(t, filter.Match("event_task_completed", nil))
+	require.False(t, filter.Match("state_changed", &EntityInfo{Id: "sensor.window"}))
+
+	require.Equal(t, Subscription{
+		EntityIds: []common.EntityId{"sensor.door"},
+		Areas:     []string{"kitchen"},
+		Events:    []string{"state_changed"},
+	}, filter.Subscription())
+
+	filter.Unsubscribe(Subscription{})
+	require.True(t, filter.IsEmpty())
+}
+
+func TestStateDiff(t *testing.T) {
+
+	oldState := events.EventEntityState{
+		Value: 20,
+		State: &events.EntityState{Name: "ON"},
+		Attributes: m.Attributes{
+			"temperature": {Name: "temperature", Type: common.AttributeFloat, Value: 20.5},
+			"humidity":    {Name: "humidity", Type: common.AttributeInt, Value: 40},
+			"battery":     {Name: "battery", Type: common.AttributeInt, Value: 90},
+		},
+	}
+	newState := events.EventEntityState{
+		Value: 20,
+		State: &events.EntityState{Name: "ON"},
+		Attributes: m.Attributes{
+			"temperature": {Name: "temperature", Type: common.AttributeFloat, Value: 21.0},
+			"humidity":    {Name: "humidity", Type: common.AttributeInt, Value: 40},
+		},
+	}
+
+	diff := NewStateDiff("sensor", "sensor.room", oldState, newState)
+	require.Nil(t, diff.State)
+	require.Nil(t, diff.Value)
+	require.Len(t, diff.Attributes, 1)
+	require.Equal(t, 21.0, diff.Attributes["temperature"].Value)
+	require.Equal(t, []string{"battery"}, diff.Removed)
+
+	diff = NewStateDiff("sensor", "sensor.room", newState, newState)
+	require.True(t, diff.IsEmpty())
+
+	newState.State = &events.EntityState{Name: "OFF"}
+	diff = NewStateDiff("sensor", "sensor.room", oldState, newState)
+	require.Equal(t, "OFF", diff.State.Name)
+
+	// the whole state
+	diff = NewStateDiff("sensor", "sensor.room", events.EventEntityState{}, newState)
+	require.Equal(t, 20, diff.Value)
+	require.Len(t, diff.Attributes, 2)
+}
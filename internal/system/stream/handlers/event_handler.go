@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	webpush2 "github.com/e154/smart-home/internal/plugins/webpush"
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
 	stream2 "github.com/e154/smart-home/internal/system/stream"
 	"github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/events"
@@ -41,16 +42,19 @@ var (
 )
 
 type EventHandler struct {
-	stream   *stream2.Stream
-	eventBus bus.Bus
+	stream       *stream2.Stream
+	eventBus     bus.Bus
+	entityAccess entity_access.EntityAccessService
 }
 
 func NewEventHandler(lc fx.Lifecycle,
 	stream *stream2.Stream,
-	eventBus bus.Bus) *EventHandler {
+	eventBus bus.Bus,
+	entityAccess entity_access.EntityAccessService) *EventHandler {
 	handler := &EventHandler{
-		stream:   stream,
-		eventBus: eventBus,
+		stream:       stream,
+		eventBus:     eventBus,
+		entityAccess: entityAccess,
 	}
 
 	lc.Append(fx.Hook{
@@ -75,6 +79,8 @@ func (s *EventHandler) Start(_ context.Context) error {
 	s.stream.Subscribe("command_terminal", s.CommandTerminal)
 	s.stream.Subscribe("event_get_server_version", s.EventGetServerVersion)
 	s.stream.Subscribe("event_stt", s.EventSTT)
+	s.stream.Subscribe("event_subscribe", s.EventSubscribe)
+	s.stream.Subscribe("event_unsubscribe", s.EventUnsubscribe)
 	return nil
 }
 
@@ -88,6 +94,8 @@ func (s *EventHandler) Shutdown(_ context.Context) error {
 	s.stream.UnSubscribe("command_terminal")
 	s.stream.UnSubscribe("event_get_server_version")
 	s.stream.UnSubscribe("event_stt")
+	s.stream.UnSubscribe("event_subscribe")
+	s.stream.UnSubscribe("event_unsubscribe")
 	return nil
 }
 
@@ -134,6 +142,9 @@ func (s *EventHandler) EventGetLastState(client stream2.IStreamClient, query str
 	req := map[string]common.EntityId{}
 	_ = json.Unmarshal(body, &req)
 	id := req["entity_id"]
+	if !s.canRead(client, id) {
+		return
+	}
 	s.eventBus.Publish("system/entities/"+id.String(), events.EventGetLastState{
 		EntityId: id,
 	})
@@ -142,6 +153,9 @@ func (s *EventHandler) EventGetLastState(client stream2.IStreamClient, query str
 func (s *EventHandler) EventGetStateById(client stream2.IStreamClient, query string, body []byte) {
 	req := events.EventGetStateById{}
 	_ = json.Unmarshal(body, &req)
+	if !s.canRead(client, req.EntityId) {
+		return
+	}
 	s.eventBus.Publish("system/entities/"+req.EntityId.String(), events.EventGetStateById{
 		EntityId:  req.EntityId,
 		StorageId: req.StorageId,
@@ -152,6 +166,15 @@ func (s *EventHandler) EventGetStateById(client stream2.IStreamClient, query str
 	})
 }
 
+func (s *EventHandler) canRead(client stream2.IStreamClient, entityId common.EntityId) bool {
+	err := s.entityAccess.Check(context.Background(), client.GetUser(), entityId, common.EntityAccessRead)
+	if err != nil {
+		log.Warnf("entity %s: %s", entityId, err.Error())
+		return false
+	}
+	return true
+}
+
 func (s *EventHandler) CommandTerminal(client stream2.IStreamClient, query string, body []byte) {
 	s.eventBus.Publish("system/terminal", events.CommandTerminal{
 		Common: events.Common{
@@ -196,3 +219,33 @@ func (s *EventHandler) EventSTT(client stream2.IStreamClient, query string, body
 		Payload: buf,
 	})
 }
+
+// EventSubscribe adds the entities, plugins, areas, tags or event types to the subscriptions of the client,
+// the subscribed client receives only the matching events and the state changes as the diff
+func (s *EventHandler) EventSubscribe(client stream2.IStreamClient, id string, body []byte) {
+	sub := stream2.Subscription{}
+	if err := json.Unmarshal(body, &sub); err != nil {
+		log.Error(err.Error())
+		return
+	}
+	client.Filter().Subscribe(sub)
+	s.sendSubscriptions(client, id)
+}
+
+// EventUnsubscribe removes the items from the subscriptions of the client, the empty body removes all
+func (s *EventHandler) EventUnsubscribe(client stream2.IStreamClient, id string, body []byte) {
+	sub := stream2.Subscription{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &sub); err != nil {
+			log.Error(err.Error())
+			return
+		}
+	}
+	client.Filter().Unsubscribe(sub)
+	s.sendSubscriptions(client, id)
+}
+
+func (s *EventHandler) sendSubscriptions(client stream2.IStreamClient, id string) {
+	b, _ := json.Marshal(client.Filter().Subscription())
+	_ = client.Send(id, "event_subscriptions", b)
+}
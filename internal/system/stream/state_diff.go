@@ -0,0 +1,88 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package stream
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// EventStateDiff is sent to the subscribed clients instead of the whole state,
+// it contains only the fields that differ from the old state
+type EventStateDiff struct {
+	EntityId    common.EntityId     `json:"entity_id"`
+	PluginName  string              `json:"plugin_name"`
+	State       *events.EntityState `json:"state,omitempty"`
+	Value       interface{}         `json:"value,omitempty"`
+	Attributes  m.Attributes        `json:"attributes,omitempty"`
+	Removed     []string            `json:"removed,omitempty"`
+	LastChanged *time.Time          `json:"last_changed,omitempty"`
+	LastUpdated *time.Time          `json:"last_updated,omitempty"`
+}
+
+// IsEmpty ...
+func (d *EventStateDiff) IsEmpty() bool {
+	return d.State == nil && d.Value == nil && len(d.Attributes) == 0 && len(d.Removed) == 0
+}
+
+// NewStateDiff compares the states, the empty old state gives the whole new state
+func NewStateDiff(pluginName string, entityId common.EntityId, oldState, newState events.EventEntityState) *EventStateDiff {
+	diff := &EventStateDiff{
+		EntityId:    entityId,
+		PluginName:  pluginName,
+		LastChanged: newState.LastChanged,
+		LastUpdated: newState.LastUpdated,
+	}
+
+	if newState.State != nil && !reflect.DeepEqual(oldState.State, newState.State) {
+		diff.State = newState.State
+	}
+
+	if !reflect.DeepEqual(oldState.Value, newState.Value) {
+		diff.Value = newState.Value
+	}
+
+	for name, attr := range newState.Attributes {
+		if attr == nil {
+			continue
+		}
+		if old, ok := oldState.Attributes[name]; ok && old != nil &&
+			old.Type == attr.Type && reflect.DeepEqual(old.Value, attr.Value) {
+			continue
+		}
+		if diff.Attributes == nil {
+			diff.Attributes = m.Attributes{}
+		}
+		diff.Attributes[name] = attr
+	}
+
+	for name := range oldState.Attributes {
+		if _, ok := newState.Attributes[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	sort.Strings(diff.Removed)
+
+	return diff
+}
@@ -22,6 +22,9 @@ import (
 	"context"
 	"sync"
 
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common"
 	"github.com/e154/smart-home/pkg/events"
 	"github.com/e154/smart-home/pkg/logger"
 	m "github.com/e154/smart-home/pkg/models"
@@ -44,22 +47,28 @@ var (
 // Stream ...
 type Stream struct {
 	*eventHandler
-	subMx       sync.Mutex
-	subscribers map[string]func(client IStreamClient, id string, msg []byte)
-	sessions    sync.Map
-	eventBus    bus.Bus
+	subMx        sync.Mutex
+	subscribers  map[string]func(client IStreamClient, id string, msg []byte)
+	sessions     sync.Map
+	eventBus     bus.Bus
+	entities     *entityCache
+	entityAccess entity_access.EntityAccessService
 }
 
 // NewStreamService ...
 func NewStreamService(lc fx.Lifecycle,
-	eventBus bus.Bus) (s *Stream) {
+	eventBus bus.Bus,
+	adaptors *adaptors.Adaptors,
+	entityAccess entity_access.EntityAccessService) (s *Stream) {
 	s = &Stream{
-		subscribers: make(map[string]func(client IStreamClient, id string, msg []byte)),
-		sessions:    sync.Map{},
-		eventBus:    eventBus,
+		subscribers:  make(map[string]func(client IStreamClient, id string, msg []byte)),
+		sessions:     sync.Map{},
+		eventBus:     eventBus,
+		entities:     newEntityCache(adaptors),
+		entityAccess: entityAccess,
 	}
 
-	s.eventHandler = NewEventHandler(s.Broadcast, s.DirectMessage)
+	s.eventHandler = NewEventHandler(s.Broadcast, s.DirectMessage, s.publish, s.entities.Delete, s.closeSessions)
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) (err error) {
@@ -102,6 +111,57 @@ func (s *Stream) Broadcast(query string, message []byte) {
 	})
 }
 
+// publish sends the event to the clients whose subscriptions match the event
+// and who may read the entity of the event
+func (s *Stream) publish(msg *eventMessage) {
+	s.sessions.Range(func(key, value interface{}) bool {
+		cli := value.(*Client)
+		if msg.entityId != nil && !s.canRead(cli, *msg.entityId) {
+			return true
+		}
+
+		filter := cli.Filter()
+		if filter.IsEmpty() {
+			_ = cli.Send(uuid.NewString(), msg.query, msg.body)
+			return true
+		}
+
+		var entity *EntityInfo
+		if msg.entityId != nil {
+			if filter.NeedsEntityInfo() {
+				entity = s.entities.Get(*msg.entityId)
+			} else {
+				entity = &EntityInfo{Id: *msg.entityId}
+			}
+		}
+		if !filter.Match(msg.query, entity) {
+			return true
+		}
+
+		if !msg.isState {
+			_ = cli.Send(uuid.NewString(), msg.query, msg.body)
+			return true
+		}
+		if msg.diff != nil {
+			_ = cli.Send(uuid.NewString(), "state_diff", msg.diff)
+		}
+		return true
+	})
+}
+
+// canRead ...
+func (s *Stream) canRead(cli *Client, entityId common.EntityId) bool {
+	if s.entityAccess == nil {
+		return true
+	}
+	rules := s.entityAccess.Rules(context.Background(), cli.user)
+	if rules == nil {
+		return true
+	}
+	entity := s.entities.Get(entityId)
+	return rules.Level(entityId, entity.AreaId, entity.Tags).Includes(common.EntityAccessRead)
+}
+
 // DirectMessage ...
 func (s *Stream) DirectMessage(userID int64, sessionID string, query string, message []byte) {
 	s.sessions.Range(func(key, value interface{}) bool {
@@ -120,6 +180,21 @@ func (s *Stream) DirectMessage(userID int64, sessionID string, query string, mes
 	})
 }
 
+// closeSessions closes the connections opened with the tokens of the revoked session
+func (s *Stream) closeSessions(userId, sessionId int64) {
+	s.sessions.Range(func(key, value interface{}) bool {
+		cli := value.(*Client)
+		if cli.user == nil || cli.user.Id != userId {
+			return true
+		}
+		if sessionId == 0 || cli.authSessionId == sessionId {
+			log.Infof("websocket session closed, the session %d of user %d is revoked", cli.authSessionId, userId)
+			cli.Close()
+		}
+		return true
+	})
+}
+
 // Subscribe ...
 func (s *Stream) Subscribe(command string, f func(IStreamClient, string, []byte)) {
 	log.Infof("subscribe %s", command)
@@ -143,10 +218,11 @@ func (s *Stream) UnSubscribe(command string) {
 }
 
 // NewConnection ...
-func (s *Stream) NewConnection(ws *websocket.Conn, user *m.User) {
+func (s *Stream) NewConnection(ws *websocket.Conn, user *m.User, authSessionId int64) {
 
 	id := uuid.NewString()
 	client := NewClient(ws, user, id)
+	client.authSessionId = authSessionId
 	defer func() {
 		log.Infof("websocket session closed, email: '%s'", user.Email)
 		s.sessions.Delete(id)
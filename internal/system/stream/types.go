@@ -37,6 +37,7 @@ type IStreamClient interface {
 	GetUser() *m.User
 	SessionID() string
 	Send(id string, query string, body []byte) error
+	Filter() *Filter
 }
 
 type Message struct {
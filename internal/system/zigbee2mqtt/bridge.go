@@ -26,6 +26,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/e154/bus"
+	"go.uber.org/atomic"
+
 	"github.com/e154/smart-home/internal/common"
 	"github.com/e154/smart-home/pkg/adaptors"
 	"github.com/e154/smart-home/pkg/apperr"
@@ -37,6 +40,7 @@ import (
 // Bridge ...
 type Bridge struct {
 	adaptors       *adaptors.Adaptors
+	eventBus       bus.Bus
 	mqtt           mqtt.MqttServ
 	mqttClient     mqtt.MqttCli
 	isStarted      bool
@@ -51,17 +55,28 @@ type Bridge struct {
 	scanInProcess  bool
 	lastScan       *time.Time
 	networkmap     string
+	pendingLock    sync.Mutex
+	pending        map[string]chan BridgeResponse
+	transaction    atomic.Int64
+	requestTimeout time.Duration
+	otaLock        sync.Mutex
+	otaInProgress  map[string]bool
 }
 
 // NewBridge ...
 func NewBridge(mqtt mqtt.MqttServ,
 	adaptors *adaptors.Adaptors,
+	eventBus bus.Bus,
 	model *models.Zigbee2mqtt) *Bridge {
 	return &Bridge{
-		adaptors: adaptors,
-		devices:  make(map[string]*Device),
-		model:    model,
-		mqtt:     mqtt,
+		adaptors:       adaptors,
+		eventBus:       eventBus,
+		devices:        make(map[string]*Device),
+		model:          model,
+		mqtt:           mqtt,
+		pending:        make(map[string]chan BridgeResponse),
+		requestTimeout: DefaultRequestTimeout,
+		otaInProgress:  make(map[string]bool),
 	}
 }
 
@@ -104,72 +119,60 @@ func (g *Bridge) Stop(ctx context.Context) {
 
 func (g *Bridge) onBridgePublish(client mqtt.MqttCli, message mqtt.Message) {
 
-	var topic = strings.Split(message.Topic, "/")
+	var topic = strings.SplitN(strings.TrimPrefix(message.Topic, g.topic("/bridge/")), "/", 2)
 
-	switch topic[2] {
+	switch topic[0] {
 	case "state":
 		g.onBridgeStatePublish(client, message)
 	case "log", "logging":
 		g.onLogPublish(client, message)
-	case "config":
-		g.onConfigPublish(client, message)
-	case "networkmap":
-		g.onNetworkmapPublish(client, message)
+	case "info":
+		g.onInfoPublish(client, message)
 	case "devices":
 		g.onDevices(client, message)
+	case "groups":
+		g.onGroups(client, message)
 	case "event":
 		g.onEvent(client, message)
+	case "response":
+		g.onResponsePublish(client, message)
+	case "request", "extensions", "definitions", "converters":
+		// own requests and the frontend topics
 	default:
 		log.Warnf("unknown topic %v", message.Topic)
 	}
 }
 
 func (g *Bridge) onBridgeStatePublish(client mqtt.MqttCli, message mqtt.Message) {
+	state := string(message.Payload)
+	// the current versions publish {"state":"online"}
+	if strings.HasPrefix(state, "{") {
+		payload := struct {
+			State string `json:"state"`
+		}{}
+		_ = json.Unmarshal(message.Payload, &payload)
+		state = payload.State
+	}
 	g.settingsLock.Lock()
-	g.state = string(message.Payload)
+	g.state = state
 	g.settingsLock.Unlock()
 }
 
-func (g *Bridge) onNetworkmapPublish(client mqtt.MqttCli, message mqtt.Message) {
-
-	var topic = strings.Split(message.Topic, "/")
-
-	if len(topic) < 4 {
-		return
-	}
-
-	switch topic[3] {
-	case "raw":
-		log.Info("method not implemented")
-	case "graphviz":
-		g.networkmapLock.Lock()
-		g.scanInProcess = false
-		g.lastScan = pkgCommon.Time(time.Now())
-		g.networkmap = string(message.Payload)
-		g.networkmapLock.Unlock()
-	}
-}
-
-func (g *Bridge) onConfigPublish(client mqtt.MqttCli, message mqtt.Message) {
-
-	var topic = strings.Split(message.Topic, "/")
-
-	if len(topic) > 3 {
-		switch topic[3] {
-		case "devices":
-			g.onConfigDevicesPublish(client, message)
-			return
-		}
-	}
-
+func (g *Bridge) onInfoPublish(client mqtt.MqttCli, message mqtt.Message) {
 	config := BridgeConfig{}
-	_ = json.Unmarshal(message.Payload, &config)
+	if err := json.Unmarshal(message.Payload, &config); err != nil {
+		log.Warn(err.Error())
+	}
 	g.settingsLock.Lock()
 	g.config = config
 	g.settingsLock.Unlock()
 }
 
-func (g *Bridge) onConfigDevicesPublish(client mqtt.MqttCli, message mqtt.Message) {}
+func (g *Bridge) getConfig() BridgeConfig {
+	g.settingsLock.Lock()
+	defer g.settingsLock.Unlock()
+	return g.config
+}
 
 func (g *Bridge) safeGetDevice(friendlyName string) (device *Device, err error) {
 
@@ -211,6 +214,29 @@ func (g *Bridge) safeUpdateDevice(device *Device) (err error) {
 
 	g.devices[device.friendlyName] = device
 
+	g.unsafeUpdateModelDevices()
+
+	//todo add metric ...
+
+	return
+}
+
+func (g *Bridge) safeRemoveDevice(friendlyName string) (err error) {
+	g.devicesLock.Lock()
+	defer g.devicesLock.Unlock()
+
+	if err = g.adaptors.Zigbee2mqttDevice.Delete(context.Background(), friendlyName); err != nil {
+		return
+	}
+
+	delete(g.devices, friendlyName)
+
+	g.unsafeUpdateModelDevices()
+
+	return
+}
+
+func (g *Bridge) unsafeUpdateModelDevices() {
 	//TODO optimize
 	g.modelLock.Lock()
 	g.model.Devices = make([]*models.Zigbee2mqttDevice, len(g.devices))
@@ -220,10 +246,6 @@ func (g *Bridge) safeUpdateDevice(device *Device) (err error) {
 		i++
 	}
 	g.modelLock.Unlock()
-
-	//todo add metric ...
-
-	return
 }
 
 func (g *Bridge) safeGetDeviceList() (err error) {
@@ -268,56 +290,109 @@ func (g *Bridge) getState() string {
 	return g.state
 }
 
-// get config
-func (g *Bridge) getConfig() {
-	_ = g.mqttClient.Publish(g.topic("/bridge/config/get"), []byte{})
+func (g *Bridge) configPermitJoin(tr bool) {
+	g.send("permit_join", map[string]interface{}{
+		"value": tr,
+	})
 }
 
-// get device list
-func (g *Bridge) getDevices() {
-	_ = g.mqttClient.Publish(g.topic("/bridge/config/devices/get"), []byte{})
+func (g *Bridge) configOptions(ctx context.Context, options map[string]interface{}) (err error) {
+	_, err = g.request(ctx, "options", map[string]interface{}{
+		"options": options,
+	}, g.requestTimeout)
+	return
 }
 
-func (g *Bridge) configPermitJoin(tr bool) {
-	var permitJoin = "true"
-	if !tr {
-		permitJoin = "false"
-	}
-	_ = g.mqttClient.Publish(g.topic("/bridge/config/permit_join"), []byte(permitJoin))
+// the format of the last_seen attribute: disable|ISO_8601|ISO_8601_local|epoch
+func (g *Bridge) configLastSeen(ctx context.Context, format string) error {
+	return g.configOptions(ctx, map[string]interface{}{
+		"advanced": map[string]interface{}{"last_seen": format},
+	})
 }
 
-func (g *Bridge) configLastSeen() {}
-func (g *Bridge) configElapsed()  {}
+func (g *Bridge) configElapsed(ctx context.Context, elapsed bool) error {
+	return g.configOptions(ctx, map[string]interface{}{
+		"advanced": map[string]interface{}{"elapsed": elapsed},
+	})
+}
+
+func (g *Bridge) configLogLevel(ctx context.Context, level string) error {
+	return g.configOptions(ctx, map[string]interface{}{
+		"advanced": map[string]interface{}{"log_level": level},
+	})
+}
+
+// Restart restarts zigbee2mqtt
+func (g *Bridge) Restart(ctx context.Context) (err error) {
+	_, err = g.request(ctx, "restart", nil, g.requestTimeout)
+	return
+}
 
-// Resets the ZNP (CC2530/CC2531).
-func (g *Bridge) configReset() {
-	_ = g.mqttClient.Publish(g.topic("/bridge/config/reset"), []byte{})
+// DeviceOptions changes the options of the device, e.g. {"transition": 1}
+func (g *Bridge) DeviceOptions(ctx context.Context, friendlyName string, options map[string]interface{}) (err error) {
+	_, err = g.request(ctx, "device/options", map[string]interface{}{
+		"id":      friendlyName,
+		"options": options,
+	}, g.requestTimeout)
+	return
 }
 
-// ConfigReset ...
-func (g *Bridge) ConfigReset() {
-	g.configReset()
-	time.Sleep(time.Second * 5)
+// DeviceBind binds the clusters of the device to the other device or group, all clusters if the list is empty
+func (g *Bridge) DeviceBind(ctx context.Context, from, to string, clusters []string) (err error) {
+	_, err = g.request(ctx, "device/bind", bindPayload(from, to, clusters), g.requestTimeout)
+	return
 }
 
-func (g *Bridge) configLogLevel() {}
+// DeviceUnbind ...
+func (g *Bridge) DeviceUnbind(ctx context.Context, from, to string, clusters []string) (err error) {
+	_, err = g.request(ctx, "device/unbind", bindPayload(from, to, clusters), g.requestTimeout)
+	return
+}
 
-// DeviceOptions ...
-func (g *Bridge) DeviceOptions() {}
+func bindPayload(from, to string, clusters []string) map[string]interface{} {
+	payload := map[string]interface{}{
+		"from": from,
+		"to":   to,
+	}
+	if len(clusters) > 0 {
+		payload["clusters"] = clusters
+	}
+	return payload
+}
 
 // Remove ...
-func (g *Bridge) Remove(friendlyName string) {
-	_ = g.mqttClient.Publish(g.topic("/bridge/config/remove"), []byte(friendlyName))
+func (g *Bridge) Remove(ctx context.Context, friendlyName string) (err error) {
+	_, err = g.request(ctx, "device/remove", map[string]interface{}{
+		"id": friendlyName,
+	}, g.requestTimeout)
+	return
 }
 
-// Ban ...
-func (g *Bridge) Ban(friendlyName string) {
-	_ = g.mqttClient.Publish(g.topic("/bridge/config/force_remove"), []byte(friendlyName))
+// Ban removes the device and adds it to the blocklist
+func (g *Bridge) Ban(ctx context.Context, friendlyName string) (err error) {
+	if _, err = g.request(ctx, "device/remove", map[string]interface{}{
+		"id":    friendlyName,
+		"force": true,
+		"block": true,
+	}, g.requestTimeout); err != nil {
+		return
+	}
+	g.deviceForceRemoved(friendlyName)
+	return
 }
 
-// Whitelist ...
-func (g *Bridge) Whitelist(friendlyName string) {
-	_ = g.mqttClient.Publish(g.topic("/bridge/config/whitelist"), []byte(friendlyName))
+// Whitelist adds the device to the passlist
+func (g *Bridge) Whitelist(ctx context.Context, friendlyName string) (err error) {
+	passlist := g.getConfig().Config.Passlist
+	for _, name := range passlist {
+		if name == friendlyName {
+			return
+		}
+	}
+	err = g.configOptions(ctx, map[string]interface{}{
+		"passlist": append(passlist, friendlyName),
+	})
+	return
 }
 
 // RenameDevice ...
@@ -335,14 +410,14 @@ func (g *Bridge) RenameDevice(friendlyName, name string) (err error) {
 	return
 }
 
-// RenameLast ...
-func (g *Bridge) RenameLast() {}
-
-// AddGroup ...
-func (g *Bridge) AddGroup() {}
-
-// RemoveGroup ...
-func (g *Bridge) RemoveGroup() {}
+// RenameLast renames the last joined device
+func (g *Bridge) RenameLast(ctx context.Context, name string) (err error) {
+	_, err = g.request(ctx, "device/rename", map[string]interface{}{
+		"last": true,
+		"to":   name,
+	}, g.requestTimeout)
+	return
+}
 
 // UpdateNetworkmap ...
 func (g *Bridge) UpdateNetworkmap() {
@@ -354,7 +429,28 @@ func (g *Bridge) UpdateNetworkmap() {
 	}
 	g.scanInProcess = true
 
-	_ = g.mqttClient.Publish(g.topic("/bridge/networkmap"), []byte("graphviz"))
+	go func() {
+		data, err := g.request(context.Background(), "networkmap", map[string]interface{}{
+			"type":   "graphviz",
+			"routes": false,
+		}, networkmapTimeout)
+
+		g.networkmapLock.Lock()
+		defer g.networkmapLock.Unlock()
+
+		g.scanInProcess = false
+		if err != nil {
+			log.Error(err.Error())
+			return
+		}
+
+		networkmap := struct {
+			Value string `json:"value"`
+		}{}
+		_ = json.Unmarshal(data, &networkmap)
+		g.lastScan = pkgCommon.Time(time.Now())
+		g.networkmap = networkmap.Value
+	}()
 }
 
 // Networkmap ...
@@ -382,6 +478,9 @@ func (g *Bridge) deviceLeave(friendlyName string) {
 	if err = g.safeUpdateDevice(device); err != nil {
 		log.Error(err.Error())
 	}
+	if g.autoDiscovery() {
+		g.removeDiscoveredEntity(friendlyName)
+	}
 }
 
 func (g *Bridge) deviceJoined(friendlyName string) {
@@ -451,6 +550,11 @@ func (g *Bridge) updateDevice(params DeviceInfo) {
 	device := NewDevice(params.FriendlyName, model)
 	if err := g.safeUpdateDevice(device); err != nil {
 		log.Error(err.Error())
+		return
+	}
+
+	if g.autoDiscovery() {
+		g.discoverDevice(params)
 	}
 }
 
@@ -476,6 +580,7 @@ func (g *Bridge) UpdateModel(model *models.Zigbee2mqtt) {
 	g.model.Login = model.Login
 	g.model.EncryptedPassword = model.EncryptedPassword
 	g.model.PermitJoin = model.PermitJoin
+	g.model.AutoDiscovery = model.AutoDiscovery
 
 	g.configPermitJoin(g.model.PermitJoin)
 }
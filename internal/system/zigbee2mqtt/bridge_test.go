@@ -0,0 +1,225 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package zigbee2mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DrmagicE/gmqtt/server"
+	"github.com/e154/bus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/events"
+	"github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/mqtt"
+)
+
+// fakeMqttCli delivers the published messages to the subscribers, the requests of the bridge are answered by the respond func
+type fakeMqttCli struct {
+	sync.Mutex
+	handlers map[string]mqtt.MessageHandler
+	requests map[string]map[string]interface{}
+	respond  func(cli *fakeMqttCli, command string, payload map[string]interface{})
+}
+
+func newFakeMqttCli() *fakeMqttCli {
+	return &fakeMqttCli{
+		handlers: make(map[string]mqtt.MessageHandler),
+		requests: make(map[string]map[string]interface{}),
+	}
+}
+
+func (f *fakeMqttCli) Publish(topic string, payload []byte) error {
+	if command, ok := strings.CutPrefix(topic, "zigbee2mqtt/bridge/request/"); ok {
+		request := make(map[string]interface{})
+		_ = json.Unmarshal(payload, &request)
+		f.Lock()
+		f.requests[command] = request
+		f.Unlock()
+		if f.respond != nil {
+			go f.respond(f, command, request)
+		}
+		return nil
+	}
+	f.deliver(topic, payload)
+	return nil
+}
+
+func (f *fakeMqttCli) deliver(topic string, payload []byte) {
+	f.Lock()
+	var handlers []mqtt.MessageHandler
+	for filter, handler := range f.handlers {
+		if filter == topic || (strings.HasSuffix(filter, "#") && strings.HasPrefix(topic, strings.TrimSuffix(filter, "#"))) {
+			handlers = append(handlers, handler)
+		}
+	}
+	f.Unlock()
+	for _, handler := range handlers {
+		handler(f, mqtt.Message{Topic: topic, Payload: payload})
+	}
+}
+
+func (f *fakeMqttCli) reply(command string, request map[string]interface{}, status string, data interface{}) {
+	b, _ := json.Marshal(data)
+	payload, _ := json.Marshal(BridgeResponse{
+		Data:        b,
+		Status:      status,
+		Transaction: request["transaction"].(string),
+	})
+	f.deliver("zigbee2mqtt/bridge/response/"+command, payload)
+}
+
+func (f *fakeMqttCli) request(command string) map[string]interface{} {
+	f.Lock()
+	defer f.Unlock()
+	return f.requests[command]
+}
+
+func (f *fakeMqttCli) Subscribe(topic string, handler mqtt.MessageHandler) error {
+	f.Lock()
+	f.handlers[topic] = handler
+	f.Unlock()
+	return nil
+}
+
+func (f *fakeMqttCli) Unsubscribe(topic string) {
+	f.Lock()
+	delete(f.handlers, topic)
+	f.Unlock()
+}
+
+func (f *fakeMqttCli) UnsubscribeAll() {
+	f.Lock()
+	f.handlers = make(map[string]mqtt.MessageHandler)
+	f.Unlock()
+}
+
+func (f *fakeMqttCli) OnMsgArrived(context.Context, server.Client, *server.MsgArrivedRequest) {}
+
+func newTestBridge(eventBus bus.Bus) (*Bridge, *fakeMqttCli) {
+	cli := newFakeMqttCli()
+	bridge := NewBridge(nil, nil, eventBus, &models.Zigbee2mqtt{Id: 1, BaseTopic: "zigbee2mqtt"})
+	bridge.mqttClient = cli
+	bridge.requestTimeout = time.Millisecond * 200
+	_ = cli.Subscribe("zigbee2mqtt/bridge/#", bridge.onBridgePublish)
+	return bridge, cli
+}
+
+func TestBridge_Request(t *testing.T) {
+
+	bridge, cli := newTestBridge(bus.NewBus())
+	cli.respond = func(cli *fakeMqttCli, command string, request map[string]interface{}) {
+		switch command {
+		case "device/ota_update/check":
+			// the response of the other client is ignored
+			cli.reply(command, map[string]interface{}{"transaction": "other"}, "ok", map[string]interface{}{"update_available": false})
+			cli.reply(command, request, "ok", map[string]interface{}{"id": request["id"], "update_available": true})
+		case "device/options":
+			cli.reply(command, request, "error", nil)
+		}
+	}
+
+	updateAvailable, err := bridge.OtaCheck(context.Background(), "lamp")
+	require.NoError(t, err)
+	require.True(t, updateAvailable)
+	require.Equal(t, "lamp", cli.request("device/ota_update/check")["id"])
+
+	err = bridge.DeviceOptions(context.Background(), "lamp", map[string]interface{}{"transition": 1})
+	require.ErrorIs(t, err, apperr.ErrZigbee2mqttRequest)
+	require.Equal(t, map[string]interface{}{"transition": float64(1)}, cli.request("device/options")["options"])
+
+	// no response
+	err = bridge.DeviceBind(context.Background(), "switch", "lamp", nil)
+	require.ErrorIs(t, err, apperr.ErrZigbee2mqttTimeout)
+	require.Equal(t, "switch", cli.request("device/bind")["from"])
+	require.NotContains(t, cli.request("device/bind"), "clusters")
+
+	bridge.pendingLock.Lock()
+	require.Empty(t, bridge.pending)
+	bridge.pendingLock.Unlock()
+}
+
+func TestBridge_Info(t *testing.T) {
+
+	bridge, cli := newTestBridge(bus.NewBus())
+	cli.respond = func(cli *fakeMqttCli, command string, request map[string]interface{}) {
+		cli.reply(command, request, "ok", map[string]interface{}{})
+	}
+
+	cli.deliver("zigbee2mqtt/bridge/state", []byte(`{"state":"online"}`))
+	require.Equal(t, "online", bridge.getState())
+
+	cli.deliver("zigbee2mqtt/bridge/info", []byte(`{"version":"1.35.0","permit_join":true,"config":{"passlist":["lamp"]}}`))
+	require.Equal(t, "1.35.0", bridge.getConfig().Version)
+
+	require.NoError(t, bridge.Whitelist(context.Background(), "switch"))
+	options := cli.request("options")["options"].(map[string]interface{})
+	require.Equal(t, []interface{}{"lamp", "switch"}, options["passlist"])
+}
+
+func TestBridge_OtaUpdate(t *testing.T) {
+
+	eventBus := bus.NewBus()
+	bridge, cli := newTestBridge(eventBus)
+	bridge.devices["lamp"] = NewDevice("lamp", &models.Zigbee2mqttDevice{Id: "lamp"})
+
+	cli.respond = func(cli *fakeMqttCli, command string, request map[string]interface{}) {
+		if command != "device/ota_update/update" {
+			return
+		}
+		cli.deliver("zigbee2mqtt/lamp", []byte(`{"state":"ON","update":{"state":"updating","progress":42.5,"remaining":120}}`))
+		cli.reply(command, request, "ok", map[string]interface{}{"id": "lamp"})
+	}
+
+	progress := make(chan events.EventZigbee2mqttOtaProgress, 1)
+	finished := make(chan events.EventZigbee2mqttOtaFinished, 1)
+	_ = eventBus.Subscribe("system/services/zigbee2mqtt", func(_ string, msg interface{}) {
+		switch v := msg.(type) {
+		case events.EventZigbee2mqttOtaProgress:
+			progress <- v
+		case events.EventZigbee2mqttOtaFinished:
+			finished <- v
+		}
+	})
+
+	require.ErrorIs(t, bridge.OtaUpdate(context.Background(), "switch"), apperr.ErrNotFound)
+	require.NoError(t, bridge.OtaUpdate(context.Background(), "lamp"))
+
+	select {
+	case event := <-progress:
+		require.Equal(t, "updating", event.State)
+		require.Equal(t, 42.5, event.Progress)
+		require.Equal(t, int64(120), event.Remaining)
+	case <-time.After(time.Second):
+		t.Fatal("no progress event")
+	}
+
+	select {
+	case event := <-finished:
+		require.Equal(t, "lamp", event.FriendlyName)
+		require.Empty(t, event.Error)
+	case <-time.After(time.Second):
+		t.Fatal("no finished event")
+	}
+}
@@ -0,0 +1,349 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package zigbee2mqtt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+const (
+	// DiscoveredSetting marks the entities created by the bridge, only these entities are updated and removed by the bridge
+	DiscoveredSetting = "discovered"
+)
+
+const (
+	// ExposeBinary ...
+	ExposeBinary = "binary"
+	// ExposeNumeric ...
+	ExposeNumeric = "numeric"
+	// ExposeEnum ...
+	ExposeEnum = "enum"
+	// ExposeText ...
+	ExposeText = "text"
+	// ExposeComposite the object value, e.g. color_xy
+	ExposeComposite = "composite"
+)
+
+const (
+	// the access bits of the expose
+	accessState = 1
+	accessSet   = 2
+)
+
+// Features returns the exposes with the plain values, the features of the light, switch, climate and other
+// specific exposes are flattened
+func (d EventDeviceInfoDef) Features() (features []EventDeviceInfoDefExpose) {
+	var exist = make(map[string]struct{})
+	var walk func(exposes []EventDeviceInfoDefExpose)
+	walk = func(exposes []EventDeviceInfoDefExpose) {
+		for _, expose := range exposes {
+			switch expose.Type {
+			case ExposeBinary, ExposeNumeric, ExposeEnum, ExposeText:
+				if expose.Property == "" {
+					continue
+				}
+				if _, ok := exist[expose.Property]; ok {
+					continue
+				}
+				exist[expose.Property] = struct{}{}
+				features = append(features, expose)
+			case ExposeComposite:
+			default:
+				walk(expose.Features)
+			}
+		}
+	}
+	walk(d.Exposes)
+	return
+}
+
+// ActionPayload returns the payload of the <device>/set topic for the action of the discovered entity
+func (d EventDeviceInfoDef) ActionPayload(action string, args map[string]interface{}) (payload map[string]interface{}, ok bool) {
+	for _, feature := range d.Features() {
+		if feature.Access&accessSet == 0 {
+			continue
+		}
+		switch feature.Type {
+		case ExposeBinary:
+			for suffix, value := range binaryValues(feature) {
+				if action == binaryActionName(feature.Property, suffix) {
+					return map[string]interface{}{feature.Property: value}, true
+				}
+			}
+		default:
+			if action == "set_"+feature.Property {
+				value, exist := args["value"]
+				if !exist {
+					return nil, false
+				}
+				return map[string]interface{}{feature.Property: value}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func binaryValues(feature EventDeviceInfoDefExpose) map[string]interface{} {
+	values := make(map[string]interface{})
+	if feature.ValueOn != nil {
+		values["on"] = feature.ValueOn
+	}
+	if feature.ValueOff != nil {
+		values["off"] = feature.ValueOff
+	}
+	if feature.ValueToggle != nil {
+		values["toggle"] = feature.ValueToggle
+	}
+	return values
+}
+
+// the actions of the main switch are "on", "off" and "toggle", the others are prefixed with the property
+func binaryActionName(property, suffix string) string {
+	if property == "state" {
+		return suffix
+	}
+	return property + "_" + suffix
+}
+
+// DiscoveredEntity builds the entity of the device with the attributes, states and actions from the exposes
+func DiscoveredEntity(info DeviceInfo) (entity *m.Entity) {
+
+	entityId := common.EntityId(fmt.Sprintf("%s.%s", pluginName, info.FriendlyName))
+
+	description := info.Definition.Description
+	if description == "" {
+		description = info.FriendlyName
+	}
+
+	entity = &m.Entity{
+		Id:          entityId,
+		Description: description,
+		PluginName:  pluginName,
+		AutoLoad:    true,
+		Attributes:  m.Attributes{},
+		Settings: m.Attributes{
+			DiscoveredSetting: {
+				Name:  DiscoveredSetting,
+				Type:  common.AttributeBool,
+				Value: true,
+			},
+		},
+	}
+
+	for _, feature := range info.Definition.Features() {
+
+		attr := &m.Attribute{
+			Name: feature.Property,
+			Type: common.AttributeString,
+		}
+		switch feature.Type {
+		case ExposeBinary:
+			if _, ok := feature.ValueOn.(bool); ok {
+				attr.Type = common.AttributeBool
+			}
+		case ExposeNumeric:
+			attr.Type = common.AttributeFloat
+		}
+		entity.Attributes[feature.Property] = attr
+
+		// the states of the main switch
+		if feature.Type == ExposeBinary && feature.Property == "state" {
+			for _, value := range []interface{}{feature.ValueOn, feature.ValueOff} {
+				if name, ok := value.(string); ok {
+					entity.States = append(entity.States, &m.EntityState{
+						Name:        name,
+						Description: strings.ToLower(name),
+						EntityId:    entityId,
+					})
+				}
+			}
+		}
+
+		if feature.Access&accessSet == 0 {
+			continue
+		}
+
+		switch feature.Type {
+		case ExposeBinary:
+			for _, suffix := range []string{"on", "off", "toggle"} {
+				if _, ok := binaryValues(feature)[suffix]; !ok {
+					continue
+				}
+				entity.Actions = append(entity.Actions, &m.EntityAction{
+					Name:        binaryActionName(feature.Property, suffix),
+					Description: fmt.Sprintf("%s %s", feature.Property, suffix),
+					EntityId:    entityId,
+				})
+			}
+		default:
+			entity.Actions = append(entity.Actions, &m.EntityAction{
+				Name:        "set_" + feature.Property,
+				Description: feature.Description,
+				EntityId:    entityId,
+			})
+		}
+	}
+
+	return
+}
+
+// MergeDiscoveredEntity adds to the existing entity the attributes, actions and states which it does not have yet,
+// the items edited by the user are kept as is
+func MergeDiscoveredEntity(entity, discovered *m.Entity) (changed bool, actions []*m.EntityAction, states []*m.EntityState) {
+
+	if entity.Attributes == nil {
+		entity.Attributes = m.Attributes{}
+	}
+	for name, attr := range discovered.Attributes {
+		if _, ok := entity.Attributes[name]; !ok {
+			entity.Attributes[name] = attr
+			changed = true
+		}
+	}
+
+	var exist = make(map[string]struct{})
+	for _, action := range entity.Actions {
+		exist[action.Name] = struct{}{}
+	}
+	for _, action := range discovered.Actions {
+		if _, ok := exist[action.Name]; !ok {
+			actions = append(actions, action)
+		}
+	}
+
+	exist = make(map[string]struct{})
+	for _, state := range entity.States {
+		exist[state.Name] = struct{}{}
+	}
+	for _, state := range discovered.States {
+		if _, ok := exist[state.Name]; !ok {
+			states = append(states, state)
+		}
+	}
+
+	return
+}
+
+// IsDiscovered ...
+func IsDiscovered(entity *m.Entity) bool {
+	if entity == nil || entity.Settings == nil {
+		return false
+	}
+	attr, ok := entity.Settings[DiscoveredSetting]
+	return ok && attr.Bool()
+}
+
+func validDiscoveryName(friendlyName string) bool {
+	return friendlyName != "" && !strings.ContainsAny(friendlyName, " ./")
+}
+
+func (g *Bridge) autoDiscovery() bool {
+	g.modelLock.Lock()
+	defer g.modelLock.Unlock()
+	return g.model.AutoDiscovery
+}
+
+// discoverDevice creates or updates the entity of the device
+func (g *Bridge) discoverDevice(info DeviceInfo) {
+
+	if !validDiscoveryName(info.FriendlyName) {
+		log.Warnf("device \"%s\" is skipped by the discovery, the name contains spaces, dots or slashes", info.FriendlyName)
+		return
+	}
+
+	ctx := context.Background()
+	discovered := DiscoveredEntity(info)
+
+	entity, err := g.adaptors.Entity.GetById(ctx, discovered.Id)
+	if err != nil {
+		if err = g.adaptors.Entity.Add(ctx, discovered); err != nil {
+			log.Error(err.Error())
+			return
+		}
+		log.Infof("added new entity id:(%s)", discovered.Id)
+		g.eventBus.Publish("system/models/entities/"+discovered.Id.String(), events.EventCreatedEntityModel{
+			EntityId: discovered.Id,
+		})
+		return
+	}
+
+	// the entity created by the user
+	if !IsDiscovered(entity) {
+		return
+	}
+
+	changed, actions, states := MergeDiscoveredEntity(entity, discovered)
+	if !changed && len(actions) == 0 && len(states) == 0 {
+		return
+	}
+
+	err = g.adaptors.Transaction.Do(ctx, func(ctx context.Context) error {
+		if len(actions) > 0 {
+			if err := g.adaptors.EntityAction.AddMultiple(ctx, actions); err != nil {
+				return err
+			}
+		}
+		if len(states) > 0 {
+			if err := g.adaptors.EntityState.AddMultiple(ctx, states); err != nil {
+				return err
+			}
+		}
+		// the actions and states are already saved
+		entity.Actions = nil
+		entity.States = nil
+		return g.adaptors.Entity.Update(ctx, entity)
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	log.Infof("updated entity id:(%s)", entity.Id)
+
+	g.eventBus.Publish("system/models/entities/"+entity.Id.String(), events.EventUpdatedEntityModel{
+		EntityId: entity.Id,
+	})
+}
+
+// removeDiscoveredEntity removes the entity of the device if it was created by the bridge
+func (g *Bridge) removeDiscoveredEntity(friendlyName string) {
+
+	entityId := common.EntityId(fmt.Sprintf("%s.%s", pluginName, friendlyName))
+
+	entity, err := g.adaptors.Entity.GetById(context.Background(), entityId)
+	if err != nil || !IsDiscovered(entity) {
+		return
+	}
+
+	if err = g.adaptors.Entity.Delete(context.Background(), entityId); err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	log.Infof("entity id:(%s) was deleted", entityId)
+
+	g.eventBus.Publish("system/models/entities/"+entityId.String(), events.CommandUnloadEntity{
+		EntityId: entityId,
+	})
+}
@@ -0,0 +1,128 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package zigbee2mqtt
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/e154/smart-home/pkg/common"
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+const testLightInfo = `{
+  "friendly_name": "kitchen_lamp",
+  "ieee_address": "0x0017880104e45517",
+  "type": "Router",
+  "interview_completed": true,
+  "definition": {
+    "model": "9290012573A",
+    "vendor": "Philips",
+    "description": "Hue white and color ambiance E26/E27",
+    "supports_ota": true,
+    "options": [{"access": 2, "name": "transition", "property": "transition", "type": "numeric", "value_min": 0}],
+    "exposes": [
+      {
+        "type": "light",
+        "features": [
+          {"access": 7, "name": "state", "property": "state", "type": "binary", "value_on": "ON", "value_off": "OFF", "value_toggle": "TOGGLE"},
+          {"access": 7, "name": "brightness", "property": "brightness", "type": "numeric", "value_min": 0, "value_max": 254},
+          {"access": 7, "name": "color_temp", "property": "color_temp", "type": "numeric", "unit": "mired", "value_min": 150, "value_max": 500, "value_step": 0.5},
+          {"access": 7, "name": "color_xy", "property": "color", "type": "composite", "features": [
+            {"access": 7, "name": "x", "property": "x", "type": "numeric"},
+            {"access": 7, "name": "y", "property": "y", "type": "numeric"}
+          ]}
+        ]
+      },
+      {"access": 2, "name": "effect", "property": "effect", "type": "enum", "values": ["blink", "breathe"]},
+      {"access": 1, "name": "linkquality", "property": "linkquality", "type": "numeric", "unit": "lqi"},
+      {"access": 1, "name": "occupancy", "property": "occupancy", "type": "binary", "value_on": true, "value_off": false}
+    ]
+  }
+}`
+
+func TestDiscoveredEntity(t *testing.T) {
+
+	info := DeviceInfo{}
+	require.NoError(t, json.Unmarshal([]byte(testLightInfo), &info))
+
+	entity := DiscoveredEntity(info)
+	require.Equal(t, common.EntityId("zigbee2mqtt.kitchen_lamp"), entity.Id)
+	require.True(t, IsDiscovered(entity))
+
+	// the composite color is skipped
+	require.Len(t, entity.Attributes, 6)
+	require.Equal(t, common.AttributeString, entity.Attributes["state"].Type)
+	require.Equal(t, common.AttributeFloat, entity.Attributes["brightness"].Type)
+	require.Equal(t, common.AttributeBool, entity.Attributes["occupancy"].Type)
+	require.Equal(t, common.AttributeString, entity.Attributes["effect"].Type)
+
+	var states, actions []string
+	for _, state := range entity.States {
+		states = append(states, state.Name)
+	}
+	for _, action := range entity.Actions {
+		actions = append(actions, action.Name)
+	}
+	require.Equal(t, []string{"ON", "OFF"}, states)
+	require.Equal(t, []string{"on", "off", "toggle", "set_brightness", "set_color_temp", "set_effect"}, actions)
+
+	payload, ok := info.Definition.ActionPayload("toggle", nil)
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{"state": "TOGGLE"}, payload)
+
+	payload, ok = info.Definition.ActionPayload("set_brightness", map[string]interface{}{"value": 128})
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{"brightness": 128}, payload)
+
+	_, ok = info.Definition.ActionPayload("set_brightness", nil)
+	require.False(t, ok)
+	// read only
+	_, ok = info.Definition.ActionPayload("set_linkquality", map[string]interface{}{"value": 1})
+	require.False(t, ok)
+}
+
+func TestMergeDiscoveredEntity(t *testing.T) {
+
+	info := DeviceInfo{}
+	require.NoError(t, json.Unmarshal([]byte(testLightInfo), &info))
+
+	// the entity edited by the user, the device got the new exposes after the re-interview
+	entity := DiscoveredEntity(info)
+	entity.Attributes["brightness"] = &m.Attribute{Name: "brightness", Type: common.AttributeInt}
+	delete(entity.Attributes, "effect")
+	entity.Actions = entity.Actions[:2]
+	entity.Actions[0].Description = "turn on"
+	entity.States = entity.States[:1]
+
+	changed, actions, states := MergeDiscoveredEntity(entity, DiscoveredEntity(info))
+	require.True(t, changed)
+	require.Equal(t, common.AttributeInt, entity.Attributes["brightness"].Type)
+	require.Contains(t, entity.Attributes, "effect")
+	require.Equal(t, "turn on", entity.Actions[0].Description)
+	require.Len(t, actions, 4)
+	require.Len(t, states, 1)
+	require.Equal(t, "OFF", states[0].Name)
+
+	changed, actions, states = MergeDiscoveredEntity(DiscoveredEntity(info), DiscoveredEntity(info))
+	require.False(t, changed)
+	require.Empty(t, actions)
+	require.Empty(t, states)
+}
@@ -0,0 +1,183 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package zigbee2mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/events"
+	"github.com/e154/smart-home/pkg/models"
+	"github.com/e154/smart-home/pkg/mqtt"
+)
+
+const (
+	// the plugin which controls the devices of the bridge
+	pluginName = "zigbee2mqtt"
+)
+
+// AddGroup ...
+func (g *Bridge) AddGroup(ctx context.Context, name string) (err error) {
+
+	var data json.RawMessage
+	if data, err = g.request(ctx, "group/add", map[string]interface{}{
+		"friendly_name": name,
+	}, g.requestTimeout); err != nil {
+		return
+	}
+
+	group := GroupInfo{}
+	_ = json.Unmarshal(data, &group)
+	if group.FriendlyName == "" {
+		group.FriendlyName = name
+	}
+
+	g.updateGroup(group)
+
+	return
+}
+
+// RemoveGroup ...
+func (g *Bridge) RemoveGroup(ctx context.Context, name string) (err error) {
+
+	if _, err = g.request(ctx, "group/remove", map[string]interface{}{
+		"id": name,
+	}, g.requestTimeout); err != nil {
+		return
+	}
+
+	if err = g.safeRemoveDevice(name); err != nil {
+		log.Error(err.Error())
+	}
+
+	g.removeDiscoveredEntity(name)
+
+	return
+}
+
+// GroupAddMember ...
+func (g *Bridge) GroupAddMember(ctx context.Context, group, device string) (err error) {
+	_, err = g.request(ctx, "group/members/add", map[string]interface{}{
+		"group":  group,
+		"device": device,
+	}, g.requestTimeout)
+	return
+}
+
+// GroupRemoveMember ...
+func (g *Bridge) GroupRemoveMember(ctx context.Context, group, device string) (err error) {
+	_, err = g.request(ctx, "group/members/remove", map[string]interface{}{
+		"group":  group,
+		"device": device,
+	}, g.requestTimeout)
+	return
+}
+
+// onGroups handles the retained bridge/groups message with the whole list of the groups
+func (g *Bridge) onGroups(client mqtt.MqttCli, message mqtt.Message) {
+
+	groups := make([]GroupInfo, 0)
+	if err := json.Unmarshal(message.Payload, &groups); err != nil {
+		log.Warn(err.Error())
+		return
+	}
+
+	var exist = make(map[string]struct{})
+	for _, group := range groups {
+		exist[group.FriendlyName] = struct{}{}
+		g.updateGroup(group)
+	}
+
+	// groups removed outside of the smart home
+	g.devicesLock.Lock()
+	var removedGroups []*Device
+	for name, device := range g.devices {
+		if _, ok := exist[name]; ok {
+			continue
+		}
+		if model := device.GetModel(); model.Type == Group && model.Status == active {
+			removedGroups = append(removedGroups, device)
+		}
+	}
+	g.devicesLock.Unlock()
+
+	for _, device := range removedGroups {
+		device.SetStatus(removed)
+		if err := g.safeUpdateDevice(device); err != nil {
+			log.Error(err.Error())
+		}
+	}
+}
+
+func (g *Bridge) updateGroup(group GroupInfo) {
+
+	payload, _ := json.Marshal(group)
+	model := &models.Zigbee2mqttDevice{
+		Id:            group.FriendlyName,
+		Zigbee2mqttId: g.model.Id,
+		Name:          group.FriendlyName,
+		Type:          Group,
+		Model:         "group",
+		Description:   fmt.Sprintf("zigbee group %d", group.Id),
+		Status:        active,
+		Payload:       payload,
+	}
+
+	device := NewDevice(group.FriendlyName, model)
+	if err := g.safeUpdateDevice(device); err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	g.addGroupEntity(group.FriendlyName)
+}
+
+// addGroupEntity creates the entity of the zigbee2mqtt plugin for the group, so the group can be controlled like a device
+func (g *Bridge) addGroupEntity(name string) {
+
+	entityId := common.EntityId(fmt.Sprintf("%s.%s", pluginName, name))
+	if _, err := g.adaptors.Entity.GetById(context.Background(), entityId); err == nil {
+		return
+	}
+
+	entity := &models.Entity{
+		Id:          entityId,
+		Description: fmt.Sprintf("zigbee2mqtt group %s", name),
+		PluginName:  pluginName,
+		AutoLoad:    true,
+		Settings: models.Attributes{
+			DiscoveredSetting: {
+				Name:  DiscoveredSetting,
+				Type:  common.AttributeBool,
+				Value: true,
+			},
+		},
+	}
+	if err := g.adaptors.Entity.Add(context.Background(), entity); err != nil {
+		log.Error(err.Error())
+		return
+	}
+
+	log.Infof("added new entity id:(%s)", entityId)
+
+	g.eventBus.Publish("system/models/entities/"+entityId.String(), events.EventCreatedEntityModel{
+		EntityId: entityId,
+	})
+}
@@ -0,0 +1,115 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package zigbee2mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/events"
+	"github.com/e154/smart-home/pkg/mqtt"
+)
+
+// OtaCheck checks whether the new firmware is available for the device
+func (g *Bridge) OtaCheck(ctx context.Context, friendlyName string) (updateAvailable bool, err error) {
+
+	var data json.RawMessage
+	if data, err = g.request(ctx, "device/ota_update/check", map[string]interface{}{
+		"id": friendlyName,
+	}, g.requestTimeout); err != nil {
+		return
+	}
+
+	result := struct {
+		UpdateAvailable bool `json:"update_available"`
+	}{}
+	_ = json.Unmarshal(data, &result)
+	updateAvailable = result.UpdateAvailable
+
+	return
+}
+
+// OtaUpdate starts the firmware update of the device, the progress is published as the
+// EventZigbee2mqttOtaProgress events, the EventZigbee2mqttOtaFinished is published at the end
+func (g *Bridge) OtaUpdate(ctx context.Context, friendlyName string) (err error) {
+
+	if _, err = g.safeGetDevice(friendlyName); err != nil {
+		return
+	}
+
+	g.otaLock.Lock()
+	if g.otaInProgress[friendlyName] {
+		g.otaLock.Unlock()
+		err = fmt.Errorf("%s: update in progress: %w", friendlyName, apperr.ErrInvalidRequest)
+		return
+	}
+	g.otaInProgress[friendlyName] = true
+	g.otaLock.Unlock()
+
+	// the device publishes the progress in the "update" attribute of its state
+	deviceTopic := g.GetDeviceTopic(friendlyName)
+	_ = g.mqttClient.Subscribe(deviceTopic, func(client mqtt.MqttCli, message mqtt.Message) {
+		g.onOtaProgress(friendlyName, message)
+	})
+
+	go func() {
+		_, err := g.request(context.Background(), "device/ota_update/update", map[string]interface{}{
+			"id": friendlyName,
+		}, otaRequestTimeout)
+
+		g.mqttClient.Unsubscribe(deviceTopic)
+
+		g.otaLock.Lock()
+		delete(g.otaInProgress, friendlyName)
+		g.otaLock.Unlock()
+
+		event := events.EventZigbee2mqttOtaFinished{
+			BridgeId:     g.model.Id,
+			FriendlyName: friendlyName,
+		}
+		if err != nil {
+			log.Error(err.Error())
+			event.Error = err.Error()
+		} else {
+			log.Infof("device %s updated", friendlyName)
+		}
+		g.eventBus.Publish("system/services/zigbee2mqtt", event)
+	}()
+
+	return
+}
+
+func (g *Bridge) onOtaProgress(friendlyName string, message mqtt.Message) {
+
+	state := struct {
+		Update *OtaUpdateState `json:"update"`
+	}{}
+	if err := json.Unmarshal(message.Payload, &state); err != nil || state.Update == nil {
+		return
+	}
+
+	g.eventBus.Publish("system/services/zigbee2mqtt", events.EventZigbee2mqttOtaProgress{
+		BridgeId:     g.model.Id,
+		FriendlyName: friendlyName,
+		State:        state.Update.State,
+		Progress:     state.Update.Progress,
+		Remaining:    state.Update.Remaining,
+	})
+}
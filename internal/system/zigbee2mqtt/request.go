@@ -0,0 +1,144 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package zigbee2mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/e154/smart-home/pkg/apperr"
+	"github.com/e154/smart-home/pkg/mqtt"
+)
+
+const (
+	// DefaultRequestTimeout how long the bridge waits for the response of zigbee2mqtt
+	DefaultRequestTimeout = time.Second * 10
+	// otaRequestTimeout the firmware update takes several minutes
+	otaRequestTimeout = time.Minute * 30
+	// networkmapTimeout the scan of the large network takes a while
+	networkmapTimeout = time.Minute * 5
+)
+
+// BridgeResponse the message of the bridge/response/# topics
+type BridgeResponse struct {
+	Data        json.RawMessage `json:"data"`
+	Status      string          `json:"status"`
+	Error       string          `json:"error,omitempty"`
+	Transaction string          `json:"transaction,omitempty"`
+}
+
+// IsOk ...
+func (r BridgeResponse) IsOk() bool {
+	return r.Status == "ok"
+}
+
+// request publishes the bridge/request/<command> message and waits for the response with the same transaction
+func (g *Bridge) request(ctx context.Context, command string, payload map[string]interface{}, timeout time.Duration) (data json.RawMessage, err error) {
+
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+
+	transaction := g.newTransaction()
+	payload["transaction"] = transaction
+
+	ch := make(chan BridgeResponse, 1)
+	g.pendingLock.Lock()
+	g.pending[transaction] = ch
+	g.pendingLock.Unlock()
+
+	defer func() {
+		g.pendingLock.Lock()
+		delete(g.pending, transaction)
+		g.pendingLock.Unlock()
+	}()
+
+	b, _ := json.Marshal(payload)
+	if err = g.mqttClient.Publish(g.topic("/bridge/request/"+command), b); err != nil {
+		err = fmt.Errorf("%s: %w", err.Error(), apperr.ErrZigbee2mqttRequest)
+		return
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-ch:
+		if !resp.IsOk() {
+			err = fmt.Errorf("%s: %s: %w", command, resp.Error, apperr.ErrZigbee2mqttRequest)
+			return
+		}
+		data = resp.Data
+	case <-timer.C:
+		err = fmt.Errorf("%s: %w", command, apperr.ErrZigbee2mqttTimeout)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return
+}
+
+// send publishes the bridge/request/<command> message without waiting for the response
+func (g *Bridge) send(command string, payload map[string]interface{}) {
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+	payload["transaction"] = g.newTransaction()
+	b, _ := json.Marshal(payload)
+	if err := g.mqttClient.Publish(g.topic("/bridge/request/"+command), b); err != nil {
+		log.Error(err.Error())
+	}
+}
+
+func (g *Bridge) newTransaction() string {
+	return fmt.Sprintf("smart-home-%d-%d", g.model.Id, g.transaction.Inc())
+}
+
+// onResponsePublish handles the bridge/response/<command> messages
+func (g *Bridge) onResponsePublish(_ mqtt.MqttCli, message mqtt.Message) {
+
+	command := strings.TrimPrefix(message.Topic, g.topic("/bridge/response/"))
+
+	resp := BridgeResponse{}
+	if err := json.Unmarshal(message.Payload, &resp); err != nil {
+		log.Warnf("bad response %s: %s", message.Topic, err.Error())
+		return
+	}
+
+	if resp.Transaction == "" {
+		return
+	}
+
+	g.pendingLock.Lock()
+	ch, ok := g.pending[resp.Transaction]
+	g.pendingLock.Unlock()
+	if !ok {
+		if !resp.IsOk() {
+			log.Warnf("request %s failed: %s", command, resp.Error)
+		}
+		return
+	}
+
+	select {
+	case ch <- resp:
+	default:
+	}
+}
@@ -37,6 +37,15 @@ type Zigbee2mqtt interface {
 	BridgeUpdateNetworkmap(bridgeId int64) (err error)
 	GetTopicByDevice(model *models.Zigbee2mqttDevice) (topic string, err error)
 	DeviceRename(friendlyName, name string) (err error)
+	AddGroup(ctx context.Context, bridgeId int64, name string) (err error)
+	RemoveGroup(ctx context.Context, bridgeId int64, name string) (err error)
+	GroupAddMember(ctx context.Context, bridgeId int64, group, device string) (err error)
+	GroupRemoveMember(ctx context.Context, bridgeId int64, group, device string) (err error)
+	DeviceOtaCheck(ctx context.Context, bridgeId int64, friendlyName string) (updateAvailable bool, err error)
+	DeviceOtaUpdate(ctx context.Context, bridgeId int64, friendlyName string) (err error)
+	DeviceOptions(ctx context.Context, bridgeId int64, friendlyName string, options map[string]interface{}) (err error)
+	DeviceBind(ctx context.Context, bridgeId int64, from, to string, clusters []string) (err error)
+	DeviceUnbind(ctx context.Context, bridgeId int64, from, to string, clusters []string) (err error)
 }
 
 // Zigbee2mqttBridge ...
@@ -67,17 +76,53 @@ type BridgeConfigMeta struct {
 
 // BridgeConfigCoordinator ...
 type BridgeConfigCoordinator struct {
-	Type string           `json:"type"`
-	Meta BridgeConfigMeta `json:"meta"`
+	Type        string           `json:"type"`
+	IeeeAddress string           `json:"ieee_address,omitempty"`
+	Meta        BridgeConfigMeta `json:"meta"`
 }
 
-// BridgeConfig ...
+// BridgeAdvancedSettings ...
+type BridgeAdvancedSettings struct {
+	LogLevel string `json:"log_level,omitempty"`
+	LastSeen string `json:"last_seen,omitempty"`
+	Elapsed  bool   `json:"elapsed,omitempty"`
+}
+
+// BridgeSettings the configuration of zigbee2mqtt, part of the bridge/info message
+type BridgeSettings struct {
+	Advanced  BridgeAdvancedSettings `json:"advanced"`
+	Passlist  []string               `json:"passlist,omitempty"`
+	Blocklist []string               `json:"blocklist,omitempty"`
+}
+
+// BridgeConfig the bridge/info message
 type BridgeConfig struct {
 	Version     string                  `json:"version"`
 	Commit      string                  `json:"commit"`
 	Coordinator BridgeConfigCoordinator `json:"coordinator"`
 	LogLevel    string                  `json:"log_level"`
-	PermitJoin  string                  `json:"permit_join"`
+	PermitJoin  bool                    `json:"permit_join"`
+	Config      BridgeSettings          `json:"config"`
+}
+
+// GroupMember ...
+type GroupMember struct {
+	IeeeAddress string `json:"ieee_address"`
+	Endpoint    int64  `json:"endpoint"`
+}
+
+// GroupInfo the item of the bridge/groups message
+type GroupInfo struct {
+	Id           int64         `json:"id"`
+	FriendlyName string        `json:"friendly_name"`
+	Members      []GroupMember `json:"members"`
+}
+
+// OtaUpdateState the "update" attribute of the device state
+type OtaUpdateState struct {
+	State     string  `json:"state"`
+	Progress  float64 `json:"progress"`
+	Remaining int64   `json:"remaining"`
 }
 
 const (
@@ -108,12 +153,17 @@ const (
 type EventDeviceInfoDefExpose struct {
 	Access      int64                      `json:"access,omitempty"`
 	Description string                     `json:"description,omitempty"`
+	Endpoint    string                     `json:"endpoint,omitempty"`
 	Name        string                     `json:"name,omitempty"`
 	Property    string                     `json:"property,omitempty"`
 	Type        string                     `json:"type,omitempty"`
 	Unit        string                     `json:"unit,omitempty"`
-	ValueMax    int64                      `json:"value_max,omitempty"`
-	ValueMin    int64                      `json:"value_min,omitempty"`
+	ValueMax    float64                    `json:"value_max,omitempty"`
+	ValueMin    float64                    `json:"value_min,omitempty"`
+	ValueStep   float64                    `json:"value_step,omitempty"`
+	ValueOn     interface{}                `json:"value_on,omitempty"`
+	ValueOff    interface{}                `json:"value_off,omitempty"`
+	ValueToggle interface{}                `json:"value_toggle,omitempty"`
 	Values      []string                   `json:"values,omitempty"`
 	Features    []EventDeviceInfoDefExpose `json:"features,omitempty"`
 }
@@ -123,7 +173,7 @@ type EventDeviceInfoDef struct {
 	Description string                     `json:"description,omitempty"`
 	Exposes     []EventDeviceInfoDefExpose `json:"exposes"`
 	Model       string                     `json:"model"`
-	Options     []string                   `json:"options"`
+	Options     []EventDeviceInfoDefExpose `json:"options"`
 	SupportsOta bool                       `json:"supports_ota"`
 	Vendor      string                     `json:"vendor"`
 }
@@ -133,6 +183,8 @@ const (
 	Coordinator = "Coordinator"
 	// EndDevice ...
 	EndDevice = "EndDevice"
+	// Group ...
+	Group = "Group"
 )
 
 // DeviceInfo ...
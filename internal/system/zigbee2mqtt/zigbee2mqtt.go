@@ -103,7 +103,7 @@ func (z *zigbee2mqtt) Start(ctx context.Context) (err error) {
 
 	//todo fix race condition
 	for _, model := range models {
-		bridge := NewBridge(z.mqtt, z.adaptors, model)
+		bridge := NewBridge(z.mqtt, z.adaptors, z.eventBus, model)
 		bridge.Start()
 
 		z.bridgesLock.Lock()
@@ -142,7 +142,7 @@ func (z *zigbee2mqtt) AddBridge(model *m.Zigbee2mqtt) (err error) {
 	z.bridgesLock.Lock()
 	defer z.bridgesLock.Unlock()
 
-	bridge := NewBridge(z.mqtt, z.adaptors, model)
+	bridge := NewBridge(z.mqtt, z.adaptors, z.eventBus, model)
 	bridge.Start()
 	z.bridges[model.Id] = bridge
 	return
@@ -172,7 +172,7 @@ func (z *zigbee2mqtt) UpdateBridge(model *m.Zigbee2mqtt) (err error) {
 		return
 	}
 
-	bridge = NewBridge(z.mqtt, z.adaptors, model)
+	bridge = NewBridge(z.mqtt, z.adaptors, z.eventBus, model)
 	bridge.Start()
 	z.bridges[model.Id] = bridge
 
@@ -195,36 +195,27 @@ func (z *zigbee2mqtt) DeleteBridge(bridgeId int64) (err error) {
 
 // ResetBridge ...
 func (z *zigbee2mqtt) ResetBridge(bridgeId int64) (err error) {
-	z.bridgesLock.Lock()
-	defer z.bridgesLock.Unlock()
-
 	var bridge *Bridge
-	if bridge, err = z.unsafeGetBridge(bridgeId); err == nil {
-		bridge.ConfigReset()
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.Restart(context.Background())
 	}
 	return
 }
 
 // BridgeDeviceBan ...
 func (z *zigbee2mqtt) BridgeDeviceBan(bridgeId int64, friendlyName string) (err error) {
-	z.bridgesLock.Lock()
-	defer z.bridgesLock.Unlock()
-
 	var bridge *Bridge
-	if bridge, err = z.unsafeGetBridge(bridgeId); err == nil {
-		bridge.Ban(friendlyName)
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.Ban(context.Background(), friendlyName)
 	}
 	return
 }
 
 // BridgeDeviceWhitelist ...
 func (z *zigbee2mqtt) BridgeDeviceWhitelist(bridgeId int64, friendlyName string) (err error) {
-	z.bridgesLock.Lock()
-	defer z.bridgesLock.Unlock()
-
 	var bridge *Bridge
-	if bridge, err = z.unsafeGetBridge(bridgeId); err == nil {
-		bridge.Whitelist(friendlyName)
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.Whitelist(context.Background(), friendlyName)
 	}
 	return
 }
@@ -253,6 +244,13 @@ func (z *zigbee2mqtt) BridgeUpdateNetworkmap(bridgeId int64) (err error) {
 	return
 }
 
+// getBridge the requests to zigbee2mqtt wait for the response, so the bridge lock is not held during the call
+func (z *zigbee2mqtt) getBridge(bridgeId int64) (bridge *Bridge, err error) {
+	z.bridgesLock.Lock()
+	defer z.bridgesLock.Unlock()
+	return z.unsafeGetBridge(bridgeId)
+}
+
 func (z *zigbee2mqtt) unsafeGetBridge(bridgeId int64) (bridge *Bridge, err error) {
 	var ok bool
 	if bridge, ok = z.bridges[bridgeId]; !ok {
@@ -290,6 +288,87 @@ func (z *zigbee2mqtt) DeviceRename(friendlyName, name string) (err error) {
 	return
 }
 
+// AddGroup ...
+func (z *zigbee2mqtt) AddGroup(ctx context.Context, bridgeId int64, name string) (err error) {
+	var bridge *Bridge
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.AddGroup(ctx, name)
+	}
+	return
+}
+
+// RemoveGroup ...
+func (z *zigbee2mqtt) RemoveGroup(ctx context.Context, bridgeId int64, name string) (err error) {
+	var bridge *Bridge
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.RemoveGroup(ctx, name)
+	}
+	return
+}
+
+// GroupAddMember ...
+func (z *zigbee2mqtt) GroupAddMember(ctx context.Context, bridgeId int64, group, device string) (err error) {
+	var bridge *Bridge
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.GroupAddMember(ctx, group, device)
+	}
+	return
+}
+
+// GroupRemoveMember ...
+func (z *zigbee2mqtt) GroupRemoveMember(ctx context.Context, bridgeId int64, group, device string) (err error) {
+	var bridge *Bridge
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.GroupRemoveMember(ctx, group, device)
+	}
+	return
+}
+
+// DeviceOtaCheck ...
+func (z *zigbee2mqtt) DeviceOtaCheck(ctx context.Context, bridgeId int64, friendlyName string) (updateAvailable bool, err error) {
+	var bridge *Bridge
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		updateAvailable, err = bridge.OtaCheck(ctx, friendlyName)
+	}
+	return
+}
+
+// DeviceOtaUpdate ...
+func (z *zigbee2mqtt) DeviceOtaUpdate(ctx context.Context, bridgeId int64, friendlyName string) (err error) {
+	var bridge *Bridge
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.OtaUpdate(ctx, friendlyName)
+	}
+	return
+}
+
+// DeviceOptions ...
+func (z *zigbee2mqtt) DeviceOptions(ctx context.Context, bridgeId int64, friendlyName string, options map[string]interface{}) (err error) {
+	var bridge *Bridge
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.DeviceOptions(ctx, friendlyName, options)
+	}
+	return
+}
+
+// DeviceBind ...
+func (z *zigbee2mqtt) DeviceBind(ctx context.Context, bridgeId int64, from, to string, clusters []string) (err error) {
+	var bridge *Bridge
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.DeviceBind(ctx, from, to, clusters)
+	}
+	return
+}
+
+// DeviceUnbind ...
+func (z *zigbee2mqtt) DeviceUnbind(ctx context.Context, bridgeId int64, from, to string, clusters []string) (err error) {
+	var bridge *Bridge
+	if bridge, err = z.getBridge(bridgeId); err == nil {
+		err = bridge.DeviceUnbind(ctx, from, to, clusters)
+	}
+	return
+}
+
 // Authenticator ...
 func (z *zigbee2mqtt) Authenticator(login, password string) (err error) {
 
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+alter table scripts
+    add column timeout integer not null default 0;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+alter table scripts
+    drop column timeout;
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+create type task_mode as enum ('single', 'restart', 'queued', 'parallel');
+
+alter table tasks
+    add column mode     task_mode default 'single'::task_mode not null,
+    add column max_runs integer   default 0                   not null;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+alter table tasks
+    drop column mode,
+    drop column max_runs;
+
+drop type task_mode;
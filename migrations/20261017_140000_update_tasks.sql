@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+alter table tasks
+    add column steps jsonb default '[]'::jsonb not null;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+alter table tasks
+    drop column steps;
@@ -0,0 +1,24 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+create table task_runs
+(
+    id         bigserial primary key,
+    task_id    bigint                                              not null
+        constraint task_runs_2_tasks_fk
+            references tasks
+            on update cascade on delete cascade,
+    trigger_id bigint                                              null,
+    entity_id  text                                                null,
+    payload    jsonb                    default '{}'::jsonb        not null,
+    status     text                                                not null,
+    error      text                     default ''                 not null,
+    trace      jsonb                    default '[]'::jsonb        not null,
+    start      timestamp with time zone default CURRENT_TIMESTAMP,
+    "end"      timestamp with time zone default null
+);
+
+create index task_runs_task_id_start_idx on task_runs (task_id, start);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+drop table task_runs;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+alter table conditions
+    add column rule jsonb;
+
+alter type condition_type add value if not exists 'not';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+alter table conditions
+    drop column rule;
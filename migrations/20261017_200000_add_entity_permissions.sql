@@ -0,0 +1,30 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+create table entity_permissions
+(
+    id         bigserial primary key,
+    role_name  text                                                not null
+        constraint entity_permissions_2_roles_fk
+            references roles
+            on update cascade on delete cascade,
+    entity_id  text                                                null
+        constraint entity_permissions_2_entities_fk
+            references entities
+            on update cascade on delete cascade,
+    area_id    bigint                                              null
+        constraint entity_permissions_2_areas_fk
+            references areas
+            on update cascade on delete cascade,
+    tag        text                                                null,
+    level      text                                                not null,
+    created_at timestamp with time zone default CURRENT_TIMESTAMP not null,
+    constraint entity_permissions_one_target_chk
+        check (num_nonnulls(entity_id, area_id, tag) = 1)
+);
+
+create unique index entity_permissions_unq
+    on entity_permissions (role_name, coalesce(entity_id, ''), coalesce(area_id, 0), coalesce(tag, ''));
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+drop table entity_permissions;
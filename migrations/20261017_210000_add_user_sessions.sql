@@ -0,0 +1,31 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+create table user_sessions
+(
+    id             bigserial primary key,
+    user_id        bigint                                              not null
+        constraint user_sessions_2_users_fk
+            references users
+            on update cascade on delete cascade,
+    refresh_token  text                                                not null,
+    previous_token text                                                null,
+    device         text                                                null,
+    ip             text                                                null,
+    created_at     timestamp with time zone default CURRENT_TIMESTAMP not null,
+    last_seen_at   timestamp with time zone default CURRENT_TIMESTAMP not null,
+    expires_at     timestamp with time zone                            not null,
+    revoked_at     timestamp with time zone                            null
+);
+
+create unique index user_sessions_refresh_token_unq
+    on user_sessions (refresh_token);
+
+create index user_sessions_previous_token_idx
+    on user_sessions (previous_token);
+
+create index user_sessions_user_id_idx
+    on user_sessions (user_id);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+drop table user_sessions;
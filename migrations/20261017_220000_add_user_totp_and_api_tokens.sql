@@ -0,0 +1,47 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+create table user_totp
+(
+    user_id        bigint primary key
+        constraint user_totp_2_users_fk
+            references users
+            on update cascade on delete cascade,
+    secret         text                                                not null,
+    enabled        boolean                  default false              not null,
+    recovery_codes jsonb                    default '[]'               not null,
+    created_at     timestamp with time zone default CURRENT_TIMESTAMP not null,
+    updated_at     timestamp with time zone default CURRENT_TIMESTAMP not null
+);
+
+alter table roles
+    add column require_totp boolean default false not null;
+
+create table api_tokens
+(
+    id           bigserial primary key,
+    user_id      bigint                                              not null
+        constraint api_tokens_2_users_fk
+            references users
+            on update cascade on delete cascade,
+    name         text                                                not null,
+    token        text                                                not null,
+    scopes       jsonb                    default '[]'               not null,
+    expires_at   timestamp with time zone                            null,
+    last_used_at timestamp with time zone                            null,
+    created_at   timestamp with time zone default CURRENT_TIMESTAMP not null
+);
+
+create unique index api_tokens_token_unq
+    on api_tokens (token);
+
+create unique index api_tokens_user_name_unq
+    on api_tokens (user_id, name);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+drop table api_tokens;
+
+alter table roles
+    drop column require_totp;
+
+drop table user_totp;
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+alter table message_deliveries
+    ADD COLUMN attempts        integer                  default 0     not null,
+    ADD COLUMN next_attempt_at timestamp with time zone default NULL,
+    ADD COLUMN attempt_log     jsonb                    default '[]'  not null,
+    ADD COLUMN fallback        jsonb                    default '[]'  not null;
+
+create index message_deliveries_next_attempt_idx
+    on message_deliveries (status, next_attempt_at);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+drop index message_deliveries_next_attempt_idx;
+
+alter table message_deliveries
+    DROP COLUMN attempts,
+    DROP COLUMN next_attempt_at,
+    DROP COLUMN attempt_log,
+    DROP COLUMN fallback;
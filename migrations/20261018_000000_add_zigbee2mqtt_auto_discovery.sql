@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+alter table zigbee2mqtt
+    ADD COLUMN auto_discovery boolean default false not null;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+alter table zigbee2mqtt
+    DROP COLUMN auto_discovery;
@@ -0,0 +1,48 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+create table metric_bucket_stats
+(
+    metric_id  bigint                   not null
+        constraint metric_id_at_metric_bucket_stats_2_metric_fk
+            references metrics
+            on update cascade on delete cascade,
+    resolution text                     not null,
+    name       text                     not null,
+    time       timestamp with time zone not null,
+    min        double precision         not null,
+    max        double precision         not null,
+    mean       double precision         not null,
+    sum        double precision         not null,
+    count      bigint                   not null,
+    constraint metric_bucket_stats_pkey
+        primary key (metric_id, resolution, name, time)
+);
+
+create index resolution_time_at_metric_bucket_stats_idx
+    on metric_bucket_stats (resolution, time);
+
+create table entity_storage_stats
+(
+    entity_id  text                     not null
+        constraint entity_storage_stats_2_entities_fk
+            references entities
+            on update cascade on delete cascade,
+    resolution text                     not null,
+    name       text                     not null,
+    time       timestamp with time zone not null,
+    min        double precision         not null,
+    max        double precision         not null,
+    mean       double precision         not null,
+    sum        double precision         not null,
+    count      bigint                   not null,
+    constraint entity_storage_stats_pkey
+        primary key (entity_id, resolution, name, time)
+);
+
+create index resolution_time_at_entity_storage_stats_idx
+    on entity_storage_stats (resolution, time);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+drop table if exists entity_storage_stats;
+drop table if exists metric_bucket_stats;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+alter table variables
+    add column encrypted boolean not null default false;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+alter table variables
+    drop column if exists encrypted;
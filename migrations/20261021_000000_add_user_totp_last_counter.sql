@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+alter table user_totp
+    add column last_counter bigint default 0 not null;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+alter table user_totp
+    drop column if exists last_counter;
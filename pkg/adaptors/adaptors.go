@@ -23,9 +23,13 @@ type Adaptors struct {
 	Tag               TagRepo
 	Role              RoleRepo
 	Permission        PermissionRepo
+	EntityPermission  EntityPermissionRepo
 	User              UserRepo
 	UserMeta          UserMetaRepo
 	UserDevice        UserDeviceRepo
+	UserSession       UserSessionRepo
+	UserTotp          UserTotpRepo
+	ApiToken          ApiTokenRepo
 	Image             ImageRepo
 	Variable          VariableRepo
 	Entity            EntityRepo
@@ -48,6 +52,7 @@ type Adaptors struct {
 	Trigger           TriggerRepo
 	Task              TaskRepo
 	RunHistory        RunHistoryRepo
+	TaskRun           TaskRunRepo
 	Plugin            PluginRepo
 	TelegramChat      TelegramChatRepo
 	Dashboard         DashboardRepo
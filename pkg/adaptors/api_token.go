@@ -0,0 +1,36 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"context"
+	"time"
+
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// ApiTokenRepo ...
+type ApiTokenRepo interface {
+	Add(ctx context.Context, token *m.ApiToken) (id int64, err error)
+	GetById(ctx context.Context, id int64) (token *m.ApiToken, err error)
+	GetByToken(ctx context.Context, hash string) (token *m.ApiToken, err error)
+	List(ctx context.Context, userId int64) (list []*m.ApiToken, err error)
+	Touch(ctx context.Context, id int64, lastUsed time.Time) (err error)
+	Delete(ctx context.Context, id int64) (err error)
+}
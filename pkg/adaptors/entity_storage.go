@@ -35,4 +35,8 @@ type EntityStorageRepo interface {
 		startDate, endDate *time.Time) (list []*m.EntityStorage, total int64, err error)
 	GetLastThreeById(ctx context.Context, entityId common.EntityId, id int64) (list []*m.EntityStorage, err error)
 	DeleteOldest(ctx context.Context, days int) (err error)
+	Rollup(ctx context.Context) (err error)
+	Statistics(ctx context.Context, entityId common.EntityId, attributes []string, from, to time.Time) (list []*m.EntityStorageStat, err error)
+	DeleteOldestStats(ctx context.Context, resolution common.StatsResolution, days int) (err error)
+	History(ctx context.Context, query *m.EntityHistoryQuery) (list []*m.EntityHistoryPoint, err error)
 }
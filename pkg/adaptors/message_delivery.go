@@ -20,7 +20,9 @@ package adaptors
 
 import (
 	"context"
+	"time"
 
+	"github.com/e154/smart-home/pkg/common"
 	m "github.com/e154/smart-home/pkg/models"
 )
 
@@ -30,6 +32,7 @@ type MessageDeliveryRepo interface {
 	SetStatus(ctx context.Context, msg *m.MessageDelivery) (err error)
 	List(ctx context.Context, limit, offset int64, orderBy, sort string, query *m.MessageDeliveryQuery) (list []*m.MessageDelivery, total int64, err error)
 	GetAllUncompleted(ctx context.Context, limit, offset int64) (list []*m.MessageDelivery, total int64, err error)
+	GetQueued(ctx context.Context, messageType string, entityId *common.EntityId, now time.Time, limit int64) (list []*m.MessageDelivery, err error)
 	Delete(ctx context.Context, id int64) (err error)
 	GetById(ctx context.Context, id int64) (ver *m.MessageDelivery, err error)
 }
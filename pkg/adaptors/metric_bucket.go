@@ -31,7 +31,10 @@ type MetricBucketRepo interface {
 	Add(ctx context.Context, ver *m.MetricDataItem) error
 	AddMultiple(ctx context.Context, items []*m.MetricDataItem) (err error)
 	List(ctx context.Context, from, to *time.Time, metricId int64, optionItems []string, metricRange *common.MetricRange) (list []*m.MetricDataItem, err error)
+	GetLast(ctx context.Context, metricIds []int64) (list []*m.MetricDataItem, err error)
 	DeleteOldest(ctx context.Context, days int) (err error)
 	DeleteById(ctx context.Context, id int64) (err error)
 	DeleteByMetricId(ctx context.Context, metricId int64) (err error)
+	Rollup(ctx context.Context) (err error)
+	DeleteOldestStats(ctx context.Context, resolution common.StatsResolution, days int) (err error)
 }
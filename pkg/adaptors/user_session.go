@@ -0,0 +1,40 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package adaptors
+
+import (
+	"context"
+	"time"
+
+	m "github.com/e154/smart-home/pkg/models"
+)
+
+// UserSessionRepo ...
+type UserSessionRepo interface {
+	Add(ctx context.Context, session *m.UserSession) (id int64, err error)
+	GetById(ctx context.Context, id int64) (session *m.UserSession, err error)
+	GetByToken(ctx context.Context, token string) (session *m.UserSession, err error)
+	// Rotate saves the new refresh token if the session still has the token, ok is false otherwise
+	Rotate(ctx context.Context, session *m.UserSession, token string) (ok bool, err error)
+	Touch(ctx context.Context, id int64, lastSeen time.Time) (err error)
+	Revoke(ctx context.Context, id int64) (err error)
+	RevokeAll(ctx context.Context, userId int64) (err error)
+	List(ctx context.Context, userId int64) (list []*m.UserSession, err error)
+	DeleteOld(ctx context.Context, before time.Time) (err error)
+}
@@ -159,6 +159,13 @@ var (
 	ErrPermissionGet    = ErrorWithCode("PERMISSION_GET_ERROR", "failed to get permission", ErrInternal)
 	ErrPermissionDelete = ErrorWithCode("PERMISSION_DELETE_ERROR", "failed to delete permission", ErrInternal)
 
+	ErrEntityPermissionAdd      = ErrorWithCode("ENTITY_PERMISSION_ADD_ERROR", "failed to add entity permission", ErrInternal)
+	ErrEntityPermissionGet      = ErrorWithCode("ENTITY_PERMISSION_GET_ERROR", "failed to get entity permission", ErrInternal)
+	ErrEntityPermissionList     = ErrorWithCode("ENTITY_PERMISSION_LIST_ERROR", "failed to list entity permission", ErrInternal)
+	ErrEntityPermissionNotFound = ErrorWithCode("ENTITY_PERMISSION_NOT_FOUND_ERROR", "entity permission is not found", ErrNotFound)
+	ErrEntityPermissionDelete   = ErrorWithCode("ENTITY_PERMISSION_DELETE_ERROR", "failed to delete entity permission", ErrInternal)
+	ErrEntityAccessForbidden    = ErrorWithCode("ENTITY_ACCESS_FORBIDDEN", "access to the entity is forbidden", ErrAccessForbidden)
+
 	ErrPluginAdd             = ErrorWithCode("PLUGIN_ADD_ERROR", "failed to add plugin", ErrInternal)
 	ErrPluginGet             = ErrorWithCode("PLUGIN_GET_ERROR", "failed to get plugin", ErrInternal)
 	ErrPluginUpdate          = ErrorWithCode("PLUGIN_UPDATE_ERROR", "failed to update plugin", ErrInternal)
@@ -197,6 +204,10 @@ var (
 	ErrScriptStat     = ErrorWithCode("SCRIPT_STAT_ERROR", "failed to get script statistic", ErrInternal)
 	ErrScriptCompile  = ErrorWithCode("SCRIPT_COMPILE_ERROR", "failed to compile script", ErrInternal)
 
+	ErrScriptTimeout       = ErrorWithCode("SCRIPT_TIMEOUT_ERROR", "script execution timeout", ErrInternal)
+	ErrScriptStackOverflow = ErrorWithCode("SCRIPT_STACK_OVERFLOW_ERROR", "script call stack size exceeded", ErrInternal)
+	ErrScriptMemoryLimit   = ErrorWithCode("SCRIPT_MEMORY_LIMIT_ERROR", "script memory limit exceeded", ErrInternal)
+
 	ErrAutomationStat = ErrorWithCode("AUTOMATION_STAT_ERROR", "failed to get automation statistic", ErrInternal)
 
 	ErrTagSearch   = ErrorWithCode("TAG_SEARCH_ERROR", "failed to search tag", ErrInternal)
@@ -217,6 +228,13 @@ var (
 	ErrTaskDeleteTrigger   = ErrorWithCode("TASK_DELETE_TRIGGER_ERROR", "task delete trigger failed", ErrInternal)
 	ErrTaskDeleteCondition = ErrorWithCode("TASK_DELETE_CONDITION_ERROR", "task delete condition failed", ErrInternal)
 	ErrTaskDeleteAction    = ErrorWithCode("TASK_DELETE_ACTION_ERROR", "task delete action failed", ErrInternal)
+	ErrTaskNotLoaded       = ErrorWithCode("TASK_NOT_LOADED", "task not loaded", ErrInvalidRequest)
+
+	ErrTaskRunAdd      = ErrorWithCode("TASK_RUN_ADD_ERROR", "failed to add task run", ErrInternal)
+	ErrTaskRunGet      = ErrorWithCode("TASK_RUN_GET_ERROR", "failed to get task run", ErrInternal)
+	ErrTaskRunList     = ErrorWithCode("TASK_RUN_LIST_ERROR", "failed to list task run", ErrInternal)
+	ErrTaskRunNotFound = ErrorWithCode("TASK_RUN_NOT_FOUND_ERROR", "task run is not found", ErrNotFound)
+	ErrTaskRunDelete   = ErrorWithCode("TASK_RUN_DELETE_ERROR", "failed to delete task run", ErrInternal)
 
 	ErrChatAdd    = ErrorWithCode("CHAT_ADD_ERROR", "failed to add chat", ErrInternal)
 	ErrChatList   = ErrorWithCode("CHAT_LIST_ERROR", "failed to list chat", ErrInternal)
@@ -263,6 +281,8 @@ var (
 	ErrZigbee2mqttList     = ErrorWithCode("ZIGBEE2MQTT_LIST_ERROR", "failed to list zigbee2mqtt", ErrInternal)
 	ErrZigbee2mqttNotFound = ErrorWithCode("ZIGBEE2MQTT_NOT_FOUND_ERROR", "zigbee2mqtt is not found", ErrNotFound)
 	ErrZigbee2mqttDelete   = ErrorWithCode("ZIGBEE2MQTT_DELETE_ERROR", "failed to delete zigbee2mqtt", ErrInternal)
+	ErrZigbee2mqttRequest  = ErrorWithCode("ZIGBEE2MQTT_REQUEST_ERROR", "zigbee2mqtt request failed", ErrInternal)
+	ErrZigbee2mqttTimeout  = ErrorWithCode("ZIGBEE2MQTT_TIMEOUT_ERROR", "zigbee2mqtt request timeout", ErrInternal)
 
 	ErrZigbeeDeviceAdd      = ErrorWithCode("ZIGBEE_DEVICE_ADD_ERROR", "failed to add device", ErrInternal)
 	ErrZigbeeDeviceGet      = ErrorWithCode("ZIGBEE_DEVICE_GET_ERROR", "failed to get device", ErrInternal)
@@ -277,6 +297,34 @@ var (
 	ErrUserDeviceAdd    = ErrorWithCode("USER_DEVICE_ADD_ERROR", "failed to add user device", ErrInternal)
 	ErrUserDeviceList   = ErrorWithCode("USER_DEVICE_LIST_ERROR", "failed to list user devices", ErrInternal)
 
+	ErrUserSessionAdd      = ErrorWithCode("USER_SESSION_ADD_ERROR", "failed to add user session", ErrInternal)
+	ErrUserSessionGet      = ErrorWithCode("USER_SESSION_GET_ERROR", "failed to get user session", ErrInternal)
+	ErrUserSessionUpdate   = ErrorWithCode("USER_SESSION_UPDATE_ERROR", "failed to update user session", ErrInternal)
+	ErrUserSessionList     = ErrorWithCode("USER_SESSION_LIST_ERROR", "failed to list user sessions", ErrInternal)
+	ErrUserSessionNotFound = ErrorWithCode("USER_SESSION_NOT_FOUND_ERROR", "user session is not found", ErrNotFound)
+	ErrUserSessionRevoke   = ErrorWithCode("USER_SESSION_REVOKE_ERROR", "failed to revoke user session", ErrInternal)
+	ErrUserSessionRevoked  = ErrorWithCode("USER_SESSION_REVOKED", "user session is revoked or expired", ErrUnauthorized)
+
+	ErrUserTotpGet               = ErrorWithCode("USER_TOTP_GET_ERROR", "failed to get two-factor authentication", ErrInternal)
+	ErrUserTotpSave              = ErrorWithCode("USER_TOTP_SAVE_ERROR", "failed to save two-factor authentication", ErrInternal)
+	ErrUserTotpDelete            = ErrorWithCode("USER_TOTP_DELETE_ERROR", "failed to delete two-factor authentication", ErrInternal)
+	ErrUserTotpNotFound          = ErrorWithCode("USER_TOTP_NOT_FOUND_ERROR", "two-factor authentication is not enrolled", ErrNotFound)
+	ErrUserTotpRequired          = ErrorWithCode("USER_TOTP_REQUIRED", "two-factor authentication code is required", ErrUnauthorized)
+	ErrUserTotpInvalid           = ErrorWithCode("USER_TOTP_INVALID", "two-factor authentication code is not valid", ErrUnauthorized)
+	ErrUserTotpEnrolmentRequired = ErrorWithCode("USER_TOTP_ENROLMENT_REQUIRED", "the role requires two-factor authentication", ErrAccessForbidden)
+	ErrUserTotpAlreadyEnabled    = ErrorWithCode("USER_TOTP_ALREADY_ENABLED", "two-factor authentication is already enabled", ErrInvalidRequest)
+	ErrUserTotpThrottled         = ErrorWithCode("USER_TOTP_THROTTLED", "too many two-factor authentication attempts, try again later", ErrUnauthorized)
+	ErrUserCredentialsForbidden  = ErrorWithCode("USER_CREDENTIALS_FORBIDDEN", "access to the user credentials is forbidden", ErrAccessForbidden)
+
+	ErrApiTokenAdd      = ErrorWithCode("API_TOKEN_ADD_ERROR", "failed to add api token", ErrInternal)
+	ErrApiTokenGet      = ErrorWithCode("API_TOKEN_GET_ERROR", "failed to get api token", ErrInternal)
+	ErrApiTokenUpdate   = ErrorWithCode("API_TOKEN_UPDATE_ERROR", "failed to update api token", ErrInternal)
+	ErrApiTokenList     = ErrorWithCode("API_TOKEN_LIST_ERROR", "failed to list api tokens", ErrInternal)
+	ErrApiTokenNotFound = ErrorWithCode("API_TOKEN_NOT_FOUND_ERROR", "api token is not found", ErrNotFound)
+	ErrApiTokenDelete   = ErrorWithCode("API_TOKEN_DELETE_ERROR", "failed to delete api token", ErrInternal)
+	ErrApiTokenExpired  = ErrorWithCode("API_TOKEN_EXPIRED", "api token is expired", ErrUnauthorized)
+	ErrApiTokenExists   = ErrorWithCode("API_TOKEN_EXISTS", "api token with the name already exists", ErrInvalidRequest)
+
 	ErrBackupNotFound           = ErrorWithCode("BACKUP_NOT_FOUND_ERROR", "backup not found", ErrNotFound)
 	ErrBackupNameNotUnique      = ErrorWithCode("BACKUP_NAME_NOT_UNIQUE_ERROR", "backup name not unique", ErrInvalidRequest)
 	ErrBackupRestoreForbidden   = ErrorWithCode("BACKUP_RESTORE_ERROR", "failed to restore backup", ErrAccessForbidden)
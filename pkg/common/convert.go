@@ -36,11 +36,21 @@ func StringValue(v *string) string {
 	return ""
 }
 
+// Int32 ...
+func Int32(v int32) *int32 {
+	return &v
+}
+
 // Int64 ...
 func Int64(v int64) *int64 {
 	return &v
 }
 
+// Float64 ...
+func Float64(v float64) *float64 {
+	return &v
+}
+
 // Int64Value ...
 func Int64Value(v *int64) int64 {
 	if v != nil {
@@ -18,7 +18,11 @@
 
 package common
 
-import "github.com/gin-gonic/gin"
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
 
 // ConditionType ...
 type ConditionType string
@@ -28,8 +32,170 @@ const (
 	ConditionOr = ConditionType("or")
 	// ConditionAnd ...
 	ConditionAnd = ConditionType("and")
+	// ConditionNot passes when none of the conditions passed
+	ConditionNot = ConditionType("not")
 )
 
+// ConditionRuleType ...
+type ConditionRuleType string
+
+const (
+	// ConditionRuleState compares the entity state
+	ConditionRuleState = ConditionRuleType("state")
+	// ConditionRuleNumeric compares the entity value or attribute with the thresholds
+	ConditionRuleNumeric = ConditionRuleType("numeric")
+	// ConditionRuleTime checks the time of day and the weekday
+	ConditionRuleTime = ConditionRuleType("time")
+	// ConditionRuleSun compares the sun elevation with the thresholds
+	ConditionRuleSun = ConditionRuleType("sun")
+	// ConditionRuleTemplate renders the template, passes if the result is "true"
+	ConditionRuleTemplate = ConditionRuleType("template")
+	// ConditionRuleAnd passes when all nested rules passed
+	ConditionRuleAnd = ConditionRuleType("and")
+	// ConditionRuleOr passes when one of the nested rules passed
+	ConditionRuleOr = ConditionRuleType("or")
+	// ConditionRuleNot passes when none of the nested rules passed
+	ConditionRuleNot = ConditionRuleType("not")
+)
+
+// SunAttrElevation the attribute of the sun entity compared by the sun rule
+const SunAttrElevation = "elevation"
+
+// String ...
+func (t ConditionRuleType) String() string {
+	return string(t)
+}
+
+// TaskMode ...
+type TaskMode string
+
+const (
+	// TaskModeSingle skips a new run while the previous one is still going
+	TaskModeSingle = TaskMode("single")
+	// TaskModeRestart cancels the current run and starts a new one
+	TaskModeRestart = TaskMode("restart")
+	// TaskModeQueued runs one after another in the order of arrival
+	TaskModeQueued = TaskMode("queued")
+	// TaskModeParallel starts a new run alongside the running ones
+	TaskModeParallel = TaskMode("parallel")
+)
+
+// String ...
+func (m TaskMode) String() string {
+	return string(m)
+}
+
+// TaskRunStatus ...
+type TaskRunStatus string
+
+const (
+	// TaskRunCompleted all steps were executed
+	TaskRunCompleted = TaskRunStatus("completed")
+	// TaskRunFailed one of the steps returned an error
+	TaskRunFailed = TaskRunStatus("failed")
+	// TaskRunCancelled the run was cancelled by the task mode or the task was stopped
+	TaskRunCancelled = TaskRunStatus("cancelled")
+	// TaskRunNotPassed the conditions of the task were not met
+	TaskRunNotPassed = TaskRunStatus("not_passed")
+	// TaskRunSkipped the run was not started because of the task mode
+	TaskRunSkipped = TaskRunStatus("skipped")
+)
+
+// StepType ...
+type StepType string
+
+const (
+	// StepTypeAction runs the action
+	StepTypeAction = StepType("action")
+	// StepTypeDelay pauses the sequence
+	StepTypeDelay = StepType("delay")
+	// StepTypeWait waits for the entity state
+	StepTypeWait = StepType("wait")
+	// StepTypeIf runs one of the branches depending on the condition
+	StepTypeIf = StepType("if")
+	// StepTypeRepeat repeats the steps until the condition
+	StepTypeRepeat = StepType("repeat")
+	// StepTypeParallel runs the steps at the same time
+	StepTypeParallel = StepType("parallel")
+)
+
+// String ...
+func (s StepType) String() string {
+	return string(s)
+}
+
+// ArchiveFormat ...
+type ArchiveFormat string
+
+const (
+	// ArchiveFormatJson ...
+	ArchiveFormatJson = ArchiveFormat("json")
+	// ArchiveFormatYaml ...
+	ArchiveFormatYaml = ArchiveFormat("yaml")
+)
+
+// ImportConflict defines what happens to the imported object that already exists
+type ImportConflict string
+
+const (
+	// ImportConflictSkip keeps the existing object, the references point to it
+	ImportConflictSkip = ImportConflict("skip")
+	// ImportConflictOverwrite replaces the existing object
+	ImportConflictOverwrite = ImportConflict("overwrite")
+	// ImportConflictRename adds the object under the new name
+	ImportConflictRename = ImportConflict("rename")
+)
+
+// ImportStatus ...
+type ImportStatus string
+
+const (
+	// ImportStatusCreated ...
+	ImportStatusCreated = ImportStatus("created")
+	// ImportStatusUpdated ...
+	ImportStatusUpdated = ImportStatus("updated")
+	// ImportStatusSkipped ...
+	ImportStatusSkipped = ImportStatus("skipped")
+	// ImportStatusRenamed ...
+	ImportStatusRenamed = ImportStatus("renamed")
+)
+
+// EntityAccessLevel is the right of the role on the entity, the higher level includes the lower ones
+type EntityAccessLevel string
+
+const (
+	// EntityAccessNone denies the entity, it overrides the area and tag permissions
+	EntityAccessNone = EntityAccessLevel("none")
+	// EntityAccessRead allows to see the entity and its state
+	EntityAccessRead = EntityAccessLevel("read")
+	// EntityAccessControl allows to call the actions and to set the state
+	EntityAccessControl = EntityAccessLevel("control")
+	// EntityAccessEdit allows to change the entity settings
+	EntityAccessEdit = EntityAccessLevel("edit")
+)
+
+// Includes ...
+func (l EntityAccessLevel) Includes(level EntityAccessLevel) bool {
+	return l.rank() >= level.rank()
+}
+
+func (l EntityAccessLevel) rank() int {
+	switch l {
+	case EntityAccessRead:
+		return 1
+	case EntityAccessControl:
+		return 2
+	case EntityAccessEdit:
+		return 3
+	}
+	return 0
+}
+
+// String ...
+func (l EntityAccessLevel) String() string {
+	return string(l)
+}
+
 // RunMode ...
 type RunMode string
 
@@ -69,6 +235,7 @@ const (
 	MetricRange7d  = MetricRange("7d")
 	MetricRange30d = MetricRange("30d")
 	MetricRange1m  = MetricRange("1m")
+	MetricRange1y  = MetricRange("1y")
 )
 
 func (m MetricRange) String() string {
@@ -79,6 +246,63 @@ func (m MetricRange) Ptr() *MetricRange {
 	return &m
 }
 
+// StatsResolution ...
+type StatsResolution string
+
+const (
+	// StatsResolutionRaw ...
+	StatsResolutionRaw = StatsResolution("raw")
+	// StatsResolutionHour ...
+	StatsResolutionHour = StatsResolution("hour")
+	// StatsResolutionDay ...
+	StatsResolutionDay = StatsResolution("day")
+)
+
+const (
+	// StatsRawMaxRange is the longest range still served from raw rows
+	StatsRawMaxRange = 7 * 24 * time.Hour
+	// StatsHourMaxRange is the longest range still served from hourly rollups
+	StatsHourMaxRange = 93 * 24 * time.Hour
+)
+
+func (r StatsResolution) String() string {
+	return string(r)
+}
+
+// StatsResolutionByRange returns the coarsest resolution that still gives a detailed graph for the range
+func StatsResolutionByRange(from, to time.Time) StatsResolution {
+	switch d := to.Sub(from); {
+	case d <= StatsRawMaxRange:
+		return StatsResolutionRaw
+	case d <= StatsHourMaxRange:
+		return StatsResolutionHour
+	default:
+		return StatsResolutionDay
+	}
+}
+
+// HistoryAggregation ...
+type HistoryAggregation string
+
+const (
+	// HistoryAggregationLast ...
+	HistoryAggregationLast = HistoryAggregation("last")
+	// HistoryAggregationMin ...
+	HistoryAggregationMin = HistoryAggregation("min")
+	// HistoryAggregationMax ...
+	HistoryAggregationMax = HistoryAggregation("max")
+	// HistoryAggregationMean ...
+	HistoryAggregationMean = HistoryAggregation("mean")
+	// HistoryAggregationCount ...
+	HistoryAggregationCount = HistoryAggregation("count")
+	// HistoryAggregationDuration sums the seconds the value was equal to the requested state
+	HistoryAggregationDuration = HistoryAggregation("duration")
+)
+
+func (a HistoryAggregation) String() string {
+	return string(a)
+}
+
 // LogLevel ...
 type LogLevel string
 
@@ -33,6 +33,18 @@ type EventTaskCompleted struct {
 	Ctx context.Context `json:"ctx"`
 }
 
+// EventTaskRunFinished ...
+type EventTaskRunFinished struct {
+	Id  int64      `json:"id"`
+	Run *m.TaskRun `json:"run"`
+}
+
+// CommandReplayTaskRun ...
+type CommandReplayTaskRun struct {
+	Id  int64      `json:"id"`
+	Run *m.TaskRun `json:"run"`
+}
+
 // CommandEnableTask ...
 type CommandEnableTask struct {
 	Id int64 `json:"id"`
@@ -84,6 +96,12 @@ type EventTriggerCompleted struct {
 	Ctx      context.Context  `json:"ctx"`
 }
 
+// EventTriggerNotPassed the trigger script rejected the message
+type EventTriggerNotPassed struct {
+	Id   int64           `json:"id"`
+	Args *TriggerMessage `json:"args"`
+}
+
 // CommandEnableTrigger ...
 type CommandEnableTrigger struct {
 	Id int64 `json:"id"`
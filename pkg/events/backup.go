@@ -35,6 +35,11 @@ type EventUploadedBackup struct {
 	Name string `json:"name"`
 }
 
+type EventUploadedBackupToTarget struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+}
+
 type EventStartedRestore struct {
 	Name string `json:"name"`
 }
@@ -43,8 +48,12 @@ type CommandCreateBackup struct {
 	Scheduler bool `json:"scheduler"`
 }
 
+// CommandClearStorage the last Num backups are kept if the daily, weekly and monthly retention is not set
 type CommandClearStorage struct {
-	Num int64 `json:"num"`
+	Num     int64 `json:"num"`
+	Daily   int   `json:"daily"`
+	Weekly  int   `json:"weekly"`
+	Monthly int   `json:"monthly"`
 }
 
 type CommandSendFileToTelegram struct {
@@ -65,3 +65,23 @@ func (e1 EventEntityState) Compare(e2 EventEntityState) (ident bool) {
 
 	return
 }
+
+// Number returns the numeric attribute, the value of the state is used without the attribute name
+func (e1 EventEntityState) Number(attribute string) (float64, bool) {
+	if attribute == "" {
+		return common.ToFloat64(e1.Value)
+	}
+	attr, ok := e1.Attributes[attribute]
+	if !ok || attr == nil {
+		return 0, false
+	}
+	return common.ToFloat64(attr.Value)
+}
+
+// StateName ...
+func (e1 EventEntityState) StateName() string {
+	if e1.State == nil {
+		return ""
+	}
+	return e1.State.Name
+}
@@ -43,3 +43,10 @@ type EventRemovedScriptModel struct {
 	ScriptId int64     `json:"script_id"`
 	Script   *m.Script `json:"script"`
 }
+
+// EventScriptInterrupted ...
+type EventScriptInterrupted struct {
+	ScriptId int64  `json:"script_id"`
+	Code     string `json:"code"`
+	Error    string `json:"error"`
+}
@@ -40,3 +40,14 @@ type EventDirectMessage struct {
 type EventUserSignedIn struct {
 	User *m.User `json:"user"`
 }
+
+// EventUpdatedRoleModel the role, its parent or the entity permissions of the role have changed
+type EventUpdatedRoleModel struct {
+	Name string `json:"name"`
+}
+
+// EventUserSessionRevoked the session of the user is revoked, the zero session id means all sessions
+type EventUserSessionRevoked struct {
+	UserId    int64 `json:"user_id"`
+	SessionId int64 `json:"session_id"`
+}
@@ -38,3 +38,19 @@ type EventUpdatedZigbee2mqttModel struct {
 type EventRemovedZigbee2mqttModel struct {
 	Id int64 `json:"id"`
 }
+
+// EventZigbee2mqttOtaProgress ...
+type EventZigbee2mqttOtaProgress struct {
+	BridgeId     int64   `json:"bridge_id"`
+	FriendlyName string  `json:"friendly_name"`
+	State        string  `json:"state"`
+	Progress     float64 `json:"progress"`
+	Remaining    int64   `json:"remaining"`
+}
+
+// EventZigbee2mqttOtaFinished ...
+type EventZigbee2mqttOtaFinished struct {
+	BridgeId     int64  `json:"bridge_id"`
+	FriendlyName string `json:"friendly_name"`
+	Error        string `json:"error,omitempty"`
+}
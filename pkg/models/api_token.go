@@ -0,0 +1,45 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+)
+
+// ApiTokenPrefix the api tokens differ from the jwt tokens by the prefix
+const ApiTokenPrefix = "smh_"
+
+// ApiToken is the long-lived token of the user for the integrations.
+// The empty scopes give the access of the user role, otherwise only the listed
+// access list levels ("package" or "package:level") are allowed.
+type ApiToken struct {
+	Id         int64      `json:"id"`
+	UserId     int64      `json:"user_id"`
+	Name       string     `json:"name" validate:"required,max=255"`
+	Token      string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsExpired ...
+func (t *ApiToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
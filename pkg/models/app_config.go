@@ -68,4 +68,7 @@ type AppConfig struct {
 	GateClientServerPort           int            `json:"gate_client_server_port" env:"GATE_CLIENT_SERVER_PORT"`
 	GateClientPoolIdleSize         int            `json:"gate_client_pool_idle_size" env:"GATE_CLIENT_POOL_IDLE_SIZE"`
 	GateClientPoolMaxSize          int            `json:"gate_client_pool_max_size" env:"GATE_CLIENT_POOL_MAX_SIZE"`
+	ScriptTimeout                  int            `json:"script_timeout" env:"SCRIPT_TIMEOUT"`
+	ScriptMaxCallStackSize         int            `json:"script_max_call_stack_size" env:"SCRIPT_MAX_CALL_STACK_SIZE"`
+	ScriptMemoryLimit              int            `json:"script_memory_limit" env:"SCRIPT_MEMORY_LIMIT"`
 }
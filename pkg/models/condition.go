@@ -18,17 +18,45 @@
 
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+)
 
 // Condition ...
 type Condition struct {
-	Id          int64     `json:"id"`
-	Name        string    `json:"name" validate:"required,lte=255"`
-	Script      *Script   `json:"script"`
-	ScriptId    *int64    `json:"script_id" validate:"required"`
-	AreaId      *int64    `json:"area_id"`
-	Area        *Area     `json:"area"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id          int64          `json:"id"`
+	Name        string         `json:"name" validate:"required,lte=255"`
+	Script      *Script        `json:"script"`
+	ScriptId    *int64         `json:"script_id" validate:"required_without=Rule"`
+	Rule        *ConditionRule `json:"rule" validate:"omitempty"`
+	AreaId      *int64         `json:"area_id"`
+	Area        *Area          `json:"area"`
+	Description string         `json:"description"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// ConditionRule is the native condition evaluated without the script
+type ConditionRule struct {
+	Type     common.ConditionRuleType `json:"type" validate:"required,oneof=state numeric time sun template and or not"`
+	EntityId *common.EntityId         `json:"entity_id,omitempty"`
+	// state
+	State    string `json:"state,omitempty"`
+	NotEqual bool   `json:"not_equal,omitempty"`
+	// numeric and sun, the entity value is used without the attribute
+	Attribute string   `json:"attribute,omitempty"`
+	Above     *float64 `json:"above,omitempty"`
+	Below     *float64 `json:"below,omitempty"`
+	// For the state or value must be held at least (ms)
+	For int `json:"for,omitempty" validate:"gte=0"`
+	// time window "15:04", the window may cross midnight
+	After    string `json:"after,omitempty"`
+	Before   string `json:"before,omitempty"`
+	Weekdays []int  `json:"weekdays,omitempty" validate:"dive,gte=0,lte=6"`
+	// template
+	Template string `json:"template,omitempty"`
+	// and, or, not
+	Rules []*ConditionRule `json:"rules,omitempty" validate:"dive"`
 }
@@ -0,0 +1,112 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigArchiveVersion ...
+const ConfigArchiveVersion = 1
+
+// ConfigArchive is the portable set of the configuration objects.
+// The objects refer to each other by the identifiers of the source instance,
+// the nested objects keep only the identifier and the name.
+type ConfigArchive struct {
+	Version    int          `json:"version"`
+	CreatedAt  time.Time    `json:"created_at"`
+	Scripts    []*Script    `json:"scripts,omitempty"`
+	Areas      []*Area      `json:"areas,omitempty"`
+	Entities   []*Entity    `json:"entities,omitempty"`
+	Variables  []Variable   `json:"variables,omitempty"`
+	Conditions []*Condition `json:"conditions,omitempty"`
+	Triggers   []*Trigger   `json:"triggers,omitempty"`
+	Actions    []*Action    `json:"actions,omitempty"`
+	Tasks      []*Task      `json:"tasks,omitempty"`
+	Dashboards []*Dashboard `json:"dashboards,omitempty"`
+}
+
+// Marshal ...
+func (a *ConfigArchive) Marshal(format common.ArchiveFormat) ([]byte, error) {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil || format != common.ArchiveFormatYaml {
+		return data, err
+	}
+	// yaml follows the json names of the fields
+	var doc interface{}
+	if err = json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// UnmarshalConfigArchive ...
+func UnmarshalConfigArchive(data []byte, format common.ArchiveFormat) (archive *ConfigArchive, err error) {
+	if format == common.ArchiveFormatYaml {
+		var doc interface{}
+		if err = yaml.Unmarshal(data, &doc); err != nil {
+			return
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return
+		}
+	}
+	archive = &ConfigArchive{}
+	if err = json.Unmarshal(data, archive); err != nil {
+		return
+	}
+	if archive.Version > ConfigArchiveVersion {
+		err = fmt.Errorf("unsupported archive version %d", archive.Version)
+	}
+	return
+}
+
+// ConfigExportParams selects the objects of the archive,
+// the objects they refer to by the numeric identifiers are added automatically
+type ConfigExportParams struct {
+	Scripts    []int64           `json:"scripts"`
+	Areas      []int64           `json:"areas"`
+	Entities   []common.EntityId `json:"entities"`
+	Variables  []string          `json:"variables"`
+	Conditions []int64           `json:"conditions"`
+	Triggers   []int64           `json:"triggers"`
+	Actions    []int64           `json:"actions"`
+	Tasks      []int64           `json:"tasks"`
+	Dashboards []int64           `json:"dashboards"`
+}
+
+// ConfigImportItem ...
+type ConfigImportItem struct {
+	Type   string              `json:"type"`
+	Name   string              `json:"name"`
+	OldId  string              `json:"old_id"`
+	NewId  string              `json:"new_id"`
+	Status common.ImportStatus `json:"status"`
+}
+
+// ConfigImportResult ...
+type ConfigImportResult struct {
+	Items []*ConfigImportItem `json:"items"`
+}
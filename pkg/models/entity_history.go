@@ -0,0 +1,53 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+)
+
+// EntityHistoryQuery ...
+type EntityHistoryQuery struct {
+	EntityIds []common.EntityId
+	// Attribute is a dot separated path into the attributes, the state is used when empty
+	Attribute   string
+	Aggregation common.HistoryAggregation
+	// Interval is the bucket size, the whole range is one bucket when zero
+	Interval time.Duration
+	// State is the value the duration aggregation is counted for
+	State string
+	From  time.Time
+	To    time.Time
+}
+
+// EntityHistorySample is the value the entity had since the moment it was stored
+type EntityHistorySample struct {
+	EntityId common.EntityId
+	Time     time.Time
+	Value    *string
+}
+
+// EntityHistoryPoint is the aggregated value of one bucket, the bucket starts at Time
+type EntityHistoryPoint struct {
+	EntityId common.EntityId `json:"entity_id"`
+	Time     time.Time       `json:"time"`
+	Value    interface{}     `json:"value"`
+}
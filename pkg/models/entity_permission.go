@@ -0,0 +1,37 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+)
+
+// EntityPermission grants the level of access to the entity, to the entities of the area or with the tag.
+// The role without entity permissions has access to all entities.
+type EntityPermission struct {
+	Id        int64                    `json:"id"`
+	RoleName  string                   `json:"role_name" validate:"required"`
+	EntityId  *common.EntityId         `json:"entity_id"`
+	AreaId    *int64                   `json:"area_id"`
+	Tag       *string                  `json:"tag"`
+	Level     common.EntityAccessLevel `json:"level" validate:"required,oneof=none read control edit"`
+	CreatedAt time.Time                `json:"created_at"`
+}
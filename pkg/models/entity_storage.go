@@ -34,3 +34,16 @@ type EntityStorage struct {
 	Attributes        AttributeValue
 	CreatedAt         time.Time
 }
+
+// EntityStorageStat is a rolled up numeric attribute over one period
+type EntityStorageStat struct {
+	EntityId   common.EntityId        `json:"entity_id"`
+	Resolution common.StatsResolution `json:"resolution"`
+	Name       string                 `json:"name"`
+	Time       time.Time              `json:"time"`
+	Min        float64                `json:"min"`
+	Max        float64                `json:"max"`
+	Mean       float64                `json:"mean"`
+	Sum        float64                `json:"sum"`
+	Count      int64                  `json:"count"`
+}
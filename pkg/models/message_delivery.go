@@ -45,16 +45,34 @@ type MessageDeliveryQuery struct {
 	Types     []string   `json:"triggers"`
 }
 
+// MessageDeliveryAttempt ...
+type MessageDeliveryAttempt struct {
+	Attempt   int       `json:"attempt"`
+	Error     *string   `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MessageFallback the message is sent by the next provider when the delivery fails after all attempts
+type MessageFallback struct {
+	Type       string           `json:"type"`
+	EntityId   *common.EntityId `json:"entity_id"`
+	Attributes AttributeValue   `json:"attributes"`
+}
+
 // MessageDelivery ...
 type MessageDelivery struct {
-	Id                 int64            `json:"id"`
-	Message            *Message         `json:"message"`
-	MessageId          int64            `json:"message_id"`
-	Address            string           `json:"address"`
-	EntityId           *common.EntityId `json:"entity_id"`
-	Status             MessageStatus    `json:"status"`
-	ErrorMessageStatus *string          `json:"error_message_status"`
-	ErrorMessageBody   *string          `json:"error_message_body"`
-	CreatedAt          time.Time        `json:"created_at"`
-	UpdatedAt          time.Time        `json:"updated_at"`
+	Id                 int64                    `json:"id"`
+	Message            *Message                 `json:"message"`
+	MessageId          int64                    `json:"message_id"`
+	Address            string                   `json:"address"`
+	EntityId           *common.EntityId         `json:"entity_id"`
+	Status             MessageStatus            `json:"status"`
+	ErrorMessageStatus *string                  `json:"error_message_status"`
+	ErrorMessageBody   *string                  `json:"error_message_body"`
+	Attempts           int                      `json:"attempts"`
+	NextAttemptAt      *time.Time               `json:"next_attempt_at"`
+	AttemptLog         []MessageDeliveryAttempt `json:"attempt_log"`
+	Fallback           []MessageFallback        `json:"fallback"`
+	CreatedAt          time.Time                `json:"created_at"`
+	UpdatedAt          time.Time                `json:"updated_at"`
 }
@@ -56,11 +56,11 @@ func (d *Metric) RangesByType() []string {
 
 	switch d.Type {
 	case common.MetricTypeLine, common.MetricTypeBar, common.MetricTypeHorizontalBar:
-		d.Ranges = []string{"1h", "6h", "12h", "24h", "7d", "30d"}
+		d.Ranges = []string{"1h", "6h", "12h", "24h", "7d", "30d", "1y"}
 	case common.MetricTypeDoughnut, common.MetricTypeRadar, common.MetricTypePie:
 		d.Ranges = []string{"current"}
 	default:
-		d.Ranges = []string{"1h", "6h", "12h", "24h", "7d", "30d"}
+		d.Ranges = []string{"1h", "6h", "12h", "24h", "7d", "30d", "1y"}
 	}
 
 	return d.Ranges
@@ -29,6 +29,7 @@ type Role struct {
 	Parent      *Role               `json:"parent"`
 	Children    []*Role             `json:"children"`
 	AccessList  map[string][]string `json:"access_list"`
+	RequireTotp bool                `json:"require_totp"`
 	CreatedAt   time.Time           `json:"created_at"`
 	UpdatedAt   time.Time           `json:"updated_at"`
 }
@@ -32,6 +32,7 @@ type Script struct {
 	Source      string         `json:"source"`
 	Description string         `json:"description"`
 	Compiled    string         `json:"-"`
+	Timeout     int            `json:"timeout"`
 	Versions    ScriptVersions `json:"versions"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
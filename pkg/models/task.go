@@ -30,12 +30,15 @@ type Task struct {
 	Triggers    []*Trigger           `json:"triggers" validate:"dive"`
 	Conditions  []*Condition         `json:"conditions" validate:"dive"`
 	Actions     []*Action            `json:"actions" validate:"dive"`
+	Steps       []*TaskStep          `json:"steps" validate:"dive"`
 	Telemetry   telemetry.Telemetry  `json:"telemetry"`
 	CreatedAt   time.Time            `json:"created_at"`
 	UpdatedAt   time.Time            `json:"updated_at"`
 	Name        string               `json:"name" validate:"required,lte=255"`
 	Description string               `json:"description" validate:"lte=255"`
-	Condition   common.ConditionType `json:"condition" validate:"required,oneof=or and"`
+	Condition   common.ConditionType `json:"condition" validate:"required,oneof=or and not"`
+	Mode        common.TaskMode      `json:"mode" validate:"omitempty,oneof=single restart queued parallel"`
+	MaxRuns     int                  `json:"max_runs" validate:"gte=0"`
 	Id          int64                `json:"id"`
 	Area        *Area                `json:"area"`
 	AreaId      *int64               `json:"area_id"`
@@ -63,9 +66,12 @@ type NewTask struct {
 	TriggerIds   []int64              `json:"triggers" validate:"dive"`
 	ConditionIds []int64              `json:"conditions" validate:"dive"`
 	ActionIds    []int64              `json:"actions" validate:"dive"`
+	Steps        []*TaskStep          `json:"steps" validate:"dive"`
 	Name         string               `json:"name" validate:"required,lte=255"`
 	Description  string               `json:"description" validate:"lte=255"`
-	Condition    common.ConditionType `json:"condition" validate:"required,oneof=or and"`
+	Condition    common.ConditionType `json:"condition" validate:"required,oneof=or and not"`
+	Mode         common.TaskMode      `json:"mode" validate:"omitempty,oneof=single restart queued parallel"`
+	MaxRuns      int                  `json:"max_runs" validate:"gte=0"`
 	Area         *Area                `json:"area"`
 	AreaId       *int64               `json:"area_id"`
 	Enabled      bool                 `json:"enabled"`
@@ -74,17 +80,49 @@ type NewTask struct {
 
 // UpdateTask ...
 type UpdateTask struct {
-	TriggerIds   []int64 `json:"triggers" validate:"dive"`
-	ConditionIds []int64 `json:"conditions" validate:"dive"`
-	ActionIds    []int64 `json:"actions" validate:"dive"`
+	TriggerIds   []int64     `json:"triggers" validate:"dive"`
+	ConditionIds []int64     `json:"conditions" validate:"dive"`
+	ActionIds    []int64     `json:"actions" validate:"dive"`
+	Steps        []*TaskStep `json:"steps" validate:"dive"`
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 	Name         string               `json:"name" validate:"required,lte=255"`
 	Description  string               `json:"description" validate:"lte=255"`
-	Condition    common.ConditionType `json:"condition" validate:"required,oneof=or and"`
+	Condition    common.ConditionType `json:"condition" validate:"required,oneof=or and not"`
+	Mode         common.TaskMode      `json:"mode" validate:"omitempty,oneof=single restart queued parallel"`
+	MaxRuns      int                  `json:"max_runs" validate:"gte=0"`
 	Id           int64                `json:"id"`
 	Area         *Area                `json:"area"`
 	AreaId       *int64               `json:"area_id"`
 	Enabled      bool                 `json:"enabled"`
 	IsLoaded     bool                 `json:"is_loaded"`
 }
+
+// TaskStep is a single step of the ordered action sequence of the task.
+// Delay and Timeout are set in milliseconds.
+type TaskStep struct {
+	Type              common.StepType  `json:"type" validate:"required,oneof=action delay wait if repeat parallel"`
+	ActionId          *int64           `json:"action_id,omitempty"`
+	Delay             int              `json:"delay,omitempty" validate:"gte=0"`
+	EntityId          *common.EntityId `json:"entity_id,omitempty"`
+	State             string           `json:"state,omitempty"`
+	Timeout           int              `json:"timeout,omitempty" validate:"gte=0"`
+	ContinueOnTimeout bool             `json:"continue_on_timeout,omitempty"`
+	ConditionId       *int64           `json:"condition_id,omitempty"`
+	MaxIterations     int              `json:"max_iterations,omitempty" validate:"gte=0"`
+	Then              []*TaskStep      `json:"then,omitempty" validate:"dive"`
+	Else              []*TaskStep      `json:"else,omitempty" validate:"dive"`
+	Steps             []*TaskStep      `json:"steps,omitempty" validate:"dive"`
+	Action            *Action          `json:"-"`
+	Condition         *Condition       `json:"-"`
+}
+
+// WalkTaskSteps calls f for every step including the nested ones
+func WalkTaskSteps(steps []*TaskStep, f func(step *TaskStep)) {
+	for _, step := range steps {
+		f(step)
+		WalkTaskSteps(step.Then, f)
+		WalkTaskSteps(step.Else, f)
+		WalkTaskSteps(step.Steps, f)
+	}
+}
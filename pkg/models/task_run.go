@@ -0,0 +1,51 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/e154/smart-home/pkg/common"
+)
+
+// TaskRun ...
+type TaskRun struct {
+	Id        int64                `json:"id"`
+	TaskId    int64                `json:"task_id"`
+	TriggerId *int64               `json:"trigger_id"`
+	EntityId  *common.EntityId     `json:"entity_id"`
+	Payload   json.RawMessage      `json:"payload"`
+	Status    common.TaskRunStatus `json:"status"`
+	Error     string               `json:"error"`
+	Trace     []*TaskRunStep       `json:"trace"`
+	Start     time.Time            `json:"start"`
+	End       *time.Time           `json:"end"`
+}
+
+// TaskRunStep is a record of a condition or a step executed during the task run
+type TaskRunStep struct {
+	Type     string        `json:"type"`
+	Id       *int64        `json:"id,omitempty"`
+	Level    int           `json:"level"`
+	Result   string        `json:"result,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+}
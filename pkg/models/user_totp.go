@@ -0,0 +1,40 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"time"
+)
+
+// UserTotp is the two-factor authentication of the user, the code is required to sign in when it is enabled
+type UserTotp struct {
+	UserId        int64     `json:"user_id"`
+	Secret        string    `json:"-"`
+	Enabled       bool      `json:"enabled"`
+	RecoveryCodes []string  `json:"-"`
+	LastCounter   int64     `json:"-"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TotpEnrolment is shown to the user once, to add the secret to the authenticator app
+type TotpEnrolment struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
@@ -26,6 +26,9 @@ import (
 	"github.com/e154/smart-home/pkg/common"
 )
 
+// EncryptedVariableMask replaces the value of an encrypted variable outside the server
+const EncryptedVariableMask = "********"
+
 // Variable ...
 type Variable struct {
 	CreatedAt time.Time        `json:"created_at"`
@@ -34,6 +37,7 @@ type Variable struct {
 	Value     string           `json:"value"`
 	EntityId  *common.EntityId `json:"entity_id"`
 	System    bool             `json:"system"`
+	Encrypted bool             `json:"encrypted"`
 	Tags      []*Tag           `json:"tags"`
 	Changed   bool
 }
@@ -43,6 +47,14 @@ func NewVariable(name string) Variable {
 	return Variable{Name: name}
 }
 
+// MaskedValue returns the value of the variable, the encrypted value is masked
+func (v *Variable) MaskedValue() string {
+	if v.Encrypted && v.Value != "" {
+		return EncryptedVariableMask
+	}
+	return v.Value
+}
+
 // GetObj ...
 func (v *Variable) GetObj(obj interface{}) (err error) {
 	err = json.Unmarshal([]byte(v.Value), obj)
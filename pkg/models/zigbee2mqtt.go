@@ -42,4 +42,5 @@ type Zigbee2mqtt struct {
 	Password          *string              `json:"password"`
 	Info              *Zigbee2mqttInfo     `json:"info"`
 	PermitJoin        bool                 `json:"permit_join"`
+	AutoDiscovery     bool                 `json:"auto_discovery"`
 }
@@ -0,0 +1,110 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package triggers
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Subscription is the handler of the task with the options of its trigger
+type Subscription[T any] struct {
+	handler reflect.Value
+	Options T
+}
+
+// Call ...
+func (s *Subscription[T]) Call(topic string, message interface{}) {
+	s.handler.Call([]reflect.Value{reflect.ValueOf(topic), reflect.ValueOf(message)})
+}
+
+// Subscriptions groups the handlers by the key, it is used by the triggers whose
+// subscriptions keep their own options, so the message can't be published to the bus.
+// The handlers of the different tasks share the code pointer, the closures are compared.
+type Subscriptions[K comparable, T any] struct {
+	sync.Mutex
+	list map[K][]*Subscription[T]
+}
+
+// NewSubscriptions ...
+func NewSubscriptions[K comparable, T any]() *Subscriptions[K, T] {
+	return &Subscriptions[K, T]{
+		list: make(map[K][]*Subscription[T]),
+	}
+}
+
+// Add ...
+func (s *Subscriptions[K, T]) Add(key K, handler interface{}, options T) *Subscription[T] {
+	sub := &Subscription[T]{
+		handler: reflect.ValueOf(handler),
+		Options: options,
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.list[key] = append(s.list[key], sub)
+
+	return sub
+}
+
+// Remove returns the removed subscriptions, empty is true when the last subscription of the key was removed
+func (s *Subscriptions[K, T]) Remove(key K, handler interface{}) (removed []*Subscription[T], empty bool) {
+	rv := reflect.ValueOf(handler)
+
+	s.Lock()
+	defer s.Unlock()
+
+	list, ok := s.list[key]
+	if !ok {
+		return
+	}
+	// the new slice is built, the lists returned by Get stay unchanged
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].handler == rv {
+			removed = append(removed, list[i])
+			list = append(list[:i:i], list[i+1:]...)
+		}
+	}
+	if len(list) == 0 {
+		delete(s.list, key)
+		empty = true
+		return
+	}
+	s.list[key] = list
+
+	return
+}
+
+// Get ...
+func (s *Subscriptions[K, T]) Get(key K) []*Subscription[T] {
+	s.Lock()
+	defer s.Unlock()
+	return s.list[key]
+}
+
+// All ...
+func (s *Subscriptions[K, T]) All() (list []*Subscription[T]) {
+	s.Lock()
+	defer s.Unlock()
+	for _, subs := range s.list {
+		list = append(list, subs...)
+	}
+	return
+}
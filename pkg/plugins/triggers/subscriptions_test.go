@@ -0,0 +1,67 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package triggers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptions(t *testing.T) {
+
+	var calls []string
+	// the closures of the same function have the same code pointer
+	handler := func(name string) func(string, interface{}) {
+		return func(topic string, _ interface{}) {
+			calls = append(calls, name+":"+topic)
+		}
+	}
+	first, second := handler("first"), handler("second")
+
+	subs := NewSubscriptions[string, int]()
+	subs.Add("a", first, 1)
+	subs.Add("a", second, 2)
+	subs.Add("b", first, 3)
+	require.Len(t, subs.All(), 3)
+
+	list := subs.Get("a")
+	require.Len(t, list, 2)
+	for _, sub := range list {
+		sub.Call("a", "message")
+	}
+	require.Equal(t, []string{"first:a", "second:a"}, calls)
+
+	removed, empty := subs.Remove("a", first)
+	require.Len(t, removed, 1)
+	require.Equal(t, 1, removed[0].Options)
+	require.False(t, empty)
+	// the list returned before is not changed
+	require.Len(t, list, 2)
+	require.Len(t, subs.Get("a"), 1)
+
+	removed, empty = subs.Remove("a", second)
+	require.Len(t, removed, 1)
+	require.True(t, empty)
+
+	removed, empty = subs.Remove("c", second)
+	require.Empty(t, removed)
+	require.False(t, empty)
+	require.Len(t, subs.All(), 1)
+}
@@ -287,4 +287,7 @@ type Authorization interface {
 
 type HttpAccessFilter interface {
 	Auth(next http.Handler) http.Handler
+	// EntityAuth checks the access of the current user to the entity of the entity_id path value,
+	// the handler must be wrapped with Auth
+	EntityAuth(level common.EntityAccessLevel, next http.Handler) http.Handler
 }
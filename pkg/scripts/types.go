@@ -33,6 +33,7 @@ type IScript interface {
 	EvalString(string) (string, error)
 	CreateProgram(name, source string) (err error)
 	RunProgram(name string) (result string, err error)
+	Interrupt(v interface{})
 }
 
 type Engine interface {
@@ -49,6 +50,7 @@ type Engine interface {
 	File(path string) ([]byte, error)
 	ScriptId() int64
 	Script() *m.Script
+	Interrupt(v interface{})
 }
 
 type EngineWatcher interface {
@@ -4378,7 +4378,7 @@ export class Api<SecurityDataType extends unknown> extends HttpClient<SecurityDa
       query: {
         /** @format int64 */
         id: number;
-        range?: "6h" | "12h" | "24h" | "7d" | "30d" | "1m";
+        range?: "6h" | "12h" | "24h" | "7d" | "30d" | "1m" | "1y";
         /** @format date-time */
         startDate?: string;
         /** @format date-time */
@@ -35,6 +35,10 @@ export const HintDictionaryCoffee = {
     {text: 'getByName(key)', displayText: 'getByName'},
     {text: 'search(key)', displayText: 'search'},
 
+    // history
+    {text: 'History.query(request)', displayText: 'History.query'},
+    {text: 'History.durationInState(entityId, state)', displayText: 'History.durationInState'},
+
     // geo
     {text: 'GeoDistanceToArea(areaId, point)', displayText: 'GeoDistanceToArea'},
     {text: 'GeoPointInsideArea(areaId, point)', displayText: 'GeoPointInsideArea'},
@@ -169,6 +173,10 @@ export const HintDictionary = {
     {text: 'getByName(key)', displayText: 'getByName'},
     {text: 'search(key)', displayText: 'search'},
 
+    // history
+    {text: 'History.query(request)', displayText: 'History.query'},
+    {text: 'History.durationInState(entityId, state)', displayText: 'History.durationInState'},
+
     // geo
     {text: 'GeoDistanceToArea(areaId, point)', displayText: 'GeoDistanceToArea'},
     {text: 'GeoPointInsideArea(areaId, point)', displayText: 'GeoPointInsideArea'},
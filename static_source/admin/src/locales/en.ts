@@ -794,6 +794,10 @@ export default {
         clearLogsDays: 'Clear Logs Days',
         clearEntityStorageDays: 'Clear Entity Storage Days',
         clearRunHistoryDays: 'Clear Run History Days',
+        clearMetricsHourlyDays: 'Clear Hourly Metrics Days (0 - keep)',
+        clearMetricsDailyDays: 'Clear Daily Metrics Days (0 - keep)',
+        clearEntityStorageHourlyDays: 'Clear Hourly Entity Statistics Days (0 - keep)',
+        clearEntityStorageDailyDays: 'Clear Daily Entity Statistics Days (0 - keep)',
         time: 'Time',
         timezone: 'Timezone, default Asia/Colombo',
         backup: 'Backup',
@@ -813,6 +817,14 @@ export default {
         gateClientPoolIdleSize: 'Pool Idle Size',
         gateClientPoolMaxSize: 'Pool Max Size',
         gateClientTLS: 'TLS',
+        exportInflux: 'InfluxDB Export',
+        exportInfluxUrl: 'Write URL',
+        exportInfluxToken: 'Token',
+        exportInfluxInterval: 'Push Interval, sec (0 - disabled)',
+        exportInfluxTags: 'Tags (comma separated)',
+        exportInfluxArea: 'Area',
+        discovery: 'Device Discovery',
+        discoveryInterval: 'Search Interval, sec (0 - disabled, applied after restart)',
         hmacKey: 'HMAC Key',
         certificates: 'SSL Certificates',
         certPublic: 'Select an X.509 certificate file, commonly a crt, cer or pem file.',
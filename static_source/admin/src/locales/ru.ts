@@ -794,6 +794,10 @@ export default {
     clearLogsDays: 'Очистка логов',
     clearEntityStorageDays: 'История состояния Entity',
     clearRunHistoryDays: 'История запусков системы',
+    clearMetricsHourlyDays: 'Удаление часовых метрик (0 - хранить)',
+    clearMetricsDailyDays: 'Удаление суточных метрик (0 - хранить)',
+    clearEntityStorageHourlyDays: 'Часовая статистика Entity (0 - хранить)',
+    clearEntityStorageDailyDays: 'Суточная статистика Entity (0 - хранить)',
     time: 'Время',
     timezone: 'Часовой пояс',
     backup: 'Резервное копирование',
@@ -813,6 +817,14 @@ export default {
     gateClientPoolIdleSize: 'Минимум потоков',
     gateClientPoolMaxSize: 'Максимум потоков',
     gateClientTLS: 'TLS',
+    exportInflux: 'Экспорт в InfluxDB',
+    exportInfluxUrl: 'URL для записи',
+    exportInfluxToken: 'Токен',
+    exportInfluxInterval: 'Интервал отправки, сек (0 - выключено)',
+    exportInfluxTags: 'Теги (через запятую)',
+    exportInfluxArea: 'Зона',
+    discovery: 'Обнаружение устройств',
+    discoveryInterval: 'Интервал поиска, сек (0 - выключено, применяется после перезапуска)',
     hmacKey: 'HMAC Ключ',
     certificates: 'SSL Сертификаты',
     certPublic: 'Выберите файл сертификата X.509, обычно это файл crt, cer или pem.',
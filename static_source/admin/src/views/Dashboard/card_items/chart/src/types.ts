@@ -48,6 +48,7 @@ export const RangeList: ItemsType[] = [
   {label: '24 Hours', value: '24h'},
   {label: '7 Days', value: '7d'},
   {label: '30 Days', value: '30d'},
+  {label: '1 Year', value: '1y'},
 ];
 
 export const FilterList: ItemsType[] = [
@@ -24,7 +24,7 @@ export interface SeriesItem {
     customAttributes: Array<CustomAttribute>,
     metricIndex: number,
     metricProps: string,
-    metricRange: "6h" | "12h" | "24h" | "7d" | "30d" | "1m",
+    metricRange: "6h" | "12h" | "24h" | "7d" | "30d" | "1m" | "1y",
     metricFilter: string,
     chartData: ChartData,
 }
@@ -57,6 +57,7 @@ export const RangeList: ItemsType[] = [
     {label: '24 Hours', value: '24h'},
     {label: '7 Days', value: '7d'},
     {label: '30 Days', value: '30d'},
+    {label: '1 Year', value: '1y'},
 ];
 
 export const FilterList: ItemsType[] = [
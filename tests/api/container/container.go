@@ -25,6 +25,8 @@ import (
 	"github.com/e154/smart-home/internal/endpoint"
 	"github.com/e154/smart-home/internal/system/automation"
 	"github.com/e154/smart-home/internal/system/backup"
+	"github.com/e154/smart-home/internal/system/discovery"
+	"github.com/e154/smart-home/internal/system/exporter"
 	"github.com/e154/smart-home/internal/system/gate/client"
 	"github.com/e154/smart-home/internal/system/initial"
 	"github.com/e154/smart-home/internal/system/jwt_manager"
@@ -35,6 +37,7 @@ import (
 	"github.com/e154/smart-home/internal/system/orm"
 	"github.com/e154/smart-home/internal/system/rbac"
 	"github.com/e154/smart-home/internal/system/rbac/access_list"
+	"github.com/e154/smart-home/internal/system/rbac/entity_access"
 	"github.com/e154/smart-home/internal/system/scheduler"
 	"github.com/e154/smart-home/internal/system/scripts"
 	"github.com/e154/smart-home/internal/system/storage"
@@ -71,6 +74,7 @@ func BuildContainer() (container *dig.Container) {
 	_ = container.Provide(NewMqttCli)
 	_ = container.Provide(mqtt_authenticator.NewAuthenticator)
 	_ = container.Provide(access_list.NewAccessListService)
+	_ = container.Provide(entity_access.NewEntityAccessService)
 	_ = container.Provide(NewHttpAccessFilter)
 	_ = container.Provide(stream.NewStreamService)
 	_ = container.Provide(client.NewGateClient)
@@ -82,6 +86,8 @@ func BuildContainer() (container *dig.Container) {
 	_ = container.Provide(storage.NewStorage)
 	_ = container.Provide(supervisor.NewSupervisor)
 	_ = container.Provide(automation.NewAutomation)
+	_ = container.Provide(exporter.NewExporter)
+	_ = container.Provide(discovery.NewDiscovery)
 	_ = container.Provide(bus.NewBus)
 	_ = container.Provide(endpoint.NewCommonEndpoint)
 	_ = container.Provide(endpoint.NewEndpoint)
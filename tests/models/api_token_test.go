@@ -0,0 +1,128 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/internal/system/migrations"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestApiToken(t *testing.T) {
+
+	Convey("api tokens", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations) {
+
+			// clear database
+			_ = migrations.Purge()
+
+			err := adaptors.Role.Add(context.Background(), &models.Role{Name: "user_role", RequireTotp: true})
+			So(err, ShouldBeNil)
+
+			role, err := adaptors.Role.GetByName(context.Background(), "user_role")
+			So(err, ShouldBeNil)
+			So(role.RequireTotp, ShouldBeTrue)
+
+			user := &models.User{
+				Nickname: "user",
+				RoleName: "user_role",
+				Email:    "email@mail.com",
+				Lang:     "en",
+			}
+			err = user.SetPass("123456")
+			So(err, ShouldBeNil)
+			user.Id, err = adaptors.User.Add(context.Background(), user)
+			So(err, ShouldBeNil)
+
+			expiresAt := time.Now().Add(time.Hour)
+			token := &models.ApiToken{
+				UserId:    user.Id,
+				Name:      "grafana",
+				Token:     "hash1",
+				Scopes:    []string{"entity:read"},
+				ExpiresAt: &expiresAt,
+			}
+			token.Id, err = adaptors.ApiToken.Add(context.Background(), token)
+			So(err, ShouldBeNil)
+
+			// the name is unique per user
+			_, err = adaptors.ApiToken.Add(context.Background(), &models.ApiToken{
+				UserId: user.Id,
+				Name:   "grafana",
+				Token:  "hash2",
+			})
+			So(err, ShouldNotBeNil)
+
+			found, err := adaptors.ApiToken.GetByToken(context.Background(), "hash1")
+			So(err, ShouldBeNil)
+			So(found.Id, ShouldEqual, token.Id)
+			So(found.Scopes, ShouldResemble, []string{"entity:read"})
+			So(found.LastUsedAt, ShouldBeNil)
+			So(found.IsExpired(time.Now()), ShouldBeFalse)
+			So(found.IsExpired(expiresAt.Add(time.Second)), ShouldBeTrue)
+
+			err = adaptors.ApiToken.Touch(context.Background(), token.Id, time.Now())
+			So(err, ShouldBeNil)
+
+			list, err := adaptors.ApiToken.List(context.Background(), user.Id)
+			So(err, ShouldBeNil)
+			So(len(list), ShouldEqual, 1)
+			So(list[0].LastUsedAt, ShouldNotBeNil)
+
+			err = adaptors.ApiToken.Delete(context.Background(), token.Id)
+			So(err, ShouldBeNil)
+
+			_, err = adaptors.ApiToken.GetByToken(context.Background(), "hash1")
+			So(err, ShouldNotBeNil)
+
+			// two-factor authentication
+			err = adaptors.UserTotp.Save(context.Background(), &models.UserTotp{
+				UserId: user.Id,
+				Secret: "secret",
+			})
+			So(err, ShouldBeNil)
+
+			err = adaptors.UserTotp.Save(context.Background(), &models.UserTotp{
+				UserId:        user.Id,
+				Secret:        "secret",
+				Enabled:       true,
+				RecoveryCodes: []string{"code1", "code2"},
+			})
+			So(err, ShouldBeNil)
+
+			userTotp, err := adaptors.UserTotp.GetByUserId(context.Background(), user.Id)
+			So(err, ShouldBeNil)
+			So(userTotp.Enabled, ShouldBeTrue)
+			So(userTotp.RecoveryCodes, ShouldResemble, []string{"code1", "code2"})
+
+			err = adaptors.UserTotp.Delete(context.Background(), user.Id)
+			So(err, ShouldBeNil)
+
+			_, err = adaptors.UserTotp.GetByUserId(context.Background(), user.Id)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,212 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"context"
+	"testing"
+
+	"github.com/e154/smart-home/internal/endpoint"
+	"github.com/e154/smart-home/internal/system/migrations"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConfigArchive(t *testing.T) {
+	Convey("config archive", t, func(ctx C) {
+		err := container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations,
+			endpoint *endpoint.Endpoint) {
+
+			// clear database
+			_ = migrations.Purge()
+
+			var bg = context.Background()
+
+			err := AddPlugin(adaptors, "sensor")
+			So(err, ShouldBeNil)
+			err = AddPlugin(adaptors, "state_change")
+			So(err, ShouldBeNil)
+
+			script := &models.Script{
+				Lang:   common.ScriptLangCoffee,
+				Name:   "archive_script",
+				Source: "print 'OK'",
+			}
+			script.Id, err = adaptors.Script.Add(bg, script)
+			So(err, ShouldBeNil)
+
+			area := &models.Area{Name: "archive_area"}
+			area.Id, err = adaptors.Area.Add(bg, area)
+			So(err, ShouldBeNil)
+
+			entity := &models.Entity{
+				Id:         common.EntityId("sensor.archive"),
+				PluginName: "sensor",
+				AreaId:     common.Int64(area.Id),
+				Scripts:    []*models.Script{script},
+				Actions: []*models.EntityAction{
+					{Name: "ON", ScriptId: common.Int64(script.Id)},
+				},
+				States: []*models.EntityState{
+					{Name: "ON"},
+				},
+			}
+			err = adaptors.Entity.Add(bg, entity)
+			So(err, ShouldBeNil)
+
+			triggerId, err := adaptors.Trigger.Add(bg, &models.NewTrigger{
+				Name:       "archive_trigger",
+				PluginName: "state_change",
+				EntityIds:  []string{entity.Id.String()},
+				ScriptId:   common.Int64(script.Id),
+				Enabled:    true,
+			})
+			So(err, ShouldBeNil)
+
+			actionId, err := adaptors.Action.Add(bg, &models.Action{
+				Name:     "archive_action",
+				ScriptId: common.Int64(script.Id),
+				EntityId: entity.Id.Ptr(),
+			})
+			So(err, ShouldBeNil)
+
+			taskId, err := adaptors.Task.Add(bg, &models.NewTask{
+				Name:       "archive_task",
+				Condition:  common.ConditionOr,
+				TriggerIds: []int64{triggerId},
+				ActionIds:  []int64{actionId},
+				AreaId:     common.Int64(area.Id),
+				Steps: []*models.TaskStep{
+					{Type: common.StepTypeAction, ActionId: common.Int64(actionId)},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			err = adaptors.Variable.CreateOrUpdate(bg, models.Variable{
+				Name:     "archive_variable",
+				Value:    "foo",
+				EntityId: entity.Id.Ptr(),
+			})
+			So(err, ShouldBeNil)
+
+			archive, err := endpoint.ConfigArchive.Export(bg, &models.ConfigExportParams{
+				Entities:  []common.EntityId{entity.Id},
+				Variables: []string{"archive_variable"},
+				Tasks:     []int64{taskId},
+			})
+			So(err, ShouldBeNil)
+			So(len(archive.Scripts), ShouldEqual, 1)
+			So(len(archive.Areas), ShouldEqual, 1)
+			So(len(archive.Entities), ShouldEqual, 1)
+			So(len(archive.Triggers), ShouldEqual, 1)
+			So(len(archive.Actions), ShouldEqual, 1)
+			So(len(archive.Tasks), ShouldEqual, 1)
+
+			data, err := archive.Marshal(common.ArchiveFormatYaml)
+			So(err, ShouldBeNil)
+
+			load := func() *models.ConfigArchive {
+				archive, err := models.UnmarshalConfigArchive(data, common.ArchiveFormatYaml)
+				So(err, ShouldBeNil)
+				return archive
+			}
+
+			t.Run("skip", func(t *testing.T) {
+				Convey("", t, func(ctx C) {
+					result, err := endpoint.ConfigArchive.Import(bg, load(), common.ImportConflictSkip)
+					So(err, ShouldBeNil)
+					for _, item := range result.Items {
+						So(item.Status, ShouldEqual, common.ImportStatusSkipped)
+					}
+
+					_, total, err := adaptors.Task.List(bg, 999, 0, "desc", "id", false)
+					So(err, ShouldBeNil)
+					So(total, ShouldEqual, 1)
+				})
+			})
+
+			t.Run("rename", func(t *testing.T) {
+				Convey("", t, func(ctx C) {
+					_, err := endpoint.ConfigArchive.Import(bg, load(), common.ImportConflictRename)
+					So(err, ShouldBeNil)
+
+					newScript, err := adaptors.Script.GetByName(bg, "archive_script [IMPORTED]")
+					So(err, ShouldBeNil)
+
+					newEntity, err := adaptors.Entity.GetById(bg, "sensor.archive_imported")
+					So(err, ShouldBeNil)
+					So(len(newEntity.Actions), ShouldEqual, 1)
+					So(*newEntity.Actions[0].ScriptId, ShouldEqual, newScript.Id)
+
+					variable, err := adaptors.Variable.GetByName(bg, "archive_variable_imported")
+					So(err, ShouldBeNil)
+					So(*variable.EntityId, ShouldEqual, newEntity.Id)
+
+					list, total, err := adaptors.Task.List(bg, 999, 0, "desc", "id", false)
+					So(err, ShouldBeNil)
+					So(total, ShouldEqual, 2)
+
+					task, err := adaptors.Task.GetById(bg, list[0].Id)
+					So(err, ShouldBeNil)
+					So(task.Name, ShouldEqual, "archive_task [IMPORTED]")
+					So(*task.AreaId, ShouldNotEqual, area.Id)
+					So(len(task.Triggers), ShouldEqual, 1)
+					So(task.Triggers[0].Id, ShouldNotEqual, triggerId)
+					So(*task.Triggers[0].ScriptId, ShouldEqual, newScript.Id)
+					So(len(task.Triggers[0].Entities), ShouldEqual, 1)
+					So(task.Triggers[0].Entities[0].Id, ShouldEqual, newEntity.Id)
+					So(len(task.Actions), ShouldEqual, 1)
+					So(*task.Actions[0].EntityId, ShouldEqual, newEntity.Id)
+					So(len(task.Steps), ShouldEqual, 1)
+					So(*task.Steps[0].ActionId, ShouldEqual, task.Actions[0].Id)
+				})
+			})
+
+			t.Run("overwrite", func(t *testing.T) {
+				Convey("", t, func(ctx C) {
+					archive := load()
+					archive.Scripts[0].Source = "print 'UPDATED'"
+					archive.Tasks[0].Description = "updated"
+
+					result, err := endpoint.ConfigArchive.Import(bg, archive, common.ImportConflictOverwrite)
+					So(err, ShouldBeNil)
+					for _, item := range result.Items {
+						So(item.Status, ShouldEqual, common.ImportStatusUpdated)
+					}
+
+					updated, err := adaptors.Script.GetById(bg, script.Id)
+					So(err, ShouldBeNil)
+					So(updated.Source, ShouldEqual, "print 'UPDATED'")
+
+					task, err := adaptors.Task.GetById(bg, taskId)
+					So(err, ShouldBeNil)
+					So(task.Description, ShouldEqual, "updated")
+					So(len(task.Triggers), ShouldEqual, 1)
+					So(task.Triggers[0].Id, ShouldEqual, triggerId)
+					So(*task.Steps[0].ActionId, ShouldEqual, actionId)
+				})
+			})
+		})
+		So(err, ShouldBeNil)
+	})
+}
@@ -0,0 +1,134 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/internal/system/migrations"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/common"
+	"github.com/e154/smart-home/pkg/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStatistics(t *testing.T) {
+
+	Convey("statistics", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations) {
+
+			// clear database
+			_ = migrations.Purge()
+
+			err := AddPlugin(adaptors, "sensor")
+			So(err, ShouldBeNil)
+
+			metric := &models.Metric{
+				Name: "energy",
+				Type: common.MetricTypeLine,
+				Options: models.MetricOptions{
+					Items: []models.MetricOptionsItem{{Name: "power"}},
+				},
+			}
+			metric.Id, err = adaptors.Metric.Add(context.Background(), metric)
+			So(err, ShouldBeNil)
+
+			err = adaptors.Entity.Add(context.Background(), &models.Entity{
+				Id:         common.EntityId("sensor.meter"),
+				PluginName: "sensor",
+			})
+			So(err, ShouldBeNil)
+
+			// two samples per hour over the last three days
+			now := time.Now().Truncate(time.Hour)
+			start := now.Add(-72 * time.Hour)
+			for tm := start; tm.Before(now); tm = tm.Add(30 * time.Minute) {
+				err = adaptors.MetricBucket.Add(context.Background(), &models.MetricDataItem{
+					MetricId: metric.Id,
+					Time:     tm,
+					Value:    map[string]interface{}{"power": 10, "mode": "eco"},
+				})
+				So(err, ShouldBeNil)
+
+				_, err = adaptors.EntityStorage.Add(context.Background(), &models.EntityStorage{
+					EntityId:   "sensor.meter",
+					State:      "ON",
+					Attributes: models.AttributeValue{"energy": 2.5, "label": "meter"},
+					CreatedAt:  tm,
+				})
+				So(err, ShouldBeNil)
+			}
+
+			err = adaptors.MetricBucket.Rollup(context.Background())
+			So(err, ShouldBeNil)
+			// repeated runs must not duplicate the statistics
+			err = adaptors.MetricBucket.Rollup(context.Background())
+			So(err, ShouldBeNil)
+
+			err = adaptors.EntityStorage.Rollup(context.Background())
+			So(err, ShouldBeNil)
+
+			// a yearly range is served from the daily rollup
+			to := now
+			from := to.AddDate(-1, 0, 0)
+			data, err := adaptors.MetricBucket.List(context.Background(), &from, &to, metric.Id, []string{"power"}, nil)
+			So(err, ShouldBeNil)
+			So(len(data), ShouldBeBetweenOrEqual, 3, 4)
+			So(data[0].Value["power"], ShouldEqual, 10.0)
+
+			// a monthly range is served from the hourly rollup
+			from = to.AddDate(0, -1, 0)
+			data, err = adaptors.MetricBucket.List(context.Background(), &from, &to, metric.Id, []string{"power"}, nil)
+			So(err, ShouldBeNil)
+			So(len(data), ShouldEqual, 72)
+
+			stats, err := adaptors.EntityStorage.Statistics(context.Background(), "sensor.meter", nil, start, now)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldEqual, 72)
+			So(stats[0].Name, ShouldEqual, "energy")
+			So(stats[0].Resolution, ShouldEqual, common.StatsResolutionHour)
+			So(stats[0].Count, ShouldEqual, 2)
+			So(stats[0].Sum, ShouldEqual, 5)
+			So(stats[0].Mean, ShouldEqual, 2.5)
+
+			from = now.AddDate(-1, 0, 0)
+			stats, err = adaptors.EntityStorage.Statistics(context.Background(), "sensor.meter", []string{"energy"}, from, now)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldBeBetweenOrEqual, 3, 4)
+			So(stats[0].Resolution, ShouldEqual, common.StatsResolutionDay)
+
+			var total int64
+			for _, stat := range stats {
+				total += stat.Count
+			}
+			So(total, ShouldEqual, 144)
+
+			// retention is applied per resolution
+			err = adaptors.EntityStorage.DeleteOldestStats(context.Background(), common.StatsResolutionHour, 1)
+			So(err, ShouldBeNil)
+
+			stats, err = adaptors.EntityStorage.Statistics(context.Background(), "sensor.meter", nil, start, now)
+			So(err, ShouldBeNil)
+			So(len(stats), ShouldBeBetweenOrEqual, 23, 24)
+		})
+	})
+}
@@ -0,0 +1,119 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2024, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/internal/system/migrations"
+	"github.com/e154/smart-home/pkg/adaptors"
+	"github.com/e154/smart-home/pkg/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUserSession(t *testing.T) {
+
+	Convey("user sessions", t, func(ctx C) {
+		_ = container.Invoke(func(adaptors *adaptors.Adaptors,
+			migrations *migrations.Migrations) {
+
+			// clear database
+			_ = migrations.Purge()
+
+			err := adaptors.Role.Add(context.Background(), &models.Role{Name: "user_role"})
+			So(err, ShouldBeNil)
+
+			user := &models.User{
+				Nickname: "user",
+				RoleName: "user_role",
+				Email:    "email@mail.com",
+				Lang:     "en",
+			}
+			err = user.SetPass("123456")
+			So(err, ShouldBeNil)
+			user.Id, err = adaptors.User.Add(context.Background(), user)
+			So(err, ShouldBeNil)
+
+			now := time.Now()
+			session := &models.UserSession{
+				UserId:       user.Id,
+				RefreshToken: "token1",
+				Device:       "browser",
+				Ip:           "127.0.0.1",
+				LastSeenAt:   now,
+				ExpiresAt:    now.Add(time.Hour),
+			}
+			session.Id, err = adaptors.UserSession.Add(context.Background(), session)
+			So(err, ShouldBeNil)
+
+			// rotate the refresh token
+			previous := session.RefreshToken
+			session.PreviousToken = &previous
+			session.RefreshToken = "token2"
+			ok, err := adaptors.UserSession.Rotate(context.Background(), session, previous)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			// the token is rotated already
+			ok, err = adaptors.UserSession.Rotate(context.Background(), session, previous)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+
+			found, err := adaptors.UserSession.GetByToken(context.Background(), "token1")
+			So(err, ShouldBeNil)
+			So(found.Id, ShouldEqual, session.Id)
+			So(found.RefreshToken, ShouldEqual, "token2")
+			So(found.IsActive(time.Now()), ShouldBeTrue)
+
+			session2 := &models.UserSession{
+				UserId:       user.Id,
+				RefreshToken: "token3",
+				Device:       "phone",
+				LastSeenAt:   now,
+				ExpiresAt:    now.Add(time.Hour),
+			}
+			session2.Id, err = adaptors.UserSession.Add(context.Background(), session2)
+			So(err, ShouldBeNil)
+
+			list, err := adaptors.UserSession.List(context.Background(), user.Id)
+			So(err, ShouldBeNil)
+			So(len(list), ShouldEqual, 2)
+
+			err = adaptors.UserSession.Revoke(context.Background(), session.Id)
+			So(err, ShouldBeNil)
+			found, err = adaptors.UserSession.GetById(context.Background(), session.Id)
+			So(err, ShouldBeNil)
+			So(found.IsActive(time.Now()), ShouldBeFalse)
+
+			list, err = adaptors.UserSession.List(context.Background(), user.Id)
+			So(err, ShouldBeNil)
+			So(len(list), ShouldEqual, 1)
+			So(list[0].Id, ShouldEqual, session2.Id)
+
+			err = adaptors.UserSession.RevokeAll(context.Background(), user.Id)
+			So(err, ShouldBeNil)
+			list, err = adaptors.UserSession.List(context.Background(), user.Id)
+			So(err, ShouldBeNil)
+			So(len(list), ShouldEqual, 0)
+		})
+	})
+}
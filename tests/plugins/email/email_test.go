@@ -97,6 +97,9 @@ func TestEmail(t *testing.T) {
 						ctx.So(del.Address, ShouldBeIn, []string{"test@e154.ru", "test2@e154.ru"})
 						ctx.So(del.ErrorMessageBody, ShouldBeNil)
 						ctx.So(del.ErrorMessageStatus, ShouldBeNil)
+						ctx.So(del.Attempts, ShouldEqual, 1)
+						ctx.So(len(del.AttemptLog), ShouldEqual, 1)
+						ctx.So(del.NextAttemptAt, ShouldBeNil)
 						ctx.So(del.Message.Type, ShouldEqual, email.Name)
 
 						attr := email.NewMessageParams()
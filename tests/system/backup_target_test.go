@@ -0,0 +1,102 @@
+// This file is part of the Smart Home
+// Program complex distribution https://github.com/e154/smart-home
+// Copyright (C) 2016-2023, Filippov Alex
+//
+// This library is free software: you can redistribute it and/or
+// modify it under the terms of the GNU Lesser General Public
+// License as published by the Free Software Foundation; either
+// version 3 of the License, or (at your option) any later version.
+//
+// This library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the GNU
+// Library General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public
+// License along with this library.  If not, see
+// <https://www.gnu.org/licenses/>.
+
+package system
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/e154/smart-home/internal/system/backup"
+	m "github.com/e154/smart-home/pkg/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// the MinIO container stands in for S3:
+// docker run -d -p 9000:9000 -e MINIO_ROOT_USER=minioadmin -e MINIO_ROOT_PASSWORD=minioadmin minio/minio server /data
+func TestBackupS3Target(t *testing.T) {
+
+	var endpoint = os.Getenv("MINIO_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "127.0.0.1:9000"
+	}
+	conn, err := net.DialTimeout("tcp", endpoint, time.Second)
+	if err != nil {
+		t.Skipf("minio is not available on %s", endpoint)
+	}
+	_ = conn.Close()
+
+	t.Run("s3", func(t *testing.T) {
+		Convey("", t, func(ctx C) {
+
+			target, err := backup.NewTarget(backup.TargetConfig{
+				Type:      backup.TargetS3,
+				Endpoint:  endpoint,
+				Bucket:    "smart-home-test",
+				Path:      "snapshots",
+				AccessKey: "minioadmin",
+				SecretKey: "minioadmin",
+			})
+			ctx.So(err, ShouldBeNil)
+
+			var bg = context.Background()
+			var dir = t.TempDir()
+			var retention = backup.Retention{Daily: 1}
+
+			names := []string{"2026-10-16T00:00:00.zip", "2026-10-17T00:00:00.zip"}
+			for _, name := range names {
+				file := filepath.Join(dir, name)
+				err = os.WriteFile(file, []byte(name), 0644)
+				ctx.So(err, ShouldBeNil)
+				err = target.Upload(bg, file, name)
+				ctx.So(err, ShouldBeNil)
+				time.Sleep(time.Second)
+			}
+
+			var list m.Backups
+			list, err = target.List(bg)
+			ctx.So(err, ShouldBeNil)
+			ctx.So(len(list), ShouldEqual, 2)
+
+			downloaded := filepath.Join(dir, "downloaded.zip")
+			err = target.Download(bg, names[1], downloaded)
+			ctx.So(err, ShouldBeNil)
+			data, err := os.ReadFile(downloaded)
+			ctx.So(err, ShouldBeNil)
+			ctx.So(string(data), ShouldEqual, names[1])
+
+			// both objects are uploaded today, the newest is kept
+			expired := retention.Expired(list)
+			ctx.So(len(expired), ShouldEqual, 1)
+			ctx.So(expired[0].Name, ShouldEqual, names[0])
+
+			for _, file := range list {
+				err = target.Delete(bg, file.Name)
+				ctx.So(err, ShouldBeNil)
+			}
+			list, err = target.List(bg)
+			ctx.So(err, ShouldBeNil)
+			ctx.So(len(list), ShouldEqual, 0)
+		})
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/e154/smart-home/internal/common/debug"
 	"github.com/e154/smart-home/internal/system/jwt_manager"
@@ -94,6 +95,26 @@ func TestJwt(t *testing.T) {
 					})
 				})
 
+				t.Run("revoked session", func(t *testing.T) {
+					Convey("", t, func(ctx C) {
+
+						user := &m.User{
+							Id:       1,
+							Nickname: "John Doe",
+							RoleName: "user",
+						}
+						accessToken, expiresAt, err := jwtManager.GenerateSession(user, 999999, false)
+						ctx.So(err, ShouldBeNil)
+						ctx.So(accessToken, ShouldNotBeBlank)
+						ctx.So(expiresAt, ShouldHappenWithin, jwt_manager.AccessTokenTTL+time.Minute, time.Now())
+
+						// the session does not exist
+						claims, err := jwtManager.Verify(accessToken)
+						ctx.So(err, ShouldEqual, jwt_manager.ErrSessionRevoked)
+						ctx.So(claims, ShouldBeNil)
+					})
+				})
+
 				t.Run("invalid signature", func(t *testing.T) {
 					Convey("", t, func(ctx C) {
 